@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListUsers returns a page of users, optionally filtered by a search term
+func (cr *Controller) ListUsers(ctx *gin.Context) {
+	query := domain.UserQuery{
+		Search: ctx.Query("search"),
+		Cursor: ctx.Query("cursor"),
+	}
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+			return
+		}
+		query.Limit = limit
+	}
+
+	page, err := cr.UserUsecases.ListUsers(ctx, query)
+	if err != nil {
+		respondUserError(ctx, err, "Failed to retrieve users")
+		return
+	}
+
+	users := make([]gin.H, 0, len(page.Users))
+	for _, user := range page.Users {
+		users = append(users, userResponse(user))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"users": users, "next_cursor": page.NextCursor})
+}
+
+// DemoteUser moves an admin back to the user role
+func (cr *Controller) DemoteUser(ctx *gin.Context) {
+	if err := cr.UserUsecases.DemoteUser(ctx, ctx.Param("id")); err != nil {
+		respondUserError(ctx, err, "Failed to demote user")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User demoted successfully"})
+}
+
+// DisableUser blocks an account from logging in or using its tokens
+func (cr *Controller) DisableUser(ctx *gin.Context) {
+	if err := cr.UserUsecases.SetUserDisabled(ctx, ctx.Param("id"), true); err != nil {
+		respondUserError(ctx, err, "Failed to disable user")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User disabled successfully"})
+}
+
+// EnableUser lets a disabled account log in again
+func (cr *Controller) EnableUser(ctx *gin.Context) {
+	if err := cr.UserUsecases.SetUserDisabled(ctx, ctx.Param("id"), false); err != nil {
+		respondUserError(ctx, err, "Failed to enable user")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User enabled successfully"})
+}
+
+// DeleteUser removes a user together with their tasks
+func (cr *Controller) DeleteUser(ctx *gin.Context) {
+	if err := cr.UserUsecases.DeleteUser(ctx, ctx.Param("id")); err != nil {
+		respondUserError(ctx, err, "Failed to delete user")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
+
+// UnlockUser lifts a lockout caused by failed logins
+func (cr *Controller) UnlockUser(ctx *gin.Context) {
+	if err := cr.UserUsecases.UnlockUser(ctx, ctx.Param("id")); err != nil {
+		respondUserError(ctx, err, "Failed to unlock user")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
+}
+
+// userResponse is the public view of a user; password hashes and token
+// versions never leave the server.
+func userResponse(user *domain.User) gin.H {
+	return gin.H{
+		"id":       user.ID,
+		"username": user.Username,
+		"email":    user.Email,
+		"role":     user.Role,
+		"disabled": user.Disabled,
+	}
+}
+
+func respondUserError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, domain.ErrLastAdmin):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The last active admin cannot be demoted, disabled or deleted"})
+	case errors.Is(err, domain.ErrNotAdmin):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "User is not an admin"})
+	case errors.Is(err, domain.ErrInvalidUserQuery), errors.Is(err, domain.ErrInvalidCursor):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,104 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminControllerSuite struct {
+	suite.Suite
+	userUsecase *mocks.UserUsecases
+	router      *gin.Engine
+}
+
+func (s *AdminControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.userUsecase = new(mocks.UserUsecases)
+	ctrl := &controller.Controller{UserUsecases: s.userUsecase}
+	s.router = gin.New()
+	s.router.GET("/users", ctrl.ListUsers)
+	s.router.POST("/users/:id/demote", ctrl.DemoteUser)
+	s.router.POST("/users/:id/disable", ctrl.DisableUser)
+	s.router.POST("/users/:id/enable", ctrl.EnableUser)
+	s.router.POST("/users/:id/unlock", ctrl.UnlockUser)
+	s.router.DELETE("/users/:id", ctrl.DeleteUser)
+}
+
+func TestAdminControllerSuite(t *testing.T) {
+	suite.Run(t, new(AdminControllerSuite))
+}
+
+func (s *AdminControllerSuite) do(method, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *AdminControllerSuite) TestListUsers_HidesPasswords() {
+	page := &domain.UserPage{
+		Users:      []*domain.User{{ID: "u1", Username: "john", Password: "hashed", Role: domain.RoleUser}},
+		NextCursor: "next",
+	}
+	s.userUsecase.On("ListUsers", mock.Anything, domain.UserQuery{Search: "jo", Limit: 5, Cursor: "abc"}).Return(page, nil).Once()
+
+	res := s.do("GET", "/users?search=jo&limit=5&cursor=abc")
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"username":"john"`)
+	assert.Contains(s.T(), res.Body.String(), `"next_cursor":"next"`)
+	assert.NotContains(s.T(), res.Body.String(), "hashed")
+}
+
+func (s *AdminControllerSuite) TestListUsers_BadQuery() {
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("GET", "/users?limit=many").Code)
+
+	s.userUsecase.On("ListUsers", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidCursor).Once()
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("GET", "/users?cursor=bad").Code)
+}
+
+func (s *AdminControllerSuite) TestDemoteUser() {
+	s.userUsecase.On("DemoteUser", mock.Anything, "u1").Return(nil).Once()
+	s.userUsecase.On("DemoteUser", mock.Anything, "last").Return(domain.ErrLastAdmin).Once()
+	s.userUsecase.On("DemoteUser", mock.Anything, "plain").Return(domain.ErrNotAdmin).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.do("POST", "/users/u1/demote").Code)
+	assert.Equal(s.T(), http.StatusConflict, s.do("POST", "/users/last/demote").Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("POST", "/users/plain/demote").Code)
+}
+
+func (s *AdminControllerSuite) TestDisableAndEnableUser() {
+	s.userUsecase.On("SetUserDisabled", mock.Anything, "u1", true).Return(nil).Once()
+	s.userUsecase.On("SetUserDisabled", mock.Anything, "u1", false).Return(nil).Once()
+	s.userUsecase.On("SetUserDisabled", mock.Anything, "missing", true).Return(domain.ErrUserNotFound).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.do("POST", "/users/u1/disable").Code)
+	assert.Equal(s.T(), http.StatusOK, s.do("POST", "/users/u1/enable").Code)
+	assert.Equal(s.T(), http.StatusNotFound, s.do("POST", "/users/missing/disable").Code)
+	s.userUsecase.AssertExpectations(s.T())
+}
+
+func (s *AdminControllerSuite) TestDeleteUser() {
+	s.userUsecase.On("DeleteUser", mock.Anything, "u1").Return(nil).Once()
+	s.userUsecase.On("DeleteUser", mock.Anything, "last").Return(domain.ErrLastAdmin).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.do("DELETE", "/users/u1").Code)
+	assert.Equal(s.T(), http.StatusConflict, s.do("DELETE", "/users/last").Code)
+}
+
+func (s *AdminControllerSuite) TestUnlockUser() {
+	s.userUsecase.On("UnlockUser", mock.Anything, "u1").Return(nil).Once()
+	s.userUsecase.On("UnlockUser", mock.Anything, "missing").Return(domain.ErrUserNotFound).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.do("POST", "/users/u1/unlock").Code)
+	assert.Equal(s.T(), http.StatusNotFound, s.do("POST", "/users/missing/unlock").Code)
+}
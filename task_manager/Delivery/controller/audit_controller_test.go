@@ -0,0 +1,149 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditControllerSuite struct {
+	suite.Suite
+	auditUsecase *mocks.AuditUsecases
+	router       *gin.Engine
+	entries      []*domain.AuditEntry
+}
+
+func (s *AuditControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.auditUsecase = new(mocks.AuditUsecases)
+
+	ctrl := &controller.Controller{AuditUsecases: s.auditUsecase}
+	s.router = gin.New()
+	s.router.GET("/audit", ctrl.QueryAuditLog)
+	s.router.GET("/audit/export", ctrl.ExportAuditLog)
+	s.router.GET("/audit/verify", ctrl.VerifyAuditLog)
+
+	s.entries = []*domain.AuditEntry{
+		{ID: "a1", Sequence: 1, ActorID: "u1", Action: domain.AuditTaskCreate, TargetType: domain.AuditTargetTask, TargetID: "t1",
+			After: json.RawMessage(`{"Title":"Write report"}`), Hash: "h1"},
+		{ID: "a2", Sequence: 2, ActorID: "u1", Action: domain.AuditTaskDelete, TargetType: domain.AuditTargetTask, TargetID: "t1",
+			Before: json.RawMessage(`{"Title":"Write report"}`), PrevHash: "h1", Hash: "h2"},
+	}
+}
+
+func TestAuditControllerSuite(t *testing.T) {
+	suite.Run(t, new(AuditControllerSuite))
+}
+
+func (s *AuditControllerSuite) serve(url string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *AuditControllerSuite) TestQueryAuditLog_PassesFilters() {
+	assert := assert.New(s.T())
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	s.auditUsecase.On("QueryAuditLog", mock.Anything, domain.AuditQuery{
+		ActorID: "u1", TargetType: domain.AuditTargetTask, TargetID: "t1", From: from, To: to, Limit: 2, Cursor: "c",
+	}).Return(&domain.AuditPage{Entries: s.entries, NextCursor: "next"}, nil)
+
+	res := s.serve("/audit?actor=u1&target_type=task&target=t1&from=2025-06-01&to=2025-06-02T12:00:00Z&limit=2&cursor=c")
+
+	assert.Equal(http.StatusOK, res.Code)
+	var body struct {
+		Entries []struct {
+			ID     string          `json:"id"`
+			Before json.RawMessage `json:"before"`
+			After  json.RawMessage `json:"after"`
+			Hash   string          `json:"hash"`
+		} `json:"entries"`
+		NextCursor string `json:"next_cursor"`
+	}
+	require.NoError(s.T(), json.Unmarshal(res.Body.Bytes(), &body))
+	require.Len(s.T(), body.Entries, 2)
+	assert.JSONEq(`{"Title":"Write report"}`, string(body.Entries[0].After), "snapshots are embedded as JSON")
+	assert.Equal("null", string(body.Entries[0].Before))
+	assert.Equal("h2", body.Entries[1].Hash)
+	assert.Equal("next", body.NextCursor)
+}
+
+func (s *AuditControllerSuite) TestQueryAuditLog_BadRequests() {
+	s.auditUsecase.On("QueryAuditLog", mock.Anything, mock.Anything).
+		Return(nil, domain.ErrInvalidAuditQuery)
+
+	assert.Equal(s.T(), http.StatusBadRequest, s.serve("/audit?from=yesterday").Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.serve("/audit?limit=0").Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.serve("/audit?from=2025-06-02&to=2025-06-01").Code)
+
+	// With both bounds invalid, the error always names from.
+	for i := 0; i < 10; i++ {
+		res := s.serve("/audit?from=soon&to=later")
+		assert.Equal(s.T(), http.StatusBadRequest, res.Code)
+		assert.Contains(s.T(), res.Body.String(), `invalid from`)
+	}
+}
+
+func (s *AuditControllerSuite) TestExportAuditLog_StreamsNDJSON() {
+	s.auditUsecase.On("ExportAuditLog", mock.Anything, domain.AuditQuery{TargetID: "t1"}, mock.Anything).
+		Return(func(ctx context.Context, query domain.AuditQuery, write func(*domain.AuditEntry) error) error {
+			for _, entry := range s.entries {
+				if err := write(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	res := s.serve("/audit/export?target=t1")
+
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Equal(s.T(), "application/x-ndjson", res.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(res.Body.String()), "\n")
+	require.Len(s.T(), lines, 2)
+	var entry map[string]any
+	require.NoError(s.T(), json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(s.T(), "a2", entry["id"])
+	assert.Equal(s.T(), "h1", entry["prev_hash"])
+}
+
+func (s *AuditControllerSuite) TestExportAuditLog_EmptyAndFailed() {
+	s.auditUsecase.On("ExportAuditLog", mock.Anything, domain.AuditQuery{TargetID: "none"}, mock.Anything).Return(nil)
+	s.auditUsecase.On("ExportAuditLog", mock.Anything, domain.AuditQuery{TargetID: "broken"}, mock.Anything).
+		Return(errors.New("database down"))
+
+	res := s.serve("/audit/export?target=none")
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Equal(s.T(), "application/x-ndjson", res.Header().Get("Content-Type"))
+	assert.Empty(s.T(), res.Body.String())
+
+	// Nothing was sent yet, so the failure can still be a status.
+	assert.Equal(s.T(), http.StatusInternalServerError, s.serve("/audit/export?target=broken").Code)
+}
+
+func (s *AuditControllerSuite) TestVerifyAuditLog() {
+	s.auditUsecase.On("VerifyAuditLog", mock.Anything).
+		Return(&domain.AuditVerification{Entries: 7, BrokenAt: 4, Problem: "does not match its hash"}, nil)
+
+	res := s.serve("/audit/verify")
+
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.JSONEq(s.T(), `{"entries":7,"valid":false,"broken_at":4,"problem":"does not match its hash"}`, res.Body.String())
+}
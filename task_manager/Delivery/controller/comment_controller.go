@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListComments returns a task's comments as threads: top-level comments
+// oldest first, each with its replies nested under "replies"
+func (cr *Controller) ListComments(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	comments, err := cr.CommentUsecases.ListComments(ctx, ctx.Param("id"), actor)
+	if err != nil {
+		respondCommentError(ctx, err, "Failed to retrieve comments")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"comments": commentThreads(comments)})
+}
+
+// AddComment posts a comment on a task, or a reply to one of its comments
+// when parent_id is given
+func (cr *Controller) AddComment(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+	var request struct {
+		Body     string `json:"body" binding:"required"`
+		ParentID string `json:"parent_id"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Comment body is required"})
+		return
+	}
+
+	comment, err := cr.CommentUsecases.AddComment(ctx, ctx.Param("id"), request.ParentID, request.Body, actor)
+	if err != nil {
+		respondCommentError(ctx, err, "Failed to add comment")
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Comment added successfully", "comment": commentResponse(comment)})
+}
+
+// EditComment changes the body of the caller's own comment; the previous
+// body is kept in the comment's revisions
+func (cr *Controller) EditComment(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+	var request struct {
+		Body string `json:"body" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Comment body is required"})
+		return
+	}
+
+	comment, err := cr.CommentUsecases.EditComment(ctx, ctx.Param("id"), ctx.Param("commentId"), request.Body, actor)
+	if err != nil {
+		respondCommentError(ctx, err, "Failed to edit comment")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Comment updated successfully", "comment": commentResponse(comment)})
+}
+
+// DeleteComment deletes the caller's own comment, or any comment for
+// holders of comment:moderate
+func (cr *Controller) DeleteComment(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	if err := cr.CommentUsecases.DeleteComment(ctx, ctx.Param("id"), ctx.Param("commentId"), actor); err != nil {
+		respondCommentError(ctx, err, "Failed to delete comment")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+// respondCommentError maps comment errors onto HTTP responses, leaving
+// errors about the task itself to respondTaskError.
+func respondCommentError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidComment):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrCommentNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+	case errors.Is(err, domain.ErrNotCommentAuthor):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		respondTaskError(ctx, err, fallback)
+	}
+}
+
+// commentThreads nests replies under the comments they answer. comments
+// is oldest first, so every parent is seen before its replies and each
+// thread stays in order.
+func commentThreads(comments []*domain.Comment) []gin.H {
+	threads := make([]gin.H, 0)
+	byID := make(map[string]gin.H, len(comments))
+	for _, comment := range comments {
+		response := commentResponse(comment)
+		response["replies"] = make([]gin.H, 0)
+		byID[comment.ID] = response
+
+		parent, ok := byID[comment.ParentID]
+		if !ok {
+			threads = append(threads, response)
+			continue
+		}
+		parent["replies"] = append(parent["replies"].([]gin.H), response)
+	}
+	return threads
+}
+
+func commentResponse(comment *domain.Comment) gin.H {
+	revisions := make([]gin.H, 0, len(comment.Revisions))
+	for _, revision := range comment.Revisions {
+		revisions = append(revisions, gin.H{"body": revision.Body, "written_at": revision.WrittenAt})
+	}
+	return gin.H{
+		"id":         comment.ID,
+		"task_id":    comment.TaskID,
+		"parent_id":  comment.ParentID,
+		"author_id":  comment.AuthorID,
+		"body":       comment.Body,
+		"created_at": comment.CreatedAt,
+		"edited_at":  comment.EditedAt,
+		"revisions":  revisions,
+		"deleted":    comment.Deleted(),
+		"deleted_by": comment.DeletedBy,
+	}
+}
@@ -0,0 +1,146 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CommentControllerSuite struct {
+	suite.Suite
+	commentUsecase *mocks.CommentUsecases
+	userUsecase    *mocks.UserUsecases
+	router         *gin.Engine
+	user           *domain.User
+}
+
+func (s *CommentControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.commentUsecase = new(mocks.CommentUsecases)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.user = &domain.User{ID: "u1"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(s.user, nil)
+
+	ctrl := &controller.Controller{UserUsecases: s.userUsecase, CommentUsecases: s.commentUsecase}
+	s.router = gin.New()
+	s.router.GET("/tasks/:id/comments", ctrl.ListComments)
+	s.router.POST("/tasks/:id/comments", ctrl.AddComment)
+	s.router.PATCH("/tasks/:id/comments/:commentId", ctrl.EditComment)
+	s.router.DELETE("/tasks/:id/comments/:commentId", ctrl.DeleteComment)
+}
+
+func TestCommentControllerSuite(t *testing.T) {
+	suite.Run(t, new(CommentControllerSuite))
+}
+
+func (s *CommentControllerSuite) serve(method, url string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, url, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *CommentControllerSuite) TestListComments_NestsReplies() {
+	assert := assert.New(s.T())
+	deletedAt := time.Now()
+	s.commentUsecase.On("ListComments", mock.Anything, "t1", &domain.Actor{User: s.user}).Return([]*domain.Comment{
+		{ID: "a", TaskID: "t1", AuthorID: "u1", Body: "first"},
+		{ID: "b", TaskID: "t1", AuthorID: "u2", DeletedAt: &deletedAt, DeletedBy: "u9"},
+		{ID: "c", TaskID: "t1", ParentID: "a", AuthorID: "u2", Body: "reply"},
+		{ID: "d", TaskID: "t1", ParentID: "c", AuthorID: "u1", Body: "reply to reply"},
+		{ID: "e", TaskID: "t1", ParentID: "b", AuthorID: "u1", Body: "orphaned"},
+	}, nil)
+
+	res := s.serve("GET", "/tasks/t1/comments", nil)
+
+	assert.Equal(http.StatusOK, res.Code)
+	var body struct {
+		Comments []struct {
+			ID      string `json:"id"`
+			Body    string `json:"body"`
+			Deleted bool   `json:"deleted"`
+			Replies []struct {
+				ID      string `json:"id"`
+				Replies []struct {
+					ID string `json:"id"`
+				} `json:"replies"`
+			} `json:"replies"`
+		} `json:"comments"`
+	}
+	assert.NoError(json.Unmarshal(res.Body.Bytes(), &body))
+	if assert.Len(body.Comments, 2) {
+		assert.Equal("a", body.Comments[0].ID)
+		if assert.Len(body.Comments[0].Replies, 1) {
+			assert.Equal("c", body.Comments[0].Replies[0].ID)
+			assert.Equal("d", body.Comments[0].Replies[0].Replies[0].ID)
+		}
+		assert.True(body.Comments[1].Deleted)
+		assert.Empty(body.Comments[1].Body)
+		assert.Equal("e", body.Comments[1].Replies[0].ID)
+	}
+}
+
+func (s *CommentControllerSuite) TestAddComment() {
+	assert := assert.New(s.T())
+	s.commentUsecase.On("AddComment", mock.Anything, "t1", "a", "Agreed", mock.Anything).
+		Return(&domain.Comment{ID: "c", TaskID: "t1", ParentID: "a", AuthorID: "u1", Body: "Agreed"}, nil)
+
+	res := s.serve("POST", "/tasks/t1/comments", map[string]string{"body": "Agreed", "parent_id": "a"})
+
+	assert.Equal(http.StatusCreated, res.Code)
+	assert.Contains(res.Body.String(), `"parent_id":"a"`)
+	assert.Equal(http.StatusBadRequest, s.serve("POST", "/tasks/t1/comments", map[string]string{}).Code)
+}
+
+func (s *CommentControllerSuite) TestEditComment_Revisions() {
+	assert := assert.New(s.T())
+	editedAt := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	s.commentUsecase.On("EditComment", mock.Anything, "t1", "a", "second", mock.Anything).Return(&domain.Comment{
+		ID: "a", TaskID: "t1", Body: "second", EditedAt: &editedAt,
+		Revisions: []domain.CommentRevision{{Body: "first", WrittenAt: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)}},
+	}, nil)
+
+	res := s.serve("PATCH", "/tasks/t1/comments/a", map[string]string{"body": "second"})
+
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), `"revisions":[{"body":"first","written_at":"2025-08-01T00:00:00Z"}]`)
+	assert.Contains(res.Body.String(), `"edited_at":"2025-08-02T00:00:00Z"`)
+}
+
+func (s *CommentControllerSuite) TestErrors() {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{domain.ErrInvalidComment, http.StatusBadRequest},
+		{domain.ErrNotCommentAuthor, http.StatusForbidden},
+		{domain.ErrCommentNotFound, http.StatusNotFound},
+		{domain.ErrTaskNotFound, http.StatusNotFound},
+		{domain.ErrForbidden, http.StatusForbidden},
+	}
+	for _, c := range cases {
+		s.SetupTest()
+		s.commentUsecase.On("EditComment", mock.Anything, "t1", "a", "x", mock.Anything).Return(nil, c.err)
+		s.commentUsecase.On("DeleteComment", mock.Anything, "t1", "a", mock.Anything).Return(c.err)
+
+		assert.Equal(s.T(), c.want, s.serve("PATCH", "/tasks/t1/comments/a", map[string]string{"body": "x"}).Code, c.err.Error())
+		assert.Equal(s.T(), c.want, s.serve("DELETE", "/tasks/t1/comments/a", nil).Code, c.err.Error())
+	}
+}
@@ -2,23 +2,36 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	domain "task_manager/Domain"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Controller holds the handlers of the HTTP API. Build it with a struct
+// literal naming the usecases the routes in use need.
 type Controller struct {
-	TaskUsecases domain.TaskUsecases
-	UserUsecases domain.UserUsecases
-}
-
-func NewController(tu domain.TaskUsecases, uu domain.UserUsecases) *Controller {
-	return &Controller{
-		TaskUsecases: tu,
-		UserUsecases: uu,
-	}
+	TaskUsecases          domain.TaskUsecases
+	UserUsecases          domain.UserUsecases
+	TokenUsecases         domain.TokenUsecases
+	RoleUsecases          domain.RoleUsecases
+	PasswordResetUsecases domain.PasswordResetUsecases
+	VerificationUsecases  domain.EmailVerificationUsecases
+	TwoFactorUsecases     domain.TwoFactorUsecases
+	PersonalTokenUsecases domain.PersonalAccessTokenUsecases
+	OIDCUsecases          domain.OIDCUsecases
+	CommentUsecases       domain.CommentUsecases
+	LabelUsecases         domain.LabelUsecases
+	ReminderUsecases      domain.ReminderUsecases
+	WebhookUsecases       domain.WebhookUsecases
+	AuditUsecases         domain.AuditUsecases
 }
 
 // Register handles user registration
@@ -41,14 +54,23 @@ func (cr *Controller) Register(ctx *gin.Context) {
 		return
 	}
 
+	// The account exists either way; a failed email can be resent.
+	emailSent := true
+	if err := cr.VerificationUsecases.SendVerificationEmail(ctx, createdUser); err != nil {
+		log.Printf("verification email for user %s failed: %v", createdUser.ID, err)
+		emailSent = false
+	}
+
 	response := gin.H{
 		"message": "User registered successfully",
 		"user": gin.H{
-			"id":       createdUser.ID,
-			"username": createdUser.Username,
-			"email":    createdUser.Email,
-			"role":     createdUser.Role,
+			"id":             createdUser.ID,
+			"username":       createdUser.Username,
+			"email":          createdUser.Email,
+			"role":           createdUser.Role,
+			"email_verified": !createdUser.EmailUnverified,
 		},
+		"verification_email_sent": emailSent,
 	}
 	
 	ctx.JSON(http.StatusCreated, response)
@@ -66,14 +88,114 @@ func (cr *Controller) Login(ctx *gin.Context) {
 		return
 	}
 
-	token, _ := cr.UserUsecases.Login(ctx, loginRequest.Email, loginRequest.Password)
+	result, err := cr.UserUsecases.Login(ctx, loginRequest.Email, loginRequest.Password, ctx.ClientIP())
+	if err != nil {
+		var lockout *domain.LockoutError
+		if errors.As(err, &lockout) {
+			respondLockout(ctx, lockout)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+			return
+		}
+		if errors.Is(err, domain.ErrAccountDisabled) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in"})
+		return
+	}
+
+	if challenge := result.Challenge; challenge != nil {
+		ctx.JSON(http.StatusOK, gin.H{
+			"message":             "Two-factor authentication required",
+			"two_factor_required": true,
+			"setup_required":      challenge.SetupRequired,
+			"challenge_token":     challenge.Token,
+			"expires_at":          challenge.ExpiresAt,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"token":         result.Tokens.AccessToken,
+		"refresh_token": result.Tokens.RefreshToken,
+	})
+}
+
+// respondLockout refuses a login with 423 for a locked account and 429 for
+// a throttled IP, telling the client when to try again
+func respondLockout(ctx *gin.Context, lockout *domain.LockoutError) {
+	retryAfter := int(math.Ceil(lockout.RetryAfter.Seconds()))
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+	if errors.Is(lockout, domain.ErrAccountLocked) {
+		ctx.JSON(http.StatusLocked, gin.H{"error": "Account is temporarily locked after too many failed logins", "retry_after": retryAfter})
+		return
+	}
+	ctx.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed logins from this address", "retry_after": retryAfter})
+}
+
+// RefreshToken exchanges a refresh token for a new access/refresh pair
+func (cr *Controller) RefreshToken(ctx *gin.Context) {
+	var refreshRequest struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&refreshRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
+		return
+	}
+
+	tokens, err := cr.TokenUsecases.RefreshTokens(ctx, refreshRequest.RefreshToken)
+	if err != nil {
+		respondTokenError(ctx, err)
+		return
+	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
+		"message":       "Token refreshed successfully",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
 	})
 }
 
+// Logout revokes the caller's access token and, if supplied, every refresh
+// token descended from the same sign-in
+func (cr *Controller) Logout(ctx *gin.Context) {
+	var logoutRequest struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	// The body is optional; without it only the access token is revoked.
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&logoutRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+			return
+		}
+	}
+
+	if err := cr.TokenUsecases.Logout(ctx, logoutRequest.RefreshToken); err != nil {
+		respondTokenError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func respondTokenError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTokenReused):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token was already used; all sessions from this sign-in have been revoked"})
+	case errors.Is(err, domain.ErrAccountDisabled):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+	case errors.Is(err, domain.ErrTokenRevoked), errors.Is(err, domain.ErrInvalidToken), errors.Is(err, domain.ErrUnauthorized):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process token"})
+	}
+}
+
 // PromoteUser allows admins to promote other users to admin role
 func (cr *Controller) PromoteUser(ctx *gin.Context) {
 	var promoteRequest struct {
@@ -95,13 +217,13 @@ func (cr *Controller) PromoteUser(ctx *gin.Context) {
 		return
 	}
 
-	if user.Role == "admin" {
+	if user.Role == domain.RoleAdmin {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "User is already an admin"})
 		return	
 	}
 	
 	// Update user role to admin
-	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	err = cr.UserUsecases.PromoteUserToAdmin(dbCtx, promoteRequest.UserID)
@@ -116,41 +238,181 @@ func (cr *Controller) PromoteUser(ctx *gin.Context) {
 			"id":       user.ID,
 			"username": user.Username,
 			"email":    user.Email,
-			"role":     "admin",
+			"role":     domain.RoleAdmin,
 		},
 	})
 }
 
+// AdminOverrideHeader lets an admin explicitly act on a task they do not own.
+const AdminOverrideHeader = "X-Admin-Override"
+
+// PermissionsContextKey is where the permission middleware stores the
+// permissions of the authenticated user's role.
+const PermissionsContextKey = "permissions"
+
+// currentActor builds the actor for task operations from the authenticated
+// user, their role's permissions and the admin override header.
+func (cr *Controller) currentActor(ctx *gin.Context) (*domain.Actor, bool) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+	override, _ := strconv.ParseBool(ctx.GetHeader(AdminOverrideHeader))
+	permissions, _ := ctx.Value(PermissionsContextKey).([]string)
+	return &domain.Actor{User: user, Permissions: permissions, AdminOverride: override}, true
+}
+
+// respondTaskError maps task usecase errors onto HTTP responses. Tasks the
+// actor may not see are reported as missing rather than forbidden.
+func respondTaskError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidStatus), errors.Is(err, domain.ErrInvalidParentTask), errors.Is(err, domain.ErrInvalidChecklistItem),
+		errors.Is(err, domain.ErrInvalidDependency), errors.Is(err, domain.ErrInvalidRecurrence), errors.Is(err, domain.ErrInvalidSeriesScope):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrOpenSubtasks), errors.Is(err, domain.ErrTaskHasSubtasks),
+		errors.Is(err, domain.ErrDependencyCycle), errors.Is(err, domain.ErrOpenBlockers), errors.Is(err, domain.ErrNotRecurring):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrTaskNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	case errors.Is(err, domain.ErrChecklistItemNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Checklist item not found"})
+	case errors.Is(err, domain.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Access to this task requires the " + AdminOverrideHeader + " header"})
+	case errors.Is(err, domain.ErrUnauthorized):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
 // Task Handlers (Updated with admin checks)
 func (cr *Controller) GetAllTasks(ctx *gin.Context) {
-	// Get user from context (set by AuthMiddlewarre)
-	// user, _ := infrastructure.GetUserFromContext(ctx)
-	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
 
-	tasks, err := cr.TaskUsecases.GetAllTasks(ctx, user.ID)
+	query, err := parseTaskQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
+	page, err := cr.TaskUsecases.GetAllTasks(ctx, query, actor)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tasks"})
+		if errors.Is(err, domain.ErrInvalidTaskQuery) || errors.Is(err, domain.ErrInvalidCursor) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Listing another user's tasks requires an admin override"})
+			return
+		}
+		respondTaskError(ctx, err, "Failed to retrieve tasks")
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"tasks": tasks,
+		"tasks":       page.Tasks,
+		"next_cursor": page.NextCursor,
 	})
 }
 
-func (cr *Controller) GetTask(ctx *gin.Context) {
+// parseTaskQuery reads the GET /tasks query string: user_id (admins, with an
+// override), status, due_date (a
+// single day), due_from/due_to (a range), sort, order (asc|desc), limit and
+// cursor. Dates may be given as YYYY-MM-DD or RFC 3339.
+func parseTaskQuery(ctx *gin.Context) (domain.TaskQuery, error) {
+	query := domain.TaskQuery{
+		UserID: ctx.Query("user_id"),
+		Status: ctx.Query("status"),
+		SortBy: ctx.Query("sort"),
+		Cursor: ctx.Query("cursor"),
+	}
 
-	id := ctx.Param("id")
-	task, err := cr.TaskUsecases.GetTaskByID(ctx, id)
+	if day := ctx.Query("due_date"); day != "" {
+		if ctx.Query("due_from") != "" || ctx.Query("due_to") != "" {
+			return query, fmt.Errorf("due_date cannot be combined with due_from or due_to")
+		}
+		from, err := time.Parse(time.DateOnly, day)
+		if err != nil {
+			return query, fmt.Errorf("invalid due_date %q, expected YYYY-MM-DD", day)
+		}
+		query.DueFrom, query.DueTo = from, from.AddDate(0, 0, 1)
+	}
+	if err := parseTimeParam(ctx, "due_from", &query.DueFrom); err != nil {
+		return query, err
+	}
+	if err := parseTimeParam(ctx, "due_to", &query.DueTo); err != nil {
+		return query, err
+	}
+
+	if labels := ctx.Query("labels"); labels != "" {
+		for _, label := range strings.Split(labels, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				query.Labels = append(query.Labels, label)
+			}
+		}
+	}
+	switch match := ctx.DefaultQuery("label_match", "any"); match {
+	case "any":
+	case "all":
+		query.MatchAllLabels = true
+	default:
+		return query, fmt.Errorf("invalid label_match %q, expected any or all", match)
+	}
+
+	switch order := ctx.DefaultQuery("order", "asc"); order {
+	case "asc":
+	case "desc":
+		query.SortDesc = true
+	default:
+		return query, fmt.Errorf("invalid order %q, expected asc or desc", order)
+	}
 
+	if limit := ctx.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return query, fmt.Errorf("invalid limit %q", limit)
+		}
+		query.Limit = n
+	}
+	return query, nil
+}
+
+// parseTimeParam parses the query parameter param into dst, leaving dst
+// alone when the parameter is not given.
+func parseTimeParam(ctx *gin.Context, param string, dst *time.Time) error {
+	value := ctx.Query(param)
+	if value == "" {
+		return nil
+	}
+	t, err := parseQueryTime(value)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
+		return fmt.Errorf("invalid %s %q", param, value)
+	}
+	*dst = t
+	return nil
+}
+
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (cr *Controller) GetTask(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
 		return
 	}
 
-	if task == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	id := ctx.Param("id")
+	task, err := cr.TaskUsecases.GetTaskByID(ctx, id, actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to retrieve task")
 		return
 	}
 
@@ -161,29 +423,24 @@ func (cr *Controller) GetTask(ctx *gin.Context) {
 
 func (cr *Controller) RemoveTask(ctx *gin.Context) {
 	// Get user from context (set by AuthMiddleware)
-	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
-
-	if user == nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
 		return
 	}
 
 	id := ctx.Param("id")
-	 task, err := cr.TaskUsecases.GetTaskByID(ctx, id); 
-	 if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
-		return
-	 }
-	 
-	 if task != nil {
-		cr.TaskUsecases.DeleteTask(ctx, id)
-		ctx.JSON(http.StatusOK, gin.H{"message": "Task removed successfully"})
+	if err := cr.TaskUsecases.DeleteTask(ctx, id, actor); err != nil {
+		respondTaskError(ctx, err, "Failed to remove task")
 		return
 	}
-	ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task removed successfully"})
 }
 
 func (cr *Controller) UpdatedTask(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
 
 	id := ctx.Param("id")
 	var updatedTask *domain.Task
@@ -193,21 +450,20 @@ func (cr *Controller) UpdatedTask(ctx *gin.Context) {
 		return
 	}
 
-	task, err := cr.TaskUsecases.GetTaskByID(ctx, id); 
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
-		return
+	// A scope other than "this" applies the update across a recurring
+	// task's series.
+	var task *domain.Task
+	var err error
+	if scope := ctx.Query("scope"); scope != "" {
+		task, err = cr.TaskUsecases.UpdateTaskSeries(ctx, id, updatedTask, scope, actor)
+	} else {
+		task, err = cr.TaskUsecases.UpdateTask(ctx, id, updatedTask, actor)
 	}
-	if task != nil {
-		updatedTask, err := cr.TaskUsecases.UpdateTask(ctx, id, updatedTask)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
-			return
-		}
-		ctx.JSON(http.StatusOK, gin.H{"message": "Task updated successfully", "task": updatedTask})
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to update task")
 		return
 	}
-	ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task updated successfully", "task": task})
 }
 
 func (cr *Controller) AddTask(ctx *gin.Context) {
@@ -224,9 +480,9 @@ func (cr *Controller) AddTask(ctx *gin.Context) {
 	err := cr.TaskUsecases.CreateTask(ctx, newTask, user.ID)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add task"})
+		respondTaskError(ctx, err, "Failed to add task")
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"message": "Task added successfully"})
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Task added successfully", "task": newTask})
 }
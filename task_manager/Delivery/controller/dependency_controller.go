@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTaskBlockers lists the tasks a task is blocked by
+func (cr *Controller) GetTaskBlockers(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	blockers, err := cr.TaskUsecases.GetTaskBlockers(ctx, ctx.Param("id"), actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to retrieve blockers")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"blockers": blockers})
+}
+
+// GetTaskDependents lists the tasks a task blocks
+func (cr *Controller) GetTaskDependents(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	dependents, err := cr.TaskUsecases.GetTaskDependents(ctx, ctx.Param("id"), actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to retrieve dependents")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"dependents": dependents})
+}
+
+// AddTaskBlocker records that a task is blocked by another of its owner's
+// tasks. A dependency that would close a cycle is refused with the cycle.
+func (cr *Controller) AddTaskBlocker(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	err := cr.TaskUsecases.AddTaskBlocker(ctx, ctx.Param("id"), ctx.Param("blockerId"), actor)
+	var cycle *domain.DependencyCycleError
+	if errors.As(err, &cycle) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error(), "cycle": cycle.Path})
+		return
+	}
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to add blocker")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Blocker added successfully"})
+}
+
+// RemoveTaskBlocker unlinks a blocker from a task
+func (cr *Controller) RemoveTaskBlocker(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	if err := cr.TaskUsecases.RemoveTaskBlocker(ctx, ctx.Param("id"), ctx.Param("blockerId"), actor); err != nil {
+		respondTaskError(ctx, err, "Failed to remove blocker")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Blocker removed successfully"})
+}
+
+// GetReadyTasks lists the caller's open tasks, or with user_id another
+// user's (admins, with the override header), in an order work can follow
+func (cr *Controller) GetReadyTasks(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := cr.TaskUsecases.GetReadyTasks(ctx, ctx.Query("user_id"), actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to retrieve ready tasks")
+		return
+	}
+	if tasks == nil {
+		tasks = []*domain.ReadyTask{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
@@ -0,0 +1,126 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DependencyControllerSuite struct {
+	suite.Suite
+	taskUsecase *mocks.TaskUsecases
+	userUsecase *mocks.UserUsecases
+	router      *gin.Engine
+	user        *domain.User
+}
+
+func (s *DependencyControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.taskUsecase = new(mocks.TaskUsecases)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.user = &domain.User{ID: "u1"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(s.user, nil)
+
+	ctrl := &controller.Controller{TaskUsecases: s.taskUsecase, UserUsecases: s.userUsecase}
+	s.router = gin.New()
+	s.router.GET("/tasks/ready", ctrl.GetReadyTasks)
+	s.router.GET("/tasks/:id/blockers", ctrl.GetTaskBlockers)
+	s.router.GET("/tasks/:id/dependents", ctrl.GetTaskDependents)
+	s.router.PUT("/tasks/:id/blockers/:blockerId", ctrl.AddTaskBlocker)
+	s.router.DELETE("/tasks/:id/blockers/:blockerId", ctrl.RemoveTaskBlocker)
+}
+
+func TestDependencyControllerSuite(t *testing.T) {
+	suite.Run(t, new(DependencyControllerSuite))
+}
+
+func (s *DependencyControllerSuite) serve(method, url string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, nil)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *DependencyControllerSuite) TestBlockersAndDependents() {
+	assert := assert.New(s.T())
+	actor := &domain.Actor{User: s.user}
+	s.taskUsecase.On("GetTaskBlockers", mock.Anything, "t1", actor).Return([]*domain.Task{{ID: "b1"}}, nil)
+	s.taskUsecase.On("GetTaskDependents", mock.Anything, "t1", actor).Return([]*domain.Task{{ID: "d1"}}, nil)
+	s.taskUsecase.On("AddTaskBlocker", mock.Anything, "t1", "b1", actor).Return(nil)
+	s.taskUsecase.On("RemoveTaskBlocker", mock.Anything, "t1", "b1", actor).Return(nil)
+
+	res := s.serve("GET", "/tasks/t1/blockers")
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), `"blockers":[{"ID":"b1"`)
+	res = s.serve("GET", "/tasks/t1/dependents")
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), `"dependents":[{"ID":"d1"`)
+	assert.Equal(http.StatusOK, s.serve("PUT", "/tasks/t1/blockers/b1").Code)
+	assert.Equal(http.StatusOK, s.serve("DELETE", "/tasks/t1/blockers/b1").Code)
+}
+
+func (s *DependencyControllerSuite) TestAddTaskBlocker_Cycle() {
+	assert := assert.New(s.T())
+	s.taskUsecase.On("AddTaskBlocker", mock.Anything, "a", "b", mock.Anything).
+		Return(&domain.DependencyCycleError{Path: []string{"a", "b", "a"}})
+
+	res := s.serve("PUT", "/tasks/a/blockers/b")
+
+	assert.Equal(http.StatusConflict, res.Code)
+	var body struct {
+		Cycle []string `json:"cycle"`
+	}
+	assert.NoError(json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal([]string{"a", "b", "a"}, body.Cycle)
+}
+
+func (s *DependencyControllerSuite) TestGetReadyTasks() {
+	assert := assert.New(s.T())
+	s.taskUsecase.On("GetReadyTasks", mock.Anything, "", mock.Anything).Return([]*domain.ReadyTask{
+		{Task: &domain.Task{ID: "a"}, Depth: 0},
+		{Task: &domain.Task{ID: "b"}, Depth: 1},
+	}, nil)
+	s.taskUsecase.On("GetReadyTasks", mock.Anything, "u2", mock.Anything).Return(nil, domain.ErrForbidden)
+
+	res := s.serve("GET", "/tasks/ready")
+	assert.Equal(http.StatusOK, res.Code)
+	var body struct {
+		Tasks []struct {
+			ID    string
+			Depth int
+		} `json:"tasks"`
+	}
+	assert.NoError(json.Unmarshal(res.Body.Bytes(), &body))
+	if assert.Len(body.Tasks, 2) {
+		assert.Equal("b", body.Tasks[1].ID)
+		assert.Equal(1, body.Tasks[1].Depth)
+	}
+	assert.Equal(http.StatusForbidden, s.serve("GET", "/tasks/ready?user_id=u2").Code)
+}
+
+func (s *DependencyControllerSuite) TestErrors() {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{domain.ErrInvalidDependency, http.StatusBadRequest},
+		{&domain.OpenBlockersError{TaskID: "a", Blockers: []string{"b"}}, http.StatusConflict},
+		{domain.ErrTaskNotFound, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		s.SetupTest()
+		s.taskUsecase.On("AddTaskBlocker", mock.Anything, "a", "b", mock.Anything).Return(c.err)
+
+		assert.Equal(s.T(), c.want, s.serve("PUT", "/tasks/a/blockers/b").Code, c.err.Error())
+	}
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jwksMaxAge is how long verifiers may cache the key set. New keys are
+// published a whole grace period before they sign anything, which is far
+// longer.
+const jwksMaxAge = "public, max-age=300"
+
+// JWKS publishes the public keys access tokens are signed with, so other
+// services can verify them
+func (cr *Controller) JWKS(ctx *gin.Context) {
+	keys, err := cr.TokenUsecases.PublicKeys(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load signing keys"})
+		return
+	}
+
+	response := make([]gin.H, 0, len(keys))
+	for _, key := range keys {
+		jwk := gin.H{"kid": key.KeyID, "kty": key.KeyType, "alg": key.Algorithm, "use": key.Use}
+		if key.KeyType == "RSA" {
+			jwk["n"] = key.N
+			jwk["e"] = key.E
+		} else {
+			jwk["crv"] = key.Curve
+			jwk["x"] = key.X
+		}
+		response = append(response, jwk)
+	}
+	ctx.Header("Cache-Control", jwksMaxAge)
+	ctx.JSON(http.StatusOK, gin.H{"keys": response})
+}
@@ -0,0 +1,51 @@
+package controller_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func serveJWKS(tokens *mocks.TokenUsecases) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ctrl := &controller.Controller{TokenUsecases: tokens}
+	engine := gin.New()
+	engine.GET("/.well-known/jwks.json", ctrl.JWKS)
+
+	req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	res := httptest.NewRecorder()
+	engine.ServeHTTP(res, req)
+	return res
+}
+
+func TestJWKS(t *testing.T) {
+	tokens := new(mocks.TokenUsecases)
+	tokens.On("PublicKeys", mock.Anything).Return([]*domain.JSONWebKey{
+		{KeyID: "k1", KeyType: "RSA", Algorithm: domain.SigningAlgorithmRS256, Use: "sig", N: "modulus", E: "AQAB"},
+		{KeyID: "k2", KeyType: "OKP", Algorithm: domain.SigningAlgorithmEdDSA, Use: "sig", Curve: "Ed25519", X: "point"},
+	}, nil)
+
+	res := serveJWKS(tokens)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Header().Get("Cache-Control"), "max-age")
+	assert.JSONEq(t, `{"keys":[
+		{"kid":"k1","kty":"RSA","alg":"RS256","use":"sig","n":"modulus","e":"AQAB"},
+		{"kid":"k2","kty":"OKP","alg":"EdDSA","use":"sig","crv":"Ed25519","x":"point"}
+	]}`, res.Body.String())
+}
+
+func TestJWKS_Error(t *testing.T) {
+	tokens := new(mocks.TokenUsecases)
+	tokens.On("PublicKeys", mock.Anything).Return(nil, errors.New("database down"))
+
+	assert.Equal(t, http.StatusInternalServerError, serveJWKS(tokens).Code)
+}
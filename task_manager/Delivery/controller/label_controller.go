@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListLabels returns the caller's label catalogue ordered by name
+func (cr *Controller) ListLabels(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	labels, err := cr.LabelUsecases.ListLabels(ctx, actor)
+	if err != nil {
+		respondLabelError(ctx, err, "Failed to retrieve labels")
+		return
+	}
+	response := make([]gin.H, 0, len(labels))
+	for _, label := range labels {
+		response = append(response, labelResponse(label))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"labels": response})
+}
+
+// CreateLabel adds a label to the caller's catalogue
+func (cr *Controller) CreateLabel(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+	var request struct {
+		Name  string `json:"name" binding:"required"`
+		Color string `json:"color"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Label name is required"})
+		return
+	}
+
+	label, err := cr.LabelUsecases.CreateLabel(ctx, request.Name, request.Color, actor)
+	if err != nil {
+		respondLabelError(ctx, err, "Failed to create label")
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Label created successfully", "label": labelResponse(label)})
+}
+
+// UpdateLabel renames and/or recolours one of the caller's labels; fields
+// left out of the body are unchanged
+func (cr *Controller) UpdateLabel(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+	var request struct {
+		Name  *string `json:"name"`
+		Color *string `json:"color"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label, err := cr.LabelUsecases.UpdateLabel(ctx, ctx.Param("id"), request.Name, request.Color, actor)
+	if err != nil {
+		respondLabelError(ctx, err, "Failed to update label")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Label updated successfully", "label": labelResponse(label)})
+}
+
+// DeleteLabel removes one of the caller's labels from the catalogue and
+// from every task carrying it
+func (cr *Controller) DeleteLabel(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	if err := cr.LabelUsecases.DeleteLabel(ctx, ctx.Param("id"), actor); err != nil {
+		respondLabelError(ctx, err, "Failed to delete label")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Label deleted successfully"})
+}
+
+// AddTaskLabel attaches one of the task owner's labels to a task
+func (cr *Controller) AddTaskLabel(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	task, err := cr.TaskUsecases.AddTaskLabel(ctx, ctx.Param("id"), ctx.Param("labelId"), actor)
+	if err != nil {
+		respondLabelError(ctx, err, "Failed to add label")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Label added successfully", "task": task})
+}
+
+// RemoveTaskLabel detaches a label from a task
+func (cr *Controller) RemoveTaskLabel(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	task, err := cr.TaskUsecases.RemoveTaskLabel(ctx, ctx.Param("id"), ctx.Param("labelId"), actor)
+	if err != nil {
+		respondLabelError(ctx, err, "Failed to remove label")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Label removed successfully", "task": task})
+}
+
+// respondLabelError maps label errors onto HTTP responses, leaving errors
+// about tasks to respondTaskError.
+func respondLabelError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidLabel):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrLabelNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
+	case errors.Is(err, domain.ErrLabelAlreadyExists), errors.Is(err, domain.ErrTooManyLabels):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		respondTaskError(ctx, err, fallback)
+	}
+}
+
+func labelResponse(label *domain.Label) gin.H {
+	return gin.H{
+		"id":         label.ID,
+		"name":       label.Name,
+		"color":      label.Color,
+		"created_at": label.CreatedAt,
+	}
+}
@@ -0,0 +1,136 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LabelControllerSuite struct {
+	suite.Suite
+	labelUsecase *mocks.LabelUsecases
+	taskUsecase  *mocks.TaskUsecases
+	userUsecase  *mocks.UserUsecases
+	router       *gin.Engine
+	user         *domain.User
+}
+
+func (s *LabelControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.labelUsecase = new(mocks.LabelUsecases)
+	s.taskUsecase = new(mocks.TaskUsecases)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.user = &domain.User{ID: "u1"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(s.user, nil)
+
+	ctrl := &controller.Controller{
+		TaskUsecases:  s.taskUsecase,
+		UserUsecases:  s.userUsecase,
+		LabelUsecases: s.labelUsecase,
+	}
+	s.router = gin.New()
+	s.router.GET("/tasks", ctrl.GetAllTasks)
+	s.router.GET("/labels", ctrl.ListLabels)
+	s.router.POST("/labels", ctrl.CreateLabel)
+	s.router.PATCH("/labels/:id", ctrl.UpdateLabel)
+	s.router.DELETE("/labels/:id", ctrl.DeleteLabel)
+	s.router.PUT("/tasks/:id/labels/:labelId", ctrl.AddTaskLabel)
+	s.router.DELETE("/tasks/:id/labels/:labelId", ctrl.RemoveTaskLabel)
+}
+
+func TestLabelControllerSuite(t *testing.T) {
+	suite.Run(t, new(LabelControllerSuite))
+}
+
+func (s *LabelControllerSuite) serve(method, url string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, url, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *LabelControllerSuite) TestListLabels() {
+	s.labelUsecase.On("ListLabels", mock.Anything, &domain.Actor{User: s.user}).
+		Return([]*domain.Label{{ID: "l1", Name: "urgent", Color: "#ff0000"}}, nil)
+
+	res := s.serve("GET", "/labels", nil)
+
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"color":"#ff0000"`)
+}
+
+func (s *LabelControllerSuite) TestCreateLabel() {
+	s.labelUsecase.On("CreateLabel", mock.Anything, "urgent", "", mock.Anything).
+		Return(&domain.Label{ID: "l1", Name: "urgent", Color: domain.DefaultLabelColor}, nil)
+
+	assert.Equal(s.T(), http.StatusCreated, s.serve("POST", "/labels", map[string]string{"name": "urgent"}).Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.serve("POST", "/labels", map[string]string{"color": "#ffffff"}).Code)
+}
+
+func (s *LabelControllerSuite) TestUpdateLabel_PartialBody() {
+	s.labelUsecase.On("UpdateLabel", mock.Anything, "l1", (*string)(nil), mock.MatchedBy(func(color *string) bool {
+		return color != nil && *color == "#00ff00"
+	}), mock.Anything).Return(&domain.Label{ID: "l1", Name: "urgent", Color: "#00ff00"}, nil)
+
+	res := s.serve("PATCH", "/labels/l1", map[string]string{"color": "#00ff00"})
+
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	s.labelUsecase.AssertExpectations(s.T())
+}
+
+func (s *LabelControllerSuite) TestAddTaskLabel() {
+	s.taskUsecase.On("AddTaskLabel", mock.Anything, "t1", "l1", &domain.Actor{User: s.user}).
+		Return(&domain.Task{ID: "t1", LabelIDs: []string{"l1"}}, nil)
+
+	res := s.serve("PUT", "/tasks/t1/labels/l1", nil)
+
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"LabelIDs":["l1"]`)
+}
+
+func (s *LabelControllerSuite) TestLabelFilter() {
+	s.taskUsecase.On("GetAllTasks", mock.Anything, domain.TaskQuery{Labels: []string{"urgent", "backend"}, MatchAllLabels: true}, mock.Anything).
+		Return(&domain.TaskPage{}, nil).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.serve("GET", "/tasks?labels=urgent,%20backend,&label_match=all", nil).Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.serve("GET", "/tasks?labels=urgent&label_match=some", nil).Code)
+	s.taskUsecase.AssertExpectations(s.T())
+}
+
+func (s *LabelControllerSuite) TestErrors() {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{domain.ErrInvalidLabel, http.StatusBadRequest},
+		{domain.ErrLabelNotFound, http.StatusNotFound},
+		{domain.ErrLabelAlreadyExists, http.StatusConflict},
+		{domain.ErrTooManyLabels, http.StatusConflict},
+		{domain.ErrTaskNotFound, http.StatusNotFound},
+		{domain.ErrForbidden, http.StatusForbidden},
+	}
+	for _, c := range cases {
+		s.SetupTest()
+		s.labelUsecase.On("DeleteLabel", mock.Anything, "l1", mock.Anything).Return(c.err)
+		s.taskUsecase.On("AddTaskLabel", mock.Anything, "t1", "l1", mock.Anything).Return(nil, c.err)
+
+		assert.Equal(s.T(), c.want, s.serve("DELETE", "/labels/l1", nil).Code, c.err.Error())
+		assert.Equal(s.T(), c.want, s.serve("PUT", "/tasks/t1/labels/l1", nil).Code, c.err.Error())
+	}
+}
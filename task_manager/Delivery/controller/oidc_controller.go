@@ -0,0 +1,383 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authorizeTemplate is the sign-in page of the authorization endpoint. The
+// authorization request travels along in hidden fields, as there is no
+// session to keep it in. With a Challenge it asks for the second factor
+// instead of the password.
+var authorizeTemplate = template.Must(template.New("authorize").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Sign in</title>
+<style>
+body { font-family: sans-serif; max-width: 22rem; margin: 4rem auto; padding: 0 1rem; }
+label, input, button { display: block; width: 100%; box-sizing: border-box; }
+input { margin: .25rem 0 1rem; padding: .5rem; }
+button { padding: .5rem; }
+.error { color: #b00020; }
+</style>
+</head>
+<body>
+{{if .Client}}<h1>Sign in to {{.Client}}</h1>
+<p>with your task_manager account</p>{{else}}<h1>Sign in</h1>{{end}}
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{if .Request}}<form method="post" action="{{.Action}}">
+{{range $name, $value := .Request}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}{{if .Challenge}}<input type="hidden" name="challenge" value="{{.Challenge}}">
+<label for="code">Authentication or recovery code</label>
+<input id="code" name="code" autocomplete="one-time-code" required autofocus>
+{{else}}<label for="email">Email</label>
+<input id="email" name="email" type="email" autocomplete="username" required autofocus>
+<label for="password">Password</label>
+<input id="password" name="password" type="password" autocomplete="current-password" required>
+{{end}}<button type="submit">Sign in</button>
+</form>{{end}}
+</body>
+</html>
+`))
+
+type authorizePage struct {
+	Client    string
+	Error     string
+	Action    string
+	Request   map[string]string
+	Challenge string
+}
+
+// OpenIDConfiguration serves the OIDC discovery document
+func (cr *Controller) OpenIDConfiguration(ctx *gin.Context) {
+	metadata := cr.OIDCUsecases.Discovery()
+	ctx.Header("Cache-Control", "public, max-age=3600")
+	ctx.JSON(http.StatusOK, gin.H{
+		"issuer":                                metadata.Issuer,
+		"authorization_endpoint":                metadata.AuthorizationEndpoint,
+		"token_endpoint":                        metadata.TokenEndpoint,
+		"userinfo_endpoint":                     metadata.UserInfoEndpoint,
+		"jwks_uri":                              metadata.JWKSURI,
+		"scopes_supported":                      metadata.ScopesSupported,
+		"response_types_supported":              metadata.ResponseTypesSupported,
+		"grant_types_supported":                 metadata.GrantTypesSupported,
+		"subject_types_supported":               metadata.SubjectTypesSupported,
+		"id_token_signing_alg_values_supported": metadata.IDTokenSigningAlgValuesSupported,
+		"token_endpoint_auth_methods_supported": metadata.TokenEndpointAuthMethodsSupported,
+		"code_challenge_methods_supported":      metadata.CodeChallengeMethodsSupported,
+		"claims_supported":                      metadata.ClaimsSupported,
+	})
+}
+
+// Authorize shows the sign-in page for an authorization request
+func (cr *Controller) Authorize(ctx *gin.Context) {
+	request := authorizationRequest(ctx.Query)
+	client, err := cr.OIDCUsecases.ValidateAuthorization(ctx, request)
+	if err != nil {
+		respondAuthorizeError(ctx, request, err, http.StatusFound)
+		return
+	}
+	renderAuthorize(ctx, http.StatusOK, authorizePage{Client: client.Name, Request: authorizationFields(request)})
+}
+
+// AuthorizeSignIn checks the credentials posted from the sign-in page, or
+// the second factor, and sends the browser back to the client with a code
+func (cr *Controller) AuthorizeSignIn(ctx *gin.Context) {
+	request := authorizationRequest(ctx.PostForm)
+	challenge := ctx.PostForm("challenge")
+
+	var authorization *domain.Authorization
+	var err error
+	if challenge != "" {
+		authorization, err = cr.OIDCUsecases.AuthorizeTwoFactor(ctx, request, challenge, ctx.PostForm("code"), ctx.ClientIP())
+	} else {
+		authorization, err = cr.OIDCUsecases.Authorize(ctx, request, ctx.PostForm("email"), ctx.PostForm("password"), ctx.ClientIP())
+	}
+
+	page := authorizePage{Request: authorizationFields(request)}
+	if err != nil {
+		var lockout *domain.LockoutError
+		switch {
+		case errors.As(err, &lockout):
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockout.RetryAfter.Seconds()))))
+			page.Error = "Too many failed sign-ins. Please try again later."
+			status := http.StatusTooManyRequests
+			if errors.Is(lockout, domain.ErrAccountLocked) {
+				status = http.StatusLocked
+			}
+			renderAuthorize(ctx, status, page)
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			page.Error = "Invalid email or password."
+			renderAuthorize(ctx, http.StatusUnauthorized, page)
+		case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+			page.Error = "Invalid code."
+			page.Challenge = challenge
+			renderAuthorize(ctx, http.StatusUnauthorized, page)
+		case errors.Is(err, domain.ErrInvalidToken), errors.Is(err, domain.ErrTwoFactorNotEnabled):
+			page.Error = "Your sign-in expired. Please sign in again."
+			renderAuthorize(ctx, http.StatusUnauthorized, page)
+		case errors.Is(err, domain.ErrAccountDisabled):
+			page.Error = "This account is disabled."
+			renderAuthorize(ctx, http.StatusForbidden, page)
+		case errors.Is(err, domain.ErrTwoFactorSetupFirst):
+			page.Error = "Your account needs two-factor authentication. Sign in to task_manager to set it up, then try again."
+			renderAuthorize(ctx, http.StatusForbidden, page)
+		default:
+			respondAuthorizeError(ctx, request, err, http.StatusSeeOther)
+		}
+		return
+	}
+
+	if authorization.Challenge != nil {
+		page.Challenge = authorization.Challenge.Token
+		renderAuthorize(ctx, http.StatusOK, page)
+		return
+	}
+	ctx.Redirect(http.StatusSeeOther, authorization.RedirectURI)
+}
+
+// Token redeems an authorization code. Clients authenticate with HTTP
+// Basic or client_id and client_secret in the body
+func (cr *Controller) Token(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	request := &domain.TokenRequest{
+		GrantType:    ctx.PostForm("grant_type"),
+		Code:         ctx.PostForm("code"),
+		RedirectURI:  ctx.PostForm("redirect_uri"),
+		CodeVerifier: ctx.PostForm("code_verifier"),
+		ClientID:     ctx.PostForm("client_id"),
+		ClientSecret: ctx.PostForm("client_secret"),
+	}
+	id, secret, basic := ctx.Request.BasicAuth()
+	if basic {
+		request.ClientID = basicCredential(id)
+		request.ClientSecret = basicCredential(secret)
+	}
+
+	tokens, err := cr.OIDCUsecases.Exchange(ctx, request)
+	if err != nil {
+		var oidcErr *domain.OIDCError
+		if !errors.As(err, &oidcErr) {
+			log.Printf("oidc token exchange: %v", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+			return
+		}
+		status := http.StatusBadRequest
+		if oidcErr.Code == domain.OIDCInvalidClient {
+			status = http.StatusUnauthorized
+			if basic {
+				ctx.Header("WWW-Authenticate", `Basic realm="task_manager"`)
+			}
+		}
+		ctx.JSON(status, gin.H{"error": oidcErr.Code, "error_description": oidcErr.Description})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"access_token": tokens.AccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(tokens.ExpiresIn.Seconds()),
+		"id_token":     tokens.IDToken,
+		"scope":        strings.Join(tokens.Scopes, " "),
+	})
+}
+
+// UserInfo returns the claims about the user that the bearer token's scopes
+// release
+func (cr *Controller) UserInfo(ctx *gin.Context) {
+	header := ctx.GetHeader("Authorization")
+	accessToken, found := strings.CutPrefix(header, "Bearer ")
+	if !found || accessToken == "" {
+		ctx.Header("WWW-Authenticate", `Bearer realm="task_manager"`)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_request", "error_description": "a bearer token is required"})
+		return
+	}
+
+	info, err := cr.OIDCUsecases.UserInfo(ctx, accessToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) || errors.Is(err, domain.ErrTokenRevoked) || errors.Is(err, domain.ErrAccountDisabled) {
+			ctx.Header("WWW-Authenticate", `Bearer realm="task_manager", error="invalid_token"`)
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token", "error_description": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	response := gin.H{"sub": info.Subject}
+	if info.Username != "" {
+		response["preferred_username"] = info.Username
+	}
+	if info.Email != "" {
+		response["email"] = info.Email
+	}
+	if info.EmailVerified != nil {
+		response["email_verified"] = *info.EmailVerified
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RegisterOIDCClient registers an application that signs users in with
+// task_manager. A confidential client's secret is only ever shown in this
+// response
+func (cr *Controller) RegisterOIDCClient(ctx *gin.Context) {
+	var request struct {
+		Name         string   `json:"name" binding:"required"`
+		RedirectURIs []string `json:"redirect_uris" binding:"required"`
+		Public       bool     `json:"public"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Name and redirect_uris are required"})
+		return
+	}
+
+	registered, err := cr.OIDCUsecases.RegisterClient(ctx, request.Name, request.RedirectURIs, request.Public)
+	if err != nil {
+		respondOIDCClientError(ctx, err, "Failed to register client")
+		return
+	}
+	response := oidcClientResponse(registered.Client)
+	if registered.Secret != "" {
+		response["client_secret"] = registered.Secret
+	}
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListOIDCClients lists the registered clients, without their secrets
+func (cr *Controller) ListOIDCClients(ctx *gin.Context) {
+	clients, err := cr.OIDCUsecases.ListClients(ctx)
+	if err != nil {
+		respondOIDCClientError(ctx, err, "Failed to retrieve clients")
+		return
+	}
+	response := make([]gin.H, 0, len(clients))
+	for _, client := range clients {
+		response = append(response, oidcClientResponse(client))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"clients": response})
+}
+
+// DeleteOIDCClient removes a client; its tokens stop working at once
+func (cr *Controller) DeleteOIDCClient(ctx *gin.Context) {
+	if err := cr.OIDCUsecases.DeleteClient(ctx, ctx.Param("id")); err != nil {
+		respondOIDCClientError(ctx, err, "Failed to delete client")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Client deleted successfully"})
+}
+
+// basicCredential undoes the form encoding RFC 6749 applies to client
+// credentials before they are Basic encoded.
+func basicCredential(value string) string {
+	if unescaped, err := url.QueryUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+func oidcClientResponse(client *domain.OIDCClient) gin.H {
+	return gin.H{
+		"client_id":     client.ID,
+		"name":          client.Name,
+		"redirect_uris": client.RedirectURIs,
+		"public":        client.Public(),
+		"created_at":    client.CreatedAt,
+	}
+}
+
+func respondOIDCClientError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidOIDCClient):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrOIDCClientNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// respondAuthorizeError sends protocol errors back to the client's
+// redirect URI. Anything else, including an unknown client or redirect
+// URI, is shown to the user: redirecting there would make this server an
+// open redirector.
+func respondAuthorizeError(ctx *gin.Context, request *domain.AuthorizationRequest, err error, redirectStatus int) {
+	var oidcErr *domain.OIDCError
+	switch {
+	case errors.As(err, &oidcErr):
+		ctx.Redirect(redirectStatus, request.Redirect(url.Values{
+			"error":             {oidcErr.Code},
+			"error_description": {oidcErr.Description},
+		}))
+	case errors.Is(err, domain.ErrOIDCClientNotFound):
+		renderAuthorize(ctx, http.StatusBadRequest, authorizePage{Error: "Unknown client_id."})
+	case errors.Is(err, domain.ErrInvalidRedirectURI):
+		renderAuthorize(ctx, http.StatusBadRequest, authorizePage{Error: "The redirect_uri is not registered for this client."})
+	default:
+		log.Printf("oidc authorization: %v", err)
+		renderAuthorize(ctx, http.StatusInternalServerError, authorizePage{Error: "Something went wrong. Please try again."})
+	}
+}
+
+func renderAuthorize(ctx *gin.Context, status int, page authorizePage) {
+	page.Action = domain.OIDCAuthorizationPath
+	var body bytes.Buffer
+	if err := authorizeTemplate.Execute(&body, page); err != nil {
+		ctx.String(http.StatusInternalServerError, "Failed to render the sign-in page")
+		return
+	}
+	// The page takes a password: keep it out of caches and frames.
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("X-Frame-Options", "DENY")
+	ctx.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	ctx.Data(status, "text/html; charset=utf-8", body.Bytes())
+}
+
+// authorizationRequest reads the request parameters through get, which is
+// ctx.Query on the first visit and ctx.PostForm once the form is posted.
+func authorizationRequest(get func(string) string) *domain.AuthorizationRequest {
+	return &domain.AuthorizationRequest{
+		ClientID:            get("client_id"),
+		RedirectURI:         get("redirect_uri"),
+		ResponseType:        get("response_type"),
+		Scope:               get("scope"),
+		State:               get("state"),
+		Nonce:               get("nonce"),
+		CodeChallenge:       get("code_challenge"),
+		CodeChallengeMethod: get("code_challenge_method"),
+		Prompt:              get("prompt"),
+	}
+}
+
+// authorizationFields are the hidden fields that carry request through the
+// sign-in form. prompt is left out: it only applies to the first visit.
+func authorizationFields(request *domain.AuthorizationRequest) map[string]string {
+	fields := map[string]string{
+		"client_id":             request.ClientID,
+		"redirect_uri":          request.RedirectURI,
+		"response_type":         request.ResponseType,
+		"scope":                 request.Scope,
+		"state":                 request.State,
+		"nonce":                 request.Nonce,
+		"code_challenge":        request.CodeChallenge,
+		"code_challenge_method": request.CodeChallengeMethod,
+	}
+	for name, value := range fields {
+		if value == "" {
+			delete(fields, name)
+		}
+	}
+	return fields
+}
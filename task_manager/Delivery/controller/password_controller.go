@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForgotPassword mails a reset link. It answers the same way whether or not
+// the email is registered.
+func (cr *Controller) ForgotPassword(ctx *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+		return
+	}
+
+	if err := cr.PasswordResetUsecases.RequestPasswordReset(ctx, req.Email); err != nil {
+		// Failing loudly here would tell the caller the account exists.
+		log.Printf("password reset request failed: %v", err)
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "If the email is registered, a reset link has been sent"})
+}
+
+// ResetPassword sets a new password using a token from the reset email
+func (cr *Controller) ResetPassword(ctx *gin.Context) {
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Token and password are required"})
+		return
+	}
+
+	err := cr.PasswordResetUsecases.ResetPassword(ctx, req.Token, req.Password)
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+	case errors.Is(err, domain.ErrInvalidToken):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Reset token is invalid, expired or already used"})
+	case errors.Is(err, domain.ErrAccountDisabled):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+	}
+}
@@ -0,0 +1,68 @@
+package controller_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordControllerSuite struct {
+	suite.Suite
+	resetUsecase *mocks.PasswordResetUsecases
+	router       *gin.Engine
+}
+
+func (s *PasswordControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.resetUsecase = new(mocks.PasswordResetUsecases)
+	ctrl := &controller.Controller{PasswordResetUsecases: s.resetUsecase}
+	s.router = gin.New()
+	s.router.POST("/password/forgot", ctrl.ForgotPassword)
+	s.router.POST("/password/reset", ctrl.ResetPassword)
+}
+
+func TestPasswordControllerSuite(t *testing.T) {
+	suite.Run(t, new(PasswordControllerSuite))
+}
+
+func (s *PasswordControllerSuite) do(path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *PasswordControllerSuite) TestForgotPassword_SameAnswerOnFailure() {
+	s.resetUsecase.On("RequestPasswordReset", mock.Anything, "john@example.com").Return(nil).Once()
+	s.resetUsecase.On("RequestPasswordReset", mock.Anything, "broken@example.com").Return(errors.New("smtp down")).Once()
+
+	ok := s.do("/password/forgot", `{"email":"john@example.com"}`)
+	failed := s.do("/password/forgot", `{"email":"broken@example.com"}`)
+	assert.Equal(s.T(), http.StatusAccepted, ok.Code)
+	assert.Equal(s.T(), ok.Code, failed.Code)
+	assert.Equal(s.T(), ok.Body.String(), failed.Body.String())
+
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("/password/forgot", `{}`).Code)
+}
+
+func (s *PasswordControllerSuite) TestResetPassword() {
+	s.resetUsecase.On("ResetPassword", mock.Anything, "good", "n3w").Return(nil).Once()
+	s.resetUsecase.On("ResetPassword", mock.Anything, "spent", "n3w").Return(domain.ErrInvalidToken).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.do("/password/reset", `{"token":"good","password":"n3w"}`).Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("/password/reset", `{"token":"spent","password":"n3w"}`).Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("/password/reset", `{"token":"good"}`).Code)
+	s.resetUsecase.AssertExpectations(s.T())
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePersonalAccessToken issues a token for scripts and automation. The
+// token itself is only ever shown in this response
+func (cr *Controller) CreatePersonalAccessToken(ctx *gin.Context) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	var request struct {
+		Name      string    `json:"name" binding:"required"`
+		Scopes    []string  `json:"scopes" binding:"required"`
+		ExpiresAt time.Time `json:"expires_at" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Name, scopes and an RFC 3339 expires_at are required"})
+		return
+	}
+
+	created, err := cr.PersonalTokenUsecases.CreateToken(ctx, user.ID, request.Name, request.Scopes, request.ExpiresAt)
+	if err != nil {
+		respondPersonalTokenError(ctx, err, "Failed to create token")
+		return
+	}
+	response := personalTokenResponse(created.Record)
+	response["token"] = created.Token
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListPersonalAccessTokens lists the current user's tokens, without the
+// secrets
+func (cr *Controller) ListPersonalAccessTokens(ctx *gin.Context) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	tokens, err := cr.PersonalTokenUsecases.ListTokens(ctx, user.ID)
+	if err != nil {
+		respondPersonalTokenError(ctx, err, "Failed to retrieve tokens")
+		return
+	}
+	response := make([]gin.H, 0, len(tokens))
+	for _, token := range tokens {
+		response = append(response, personalTokenResponse(token))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"tokens": response})
+}
+
+// RevokePersonalAccessToken deletes one of the current user's tokens
+func (cr *Controller) RevokePersonalAccessToken(ctx *gin.Context) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if err := cr.PersonalTokenUsecases.RevokeToken(ctx, user.ID, ctx.Param("id")); err != nil {
+		respondPersonalTokenError(ctx, err, "Failed to revoke token")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Token revoked successfully"})
+}
+
+func personalTokenResponse(token *domain.PersonalAccessToken) gin.H {
+	return gin.H{
+		"id":           token.ID,
+		"name":         token.Name,
+		"scopes":       token.Scopes,
+		"created_at":   token.CreatedAt,
+		"expires_at":   token.ExpiresAt,
+		"last_used_at": token.LastUsedAt,
+	}
+}
+
+func respondPersonalTokenError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPersonalAccessToken):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrTokenNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Token not found"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
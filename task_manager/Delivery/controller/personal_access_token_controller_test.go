@@ -0,0 +1,96 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PersonalAccessTokenControllerSuite struct {
+	suite.Suite
+	userUsecase  *mocks.UserUsecases
+	tokenUsecase *mocks.PersonalAccessTokenUsecases
+	router       *gin.Engine
+}
+
+func (s *PersonalAccessTokenControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.tokenUsecase = new(mocks.PersonalAccessTokenUsecases)
+	ctrl := &controller.Controller{UserUsecases: s.userUsecase, PersonalTokenUsecases: s.tokenUsecase}
+	s.router = gin.New()
+	s.router.GET("/tokens", ctrl.ListPersonalAccessTokens)
+	s.router.POST("/tokens", ctrl.CreatePersonalAccessToken)
+	s.router.DELETE("/tokens/:id", ctrl.RevokePersonalAccessToken)
+
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "u1"}, nil)
+}
+
+func TestPersonalAccessTokenControllerSuite(t *testing.T) {
+	suite.Run(t, new(PersonalAccessTokenControllerSuite))
+}
+
+func (s *PersonalAccessTokenControllerSuite) do(method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *PersonalAccessTokenControllerSuite) TestCreate() {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := &domain.PersonalAccessToken{ID: "pat1", Name: "ci", Scopes: []string{domain.PermTaskRead}, ExpiresAt: expiresAt}
+	s.tokenUsecase.On("CreateToken", mock.Anything, "u1", "ci", []string{domain.PermTaskRead}, expiresAt).
+		Return(&domain.NewPersonalAccessToken{Token: "pat_secret", Record: record}, nil).Once()
+
+	res := s.do("POST", "/tokens", `{"name":"ci","scopes":["task:read"],"expires_at":"2030-01-01T00:00:00Z"}`)
+	assert.Equal(s.T(), http.StatusCreated, res.Code)
+	var body map[string]any
+	require.NoError(s.T(), json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(s.T(), "pat_secret", body["token"])
+	assert.Equal(s.T(), "pat1", body["id"])
+	assert.Nil(s.T(), body["last_used_at"])
+}
+
+func (s *PersonalAccessTokenControllerSuite) TestCreate_Invalid() {
+	s.tokenUsecase.On("CreateToken", mock.Anything, "u1", "ci", []string{"task:launch"}, mock.Anything).
+		Return(nil, domain.ErrInvalidPersonalAccessToken).Once()
+
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("POST", "/tokens", `{"name":"ci","scopes":["task:launch"],"expires_at":"2030-01-01T00:00:00Z"}`).Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("POST", "/tokens", `{"name":"ci","scopes":["task:read"],"expires_at":"next week"}`).Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("POST", "/tokens", `{"name":"ci","scopes":["task:read"]}`).Code)
+}
+
+func (s *PersonalAccessTokenControllerSuite) TestList() {
+	usedAt := time.Now()
+	s.tokenUsecase.On("ListTokens", mock.Anything, "u1").Return([]*domain.PersonalAccessToken{
+		{ID: "pat1", Name: "ci", TokenHash: "hash", LastUsedAt: &usedAt},
+	}, nil).Once()
+
+	res := s.do("GET", "/tokens", "")
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"last_used_at"`)
+	assert.NotContains(s.T(), res.Body.String(), "hash")
+}
+
+func (s *PersonalAccessTokenControllerSuite) TestRevoke() {
+	s.tokenUsecase.On("RevokeToken", mock.Anything, "u1", "pat1").Return(nil).Once()
+	s.tokenUsecase.On("RevokeToken", mock.Anything, "u1", "pat2").Return(domain.ErrTokenNotFound).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.do("DELETE", "/tokens/pat1", "").Code)
+	assert.Equal(s.T(), http.StatusNotFound, s.do("DELETE", "/tokens/pat2", "").Code)
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreviewOccurrences lists the upcoming occurrences of a recurring task's
+// series; limit caps how many (10 by default, at most 100)
+func (cr *Controller) PreviewOccurrences(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if value := ctx.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit " + strconv.Quote(value)})
+			return
+		}
+		limit = n
+	}
+
+	occurrences, err := cr.TaskUsecases.PreviewOccurrences(ctx, ctx.Param("id"), limit, actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to preview occurrences")
+		return
+	}
+	if occurrences == nil {
+		occurrences = []time.Time{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"occurrences": occurrences})
+}
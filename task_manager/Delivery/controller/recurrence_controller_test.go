@@ -0,0 +1,98 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecurrenceControllerSuite struct {
+	suite.Suite
+	taskUsecase *mocks.TaskUsecases
+	userUsecase *mocks.UserUsecases
+	router      *gin.Engine
+	user        *domain.User
+}
+
+func (s *RecurrenceControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.taskUsecase = new(mocks.TaskUsecases)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.user = &domain.User{ID: "u1"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(s.user, nil)
+
+	ctrl := &controller.Controller{TaskUsecases: s.taskUsecase, UserUsecases: s.userUsecase}
+	s.router = gin.New()
+	s.router.PUT("/tasks/:id", ctrl.UpdatedTask)
+	s.router.GET("/tasks/:id/occurrences", ctrl.PreviewOccurrences)
+}
+
+func TestRecurrenceControllerSuite(t *testing.T) {
+	suite.Run(t, new(RecurrenceControllerSuite))
+}
+
+func (s *RecurrenceControllerSuite) serve(method, url string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, url, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *RecurrenceControllerSuite) TestPreviewOccurrences() {
+	assert := assert.New(s.T())
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	first := time.Date(2025, 6, 9, 9, 0, 0, 0, berlin)
+	s.taskUsecase.On("PreviewOccurrences", mock.Anything, "t1", 2, mock.Anything).
+		Return([]time.Time{first, first.AddDate(0, 0, 7)}, nil)
+	s.taskUsecase.On("PreviewOccurrences", mock.Anything, "t1", 0, mock.Anything).Return(nil, nil)
+	s.taskUsecase.On("PreviewOccurrences", mock.Anything, "once", 0, mock.Anything).Return(nil, domain.ErrNotRecurring)
+
+	res := s.serve("GET", "/tasks/t1/occurrences?limit=2", nil)
+	assert.Equal(http.StatusOK, res.Code)
+	var body struct {
+		Occurrences []string `json:"occurrences"`
+	}
+	assert.NoError(json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal([]string{"2025-06-09T09:00:00+02:00", "2025-06-16T09:00:00+02:00"}, body.Occurrences)
+
+	res = s.serve("GET", "/tasks/t1/occurrences", nil)
+	assert.Equal(http.StatusOK, res.Code)
+	assert.JSONEq(`{"occurrences":[]}`, res.Body.String())
+	assert.Equal(http.StatusBadRequest, s.serve("GET", "/tasks/t1/occurrences?limit=none", nil).Code)
+	assert.Equal(http.StatusConflict, s.serve("GET", "/tasks/once/occurrences", nil).Code)
+}
+
+func (s *RecurrenceControllerSuite) TestUpdatedTask_Scope() {
+	assert := assert.New(s.T())
+	s.taskUsecase.On("UpdateTaskSeries", mock.Anything, "t1", mock.Anything, domain.SeriesScopeFollowing, mock.Anything).
+		Return(&domain.Task{ID: "t1", Title: "renamed"}, nil)
+	s.taskUsecase.On("UpdateTaskSeries", mock.Anything, "t1", mock.Anything, "sometimes", mock.Anything).
+		Return(nil, domain.ErrInvalidSeriesScope)
+	s.taskUsecase.On("UpdateTask", mock.Anything, "t1", mock.Anything, mock.Anything).
+		Return(nil, domain.ErrInvalidRecurrence)
+
+	res := s.serve("PUT", "/tasks/t1?scope=following", map[string]any{"Title": "renamed"})
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), `"Title":"renamed"`)
+	assert.Equal(http.StatusBadRequest, s.serve("PUT", "/tasks/t1?scope=sometimes", map[string]any{"Title": "x"}).Code)
+	// Without a scope the update is a plain one.
+	assert.Equal(http.StatusBadRequest, s.serve("PUT", "/tasks/t1", map[string]any{"Title": "x"}).Code)
+	s.taskUsecase.AssertNumberOfCalls(s.T(), "UpdateTask", 1)
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListReminders returns the caller's reminders, longest lead first
+func (cr *Controller) ListReminders(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	reminders, err := cr.ReminderUsecases.ListReminders(ctx, actor)
+	if err != nil {
+		respondReminderError(ctx, err, "Failed to retrieve reminders")
+		return
+	}
+	response := make([]gin.H, 0, len(reminders))
+	for _, reminder := range reminders {
+		response = append(response, reminderResponse(reminder))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"reminders": response})
+}
+
+// CreateReminder sets up a reminder lead_minutes before the due date of
+// each of the caller's open tasks; 0 reminds at the due time
+func (cr *Controller) CreateReminder(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+	var request struct {
+		LeadMinutes *int   `json:"lead_minutes" binding:"required"`
+		Channel     string `json:"channel"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "lead_minutes is required"})
+		return
+	}
+
+	lead := time.Duration(*request.LeadMinutes) * time.Minute
+	reminder, err := cr.ReminderUsecases.CreateReminder(ctx, lead, request.Channel, actor)
+	if err != nil {
+		respondReminderError(ctx, err, "Failed to create reminder")
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Reminder created successfully", "reminder": reminderResponse(reminder)})
+}
+
+// DeleteReminder removes one of the caller's reminders
+func (cr *Controller) DeleteReminder(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	if err := cr.ReminderUsecases.DeleteReminder(ctx, ctx.Param("id"), actor); err != nil {
+		respondReminderError(ctx, err, "Failed to delete reminder")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Reminder deleted successfully"})
+}
+
+func respondReminderError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidReminder):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrReminderNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+	case errors.Is(err, domain.ErrReminderAlreadyExists), errors.Is(err, domain.ErrTooManyReminders):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		respondTaskError(ctx, err, fallback)
+	}
+}
+
+func reminderResponse(reminder *domain.Reminder) gin.H {
+	return gin.H{
+		"id":           reminder.ID,
+		"lead_minutes": int(reminder.Lead / time.Minute),
+		"channel":      reminder.Channel,
+		"created_at":   reminder.CreatedAt,
+	}
+}
@@ -0,0 +1,90 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReminderControllerSuite struct {
+	suite.Suite
+	reminderUsecase *mocks.ReminderUsecases
+	userUsecase     *mocks.UserUsecases
+	router          *gin.Engine
+	user            *domain.User
+}
+
+func (s *ReminderControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.reminderUsecase = new(mocks.ReminderUsecases)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.user = &domain.User{ID: "u1"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(s.user, nil)
+
+	ctrl := &controller.Controller{UserUsecases: s.userUsecase, ReminderUsecases: s.reminderUsecase}
+	s.router = gin.New()
+	s.router.GET("/reminders", ctrl.ListReminders)
+	s.router.POST("/reminders", ctrl.CreateReminder)
+	s.router.DELETE("/reminders/:id", ctrl.DeleteReminder)
+}
+
+func TestReminderControllerSuite(t *testing.T) {
+	suite.Run(t, new(ReminderControllerSuite))
+}
+
+func (s *ReminderControllerSuite) serve(method, url string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, url, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *ReminderControllerSuite) TestListReminders() {
+	s.reminderUsecase.On("ListReminders", mock.Anything, &domain.Actor{User: s.user}).
+		Return([]*domain.Reminder{{ID: "r1", Lead: 24 * time.Hour, Channel: "email"}}, nil)
+
+	res := s.serve("GET", "/reminders", nil)
+
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"lead_minutes":1440`)
+}
+
+func (s *ReminderControllerSuite) TestCreateReminder() {
+	assert := assert.New(s.T())
+	s.reminderUsecase.On("CreateReminder", mock.Anything, time.Duration(0), "", mock.Anything).
+		Return(&domain.Reminder{ID: "r1", Channel: domain.ReminderChannelEmail}, nil)
+	s.reminderUsecase.On("CreateReminder", mock.Anything, time.Hour, "email", mock.Anything).
+		Return(nil, domain.ErrReminderAlreadyExists)
+
+	// A lead of 0 is a reminder at the due time, not a missing lead.
+	res := s.serve("POST", "/reminders", map[string]any{"lead_minutes": 0})
+	assert.Equal(http.StatusCreated, res.Code)
+	assert.Contains(res.Body.String(), `"lead_minutes":0`)
+	assert.Equal(http.StatusBadRequest, s.serve("POST", "/reminders", map[string]any{"channel": "email"}).Code)
+	assert.Equal(http.StatusConflict, s.serve("POST", "/reminders", map[string]any{"lead_minutes": 60, "channel": "email"}).Code)
+}
+
+func (s *ReminderControllerSuite) TestDeleteReminder() {
+	s.reminderUsecase.On("DeleteReminder", mock.Anything, "r1", mock.Anything).Return(nil)
+	s.reminderUsecase.On("DeleteReminder", mock.Anything, "missing", mock.Anything).Return(domain.ErrReminderNotFound)
+
+	assert.Equal(s.T(), http.StatusOK, s.serve("DELETE", "/reminders/r1", nil).Code)
+	assert.Equal(s.T(), http.StatusNotFound, s.serve("DELETE", "/reminders/missing", nil).Code)
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRoles lists the built-in and custom roles with their permissions
+func (cr *Controller) GetRoles(ctx *gin.Context) {
+	roles, err := cr.RoleUsecases.GetRoles(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve roles"})
+		return
+	}
+
+	response := make([]gin.H, 0, len(roles))
+	for _, role := range roles {
+		response = append(response, roleResponse(role))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"roles": response, "permissions": domain.Permissions})
+}
+
+// CreateRole adds a custom role
+func (cr *Controller) CreateRole(ctx *gin.Context) {
+	var roleRequest struct {
+		Name        string   `json:"name" binding:"required"`
+		Description string   `json:"description"`
+		Permissions []string `json:"permissions" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&roleRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Name and permissions are required"})
+		return
+	}
+
+	role, err := cr.RoleUsecases.CreateRole(ctx, &domain.Role{
+		Name:        roleRequest.Name,
+		Description: roleRequest.Description,
+		Permissions: roleRequest.Permissions,
+	})
+	if err != nil {
+		respondRoleError(ctx, err, "Failed to create role")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Role created successfully",
+		"role":    roleResponse(role),
+	})
+}
+
+// AssignRole moves a user to another role
+func (cr *Controller) AssignRole(ctx *gin.Context) {
+	var assignRequest struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&assignRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Role is required"})
+		return
+	}
+
+	userID := ctx.Param("id")
+	if err := cr.RoleUsecases.AssignRole(ctx, userID, assignRequest.Role); err != nil {
+		respondRoleError(ctx, err, "Failed to assign role")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Role assigned successfully",
+		"user_id": userID,
+		"role":    assignRequest.Role,
+	})
+}
+
+func roleResponse(role *domain.Role) gin.H {
+	return gin.H{
+		"name":        role.Name,
+		"description": role.Description,
+		"permissions": role.Permissions,
+		"built_in":    role.BuiltIn,
+	}
+}
+
+func respondRoleError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidRole):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrRoleAlreadyExists):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrRoleNotFound):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Role not found"})
+	case errors.Is(err, domain.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, domain.ErrLastAdmin):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "The last active admin cannot be moved to another role"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,88 @@
+package controller_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleControllerSuite struct {
+	suite.Suite
+	roleUsecase *mocks.RoleUsecases
+	router      *gin.Engine
+}
+
+func (s *RoleControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.roleUsecase = new(mocks.RoleUsecases)
+	ctrl := &controller.Controller{RoleUsecases: s.roleUsecase}
+	s.router = gin.New()
+	s.router.GET("/roles", ctrl.GetRoles)
+	s.router.POST("/roles", ctrl.CreateRole)
+	s.router.PUT("/users/:id/role", ctrl.AssignRole)
+}
+
+func TestRoleControllerSuite(t *testing.T) {
+	suite.Run(t, new(RoleControllerSuite))
+}
+
+func (s *RoleControllerSuite) do(method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *RoleControllerSuite) TestGetRoles() {
+	s.roleUsecase.On("GetRoles", mock.Anything).Return(domain.BuiltInRoles(), nil)
+
+	res := s.do("GET", "/roles", "")
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"name":"admin"`)
+	assert.Contains(s.T(), res.Body.String(), domain.PermTaskDeleteAny)
+}
+
+func (s *RoleControllerSuite) TestCreateRole_Success() {
+	s.roleUsecase.On("CreateRole", mock.Anything, &domain.Role{Name: "editor", Permissions: []string{"task:update"}}).
+		Return(&domain.Role{Name: "editor", Permissions: []string{"task:update"}}, nil)
+
+	res := s.do("POST", "/roles", `{"name":"editor","permissions":["task:update"]}`)
+	assert.Equal(s.T(), http.StatusCreated, res.Code)
+	assert.Contains(s.T(), res.Body.String(), "Role created successfully")
+}
+
+func (s *RoleControllerSuite) TestCreateRole_Conflict() {
+	s.roleUsecase.On("CreateRole", mock.Anything, mock.Anything).Return(nil, domain.ErrRoleAlreadyExists)
+
+	res := s.do("POST", "/roles", `{"name":"admin","permissions":[]}`)
+	assert.Equal(s.T(), http.StatusConflict, res.Code)
+}
+
+func (s *RoleControllerSuite) TestCreateRole_Invalid() {
+	s.roleUsecase.On("CreateRole", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidRole)
+
+	res := s.do("POST", "/roles", `{"name":"editor","permissions":["nope"]}`)
+	assert.Equal(s.T(), http.StatusBadRequest, res.Code)
+}
+
+func (s *RoleControllerSuite) TestAssignRole() {
+	s.roleUsecase.On("AssignRole", mock.Anything, "u1", "editor").Return(nil).Once()
+	s.roleUsecase.On("AssignRole", mock.Anything, "missing", "editor").Return(domain.ErrUserNotFound).Once()
+	s.roleUsecase.On("AssignRole", mock.Anything, "last", "editor").Return(domain.ErrLastAdmin).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.do("PUT", "/users/u1/role", `{"role":"editor"}`).Code)
+	assert.Equal(s.T(), http.StatusNotFound, s.do("PUT", "/users/missing/role", `{"role":"editor"}`).Code)
+	assert.Equal(s.T(), http.StatusConflict, s.do("PUT", "/users/last/role", `{"role":"editor"}`).Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("PUT", "/users/u1/role", `{}`).Code)
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSubtasks lists a task's direct subtasks, each with its own progress
+func (cr *Controller) GetSubtasks(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	subtasks, err := cr.TaskUsecases.GetSubtasks(ctx, ctx.Param("id"), actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to retrieve subtasks")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"subtasks": subtasks})
+}
+
+// AddChecklistItem appends an unchecked item to a task's checklist
+func (cr *Controller) AddChecklistItem(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+	var request struct {
+		Text string `json:"text" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Checklist item text is required"})
+		return
+	}
+
+	task, err := cr.TaskUsecases.AddChecklistItem(ctx, ctx.Param("id"), request.Text, actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to add checklist item")
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Checklist item added successfully", "task": task})
+}
+
+// UpdateChecklistItem edits and/or checks off a checklist item; fields left
+// out of the body are unchanged
+func (cr *Controller) UpdateChecklistItem(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+	var request struct {
+		Text *string `json:"text"`
+		Done *bool   `json:"done"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := cr.TaskUsecases.UpdateChecklistItem(ctx, ctx.Param("id"), ctx.Param("itemId"), request.Text, request.Done, actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to update checklist item")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Checklist item updated successfully", "task": task})
+}
+
+// RemoveChecklistItem deletes an item from a task's checklist
+func (cr *Controller) RemoveChecklistItem(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+
+	task, err := cr.TaskUsecases.RemoveChecklistItem(ctx, ctx.Param("id"), ctx.Param("itemId"), actor)
+	if err != nil {
+		respondTaskError(ctx, err, "Failed to remove checklist item")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Checklist item removed successfully", "task": task})
+}
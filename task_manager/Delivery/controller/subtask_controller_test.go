@@ -0,0 +1,112 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SubtaskControllerSuite struct {
+	suite.Suite
+	taskUsecase *mocks.TaskUsecases
+	userUsecase *mocks.UserUsecases
+	router      *gin.Engine
+	user        *domain.User
+}
+
+func (s *SubtaskControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.taskUsecase = new(mocks.TaskUsecases)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.user = &domain.User{ID: "u1"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(s.user, nil)
+
+	ctrl := &controller.Controller{TaskUsecases: s.taskUsecase, UserUsecases: s.userUsecase}
+	s.router = gin.New()
+	s.router.POST("/tasks", ctrl.AddTask)
+	s.router.DELETE("/tasks/:id", ctrl.RemoveTask)
+	s.router.GET("/tasks/:id/subtasks", ctrl.GetSubtasks)
+	s.router.POST("/tasks/:id/checklist", ctrl.AddChecklistItem)
+	s.router.PATCH("/tasks/:id/checklist/:itemId", ctrl.UpdateChecklistItem)
+	s.router.DELETE("/tasks/:id/checklist/:itemId", ctrl.RemoveChecklistItem)
+}
+
+func TestSubtaskControllerSuite(t *testing.T) {
+	suite.Run(t, new(SubtaskControllerSuite))
+}
+
+func (s *SubtaskControllerSuite) serve(method, url string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, url, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *SubtaskControllerSuite) TestGetSubtasks() {
+	assert := assert.New(s.T())
+	s.taskUsecase.On("GetSubtasks", mock.Anything, "t1", &domain.Actor{User: s.user}).Return([]*domain.Task{
+		{ID: "c1", ParentID: "t1", Progress: domain.TaskProgress{ChecklistItems: 2, CheckedItems: 1, Percent: 50}},
+	}, nil)
+
+	res := s.serve("GET", "/tasks/t1/subtasks", nil)
+
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), `"ParentID":"t1"`)
+	assert.Contains(res.Body.String(), `"Percent":50`)
+}
+
+func (s *SubtaskControllerSuite) TestChecklist() {
+	assert := assert.New(s.T())
+	done := true
+	task := &domain.Task{ID: "t1", Checklist: []domain.ChecklistItem{{ID: "i1", Text: "step", Done: true}}}
+	s.taskUsecase.On("AddChecklistItem", mock.Anything, "t1", "step", mock.Anything).Return(task, nil)
+	s.taskUsecase.On("UpdateChecklistItem", mock.Anything, "t1", "i1", (*string)(nil), &done, mock.Anything).Return(task, nil)
+	s.taskUsecase.On("RemoveChecklistItem", mock.Anything, "t1", "i1", mock.Anything).Return(&domain.Task{ID: "t1"}, nil)
+
+	res := s.serve("POST", "/tasks/t1/checklist", map[string]string{"text": "step"})
+	assert.Equal(http.StatusCreated, res.Code)
+	assert.Contains(res.Body.String(), `"Checklist":[{"ID":"i1","Text":"step","Done":true}]`)
+	assert.Equal(http.StatusBadRequest, s.serve("POST", "/tasks/t1/checklist", map[string]string{}).Code)
+
+	assert.Equal(http.StatusOK, s.serve("PATCH", "/tasks/t1/checklist/i1", map[string]bool{"done": true}).Code)
+	assert.Equal(http.StatusOK, s.serve("DELETE", "/tasks/t1/checklist/i1", nil).Code)
+}
+
+func (s *SubtaskControllerSuite) TestErrors() {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{domain.ErrInvalidChecklistItem, http.StatusBadRequest},
+		{domain.ErrChecklistItemNotFound, http.StatusNotFound},
+		{domain.ErrTaskNotFound, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		s.SetupTest()
+		s.taskUsecase.On("UpdateChecklistItem", mock.Anything, "t1", "i1", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.err)
+
+		assert.Equal(s.T(), c.want, s.serve("PATCH", "/tasks/t1/checklist/i1", map[string]string{"text": "x"}).Code, c.err.Error())
+	}
+
+	s.SetupTest()
+	s.taskUsecase.On("CreateTask", mock.Anything, mock.Anything, "u1").Return(domain.ErrInvalidParentTask)
+	s.taskUsecase.On("DeleteTask", mock.Anything, "t1", mock.Anything).Return(domain.ErrTaskHasSubtasks)
+	assert.Equal(s.T(), http.StatusBadRequest, s.serve("POST", "/tasks", map[string]string{"Title": "x", "ParentID": "nope"}).Code)
+	assert.Equal(s.T(), http.StatusConflict, s.serve("DELETE", "/tasks/t1", nil).Code)
+}
@@ -11,6 +11,7 @@ import (
 
 	"task_manager/mocks"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -29,7 +30,7 @@ type TaskControllerSuite struct {
 func (s *TaskControllerSuite) SetupTest() {
 	s.taskUsecase = new(mocks.TaskUsecases)
 	s.userUsecase = new(mocks.UserUsecases)
-	s.controller = controller.NewController(s.taskUsecase, s.userUsecase)
+	s.controller = &controller.Controller{TaskUsecases: s.taskUsecase, UserUsecases: s.userUsecase}
 	s.router = gin.Default()
 
 	s.router.GET("/tasks", s.controller.GetAllTasks)
@@ -45,7 +46,8 @@ func (s *TaskControllerSuite) TestGetAllTasks_Success() {
 	tasks := []*domain.Task{{ID: "t1", Title: "Test Task", UserID: "123"}}
 
 	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(user, nil)
-	s.taskUsecase.On("GetAllTasks", mock.Anything, "123").Return(tasks, nil)
+	s.taskUsecase.On("GetAllTasks", mock.Anything, domain.TaskQuery{}, &domain.Actor{User: user}).
+		Return(&domain.TaskPage{Tasks: tasks, NextCursor: "next"}, nil)
 
 	req, _ := http.NewRequest("GET", "/tasks", nil)
 	res := httptest.NewRecorder()
@@ -53,12 +55,78 @@ func (s *TaskControllerSuite) TestGetAllTasks_Success() {
 
 	assert.Equal(http.StatusOK, res.Code)
 	assert.Contains(res.Body.String(), "Test Task")
+	assert.Contains(res.Body.String(), `"next_cursor":"next"`)
+}
+
+func (s *TaskControllerSuite) TestGetAllTasks_QueryParameters() {
+	assert := assert.New(s.T())
+	user := &domain.User{ID: "123"}
+
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(user, nil)
+	s.taskUsecase.On("GetAllTasks", mock.Anything, domain.TaskQuery{
+		Status:   "pending",
+		DueFrom:  time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		DueTo:    time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+		SortBy:   "title",
+		SortDesc: true,
+		Limit:    5,
+		Cursor:   "abc",
+	}, mock.Anything).Return(&domain.TaskPage{}, nil)
+
+	req, _ := http.NewRequest("GET", "/tasks?status=pending&due_date=2025-08-01&sort=title&order=desc&limit=5&cursor=abc", nil)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusOK, res.Code)
+	s.taskUsecase.AssertExpectations(s.T())
+}
+
+func (s *TaskControllerSuite) TestGetAllTasks_BadRequest() {
+	assert := assert.New(s.T())
+	user := &domain.User{ID: "123"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(user, nil)
+	s.taskUsecase.On("GetAllTasks", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidCursor)
+
+	for _, url := range []string{"/tasks?limit=zero", "/tasks?order=sideways", "/tasks?due_date=tomorrow", "/tasks?cursor=bogus"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, req)
+
+		assert.Equal(http.StatusBadRequest, res.Code, url)
+	}
+}
+
+func (s *TaskControllerSuite) TestGetTasks_DueDateWithRange() {
+	assert := assert.New(s.T())
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "123"}, nil)
+
+	for _, url := range []string{"/tasks?due_date=2025-08-01&due_from=2025-07-01", "/tasks?due_date=2025-08-01&due_to=2025-09-01"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, req)
+
+		assert.Equal(http.StatusBadRequest, res.Code, url)
+		assert.Contains(res.Body.String(), "cannot be combined", url)
+	}
+
+	// With both range bounds invalid, the error always names due_from.
+	for i := 0; i < 10; i++ {
+		req, _ := http.NewRequest("GET", "/tasks?due_from=soon&due_to=later", nil)
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, req)
+
+		assert.Equal(http.StatusBadRequest, res.Code)
+		assert.Contains(res.Body.String(), "due_from")
+	}
+	s.taskUsecase.AssertNotCalled(s.T(), "GetAllTasks", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func (s *TaskControllerSuite) TestGetTask_Success() {
 	assert := assert.New(s.T())
-	task := &domain.Task{ID: "t1", Title: "Test Task"}
-	s.taskUsecase.On("GetTaskByID", mock.Anything, "t1").Return(task, nil)
+	user := &domain.User{ID: "123"}
+	task := &domain.Task{ID: "t1", Title: "Test Task", UserID: "123"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(user, nil)
+	s.taskUsecase.On("GetTaskByID", mock.Anything, "t1", &domain.Actor{User: user}).Return(task, nil)
 
 	req, _ := http.NewRequest("GET", "/task/t1", nil)
 	res := httptest.NewRecorder()
@@ -68,6 +136,48 @@ func (s *TaskControllerSuite) TestGetTask_Success() {
 	assert.Contains(res.Body.String(), "Test Task")
 }
 
+func (s *TaskControllerSuite) TestGetTask_NotOwner() {
+	assert := assert.New(s.T())
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "456"}, nil)
+	s.taskUsecase.On("GetTaskByID", mock.Anything, "t1", mock.Anything).Return(nil, domain.ErrTaskNotFound)
+
+	req, _ := http.NewRequest("GET", "/task/t1", nil)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusNotFound, res.Code)
+	assert.Contains(res.Body.String(), "Task not found")
+}
+
+func (s *TaskControllerSuite) TestGetTask_AdminWithoutOverride() {
+	assert := assert.New(s.T())
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "a1", Role: "admin"}, nil)
+	s.taskUsecase.On("GetTaskByID", mock.Anything, "t1", mock.Anything).Return(nil, domain.ErrForbidden)
+
+	req, _ := http.NewRequest("GET", "/task/t1", nil)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusForbidden, res.Code)
+	assert.Contains(res.Body.String(), controller.AdminOverrideHeader)
+}
+
+func (s *TaskControllerSuite) TestRemoveTask_AdminOverrideHeader() {
+	assert := assert.New(s.T())
+	admin := &domain.User{ID: "a1", Role: "admin"}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(admin, nil)
+	s.taskUsecase.On("DeleteTask", mock.Anything, "t1", &domain.Actor{User: admin, AdminOverride: true}).Return(nil)
+
+	req, _ := http.NewRequest("DELETE", "/task/t1", nil)
+	req.Header.Set(controller.AdminOverrideHeader, "true")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), "Task removed successfully")
+	s.taskUsecase.AssertExpectations(s.T())
+}
+
 func (s *TaskControllerSuite) TestAddTask_Success() {
 	assert := assert.New(s.T())
 	user := &domain.User{ID: "123"}
@@ -88,7 +198,8 @@ func (s *TaskControllerSuite) TestAddTask_Success() {
 
 func (s *TaskControllerSuite) TestUpdatedTask_NotFound() {
 	assert := assert.New(s.T())
-	s.taskUsecase.On("GetTaskByID", mock.Anything, "t1").Return(nil, nil)
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "123"}, nil)
+	s.taskUsecase.On("UpdateTask", mock.Anything, "t1", mock.Anything, mock.Anything).Return(nil, domain.ErrTaskNotFound)
 
 	body, _ := json.Marshal(&domain.Task{Title: "Updated"})
 	req, _ := http.NewRequest("PUT", "/task/t1", bytes.NewBuffer(body))
@@ -100,6 +211,36 @@ func (s *TaskControllerSuite) TestUpdatedTask_NotFound() {
 	assert.Contains(res.Body.String(), "Task not found")
 }
 
+func (s *TaskControllerSuite) TestUpdatedTask_IllegalTransition() {
+	assert := assert.New(s.T())
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "123"}, nil)
+	s.taskUsecase.On("UpdateTask", mock.Anything, "t1", mock.Anything, mock.Anything).
+		Return(nil, &domain.InvalidTransitionError{From: domain.StatusTodo, To: domain.StatusDone})
+
+	body, _ := json.Marshal(&domain.Task{Status: domain.StatusDone})
+	req, _ := http.NewRequest("PUT", "/task/t1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusConflict, res.Code)
+	assert.Contains(res.Body.String(), "cannot move task")
+}
+
+func (s *TaskControllerSuite) TestUpdatedTask_Forbidden() {
+	assert := assert.New(s.T())
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "a1", Role: "admin"}, nil)
+	s.taskUsecase.On("UpdateTask", mock.Anything, "t1", mock.Anything, mock.Anything).Return(nil, domain.ErrForbidden)
+
+	body, _ := json.Marshal(&domain.Task{Title: "Updated"})
+	req, _ := http.NewRequest("PUT", "/task/t1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusForbidden, res.Code)
+}
+
 func (s *TaskControllerSuite) TestRemoveTask_Unauthorized() {
 	assert := assert.New(s.T())
 	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(nil, nil)
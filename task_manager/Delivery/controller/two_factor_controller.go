@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// EnrollTwoFactor starts TOTP enrollment for the current user
+func (cr *Controller) EnrollTwoFactor(ctx *gin.Context) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	enrollment, err := cr.TwoFactorUsecases.Enroll(ctx, user.ID)
+	if err != nil {
+		respondTwoFactorError(ctx, err, "Failed to start two-factor enrollment")
+		return
+	}
+	ctx.JSON(http.StatusCreated, enrollmentResponse(enrollment))
+}
+
+// ConfirmTwoFactor enables TOTP with a first code and hands out the
+// recovery codes
+func (cr *Controller) ConfirmTwoFactor(ctx *gin.Context) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	var request twoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Code is required"})
+		return
+	}
+
+	codes, err := cr.TwoFactorUsecases.Confirm(ctx, user.ID, request.Code)
+	if err != nil {
+		respondTwoFactorError(ctx, err, "Failed to enable two-factor authentication")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTwoFactor turns TOTP off, given a current or recovery code
+func (cr *Controller) DisableTwoFactor(ctx *gin.Context) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	var request twoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Code is required"})
+		return
+	}
+
+	if err := cr.TwoFactorUsecases.Disable(ctx, user.ID, request.Code); err != nil {
+		respondTwoFactorError(ctx, err, "Failed to disable two-factor authentication")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (cr *Controller) RegenerateRecoveryCodes(ctx *gin.Context) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	var request twoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Code is required"})
+		return
+	}
+
+	codes, err := cr.TwoFactorUsecases.RegenerateRecoveryCodes(ctx, user.ID, request.Code)
+	if err != nil {
+		respondTwoFactorError(ctx, err, "Failed to regenerate recovery codes")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// SetupTwoFactorLogin starts enrollment for a user whose role requires
+// two-factor authentication, using the challenge from Login
+func (cr *Controller) SetupTwoFactorLogin(ctx *gin.Context) {
+	var request struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Challenge token is required"})
+		return
+	}
+
+	enrollment, err := cr.TwoFactorUsecases.SetupLogin(ctx, request.ChallengeToken)
+	if err != nil {
+		respondTwoFactorError(ctx, err, "Failed to start two-factor enrollment")
+		return
+	}
+	ctx.JSON(http.StatusCreated, enrollmentResponse(enrollment))
+}
+
+// CompleteTwoFactorLogin exchanges the challenge from Login and a TOTP or
+// recovery code for tokens
+func (cr *Controller) CompleteTwoFactorLogin(ctx *gin.Context) {
+	var request struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Challenge token and code are required"})
+		return
+	}
+
+	login, err := cr.TwoFactorUsecases.CompleteLogin(ctx, request.ChallengeToken, request.Code, ctx.ClientIP())
+	if err != nil {
+		respondTwoFactorError(ctx, err, "Failed to log in")
+		return
+	}
+	response := gin.H{
+		"message":       "Login successful",
+		"token":         login.Tokens.AccessToken,
+		"refresh_token": login.Tokens.RefreshToken,
+	}
+	if login.RecoveryCodes != nil {
+		response["recovery_codes"] = login.RecoveryCodes
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetTwoFactorRoles lists the roles whose members must use two-factor
+// authentication
+func (cr *Controller) GetTwoFactorRoles(ctx *gin.Context) {
+	roles, err := cr.TwoFactorUsecases.GetTwoFactorRoles(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve roles"})
+		return
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// SetRoleTwoFactor requires or stops requiring two-factor authentication
+// for a role
+func (cr *Controller) SetRoleTwoFactor(ctx *gin.Context) {
+	var request struct {
+		Required *bool `json:"required" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "required must be true or false"})
+		return
+	}
+
+	role := ctx.Param("name")
+	if err := cr.TwoFactorUsecases.SetRoleTwoFactorRequired(ctx, role, *request.Required); err != nil {
+		respondRoleError(ctx, err, "Failed to update role")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"role": role, "two_factor_required": *request.Required})
+}
+
+// enrollmentResponse carries the QR code as a data URI, ready to be used as
+// an image source.
+func enrollmentResponse(enrollment *domain.TwoFactorEnrollment) gin.H {
+	return gin.H{
+		"secret":      enrollment.Secret,
+		"otpauth_uri": enrollment.URI,
+		"qr_code":     "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode),
+	}
+}
+
+func respondTwoFactorError(ctx *gin.Context, err error, fallback string) {
+	var lockout *domain.LockoutError
+	switch {
+	case errors.As(err, &lockout):
+		respondLockout(ctx, lockout)
+	case errors.Is(err, domain.ErrInvalidToken):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Challenge is invalid or expired; log in again"})
+	case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+	case errors.Is(err, domain.ErrAccountDisabled):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+	case errors.Is(err, domain.ErrTwoFactorRequired):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Your role requires two-factor authentication"})
+	case errors.Is(err, domain.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, domain.ErrTwoFactorAlreadyEnabled):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+	case errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Start two-factor enrollment first"})
+	case errors.Is(err, domain.ErrTwoFactorNotEnabled):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is not enabled"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,138 @@
+package controller_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoFactorControllerSuite struct {
+	suite.Suite
+	userUsecase      *mocks.UserUsecases
+	twoFactorUsecase *mocks.TwoFactorUsecases
+	router           *gin.Engine
+}
+
+func (s *TwoFactorControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.twoFactorUsecase = new(mocks.TwoFactorUsecases)
+	ctrl := &controller.Controller{UserUsecases: s.userUsecase, TwoFactorUsecases: s.twoFactorUsecase}
+	s.router = gin.New()
+	s.router.POST("/login", ctrl.Login)
+	s.router.POST("/login/2fa", ctrl.CompleteTwoFactorLogin)
+	s.router.POST("/login/2fa/setup", ctrl.SetupTwoFactorLogin)
+	s.router.POST("/2fa/enroll", ctrl.EnrollTwoFactor)
+	s.router.POST("/2fa/confirm", ctrl.ConfirmTwoFactor)
+	s.router.POST("/2fa/disable", ctrl.DisableTwoFactor)
+	s.router.PUT("/roles/:name/two-factor", ctrl.SetRoleTwoFactor)
+}
+
+func TestTwoFactorControllerSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorControllerSuite))
+}
+
+func (s *TwoFactorControllerSuite) do(method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *TwoFactorControllerSuite) TestLogin_ReturnsChallenge() {
+	challenge := &domain.TwoFactorChallenge{Token: "challenge-token", ExpiresAt: time.Now().Add(5 * time.Minute)}
+	s.userUsecase.On("Login", mock.Anything, "john@example.com", "secret", mock.Anything).
+		Return(&domain.LoginResult{Challenge: challenge}, nil).Once()
+
+	res := s.do("POST", "/login", `{"email":"john@example.com","password":"secret"}`)
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"two_factor_required":true`)
+	assert.Contains(s.T(), res.Body.String(), `"challenge_token":"challenge-token"`)
+	assert.NotContains(s.T(), res.Body.String(), "refresh_token")
+}
+
+func (s *TwoFactorControllerSuite) TestCompleteTwoFactorLogin() {
+	pair := &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+	s.twoFactorUsecase.On("CompleteLogin", mock.Anything, "challenge", "123456", mock.Anything).
+		Return(&domain.TwoFactorLogin{Tokens: pair}, nil).Once()
+	s.twoFactorUsecase.On("CompleteLogin", mock.Anything, "challenge", "000000", mock.Anything).
+		Return(nil, domain.ErrInvalidTwoFactorCode).Once()
+	s.twoFactorUsecase.On("CompleteLogin", mock.Anything, "expired", "123456", mock.Anything).
+		Return(nil, domain.ErrInvalidToken).Once()
+	s.twoFactorUsecase.On("CompleteLogin", mock.Anything, "challenge", "654321", mock.Anything).
+		Return(nil, &domain.LockoutError{Err: domain.ErrAccountLocked, RetryAfter: time.Minute}).Once()
+
+	res := s.do("POST", "/login/2fa", `{"challenge_token":"challenge","code":"123456"}`)
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"token":"access"`)
+	assert.NotContains(s.T(), res.Body.String(), "recovery_codes")
+
+	assert.Equal(s.T(), http.StatusUnauthorized, s.do("POST", "/login/2fa", `{"challenge_token":"challenge","code":"000000"}`).Code)
+	assert.Equal(s.T(), http.StatusUnauthorized, s.do("POST", "/login/2fa", `{"challenge_token":"expired","code":"123456"}`).Code)
+	res = s.do("POST", "/login/2fa", `{"challenge_token":"challenge","code":"654321"}`)
+	assert.Equal(s.T(), http.StatusLocked, res.Code)
+	assert.Equal(s.T(), "60", res.Header().Get("Retry-After"))
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("POST", "/login/2fa", `{"challenge_token":"challenge"}`).Code)
+}
+
+func (s *TwoFactorControllerSuite) TestRequiredSetupDuringLogin() {
+	s.twoFactorUsecase.On("SetupLogin", mock.Anything, "challenge").
+		Return(&domain.TwoFactorEnrollment{Secret: "SECRET", URI: "otpauth://totp/x", QRCode: []byte{0x89, 'P', 'N', 'G'}}, nil).Once()
+	s.twoFactorUsecase.On("CompleteLogin", mock.Anything, "challenge", "123456", mock.Anything).
+		Return(&domain.TwoFactorLogin{Tokens: &domain.TokenPair{AccessToken: "access"}, RecoveryCodes: []string{"abcde-fghij"}}, nil).Once()
+
+	res := s.do("POST", "/login/2fa/setup", `{"challenge_token":"challenge"}`)
+	assert.Equal(s.T(), http.StatusCreated, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"qr_code":"data:image/png;base64,iVBORw=="`)
+
+	res = s.do("POST", "/login/2fa", `{"challenge_token":"challenge","code":"123456"}`)
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"recovery_codes":["abcde-fghij"]`)
+}
+
+func (s *TwoFactorControllerSuite) TestEnrollAndConfirm() {
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "u1"}, nil)
+	s.twoFactorUsecase.On("Enroll", mock.Anything, "u1").
+		Return(&domain.TwoFactorEnrollment{Secret: "SECRET", URI: "otpauth://totp/x", QRCode: []byte("png")}, nil).Once()
+	s.twoFactorUsecase.On("Enroll", mock.Anything, "u1").Return(nil, domain.ErrTwoFactorAlreadyEnabled).Once()
+	s.twoFactorUsecase.On("Confirm", mock.Anything, "u1", "123456").Return([]string{"abcde-fghij"}, nil).Once()
+
+	res := s.do("POST", "/2fa/enroll", "")
+	assert.Equal(s.T(), http.StatusCreated, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"otpauth_uri":"otpauth://totp/x"`)
+	assert.Equal(s.T(), http.StatusConflict, s.do("POST", "/2fa/enroll", "").Code)
+
+	res = s.do("POST", "/2fa/confirm", `{"code":"123456"}`)
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), "abcde-fghij")
+}
+
+func (s *TwoFactorControllerSuite) TestDisable_RequiredByRole() {
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "u1"}, nil)
+	s.twoFactorUsecase.On("Disable", mock.Anything, "u1", "123456").Return(domain.ErrTwoFactorRequired).Once()
+
+	assert.Equal(s.T(), http.StatusForbidden, s.do("POST", "/2fa/disable", `{"code":"123456"}`).Code)
+}
+
+func (s *TwoFactorControllerSuite) TestSetRoleTwoFactor() {
+	s.twoFactorUsecase.On("SetRoleTwoFactorRequired", mock.Anything, "admin", true).Return(nil).Once()
+	s.twoFactorUsecase.On("SetRoleTwoFactorRequired", mock.Anything, "ghost", true).Return(domain.ErrRoleNotFound).Once()
+
+	res := s.do("PUT", "/roles/admin/two-factor", `{"required":true}`)
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"two_factor_required":true`)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("PUT", "/roles/ghost/two-factor", `{"required":true}`).Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("PUT", "/roles/admin/two-factor", `{}`).Code)
+}
@@ -2,41 +2,59 @@ package controller_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"task_manager/Delivery/controller"
 	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repository "task_manager/Repository"
+	usecases "task_manager/Usecases"
 	"task_manager/mocks"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
 type ControllerSuite struct {
 	suite.Suite
 	userUsecase *mocks.UserUsecases
+	tokenUsecase *mocks.TokenUsecases
+	verificationUsecase *mocks.EmailVerificationUsecases
 	controller  *controller.Controller
 	router *gin.Engine
 }
 
 func (s *ControllerSuite) SetupTest() {
 	s.userUsecase = new(mocks.UserUsecases)
-	s.controller = controller.NewController(nil, s.userUsecase)
+	s.tokenUsecase = new(mocks.TokenUsecases)
+	s.verificationUsecase = new(mocks.EmailVerificationUsecases)
+	s.controller = &controller.Controller{
+		UserUsecases:         s.userUsecase,
+		TokenUsecases:        s.tokenUsecase,
+		VerificationUsecases: s.verificationUsecase,
+	}
 	s.router = gin.Default()
 	s.router.POST("/register", s.controller.Register)
 	s.router.POST("/login", s.controller.Login)
+	s.router.POST("/token/refresh", s.controller.RefreshToken)
+	s.router.POST("/logout", s.controller.Logout)
 	s.router.POST("/promote", s.controller.PromoteUser)
 }
 
 func (s *ControllerSuite) TestRegister_ValidInput() {
 	assert := assert.New(s.T())
 	input := domain.User{Username: "john", Email: "john@example.com", Password: "secret"}
-	expected := &domain.User{ID: "user-id", Username: "john", Email: "john@example.com", Role: "user"}
+	expected := &domain.User{ID: "user-id", Username: "john", Email: "john@example.com", Role: "user", EmailUnverified: true}
 	s.userUsecase.On("CreateUser", mock.Anything, &input).Return(expected, nil)
+	s.verificationUsecase.On("SendVerificationEmail", mock.Anything, expected).Return(nil).Once()
 
 	body, _ := json.Marshal(input)
 	req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(body))
@@ -46,13 +64,30 @@ func (s *ControllerSuite) TestRegister_ValidInput() {
 
 	assert.Equal(http.StatusCreated, res.Code)
 	assert.Contains(res.Body.String(), "User registered successfully")
+	assert.Contains(res.Body.String(), `"email_verified":false`)
+	assert.Contains(res.Body.String(), `"verification_email_sent":true`)
 	s.userUsecase.AssertExpectations(s.T())
+	s.verificationUsecase.AssertExpectations(s.T())
+}
+
+func (s *ControllerSuite) TestRegister_MailFailureStillCreatesAccount() {
+	expected := &domain.User{ID: "user-id", Username: "john", Email: "john@example.com", Role: "user", EmailUnverified: true}
+	s.userUsecase.On("CreateUser", mock.Anything, mock.Anything).Return(expected, nil)
+	s.verificationUsecase.On("SendVerificationEmail", mock.Anything, expected).Return(errors.New("smtp down")).Once()
+
+	req, _ := http.NewRequest("POST", "/register", bytes.NewBufferString(`{"username":"john","email":"john@example.com","password":"secret"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(s.T(), http.StatusCreated, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"verification_email_sent":false`)
 }
 
 func (s *ControllerSuite) TestLogin_Success() {
 	assert := assert.New(s.T())
 	loginReq := map[string]string{"email": "john@example.com", "password": "secret"}
-	s.userUsecase.On("Login", mock.Anything, "john@example.com", "secret").Return("mocked-token", nil)
+	s.userUsecase.On("Login", mock.Anything, "john@example.com", "secret", mock.Anything).Return(&domain.LoginResult{Tokens: &domain.TokenPair{AccessToken: "mocked-token", RefreshToken: "mocked-refresh"}}, nil)
 
 	body, _ := json.Marshal(loginReq)
 	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
@@ -63,9 +98,130 @@ func (s *ControllerSuite) TestLogin_Success() {
 	assert.Equal(http.StatusOK, res.Code)
 	assert.Contains(res.Body.String(), "Login successful")
 	assert.Contains(res.Body.String(), "mocked-token")
+	assert.Contains(res.Body.String(), "mocked-refresh")
+	s.userUsecase.AssertExpectations(s.T())
+}
+
+func (s *ControllerSuite) TestLogin_InvalidCredentials() {
+	assert := assert.New(s.T())
+	loginReq := map[string]string{"email": "john@example.com", "password": "wrong"}
+	s.userUsecase.On("Login", mock.Anything, "john@example.com", "wrong", mock.Anything).Return(nil, domain.ErrInvalidCredentials)
+
+	body, _ := json.Marshal(loginReq)
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusUnauthorized, res.Code)
+	assert.NotContains(res.Body.String(), "token")
+	s.userUsecase.AssertExpectations(s.T())
+}
+
+func (s *ControllerSuite) TestLogin_DisabledAccount() {
+	s.userUsecase.On("Login", mock.Anything, "john@example.com", "secret", mock.Anything).Return(nil, domain.ErrAccountDisabled)
+
+	body, _ := json.Marshal(map[string]string{"email": "john@example.com", "password": "secret"})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(s.T(), http.StatusForbidden, res.Code)
+	assert.Contains(s.T(), res.Body.String(), "Account is disabled")
+}
+
+func (s *ControllerSuite) TestLogin_Lockouts() {
+	s.userUsecase.On("Login", mock.Anything, "john@example.com", "secret", "192.0.2.1").
+		Return(nil, &domain.LockoutError{Err: domain.ErrAccountLocked, RetryAfter: 89500 * time.Millisecond}).Once()
+	s.userUsecase.On("Login", mock.Anything, "jane@example.com", "secret", "192.0.2.1").
+		Return(nil, &domain.LockoutError{Err: domain.ErrTooManyLoginAttempts, RetryAfter: 30 * time.Second}).Once()
+
+	login := func(email string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"email": email, "password": "secret"})
+		req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "192.0.2.1:40000"
+		res := httptest.NewRecorder()
+		s.router.ServeHTTP(res, req)
+		return res
+	}
+
+	res := login("john@example.com")
+	assert.Equal(s.T(), http.StatusLocked, res.Code)
+	assert.Equal(s.T(), "90", res.Header().Get("Retry-After"))
+
+	res = login("jane@example.com")
+	assert.Equal(s.T(), http.StatusTooManyRequests, res.Code)
+	assert.Equal(s.T(), "30", res.Header().Get("Retry-After"))
 	s.userUsecase.AssertExpectations(s.T())
 }
 
+func (s *ControllerSuite) TestRefreshToken_Success() {
+	assert := assert.New(s.T())
+	s.tokenUsecase.On("RefreshTokens", mock.Anything, "old-refresh").
+		Return(&domain.TokenPair{AccessToken: "new-access", RefreshToken: "new-refresh"}, nil)
+
+	req, _ := http.NewRequest("POST", "/token/refresh", bytes.NewBufferString(`{"refresh_token":"old-refresh"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), "new-access")
+	assert.Contains(res.Body.String(), "new-refresh")
+	s.tokenUsecase.AssertExpectations(s.T())
+}
+
+func (s *ControllerSuite) TestRefreshToken_Reused() {
+	assert := assert.New(s.T())
+	s.tokenUsecase.On("RefreshTokens", mock.Anything, "stolen").Return(nil, domain.ErrTokenReused)
+
+	req, _ := http.NewRequest("POST", "/token/refresh", bytes.NewBufferString(`{"refresh_token":"stolen"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusUnauthorized, res.Code)
+	s.tokenUsecase.AssertExpectations(s.T())
+}
+
+func (s *ControllerSuite) TestRefreshToken_Missing() {
+	assert := assert.New(s.T())
+	req, _ := http.NewRequest("POST", "/token/refresh", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusBadRequest, res.Code)
+}
+
+func (s *ControllerSuite) TestLogout_WithRefreshToken() {
+	assert := assert.New(s.T())
+	s.tokenUsecase.On("Logout", mock.Anything, "my-refresh").Return(nil)
+
+	req, _ := http.NewRequest("POST", "/logout", bytes.NewBufferString(`{"refresh_token":"my-refresh"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), "Logged out successfully")
+	s.tokenUsecase.AssertExpectations(s.T())
+}
+
+func (s *ControllerSuite) TestLogout_WithoutBody() {
+	assert := assert.New(s.T())
+	s.tokenUsecase.On("Logout", mock.Anything, "").Return(nil)
+
+	req, _ := http.NewRequest("POST", "/logout", nil)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusOK, res.Code)
+	s.tokenUsecase.AssertExpectations(s.T())
+}
+
 func (s *ControllerSuite) TestLogin_InvalidPayload() {
 	assert := assert.New(s.T())
 	req, _ := http.NewRequest("POST", "/login", bytes.NewBufferString("not-json"))
@@ -128,3 +284,39 @@ func (s *ControllerSuite) TestPromoteUser_NotFound() {
 func TestControllerSuite(t *testing.T) {
 	suite.Run(t, new(ControllerSuite))
 }
+
+// The promotion is recorded with the admin who made it and the request it
+// came in.
+func TestPromoteUser_Audited(t *testing.T) {
+	users := repository.NewInMemoryUserRepository()
+	auditLog := repository.NewInMemoryAuditRepository()
+	tokens := new(mocks.TokenUsecases)
+	tokens.On("ForgetTokenVersion", "u2").Return()
+	admin := &domain.User{ID: "u1", Username: "root", Email: "root@example.com", Role: domain.RoleAdmin}
+	for _, user := range []*domain.User{admin, {ID: "u2", Username: "john", Email: "john@example.com", Role: domain.RoleUser}} {
+		_, err := users.CreateUser(context.Background(), user)
+		require.NoError(t, err)
+	}
+	uc := usecases.NewUserUsecases(users, nil, nil, nil, nil, tokens, nil, repository.NewInMemoryLoginAttemptRepository(),
+		domain.DefaultAccountLockout, domain.DefaultIPLockout, nil, nil, auditLog, time.Second)
+
+	router := gin.New()
+	router.Use(infrastructure.RequestInfoMiddleware(), func(c *gin.Context) { c.Set("user", admin) })
+	router.POST("/promote", (&controller.Controller{UserUsecases: uc}).PromoteUser)
+	req, _ := http.NewRequest("POST", "/promote", bytes.NewBufferString(`{"user_id":"u2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(infrastructure.RequestIDHeader, "req-42")
+	req.RemoteAddr = "192.0.2.7:4242"
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	require.Equal(t, http.StatusOK, res.Code)
+
+	page, err := auditLog.GetAuditEntries(context.Background(), domain.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	entry := page.Entries[0]
+	assert.Equal(t, domain.AuditUserPromote, entry.Action)
+	assert.Equal(t, "u1", entry.ActorID)
+	assert.Equal(t, "192.0.2.7", entry.IP)
+	assert.Equal(t, "req-42", entry.RequestID)
+}
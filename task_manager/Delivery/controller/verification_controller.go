@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyEmail confirms an email address with the token from the
+// verification link
+func (cr *Controller) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	err := cr.VerificationUsecases.VerifyEmail(ctx, token)
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+	case errors.Is(err, domain.ErrInvalidToken):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Verification link is invalid or expired"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+	}
+}
+
+// ResendVerification mails the current user a new verification link
+func (cr *Controller) ResendVerification(ctx *gin.Context) {
+	user, _ := cr.UserUsecases.GetCurrentUser(ctx)
+	if user == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	err := cr.VerificationUsecases.ResendVerificationEmail(ctx, user.ID)
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+	case errors.Is(err, domain.ErrEmailAlreadyVerified):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Email address is already verified"})
+	case errors.Is(err, domain.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+	}
+}
@@ -0,0 +1,69 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type VerificationControllerSuite struct {
+	suite.Suite
+	userUsecase         *mocks.UserUsecases
+	verificationUsecase *mocks.EmailVerificationUsecases
+	router              *gin.Engine
+}
+
+func (s *VerificationControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.verificationUsecase = new(mocks.EmailVerificationUsecases)
+	ctrl := &controller.Controller{UserUsecases: s.userUsecase, VerificationUsecases: s.verificationUsecase}
+	s.router = gin.New()
+	s.router.GET("/verify", ctrl.VerifyEmail)
+	s.router.POST("/verify/resend", ctrl.ResendVerification)
+}
+
+func TestVerificationControllerSuite(t *testing.T) {
+	suite.Run(t, new(VerificationControllerSuite))
+}
+
+func (s *VerificationControllerSuite) do(method, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *VerificationControllerSuite) TestVerifyEmail() {
+	s.verificationUsecase.On("VerifyEmail", mock.Anything, "good").Return(nil).Once()
+	s.verificationUsecase.On("VerifyEmail", mock.Anything, "forged").Return(domain.ErrInvalidToken).Once()
+
+	assert.Equal(s.T(), http.StatusOK, s.do("GET", "/verify?token=good").Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("GET", "/verify?token=forged").Code)
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("GET", "/verify").Code)
+}
+
+func (s *VerificationControllerSuite) TestResendVerification() {
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(&domain.User{ID: "u1"}, nil)
+	s.verificationUsecase.On("ResendVerificationEmail", mock.Anything, "u1").Return(nil).Once()
+	s.verificationUsecase.On("ResendVerificationEmail", mock.Anything, "u1").Return(domain.ErrEmailAlreadyVerified).Once()
+
+	assert.Equal(s.T(), http.StatusAccepted, s.do("POST", "/verify/resend").Code)
+	assert.Equal(s.T(), http.StatusConflict, s.do("POST", "/verify/resend").Code)
+}
+
+func (s *VerificationControllerSuite) TestResendVerification_Unauthenticated() {
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(nil, domain.ErrUserNotFound)
+
+	assert.Equal(s.T(), http.StatusUnauthorized, s.do("POST", "/verify/resend").Code)
+	s.verificationUsecase.AssertNotCalled(s.T(), "ResendVerificationEmail", mock.Anything, mock.Anything)
+}
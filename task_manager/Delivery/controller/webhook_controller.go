@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListWebhooks lists every webhook, oldest first, without their secrets
+func (cr *Controller) ListWebhooks(ctx *gin.Context) {
+	webhooks, err := cr.WebhookUsecases.ListWebhooks(ctx)
+	if err != nil {
+		respondWebhookError(ctx, err, "Failed to retrieve webhooks")
+		return
+	}
+	response := make([]gin.H, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		response = append(response, webhookResponse(webhook))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"webhooks": response})
+}
+
+// CreateWebhook subscribes a URL to events. The secret is generated when
+// none is given, and only shown in this response
+func (cr *Controller) CreateWebhook(ctx *gin.Context) {
+	actor, ok := cr.currentActor(ctx)
+	if !ok {
+		return
+	}
+	var request struct {
+		URL    string   `json:"url" binding:"required"`
+		Events []string `json:"events" binding:"required"`
+		Secret string   `json:"secret"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "url and events are required"})
+		return
+	}
+
+	created, err := cr.WebhookUsecases.CreateWebhook(ctx, request.URL, request.Events, request.Secret, actor)
+	if err != nil {
+		respondWebhookError(ctx, err, "Failed to create webhook")
+		return
+	}
+	response := webhookResponse(created.Webhook)
+	response["secret"] = created.Secret
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Webhook created successfully", "webhook": response})
+}
+
+// GetWebhook returns one webhook without its secret
+func (cr *Controller) GetWebhook(ctx *gin.Context) {
+	webhook, err := cr.WebhookUsecases.GetWebhook(ctx, ctx.Param("id"))
+	if err != nil {
+		respondWebhookError(ctx, err, "Failed to retrieve webhook")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"webhook": webhookResponse(webhook)})
+}
+
+// UpdateWebhook changes the fields given. An empty secret generates a new
+// one, which is returned once
+func (cr *Controller) UpdateWebhook(ctx *gin.Context) {
+	var request struct {
+		URL    *string  `json:"url"`
+		Events []string `json:"events"`
+		Secret *string  `json:"secret"`
+		Active *bool    `json:"active"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := cr.WebhookUsecases.UpdateWebhook(ctx, ctx.Param("id"), domain.WebhookUpdate{
+		URL:    request.URL,
+		Events: request.Events,
+		Secret: request.Secret,
+		Active: request.Active,
+	})
+	if err != nil {
+		respondWebhookError(ctx, err, "Failed to update webhook")
+		return
+	}
+	response := webhookResponse(updated.Webhook)
+	if updated.Secret != "" {
+		response["secret"] = updated.Secret
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook updated successfully", "webhook": response})
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (cr *Controller) DeleteWebhook(ctx *gin.Context) {
+	if err := cr.WebhookUsecases.DeleteWebhook(ctx, ctx.Param("id")); err != nil {
+		respondWebhookError(ctx, err, "Failed to delete webhook")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// ListWebhookDeliveries returns a webhook's most recent deliveries, newest
+// first; limit caps how many (20 by default, at most 100)
+func (cr *Controller) ListWebhookDeliveries(ctx *gin.Context) {
+	limit := 0
+	if value := ctx.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit " + strconv.Quote(value)})
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := cr.WebhookUsecases.ListDeliveries(ctx, ctx.Param("id"), limit)
+	if err != nil {
+		respondWebhookError(ctx, err, "Failed to retrieve deliveries")
+		return
+	}
+	response := make([]gin.H, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, webhookDeliveryResponse(delivery))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"deliveries": response})
+}
+
+// RedeliverWebhook queues the payload of a past delivery to be sent again
+func (cr *Controller) RedeliverWebhook(ctx *gin.Context) {
+	delivery, err := cr.WebhookUsecases.Redeliver(ctx, ctx.Param("id"), ctx.Param("deliveryId"))
+	if err != nil {
+		respondWebhookError(ctx, err, "Failed to redeliver")
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "Delivery queued", "delivery": webhookDeliveryResponse(delivery)})
+}
+
+func respondWebhookError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+	case errors.Is(err, domain.ErrWebhookDeliveryNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+	default:
+		respondTaskError(ctx, err, fallback)
+	}
+}
+
+func webhookResponse(webhook *domain.Webhook) gin.H {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	return gin.H{
+		"id":         webhook.ID,
+		"url":        webhook.URL,
+		"events":     events,
+		"active":     webhook.Active,
+		"created_by": webhook.CreatedBy,
+		"created_at": webhook.CreatedAt,
+		"updated_at": webhook.UpdatedAt,
+	}
+}
+
+// webhookDeliveryResponse embeds the payload as the JSON it was sent as.
+func webhookDeliveryResponse(delivery *domain.WebhookDelivery) gin.H {
+	response := gin.H{
+		"id":              delivery.ID,
+		"event_id":        delivery.EventID,
+		"event_type":      delivery.EventType,
+		"payload":         json.RawMessage(delivery.Payload),
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"response_status": delivery.ResponseStatus,
+		"last_error":      delivery.LastError,
+		"created_at":      delivery.CreatedAt,
+		"delivered_at":    delivery.DeliveredAt,
+	}
+	if delivery.Status == domain.WebhookPending && delivery.Attempts > 0 {
+		response["next_attempt_at"] = delivery.LeaseUntil
+	}
+	if delivery.RedeliveryOf != "" {
+		response["redelivery_of"] = delivery.RedeliveryOf
+	}
+	return response
+}
@@ -0,0 +1,133 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookControllerSuite struct {
+	suite.Suite
+	webhookUsecase *mocks.WebhookUsecases
+	userUsecase    *mocks.UserUsecases
+	router         *gin.Engine
+	user           *domain.User
+}
+
+func (s *WebhookControllerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	s.webhookUsecase = new(mocks.WebhookUsecases)
+	s.userUsecase = new(mocks.UserUsecases)
+	s.user = &domain.User{ID: "admin-id", Role: domain.RoleAdmin}
+	s.userUsecase.On("GetCurrentUser", mock.Anything).Return(s.user, nil)
+
+	ctrl := &controller.Controller{UserUsecases: s.userUsecase, WebhookUsecases: s.webhookUsecase}
+	s.router = gin.New()
+	s.router.GET("/webhooks", ctrl.ListWebhooks)
+	s.router.POST("/webhooks", ctrl.CreateWebhook)
+	s.router.GET("/webhooks/:id", ctrl.GetWebhook)
+	s.router.PATCH("/webhooks/:id", ctrl.UpdateWebhook)
+	s.router.DELETE("/webhooks/:id", ctrl.DeleteWebhook)
+	s.router.GET("/webhooks/:id/deliveries", ctrl.ListWebhookDeliveries)
+	s.router.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", ctrl.RedeliverWebhook)
+}
+
+func TestWebhookControllerSuite(t *testing.T) {
+	suite.Run(t, new(WebhookControllerSuite))
+}
+
+func (s *WebhookControllerSuite) serve(method, url string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, url, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	s.router.ServeHTTP(res, req)
+	return res
+}
+
+func (s *WebhookControllerSuite) TestListWebhooks_HidesSecrets() {
+	s.webhookUsecase.On("ListWebhooks", mock.Anything).
+		Return([]*domain.Webhook{{ID: "w1", URL: "https://hooks.example.com", Events: []string{"*"}, Secret: "very-secret-value", Active: true}}, nil)
+
+	res := s.serve("GET", "/webhooks", nil)
+
+	assert.Equal(s.T(), http.StatusOK, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"url":"https://hooks.example.com"`)
+	assert.NotContains(s.T(), res.Body.String(), "very-secret-value")
+}
+
+func (s *WebhookControllerSuite) TestCreateWebhook() {
+	assert := assert.New(s.T())
+	s.webhookUsecase.On("CreateWebhook", mock.Anything, "https://hooks.example.com", []string{domain.EventTaskCreated}, "", &domain.Actor{User: s.user}).
+		Return(&domain.NewWebhook{Webhook: &domain.Webhook{ID: "w1", Secret: "generated-secret"}, Secret: "generated-secret"}, nil)
+	s.webhookUsecase.On("CreateWebhook", mock.Anything, "ftp://hooks.example.com", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, domain.ErrInvalidWebhook)
+
+	res := s.serve("POST", "/webhooks", map[string]any{"url": "https://hooks.example.com", "events": []string{domain.EventTaskCreated}})
+	assert.Equal(http.StatusCreated, res.Code)
+	assert.Contains(res.Body.String(), `"secret":"generated-secret"`, "the secret is shown once, on creation")
+
+	assert.Equal(http.StatusBadRequest, s.serve("POST", "/webhooks", map[string]any{"url": "https://hooks.example.com"}).Code)
+	assert.Equal(http.StatusBadRequest, s.serve("POST", "/webhooks", map[string]any{"url": "ftp://hooks.example.com", "events": []string{"*"}}).Code)
+}
+
+func (s *WebhookControllerSuite) TestUpdateWebhook() {
+	assert := assert.New(s.T())
+	s.webhookUsecase.On("UpdateWebhook", mock.Anything, "w1", mock.MatchedBy(func(u domain.WebhookUpdate) bool {
+		return u.Active != nil && !*u.Active && u.URL == nil && u.Secret == nil
+	})).Return(&domain.NewWebhook{Webhook: &domain.Webhook{ID: "w1", Secret: "kept-secret-value"}}, nil)
+	s.webhookUsecase.On("UpdateWebhook", mock.Anything, "missing", mock.Anything).Return(nil, domain.ErrWebhookNotFound)
+
+	res := s.serve("PATCH", "/webhooks/w1", map[string]any{"active": false})
+	assert.Equal(http.StatusOK, res.Code)
+	assert.NotContains(res.Body.String(), "secret")
+	assert.Equal(http.StatusNotFound, s.serve("PATCH", "/webhooks/missing", map[string]any{"active": true}).Code)
+}
+
+func (s *WebhookControllerSuite) TestDeleteWebhook() {
+	s.webhookUsecase.On("DeleteWebhook", mock.Anything, "w1").Return(nil)
+	s.webhookUsecase.On("DeleteWebhook", mock.Anything, "missing").Return(domain.ErrWebhookNotFound)
+
+	assert.Equal(s.T(), http.StatusOK, s.serve("DELETE", "/webhooks/w1", nil).Code)
+	assert.Equal(s.T(), http.StatusNotFound, s.serve("DELETE", "/webhooks/missing", nil).Code)
+}
+
+func (s *WebhookControllerSuite) TestListWebhookDeliveries() {
+	assert := assert.New(s.T())
+	retryAt := time.Now().Add(time.Minute)
+	s.webhookUsecase.On("ListDeliveries", mock.Anything, "w1", 5).Return([]*domain.WebhookDelivery{
+		{ID: "d1", EventType: domain.EventTaskCreated, Payload: `{"id":"e1"}`, Status: domain.WebhookPending, Attempts: 1, LeaseUntil: retryAt, ResponseStatus: 500},
+	}, nil)
+
+	res := s.serve("GET", "/webhooks/w1/deliveries?limit=5", nil)
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), `"payload":{"id":"e1"}`)
+	assert.Contains(res.Body.String(), `"next_attempt_at"`)
+	assert.Equal(http.StatusBadRequest, s.serve("GET", "/webhooks/w1/deliveries?limit=none", nil).Code)
+}
+
+func (s *WebhookControllerSuite) TestRedeliverWebhook() {
+	s.webhookUsecase.On("Redeliver", mock.Anything, "w1", "d1").
+		Return(&domain.WebhookDelivery{ID: "d2", Payload: `{}`, Status: domain.WebhookPending, RedeliveryOf: "d1"}, nil)
+	s.webhookUsecase.On("Redeliver", mock.Anything, "w1", "other").Return(nil, domain.ErrWebhookDeliveryNotFound)
+
+	res := s.serve("POST", "/webhooks/w1/deliveries/d1/redeliver", nil)
+	assert.Equal(s.T(), http.StatusAccepted, res.Code)
+	assert.Contains(s.T(), res.Body.String(), `"redelivery_of":"d1"`)
+	assert.Equal(s.T(), http.StatusNotFound, s.serve("POST", "/webhooks/w1/deliveries/other/redeliver", nil).Code)
+}
@@ -2,11 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"maps"
+	"net/http"
+	"net/netip"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 
 	"task_manager/Delivery/controller"
 	router "task_manager/Delivery/routers"
+	domain "task_manager/Domain"
 	infrastructure "task_manager/Infrastructure"
 	repository "task_manager/Repository"
 	usecases "task_manager/Usecases"
@@ -19,44 +30,536 @@ import (
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
+	// The .env file is optional; the environment may come from elsewhere.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("Error loading .env file: %v", err)
 	}
 
-	mongoURI := os.Getenv("DATABASE_URL")
-	if mongoURI == "" {
-		log.Fatal("DATABASE_URL environment variable is not set")
-	}
-	clientOptions := options.Client().ApplyURI(mongoURI)
+	// Repositories
+	repos := newRepositories(os.Getenv("STORAGE_BACKEND"))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := mongo.Connect(ctx, clientOptions)
+	// Initialize services
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Fatal("JWT_SECRET environment variable is not set")
+	}
+	accessTTL := durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	keyRotation := domain.KeyRotationPolicy{
+		Interval: durationFromEnv("JWT_KEY_ROTATION_INTERVAL", domain.DefaultKeyRotation.Interval),
+		Grace:    durationFromEnv("JWT_KEY_GRACE_PERIOD", domain.DefaultKeyRotation.Grace),
+	}
+	if keyRotation.Grace < accessTTL {
+		log.Fatal("JWT_KEY_GRACE_PERIOD must be at least ACCESS_TOKEN_TTL, or tokens would outlive their key")
+	}
+	signingAlgorithm := os.Getenv("JWT_SIGNING_ALG")
+	if signingAlgorithm == "" {
+		signingAlgorithm = domain.SigningAlgorithmRS256
+	}
+	refreshTTL := durationFromEnv("REFRESH_TOKEN_TTL", 7*24*time.Hour)
+	versionCacheTTL := durationFromEnv("TOKEN_VERSION_CACHE_TTL", 30*time.Second)
+	resetTTL := durationFromEnv("PASSWORD_RESET_TTL", time.Hour)
+	verificationTTL := durationFromEnv("EMAIL_VERIFICATION_TTL", 48*time.Hour)
+	challengeTTL := durationFromEnv("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute)
+	personalTokenMaxTTL := durationFromEnv("PERSONAL_ACCESS_TOKEN_MAX_TTL", 365*24*time.Hour)
+	unverifiedAccess, err := router.ParseUnverifiedAccess(os.Getenv("UNVERIFIED_TASK_ACCESS"))
 	if err != nil {
 		log.Fatal(err)
 	}
+	publicURL := os.Getenv("PUBLIC_URL")
+	if publicURL == "" {
+		publicURL = "http://localhost:8080"
+	}
 
-	// Collections
-	db := client.Database("task_db")
-	userRepo := repository.NewUserRepository(db, "users")
-	taskRepo := repository.NewTaskRepository(db, "tasks")
-	
-	// Initialize services
 	passwordService := infrastructure.NewPasswordService()
-	jwtService := infrastructure.NewJWTService()
+	keyRing, err := infrastructure.NewKeyRing(repos.signingKeys, jwtSecret, signingAlgorithm, keyRotation)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Fail now, rather than on the first login, if the stored keys cannot
+	// be decrypted, e.g. because JWT_SECRET changed.
+	if _, err := keyRing.PublicKeys(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	jwtService := infrastructure.NewJWTService(keyRing, accessTTL)
+	// The OIDC issuer is PUBLIC_URL; relying parties find the provider's
+	// metadata under it.
+	oidcTokenService := infrastructure.NewOIDCTokenService(keyRing, strings.TrimSuffix(publicURL, "/"), accessTTL)
+	verificationTokenService := infrastructure.NewVerificationTokenService(jwtSecret, verificationTTL)
+	challengeService := infrastructure.NewTwoFactorChallengeService(jwtSecret, challengeTTL)
+	totpIssuer := os.Getenv("TOTP_ISSUER")
+	if totpIssuer == "" {
+		totpIssuer = "Task Manager"
+	}
+	totpService := infrastructure.NewTOTPService(totpIssuer)
+	workflow, err := infrastructure.LoadWorkflow(os.Getenv("WORKFLOW_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	subtaskRules, err := domain.NewSubtaskRules(os.Getenv("SUBTASKS_ON_DELETE"), os.Getenv("SUBTASKS_ON_COMPLETE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	mailer := newMailer(os.Getenv("MAIL_TRANSPORT"))
+	// Reminder channels users can pick from; "log" writes reminders to the
+	// server log.
+	notifiers := map[string]domain.Notifier{
+		domain.ReminderChannelEmail: infrastructure.NewEmailNotifier(mailer),
+		"log":                       infrastructure.NewLogNotifier(nil),
+	}
+	reminderConfig := domain.DefaultReminderScheduler
+	reminderConfig.PollInterval = durationFromEnv("REMINDER_POLL_INTERVAL", reminderConfig.PollInterval)
+	reminderConfig.MaxLateness = durationFromEnv("REMINDER_MAX_LATENESS", reminderConfig.MaxLateness)
+	reminderConfig.Lease = durationFromEnv("REMINDER_LEASE", reminderConfig.Lease)
+	webhookConfig := domain.DefaultWebhookDispatcher
+	webhookConfig.PollInterval = durationFromEnv("WEBHOOK_POLL_INTERVAL", webhookConfig.PollInterval)
+	webhookConfig.Lease = durationFromEnv("WEBHOOK_LEASE", webhookConfig.Lease)
+	webhookConfig.RetryBackoff = durationFromEnv("WEBHOOK_RETRY_BACKOFF", webhookConfig.RetryBackoff)
+	outboxConfig := domain.DefaultOutboxRelay
+	outboxConfig.PollInterval = durationFromEnv("OUTBOX_POLL_INTERVAL", outboxConfig.PollInterval)
+	outboxConfig.Retention = durationFromEnv("OUTBOX_RETENTION", outboxConfig.Retention)
 
 	// Initialize usecases
 	timeout := 10 * time.Second
-	userUsecase := usecases.NewUserUsecases(userRepo, passwordService, jwtService, timeout)
-	taskUsecase := usecases.NewTaskUsecases(taskRepo, timeout)
+	tokenUsecase := usecases.NewTokenUsecases(jwtService, repos.refreshTokens, repos.revokedTokens, repos.users, refreshTTL, versionCacheTTL, timeout)
+	roleUsecase := usecases.NewRoleUsecases(repos.roles, repos.users, tokenUsecase, repos.transactor, repos.audit, timeout)
+	personalTokenUsecase := usecases.NewPersonalAccessTokenUsecases(repos.personalTokens, repos.users, roleUsecase, personalTokenMaxTTL, repos.transactor, repos.audit, timeout)
+	twoFactorUsecase := usecases.NewTwoFactorUsecases(repos.twoFactor, repos.twoFactorRoles, repos.users, roleUsecase, totpService, challengeService, tokenUsecase, repos.loginAttempts, domain.DefaultAccountLockout, domain.DefaultIPLockout, repos.transactor, repos.audit, timeout)
+	// Task and user changes are written to the outbox along with the change.
+	// The relay hands them on to each consumer: webhooks turns them into
+	// deliveries, which the dispatcher sends in the background.
+	outboxPublisher := usecases.NewOutboxPublisher(repos.outbox)
+	webhookPublisher := usecases.NewWebhookPublisher(repos.webhooks, repos.webhookDeliveries, timeout)
+	outboxRelay := usecases.NewOutboxRelay(repos.outbox, repos.outboxCheckpoints, repos.transactor, map[string]domain.EventPublisher{
+		"webhooks": webhookPublisher,
+	}, outboxConfig, timeout)
+	userUsecase := usecases.NewUserUsecases(repos.users, repos.tasks, repos.comments, repos.dependencies, passwordService, tokenUsecase, twoFactorUsecase, repos.loginAttempts, domain.DefaultAccountLockout, domain.DefaultIPLockout, repos.transactor, outboxPublisher, repos.audit, timeout)
+	taskUsecase := usecases.NewTaskUsecases(repos.tasks, repos.comments, repos.labels, repos.dependencies, workflow, subtaskRules, repos.transactor, outboxPublisher, repos.audit, timeout)
+	commentUsecase := usecases.NewCommentUsecases(repos.comments, repos.tasks, repos.transactor, repos.audit, timeout)
+	labelUsecase := usecases.NewLabelUsecases(repos.labels, repos.tasks, repos.transactor, repos.audit, timeout)
+	reminderUsecase := usecases.NewReminderUsecases(repos.reminders, slices.Sorted(maps.Keys(notifiers)), repos.transactor, repos.audit, timeout)
+	reminderScheduler := usecases.NewReminderScheduler(repos.reminders, repos.reminderDeliveries, repos.tasks, repos.users, workflow, notifiers, reminderConfig, timeout)
+	webhookUsecase := usecases.NewWebhookUsecases(repos.webhooks, repos.webhookDeliveries, repos.transactor, repos.audit, timeout)
+	webhookSender := infrastructure.NewHTTPWebhookSender(
+		&http.Client{Timeout: durationFromEnv("WEBHOOK_TIMEOUT", infrastructure.DefaultWebhookTimeout)},
+		webhookAllowedNetworks(os.Getenv("WEBHOOK_ALLOWED_NETWORKS")),
+	)
+	webhookDispatcher := usecases.NewWebhookDispatcher(repos.webhooks, repos.webhookDeliveries, webhookSender, webhookConfig, timeout)
+	auditUsecase := usecases.NewAuditUsecases(repos.audit, timeout)
+	passwordResetUsecase := usecases.NewPasswordResetUsecases(repos.passwordResets, repos.users, repos.personalTokens, passwordService, mailer, tokenUsecase, os.Getenv("PASSWORD_RESET_URL"), resetTTL, repos.transactor, repos.audit, timeout)
+	verificationUsecase := usecases.NewEmailVerificationUsecases(verificationTokenService, repos.users, mailer, tokenUsecase, strings.TrimSuffix(publicURL, "/")+"/verify", repos.transactor, repos.audit, timeout)
+	oidcUsecase := usecases.NewOIDCUsecases(repos.oidcClients, repos.oidcCodes, repos.users, passwordService, twoFactorUsecase, oidcTokenService, repos.loginAttempts, domain.DefaultAccountLockout, domain.DefaultIPLockout, publicURL, repos.transactor, repos.audit, timeout)
 
 	// Initialize controllers
-	ctrl := controller.NewController(taskUsecase, userUsecase)
+	ctrl := &controller.Controller{
+		TaskUsecases:          taskUsecase,
+		UserUsecases:          userUsecase,
+		TokenUsecases:         tokenUsecase,
+		RoleUsecases:          roleUsecase,
+		PasswordResetUsecases: passwordResetUsecase,
+		VerificationUsecases:  verificationUsecase,
+		TwoFactorUsecases:     twoFactorUsecase,
+		PersonalTokenUsecases: personalTokenUsecase,
+		OIDCUsecases:          oidcUsecase,
+		CommentUsecases:       commentUsecase,
+		LabelUsecases:         labelUsecase,
+		ReminderUsecases:      reminderUsecase,
+		WebhookUsecases:       webhookUsecase,
+		AuditUsecases:         auditUsecase,
+	}
 
 	// Setup router
 	engine := gin.Default()
-	router.SetupRouter(engine, ctrl)
+	// Login lockouts are keyed by client IP, so X-Forwarded-For is only
+	// believed when it comes from a proxy listed in TRUSTED_PROXIES.
+	if err := engine.SetTrustedProxies(trustedProxies(os.Getenv("TRUSTED_PROXIES"))); err != nil {
+		log.Fatal(err)
+	}
+	router.SetupRouter(engine, ctrl, tokenUsecase, personalTokenUsecase, roleUsecase, unverifiedAccess)
+
+	// SIGINT or SIGTERM stops accepting requests, relaying events, reminders
+	// and webhook deliveries, then waits up to SHUTDOWN_TIMEOUT for the ones in progress.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+	workers.Add(3)
+	go func() {
+		defer workers.Done()
+		outboxRelay.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		reminderScheduler.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		webhookDispatcher.Run(ctx)
+	}()
+
+	server := &http.Server{Addr: ":8080", Handler: engine}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), durationFromEnv("SHUTDOWN_TIMEOUT", 30*time.Second))
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP shutdown: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		log.Println("Gave up waiting for events, reminders and webhook deliveries in progress; their leases will run out and another instance will retry them")
+	}
+}
+
+// repositories groups the stores the application is wired with.
+type repositories struct {
+	users          domain.UserRepository
+	tasks          domain.TaskRepository
+	roles          domain.RoleRepository
+	refreshTokens  domain.RefreshTokenRepository
+	revokedTokens  domain.RevokedTokenRepository
+	passwordResets domain.PasswordResetTokenRepository
+	loginAttempts  domain.LoginAttemptRepository
+	twoFactor      domain.TwoFactorRepository
+	twoFactorRoles domain.TwoFactorRoleRepository
+	personalTokens domain.PersonalAccessTokenRepository
+	signingKeys    domain.SigningKeyRepository
+	oidcClients    domain.OIDCClientRepository
+	oidcCodes      domain.AuthorizationCodeRepository
+	comments       domain.CommentRepository
+	labels         domain.LabelRepository
+	dependencies   domain.DependencyRepository
+	reminders      domain.ReminderRepository
+	// reminderDeliveries holds reminder send state, shared by every
+	// instance's scheduler.
+	reminderDeliveries domain.ReminderDeliveryRepository
+	webhooks           domain.WebhookRepository
+	// webhookDeliveries is the webhook delivery log and queue.
+	webhookDeliveries domain.WebhookDeliveryRepository
+	outbox            domain.OutboxRepository
+	outboxCheckpoints domain.OutboxCheckpointRepository
+	// audit is the append-only audit log, written in the transaction of
+	// the change it records.
+	audit domain.AuditRepository
+	// transactor stores a change and its events together; nil for the
+	// in-memory store, which has no transactions.
+	transactor domain.Transactor
+}
+
+// newRepositories builds the repositories for the selected STORAGE_BACKEND:
+// "mongo" (the default), "sqlite" for a single-file embedded database at
+// SQLITE_PATH, or "memory" for database-free runs.
+func newRepositories(backend string) repositories {
+	switch backend {
+	case "", "mongo":
+		db := connectMongo()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := repository.EnsureTaskIndexes(ctx, db, domain.TaskCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureUserIndexes(ctx, db, domain.UserCollection, domain.AdminGuardCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureRoleIndexes(ctx, db, domain.RoleCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureRefreshTokenIndexes(ctx, db, domain.RefreshTokenCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureRevokedTokenIndexes(ctx, db, domain.RevokedTokenCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsurePasswordResetIndexes(ctx, db, domain.PasswordResetCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureLoginAttemptIndexes(ctx, db, domain.LoginAttemptCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureTwoFactorIndexes(ctx, db, domain.TwoFactorCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureTwoFactorRoleIndexes(ctx, db, domain.TwoFactorRoleCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsurePersonalAccessTokenIndexes(ctx, db, domain.PersonalAccessTokenCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureSigningKeyIndexes(ctx, db, domain.SigningKeyCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureOIDCClientIndexes(ctx, db, domain.OIDCClientCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureAuthorizationCodeIndexes(ctx, db, domain.AuthorizationCodeCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureCommentIndexes(ctx, db, domain.CommentCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureLabelIndexes(ctx, db, domain.LabelCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureDependencyIndexes(ctx, db, domain.DependencyCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureReminderIndexes(ctx, db, domain.ReminderCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureReminderDeliveryIndexes(ctx, db, domain.ReminderDeliveryCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureWebhookIndexes(ctx, db, domain.WebhookCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureWebhookDeliveryIndexes(ctx, db, domain.WebhookDeliveryCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureOutboxIndexes(ctx, db, domain.OutboxCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureOutboxCheckpointIndexes(ctx, db, domain.OutboxCheckpointCollection); err != nil {
+			log.Fatal(err)
+		}
+		if err := repository.EnsureAuditIndexes(ctx, db, domain.AuditCollection, domain.AuditHeadCollection); err != nil {
+			log.Fatal(err)
+		}
+		supportsTransactions, err := repository.MongoSupportsTransactions(ctx, db.Client())
+		if err != nil {
+			log.Fatal(err)
+		}
+		var transactor domain.Transactor
+		if supportsTransactions {
+			transactor = repository.NewMongoTransactor(db.Client())
+		} else if boolFromEnv("OUTBOX_ALLOW_NON_TRANSACTIONAL") {
+			log.Println("MongoDB is a standalone server without transactions: a change, its events and its audit entry are stored separately, and a crash between them loses the events or the entry")
+		} else {
+			log.Fatal("MongoDB is a standalone server without transactions, which the outbox and the audit log need: run it as a replica set, or set OUTBOX_ALLOW_NON_TRANSACTIONAL=true to store changes, events and audit entries separately")
+		}
+		return repositories{
+			users:              repository.NewUserRepository(db, domain.UserCollection, domain.AdminGuardCollection),
+			tasks:              repository.NewTaskRepository(db, domain.TaskCollection),
+			roles:              repository.NewRoleRepository(db, domain.RoleCollection),
+			refreshTokens:      repository.NewRefreshTokenRepository(db, domain.RefreshTokenCollection),
+			revokedTokens:      repository.NewRevokedTokenRepository(db, domain.RevokedTokenCollection),
+			passwordResets:     repository.NewPasswordResetTokenRepository(db, domain.PasswordResetCollection),
+			loginAttempts:      repository.NewLoginAttemptRepository(db, domain.LoginAttemptCollection),
+			twoFactor:          repository.NewTwoFactorRepository(db, domain.TwoFactorCollection),
+			twoFactorRoles:     repository.NewTwoFactorRoleRepository(db, domain.TwoFactorRoleCollection),
+			personalTokens:     repository.NewPersonalAccessTokenRepository(db, domain.PersonalAccessTokenCollection),
+			signingKeys:        repository.NewSigningKeyRepository(db, domain.SigningKeyCollection),
+			oidcClients:        repository.NewOIDCClientRepository(db, domain.OIDCClientCollection),
+			oidcCodes:          repository.NewAuthorizationCodeRepository(db, domain.AuthorizationCodeCollection),
+			comments:           repository.NewCommentRepository(db, domain.CommentCollection),
+			labels:             repository.NewLabelRepository(db, domain.LabelCollection),
+			dependencies:       repository.NewDependencyRepository(db, domain.DependencyCollection),
+			reminders:          repository.NewReminderRepository(db, domain.ReminderCollection),
+			reminderDeliveries: repository.NewReminderDeliveryRepository(db, domain.ReminderDeliveryCollection),
+			webhooks:           repository.NewWebhookRepository(db, domain.WebhookCollection),
+			webhookDeliveries:  repository.NewWebhookDeliveryRepository(db, domain.WebhookDeliveryCollection),
+			outbox:             repository.NewOutboxRepository(db, domain.OutboxCollection, domain.OutboxCounterCollection),
+			outboxCheckpoints:  repository.NewOutboxCheckpointRepository(db, domain.OutboxCheckpointCollection),
+			audit:              repository.NewAuditRepository(db, domain.AuditCollection, domain.AuditHeadCollection),
+			transactor:         transactor,
+		}
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = "task_manager.db"
+		}
+		db, err := repository.OpenSQLite(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Println("SQLite backend: sessions, password reset links, OIDC authorization codes and login lockouts are kept in memory and end on restart")
+		return repositories{
+			users:              repository.NewSQLiteUserRepository(db),
+			tasks:              repository.NewSQLiteTaskRepository(db),
+			roles:              repository.NewSQLiteRoleRepository(db),
+			refreshTokens:      repository.NewInMemoryRefreshTokenRepository(),
+			revokedTokens:      repository.NewInMemoryRevokedTokenRepository(),
+			passwordResets:     repository.NewInMemoryPasswordResetTokenRepository(),
+			loginAttempts:      repository.NewInMemoryLoginAttemptRepository(),
+			twoFactor:          repository.NewSQLiteTwoFactorRepository(db),
+			twoFactorRoles:     repository.NewSQLiteTwoFactorRoleRepository(db),
+			personalTokens:     repository.NewSQLitePersonalAccessTokenRepository(db),
+			signingKeys:        repository.NewSQLiteSigningKeyRepository(db),
+			oidcClients:        repository.NewSQLiteOIDCClientRepository(db),
+			oidcCodes:          repository.NewInMemoryAuthorizationCodeRepository(),
+			comments:           repository.NewSQLiteCommentRepository(db),
+			labels:             repository.NewSQLiteLabelRepository(db),
+			dependencies:       repository.NewSQLiteDependencyRepository(db),
+			reminders:          repository.NewSQLiteReminderRepository(db),
+			reminderDeliveries: repository.NewSQLiteReminderDeliveryRepository(db),
+			webhooks:           repository.NewSQLiteWebhookRepository(db),
+			webhookDeliveries:  repository.NewSQLiteWebhookDeliveryRepository(db),
+			outbox:             repository.NewSQLiteOutboxRepository(db),
+			outboxCheckpoints:  repository.NewSQLiteOutboxCheckpointRepository(db),
+			audit:              repository.NewSQLiteAuditRepository(db),
+			transactor:         repository.NewSQLiteTransactor(db),
+		}
+	case "memory":
+		log.Println("Using in-memory storage; data will be lost on restart")
+		return repositories{
+			users:              repository.NewInMemoryUserRepository(),
+			tasks:              repository.NewInMemoryTaskRepository(),
+			roles:              repository.NewInMemoryRoleRepository(),
+			refreshTokens:      repository.NewInMemoryRefreshTokenRepository(),
+			revokedTokens:      repository.NewInMemoryRevokedTokenRepository(),
+			passwordResets:     repository.NewInMemoryPasswordResetTokenRepository(),
+			loginAttempts:      repository.NewInMemoryLoginAttemptRepository(),
+			twoFactor:          repository.NewInMemoryTwoFactorRepository(),
+			twoFactorRoles:     repository.NewInMemoryTwoFactorRoleRepository(),
+			personalTokens:     repository.NewInMemoryPersonalAccessTokenRepository(),
+			signingKeys:        repository.NewInMemorySigningKeyRepository(),
+			oidcClients:        repository.NewInMemoryOIDCClientRepository(),
+			oidcCodes:          repository.NewInMemoryAuthorizationCodeRepository(),
+			comments:           repository.NewInMemoryCommentRepository(),
+			labels:             repository.NewInMemoryLabelRepository(),
+			dependencies:       repository.NewInMemoryDependencyRepository(),
+			reminders:          repository.NewInMemoryReminderRepository(),
+			reminderDeliveries: repository.NewInMemoryReminderDeliveryRepository(),
+			webhooks:           repository.NewInMemoryWebhookRepository(),
+			webhookDeliveries:  repository.NewInMemoryWebhookDeliveryRepository(),
+			outbox:             repository.NewInMemoryOutboxRepository(),
+			outboxCheckpoints:  repository.NewInMemoryOutboxCheckpointRepository(),
+			audit:              repository.NewInMemoryAuditRepository(),
+		}
+	default:
+		log.Fatalf("Unknown STORAGE_BACKEND %q", backend)
+		return repositories{}
+	}
+}
+
+// newMailer builds the IMailer for MAIL_TRANSPORT: "maildir" (the default)
+// writes messages into MAILDIR_PATH for local development, "smtp" relays
+// them through SMTP_HOST. Both send as MAIL_FROM.
+func newMailer(transport string) domain.IMailer {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "Task Manager <no-reply@localhost>"
+	}
+	switch transport {
+	case "", "maildir":
+		dir := os.Getenv("MAILDIR_PATH")
+		if dir == "" {
+			dir = "maildir"
+		}
+		mailer, err := infrastructure.NewMaildirMailer(dir, from)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Outgoing mail is written to %s/new", dir)
+		return mailer
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			log.Fatal("SMTP_HOST environment variable is not set")
+		}
+		port := 587
+		if value := os.Getenv("SMTP_PORT"); value != "" {
+			p, err := strconv.Atoi(value)
+			if err != nil {
+				log.Fatalf("SMTP_PORT must be a number: %q", value)
+			}
+			port = p
+		}
+		return infrastructure.NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	default:
+		log.Fatalf("Unknown MAIL_TRANSPORT %q", transport)
+		return nil
+	}
+}
+
+// trustedProxies parses the comma-separated TRUSTED_PROXIES list of IPs and
+// CIDRs. Unset means no proxy is trusted and the client IP is always the
+// peer address.
+func trustedProxies(value string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// webhookAllowedNetworks parses the comma-separated WEBHOOK_ALLOWED_NETWORKS
+// list of IPs and CIDRs that webhooks may be sent to although they are not
+// public. Unset means webhooks only go to public addresses.
+func webhookAllowedNetworks(value string) []netip.Prefix {
+	var networks []netip.Prefix
+	for _, network := range strings.Split(value, ",") {
+		if network = strings.TrimSpace(network); network == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(network)
+			if addrErr != nil {
+				log.Fatalf("WEBHOOK_ALLOWED_NETWORKS must list IPs and CIDRs: %q", network)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		networks = append(networks, prefix)
+	}
+	return networks
+}
+
+// durationFromEnv parses a Go duration such as "15m" from the environment,
+// falling back to def when the variable is unset.
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("%s must be a positive duration such as 15m: %q", key, value)
+	}
+	return d
+}
+
+// boolFromEnv parses a boolean such as "true" or "1" from the environment,
+// falling back to false when the variable is unset.
+func boolFromEnv(key string) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("%s must be true or false: %q", key, value)
+	}
+	return b
+}
+
+func connectMongo() *mongo.Database {
+	mongoURI := os.Getenv("DATABASE_URL")
+	if mongoURI == "" {
+		log.Fatal("DATABASE_URL environment variable is not set")
+	}
+	clientOptions := options.Client().ApplyURI(mongoURI)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	engine.Run(":8080")
+	return client.Database("task_db")
 }
@@ -0,0 +1,375 @@
+package router_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"html"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"task_manager/Delivery/controller"
+	router "task_manager/Delivery/routers"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	repository "task_manager/Repository"
+	usecases "task_manager/Usecases"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	flowRedirectURI = "https://rp.example.com/callback"
+	flowEmail       = "ada@example.com"
+	flowPassword    = "correct horse battery staple"
+)
+
+// discardMailer drops the verification email sent on registration.
+type discardMailer struct{}
+
+func (discardMailer) Send(context.Context, *domain.MailMessage) error { return nil }
+
+var hiddenInput = regexp.MustCompile(`<input type="hidden" name="([^"]+)" value="([^"]*)">`)
+
+// OIDCFlowSuite runs the authorization code flow end to end, the way a
+// relying party would, against the real router over HTTP.
+type OIDCFlowSuite struct {
+	suite.Suite
+	server       *httptest.Server
+	client       *http.Client
+	apiToken     string
+	clientID     string
+	clientSecret string
+}
+
+func (suite *OIDCFlowSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	// The issuer is the server's own URL, which is only known once it
+	// listens, so the handler is filled in afterwards.
+	var handler http.Handler
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	suite.client = &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	issuer := suite.server.URL
+	timeout := 5 * time.Second
+
+	users := repository.NewInMemoryUserRepository()
+	tasks := repository.NewInMemoryTaskRepository()
+	attempts := repository.NewInMemoryLoginAttemptRepository()
+	keyRing, err := infrastructure.NewKeyRing(repository.NewInMemorySigningKeyRepository(), "test-secret", domain.SigningAlgorithmRS256, domain.DefaultKeyRotation)
+	suite.Require().NoError(err)
+	workflow, err := infrastructure.LoadWorkflow("")
+	suite.Require().NoError(err)
+	passwords := infrastructure.NewPasswordService()
+	challenges := infrastructure.NewTwoFactorChallengeService("test-secret", 5*time.Minute)
+
+	tokenUC := usecases.NewTokenUsecases(infrastructure.NewJWTService(keyRing, 15*time.Minute), repository.NewInMemoryRefreshTokenRepository(), repository.NewInMemoryRevokedTokenRepository(), users, time.Hour, 0, timeout)
+	roleUC := usecases.NewRoleUsecases(repository.NewInMemoryRoleRepository(), users, tokenUC, nil, nil, timeout)
+	personalTokens := repository.NewInMemoryPersonalAccessTokenRepository()
+	patUC := usecases.NewPersonalAccessTokenUsecases(personalTokens, users, roleUC, 24*time.Hour, nil, nil, timeout)
+	twoFactorUC := usecases.NewTwoFactorUsecases(repository.NewInMemoryTwoFactorRepository(), repository.NewInMemoryTwoFactorRoleRepository(), users, roleUC, infrastructure.NewTOTPService("Task Manager"), challenges, tokenUC, attempts, domain.DefaultAccountLockout, domain.DefaultIPLockout, nil, nil, timeout)
+	comments := repository.NewInMemoryCommentRepository()
+	dependencies := repository.NewInMemoryDependencyRepository()
+	userUC := usecases.NewUserUsecases(users, tasks, comments, dependencies, passwords, tokenUC, twoFactorUC, attempts, domain.DefaultAccountLockout, domain.DefaultIPLockout, nil, nil, nil, timeout)
+	verificationUC := usecases.NewEmailVerificationUsecases(infrastructure.NewVerificationTokenService("test-secret", time.Hour), users, discardMailer{}, tokenUC, issuer+"/verify", nil, nil, timeout)
+	resetUC := usecases.NewPasswordResetUsecases(repository.NewInMemoryPasswordResetTokenRepository(), users, personalTokens, passwords, discardMailer{}, tokenUC, issuer+"/reset", time.Hour, nil, nil, timeout)
+	oidcUC := usecases.NewOIDCUsecases(repository.NewInMemoryOIDCClientRepository(), repository.NewInMemoryAuthorizationCodeRepository(), users, passwords, twoFactorUC, infrastructure.NewOIDCTokenService(keyRing, issuer, 15*time.Minute), attempts, domain.DefaultAccountLockout, domain.DefaultIPLockout, issuer, nil, nil, timeout)
+
+	taskUC := usecases.NewTaskUsecases(tasks, comments, repository.NewInMemoryLabelRepository(), dependencies, workflow, domain.DefaultSubtaskRules(), nil, nil, nil, timeout)
+	commentUC := usecases.NewCommentUsecases(comments, tasks, nil, nil, timeout)
+
+	ctrl := &controller.Controller{
+		TaskUsecases:          taskUC,
+		UserUsecases:          userUC,
+		TokenUsecases:         tokenUC,
+		RoleUsecases:          roleUC,
+		PasswordResetUsecases: resetUC,
+		VerificationUsecases:  verificationUC,
+		TwoFactorUsecases:     twoFactorUC,
+		PersonalTokenUsecases: patUC,
+		OIDCUsecases:          oidcUC,
+		CommentUsecases:       commentUC,
+	}
+	engine := gin.New()
+	router.SetupRouter(engine, ctrl, tokenUC, patUC, roleUC, router.UnverifiedAccessNone)
+	handler = engine
+
+	// The first account is the admin, who registers the relying party.
+	suite.postJSON("/register", "", map[string]string{"username": "ada", "email": flowEmail, "password": flowPassword}, nil)
+	var login struct {
+		Token string `json:"token"`
+	}
+	suite.postJSON("/login", "", map[string]string{"email": flowEmail, "password": flowPassword}, &login)
+	suite.Require().NotEmpty(login.Token)
+	suite.apiToken = login.Token
+
+	var registered struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	status := suite.postJSON("/oauth/clients", suite.apiToken, map[string]any{"name": "Relying Party", "redirect_uris": []string{flowRedirectURI}}, &registered)
+	suite.Require().Equal(http.StatusCreated, status)
+	suite.clientID = registered.ClientID
+	suite.clientSecret = registered.ClientSecret
+}
+
+func (suite *OIDCFlowSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *OIDCFlowSuite) postJSON(path, bearer string, body any, out any) int {
+	payload, err := json.Marshal(body)
+	suite.Require().NoError(err)
+	req, err := http.NewRequest(http.MethodPost, suite.server.URL+path, strings.NewReader(string(payload)))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	return suite.do(req, out)
+}
+
+func (suite *OIDCFlowSuite) get(path, bearer string, out any) int {
+	req, err := http.NewRequest(http.MethodGet, suite.server.URL+path, nil)
+	suite.Require().NoError(err)
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	return suite.do(req, out)
+}
+
+func (suite *OIDCFlowSuite) do(req *http.Request, out any) int {
+	res, err := suite.client.Do(req)
+	suite.Require().NoError(err)
+	defer res.Body.Close()
+	if out != nil {
+		suite.Require().NoError(json.NewDecoder(res.Body).Decode(out))
+	}
+	return res.StatusCode
+}
+
+// authorizeQuery is what a relying party sends the browser to, with a
+// fresh PKCE verifier.
+func (suite *OIDCFlowSuite) authorizeQuery(extra url.Values) (url.Values, string) {
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {suite.clientID},
+		"redirect_uri":          {flowRedirectURI},
+		"scope":                 {"openid profile email"},
+		"state":                 {"af0ifjsldkj"},
+		"nonce":                 {"n-0S6_WzA2Mj"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	for name, values := range extra {
+		query[name] = values
+	}
+	return query, verifier
+}
+
+// signIn loads the sign-in page and submits it with the user's password,
+// returning the redirect back to the relying party.
+func (suite *OIDCFlowSuite) signIn(query url.Values) *url.URL {
+	res, err := suite.client.Get(suite.server.URL + domain.OIDCAuthorizationPath + "?" + query.Encode())
+	suite.Require().NoError(err)
+	page, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode, string(page))
+
+	form := url.Values{}
+	for _, field := range hiddenInput.FindAllStringSubmatch(string(page), -1) {
+		form.Set(field[1], html.UnescapeString(field[2]))
+	}
+	form.Set("email", flowEmail)
+	form.Set("password", flowPassword)
+	res, err = suite.client.PostForm(suite.server.URL+domain.OIDCAuthorizationPath, form)
+	suite.Require().NoError(err)
+	res.Body.Close()
+	suite.Require().Equal(http.StatusSeeOther, res.StatusCode)
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	suite.Require().NoError(err)
+	return location
+}
+
+func (suite *OIDCFlowSuite) exchange(code, verifier string, out any) int {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {flowRedirectURI},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, suite.server.URL+domain.OIDCTokenPath, strings.NewReader(form.Encode()))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(suite.clientID), url.QueryEscape(suite.clientSecret))
+	return suite.do(req, out)
+}
+
+// verificationKeys reads the provider's JWKS, as a relying party would
+// from the discovery document.
+func (suite *OIDCFlowSuite) verificationKeys(jwksURI string) map[string]*rsa.PublicKey {
+	var jwks struct {
+		Keys []struct {
+			KeyID string `json:"kid"`
+			N     string `json:"n"`
+			E     string `json:"e"`
+		} `json:"keys"`
+	}
+	res, err := suite.client.Get(jwksURI)
+	suite.Require().NoError(err)
+	defer res.Body.Close()
+	suite.Require().NoError(json.NewDecoder(res.Body).Decode(&jwks))
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range jwks.Keys {
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		suite.Require().NoError(err)
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		suite.Require().NoError(err)
+		keys[key.KeyID] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys
+}
+
+func (suite *OIDCFlowSuite) TestAuthorizationCodeFlow() {
+	var metadata struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserInfoEndpoint      string `json:"userinfo_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.get(domain.OIDCDiscoveryPath, "", &metadata))
+	suite.Equal(suite.server.URL, metadata.Issuer)
+	suite.Equal(suite.server.URL+domain.OIDCAuthorizationPath, metadata.AuthorizationEndpoint)
+	suite.Equal(suite.server.URL+domain.OIDCTokenPath, metadata.TokenEndpoint)
+	suite.Equal(suite.server.URL+domain.OIDCUserInfoPath, metadata.UserInfoEndpoint)
+
+	query, verifier := suite.authorizeQuery(nil)
+	location := suite.signIn(query)
+	suite.Equal("rp.example.com", location.Host)
+	suite.Equal("/callback", location.Path)
+	suite.Equal("af0ifjsldkj", location.Query().Get("state"))
+	code := location.Query().Get("code")
+	suite.Require().NotEmpty(code)
+
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		IDToken     string `json:"id_token"`
+		Scope       string `json:"scope"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.exchange(code, verifier, &tokens))
+	suite.Equal("Bearer", tokens.TokenType)
+	suite.Equal("openid profile email", tokens.Scope)
+
+	keys := suite.verificationKeys(metadata.JWKSURI)
+	claims := jwt.MapClaims{}
+	idToken, err := jwt.ParseWithClaims(tokens.IDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		kid, _ := token.Header["kid"].(string)
+		return keys[kid], nil
+	})
+	suite.Require().NoError(err)
+	suite.True(idToken.Valid)
+	suite.Equal(metadata.Issuer, claims["iss"])
+	suite.Equal(suite.clientID, claims["aud"])
+	suite.Equal("n-0S6_WzA2Mj", claims["nonce"])
+	suite.Equal(flowEmail, claims["email"])
+	suite.Equal("ada", claims["preferred_username"])
+	subject, _ := claims["sub"].(string)
+	suite.NotEmpty(subject)
+
+	var info map[string]any
+	suite.Require().Equal(http.StatusOK, suite.get(domain.OIDCUserInfoPath, tokens.AccessToken, &info))
+	suite.Equal(subject, info["sub"])
+	suite.Equal(flowEmail, info["email"])
+	suite.Equal(false, info["email_verified"])
+
+	// A code is good for one exchange only.
+	var replay struct {
+		Error string `json:"error"`
+	}
+	suite.Equal(http.StatusBadRequest, suite.exchange(code, verifier, &replay))
+	suite.Equal(domain.OIDCInvalidGrant, replay.Error)
+
+	// Tokens issued to a relying party do not open the task_manager API,
+	// and task_manager tokens do not open userinfo.
+	suite.Equal(http.StatusUnauthorized, suite.get("/tasks/", tokens.AccessToken, nil))
+	suite.Equal(http.StatusUnauthorized, suite.get("/tasks/", tokens.IDToken, nil))
+	suite.Equal(http.StatusUnauthorized, suite.get(domain.OIDCUserInfoPath, suite.apiToken, nil))
+	suite.Equal(http.StatusUnauthorized, suite.get(domain.OIDCUserInfoPath, tokens.IDToken, nil))
+}
+
+func (suite *OIDCFlowSuite) TestWrongVerifierIsRejected() {
+	query, _ := suite.authorizeQuery(nil)
+	code := suite.signIn(query).Query().Get("code")
+
+	var failure struct {
+		Error string `json:"error"`
+	}
+	suite.Equal(http.StatusBadRequest, suite.exchange(code, strings.Repeat("x", 43), &failure))
+	suite.Equal(domain.OIDCInvalidGrant, failure.Error)
+}
+
+func (suite *OIDCFlowSuite) TestUnregisteredRedirectURIIsNotFollowed() {
+	query, _ := suite.authorizeQuery(url.Values{"redirect_uri": {"https://attacker.example.com/callback"}})
+	res, err := suite.client.Get(suite.server.URL + domain.OIDCAuthorizationPath + "?" + query.Encode())
+	suite.Require().NoError(err)
+	res.Body.Close()
+	suite.Equal(http.StatusBadRequest, res.StatusCode)
+	suite.Empty(res.Header.Get("Location"))
+}
+
+func (suite *OIDCFlowSuite) TestPromptNoneRedirectsWithLoginRequired() {
+	query, _ := suite.authorizeQuery(url.Values{"prompt": {"none"}})
+	res, err := suite.client.Get(suite.server.URL + domain.OIDCAuthorizationPath + "?" + query.Encode())
+	suite.Require().NoError(err)
+	res.Body.Close()
+	suite.Require().Equal(http.StatusFound, res.StatusCode)
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	suite.Require().NoError(err)
+	suite.Equal(domain.OIDCLoginRequired, location.Query().Get("error"))
+	suite.Equal("af0ifjsldkj", location.Query().Get("state"))
+}
+
+func (suite *OIDCFlowSuite) TestDeletedClientTokensStopWorking() {
+	query, verifier := suite.authorizeQuery(nil)
+	code := suite.signIn(query).Query().Get("code")
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.exchange(code, verifier, &tokens))
+
+	req, err := http.NewRequest(http.MethodDelete, suite.server.URL+"/oauth/clients/"+suite.clientID, nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+suite.apiToken)
+	suite.Require().Equal(http.StatusOK, suite.do(req, nil))
+
+	suite.Equal(http.StatusUnauthorized, suite.get(domain.OIDCUserInfoPath, tokens.AccessToken, nil))
+}
+
+func TestOIDCFlowSuite(t *testing.T) {
+	suite.Run(t, new(OIDCFlowSuite))
+}
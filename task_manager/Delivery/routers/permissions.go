@@ -0,0 +1,63 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	controller "task_manager/Delivery/controller"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission lets a request through only if the authenticated
+// user's role grants every one of permissions. Requests made with a
+// personal access token only get the permissions that are also among its
+// scopes. It must run after AuthMiddleware. The resulting permissions are
+// stored in the context for handlers that make finer-grained decisions.
+func RequirePermission(roles domain.RoleUsecases, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Value(infrastructure.UserContextKey).(*domain.User)
+		if !ok || user == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not found in context. AuthMiddleware must be called first"})
+			return
+		}
+
+		granted, err := roles.GetPermissions(c, user.Role)
+		if err != nil && !errors.Is(err, domain.ErrRoleNotFound) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve permissions"})
+			return
+		}
+		if token, ok := c.Value(infrastructure.PersonalAccessTokenContextKey).(*domain.PersonalAccessToken); ok {
+			granted = slices.DeleteFunc(slices.Clone(granted), func(permission string) bool {
+				return !slices.Contains(token.Scopes, permission)
+			})
+		}
+		for _, permission := range permissions {
+			// A role that no longer exists grants nothing.
+			if !slices.Contains(granted, permission) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Missing permission: " + permission})
+				return
+			}
+		}
+
+		c.Set(controller.PermissionsContextKey, granted)
+		c.Next()
+	}
+}
+
+// RequireSession turns away requests made with a personal access token.
+// It guards account security endpoints, such as managing tokens or
+// two-factor authentication, so that a leaked token cannot be used to mint
+// broader ones or lock its owner out. It must run after AuthMiddleware.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Value(infrastructure.PersonalAccessTokenContextKey).(*domain.PersonalAccessToken); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Personal access tokens cannot be used here; log in instead"})
+			return
+		}
+		c.Next()
+	}
+}
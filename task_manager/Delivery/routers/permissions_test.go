@@ -0,0 +1,117 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controller "task_manager/Delivery/controller"
+	router "task_manager/Delivery/routers"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+	"task_manager/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPermissionTestEngine(roles domain.RoleUsecases, user *domain.User, permissions ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", func(c *gin.Context) {
+		if user != nil {
+			c.Set(infrastructure.UserContextKey, user)
+		}
+	}, router.RequirePermission(roles, permissions...), func(c *gin.Context) {
+		granted, _ := c.Get(controller.PermissionsContextKey)
+		c.JSON(http.StatusOK, gin.H{"permissions": granted})
+	})
+	return engine
+}
+
+func serve(engine *gin.Engine) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	engine.ServeHTTP(res, req)
+	return res
+}
+
+func TestRequirePermission_Granted(t *testing.T) {
+	roles := new(mocks.RoleUsecases)
+	roles.On("GetPermissions", mock.Anything, "editor").Return([]string{domain.PermTaskRead, domain.PermTaskUpdate}, nil)
+
+	res := serve(newPermissionTestEngine(roles, &domain.User{ID: "u1", Role: "editor"}, domain.PermTaskRead, domain.PermTaskUpdate))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), domain.PermTaskUpdate)
+}
+
+func TestRequirePermission_Missing(t *testing.T) {
+	roles := new(mocks.RoleUsecases)
+	roles.On("GetPermissions", mock.Anything, "user").Return([]string{domain.PermTaskRead}, nil)
+
+	res := serve(newPermissionTestEngine(roles, &domain.User{ID: "u1", Role: "user"}, domain.PermTaskRead, domain.PermTaskDelete))
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	assert.Contains(t, res.Body.String(), domain.PermTaskDelete)
+}
+
+func TestRequirePermission_UnknownRole(t *testing.T) {
+	roles := new(mocks.RoleUsecases)
+	roles.On("GetPermissions", mock.Anything, "deleted").Return(nil, domain.ErrRoleNotFound)
+
+	res := serve(newPermissionTestEngine(roles, &domain.User{ID: "u1", Role: "deleted"}, domain.PermTaskRead))
+	assert.Equal(t, http.StatusForbidden, res.Code)
+}
+
+func TestRequirePermission_Unauthenticated(t *testing.T) {
+	res := serve(newPermissionTestEngine(new(mocks.RoleUsecases), nil, domain.PermTaskRead))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
+
+func TestRequirePermission_PersonalAccessTokenScopes(t *testing.T) {
+	roles := new(mocks.RoleUsecases)
+	roles.On("GetPermissions", mock.Anything, "editor").Return([]string{domain.PermTaskRead, domain.PermTaskUpdate}, nil)
+	token := &domain.PersonalAccessToken{ID: "pat1", Scopes: []string{domain.PermTaskRead, domain.PermTaskDelete}}
+
+	engine := func(permissions ...string) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		engine := gin.New()
+		engine.GET("/", func(c *gin.Context) {
+			c.Set(infrastructure.UserContextKey, &domain.User{ID: "u1", Role: "editor"})
+			c.Set(infrastructure.PersonalAccessTokenContextKey, token)
+		}, router.RequirePermission(roles, permissions...), func(c *gin.Context) {
+			granted, _ := c.Get(controller.PermissionsContextKey)
+			c.JSON(http.StatusOK, gin.H{"permissions": granted})
+		})
+		return engine
+	}
+
+	// Granted by both the role and the token's scopes.
+	res := serve(engine(domain.PermTaskRead))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"permissions":["task:read"]}`, res.Body.String())
+	// Granted by the role only.
+	assert.Equal(t, http.StatusForbidden, serve(engine(domain.PermTaskUpdate)).Code)
+	// In scope, but the role does not grant it.
+	assert.Equal(t, http.StatusForbidden, serve(engine(domain.PermTaskDelete)).Code)
+}
+
+func TestRequireSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", func(c *gin.Context) {
+		if c.GetHeader("X-Test-Token") != "" {
+			c.Set(infrastructure.PersonalAccessTokenContextKey, &domain.PersonalAccessToken{ID: "pat1"})
+		}
+	}, router.RequireSession(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusOK, serve(engine).Code)
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Test-Token", "1")
+	engine.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+}
@@ -2,40 +2,130 @@ package router
 
 import (
 	controller "task_manager/Delivery/controller"
+	domain "task_manager/Domain"
 	infrastructure "task_manager/Infrastructure"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(engine *gin.Engine, ctrl *controller.Controller)  {
+func SetupRouter(engine *gin.Engine, ctrl *controller.Controller, tokens domain.TokenUsecases, personalTokens domain.PersonalAccessTokenUsecases, roles domain.RoleUsecases, unverified UnverifiedAccess)  {
+	// Every request gets an ID, which the audit log records with the
+	// client IP
+	engine.Use(infrastructure.RequestInfoMiddleware())
+
 	public := engine.Group("")
 
 	// Public routes (no authentication required)
 	public.POST("/register", ctrl.Register)
 	public.POST("/login", ctrl.Login)
+	public.POST("/login/2fa", ctrl.CompleteTwoFactorLogin)
+	public.POST("/login/2fa/setup", ctrl.SetupTwoFactorLogin)
+	public.POST("/token/refresh", ctrl.RefreshToken)
+	public.POST("/password/forgot", ctrl.ForgotPassword)
+	public.POST("/password/reset", ctrl.ResetPassword)
+	public.GET("/verify", ctrl.VerifyEmail)
+	public.GET("/.well-known/jwks.json", ctrl.JWKS)
+
+	// OpenID Connect provider; the userinfo endpoint takes the tokens it
+	// issues, not task_manager tokens
+	public.GET(domain.OIDCDiscoveryPath, ctrl.OpenIDConfiguration)
+	public.GET(domain.OIDCAuthorizationPath, ctrl.Authorize)
+	public.POST(domain.OIDCAuthorizationPath, ctrl.AuthorizeSignIn)
+	public.POST(domain.OIDCTokenPath, ctrl.Token)
+	public.GET(domain.OIDCUserInfoPath, ctrl.UserInfo)
+	public.POST(domain.OIDCUserInfoPath, ctrl.UserInfo)
 
 	//Protected route
 	protected := engine.Group("")
 	// Attache the AuthMiddleware 
-	protected.Use(infrastructure.AuthMiddleware())
+	protected.Use(infrastructure.AuthMiddleware(tokens, personalTokens))
 
-	//  Admin-only routes
-	admin := protected.Group("")
-	admin.Use(infrastructure.AdminMiddleware())
+	// Account security; not available to personal access tokens
+	session := protected.Group("", RequireSession())
+	session.POST("/logout", ctrl.Logout)
+	session.POST("/verify/resend", ctrl.ResendVerification)
+	session.POST("/2fa/enroll", ctrl.EnrollTwoFactor)
+	session.POST("/2fa/confirm", ctrl.ConfirmTwoFactor)
+	session.POST("/2fa/disable", ctrl.DisableTwoFactor)
+	session.POST("/2fa/recovery-codes", ctrl.RegenerateRecoveryCodes)
+	session.GET("/tokens", ctrl.ListPersonalAccessTokens)
+	session.POST("/tokens", ctrl.CreatePersonalAccessToken)
+	session.DELETE("/tokens/:id", ctrl.RevokePersonalAccessToken)
 
-	admin.POST("/promote", ctrl.PromoteUser)
-	
-	tasks := admin.Group("/tasks")
-	{
-		tasks.POST("/", ctrl.AddTask)
-		tasks.PUT("/:id", ctrl.UpdatedTask)
-		tasks.DELETE("/:id", ctrl.RemoveTask)
+	can := func(permissions ...string) gin.HandlerFunc {
+		return RequirePermission(roles, permissions...)
 	}
 
-	// Task routes accessible to all authenticated users
-	userTasks := protected.Group("/tasks")	
+	// User and role management
+	protected.POST("/promote", can(domain.PermUserPromote), ctrl.PromoteUser)
+	protected.GET("/users", can(domain.PermUserRead), ctrl.ListUsers)
+	protected.POST("/users/:id/demote", can(domain.PermUserPromote), ctrl.DemoteUser)
+	protected.POST("/users/:id/disable", can(domain.PermUserDisable), ctrl.DisableUser)
+	protected.POST("/users/:id/enable", can(domain.PermUserDisable), ctrl.EnableUser)
+	protected.POST("/users/:id/unlock", can(domain.PermUserUnlock), ctrl.UnlockUser)
+	protected.DELETE("/users/:id", can(domain.PermUserDelete), ctrl.DeleteUser)
+	protected.PUT("/users/:id/role", can(domain.PermRoleAssign), ctrl.AssignRole)
+	protected.GET("/roles", can(domain.PermRoleManage), ctrl.GetRoles)
+	protected.POST("/roles", can(domain.PermRoleManage), ctrl.CreateRole)
+	protected.GET("/roles/two-factor", can(domain.PermRoleManage), ctrl.GetTwoFactorRoles)
+	protected.PUT("/roles/:name/two-factor", can(domain.PermRoleManage), ctrl.SetRoleTwoFactor)
+	protected.GET("/oauth/clients", can(domain.PermClientManage), ctrl.ListOIDCClients)
+	protected.POST("/oauth/clients", can(domain.PermClientManage), ctrl.RegisterOIDCClient)
+	protected.DELETE("/oauth/clients/:id", can(domain.PermClientManage), ctrl.DeleteOIDCClient)
+	protected.GET("/webhooks", can(domain.PermWebhookManage), ctrl.ListWebhooks)
+	protected.POST("/webhooks", can(domain.PermWebhookManage), ctrl.CreateWebhook)
+	protected.GET("/webhooks/:id", can(domain.PermWebhookManage), ctrl.GetWebhook)
+	protected.PATCH("/webhooks/:id", can(domain.PermWebhookManage), ctrl.UpdateWebhook)
+	protected.DELETE("/webhooks/:id", can(domain.PermWebhookManage), ctrl.DeleteWebhook)
+	protected.GET("/webhooks/:id/deliveries", can(domain.PermWebhookManage), ctrl.ListWebhookDeliveries)
+	protected.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", can(domain.PermWebhookManage), ctrl.RedeliverWebhook)
+	protected.GET("/audit", can(domain.PermAuditRead), ctrl.QueryAuditLog)
+	protected.GET("/audit/export", can(domain.PermAuditRead), ctrl.ExportAuditLog)
+	protected.GET("/audit/verify", can(domain.PermAuditRead), ctrl.VerifyAuditLog)
+
+	// Task routes; ownership is enforced by the task usecases
+	reads := RequireVerifiedEmail(unverified, false)
+	writes := RequireVerifiedEmail(unverified, true)
+	tasks := protected.Group("/tasks")
 	{
-		userTasks.GET("/", ctrl.GetAllTasks)
-		userTasks.GET("/:id", ctrl.GetTask)
+		tasks.GET("/", reads, can(domain.PermTaskRead), ctrl.GetAllTasks)
+		tasks.GET("/ready", reads, can(domain.PermTaskRead), ctrl.GetReadyTasks)
+		tasks.GET("/:id", reads, can(domain.PermTaskRead), ctrl.GetTask)
+		tasks.POST("/", writes, can(domain.PermTaskCreate), ctrl.AddTask)
+		tasks.PUT("/:id", writes, can(domain.PermTaskUpdate), ctrl.UpdatedTask)
+		tasks.DELETE("/:id", writes, can(domain.PermTaskDelete), ctrl.RemoveTask)
+
+		// Anyone who can see a task can read its comments; writing them takes
+		// comment:write as well
+		tasks.GET("/:id/comments", reads, can(domain.PermTaskRead), ctrl.ListComments)
+		tasks.POST("/:id/comments", writes, can(domain.PermTaskRead, domain.PermCommentWrite), ctrl.AddComment)
+		tasks.PATCH("/:id/comments/:commentId", writes, can(domain.PermTaskRead, domain.PermCommentWrite), ctrl.EditComment)
+		tasks.DELETE("/:id/comments/:commentId", writes, can(domain.PermTaskRead, domain.PermCommentWrite), ctrl.DeleteComment)
+
+		tasks.PUT("/:id/labels/:labelId", writes, can(domain.PermTaskUpdate), ctrl.AddTaskLabel)
+		tasks.DELETE("/:id/labels/:labelId", writes, can(domain.PermTaskUpdate), ctrl.RemoveTaskLabel)
+
+		tasks.GET("/:id/subtasks", reads, can(domain.PermTaskRead), ctrl.GetSubtasks)
+		tasks.POST("/:id/checklist", writes, can(domain.PermTaskUpdate), ctrl.AddChecklistItem)
+		tasks.PATCH("/:id/checklist/:itemId", writes, can(domain.PermTaskUpdate), ctrl.UpdateChecklistItem)
+		tasks.DELETE("/:id/checklist/:itemId", writes, can(domain.PermTaskUpdate), ctrl.RemoveChecklistItem)
+
+		tasks.GET("/:id/blockers", reads, can(domain.PermTaskRead), ctrl.GetTaskBlockers)
+		tasks.GET("/:id/dependents", reads, can(domain.PermTaskRead), ctrl.GetTaskDependents)
+		tasks.PUT("/:id/blockers/:blockerId", writes, can(domain.PermTaskUpdate), ctrl.AddTaskBlocker)
+		tasks.DELETE("/:id/blockers/:blockerId", writes, can(domain.PermTaskUpdate), ctrl.RemoveTaskBlocker)
+
+		tasks.GET("/:id/occurrences", reads, can(domain.PermTaskRead), ctrl.PreviewOccurrences)
 	}
+
+	// The caller's own label catalogue
+	protected.GET("/labels", reads, can(domain.PermTaskRead), ctrl.ListLabels)
+	protected.POST("/labels", writes, can(domain.PermTaskUpdate), ctrl.CreateLabel)
+	protected.PATCH("/labels/:id", writes, can(domain.PermTaskUpdate), ctrl.UpdateLabel)
+	protected.DELETE("/labels/:id", writes, can(domain.PermTaskUpdate), ctrl.DeleteLabel)
+
+	// The caller's own due-date reminders
+	protected.GET("/reminders", reads, can(domain.PermTaskRead), ctrl.ListReminders)
+	protected.POST("/reminders", writes, can(domain.PermTaskUpdate), ctrl.CreateReminder)
+	protected.DELETE("/reminders/:id", writes, can(domain.PermTaskUpdate), ctrl.DeleteReminder)
 }
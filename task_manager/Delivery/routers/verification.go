@@ -0,0 +1,55 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UnverifiedAccess is how much of the task API users who have not verified
+// their email address yet may use.
+type UnverifiedAccess string
+
+const (
+	UnverifiedAccessNone UnverifiedAccess = "none"
+	UnverifiedAccessRead UnverifiedAccess = "read"
+	UnverifiedAccessFull UnverifiedAccess = "full"
+)
+
+// ParseUnverifiedAccess reads an UNVERIFIED_TASK_ACCESS setting; empty
+// means none.
+func ParseUnverifiedAccess(value string) (UnverifiedAccess, error) {
+	switch access := UnverifiedAccess(value); access {
+	case "":
+		return UnverifiedAccessNone, nil
+	case UnverifiedAccessNone, UnverifiedAccessRead, UnverifiedAccessFull:
+		return access, nil
+	default:
+		return "", fmt.Errorf("unverified task access must be none, read or full: %q", value)
+	}
+}
+
+// RequireVerifiedEmail turns away users with an unverified email address
+// unless access allows the route: read routes are open at "read" and
+// above, write routes only at "full". It must run after AuthMiddleware.
+func RequireVerifiedEmail(access UnverifiedAccess, write bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Value(infrastructure.UserContextKey).(*domain.User)
+		if !ok || user == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not found in context. AuthMiddleware must be called first"})
+			return
+		}
+		allowed := !user.EmailUnverified ||
+			access == UnverifiedAccessFull ||
+			(access == UnverifiedAccessRead && !write)
+		if !allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Verify your email address to use this endpoint"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	router "task_manager/Delivery/routers"
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVerificationTestEngine(access router.UnverifiedAccess, write bool, user *domain.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", func(c *gin.Context) {
+		c.Set(infrastructure.UserContextKey, user)
+	}, router.RequireVerifiedEmail(access, write), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return engine
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	unverified := &domain.User{ID: "u1", EmailUnverified: true}
+	verified := &domain.User{ID: "u2"}
+
+	cases := []struct {
+		access router.UnverifiedAccess
+		write  bool
+		user   *domain.User
+		want   int
+	}{
+		{router.UnverifiedAccessNone, false, unverified, http.StatusForbidden},
+		{router.UnverifiedAccessNone, false, verified, http.StatusOK},
+		{router.UnverifiedAccessRead, false, unverified, http.StatusOK},
+		{router.UnverifiedAccessRead, true, unverified, http.StatusForbidden},
+		{router.UnverifiedAccessFull, true, unverified, http.StatusOK},
+		{router.UnverifiedAccessNone, true, verified, http.StatusOK},
+	}
+	for _, tc := range cases {
+		res := serve(newVerificationTestEngine(tc.access, tc.write, tc.user))
+		assert.Equal(t, tc.want, res.Code, "access=%s write=%v unverified=%v", tc.access, tc.write, tc.user.EmailUnverified)
+	}
+}
+
+func TestParseUnverifiedAccess(t *testing.T) {
+	access, err := router.ParseUnverifiedAccess("")
+	require.NoError(t, err)
+	assert.Equal(t, router.UnverifiedAccessNone, access)
+
+	access, err = router.ParseUnverifiedAccess("read")
+	require.NoError(t, err)
+	assert.Equal(t, router.UnverifiedAccessRead, access)
+
+	_, err = router.ParseUnverifiedAccess("some")
+	assert.Error(t, err)
+}
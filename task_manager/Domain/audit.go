@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	AuditCollection = "audit_log"
+	// AuditHeadCollection holds the sequence and hash of the newest audit
+	// entry, which the next one is chained to.
+	AuditHeadCollection = "audit_head"
+)
+
+// Context keys under which the request middleware stores the request ID
+// and client IP, for the audit log to pick up.
+const (
+	RequestIDContextKey = "request_id"
+	ClientIPContextKey  = "client_ip"
+)
+
+// Audited actions.
+const (
+	AuditTaskCreate  = "task.create"
+	AuditTaskUpdate  = "task.update"
+	AuditTaskDelete  = "task.delete"
+	AuditUserCreate  = "user.create"
+	AuditUserPromote = "user.promote"
+	AuditUserDemote  = "user.demote"
+	AuditUserDisable = "user.disable"
+	AuditUserEnable  = "user.enable"
+	AuditUserDelete  = "user.delete"
+	AuditUserUnlock  = "user.unlock"
+	// AuditTaskBlockerAdd and AuditTaskBlockerRemove target the blocked
+	// task.
+	AuditTaskBlockerAdd    = "task.blocker_add"
+	AuditTaskBlockerRemove = "task.blocker_remove"
+	// AuditUserLogin is recorded when a login succeeds, before its tokens
+	// are issued, and
+	// AuditUserLoginFailed when it is refused for a wrong password, a
+	// lockout or a disabled account.
+	AuditUserLogin       = "user.login"
+	AuditUserLoginFailed = "user.login_failed"
+	// Account security.
+	AuditPasswordReset    = "user.password_reset"
+	AuditEmailVerify      = "user.email_verify"
+	AuditTwoFactorEnable  = "user.two_factor_enable"
+	AuditTwoFactorDisable = "user.two_factor_disable"
+	AuditTokenCreate      = "token.create"
+	AuditTokenRevoke      = "token.revoke"
+	// Comments, labels and reminders.
+	AuditCommentCreate  = "comment.create"
+	AuditCommentEdit    = "comment.edit"
+	AuditCommentDelete  = "comment.delete"
+	AuditLabelCreate    = "label.create"
+	AuditLabelUpdate    = "label.update"
+	AuditLabelDelete    = "label.delete"
+	AuditReminderCreate = "reminder.create"
+	AuditReminderDelete = "reminder.delete"
+	// Roles and the admin configuration. AuditRoleAssign targets the user
+	// whose role changed.
+	AuditRoleCreate    = "role.create"
+	AuditRoleAssign    = "role.assign"
+	AuditRoleTwoFactor = "role.two_factor"
+	AuditWebhookCreate = "webhook.create"
+	AuditWebhookUpdate = "webhook.update"
+	AuditWebhookDelete = "webhook.delete"
+	AuditClientCreate  = "oidc_client.create"
+	AuditClientDelete  = "oidc_client.delete"
+)
+
+// Kinds of audit targets.
+const (
+	AuditTargetTask     = "task"
+	AuditTargetComment  = "comment"
+	AuditTargetLabel    = "label"
+	AuditTargetReminder = "reminder"
+	AuditTargetUser     = "user"
+	AuditTargetRole     = "role"
+	AuditTargetToken    = "personal_access_token"
+	AuditTargetWebhook  = "webhook"
+	AuditTargetClient   = "oidc_client"
+)
+
+// AuditEntry records one change: who made it, to what, and how the target
+// looked before and after. Entries are never changed or removed once
+// appended.
+//
+// Entries form a hash chain: each carries the Hash of the one before it in
+// PrevHash, and its own Hash covers every other field. Editing, removing or
+// reordering entries breaks the chain from that point on.
+type AuditEntry struct {
+	ID string
+	// Sequence numbers entries from 1 with no gaps, in the order they were
+	// appended.
+	Sequence int64
+	// OccurredAt is kept to the millisecond, which is what every store can
+	// hold, so that the hash still matches once it is read back.
+	OccurredAt time.Time
+	// ActorID is empty when the actor is not signed in, as in a failed
+	// login; ActorName is then the email they gave.
+	ActorID    string
+	ActorName  string
+	Action     string
+	TargetType string
+	TargetID   string
+	// Before and After are JSON snapshots of the target; Before is empty
+	// for creations and After for deletions. Users are recorded without
+	// their password.
+	Before json.RawMessage
+	After  json.RawMessage
+	// Reason is why a refused action, such as a failed login, was refused.
+	Reason    string
+	IP        string
+	RequestID string
+	PrevHash  string
+	Hash      string
+}
+
+// ComputeHash returns the hex SHA-256 the entry's Hash should be, given its
+// other fields.
+func (e *AuditEntry) ComputeHash() string {
+	// A JSON array of strings keeps the fields apart whatever they contain.
+	fields, _ := json.Marshal([]string{
+		e.ID,
+		strconv.FormatInt(e.Sequence, 10),
+		e.OccurredAt.UTC().Format(time.RFC3339Nano),
+		e.ActorID,
+		e.ActorName,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		string(e.Before),
+		string(e.After),
+		e.Reason,
+		e.IP,
+		e.RequestID,
+		e.PrevHash,
+	})
+	sum := sha256.Sum256(fields)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditQuery selects a page of the audit log, oldest first. Zero values
+// mean "no filter"; From is inclusive and To exclusive. Cursor is the
+// NextCursor of a previous page with the same filters.
+type AuditQuery struct {
+	ActorID    string
+	TargetType string
+	TargetID   string
+	From       time.Time
+	To         time.Time
+	Limit      int
+	Cursor     string
+}
+
+// AuditPage is one page of an AuditQuery; NextCursor is empty on the last
+// page.
+type AuditPage struct {
+	Entries    []*AuditEntry
+	NextCursor string
+}
+
+// AuditVerification is the outcome of checking the audit log's hash chain.
+type AuditVerification struct {
+	// Entries is how many entries were checked.
+	Entries int64
+	Valid   bool
+	// BrokenAt is the sequence of the first entry that does not fit the
+	// chain, and Problem says why; both are empty when Valid.
+	BrokenAt int64
+	Problem  string
+}
+
+type AuditRepository interface {
+	// AppendAuditEntry chains entry to the newest one, setting its
+	// Sequence, PrevHash and Hash, and stores it. Called in a transaction,
+	// it is stored with the change it records or not at all.
+	AppendAuditEntry(c context.Context, entry *AuditEntry) error
+	// GetAuditEntries returns a page of the entries matching query, by
+	// sequence.
+	GetAuditEntries(c context.Context, query AuditQuery) (*AuditPage, error)
+}
+
+type AuditUsecases interface {
+	// QueryAuditLog returns a page of the audit log; it fails with
+	// ErrInvalidAuditQuery for a bad limit or time range.
+	QueryAuditLog(ctx context.Context, query AuditQuery) (*AuditPage, error)
+	// ExportAuditLog calls write with every entry matching query, oldest
+	// first, ignoring its Limit and Cursor. It stops at the first error
+	// write returns.
+	ExportAuditLog(ctx context.Context, query AuditQuery, write func(*AuditEntry) error) error
+	// VerifyAuditLog walks the whole log and checks its hash chain.
+	VerifyAuditLog(ctx context.Context) (*AuditVerification, error)
+}
+
+var (
+	ErrInvalidAuditQuery = errors.New("invalid audit query")
+	// ErrAuditLogBusy is returned when an entry could not be appended
+	// because other appends kept getting in first.
+	ErrAuditLogBusy = errors.New("audit log is busy")
+)
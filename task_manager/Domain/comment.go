@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const CommentCollection = "task_comments"
+
+// MaxCommentLength is the longest comment body accepted, in characters.
+const MaxCommentLength = 10000
+
+// Comment is a remark on a task. A reply names the comment it answers in
+// ParentID; top-level comments leave it empty. Editing keeps every earlier
+// body in Revisions, oldest first. Deleting leaves a tombstone without a
+// body, so that replies keep their place in the thread.
+type Comment struct {
+	ID        string
+	TaskID    string
+	ParentID  string
+	AuthorID  string
+	Body      string
+	CreatedAt time.Time
+	// EditedAt is nil until the comment is first edited.
+	EditedAt  *time.Time
+	Revisions []CommentRevision
+	// DeletedAt is set when the comment is deleted, by its author or by a
+	// moderator (DeletedBy).
+	DeletedAt *time.Time
+	DeletedBy string
+}
+
+// CommentRevision is a body a comment had before an edit, and when that
+// body was written.
+type CommentRevision struct {
+	Body      string
+	WrittenAt time.Time
+}
+
+// Deleted reports whether the comment is a tombstone.
+func (c *Comment) Deleted() bool {
+	return c.DeletedAt != nil
+}
+
+type CommentRepository interface {
+	CreateComment(c context.Context, comment *Comment) error
+	GetCommentByID(c context.Context, commentId string) (*Comment, error)
+	// GetCommentsByTask lists a task's comments oldest first, tombstones
+	// included.
+	GetCommentsByTask(c context.Context, taskId string) ([]*Comment, error)
+	// EditComment replaces the body of a comment that is not deleted and,
+	// in the same write, appends the body it replaces to Revisions. It
+	// fails with ErrCommentNotFound for missing and deleted comments.
+	EditComment(c context.Context, commentId string, body string, editedAt time.Time) (*Comment, error)
+	// DeleteComment turns the comment into a tombstone, clearing its body
+	// and revisions. It fails with ErrCommentNotFound if the comment is
+	// missing or already deleted.
+	DeleteComment(c context.Context, commentId string, deletedBy string, deletedAt time.Time) error
+	// CountCommentsByTask counts the comments that are not deleted on each
+	// of the tasks. Tasks without comments may be left out of the map.
+	CountCommentsByTask(c context.Context, taskIds []string) (map[string]int, error)
+	DeleteCommentsByTask(c context.Context, taskId string) error
+	// DeleteCommentsByTasks deletes the comments of all of the tasks.
+	DeleteCommentsByTasks(c context.Context, taskIds []string) error
+}
+
+// CommentUsecases operate on the comments of tasks the actor can read,
+// under the same ownership rules as the tasks themselves.
+type CommentUsecases interface {
+	// ListComments returns the task's comments oldest first. Tombstones
+	// are only included while they still have replies.
+	ListComments(ctx context.Context, taskId string, actor *Actor) ([]*Comment, error)
+	// AddComment posts a comment, or a reply when parentId is set.
+	AddComment(ctx context.Context, taskId string, parentId string, body string, actor *Actor) (*Comment, error)
+	// EditComment changes the body of one of the actor's own comments.
+	EditComment(ctx context.Context, taskId string, commentId string, body string, actor *Actor) (*Comment, error)
+	// DeleteComment deletes one of the actor's own comments, or anyone's
+	// with PermCommentModerate.
+	DeleteComment(ctx context.Context, taskId string, commentId string, actor *Actor) error
+}
+
+var (
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrCommentAlreadyExists = errors.New("comment already exists")
+	ErrInvalidComment       = errors.New("invalid comment")
+	ErrNotCommentAuthor     = errors.New("only the author can change a comment")
+)
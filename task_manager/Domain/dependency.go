@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const DependencyCollection = "task_dependencies"
+
+// TaskDependency records that TaskID is blocked by BlockerID. Both tasks
+// belong to UserID; dependencies never cross owners.
+type TaskDependency struct {
+	TaskID    string
+	BlockerID string
+	UserID    string
+	CreatedAt time.Time
+}
+
+// ReadyTask is an open task in a user's ready-to-work list. Depth is 0 for
+// a task that can be started now, and otherwise one more than the deepest
+// of its open blockers.
+type ReadyTask struct {
+	*Task
+	Depth int
+}
+
+type DependencyRepository interface {
+	// AddDependency fails with ErrDependencyAlreadyExists if the task is
+	// already blocked by the blocker.
+	AddDependency(c context.Context, dependency *TaskDependency) error
+	// RemoveDependency succeeds whether or not the dependency exists.
+	RemoveDependency(c context.Context, taskId string, blockerId string) error
+	// GetBlockers lists the dependencies of a task on its blockers, and
+	// GetDependents those of other tasks on it, oldest first.
+	GetBlockers(c context.Context, taskId string) ([]*TaskDependency, error)
+	GetDependents(c context.Context, blockerId string) ([]*TaskDependency, error)
+	// GetDependenciesByUser returns a user's whole dependency graph.
+	GetDependenciesByUser(c context.Context, userId string) ([]*TaskDependency, error)
+	// DeleteDependenciesByTask removes every dependency to or from a task.
+	DeleteDependenciesByTask(c context.Context, taskId string) error
+	// DeleteDependenciesByUser removes a user's whole dependency graph.
+	DeleteDependenciesByUser(c context.Context, userId string) error
+}
+
+var (
+	ErrDependencyCycle         = errors.New("dependency cycle")
+	ErrDependencyAlreadyExists = errors.New("dependency already exists")
+	ErrInvalidDependency       = errors.New("invalid dependency")
+	ErrOpenBlockers            = errors.New("task has open blockers")
+)
+
+// DependencyCycleError is returned when a new dependency would close a
+// cycle. Path lists the cycle from the task back to itself, each task
+// blocked by the next, so a task blocked by itself has the path
+// [task, task]. It matches ErrDependencyCycle with errors.Is.
+type DependencyCycleError struct {
+	Path []string
+}
+
+func (e *DependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency cycle: %s", strings.Join(e.Path, " -> "))
+}
+
+func (e *DependencyCycleError) Is(target error) bool {
+	return target == ErrDependencyCycle
+}
+
+// OpenBlockersError is returned when a task cannot start because some of
+// its blockers are still open. It matches ErrOpenBlockers with errors.Is.
+type OpenBlockersError struct {
+	TaskID   string
+	Blockers []string
+}
+
+func (e *OpenBlockersError) Error() string {
+	return fmt.Sprintf("task %q is blocked by open tasks: %s", e.TaskID, strings.Join(e.Blockers, ", "))
+}
+
+func (e *OpenBlockersError) Is(target error) bool {
+	return target == ErrOpenBlockers
+}
@@ -9,6 +9,14 @@ import (
 const (
 	TaskCollection = "tasks"
 	UserCollection = "users"
+	// AdminGuardCollection holds the document that changes taking away
+	// admin rights all write to, so that concurrent ones conflict.
+	AdminGuardCollection = "admin_guard"
+	RefreshTokenCollection = "refresh_tokens"
+	RevokedTokenCollection = "revoked_tokens"
+	RoleCollection = "roles"
+	PasswordResetCollection = "password_resets"
+	LoginAttemptCollection = "login_attempts"
 )
 
 // MODELS
@@ -19,6 +27,28 @@ type Task struct {
 	Description string
 	DueDate     time.Time
 	Status      string 
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	// ParentID is the task this one is a subtask of, owned by the same
+	// user; empty for top-level tasks. It is set when the task is created
+	// and does not change.
+	ParentID string
+	// Checklist is changed with the checklist usecases; UpdateTask leaves
+	// it alone.
+	Checklist []ChecklistItem
+	// LabelIDs are the IDs of labels from the owner's catalogue. They are
+	// changed with AddTaskLabel and RemoveTaskLabel; UpdateTask leaves them
+	// alone.
+	LabelIDs []string
+	// Recurrence is set on the occurrences of a repeating series and nil
+	// for one-off tasks. UpdateTask leaves it alone; series edits change
+	// it with SetTaskRecurrence.
+	Recurrence *Recurrence
+	// CommentCount is filled in by the task usecases from the comment
+	// repository; it is not stored with the task.
+	CommentCount int `bson:"-"`
+	// Progress is likewise computed by the task usecases.
+	Progress TaskProgress `bson:"-"`
 }
 
 type User struct {
@@ -27,33 +57,286 @@ type User struct {
 	Email    string
 	Password string
 	Role   string
+	// TokenVersion is embedded in every token issued to the user. Bumping
+	// it, as role changes do, invalidates all of the user's existing tokens.
+	TokenVersion int
+	// Disabled accounts cannot log in or use tokens issued before.
+	Disabled bool
+	// EmailUnverified is set on new accounts until they follow the link in
+	// the verification email. Accounts created before verification existed
+	// have the zero value and count as verified.
+	EmailUnverified bool
+}
+
+// UserQuery selects a page of users ordered by username. Search matches a
+// case-insensitive substring of the username or email.
+type UserQuery struct {
+	Search string
+	Limit  int
+	Cursor string
+}
+
+// UserPage is one page of users; NextCursor is empty on the last page.
+type UserPage struct {
+	Users      []*User
+	NextCursor string
+}
+
+// Actor is the authenticated user a task operation is performed for, with
+// the permissions their role grants. AdminOverride is an explicit request to
+// act on a task the user does not own; without it even holders of the ":any"
+// permissions are held to the same ownership rules as everyone.
+type Actor struct {
+	User          *User
+	Permissions   []string
+	AdminOverride bool
+}
+
+// TokenPair is what signing in or refreshing returns: a short-lived access
+// JWT and an opaque, single-use refresh token.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// AccessClaims are the verified contents of an access token. TokenID is the
+// JWT "jti" and keys the revocation list.
+type AccessClaims struct {
+	TokenID  string
+	UserID   string
+	Username string
+	Email    string
+	Role     string
+	// TokenVersion is the user's TokenVersion when the token was issued.
+	TokenVersion int
+	ExpiresAt    time.Time
+	// EmailUnverified is not part of the token; Authenticate fills it in
+	// from the user's current state.
+	EmailUnverified bool
+}
+
+// VerificationClaims are the verified contents of an email verification
+// token. The token is only good for the address it was mailed to.
+type VerificationClaims struct {
+	UserID    string
+	Email     string
+	ExpiresAt time.Time
+}
+
+// RefreshToken is the stored record of an issued refresh token; only the
+// SHA-256 hash of the token itself is kept. Every token obtained by rotating
+// another shares its FamilyID, so a replayed token can revoke the whole
+// chain. UsedAt is set when the token is rotated.
+type RefreshToken struct {
+	ID        string
+	FamilyID  string
+	UserID    string
+	TokenHash string
+	// TokenVersion is the user's TokenVersion when the token was issued.
+	TokenVersion int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// PasswordResetToken is the stored record of a password reset link. Like
+// refresh tokens only the SHA-256 hash is kept, and a token works once.
+type PasswordResetToken struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// MailMessage is a plain-text email to a single recipient.
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Fields a task listing can be sorted on.
+const (
+	TaskSortID          = "id"
+	TaskSortUserID      = "user_id"
+	TaskSortTitle       = "title"
+	TaskSortDescription = "description"
+	TaskSortDueDate     = "due_date"
+	TaskSortStatus      = "status"
+)
+
+var TaskSortFields = []string{
+	TaskSortID, TaskSortUserID, TaskSortTitle, TaskSortDescription, TaskSortDueDate, TaskSortStatus,
+}
+
+// TaskQuery selects a page of a user's tasks. Zero values mean "no filter".
+// DueFrom is inclusive and DueTo is exclusive. Cursor is the opaque
+// NextCursor of a previous page and must be used with the same sort.
+//
+// Labels matches tasks carrying any of the labels, or all of them with
+// MatchAllLabels. Repositories take label IDs; GetAllTasks also accepts
+// names from the owner's catalogue and resolves them.
+type TaskQuery struct {
+	UserID         string
+	Status         string
+	DueFrom        time.Time
+	DueTo          time.Time
+	Labels         []string
+	MatchAllLabels bool
+	SortBy         string
+	SortDesc       bool
+	Limit          int
+	Cursor         string
+}
+
+// TaskPage is one page of a TaskQuery. NextCursor is empty on the last page.
+type TaskPage struct {
+	Tasks      []*Task
+	NextCursor string
 }
 
 // REPOSITORIES
 type TaskRepository interface {
-	GetAllTasks(c context.Context, userId string) ([]*Task, error)
+	GetAllTasks(c context.Context, query TaskQuery) (*TaskPage, error)
 	GetTaskByID(c context.Context, taskId string) (*Task, error)
 	CreateTask(c context.Context, task *Task) error
+	// UpdateTask stores the task's own fields. Its parent, checklist,
+	// labels and recurrence are only changed by the methods dedicated to
+	// them.
 	UpdateTask(c context.Context, taskId string, task *Task) (*Task, error)
 	DeleteTask(c context.Context, taskId string) error
+	DeleteTasksByUser(c context.Context, userId string) error
+	// AddTaskLabel attaches a label to a task; attaching it twice is not an
+	// error. RemoveTaskLabel detaches it, and likewise succeeds if it was
+	// not attached. Both fail with ErrTaskNotFound for a missing task.
+	AddTaskLabel(c context.Context, taskId string, labelId string) error
+	RemoveTaskLabel(c context.Context, taskId string, labelId string) error
+	// RemoveLabelFromTasks detaches a label from all of a user's tasks.
+	RemoveLabelFromTasks(c context.Context, userId string, labelId string) error
+	// GetSubtasks lists the direct subtasks of a task by due date.
+	GetSubtasks(c context.Context, parentId string) ([]*Task, error)
+	// CountSubtasksByStatus counts the direct subtasks of each parent by
+	// status. Parents without subtasks may be left out of the map.
+	CountSubtasksByStatus(c context.Context, parentIds []string) (map[string]map[string]int, error)
+	// DetachSubtasks makes the direct subtasks of a task top-level tasks.
+	DetachSubtasks(c context.Context, parentId string) error
+	// SetTaskChecklist replaces a task's checklist, failing with
+	// ErrTaskNotFound for a missing task.
+	SetTaskChecklist(c context.Context, taskId string, checklist []ChecklistItem) error
+	// GetTasksBySeries lists the occurrences of a recurring series by
+	// their slot in it.
+	GetTasksBySeries(c context.Context, seriesId string) ([]*Task, error)
+	// SetTaskRecurrence replaces a task's recurrence, failing with
+	// ErrTaskNotFound for a missing task.
+	SetTaskRecurrence(c context.Context, taskId string, recurrence *Recurrence) error
+	// GetTasksDueBetween lists the tasks of every user due from from up to,
+	// but not including, to, by due date.
+	GetTasksDueBetween(c context.Context, from time.Time, to time.Time) ([]*Task, error)
 }
 type UserRepository interface {
-	GetAllUsers(c context.Context, user *User) ([]*User, error)
+	GetAllUsers(c context.Context, query UserQuery) (*UserPage, error)
 	GetUserByID(c context.Context, userId string) (*User, error)
 	GetUserByEmail(c context.Context, email string) (*User, error)
 	GetUserByUsername(c context.Context, username string) (*User, error)
 	CreateUser(c context.Context, user *User) (*User, error)
+	// PromoteUserToAdmin also bumps the user's TokenVersion, in the same
+	// write, so tokens carrying the old role stop working.
 	PromoteUserToAdmin(c context.Context, userId string) error
+	// UpdateUserRole sets the user's role and bumps their TokenVersion.
+	// UpdateUserRole, SetUserDisabled and DeleteUser fail with ErrLastAdmin
+	// rather than leave no active admin, checking and writing atomically.
+	UpdateUserRole(c context.Context, userId string, role string) error
+	// SetUserDisabled disables or re-enables the account and bumps its
+	// TokenVersion.
+	SetUserDisabled(c context.Context, userId string, disabled bool) error
+	DeleteUser(c context.Context, userId string) error
+	// CountActiveAdmins counts users in the admin role that are not disabled.
+	CountActiveAdmins(c context.Context) (int, error)
+	// GetUsersByRole lists the members of a role, by username.
+	GetUsersByRole(c context.Context, role string) ([]*User, error)
+	// BumpTokenVersion bumps the user's TokenVersion, signing them out
+	// everywhere.
+	BumpTokenVersion(c context.Context, userId string) error
+	// MarkEmailVerified clears the user's EmailUnverified flag.
+	MarkEmailVerified(c context.Context, userId string) error
+	// UpdateUserPassword stores a new password hash and bumps the user's
+	// TokenVersion, signing them out everywhere.
+	UpdateUserPassword(c context.Context, userId string, passwordHash string) error
 	UserExists(c context.Context) (bool, error)
 }
 
+type RefreshTokenRepository interface {
+	CreateRefreshToken(c context.Context, token *RefreshToken) error
+	GetRefreshTokenByHash(c context.Context, tokenHash string) (*RefreshToken, error)
+	// MarkRefreshTokenUsed atomically sets UsedAt if it is still unset and
+	// reports whether it did, so concurrent rotations cannot both succeed.
+	MarkRefreshTokenUsed(c context.Context, tokenId string, usedAt time.Time) (bool, error)
+	RevokeRefreshTokenFamily(c context.Context, familyId string, revokedAt time.Time) error
+}
+
+// RevokedTokenRepository is the access-token revocation list, keyed by jti.
+// Entries only need to outlive the token they revoke.
+type RevokedTokenRepository interface {
+	RevokeToken(c context.Context, tokenId string, expiresAt time.Time) error
+	IsTokenRevoked(c context.Context, tokenId string) (bool, error)
+}
+
+type PasswordResetTokenRepository interface {
+	CreatePasswordResetToken(c context.Context, token *PasswordResetToken) error
+	GetPasswordResetTokenByHash(c context.Context, tokenHash string) (*PasswordResetToken, error)
+	// MarkPasswordResetTokenUsed atomically sets UsedAt if it is still unset
+	// and reports whether it did.
+	MarkPasswordResetTokenUsed(c context.Context, tokenId string, usedAt time.Time) (bool, error)
+	// DeletePasswordResetTokensByUser drops every outstanding token of a user.
+	DeletePasswordResetTokensByUser(c context.Context, userId string) error
+}
+
+// RoleRepository stores custom roles; built-in roles live in code.
+type RoleRepository interface {
+	CreateRole(c context.Context, role *Role) error
+	GetRoleByName(c context.Context, name string) (*Role, error)
+	GetAllRoles(c context.Context) ([]*Role, error)
+}
+
 // USECASES
 type TaskUsecases interface {
-	GetAllTasks(ctx context.Context, userId string) ([]*Task, error)
-	GetTaskByID(ctx context.Context, taskId string) (*Task, error)
+	GetAllTasks(ctx context.Context, query TaskQuery, actor *Actor) (*TaskPage, error)
+	GetTaskByID(ctx context.Context, taskId string, actor *Actor) (*Task, error)
 	CreateTask(ctx context.Context, task *Task, userId string) error
-	UpdateTask(ctx context.Context, taskId string, task *Task) (*Task, error)
-	DeleteTask(ctx context.Context, taskId string) error
+	UpdateTask(ctx context.Context, taskId string, task *Task, actor *Actor) (*Task, error)
+	DeleteTask(ctx context.Context, taskId string, actor *Actor) error
+	// AddTaskLabel attaches one of the task owner's labels to the task,
+	// failing with ErrTooManyLabels past MaxTaskLabels.
+	AddTaskLabel(ctx context.Context, taskId string, labelId string, actor *Actor) (*Task, error)
+	RemoveTaskLabel(ctx context.Context, taskId string, labelId string, actor *Actor) (*Task, error)
+	// GetSubtasks lists the direct subtasks of a task the actor can read.
+	GetSubtasks(ctx context.Context, taskId string, actor *Actor) ([]*Task, error)
+	AddChecklistItem(ctx context.Context, taskId string, text string, actor *Actor) (*Task, error)
+	// UpdateChecklistItem changes an item's text and/or whether it is
+	// done; nil leaves a field as it is.
+	UpdateChecklistItem(ctx context.Context, taskId string, itemId string, text *string, done *bool, actor *Actor) (*Task, error)
+	RemoveChecklistItem(ctx context.Context, taskId string, itemId string, actor *Actor) (*Task, error)
+	// AddTaskBlocker records that the task is blocked by another of its
+	// owner's tasks, failing with a *DependencyCycleError if the blocker
+	// already depends on the task. Adding it twice is not an error.
+	AddTaskBlocker(ctx context.Context, taskId string, blockerId string, actor *Actor) error
+	RemoveTaskBlocker(ctx context.Context, taskId string, blockerId string, actor *Actor) error
+	// GetTaskBlockers lists the tasks blocking a task, and GetTaskDependents
+	// the tasks it blocks.
+	GetTaskBlockers(ctx context.Context, taskId string, actor *Actor) ([]*Task, error)
+	GetTaskDependents(ctx context.Context, taskId string, actor *Actor) ([]*Task, error)
+	// GetReadyTasks lists a user's open tasks in an order work can follow:
+	// every task after its open blockers. An empty userId means the actor.
+	GetReadyTasks(ctx context.Context, userId string, actor *Actor) ([]*ReadyTask, error)
+	// UpdateTaskSeries updates an occurrence of a recurring task and, with
+	// SeriesScopeFollowing or SeriesScopeAll, the rest of its series.
+	UpdateTaskSeries(ctx context.Context, taskId string, task *Task, scope string, actor *Actor) (*Task, error)
+	// PreviewOccurrences lists the upcoming occurrences of a recurring
+	// task's series, failing with ErrNotRecurring for other tasks.
+	PreviewOccurrences(ctx context.Context, taskId string, limit int, actor *Actor) ([]time.Time, error)
 }
 type UserUsecases interface {
 	GetUserByID(ctx context.Context, userId string) (*User, error)
@@ -61,8 +344,64 @@ type UserUsecases interface {
 	GetUserByUsername(ctx context.Context, username string) (*User, error)
 	CreateUser(ctx context.Context, user *User) (*User, error)
 	PromoteUserToAdmin(ctx context.Context, userId string) error
-	Login(ctx context.Context, email, password string) (string, error)
+	// Login refuses with a *LockoutError while the account or clientIP is
+	// locked after repeated failures. Users who need a second factor get a
+	// challenge instead of tokens.
+	Login(ctx context.Context, email, password, clientIP string) (*LoginResult, error)
 	GetCurrentUser(ctx context.Context) (*User, error)
+	ListUsers(ctx context.Context, query UserQuery) (*UserPage, error)
+	// DemoteUser moves an admin back to the user role. Like disabling and
+	// deleting, it fails with ErrLastAdmin for the only active admin.
+	DemoteUser(ctx context.Context, userId string) error
+	SetUserDisabled(ctx context.Context, userId string, disabled bool) error
+	// DeleteUser removes the user and all of their tasks.
+	DeleteUser(ctx context.Context, userId string) error
+	// UnlockUser clears the failed-login count of the user's account.
+	UnlockUser(ctx context.Context, userId string) error
+}
+type PasswordResetUsecases interface {
+	// RequestPasswordReset mails a reset link to the account with this
+	// email. Unknown and disabled accounts are silently ignored so the
+	// endpoint does not reveal which emails are registered.
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword spends a reset token and sets a new password. The user's
+	// existing tokens stop working.
+	ResetPassword(ctx context.Context, resetToken string, newPassword string) error
+}
+type EmailVerificationUsecases interface {
+	// SendVerificationEmail mails a signed verification link to the user.
+	SendVerificationEmail(ctx context.Context, user *User) error
+	// ResendVerificationEmail mails a fresh link to a user who has not
+	// verified yet, or fails with ErrEmailAlreadyVerified.
+	ResendVerificationEmail(ctx context.Context, userId string) error
+	// VerifyEmail checks a verification token and marks the address
+	// verified. Verifying twice is not an error.
+	VerifyEmail(ctx context.Context, verificationToken string) error
+}
+type RoleUsecases interface {
+	// GetRoles lists the built-in roles followed by the custom ones.
+	GetRoles(ctx context.Context) ([]*Role, error)
+	CreateRole(ctx context.Context, role *Role) (*Role, error)
+	// GetPermissions returns what role grants; ErrRoleNotFound if it does
+	// not exist.
+	GetPermissions(ctx context.Context, role string) ([]string, error)
+	// AssignRole moves a user to an existing role, invalidating their tokens.
+	AssignRole(ctx context.Context, userId string, role string) error
+}
+type TokenUsecases interface {
+	IssueTokens(ctx context.Context, user *User) (*TokenPair, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error)
+	// Logout revokes the access token in ctx (set by the auth middleware)
+	// and, when given, the refresh token's whole family.
+	Logout(ctx context.Context, refreshToken string) error
+	// Authenticate verifies an access token, rejecting revoked tokens and
+	// tokens whose TokenVersion is no longer the user's current one.
+	Authenticate(ctx context.Context, accessToken string) (*AccessClaims, error)
+	// ForgetTokenVersion drops the cached TokenVersion of a user, so a bump
+	// made by this process takes effect on the next request.
+	ForgetTokenVersion(userId string)
+	// PublicKeys returns the public keys access tokens are verified with.
+	PublicKeys(ctx context.Context) ([]*JSONWebKey, error)
 }
 
 type IPasswordService interface {
@@ -70,8 +409,24 @@ type IPasswordService interface {
 	VerifyPassword(user *User, password string) bool
 }
 
+// IVerificationTokenService signs email verification tokens, which are
+// stateless: nothing is stored until the address is verified.
+type IVerificationTokenService interface {
+	GenerateVerificationToken(user *User) (string, error)
+	ParseVerificationToken(token string) (*VerificationClaims, error)
+}
+
+// IMailer delivers outgoing email.
+type IMailer interface {
+	Send(ctx context.Context, message *MailMessage) error
+}
+
 type IJWTService interface {
 	GenerateToken(user *User) (string, error)
+	ParseToken(token string) (*AccessClaims, error)
+	// PublicKeys returns the keys access tokens may currently be verified
+	// with, for publishing as a JWKS.
+	PublicKeys(ctx context.Context) ([]*JSONWebKey, error)
 }
 
 var (
@@ -81,4 +436,19 @@ var (
 	ErrUserAlreadyExists = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden = errors.New("forbidden")
+	ErrInvalidToken = errors.New("invalid or expired token")
+	ErrTokenRevoked = errors.New("token has been revoked")
+	ErrTokenReused = errors.New("refresh token reuse detected")
+	ErrTokenNotFound = errors.New("token not found")
+	ErrTokenAlreadyExists = errors.New("token already exists")
+	ErrInvalidTaskQuery = errors.New("invalid task query")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidUserQuery = errors.New("invalid user query")
+	ErrAccountDisabled = errors.New("account is disabled")
+	ErrLastAdmin = errors.New("cannot remove the last active admin")
+	ErrNotAdmin = errors.New("user is not an admin")
+	ErrInvalidMessage = errors.New("invalid mail message")
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
 )
\ No newline at end of file
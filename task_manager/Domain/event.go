@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	OutboxCollection           = "outbox"
+	OutboxCheckpointCollection = "outbox_checkpoints"
+	// OutboxCounterCollection holds the counter that numbers outbox events.
+	OutboxCounterCollection = "outbox_counters"
+)
+
+// Event types published by the task and user usecases.
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	// EventTaskCompleted follows the task.updated event of an update that
+	// completed the task.
+	EventTaskCompleted = "task.completed"
+	EventTaskDeleted   = "task.deleted"
+	EventUserCreated   = "user.created"
+	EventUserPromoted  = "user.promoted"
+	EventUserDeleted   = "user.deleted"
+)
+
+// EventTypes lists every event type that is published.
+var EventTypes = []string{
+	EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted,
+	EventUserCreated, EventUserPromoted, EventUserDeleted,
+}
+
+// Event is a change to a task or user that other systems may react to.
+type Event struct {
+	ID   string
+	Type string
+	// Sequence is the event's position in the outbox, set when it is
+	// appended. Events are numbered in the order their changes committed.
+	Sequence   int64
+	OccurredAt time.Time
+	// Task is set on task events; for task.deleted it is the task as it was
+	// before it was deleted.
+	Task *Task
+	// User is set on user events, without its password.
+	User *User
+}
+
+// EventPublisher hands events to whoever subscribed to them. The task and
+// user usecases publish to the outbox in the same transaction as the
+// change, so a failure to publish undoes the change. The outbox relay in
+// turn publishes each event to its consumers.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+// Transactor runs fn so that the repository calls it makes with the ctx it
+// is given are stored together or not at all. A call made while a
+// transaction is already under way in ctx joins it.
+//
+// fn may be run more than once if the store asks for a transaction to be
+// retried, so it should not have effects outside the store.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// OutboxRepository is the log of published events that the relay hands to
+// consumers.
+type OutboxRepository interface {
+	// AppendEvent stores event after every event already in the outbox and
+	// sets its Sequence. Appended inside a transaction, the event is only
+	// numbered and seen once the transaction commits.
+	AppendEvent(c context.Context, event *Event) error
+	// GetEventsAfter returns up to limit events whose Sequence is above
+	// sequence, in order.
+	GetEventsAfter(c context.Context, sequence int64, limit int) ([]*Event, error)
+	// DeleteEvents removes the events up to and including sequence that
+	// occurred before the given time.
+	DeleteEvents(c context.Context, through int64, before time.Time) error
+}
+
+// OutboxCheckpoint records how far a consumer has got through the outbox.
+// A relay leases the checkpoint while it hands the consumer events, so one
+// relay at a time serves each consumer.
+type OutboxCheckpoint struct {
+	Consumer string
+	// Sequence is that of the last event the consumer handled; 0 before the
+	// first.
+	Sequence   int64
+	LeaseOwner string
+	LeaseUntil time.Time
+	UpdatedAt  time.Time
+}
+
+type OutboxCheckpointRepository interface {
+	// ClaimCheckpoint leases the consumer's checkpoint to owner until
+	// leaseUntil, starting it at 0 the first time. It fails with
+	// ErrOutboxLeaseHeld while another owner's lease has not run out at now.
+	ClaimCheckpoint(c context.Context, consumer string, owner string, now time.Time, leaseUntil time.Time) (*OutboxCheckpoint, error)
+	// AdvanceCheckpoint moves the consumer on to sequence. It fails with
+	// ErrOutboxLeaseLost if owner no longer holds the checkpoint.
+	AdvanceCheckpoint(c context.Context, consumer string, owner string, sequence int64) error
+	// GetCheckpoints lists every consumer's checkpoint.
+	GetCheckpoints(c context.Context) ([]*OutboxCheckpoint, error)
+}
+
+// OutboxRelayConfig tunes the outbox relay.
+type OutboxRelayConfig struct {
+	// PollInterval is how often the relay looks for new events.
+	PollInterval time.Duration
+	// Lease is how long a relay holds a consumer. Another relay takes the
+	// consumer over once a lease runs out without being renewed.
+	Lease time.Duration
+	// Retention is how long events are kept after every consumer has
+	// handled them.
+	Retention time.Duration
+	// BatchSize is how many events are read at once.
+	BatchSize int
+}
+
+var DefaultOutboxRelay = OutboxRelayConfig{
+	PollInterval: time.Second,
+	Lease:        30 * time.Second,
+	Retention:    24 * time.Hour,
+	BatchSize:    100,
+}
+
+// OutboxRelay hands outbox events to its consumers, each in order and once:
+// a consumer's checkpoint is advanced in the same transaction as the writes
+// it makes for the event. Consumers that act outside the store, such as
+// sending a request, may see an event again if the relay dies between the
+// two, and should tell repeats apart by the event ID.
+type OutboxRelay interface {
+	// RelayPending hands every consumer the relay can claim the events it
+	// has not yet handled, then deletes events that every consumer has
+	// handled and are older than the retention.
+	RelayPending(ctx context.Context) error
+	// Run calls RelayPending every PollInterval until ctx is cancelled.
+	Run(ctx context.Context)
+}
+
+var (
+	ErrOutboxLeaseHeld = errors.New("another relay holds this outbox consumer")
+	ErrOutboxLeaseLost = errors.New("outbox consumer lease lost")
+)
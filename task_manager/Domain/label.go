@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const LabelCollection = "labels"
+
+const (
+	// MaxLabelNameLength is the longest label name accepted, in characters.
+	MaxLabelNameLength = 50
+	// MaxTaskLabels is how many labels a single task can carry.
+	MaxTaskLabels = 20
+	// DefaultLabelColor is used for labels created without a colour.
+	DefaultLabelColor = "#808080"
+)
+
+// Label is an entry in a user's label catalogue. Names are unique per user,
+// ignoring case, and Color is a "#rrggbb" hex colour. Tasks refer to labels
+// by ID, so renaming or recolouring a label updates every task carrying it.
+type Label struct {
+	ID        string
+	UserID    string
+	Name      string
+	Color     string
+	CreatedAt time.Time
+}
+
+type LabelRepository interface {
+	// CreateLabel fails with ErrLabelAlreadyExists if the user already has
+	// a label with the same name, ignoring case.
+	CreateLabel(c context.Context, label *Label) error
+	GetLabelByID(c context.Context, labelId string) (*Label, error)
+	// GetLabelsByUser lists a user's catalogue ordered by name.
+	GetLabelsByUser(c context.Context, userId string) ([]*Label, error)
+	// UpdateLabel stores a new name and colour, failing like CreateLabel
+	// when the name is taken.
+	UpdateLabel(c context.Context, label *Label) error
+	DeleteLabel(c context.Context, labelId string) error
+}
+
+// LabelUsecases manage the actor's own label catalogue. Attaching labels
+// to tasks is done through TaskUsecases.
+type LabelUsecases interface {
+	ListLabels(ctx context.Context, actor *Actor) ([]*Label, error)
+	// CreateLabel adds a label; an empty color means DefaultLabelColor.
+	CreateLabel(ctx context.Context, name string, color string, actor *Actor) (*Label, error)
+	// UpdateLabel renames and/or recolours a label; nil leaves a field as
+	// it is.
+	UpdateLabel(ctx context.Context, labelId string, name *string, color *string, actor *Actor) (*Label, error)
+	// DeleteLabel removes the label from the catalogue and from every task
+	// carrying it.
+	DeleteLabel(ctx context.Context, labelId string, actor *Actor) error
+}
+
+var (
+	ErrLabelNotFound      = errors.New("label not found")
+	ErrLabelAlreadyExists = errors.New("a label with this name already exists")
+	ErrInvalidLabel       = errors.New("invalid label")
+	ErrTooManyLabels      = errors.New("task has too many labels")
+)
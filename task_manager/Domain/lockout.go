@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LoginAttempts counts recent failed logins for one key: an account or a
+// client IP. The count starts over once ExpiresAt passes without another
+// failure.
+type LoginAttempts struct {
+	Key         string
+	Failures    int
+	LastFailure time.Time
+	// PreviousFailure is what LastFailure was before the latest failure,
+	// or zero if that failure started the count.
+	PreviousFailure time.Time
+	ExpiresAt       time.Time
+}
+
+// LoginAttemptRepository stores failed-login counters.
+type LoginAttemptRepository interface {
+	// GetLoginAttempts returns the counter for key, or nil if there have
+	// been no recent failures.
+	GetLoginAttempts(c context.Context, key string) (*LoginAttempts, error)
+	// RecordLoginFailure atomically counts a failure at time at, starting a
+	// new count if the previous one has expired, and keeps the counter
+	// until at+window. It returns the updated counter.
+	RecordLoginFailure(c context.Context, key string, at time.Time, window time.Duration) (*LoginAttempts, error)
+	// ReleaseLoginAttempt takes back a failure counted by
+	// RecordLoginFailure, which returned recorded, for an attempt that did
+	// not fail after all. If nothing was counted since, the counter is
+	// restored to what it was; otherwise only the count goes down.
+	ReleaseLoginAttempt(c context.Context, key string, recorded *LoginAttempts) error
+	ResetLoginAttempts(c context.Context, key string) error
+}
+
+// LockoutPolicy decides how long a key is locked after repeated failures.
+// The first Threshold failures are free; each one after that locks the key
+// for BaseDelay, doubled for every further failure and capped at MaxDelay.
+// Failures are forgotten after Window without another one, which should be
+// longer than MaxDelay.
+type LockoutPolicy struct {
+	Threshold int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Window    time.Duration
+}
+
+// Default policies. Accounts lock quickly; an IP gets more room because
+// many users may share one address.
+var (
+	DefaultAccountLockout = LockoutPolicy{Threshold: 5, BaseDelay: 30 * time.Second, MaxDelay: 15 * time.Minute, Window: time.Hour}
+	DefaultIPLockout      = LockoutPolicy{Threshold: 20, BaseDelay: 30 * time.Second, MaxDelay: 15 * time.Minute, Window: time.Hour}
+)
+
+// LockedUntil is when attempts may next be made for the counter; the zero
+// time if it is not locked.
+func (p LockoutPolicy) LockedUntil(attempts *LoginAttempts) time.Time {
+	if attempts == nil || attempts.Failures < p.Threshold {
+		return time.Time{}
+	}
+	delay := p.BaseDelay
+	for i := p.Threshold; i < attempts.Failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return attempts.LastFailure.Add(delay)
+}
+
+var (
+	ErrAccountLocked        = errors.New("account is temporarily locked")
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+)
+
+// LockoutError reports a refused login. Err is ErrAccountLocked when the
+// account is locked and ErrTooManyLoginAttempts when the client's IP is.
+type LockoutError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%v; retry after %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockoutError) Unwrap() error {
+	return e.Err
+}
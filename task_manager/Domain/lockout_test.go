@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockoutPolicy_LockedUntil(t *testing.T) {
+	policy := domain.LockoutPolicy{Threshold: 3, BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute, Window: time.Hour}
+	last := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, policy.LockedUntil(nil).IsZero())
+	assert.True(t, policy.LockedUntil(&domain.LoginAttempts{Failures: 2, LastFailure: last}).IsZero())
+
+	cases := map[int]time.Duration{
+		3:  30 * time.Second,
+		4:  time.Minute,
+		5:  2 * time.Minute,
+		6:  4 * time.Minute,
+		7:  5 * time.Minute,
+		50: 5 * time.Minute,
+	}
+	for failures, delay := range cases {
+		until := policy.LockedUntil(&domain.LoginAttempts{Failures: failures, LastFailure: last})
+		assert.Equal(t, last.Add(delay), until, "failures=%d", failures)
+	}
+}
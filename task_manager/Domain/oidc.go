@@ -0,0 +1,259 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	OIDCClientCollection        = "oidc_clients"
+	AuthorizationCodeCollection = "oidc_authorization_codes"
+)
+
+// Scopes a relying party may request. openid is required; profile releases
+// the username and email the address.
+const (
+	OIDCScopeOpenID  = "openid"
+	OIDCScopeProfile = "profile"
+	OIDCScopeEmail   = "email"
+)
+
+var OIDCScopes = []string{OIDCScopeOpenID, OIDCScopeProfile, OIDCScopeEmail}
+
+// Where the provider's endpoints are mounted, relative to the issuer.
+const (
+	OIDCDiscoveryPath     = "/.well-known/openid-configuration"
+	OIDCJWKSPath          = "/.well-known/jwks.json"
+	OIDCAuthorizationPath = "/oauth/authorize"
+	OIDCTokenPath         = "/oauth/token"
+	OIDCUserInfoPath      = "/oauth/userinfo"
+)
+
+// OIDCClient is an application registered to sign users in with their
+// task_manager accounts. Confidential clients authenticate to the token
+// endpoint with a secret, of which only the SHA-256 hash is kept; public
+// clients, such as single-page apps, have none and rely on PKCE alone.
+type OIDCClient struct {
+	ID           string
+	Name         string
+	SecretHash   string
+	RedirectURIs []string
+	CreatedAt    time.Time
+}
+
+func (c *OIDCClient) Public() bool {
+	return c.SecretHash == ""
+}
+
+// NewOIDCClient is what registering a client returns. Secret is empty for
+// public clients and is never available again.
+type NewOIDCClient struct {
+	Secret string
+	Client *OIDCClient
+}
+
+// AuthorizationCode is the stored record of a code handed to a client's
+// redirect URI; like other opaque tokens only its hash is kept. It remembers
+// the request it answers so the token endpoint can check that the same
+// client, redirect URI and PKCE verifier come back with it.
+type AuthorizationCode struct {
+	ID            string
+	CodeHash      string
+	ClientID      string
+	UserID        string
+	RedirectURI   string
+	Scopes        []string
+	Nonce         string
+	CodeChallenge string
+	// AuthTime is when the user entered their password.
+	AuthTime  time.Time
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// AuthorizationRequest is an authorization endpoint request in OAuth 2.0
+// terms. Only the code response type and S256 code challenges are
+// supported.
+type AuthorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Prompt              string
+}
+
+// Redirect returns the request's redirect URI with params, and the state
+// if the client sent one, added to its query.
+func (r *AuthorizationRequest) Redirect(params url.Values) string {
+	target, err := url.Parse(r.RedirectURI)
+	if err != nil {
+		return r.RedirectURI
+	}
+	query := target.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if r.State != "" {
+		query.Set("state", r.State)
+	}
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// Authorization is the outcome of a sign-in at the authorization endpoint:
+// either the redirect URI to send the browser back to, carrying the code,
+// or a challenge for the user's second factor.
+type Authorization struct {
+	RedirectURI string
+	Challenge   *TwoFactorChallenge
+}
+
+// TokenRequest is a token endpoint request. The client's credentials come
+// from HTTP Basic authentication or the request body.
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	ClientID     string
+	ClientSecret string
+}
+
+// OIDCTokens is what the token endpoint returns.
+type OIDCTokens struct {
+	AccessToken string
+	IDToken     string
+	ExpiresIn   time.Duration
+	Scopes      []string
+}
+
+// OIDCUserInfo is what a relying party learns about a user, limited to the
+// granted scopes: Username is only set with profile, Email and
+// EmailVerified only with email.
+type OIDCUserInfo struct {
+	Subject       string
+	Username      string
+	Email         string
+	EmailVerified *bool
+}
+
+// OIDCAccessClaims are the verified contents of an access token issued to
+// a relying party for the userinfo endpoint.
+type OIDCAccessClaims struct {
+	TokenID      string
+	UserID       string
+	ClientID     string
+	Scopes       []string
+	TokenVersion int
+	ExpiresAt    time.Time
+}
+
+// OIDCProviderMetadata is the discovery document.
+type OIDCProviderMetadata struct {
+	Issuer                            string
+	AuthorizationEndpoint             string
+	TokenEndpoint                     string
+	UserInfoEndpoint                  string
+	JWKSURI                           string
+	ScopesSupported                   []string
+	ResponseTypesSupported            []string
+	GrantTypesSupported               []string
+	SubjectTypesSupported             []string
+	IDTokenSigningAlgValuesSupported  []string
+	TokenEndpointAuthMethodsSupported []string
+	CodeChallengeMethodsSupported     []string
+	ClaimsSupported                   []string
+}
+
+// OIDCError is an error the protocol defines, reported to the client as
+// error and error_description. At the authorization endpoint it is sent to
+// the redirect URI; anything else there is shown to the user instead, as
+// the redirect URI cannot be trusted.
+type OIDCError struct {
+	Code        string
+	Description string
+}
+
+func (e *OIDCError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// Error codes of RFC 6749 and OpenID Connect Core.
+const (
+	OIDCInvalidRequest          = "invalid_request"
+	OIDCInvalidClient           = "invalid_client"
+	OIDCInvalidGrant            = "invalid_grant"
+	OIDCInvalidScope            = "invalid_scope"
+	OIDCUnsupportedResponseType = "unsupported_response_type"
+	OIDCUnsupportedGrantType    = "unsupported_grant_type"
+	OIDCAccessDenied            = "access_denied"
+	OIDCLoginRequired           = "login_required"
+)
+
+type OIDCClientRepository interface {
+	CreateOIDCClient(c context.Context, client *OIDCClient) error
+	GetOIDCClientByID(c context.Context, clientId string) (*OIDCClient, error)
+	// GetOIDCClients lists every client, oldest first.
+	GetOIDCClients(c context.Context) ([]*OIDCClient, error)
+	DeleteOIDCClient(c context.Context, clientId string) error
+}
+
+type AuthorizationCodeRepository interface {
+	CreateAuthorizationCode(c context.Context, code *AuthorizationCode) error
+	GetAuthorizationCodeByHash(c context.Context, codeHash string) (*AuthorizationCode, error)
+	// MarkAuthorizationCodeUsed atomically sets UsedAt if it is still unset
+	// and reports whether it did, so a code cannot be redeemed twice.
+	MarkAuthorizationCodeUsed(c context.Context, codeId string, usedAt time.Time) (bool, error)
+}
+
+type OIDCUsecases interface {
+	// RegisterClient registers a client allowed to redirect to
+	// redirectURIs. Confidential clients get a secret.
+	RegisterClient(ctx context.Context, name string, redirectURIs []string, public bool) (*NewOIDCClient, error)
+	ListClients(ctx context.Context) ([]*OIDCClient, error)
+	DeleteClient(ctx context.Context, clientId string) error
+
+	// Discovery returns the provider metadata.
+	Discovery() *OIDCProviderMetadata
+	// ValidateAuthorization checks an authorization request. Errors about
+	// the client or redirect URI are plain errors; anything else is an
+	// *OIDCError to be sent to the redirect URI.
+	ValidateAuthorization(ctx context.Context, request *AuthorizationRequest) (*OIDCClient, error)
+	// Authorize signs the user in with their password, subject to the same
+	// lockout as Login, and issues a code unless a second factor is due.
+	Authorize(ctx context.Context, request *AuthorizationRequest, email, password, clientIP string) (*Authorization, error)
+	// AuthorizeTwoFactor completes a sign-in that was challenged for a
+	// TOTP or recovery code.
+	AuthorizeTwoFactor(ctx context.Context, request *AuthorizationRequest, challengeToken, code, clientIP string) (*Authorization, error)
+	// Exchange redeems an authorization code for an ID token and an access
+	// token. Failures are *OIDCErrors.
+	Exchange(ctx context.Context, request *TokenRequest) (*OIDCTokens, error)
+	// UserInfo returns the claims the access token's scopes release.
+	UserInfo(ctx context.Context, accessToken string) (*OIDCUserInfo, error)
+}
+
+// IOIDCTokenService signs the tokens issued to relying parties with the
+// same key ring as access tokens. They carry the client as audience, which
+// keeps them from being accepted as task_manager access tokens.
+type IOIDCTokenService interface {
+	GenerateIDToken(info *OIDCUserInfo, clientId, nonce string, authTime time.Time) (string, error)
+	GenerateAccessToken(user *User, clientId string, scopes []string) (string, time.Duration, error)
+	ParseAccessToken(token string) (*OIDCAccessClaims, error)
+	// SigningAlgorithm is the algorithm new tokens are signed with.
+	SigningAlgorithm() string
+}
+
+var (
+	ErrOIDCClientNotFound      = errors.New("oidc client not found")
+	ErrOIDCClientAlreadyExists = errors.New("oidc client already exists")
+	ErrInvalidOIDCClient       = errors.New("invalid oidc client")
+	ErrInvalidRedirectURI      = errors.New("redirect_uri is not registered for this client")
+	ErrTwoFactorSetupFirst     = errors.New("two-factor authentication must be set up by signing in to task_manager first")
+)
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const PersonalAccessTokenCollection = "personal_access_tokens"
+
+// PersonalAccessTokenPrefix starts every personal access token, which is
+// how the auth middleware tells them apart from JWTs.
+const PersonalAccessTokenPrefix = "pat_"
+
+// PersonalAccessToken is the stored record of a long-lived token for
+// scripts and automation. Like refresh tokens only the SHA-256 hash of the
+// token is kept. A token acts as its owner, restricted to Scopes: it is
+// granted a permission only while both the scopes and the owner's role
+// include it.
+type PersonalAccessToken struct {
+	ID        string
+	UserID    string
+	Name      string
+	TokenHash string
+	Scopes    []string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// LastUsedAt is nil until the token is first used. It is only updated
+	// about once a minute, not on every request.
+	LastUsedAt *time.Time
+}
+
+// NewPersonalAccessToken is what creating a token returns. Token is the
+// secret itself and is never available again.
+type NewPersonalAccessToken struct {
+	Token  string
+	Record *PersonalAccessToken
+}
+
+type PersonalAccessTokenRepository interface {
+	CreatePersonalAccessToken(c context.Context, token *PersonalAccessToken) error
+	GetPersonalAccessTokenByHash(c context.Context, tokenHash string) (*PersonalAccessToken, error)
+	// GetPersonalAccessTokensByUser lists a user's tokens, newest first.
+	GetPersonalAccessTokensByUser(c context.Context, userId string) ([]*PersonalAccessToken, error)
+	// DeletePersonalAccessToken deletes the token only if it belongs to the
+	// user, and fails with ErrTokenNotFound otherwise.
+	DeletePersonalAccessToken(c context.Context, userId string, tokenId string) error
+	// DeletePersonalAccessTokensByUser revokes every token of the user.
+	DeletePersonalAccessTokensByUser(c context.Context, userId string) error
+	TouchPersonalAccessToken(c context.Context, tokenId string, usedAt time.Time) error
+}
+
+type PersonalAccessTokenUsecases interface {
+	// CreateToken issues a token that expires at expiresAt. Every scope
+	// must be a permission the user's role currently grants.
+	CreateToken(ctx context.Context, userId string, name string, scopes []string, expiresAt time.Time) (*NewPersonalAccessToken, error)
+	ListTokens(ctx context.Context, userId string) ([]*PersonalAccessToken, error)
+	RevokeToken(ctx context.Context, userId string, tokenId string) error
+	// Authenticate checks a personal access token and returns its owner,
+	// rejecting unknown, expired and revoked tokens and disabled accounts.
+	Authenticate(ctx context.Context, token string) (*User, *PersonalAccessToken, error)
+}
+
+var ErrInvalidPersonalAccessToken = errors.New("invalid personal access token")
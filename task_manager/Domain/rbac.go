@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Permissions a role can grant. The plain task permissions cover the
+// caller's own tasks; the ":any" variants extend them to other users' tasks,
+// which must still be requested explicitly with an admin override.
+const (
+	PermTaskRead      = "task:read"
+	PermTaskReadAny   = "task:read:any"
+	PermTaskCreate    = "task:create"
+	PermTaskUpdate    = "task:update"
+	PermTaskUpdateAny = "task:update:any"
+	PermTaskDelete    = "task:delete"
+	PermTaskDeleteAny = "task:delete:any"
+	PermUserRead      = "user:read"
+	PermUserPromote   = "user:promote"
+	PermUserDisable   = "user:disable"
+	PermUserDelete    = "user:delete"
+	PermUserUnlock    = "user:unlock"
+	PermRoleManage    = "role:manage"
+	PermRoleAssign    = "role:assign"
+	PermClientManage  = "client:manage"
+	PermWebhookManage = "webhook:manage"
+	PermAuditRead     = "audit:read"
+	// PermCommentWrite allows adding comments to tasks the holder can
+	// access, and editing and deleting their own.
+	PermCommentWrite = "comment:write"
+	// PermCommentModerate allows deleting other people's comments on tasks
+	// the holder can access.
+	PermCommentModerate = "comment:moderate"
+)
+
+// Permissions lists every permission the application checks.
+var Permissions = []string{
+	PermTaskRead, PermTaskReadAny,
+	PermTaskCreate,
+	PermTaskUpdate, PermTaskUpdateAny,
+	PermTaskDelete, PermTaskDeleteAny,
+	PermUserRead, PermUserPromote, PermUserDisable, PermUserDelete, PermUserUnlock,
+	PermRoleManage, PermRoleAssign,
+	PermClientManage,
+	PermWebhookManage,
+	PermAuditRead,
+	PermCommentWrite, PermCommentModerate,
+}
+
+// Built-in roles. The first user to register becomes an admin; everyone
+// after starts as a user.
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Role is a named set of permissions. Built-in roles are defined in code and
+// cannot be changed; custom roles are created by admins and stored.
+type Role struct {
+	Name        string
+	Description string
+	Permissions []string
+	BuiltIn     bool
+}
+
+var ErrRoleNotFound = errors.New("role not found")
+var ErrRoleAlreadyExists = errors.New("role already exists")
+var ErrInvalidRole = errors.New("invalid role")
+
+// BuiltInRoles returns the roles every installation has. Admins hold every
+// permission; users may read their own tasks, as before roles existed, and
+// comment on them.
+func BuiltInRoles() []*Role {
+	return []*Role{
+		{Name: RoleAdmin, Description: "Full access", Permissions: slices.Clone(Permissions), BuiltIn: true},
+		{Name: RoleUser, Description: "Read and comment on own tasks", Permissions: []string{PermTaskRead, PermCommentWrite}, BuiltIn: true},
+	}
+}
+
+// BuiltInRole returns the built-in role called name, or nil.
+func BuiltInRole(name string) *Role {
+	for _, role := range BuiltInRoles() {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// Validate checks a custom role: a lower_snake_case name that does not
+// shadow a built-in role, and only known permissions. Duplicate permissions
+// are removed.
+func (r *Role) Validate() error {
+	if !roleNamePattern.MatchString(r.Name) {
+		return fmt.Errorf("%w: name must be 2-32 lower_snake_case characters", ErrInvalidRole)
+	}
+	if BuiltInRole(r.Name) != nil {
+		return fmt.Errorf("%w: %q is a built-in role", ErrRoleAlreadyExists, r.Name)
+	}
+	var perms []string
+	for _, p := range r.Permissions {
+		if !slices.Contains(Permissions, p) {
+			return fmt.Errorf("%w: unknown permission %q", ErrInvalidRole, p)
+		}
+		if !slices.Contains(perms, p) {
+			perms = append(perms, p)
+		}
+	}
+	r.Permissions = perms
+	return nil
+}
+
+// Can reports whether the actor's role grants permission.
+func (a *Actor) Can(permission string) bool {
+	return a != nil && slices.Contains(a.Permissions, permission)
+}
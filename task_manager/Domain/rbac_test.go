@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"testing"
+
+	domain "task_manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltInRoles(t *testing.T) {
+	admin := domain.BuiltInRole(domain.RoleAdmin)
+	if assert.NotNil(t, admin) {
+		assert.ElementsMatch(t, domain.Permissions, admin.Permissions)
+	}
+	user := domain.BuiltInRole(domain.RoleUser)
+	if assert.NotNil(t, user) {
+		assert.Equal(t, []string{domain.PermTaskRead, domain.PermCommentWrite}, user.Permissions)
+	}
+	assert.Nil(t, domain.BuiltInRole("editor"))
+
+	// Callers get their own copy to mutate.
+	admin.Permissions[0] = "mutated"
+	assert.NotEqual(t, "mutated", domain.BuiltInRole(domain.RoleAdmin).Permissions[0])
+}
+
+func TestRole_Validate(t *testing.T) {
+	role := &domain.Role{Name: "editor", Permissions: []string{domain.PermTaskRead, domain.PermTaskUpdate, domain.PermTaskRead}}
+	assert.NoError(t, role.Validate())
+	assert.Equal(t, []string{domain.PermTaskRead, domain.PermTaskUpdate}, role.Permissions)
+
+	assert.ErrorIs(t, (&domain.Role{Name: "Editor"}).Validate(), domain.ErrInvalidRole)
+	assert.ErrorIs(t, (&domain.Role{Name: "x"}).Validate(), domain.ErrInvalidRole)
+	assert.ErrorIs(t, (&domain.Role{Name: "editor", Permissions: []string{"task:fly"}}).Validate(), domain.ErrInvalidRole)
+	assert.ErrorIs(t, (&domain.Role{Name: domain.RoleAdmin}).Validate(), domain.ErrRoleAlreadyExists)
+}
+
+func TestActor_Can(t *testing.T) {
+	actor := &domain.Actor{Permissions: []string{domain.PermTaskDeleteAny}}
+	assert.True(t, actor.Can(domain.PermTaskDeleteAny))
+	assert.False(t, actor.Can(domain.PermTaskUpdateAny))
+
+	var nobody *domain.Actor
+	assert.False(t, nobody.Can(domain.PermTaskRead))
+}
@@ -0,0 +1,570 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxOccurrencePreview is the most upcoming occurrences listed at once.
+	MaxOccurrencePreview = 100
+	// maxRecurrencePeriods bounds the expansion of rules that match rarely
+	// or never, such as the 30th of February.
+	maxRecurrencePeriods = 100000
+)
+
+// Recurrence makes a task one occurrence of a repeating series. Rule is an
+// RFC 5545 RRULE evaluated in TimeZone from Start, the due date of the
+// series' first occurrence. Occurrence is the slot of the series this task
+// fills; it stays put when the task's own due date is moved, so the next
+// occurrence keeps to the rule. Every occurrence of a series shares its
+// SeriesID.
+type Recurrence struct {
+	Rule       string
+	TimeZone   string
+	SeriesID   string
+	Start      time.Time
+	Occurrence time.Time
+}
+
+// Parse reads the rule in the series' time zone.
+func (r *Recurrence) Parse() (*RecurrenceRule, *time.Location, error) {
+	loc, err := LoadTimeZone(r.TimeZone)
+	if err != nil {
+		return nil, nil, err
+	}
+	rule, err := ParseRecurrenceRule(r.Rule, loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rule, loc, nil
+}
+
+// Upcoming lists up to n occurrences of the series after this one.
+func (r *Recurrence) Upcoming(n int) ([]time.Time, error) {
+	rule, loc, err := r.Parse()
+	if err != nil {
+		return nil, err
+	}
+	return rule.After(r.Start.In(loc), r.Occurrence, n), nil
+}
+
+// LoadTimeZone loads an IANA time zone; empty means UTC.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidRecurrence, name)
+	}
+	return loc, nil
+}
+
+// The parts of a series an update to one of its occurrences applies to.
+const (
+	// SeriesScopeThis changes only the occurrence itself.
+	SeriesScopeThis = "this"
+	// SeriesScopeFollowing changes the occurrence and every later one,
+	// splitting them off into a series of their own.
+	SeriesScopeFollowing = "following"
+	// SeriesScopeAll changes every occurrence of the series.
+	SeriesScopeAll = "all"
+)
+
+var (
+	ErrInvalidRecurrence  = errors.New("invalid recurrence")
+	ErrInvalidSeriesScope = errors.New("invalid series scope")
+	ErrNotRecurring       = errors.New("task is not recurring")
+)
+
+// Recurrence frequencies. The sub-daily ones of RFC 5545 are not
+// supported: tasks are due on days, not every few minutes.
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// RecurrenceDay is a BYDAY entry: a weekday, optionally the Nth of its
+// month or year, counted from the end when N is negative.
+type RecurrenceDay struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// RecurrenceRule is a parsed RRULE. It supports FREQ (DAILY to YEARLY),
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH, BYSETPOS and WKST.
+// Occurrences keep the wall-clock time of the series start in its time
+// zone, across daylight saving changes.
+type RecurrenceRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []RecurrenceDay
+	ByMonthDay []int
+	ByMonth    []int
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrenceRule parses an RRULE value, with or without its "RRULE:"
+// prefix. A floating or date-only UNTIL is read in loc; a date-only one
+// includes the whole day.
+func ParseRecurrenceRule(value string, loc *time.Location) (*RecurrenceRule, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidRecurrence, fmt.Sprintf(format, args...))
+	}
+
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, "RRULE:")
+	if value == "" {
+		return nil, invalid("empty rule")
+	}
+	rule := &RecurrenceRule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, invalid("malformed part %q", part)
+		}
+		if seen[key] {
+			return nil, invalid("%s given twice", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch val {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				rule.Freq = val
+			case "SECONDLY", "MINUTELY", "HOURLY":
+				return nil, invalid("FREQ=%s is not supported", val)
+			default:
+				return nil, invalid("unknown FREQ %q", val)
+			}
+		case "INTERVAL":
+			if rule.Interval, err = strconv.Atoi(val); err != nil || rule.Interval < 1 {
+				return nil, invalid("INTERVAL must be a positive number")
+			}
+		case "COUNT":
+			if rule.Count, err = strconv.Atoi(val); err != nil || rule.Count < 1 {
+				return nil, invalid("COUNT must be a positive number")
+			}
+		case "UNTIL":
+			if rule.Until, err = parseUntil(val, loc); err != nil {
+				return nil, invalid("UNTIL %q is not a date or date-time", val)
+			}
+		case "BYDAY":
+			for _, item := range strings.Split(val, ",") {
+				day, err := parseRecurrenceDay(item)
+				if err != nil {
+					return nil, invalid("BYDAY %q: %v", item, err)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			if rule.ByMonthDay, err = parseIntList(val, 31, true); err != nil {
+				return nil, invalid("BYMONTHDAY: %v", err)
+			}
+		case "BYMONTH":
+			if rule.ByMonth, err = parseIntList(val, 12, false); err != nil {
+				return nil, invalid("BYMONTH: %v", err)
+			}
+		case "BYSETPOS":
+			if rule.BySetPos, err = parseIntList(val, 366, true); err != nil {
+				return nil, invalid("BYSETPOS: %v", err)
+			}
+		case "WKST":
+			i := slices.Index(weekdayCodes, val)
+			if i < 0 {
+				return nil, invalid("unknown WKST %q", val)
+			}
+			rule.WeekStart = time.Weekday(i)
+		case "BYSECOND", "BYMINUTE", "BYHOUR", "BYYEARDAY", "BYWEEKNO":
+			return nil, invalid("%s is not supported", key)
+		default:
+			return nil, invalid("unknown part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, invalid("FREQ is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, invalid("COUNT and UNTIL cannot both be given")
+	}
+	if rule.Freq == FreqWeekly && len(rule.ByMonthDay) > 0 {
+		return nil, invalid("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	for _, day := range rule.ByDay {
+		if day.N == 0 {
+			continue
+		}
+		if rule.Freq != FreqMonthly && rule.Freq != FreqYearly {
+			return nil, invalid("numbered BYDAY needs FREQ=MONTHLY or YEARLY")
+		}
+		if rule.Freq == FreqMonthly || len(rule.ByMonth) > 0 {
+			if day.N < -5 || day.N > 5 {
+				return nil, invalid("a month has at most five of each weekday")
+			}
+		}
+	}
+	if len(rule.BySetPos) > 0 && len(rule.ByDay)+len(rule.ByMonthDay)+len(rule.ByMonth) == 0 {
+		return nil, invalid("BYSETPOS needs another BYxxx part")
+	}
+	return rule, nil
+}
+
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if at, err := time.Parse("20060102T150405Z", value); err == nil {
+		return at, nil
+	}
+	if at, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return at, nil
+	}
+	day, err := time.ParseInLocation("20060102", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, loc), nil
+}
+
+func parseRecurrenceDay(value string) (RecurrenceDay, error) {
+	if len(value) < 2 {
+		return RecurrenceDay{}, errors.New("not a weekday")
+	}
+	code, number := value[len(value)-2:], value[:len(value)-2]
+	weekday := slices.Index(weekdayCodes, code)
+	if weekday < 0 {
+		return RecurrenceDay{}, errors.New("not a weekday")
+	}
+	day := RecurrenceDay{Weekday: time.Weekday(weekday)}
+	if number != "" {
+		n, err := strconv.Atoi(number)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return RecurrenceDay{}, errors.New("ordinal must be between -53 and 53, and not 0")
+		}
+		day.N = n
+	}
+	return day, nil
+}
+
+// parseIntList reads a comma-separated list of numbers between 1 and max,
+// or -max and -1 too when negative is allowed.
+func parseIntList(value string, max int, negative bool) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n > max || n < -max || (n < 0 && !negative) {
+			return nil, fmt.Errorf("%q is out of range", item)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// String renders the rule as a canonical RRULE value, without the prefix.
+// UNTIL is always written in UTC.
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	joinInts := func(list []int) string {
+		items := make([]string, len(list))
+		for i, n := range list {
+			items[i] = strconv.Itoa(n)
+		}
+		return strings.Join(items, ",")
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = weekdayCodes[day.Weekday]
+			if day.N != 0 {
+				days[i] = strconv.Itoa(day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCodes[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// After lists up to n occurrences of the series starting at start that
+// fall strictly after after. start carries the series' time zone.
+func (r *RecurrenceRule) After(start, after time.Time, n int) []time.Time {
+	var occurrences []time.Time
+	if n <= 0 {
+		return occurrences
+	}
+	r.each(start, func(at time.Time) bool {
+		if at.After(after) {
+			occurrences = append(occurrences, at)
+		}
+		return len(occurrences) < n
+	})
+	return occurrences
+}
+
+// Split divides the series starting at start at the occurrence at: the
+// first rule keeps the occurrences before it and the second, for a series
+// starting at at, the rest, including what is left of any COUNT.
+func (r *RecurrenceRule) Split(start, at time.Time) (before, rest *RecurrenceRule) {
+	earlier := 0
+	r.each(start, func(occurrence time.Time) bool {
+		if !occurrence.Before(at) {
+			return false
+		}
+		earlier++
+		return true
+	})
+
+	head, tail := *r, *r
+	head.Count = 0
+	head.Until = at.Add(-time.Second)
+	if tail.Count > 0 {
+		tail.Count = max(tail.Count-earlier, 1)
+	}
+	return &head, &tail
+}
+
+// each calls yield with the occurrences of the series starting at start,
+// in order, until it returns false or the series ends.
+func (r *RecurrenceRule) each(start time.Time, yield func(time.Time) bool) {
+	loc := start.Location()
+	hour, minute, second := start.Clock()
+	first := civilDate(start.Year(), start.Month(), start.Day())
+	count := 0
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		days, ok := r.expand(first, period)
+		if !ok {
+			return
+		}
+		for _, day := range days {
+			at := inZone(day, hour, minute, second, start.Nanosecond(), loc)
+			if at.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && at.After(r.Until) {
+				return
+			}
+			count++
+			if !yield(at) {
+				return
+			}
+			if r.Count > 0 && count >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// inZone is the given wall clock time on day in loc. A time that falls in
+// a gap, when the clocks go forward, is read with the offset from before
+// the gap as RFC 5545 asks, which moves it forward by the gap's length:
+// 02:30 on the day New York skips to 03:00 is 03:30.
+func inZone(day time.Time, hour, minute, second, nsec int, loc *time.Location) time.Time {
+	at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, nsec, loc)
+	wall := time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), second, nsec, time.UTC)
+	want := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, nsec, time.UTC)
+	// time.Date picks the offset from after the gap, so the wall clock comes
+	// out earlier than asked; a later one is already past the gap.
+	if wall.Before(want) {
+		at = at.Add(want.Sub(wall))
+	}
+	return at
+}
+
+// civilDate is a calendar day, as midnight UTC so day arithmetic ignores
+// time zones.
+func civilDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// expand lists the days of the given period of the rule, counting from
+// the one containing first, in order and after BYSETPOS. It reports false
+// once the periods run past the calendar.
+func (r *RecurrenceRule) expand(first time.Time, period int) ([]time.Time, bool) {
+	step := period * r.Interval
+	var days []time.Time
+	switch r.Freq {
+	case FreqDaily:
+		day := first.AddDate(0, 0, step)
+		if day.Year() > 9999 {
+			return nil, false
+		}
+		if r.inMonths(day) && r.onMonthDays(day) && r.onWeekdays(day) {
+			days = append(days, day)
+		}
+	case FreqWeekly:
+		weekStart := first.AddDate(0, 0, -((int(first.Weekday())-int(r.WeekStart)+7)%7)+7*step)
+		if weekStart.Year() > 9999 {
+			return nil, false
+		}
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			matches := day.Weekday() == first.Weekday()
+			if len(r.ByDay) > 0 {
+				matches = r.onWeekdays(day)
+			}
+			if matches && r.inMonths(day) {
+				days = append(days, day)
+			}
+		}
+	case FreqMonthly:
+		month := civilDate(first.Year(), first.Month()+time.Month(step), 1)
+		if month.Year() > 9999 {
+			return nil, false
+		}
+		if r.inMonths(month) {
+			days = r.monthDays(month, first.Day())
+		}
+	case FreqYearly:
+		year := first.Year() + step
+		if year > 9999 {
+			return nil, false
+		}
+		switch {
+		case len(r.ByMonth) > 0:
+			for _, month := range slices.Sorted(slices.Values(r.ByMonth)) {
+				days = append(days, r.monthDays(civilDate(year, time.Month(month), 1), first.Day())...)
+			}
+		case len(r.ByMonthDay) > 0:
+			for month := time.January; month <= time.December; month++ {
+				days = append(days, r.monthDays(civilDate(year, month, 1), first.Day())...)
+			}
+		case len(r.ByDay) > 0:
+			start := civilDate(year, time.January, 1)
+			days = nthWeekdays(start, start.AddDate(1, 0, 0), r.ByDay)
+		default:
+			day := civilDate(year, first.Month(), first.Day())
+			if day.Month() == first.Month() {
+				days = append(days, day)
+			}
+		}
+	}
+
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	days = slices.CompactFunc(days, func(a, b time.Time) bool { return a.Equal(b) })
+	if len(r.BySetPos) == 0 {
+		return days, true
+	}
+	var picked []time.Time
+	for _, pos := range r.BySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(days) + pos
+		}
+		if i >= 0 && i < len(days) {
+			picked = append(picked, days[i])
+		}
+	}
+	slices.SortFunc(picked, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(picked, func(a, b time.Time) bool { return a.Equal(b) }), true
+}
+
+// monthDays lists the days of the month starting at month that the rule's
+// BYMONTHDAY and BYDAY pick, or the day of the month of the series start
+// when it has neither. Days the month does not have are skipped.
+func (r *RecurrenceRule) monthDays(month time.Time, startDay int) []time.Time {
+	next := month.AddDate(0, 1, 0)
+	last := next.AddDate(0, 0, -1).Day()
+	if len(r.ByMonthDay) == 0 {
+		if len(r.ByDay) > 0 {
+			return nthWeekdays(month, next, r.ByDay)
+		}
+		if startDay > last {
+			return nil
+		}
+		return []time.Time{month.AddDate(0, 0, startDay-1)}
+	}
+
+	var weekdays []time.Time
+	if len(r.ByDay) > 0 {
+		weekdays = nthWeekdays(month, next, r.ByDay)
+	}
+	var days []time.Time
+	for _, monthDay := range r.ByMonthDay {
+		if monthDay < 0 {
+			monthDay = last + 1 + monthDay
+		}
+		if monthDay < 1 || monthDay > last {
+			continue
+		}
+		day := month.AddDate(0, 0, monthDay-1)
+		if len(r.ByDay) > 0 && !slices.ContainsFunc(weekdays, day.Equal) {
+			continue
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// nthWeekdays lists the days in [from, to) that a BYDAY list picks, with
+// ordinals counted within that range.
+func nthWeekdays(from, to time.Time, byDay []RecurrenceDay) []time.Time {
+	var days []time.Time
+	for _, want := range byDay {
+		var matching []time.Time
+		for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+			if day.Weekday() == want.Weekday {
+				matching = append(matching, day)
+			}
+		}
+		switch {
+		case want.N == 0:
+			days = append(days, matching...)
+		case want.N > 0 && want.N <= len(matching):
+			days = append(days, matching[want.N-1])
+		case want.N < 0 && -want.N <= len(matching):
+			days = append(days, matching[len(matching)+want.N])
+		}
+	}
+	return days
+}
+
+func (r *RecurrenceRule) inMonths(day time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, int(day.Month()))
+}
+
+func (r *RecurrenceRule) onMonthDays(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := civilDate(day.Year(), day.Month()+1, 0).Day()
+	return slices.Contains(r.ByMonthDay, day.Day()) || slices.Contains(r.ByMonthDay, day.Day()-last-1)
+}
+
+func (r *RecurrenceRule) onWeekdays(day time.Time) bool {
+	return len(r.ByDay) == 0 || slices.ContainsFunc(r.ByDay, func(want RecurrenceDay) bool {
+		return want.Weekday == day.Weekday()
+	})
+}
@@ -0,0 +1,146 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dates(times []time.Time) []string {
+	var days []string
+	for _, at := range times {
+		days = append(days, at.Format("2006-01-02"))
+	}
+	return days
+}
+
+func TestRecurrenceRule_Expansion(t *testing.T) {
+	cases := []struct {
+		rule  string
+		start string
+		want  []string
+	}{
+		{"FREQ=DAILY;COUNT=3", "2025-03-01", []string{"2025-03-01", "2025-03-02", "2025-03-03"}},
+		{"FREQ=DAILY;UNTIL=20250303", "2025-03-01", []string{"2025-03-01", "2025-03-02", "2025-03-03"}},
+		{"FREQ=WEEKLY;BYDAY=MO,WE", "2025-06-02", []string{"2025-06-02", "2025-06-04", "2025-06-09", "2025-06-11"}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "2025-06-03", []string{"2025-06-03", "2025-06-17", "2025-07-01"}},
+		// Months without the start's day are skipped, unless counted from the end.
+		{"FREQ=MONTHLY", "2025-01-31", []string{"2025-01-31", "2025-03-31", "2025-05-31"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "2025-01-31", []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30"}},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2025-01-01", []string{"2025-01-31", "2025-02-28", "2025-03-28"}},
+		// The last working day of the month.
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", "2025-05-01", []string{"2025-05-30", "2025-06-30", "2025-07-31"}},
+		{"FREQ=YEARLY", "2024-02-29", []string{"2024-02-29", "2028-02-29"}},
+		{"FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "2025-01-01", []string{"2025-11-27", "2026-11-26"}},
+		{"FREQ=YEARLY;BYMONTHDAY=1;COUNT=3", "2025-01-01", []string{"2025-01-01", "2025-02-01", "2025-03-01"}},
+	}
+	for _, c := range cases {
+		rule, err := domain.ParseRecurrenceRule(c.rule, time.UTC)
+		require.NoError(t, err, c.rule)
+		start, err := time.Parse("2006-01-02 15:04", c.start+" 09:00")
+		require.NoError(t, err)
+
+		got := rule.After(start, start.Add(-time.Second), len(c.want))
+		assert.Equal(t, c.want, dates(got), c.rule)
+	}
+}
+
+func TestRecurrenceRule_KeepsWallClockAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	rule, err := domain.ParseRecurrenceRule("RRULE:FREQ=DAILY", berlin)
+	require.NoError(t, err)
+	start := time.Date(2025, 3, 29, 9, 0, 0, 0, berlin)
+
+	next := rule.After(start, start, 1)
+
+	require.Len(t, next, 1)
+	assert.Equal(t, 9, next[0].Hour())
+	assert.Equal(t, 7, next[0].UTC().Hour())
+}
+
+func TestRecurrenceRule_MovesTimesInDSTGapForward(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	rule, err := domain.ParseRecurrenceRule("RRULE:FREQ=DAILY", newYork)
+	require.NoError(t, err)
+	start := time.Date(2026, 3, 7, 2, 30, 0, 0, newYork)
+
+	next := rule.After(start, start, 2)
+
+	// 02:30 does not exist on 2026-03-08; read with the EST offset it is
+	// 03:30 EDT. The day after keeps 02:30 again.
+	require.Len(t, next, 2)
+	assert.Equal(t, time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC), next[0].UTC())
+	assert.Equal(t, "03:30 EDT", next[0].Format("15:04 MST"))
+	assert.Equal(t, "02:30 EDT", next[1].Format("15:04 MST"))
+}
+
+func TestParseRecurrenceRule_Invalid(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;UNTIL=tomorrow",
+	} {
+		_, err := domain.ParseRecurrenceRule(rule, time.UTC)
+		assert.ErrorIs(t, err, domain.ErrInvalidRecurrence, rule)
+	}
+}
+
+func TestRecurrenceRule_String(t *testing.T) {
+	rule, err := domain.ParseRecurrenceRule("rrule:byday=-1fr;freq=monthly;interval=2;wkst=su", time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR;WKST=SU", rule.String())
+
+	rule, err = domain.ParseRecurrenceRule("FREQ=DAILY;UNTIL=20250301T120000Z", time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=DAILY;UNTIL=20250301T120000Z", rule.String())
+}
+
+func TestRecurrenceRule_Split(t *testing.T) {
+	rule, err := domain.ParseRecurrenceRule("FREQ=WEEKLY;COUNT=5", time.UTC)
+	require.NoError(t, err)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	at := start.AddDate(0, 0, 14)
+
+	before, rest := rule.Split(start, at)
+
+	assert.Equal(t, []string{"2025-06-02", "2025-06-09"}, dates(before.After(start, start.Add(-time.Second), 10)))
+	assert.Equal(t, 3, rest.Count)
+	assert.Equal(t, []string{"2025-06-16", "2025-06-23", "2025-06-30"}, dates(rest.After(at, at.Add(-time.Second), 10)))
+	assert.Equal(t, 5, rule.Count)
+}
+
+func TestRecurrence_Upcoming(t *testing.T) {
+	start := time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)
+	recurrence := &domain.Recurrence{
+		Rule:       "FREQ=WEEKLY;BYDAY=MO",
+		TimeZone:   "Europe/Berlin",
+		Start:      start,
+		Occurrence: start.AddDate(0, 0, 7),
+	}
+
+	upcoming, err := recurrence.Upcoming(2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-06-16", "2025-06-23"}, dates(upcoming))
+	assert.Equal(t, "Europe/Berlin", upcoming[0].Location().String())
+	assert.Equal(t, 9, upcoming[0].Hour())
+
+	recurrence.TimeZone = "Mars/Olympus_Mons"
+	_, err = recurrence.Upcoming(2)
+	assert.ErrorIs(t, err, domain.ErrInvalidRecurrence)
+}
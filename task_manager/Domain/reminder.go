@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	ReminderCollection         = "reminders"
+	ReminderDeliveryCollection = "reminder_deliveries"
+)
+
+const (
+	// MaxReminderLead is the furthest ahead of a due date a reminder can be
+	// set.
+	MaxReminderLead = 30 * 24 * time.Hour
+	// MaxRemindersPerUser is how many reminders one user can configure.
+	MaxRemindersPerUser = 10
+	// ReminderChannelEmail is the channel reminders use when none is given.
+	ReminderChannelEmail = "email"
+)
+
+// Reminder is a user's standing request to be notified Lead before each of
+// their open tasks is due, e.g. 24h, or 0 for at the due time. Leads are
+// whole minutes, and a user cannot have two reminders with the same lead
+// on the same channel.
+type Reminder struct {
+	ID        string
+	UserID    string
+	Lead      time.Duration
+	Channel   string
+	CreatedAt time.Time
+}
+
+// Delivery states of a reminder.
+const (
+	// ReminderPending deliveries are waiting to be sent, or to be retried
+	// once LeaseUntil has passed.
+	ReminderPending = "pending"
+	ReminderSent    = "sent"
+	// ReminderFailed deliveries ran out of attempts.
+	ReminderFailed = "failed"
+	// ReminderCancelled deliveries were no longer wanted by the time they
+	// came up: the task was closed, deleted or moved, or the reminder was
+	// removed.
+	ReminderCancelled = "cancelled"
+)
+
+// ReminderDelivery is the send state of one reminder for one task. Its ID is
+// derived from the reminder, the task and SendAt, so every scheduler
+// instance that finds the same reminder due stores the same delivery, and
+// moving the task's due date makes a new one.
+//
+// A scheduler claims a pending delivery by leasing it: LeaseOwner holds it
+// until LeaseUntil, and no other scheduler may claim it before then. A
+// delivery whose holder dies before recording the outcome is claimed again
+// once the lease runs out, so reminders are sent at least once.
+type ReminderDelivery struct {
+	ID         string
+	ReminderID string
+	TaskID     string
+	UserID     string
+	Channel    string
+	SendAt     time.Time
+	Status     string
+	Attempts   int
+	LeaseOwner string
+	// LeaseUntil is when the current lease expires or, after a failed
+	// attempt, when the delivery may be retried.
+	LeaseUntil time.Time
+	LastError  string
+	SentAt     *time.Time
+	CreatedAt  time.Time
+}
+
+// ReminderNotice is what a Notifier is asked to send.
+type ReminderNotice struct {
+	User     *User
+	Task     *Task
+	Reminder *Reminder
+}
+
+// Notifier sends reminders over one channel. Notify may be called again for
+// a notice it already sent if the outcome could not be recorded.
+type Notifier interface {
+	Notify(ctx context.Context, notice *ReminderNotice) error
+}
+
+type ReminderRepository interface {
+	// CreateReminder fails with ErrReminderAlreadyExists if the user has a
+	// reminder with the same lead on the same channel.
+	CreateReminder(c context.Context, reminder *Reminder) error
+	GetReminderByID(c context.Context, reminderId string) (*Reminder, error)
+	// GetRemindersByUser lists a user's reminders by lead, longest first.
+	GetRemindersByUser(c context.Context, userId string) ([]*Reminder, error)
+	// GetReminderLeads lists the distinct leads of all users' reminders.
+	GetReminderLeads(c context.Context) ([]time.Duration, error)
+	DeleteReminder(c context.Context, reminderId string) error
+}
+
+type ReminderDeliveryRepository interface {
+	// EnqueueReminderDelivery stores a new delivery. A delivery already
+	// stored under the same ID is left as it is, and that is not an error.
+	EnqueueReminderDelivery(c context.Context, delivery *ReminderDelivery) error
+	// ClaimReminderDeliveries leases up to limit pending deliveries whose
+	// SendAt and LeaseUntil are not after now to owner until leaseUntil,
+	// oldest first, counting an attempt on each. A delivery is only ever
+	// handed to one claimant per lease. On error it still returns the
+	// deliveries it claimed, which the caller has to finish.
+	ClaimReminderDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*ReminderDelivery, error)
+	// FinishReminderDelivery stores the Status, Attempts, LeaseUntil,
+	// LastError and SentAt of a delivery claimed by owner and releases the
+	// lease. It fails with ErrReminderLeaseLost if owner no longer holds it.
+	FinishReminderDelivery(c context.Context, delivery *ReminderDelivery, owner string) error
+}
+
+// ReminderUsecases manage the actor's own reminders.
+type ReminderUsecases interface {
+	ListReminders(ctx context.Context, actor *Actor) ([]*Reminder, error)
+	// CreateReminder adds a reminder lead before due dates; an empty
+	// channel means ReminderChannelEmail.
+	CreateReminder(ctx context.Context, lead time.Duration, channel string, actor *Actor) (*Reminder, error)
+	DeleteReminder(ctx context.Context, reminderId string, actor *Actor) error
+}
+
+// ReminderSchedulerConfig tunes the reminder scheduler.
+type ReminderSchedulerConfig struct {
+	// PollInterval is how often due reminders are looked for.
+	PollInterval time.Duration
+	// MaxLateness is how long after its time a reminder is still sent, e.g.
+	// after downtime. A reminder that was already this late when its task
+	// was created or moved is not sent at all.
+	MaxLateness time.Duration
+	// Lease is how long a claimed delivery is held before another
+	// scheduler may take it over. Sends time out after half of it.
+	Lease time.Duration
+	// MaxAttempts is how often a delivery is tried before it fails, with
+	// RetryBackoff doubling between attempts.
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// Workers is how many reminders are sent at once, and BatchSize how
+	// many are claimed at a time.
+	Workers   int
+	BatchSize int
+}
+
+var DefaultReminderScheduler = ReminderSchedulerConfig{
+	PollInterval: 30 * time.Second,
+	MaxLateness:  time.Hour,
+	Lease:        2 * time.Minute,
+	MaxAttempts:  5,
+	RetryBackoff: time.Minute,
+	Workers:      4,
+	BatchSize:    50,
+}
+
+// ReminderScheduler finds due reminders and sends them through the
+// notifiers of their channels. Several instances may run against the same
+// store; each delivery is claimed by one of them at a time.
+type ReminderScheduler interface {
+	// DispatchDue records the deliveries that have come due and sends
+	// every pending one that can be claimed.
+	DispatchDue(ctx context.Context) error
+	// Run calls DispatchDue every PollInterval until ctx is cancelled, and
+	// returns once the sends in progress have finished.
+	Run(ctx context.Context)
+}
+
+var (
+	ErrReminderNotFound      = errors.New("reminder not found")
+	ErrReminderAlreadyExists = errors.New("a reminder with this lead and channel already exists")
+	ErrInvalidReminder       = errors.New("invalid reminder")
+	ErrTooManyReminders      = errors.New("too many reminders")
+	ErrReminderLeaseLost     = errors.New("reminder delivery lease lost")
+)
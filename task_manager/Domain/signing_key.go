@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const SigningKeyCollection = "signing_keys"
+
+// Algorithms access tokens can be signed with.
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey is one key of the access token key ring. A key is published
+// as soon as it is created, signs new tokens from NotBefore until
+// RetiresAt, and keeps verifying the tokens it signed until ExpiresAt.
+// PrivateKey is a PKCS #8 encoding, sealed by the key ring before it is
+// stored.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey []byte
+	CreatedAt  time.Time
+	NotBefore  time.Time
+	RetiresAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// JSONWebKey is the public half of a signing key in RFC 7517 terms. N and E
+// are set for RSA keys, Curve and X for Ed25519 keys; all binary values are
+// base64url encoded without padding.
+type JSONWebKey struct {
+	KeyID     string
+	KeyType   string
+	Algorithm string
+	Use       string
+	N         string
+	E         string
+	Curve     string
+	X         string
+}
+
+type SigningKeyRepository interface {
+	CreateSigningKey(c context.Context, key *SigningKey) error
+	// GetSigningKeys returns the keys that have not expired at now, ordered
+	// by NotBefore.
+	GetSigningKeys(c context.Context, now time.Time) ([]*SigningKey, error)
+}
+
+// KeyRotationPolicy schedules the key ring. Each key signs for Interval.
+// The next key is created Grace before the current one retires, so that
+// verifiers caching the published keys learn it before it is used, and a
+// retired key keeps verifying for Grace after, so that the tokens it signed
+// can run out. Grace must be shorter than Interval and at least as long as
+// the access token lifetime.
+type KeyRotationPolicy struct {
+	Interval time.Duration
+	Grace    time.Duration
+}
+
+var DefaultKeyRotation = KeyRotationPolicy{Interval: 30 * 24 * time.Hour, Grace: 24 * time.Hour}
+
+var ErrInvalidKeyRotation = errors.New("key rotation grace period must be shorter than the interval")
+
+func (p KeyRotationPolicy) Validate() error {
+	if p.Grace <= 0 || p.Interval <= p.Grace {
+		return ErrInvalidKeyRotation
+	}
+	return nil
+}
+
+// Active returns the key to sign with at now: of the keys whose signing
+// window contains now, the one that started last. It is nil if there is
+// none.
+func (p KeyRotationPolicy) Active(keys []*SigningKey, now time.Time) *SigningKey {
+	var active *SigningKey
+	for _, key := range keys {
+		if now.Before(key.NotBefore) || !now.Before(key.RetiresAt) {
+			continue
+		}
+		if active == nil || key.NotBefore.After(active.NotBefore) {
+			active = key
+		}
+	}
+	return active
+}
+
+// Next reports whether a new key is due at now and, if so, its signing
+// window. A key is due once no key signs beyond now+Grace; it starts when
+// the last one retires, or at once if none is signing.
+func (p KeyRotationPolicy) Next(keys []*SigningKey, now time.Time) (notBefore time.Time, retiresAt time.Time, due bool) {
+	var last time.Time
+	for _, key := range keys {
+		if key.RetiresAt.After(last) {
+			last = key.RetiresAt
+		}
+	}
+	if last.After(now.Add(p.Grace)) {
+		return time.Time{}, time.Time{}, false
+	}
+	notBefore = now
+	if last.After(now) {
+		notBefore = last
+	}
+	return notBefore, notBefore.Add(p.Interval), true
+}
+
+// Expiry is when a key retiring at retiresAt stops verifying.
+func (p KeyRotationPolicy) Expiry(retiresAt time.Time) time.Time {
+	return retiresAt.Add(p.Grace)
+}
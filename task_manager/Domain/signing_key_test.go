@@ -0,0 +1,54 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRotationPolicy_Validate(t *testing.T) {
+	assert.NoError(t, domain.DefaultKeyRotation.Validate())
+	assert.ErrorIs(t, domain.KeyRotationPolicy{Interval: time.Hour, Grace: time.Hour}.Validate(), domain.ErrInvalidKeyRotation)
+	assert.ErrorIs(t, domain.KeyRotationPolicy{Interval: time.Hour}.Validate(), domain.ErrInvalidKeyRotation)
+}
+
+func TestKeyRotationPolicy_Schedule(t *testing.T) {
+	policy := domain.KeyRotationPolicy{Interval: 10 * time.Hour, Grace: 2 * time.Hour}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// An empty ring needs a key at once.
+	notBefore, retiresAt, due := policy.Next(nil, start)
+	require.True(t, due)
+	assert.Equal(t, start, notBefore)
+	assert.Equal(t, start.Add(10*time.Hour), retiresAt)
+	first := &domain.SigningKey{ID: "k1", NotBefore: notBefore, RetiresAt: retiresAt, ExpiresAt: policy.Expiry(retiresAt)}
+	keys := []*domain.SigningKey{first}
+	assert.Equal(t, first, policy.Active(keys, start))
+
+	// Nothing is due until Grace before the key retires.
+	_, _, due = policy.Next(keys, start.Add(7*time.Hour))
+	assert.False(t, due)
+
+	// Then the next key is created to start when the first retires.
+	notBefore, retiresAt, due = policy.Next(keys, start.Add(8*time.Hour))
+	require.True(t, due)
+	assert.Equal(t, start.Add(10*time.Hour), notBefore)
+	assert.Equal(t, start.Add(20*time.Hour), retiresAt)
+	second := &domain.SigningKey{ID: "k2", NotBefore: notBefore, RetiresAt: retiresAt, ExpiresAt: policy.Expiry(retiresAt)}
+	keys = append(keys, second)
+
+	// The first key signs until it retires, published alongside the second.
+	assert.Equal(t, first, policy.Active(keys, start.Add(9*time.Hour)))
+	assert.Equal(t, second, policy.Active(keys, start.Add(10*time.Hour)))
+	assert.Equal(t, start.Add(12*time.Hour), first.ExpiresAt)
+
+	// After a long outage the new key starts at once.
+	notBefore, _, due = policy.Next(keys, start.Add(50*time.Hour))
+	require.True(t, due)
+	assert.Equal(t, start.Add(50*time.Hour), notBefore)
+	assert.Nil(t, policy.Active(keys, start.Add(50*time.Hour)))
+}
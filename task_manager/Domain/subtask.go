@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxSubtaskDepth is how deeply subtasks may nest; a top-level task is
+	// at depth 1.
+	MaxSubtaskDepth = 5
+	// MaxChecklistItems is how many checklist items a single task can have.
+	MaxChecklistItems = 100
+	// MaxChecklistItemLength is the longest checklist item text accepted,
+	// in characters.
+	MaxChecklistItemLength = 500
+)
+
+// ChecklistItem is one step of a task's checklist: lighter than a subtask,
+// with no status of its own beyond Done.
+type ChecklistItem struct {
+	ID   string
+	Text string
+	Done bool
+}
+
+// TaskProgress rolls up a task's direct subtasks and checklist. Subtasks
+// in a status that closes work without completing it, such as cancelled,
+// are left out. Percent covers subtasks and checklist items together; a
+// task with neither is 0% until it is itself completed, then 100%.
+type TaskProgress struct {
+	Subtasks          int
+	CompletedSubtasks int
+	ChecklistItems    int
+	CheckedItems      int
+	Percent           int
+}
+
+// What happens to a task's subtasks when it is deleted.
+const (
+	// SubtasksDelete deletes the subtasks too, all the way down.
+	SubtasksDelete = "delete"
+	// SubtasksDetach turns the subtasks into top-level tasks.
+	SubtasksDetach = "detach"
+	// SubtasksRestrict refuses to delete a task that has subtasks, or to
+	// complete one whose subtasks are still open.
+	SubtasksRestrict = "restrict"
+)
+
+// What happens to a task's open subtasks when it is completed, besides
+// SubtasksRestrict.
+const (
+	// SubtasksComplete moves open subtasks into the parent's new status,
+	// whatever transitions the workflow would normally allow.
+	SubtasksComplete = "complete"
+	// SubtasksIgnore leaves open subtasks as they are.
+	SubtasksIgnore = "ignore"
+)
+
+// SubtaskRules are the cascading rules applied to a task's subtasks when it
+// is deleted or completed.
+type SubtaskRules struct {
+	OnDelete   string
+	OnComplete string
+}
+
+// DefaultSubtaskRules delete subtasks along with their parent and keep a
+// parent from being completed while subtasks are open.
+func DefaultSubtaskRules() SubtaskRules {
+	return SubtaskRules{OnDelete: SubtasksDelete, OnComplete: SubtasksRestrict}
+}
+
+// NewSubtaskRules validates a rule set; empty values take the defaults.
+func NewSubtaskRules(onDelete, onComplete string) (SubtaskRules, error) {
+	rules := DefaultSubtaskRules()
+	if onDelete != "" {
+		rules.OnDelete = onDelete
+	}
+	if onComplete != "" {
+		rules.OnComplete = onComplete
+	}
+	switch rules.OnDelete {
+	case SubtasksDelete, SubtasksDetach, SubtasksRestrict:
+	default:
+		return rules, fmt.Errorf("subtasks: unknown delete rule %q", rules.OnDelete)
+	}
+	switch rules.OnComplete {
+	case SubtasksComplete, SubtasksIgnore, SubtasksRestrict:
+	default:
+		return rules, fmt.Errorf("subtasks: unknown complete rule %q", rules.OnComplete)
+	}
+	return rules, nil
+}
+
+var (
+	ErrInvalidParentTask     = errors.New("invalid parent task")
+	ErrTaskHasSubtasks       = errors.New("task has subtasks")
+	ErrOpenSubtasks          = errors.New("task has open subtasks")
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrInvalidChecklistItem  = errors.New("invalid checklist item")
+)
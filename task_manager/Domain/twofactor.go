@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	TwoFactorCollection     = "two_factor"
+	TwoFactorRoleCollection = "two_factor_roles"
+)
+
+// TwoFactor is a user's TOTP (RFC 6238) enrollment. It is created pending
+// by enrollment and Enabled once the user proves their authenticator works
+// by entering a first code. Secret is the base32 shared secret. Only the
+// SHA-256 hashes of unused recovery codes are kept. LastUsedStep is the
+// time step of the last accepted code, so no code works twice.
+type TwoFactor struct {
+	UserID        string
+	Secret        string
+	Enabled       bool
+	RecoveryCodes []string
+	LastUsedStep  int64
+	CreatedAt     time.Time
+	EnabledAt     *time.Time
+}
+
+// TwoFactorEnrollment is what a user needs to add the account to an
+// authenticator app: the otpauth:// URI, also rendered as a QR code PNG,
+// and the secret itself for manual entry.
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+	QRCode []byte
+}
+
+// TwoFactorChallenge stands in for tokens when a password alone is not
+// enough to sign in. The token is exchanged, together with a code, at the
+// second login step. SetupRequired means the user's role requires 2FA but
+// the user has not enrolled yet, and must do so before signing in.
+type TwoFactorChallenge struct {
+	Token         string
+	ExpiresAt     time.Time
+	SetupRequired bool
+}
+
+// TwoFactorChallengeClaims are the verified contents of a challenge token.
+type TwoFactorChallengeClaims struct {
+	UserID string
+	// TokenVersion is the user's TokenVersion when the challenge was
+	// issued; changing the password or role voids outstanding challenges.
+	TokenVersion  int
+	SetupRequired bool
+	ExpiresAt     time.Time
+}
+
+// LoginResult is the outcome of a password login: either tokens, or a
+// challenge when a second factor is needed.
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge *TwoFactorChallenge
+}
+
+// TwoFactorLogin is the outcome of the second login step. RecoveryCodes is
+// only set when the step also completed a required enrollment.
+type TwoFactorLogin struct {
+	Tokens        *TokenPair
+	RecoveryCodes []string
+}
+
+type TwoFactorRepository interface {
+	// GetTwoFactor returns ErrTwoFactorNotEnrolled if the user has never
+	// started enrolling.
+	GetTwoFactor(c context.Context, userId string) (*TwoFactor, error)
+	// SaveTwoFactor creates or replaces the user's enrollment.
+	SaveTwoFactor(c context.Context, twoFactor *TwoFactor) error
+	DeleteTwoFactor(c context.Context, userId string) error
+	// UseTOTPStep atomically records step as used if it is later than the
+	// last used one and reports whether it did, so a code cannot be
+	// replayed, even concurrently.
+	UseTOTPStep(c context.Context, userId string, step int64) (bool, error)
+	// UseRecoveryCode atomically removes the recovery code hash and reports
+	// whether it was there.
+	UseRecoveryCode(c context.Context, userId string, codeHash string) (bool, error)
+}
+
+// TwoFactorRoleRepository stores which roles require their members to use
+// two-factor authentication.
+type TwoFactorRoleRepository interface {
+	GetTwoFactorRoles(c context.Context) ([]string, error)
+	SetTwoFactorRequired(c context.Context, role string, required bool) error
+}
+
+type TwoFactorUsecases interface {
+	// Enroll starts, or restarts, enrollment for the user. It fails with
+	// ErrTwoFactorAlreadyEnabled once 2FA is on.
+	Enroll(ctx context.Context, userId string) (*TwoFactorEnrollment, error)
+	// Confirm enables 2FA with a first code from the authenticator and
+	// returns the recovery codes, which are shown only this once.
+	Confirm(ctx context.Context, userId string, code string) ([]string, error)
+	// Disable turns 2FA off, given a current code or a recovery code.
+	Disable(ctx context.Context, userId string, code string) error
+	// RegenerateRecoveryCodes replaces the recovery codes, given a current
+	// code.
+	RegenerateRecoveryCodes(ctx context.Context, userId string, code string) ([]string, error)
+
+	// BeginLogin is called by Login once the password has been checked. It
+	// returns a challenge if the user must pass a second factor, or nil.
+	BeginLogin(ctx context.Context, user *User) (*TwoFactorChallenge, error)
+	// SetupLogin starts enrollment for a user holding a challenge that
+	// requires setup.
+	SetupLogin(ctx context.Context, challengeToken string) (*TwoFactorEnrollment, error)
+	// CompleteLogin checks a TOTP or recovery code against the challenge and
+	// issues tokens. Failures count towards the login lockout.
+	CompleteLogin(ctx context.Context, challengeToken, code, clientIP string) (*TwoFactorLogin, error)
+	// VerifyLogin checks a code against the challenge like CompleteLogin
+	// but returns the user instead of issuing tokens, for sign-ins on
+	// behalf of other applications. Challenges that require setup fail
+	// with ErrTwoFactorNotEnabled.
+	VerifyLogin(ctx context.Context, challengeToken, code, clientIP string) (*User, error)
+
+	GetTwoFactorRoles(ctx context.Context) ([]string, error)
+	SetRoleTwoFactorRequired(ctx context.Context, role string, required bool) error
+}
+
+// ITOTPService implements RFC 6238 time-based one-time passwords.
+type ITOTPService interface {
+	GenerateSecret() (string, error)
+	// KeyURI is the otpauth:// URI authenticator apps import.
+	KeyURI(accountName, secret string) string
+	// QRCode renders content as a PNG QR code.
+	QRCode(content string) ([]byte, error)
+	// Validate checks code against secret at time at, allowing for some
+	// clock drift, and returns the time step it matched.
+	Validate(secret, code string, at time.Time) (int64, bool)
+}
+
+// ITwoFactorChallengeService signs the short-lived challenge tokens issued
+// between the password and the code step of a login.
+type ITwoFactorChallengeService interface {
+	GenerateChallengeToken(user *User, setupRequired bool) (string, time.Time, error)
+	ParseChallengeToken(token string) (*TwoFactorChallengeClaims, error)
+}
+
+var (
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorRequired       = errors.New("two-factor authentication is required for this role")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+)
@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	WebhookCollection         = "webhooks"
+	WebhookDeliveryCollection = "webhook_deliveries"
+)
+
+// WebhookAllEvents subscribes a webhook to every event type, including ones
+// added later.
+const WebhookAllEvents = "*"
+
+// MinWebhookSecretLength is the shortest secret a webhook may be given.
+const MinWebhookSecretLength = 16
+
+// Webhook subscribes a URL to events. Each delivery is signed with Secret,
+// which is only shown when it is set.
+type Webhook struct {
+	ID     string
+	URL    string
+	Events []string
+	Secret string
+	// Inactive webhooks receive no new events.
+	Active    bool
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Subscribes reports whether the webhook wants events of eventType.
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == eventType || subscribed == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookUpdate changes some fields of a webhook; nil leaves a field as it
+// is. An empty Secret generates a new one.
+type WebhookUpdate struct {
+	URL    *string
+	Events []string
+	Secret *string
+	Active *bool
+}
+
+// Delivery states of a webhook event.
+const (
+	// WebhookPending deliveries are waiting to be sent, or to be retried
+	// once LeaseUntil has passed.
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	// WebhookDead deliveries ran out of attempts, or their webhook was
+	// deactivated before they went out. They stay in the log and can be
+	// redelivered.
+	WebhookDead = "dead"
+)
+
+// WebhookDelivery is one event sent, or to be sent, to one webhook. Payload
+// is the JSON body, fixed when the event is published so that retries and
+// redeliveries send the same bytes.
+//
+// Dispatchers claim pending deliveries by leasing them, as the reminder
+// scheduler does: LeaseOwner holds a delivery until LeaseUntil, and a
+// delivery whose holder dies is claimed again once the lease runs out, so
+// events are delivered at least once. Receivers can tell repeats apart by
+// the event ID in the payload.
+type WebhookDelivery struct {
+	ID        string
+	WebhookID string
+	EventID   string
+	EventType string
+	Payload   string
+	Status    string
+	Attempts  int
+	// LeaseOwner and LeaseUntil are as on ReminderDelivery; LeaseUntil is
+	// also the retry time after a failed attempt.
+	LeaseOwner string
+	LeaseUntil time.Time
+	LastError  string
+	// ResponseStatus is the HTTP status of the last attempt, or 0 if no
+	// response came back.
+	ResponseStatus int
+	// RedeliveryOf is the delivery this one repeats, if it was redelivered
+	// by hand.
+	RedeliveryOf string
+	CreatedAt    time.Time
+	DeliveredAt  *time.Time
+}
+
+// WebhookSender posts a delivery's payload to a webhook, signed with the
+// webhook's secret. It returns the response status, and an error only if
+// no response came back.
+type WebhookSender interface {
+	Send(ctx context.Context, webhook *Webhook, delivery *WebhookDelivery) (int, error)
+}
+
+type WebhookRepository interface {
+	CreateWebhook(c context.Context, webhook *Webhook) error
+	GetWebhookByID(c context.Context, webhookId string) (*Webhook, error)
+	// GetWebhooks lists all webhooks, oldest first.
+	GetWebhooks(c context.Context) ([]*Webhook, error)
+	// GetWebhooksForEvent lists the active webhooks subscribed to
+	// eventType, directly or through WebhookAllEvents.
+	GetWebhooksForEvent(c context.Context, eventType string) ([]*Webhook, error)
+	// UpdateWebhook stores every field of the webhook but its ID, CreatedBy
+	// and CreatedAt.
+	UpdateWebhook(c context.Context, webhook *Webhook) error
+	DeleteWebhook(c context.Context, webhookId string) error
+}
+
+type WebhookDeliveryRepository interface {
+	// CreateWebhookDelivery stores a new delivery. A delivery already
+	// stored under the same ID is left as it is, and that is not an error.
+	CreateWebhookDelivery(c context.Context, delivery *WebhookDelivery) error
+	GetWebhookDeliveryByID(c context.Context, deliveryId string) (*WebhookDelivery, error)
+	// GetWebhookDeliveries lists up to limit of a webhook's deliveries,
+	// newest first.
+	GetWebhookDeliveries(c context.Context, webhookId string, limit int) ([]*WebhookDelivery, error)
+	// ClaimWebhookDeliveries leases up to limit pending deliveries whose
+	// LeaseUntil is not after now to owner until leaseUntil, oldest first,
+	// counting an attempt on each. A delivery is only ever handed to one
+	// claimant per lease. On error it still returns the deliveries it
+	// claimed, which the caller has to finish.
+	ClaimWebhookDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*WebhookDelivery, error)
+	// FinishWebhookDelivery stores the Status, Attempts, LeaseUntil,
+	// LastError, ResponseStatus and DeliveredAt of a delivery claimed by
+	// owner and releases the lease. It fails with ErrWebhookLeaseLost if
+	// owner no longer holds it.
+	FinishWebhookDelivery(c context.Context, delivery *WebhookDelivery, owner string) error
+	// DeleteWebhookDeliveries removes all deliveries of a webhook.
+	DeleteWebhookDeliveries(c context.Context, webhookId string) error
+}
+
+// NewWebhook is a webhook just created or given a new secret, with the
+// secret to hand to the receiver.
+type NewWebhook struct {
+	Webhook *Webhook
+	Secret  string
+}
+
+// WebhookUsecases manage webhook subscriptions and their delivery log.
+type WebhookUsecases interface {
+	ListWebhooks(ctx context.Context) ([]*Webhook, error)
+	GetWebhook(ctx context.Context, webhookId string) (*Webhook, error)
+	// CreateWebhook subscribes url to events. An empty secret generates
+	// one. New webhooks are active.
+	CreateWebhook(ctx context.Context, url string, events []string, secret string, actor *Actor) (*NewWebhook, error)
+	// UpdateWebhook returns the new secret only if it was changed.
+	UpdateWebhook(ctx context.Context, webhookId string, update WebhookUpdate) (*NewWebhook, error)
+	// DeleteWebhook removes the webhook and its delivery log.
+	DeleteWebhook(ctx context.Context, webhookId string) error
+	// ListDeliveries returns up to limit of a webhook's deliveries, newest
+	// first; 0 means a default page.
+	ListDeliveries(ctx context.Context, webhookId string, limit int) ([]*WebhookDelivery, error)
+	// Redeliver queues a new delivery of the same payload. Any delivery can
+	// be redelivered, including ones that succeeded.
+	Redeliver(ctx context.Context, webhookId string, deliveryId string) (*WebhookDelivery, error)
+}
+
+// WebhookDispatcherConfig tunes the webhook dispatcher.
+type WebhookDispatcherConfig struct {
+	// PollInterval is how often pending deliveries are looked for.
+	PollInterval time.Duration
+	// Lease is how long a claimed delivery is held before another
+	// dispatcher may take it over. Requests time out after half of it.
+	Lease time.Duration
+	// MaxAttempts is how often a delivery is tried before it is dead, with
+	// RetryBackoff doubling between attempts.
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// Workers is how many deliveries are sent at once, and BatchSize how
+	// many are claimed at a time.
+	Workers   int
+	BatchSize int
+}
+
+// DefaultWebhookDispatcher keeps retrying for about an hour.
+var DefaultWebhookDispatcher = WebhookDispatcherConfig{
+	PollInterval: 5 * time.Second,
+	Lease:        time.Minute,
+	MaxAttempts:  8,
+	RetryBackoff: 30 * time.Second,
+	Workers:      4,
+	BatchSize:    50,
+}
+
+// WebhookDispatcher sends pending webhook deliveries. Several instances may
+// run against the same store; each delivery is claimed by one of them at a
+// time.
+type WebhookDispatcher interface {
+	// DispatchPending sends every pending delivery that can be claimed.
+	DispatchPending(ctx context.Context) error
+	// Run calls DispatchPending every PollInterval until ctx is cancelled,
+	// and returns once the requests in progress have finished.
+	Run(ctx context.Context)
+}
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookAlreadyExists    = errors.New("webhook already exists")
+	ErrInvalidWebhook          = errors.New("invalid webhook")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookLeaseLost        = errors.New("webhook delivery lease lost")
+)
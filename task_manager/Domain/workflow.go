@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Statuses of the default workflow.
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusBlocked    = "blocked"
+	StatusDone       = "done"
+	StatusCancelled  = "cancelled"
+)
+
+// WorkflowStatus is one named state a task can be in. Entering a status with
+// StartsWork stamps Task.StartedAt (once); entering one with CompletesWork
+// stamps Task.CompletedAt, which is cleared again when the task leaves it.
+// ClosesWork marks statuses, like cancelled, that end work on a task
+// without completing it.
+type WorkflowStatus struct {
+	Name          string `json:"name"`
+	StartsWork    bool   `json:"starts_work"`
+	CompletesWork bool   `json:"completes_work"`
+	ClosesWork    bool   `json:"closes_work"`
+}
+
+// Workflow is the set of statuses a task may take and the transitions
+// allowed between them. Build one with NewWorkflow or DefaultWorkflow.
+type Workflow struct {
+	Initial     string              `json:"initial"`
+	Statuses    []WorkflowStatus    `json:"statuses"`
+	Transitions map[string][]string `json:"transitions"`
+	// Aliases maps legacy or alternative spellings onto status names.
+	Aliases map[string]string `json:"aliases"`
+
+	byName map[string]WorkflowStatus
+}
+
+var ErrInvalidStatus = errors.New("invalid status")
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// InvalidTransitionError is returned when a task may not move from one status
+// to another. It matches ErrInvalidTransition with errors.Is.
+type InvalidTransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot move task from %q to %q (allowed: %s)", e.From, e.To, strings.Join(e.Allowed, ", "))
+}
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// DefaultWorkflow is todo → in_progress → done, where any open task can be
+// blocked or cancelled, done tasks can be reopened and cancelled tasks
+// restored. The statuses used before workflows existed are kept as aliases.
+func DefaultWorkflow() *Workflow {
+	w, err := NewWorkflow(Workflow{
+		Initial: StatusTodo,
+		Statuses: []WorkflowStatus{
+			{Name: StatusTodo},
+			{Name: StatusInProgress, StartsWork: true},
+			{Name: StatusBlocked},
+			{Name: StatusDone, StartsWork: true, CompletesWork: true},
+			{Name: StatusCancelled, ClosesWork: true},
+		},
+		Transitions: map[string][]string{
+			StatusTodo:       {StatusInProgress, StatusBlocked, StatusCancelled},
+			StatusInProgress: {StatusDone, StatusBlocked, StatusTodo, StatusCancelled},
+			StatusBlocked:    {StatusTodo, StatusInProgress, StatusCancelled},
+			StatusDone:       {StatusInProgress},
+			StatusCancelled:  {StatusTodo},
+		},
+		Aliases: map[string]string{
+			"pending":   StatusTodo,
+			"open":      StatusTodo,
+			"completed": StatusDone,
+			"canceled":  StatusCancelled,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// NewWorkflow validates a workflow definition: the initial status, every
+// transition and every alias must refer to a declared status.
+func NewWorkflow(def Workflow) (*Workflow, error) {
+	w := def
+	w.byName = make(map[string]WorkflowStatus, len(def.Statuses))
+	for _, st := range def.Statuses {
+		if st.Name == "" || st.Name != normalizeStatusName(st.Name) {
+			return nil, fmt.Errorf("workflow: status name %q must be lower_snake_case", st.Name)
+		}
+		if _, dup := w.byName[st.Name]; dup {
+			return nil, fmt.Errorf("workflow: duplicate status %q", st.Name)
+		}
+		w.byName[st.Name] = st
+	}
+	if _, ok := w.byName[w.Initial]; !ok {
+		return nil, fmt.Errorf("workflow: initial status %q is not declared", w.Initial)
+	}
+	for from, targets := range w.Transitions {
+		if _, ok := w.byName[from]; !ok {
+			return nil, fmt.Errorf("workflow: transition from undeclared status %q", from)
+		}
+		for _, to := range targets {
+			if _, ok := w.byName[to]; !ok {
+				return nil, fmt.Errorf("workflow: transition from %q to undeclared status %q", from, to)
+			}
+		}
+	}
+	aliases := make(map[string]string, len(def.Aliases))
+	for alias, target := range def.Aliases {
+		if _, ok := w.byName[target]; !ok {
+			return nil, fmt.Errorf("workflow: alias %q points to undeclared status %q", alias, target)
+		}
+		aliases[normalizeStatusName(alias)] = target
+	}
+	w.Aliases = aliases
+	return &w, nil
+}
+
+// Normalize resolves user input such as "In Progress" or a legacy alias like
+// "pending" to a declared status name.
+func (w *Workflow) Normalize(status string) (string, error) {
+	name := normalizeStatusName(status)
+	if target, ok := w.Aliases[name]; ok {
+		name = target
+	}
+	if _, ok := w.byName[name]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+	return name, nil
+}
+
+// CanTransition reports whether the workflow allows moving from one declared
+// status directly to another.
+func (w *Workflow) CanTransition(from, to string) bool {
+	for _, allowed := range w.Transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen reports whether work on a task in status is still outstanding:
+// the status neither completes nor closes work. Statuses the workflow does
+// not know count as open.
+func (w *Workflow) IsOpen(status string) bool {
+	st := w.byName[status]
+	return !st.CompletesWork && !st.ClosesWork
+}
+
+// IsComplete reports whether status completes work.
+func (w *Workflow) IsComplete(status string) bool {
+	return w.byName[status].CompletesWork
+}
+
+// StartsWork reports whether entering status starts work on a task.
+func (w *Workflow) StartsWork(status string) bool {
+	return w.byName[status].StartsWork
+}
+
+// Enter puts a new task into status (the initial status when empty) and
+// stamps its timestamps as of now.
+func (w *Workflow) Enter(task *Task, status string, now time.Time) error {
+	if status == "" {
+		status = w.Initial
+	}
+	name, err := w.Normalize(status)
+	if err != nil {
+		return err
+	}
+	task.Status = name
+	task.StartedAt, task.CompletedAt = nil, nil
+	w.stamp(task, now)
+	return nil
+}
+
+// Transition moves task to status if the workflow allows it, recording when
+// work started and completed. Moving to the current status is a no-op. A
+// stored status the workflow does not know, from before workflows were
+// configured, is treated as the initial status.
+func (w *Workflow) Transition(task *Task, status string, now time.Time) error {
+	to, err := w.Normalize(status)
+	if err != nil {
+		return err
+	}
+	from, err := w.Normalize(task.Status)
+	if err != nil {
+		from = w.Initial
+	}
+	if from == to {
+		task.Status = to
+		return nil
+	}
+	if !w.CanTransition(from, to) {
+		return &InvalidTransitionError{From: from, To: to, Allowed: w.Transitions[from]}
+	}
+	task.Status = to
+	w.stamp(task, now)
+	return nil
+}
+
+// Force moves task to status whether or not the workflow allows the
+// transition, stamping its timestamps as Transition would. It is for moves
+// that follow from another task's, like a parent carrying its subtasks
+// along when it is completed.
+func (w *Workflow) Force(task *Task, status string, now time.Time) error {
+	to, err := w.Normalize(status)
+	if err != nil {
+		return err
+	}
+	if task.Status == to {
+		return nil
+	}
+	task.Status = to
+	w.stamp(task, now)
+	return nil
+}
+
+func (w *Workflow) stamp(task *Task, now time.Time) {
+	st := w.byName[task.Status]
+	if st.StartsWork && task.StartedAt == nil {
+		task.StartedAt = &now
+	}
+	if st.CompletesWork {
+		task.CompletedAt = &now
+	} else {
+		task.CompletedAt = nil
+	}
+}
+
+func normalizeStatusName(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(status)
+}
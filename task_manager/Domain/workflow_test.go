@@ -0,0 +1,124 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultWorkflow_Normalize(t *testing.T) {
+	w := domain.DefaultWorkflow()
+
+	for input, want := range map[string]string{
+		"todo":        domain.StatusTodo,
+		"pending":     domain.StatusTodo,
+		"In Progress": domain.StatusInProgress,
+		"in-progress": domain.StatusInProgress,
+		"Completed":   domain.StatusDone,
+	} {
+		got, err := w.Normalize(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := w.Normalize("whenever")
+	assert.ErrorIs(t, err, domain.ErrInvalidStatus)
+}
+
+func TestWorkflow_TransitionStampsTimes(t *testing.T) {
+	w := domain.DefaultWorkflow()
+	t0 := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	task := &domain.Task{}
+
+	require.NoError(t, w.Enter(task, "", t0))
+	assert.Equal(t, domain.StatusTodo, task.Status)
+	assert.Nil(t, task.StartedAt)
+
+	require.NoError(t, w.Transition(task, "in progress", t0.Add(time.Hour)))
+	assert.Equal(t, t0.Add(time.Hour), *task.StartedAt)
+	assert.Nil(t, task.CompletedAt)
+
+	require.NoError(t, w.Transition(task, domain.StatusDone, t0.Add(2*time.Hour)))
+	assert.Equal(t, t0.Add(2*time.Hour), *task.CompletedAt)
+
+	// Reopening clears the completion but keeps the original start.
+	require.NoError(t, w.Transition(task, domain.StatusInProgress, t0.Add(3*time.Hour)))
+	assert.Nil(t, task.CompletedAt)
+	assert.Equal(t, t0.Add(time.Hour), *task.StartedAt)
+}
+
+func TestWorkflow_RejectsIllegalTransition(t *testing.T) {
+	w := domain.DefaultWorkflow()
+	task := &domain.Task{Status: domain.StatusTodo}
+
+	err := w.Transition(task, domain.StatusDone, time.Now())
+
+	var transitionErr *domain.InvalidTransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	assert.Equal(t, domain.StatusTodo, transitionErr.From)
+	assert.Equal(t, domain.StatusDone, transitionErr.To)
+	assert.Equal(t, domain.StatusTodo, task.Status, "a rejected transition must not change the task")
+}
+
+func TestWorkflow_LegacyStatusTreatedAsInitial(t *testing.T) {
+	w := domain.DefaultWorkflow()
+	task := &domain.Task{Status: "someday maybe"}
+
+	assert.NoError(t, w.Transition(task, domain.StatusInProgress, time.Now()))
+	assert.Equal(t, domain.StatusInProgress, task.Status)
+}
+
+func TestNewWorkflow_Validation(t *testing.T) {
+	statuses := []domain.WorkflowStatus{{Name: "open"}, {Name: "closed", CompletesWork: true}}
+
+	_, err := domain.NewWorkflow(domain.Workflow{Initial: "missing", Statuses: statuses})
+	assert.Error(t, err)
+
+	_, err = domain.NewWorkflow(domain.Workflow{Initial: "open", Statuses: statuses,
+		Transitions: map[string][]string{"open": {"archived"}}})
+	assert.Error(t, err)
+
+	_, err = domain.NewWorkflow(domain.Workflow{Initial: "open", Statuses: append(statuses, domain.WorkflowStatus{Name: "Open Later"})})
+	assert.Error(t, err)
+
+	w, err := domain.NewWorkflow(domain.Workflow{Initial: "open", Statuses: statuses,
+		Transitions: map[string][]string{"open": {"closed"}}, Aliases: map[string]string{"Pending": "open"}})
+	require.NoError(t, err)
+	got, err := w.Normalize("pending")
+	assert.NoError(t, err)
+	assert.Equal(t, "open", got)
+	assert.True(t, w.CanTransition("open", "closed"))
+	assert.False(t, w.CanTransition("closed", "open"))
+}
+
+func TestWorkflow_OpenStatuses(t *testing.T) {
+	w := domain.DefaultWorkflow()
+
+	assert.True(t, w.IsOpen(domain.StatusTodo))
+	assert.True(t, w.IsOpen(domain.StatusBlocked))
+	assert.True(t, w.IsOpen("someday maybe"))
+	assert.False(t, w.IsOpen(domain.StatusDone))
+	assert.False(t, w.IsOpen(domain.StatusCancelled))
+	assert.True(t, w.IsComplete(domain.StatusDone))
+	assert.False(t, w.IsComplete(domain.StatusCancelled))
+	assert.True(t, w.StartsWork(domain.StatusInProgress))
+	assert.False(t, w.StartsWork(domain.StatusBlocked))
+}
+
+func TestWorkflow_ForceSkipsTransitionRules(t *testing.T) {
+	w := domain.DefaultWorkflow()
+	now := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	task := &domain.Task{Status: domain.StatusTodo}
+
+	require.NoError(t, w.Force(task, domain.StatusDone, now))
+	assert.Equal(t, domain.StatusDone, task.Status)
+	assert.Equal(t, now, *task.StartedAt)
+	assert.Equal(t, now, *task.CompletedAt)
+
+	assert.ErrorIs(t, w.Force(task, "whenever", now), domain.ErrInvalidStatus)
+}
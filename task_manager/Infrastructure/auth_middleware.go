@@ -1,29 +1,28 @@
 package infrastructure
 
 import (
+	"errors"
 	"net/http"
-	"os"
 	"strings"
 	domain "task_manager/Domain"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
 
 // UserContextKey is the key used to store user information in the Gin context
 const UserContextKey = "user"
 
-// UserClaims represents the claims in the JWT token
-type UserClaims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
-	jwt.StandardClaims
-}
+// ClaimsContextKey is the key under which the verified access token claims
+// are stored in the Gin context, for handlers such as logout.
+const ClaimsContextKey = "claims"
+
+// PersonalAccessTokenContextKey is where the *domain.PersonalAccessToken
+// is stored when a request authenticates with one instead of a JWT.
+const PersonalAccessTokenContextKey = "personal_access_token"
 
-// AuthMiddleware validates JWT tokens and sets user information in the context
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware validates JWT tokens and personal access tokens and sets
+// user information in the context
+func AuthMiddleware(tokens domain.TokenUsecases, personalTokens domain.PersonalAccessTokenUsecases) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -43,75 +42,50 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Extract the token
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// Parse and validate the JWT token
-		token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-			// Validate the signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
+		if strings.HasPrefix(tokenString, domain.PersonalAccessTokenPrefix) {
+			user, token, err := personalTokens.Authenticate(c, tokenString)
+			if err != nil {
+				respondAuthError(c, err)
+				return
 			}
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			c.Set(UserContextKey, user)
+			c.Set(PersonalAccessTokenContextKey, token)
+			c.Next()
 			return
 		}
 
-		// Check if the token is valid
-		if !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
-		}
-
-		// Extract claims
-		claims, ok := token.Claims.(*UserClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
+		// Verify the token and check it against the revocation list
+		claims, err := tokens.Authenticate(c, tokenString)
+		if err != nil {
+			respondAuthError(c, err)
 			return
 		}
 
 		// Create user object from claims
 		user := &domain.User{
-			ID:       claims.UserID,
-			Username: claims.Username,
-			Email:    claims.Email,
-			Role:     claims.Role,
+			ID:              claims.UserID,
+			Username:        claims.Username,
+			Email:           claims.Email,
+			Role:            claims.Role,
+			EmailUnverified: claims.EmailUnverified,
 		}
 
 		// Set user in context
 		c.Set(UserContextKey, user)
+		c.Set(ClaimsContextKey, claims)
 		c.Next()
 	}
 }
 
-// AdminMiddleware checks if the user in context has admin role
-func AdminMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get user from context (set by AuthMiddleware)
-		userInterface, exists := c.Get(UserContextKey)
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context. AuthMiddleware must be called first"})
-			c.Abort()
-			return
-		}
-
-		user, ok := userInterface.(*domain.User)
-		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user type in context"})
-			c.Abort()
-			return
-		}
-
-		// Check if user has admin role
-		if user.Role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-			c.Abort()
-			return
-		}
-
-		c.Next()
+func respondAuthError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrAccountDisabled) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+	} else if errors.Is(err, domain.ErrTokenRevoked) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+	} else if errors.Is(err, domain.ErrInvalidToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify token"})
 	}
+	c.Abort()
 }
@@ -1,43 +1,109 @@
 package infrastructure
 
 import (
-	"log"
-	"os"
+	"context"
 	"time"
 
 	domain "task_manager/Domain"
 
-	"github.com/joho/godotenv"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
+// UserClaims represents the claims in the JWT token
+type UserClaims struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	Version  int    `json:"ver"`
+	jwt.StandardClaims
+}
+
 type JWTService struct {
+	keys      *KeyRing
+	accessTTL time.Duration
 }
 
-func NewJWTService() domain.IJWTService {
-	return &JWTService{}
+// NewJWTService signs access tokens with the active key of keys, naming it
+// in the "kid" header, and makes them expire after accessTTL. Every token
+// carries a unique jti so it can be revoked.
+func NewJWTService(keys *KeyRing, accessTTL time.Duration) domain.IJWTService {
+	return &JWTService{
+		keys:      keys,
+		accessTTL: accessTTL,
+	}
 }
 
 func (js *JWTService) GenerateToken(user *domain.User) (string, error) {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
-	}
-	var jwtSecret = os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT_SECRET environment variable is not set")
+	key, err := js.keys.signingKey()
+	if err != nil {
+		return "", err
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"email":    user.Email,
-		"role":     user.Role,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.record.Algorithm), UserClaims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+		Version:  user.TokenVersion,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.New().String(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(js.accessTTL).Unix(),
+		},
 	})
-	
-	jwtToken, err := token.SignedString([]byte(jwtSecret))
+	token.Header["kid"] = key.record.ID
+
+	jwtToken, err := token.SignedString(key.private)
 	if err != nil {
 		return "", err
 	}
 	return jwtToken, nil
-}
\ No newline at end of file
+}
+
+// ParseToken verifies the signature and expiry of an access token against
+// the key named by its "kid" header.
+func (js *JWTService) ParseToken(tokenString string) (*domain.AccessClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, verificationKeyFunc(js.keys))
+	if err != nil || !token.Valid {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// Tokens issued to OIDC clients are signed with the same keys but name
+	// the client as audience; they are not for this API.
+	claims, ok := token.Claims.(*UserClaims)
+	if !ok || claims.Id == "" || claims.Audience != "" {
+		return nil, domain.ErrInvalidToken
+	}
+	return &domain.AccessClaims{
+		TokenID:      claims.Id,
+		UserID:       claims.UserID,
+		Username:     claims.Username,
+		Email:        claims.Email,
+		Role:         claims.Role,
+		TokenVersion: claims.Version,
+		ExpiresAt:    time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// verificationKeyFunc resolves the key a token was signed with from its
+// "kid" header.
+func verificationKeyFunc(keys *KeyRing) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		record, public, ok := keys.verificationKey(kid)
+		if !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		// Validate the signing method against the key's, never the header's
+		if token.Method.Alg() != record.Algorithm {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return public, nil
+	}
+}
+
+func (js *JWTService) PublicKeys(ctx context.Context) ([]*domain.JSONWebKey, error) {
+	return js.keys.PublicKeys(ctx)
+}
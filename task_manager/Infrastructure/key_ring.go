@@ -0,0 +1,284 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+// keyRingPurpose derives the key that seals private signing keys at rest
+// from JWT_SECRET.
+const keyRingPurpose = "signing_keys"
+
+// keyRingRefresh is how often the ring reloads its keys, picking up keys
+// created by other instances. An unknown kid triggers a reload sooner, but
+// no more than once per keyRingMissRefresh.
+const (
+	keyRingRefresh     = time.Minute
+	keyRingMissRefresh = 5 * time.Second
+	keyRingTimeout     = 5 * time.Second
+)
+
+// KeyRing holds the asymmetric keys access tokens are signed with. Keys are
+// stored through a SigningKeyRepository so that every instance signs and
+// verifies with the same ring, and are rotated lazily as the policy
+// schedules it. Private keys are encrypted with a key derived from secret
+// before they are stored.
+type KeyRing struct {
+	repository domain.SigningKeyRepository
+	algorithm  string
+	policy     domain.KeyRotationPolicy
+	sealer     cipher.AEAD
+
+	mu       sync.Mutex
+	keys     []*ringKey
+	loadedAt time.Time
+}
+
+// ringKey is a stored key with its private half unsealed.
+type ringKey struct {
+	record  *domain.SigningKey
+	private crypto.Signer
+}
+
+// NewKeyRing creates keys of algorithm, RS256 or EdDSA, on the schedule of
+// policy.
+func NewKeyRing(repository domain.SigningKeyRepository, secret string, algorithm string, policy domain.KeyRotationPolicy) (*KeyRing, error) {
+	if algorithm != domain.SigningAlgorithmRS256 && algorithm != domain.SigningAlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q; use RS256 or EdDSA", algorithm)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(keyRingPurpose))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	sealer, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyRing{
+		repository: repository,
+		algorithm:  algorithm,
+		policy:     policy,
+		sealer:     sealer,
+	}, nil
+}
+
+// signingKey returns the key to sign new tokens with, creating the next
+// key first if one is due.
+func (kr *KeyRing) signingKey() (*ringKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keyRingTimeout)
+	defer cancel()
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	now := time.Now()
+	if err := kr.refresh(ctx, now, false); err != nil {
+		return nil, err
+	}
+	if notBefore, retiresAt, due := kr.policy.Next(kr.records(), now); due {
+		// Two instances may both create a key here; that only means the
+		// ring briefly has one more key than it needs.
+		key, err := kr.create(ctx, now, notBefore, retiresAt)
+		if err != nil {
+			return nil, err
+		}
+		kr.keys = append(kr.keys, key)
+	}
+
+	active := kr.policy.Active(kr.records(), now)
+	for _, key := range kr.keys {
+		if key.record == active {
+			return key, nil
+		}
+	}
+	return nil, errors.New("key ring has no active signing key")
+}
+
+// verificationKey returns the public key with id kid, if it has not
+// expired.
+func (kr *KeyRing) verificationKey(kid string) (*domain.SigningKey, crypto.PublicKey, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), keyRingTimeout)
+	defer cancel()
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	now := time.Now()
+	if err := kr.refresh(ctx, now, false); err != nil {
+		return nil, nil, false
+	}
+	key := kr.find(kid, now)
+	if key == nil && now.Sub(kr.loadedAt) >= keyRingMissRefresh {
+		// The key may have been created by another instance.
+		if err := kr.refresh(ctx, now, true); err != nil {
+			return nil, nil, false
+		}
+		key = kr.find(kid, now)
+	}
+	if key == nil {
+		return nil, nil, false
+	}
+	return key.record, key.private.Public(), true
+}
+
+// PublicKeys returns every key that has not expired as a JWK, including
+// keys that have not started signing yet.
+func (kr *KeyRing) PublicKeys(ctx context.Context) ([]*domain.JSONWebKey, error) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	now := time.Now()
+	if err := kr.refresh(ctx, now, false); err != nil {
+		return nil, err
+	}
+	var jwks []*domain.JSONWebKey
+	for _, key := range kr.keys {
+		if !now.Before(key.record.ExpiresAt) {
+			continue
+		}
+		jwks = append(jwks, toJSONWebKey(key.record, key.private.Public()))
+	}
+	return jwks, nil
+}
+
+func (kr *KeyRing) find(kid string, now time.Time) *ringKey {
+	for _, key := range kr.keys {
+		if key.record.ID == kid && now.Before(key.record.ExpiresAt) {
+			return key
+		}
+	}
+	return nil
+}
+
+func (kr *KeyRing) records() []*domain.SigningKey {
+	records := make([]*domain.SigningKey, 0, len(kr.keys))
+	for _, key := range kr.keys {
+		records = append(records, key.record)
+	}
+	return records
+}
+
+// refresh reloads the keys from the repository when they are older than
+// keyRingRefresh, or when force is set. kr.mu must be held.
+func (kr *KeyRing) refresh(ctx context.Context, now time.Time, force bool) error {
+	if !force && !kr.loadedAt.IsZero() && now.Sub(kr.loadedAt) < keyRingRefresh {
+		return nil
+	}
+	records, err := kr.repository.GetSigningKeys(ctx, now)
+	if err != nil {
+		return err
+	}
+	keys := make([]*ringKey, 0, len(records))
+	for _, record := range records {
+		private, err := kr.unseal(record)
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", record.ID, err)
+		}
+		keys = append(keys, &ringKey{record: record, private: private})
+	}
+	kr.keys = keys
+	kr.loadedAt = now
+	return nil
+}
+
+func (kr *KeyRing) create(ctx context.Context, now, notBefore, retiresAt time.Time) (*ringKey, error) {
+	var private crypto.Signer
+	var err error
+	switch kr.algorithm {
+	case domain.SigningAlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	record := &domain.SigningKey{
+		ID:        uuid.New().String(),
+		Algorithm: kr.algorithm,
+		CreatedAt: now,
+		NotBefore: notBefore,
+		RetiresAt: retiresAt,
+		ExpiresAt: kr.policy.Expiry(retiresAt),
+	}
+	if record.PrivateKey, err = kr.seal(record.ID, der); err != nil {
+		return nil, err
+	}
+	if err := kr.repository.CreateSigningKey(ctx, record); err != nil {
+		return nil, err
+	}
+	return &ringKey{record: record, private: private}, nil
+}
+
+// seal encrypts a PKCS #8 private key, binding it to its kid. The nonce is
+// prepended to the ciphertext.
+func (kr *KeyRing) seal(kid string, der []byte) ([]byte, error) {
+	nonce := make([]byte, kr.sealer.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return kr.sealer.Seal(nonce, nonce, der, []byte(kid)), nil
+}
+
+func (kr *KeyRing) unseal(record *domain.SigningKey) (crypto.Signer, error) {
+	size := kr.sealer.NonceSize()
+	if len(record.PrivateKey) < size {
+		return nil, errors.New("sealed private key is truncated")
+	}
+	der, err := kr.sealer.Open(nil, record.PrivateKey[:size], record.PrivateKey[size:], []byte(record.ID))
+	if err != nil {
+		return nil, errors.New("cannot decrypt private key; was JWT_SECRET changed?")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot sign")
+	}
+	return signer, nil
+}
+
+func toJSONWebKey(record *domain.SigningKey, public crypto.PublicKey) *domain.JSONWebKey {
+	jwk := &domain.JSONWebKey{KeyID: record.ID, Algorithm: record.Algorithm, Use: "sig"}
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	}
+	return jwk
+}
@@ -0,0 +1,186 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// SMTPMailer delivers mail through an SMTP relay, upgrading to TLS when the
+// server offers STARTTLS.
+type SMTPMailer struct {
+	host string
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer sends as from through host:port. Without a username the
+// relay is used unauthenticated; with one, PLAIN auth is used, which
+// net/smtp only allows over TLS or to localhost.
+func NewSMTPMailer(host string, port int, username, password, from string) domain.IMailer {
+	mailer := &SMTPMailer{
+		host: host,
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+	}
+	if username != "" {
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer
+}
+
+func (sm *SMTPMailer) Send(ctx context.Context, message *domain.MailMessage) error {
+	raw, err := formatMailMessage(sm.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", sm.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, sm.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: sm.host}); err != nil {
+			return err
+		}
+	}
+	if sm.auth != nil {
+		if err := client.Auth(sm.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(sm.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// MaildirMailer "delivers" mail by writing each message into a Maildir, so
+// the whole flow can be followed on a development machine with any mail
+// client, or by reading the files in dir/new.
+type MaildirMailer struct {
+	dir  string
+	from string
+}
+
+// maildirSequence keeps file names unique within this process.
+var maildirSequence atomic.Uint64
+
+// NewMaildirMailer creates dir/tmp, dir/new and dir/cur if they are missing.
+func NewMaildirMailer(dir, from string) (domain.IMailer, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, err
+		}
+	}
+	return &MaildirMailer{dir: dir, from: from}, nil
+}
+
+func (mm *MaildirMailer) Send(ctx context.Context, message *domain.MailMessage) error {
+	now := time.Now()
+	raw, err := formatMailMessage(mm.from, message, now)
+	if err != nil {
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	hostname = strings.NewReplacer("/", "\\057", ":", "\\072").Replace(hostname)
+	name := fmt.Sprintf("%d.%d_%d.%s", now.Unix(), os.Getpid(), maildirSequence.Add(1), hostname)
+
+	// Messages are written to tmp and renamed into new, so readers never
+	// see a partial file.
+	tmp := filepath.Join(mm.dir, "tmp", name)
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(mm.dir, "new", name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// formatMailMessage renders an RFC 5322 message with a quoted-printable
+// UTF-8 body. Addresses and the subject must not contain line breaks,
+// which would let them inject headers.
+func formatMailMessage(from string, message *domain.MailMessage, now time.Time) ([]byte, error) {
+	if strings.ContainsAny(from+message.To+message.Subject, "\r\n") {
+		return nil, domain.ErrInvalidMessage
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sender: %v", domain.ErrInvalidMessage, err)
+	}
+	if _, err := mail.ParseAddress(message.To); err != nil {
+		return nil, fmt.Errorf("%w: recipient: %v", domain.ErrInvalidMessage, err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domainPart := sender.Address[strings.LastIndex(sender.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sender.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domainPart)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := strings.ReplaceAll(message.Body, "\r\n", "\n")
+	body = strings.ReplaceAll(body, "\n", "\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\r\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,68 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// EmailNotifier sends reminders to the user's address through an IMailer.
+type EmailNotifier struct {
+	mailer domain.IMailer
+}
+
+func NewEmailNotifier(mailer domain.IMailer) domain.Notifier {
+	return &EmailNotifier{mailer: mailer}
+}
+
+func (en *EmailNotifier) Notify(ctx context.Context, notice *domain.ReminderNotice) error {
+	due := reminderDueText(notice.Task)
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", notice.User.Username)
+	fmt.Fprintf(&body, "Your task %q is due %s.\n", notice.Task.Title, due)
+	if notice.Task.Description != "" {
+		fmt.Fprintf(&body, "\n%s\n", notice.Task.Description)
+	}
+	body.WriteString("\nYou are receiving this because you set up a reminder in Task Manager.\n")
+
+	return en.mailer.Send(ctx, &domain.MailMessage{
+		To:      notice.User.Email,
+		Subject: "Reminder: " + notice.Task.Title,
+		Body:    body.String(),
+	})
+}
+
+// LogNotifier writes reminders to a log instead of sending them, for
+// development and as a fallback channel.
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier logs to logger, or the standard logger when it is nil.
+func NewLogNotifier(logger *log.Logger) domain.Notifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogNotifier{logger: logger}
+}
+
+func (ln *LogNotifier) Notify(ctx context.Context, notice *domain.ReminderNotice) error {
+	ln.logger.Printf("Reminder for %s: task %s %q is due %s", notice.User.Username, notice.Task.ID, notice.Task.Title, reminderDueText(notice.Task))
+	return nil
+}
+
+// reminderDueText renders a task's due date in the time zone of its series,
+// or UTC for one-off tasks.
+func reminderDueText(task *domain.Task) string {
+	loc := time.UTC
+	if task.Recurrence != nil {
+		if tz, err := domain.LoadTimeZone(task.Recurrence.TimeZone); err == nil {
+			loc = tz
+		}
+	}
+	return task.DueDate.In(loc).Format("Mon, 02 Jan 2006 15:04 MST")
+}
@@ -0,0 +1,131 @@
+package infrastructure
+
+import (
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+)
+
+// oidcAccessTokenType is the "typ" header of access tokens issued to OIDC
+// clients (RFC 9068), which tells them apart from ID tokens: both name the
+// client as audience.
+const oidcAccessTokenType = "at+jwt"
+
+// IDTokenClaims are the claims of an ID token. The profile and email
+// claims are only present when the matching scope was granted.
+type IDTokenClaims struct {
+	Nonce             string `json:"nonce,omitempty"`
+	AuthTime          int64  `json:"auth_time"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+	jwt.StandardClaims
+}
+
+// OIDCAccessTokenClaims are the claims of an access token issued to an
+// OIDC client. Version is the user's TokenVersion, as in UserClaims.
+type OIDCAccessTokenClaims struct {
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"`
+	Version  int    `json:"ver"`
+	jwt.StandardClaims
+}
+
+type OIDCTokenService struct {
+	keys     *KeyRing
+	issuer   string
+	tokenTTL time.Duration
+}
+
+// NewOIDCTokenService signs ID and access tokens for OIDC clients with the
+// key ring, as issuer, valid for tokenTTL.
+func NewOIDCTokenService(keys *KeyRing, issuer string, tokenTTL time.Duration) domain.IOIDCTokenService {
+	return &OIDCTokenService{
+		keys:     keys,
+		issuer:   issuer,
+		tokenTTL: tokenTTL,
+	}
+}
+
+func (ots *OIDCTokenService) GenerateIDToken(info *domain.OIDCUserInfo, clientId, nonce string, authTime time.Time) (string, error) {
+	now := time.Now()
+	return ots.sign("JWT", IDTokenClaims{
+		Nonce:             nonce,
+		AuthTime:          authTime.Unix(),
+		PreferredUsername: info.Username,
+		Email:             info.Email,
+		EmailVerified:     info.EmailVerified,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    ots.issuer,
+			Subject:   info.Subject,
+			Audience:  clientId,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ots.tokenTTL).Unix(),
+		},
+	})
+}
+
+func (ots *OIDCTokenService) GenerateAccessToken(user *domain.User, clientId string, scopes []string) (string, time.Duration, error) {
+	now := time.Now()
+	token, err := ots.sign(oidcAccessTokenType, OIDCAccessTokenClaims{
+		ClientID: clientId,
+		Scope:    strings.Join(scopes, " "),
+		Version:  user.TokenVersion,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.New().String(),
+			Issuer:    ots.issuer,
+			Subject:   user.ID,
+			Audience:  clientId,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ots.tokenTTL).Unix(),
+		},
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return token, ots.tokenTTL, nil
+}
+
+// ParseAccessToken verifies an access token issued to an OIDC client. ID
+// tokens and task_manager access tokens are rejected.
+func (ots *OIDCTokenService) ParseAccessToken(tokenString string) (*domain.OIDCAccessClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &OIDCAccessTokenClaims{}, verificationKeyFunc(ots.keys))
+	if err != nil || !token.Valid {
+		return nil, domain.ErrInvalidToken
+	}
+	if typ, _ := token.Header["typ"].(string); typ != oidcAccessTokenType {
+		return nil, domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*OIDCAccessTokenClaims)
+	if !ok || claims.Id == "" || claims.Issuer != ots.issuer || claims.Audience == "" || claims.Audience != claims.ClientID {
+		return nil, domain.ErrInvalidToken
+	}
+	return &domain.OIDCAccessClaims{
+		TokenID:      claims.Id,
+		UserID:       claims.Subject,
+		ClientID:     claims.ClientID,
+		Scopes:       strings.Fields(claims.Scope),
+		TokenVersion: claims.Version,
+		ExpiresAt:    time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+func (ots *OIDCTokenService) SigningAlgorithm() string {
+	return ots.keys.algorithm
+}
+
+func (ots *OIDCTokenService) sign(typ string, claims jwt.Claims) (string, error) {
+	key, err := ots.keys.signingKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.record.Algorithm), claims)
+	token.Header["typ"] = typ
+	token.Header["kid"] = key.record.ID
+	return token.SignedString(key.private)
+}
@@ -0,0 +1,34 @@
+package infrastructure
+
+import (
+	"regexp"
+
+	domain "task_manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern is what an incoming request ID must look like to be
+// kept; anything else is replaced so that callers cannot put arbitrary
+// text into the audit log.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestInfoMiddleware stores the request ID and the client IP in the Gin
+// context for the audit log. The request ID is the caller's X-Request-ID
+// if it has a sane one, or a new UUID, and is echoed in the response.
+func RequestInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+		c.Set(domain.RequestIDContextKey, requestID)
+		c.Set(domain.ClientIPContextKey, c.ClientIP())
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
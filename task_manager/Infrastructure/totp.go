@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// The parameters every authenticator app supports; otpauth URIs spell them
+// out anyway.
+const (
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	// totpSkew is how many steps either side of the current one are
+	// accepted, to allow for clock drift between server and phone.
+	totpSkew = 1
+	// totpSecretSize is 160 bits, the HMAC-SHA1 block recommended by RFC 4226.
+	totpSecretSize = 20
+	qrCodeSize     = 256
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TOTPService struct {
+	issuer string
+}
+
+// NewTOTPService generates and checks RFC 6238 codes. issuer names the
+// application in users' authenticator apps.
+func NewTOTPService(issuer string) domain.ITOTPService {
+	return &TOTPService{
+		issuer: issuer,
+	}
+}
+
+func (ts *TOTPService) GenerateSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// KeyURI follows the Key Uri Format understood by Google Authenticator and
+// compatible apps.
+func (ts *TOTPService) KeyURI(accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", ts.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	label := url.PathEscape(ts.issuer) + ":" + url.PathEscape(accountName)
+	// Some apps do not read "+" as a space; a literal "+" is already
+	// escaped as %2B, so the replacement is safe.
+	return "otpauth://totp/" + label + "?" + strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func (ts *TOTPService) QRCode(content string) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, qrCodeSize)
+}
+
+func (ts *TOTPService) Validate(secret, code string, at time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+	current := at.Unix() / int64(totpPeriod.Seconds())
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode is the HOTP value (RFC 4226) of key for counter step.
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
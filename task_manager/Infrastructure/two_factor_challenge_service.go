@@ -0,0 +1,83 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// challengePurpose marks a JWT as a two-factor login challenge.
+const challengePurpose = "2fa_challenge"
+
+// ChallengeClaims represents the claims in a two-factor challenge token
+type ChallengeClaims struct {
+	Version int    `json:"ver"`
+	Setup   bool   `json:"setup,omitempty"`
+	Purpose string `json:"purpose"`
+	jwt.StandardClaims
+}
+
+type TwoFactorChallengeService struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewTwoFactorChallengeService signs HS256 challenge tokens that expire
+// after ttl, with a key derived from secret like verification tokens.
+func NewTwoFactorChallengeService(secret string, ttl time.Duration) domain.ITwoFactorChallengeService {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(challengePurpose))
+	return &TwoFactorChallengeService{
+		key: mac.Sum(nil),
+		ttl: ttl,
+	}
+}
+
+func (cs *TwoFactorChallengeService) GenerateChallengeToken(user *domain.User, setupRequired bool) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(cs.ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, ChallengeClaims{
+		Version: user.TokenVersion,
+		Setup:   setupRequired,
+		Purpose: challengePurpose,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   user.ID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	})
+	signed, err := token.SignedString(cs.key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseChallengeToken verifies the signature, expiry and purpose of a
+// challenge token.
+func (cs *TwoFactorChallengeService) ParseChallengeToken(tokenString string) (*domain.TwoFactorChallengeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ChallengeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return cs.key, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*ChallengeClaims)
+	if !ok || claims.Purpose != challengePurpose || claims.Subject == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	return &domain.TwoFactorChallengeClaims{
+		UserID:        claims.Subject,
+		TokenVersion:  claims.Version,
+		SetupRequired: claims.Setup,
+		ExpiresAt:     time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// verificationPurpose marks a JWT as an email verification token, so it can
+// never be mistaken for an access token or the other way round.
+const verificationPurpose = "verify_email"
+
+// VerificationClaims represents the claims in an email verification token
+type VerificationClaims struct {
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.StandardClaims
+}
+
+type VerificationTokenService struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewVerificationTokenService signs HS256 verification tokens that expire
+// after ttl. The signing key is derived from secret rather than being
+// secret itself, so access tokens and verification tokens cannot be
+// swapped even if the purpose claim were ignored.
+func NewVerificationTokenService(secret string, ttl time.Duration) domain.IVerificationTokenService {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(verificationPurpose))
+	return &VerificationTokenService{
+		key: mac.Sum(nil),
+		ttl: ttl,
+	}
+}
+
+func (vs *VerificationTokenService) GenerateVerificationToken(user *domain.User) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, VerificationClaims{
+		Email:   user.Email,
+		Purpose: verificationPurpose,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   user.ID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(vs.ttl).Unix(),
+		},
+	})
+	return token.SignedString(vs.key)
+}
+
+// ParseVerificationToken verifies the signature, expiry and purpose of a
+// verification token.
+func (vs *VerificationTokenService) ParseVerificationToken(tokenString string) (*domain.VerificationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &VerificationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return vs.key, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*VerificationClaims)
+	if !ok || claims.Purpose != verificationPurpose || claims.Subject == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	return &domain.VerificationClaims{
+		UserID:    claims.Subject,
+		Email:     claims.Email,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// Headers sent with every webhook request. The signature header carries
+// the time of signing and the HMAC, as "t=<unix seconds>,v1=<hex>".
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// DefaultWebhookTimeout bounds a webhook request, response included, when
+// NewHTTPWebhookSender is given no client.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Address ranges that are not public but that netip.Addr has no method for.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// HTTPWebhookSender posts webhook payloads with net/http. It does not follow
+// redirects: a receiver that moved has to be updated by an admin.
+type HTTPWebhookSender struct {
+	client  *http.Client
+	allowed []netip.Prefix
+}
+
+// NewHTTPWebhookSender sends with a copy of client, or of a client that
+// gives up after DefaultWebhookTimeout when it is nil. It refuses to connect
+// to loopback, link-local, private and other non-public addresses unless
+// they are in allowed. The check is made on the address being dialled, so a
+// public name that resolves to an internal address is refused too. The
+// client's Transport, if set, must be an *http.Transport; it is cloned and
+// never goes through a proxy.
+func NewHTTPWebhookSender(client *http.Client, allowed []netip.Prefix) domain.WebhookSender {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	hs := &HTTPWebhookSender{allowed: allowed}
+
+	base, ok := client.Transport.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+	transport.Proxy = nil
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: hs.checkAddress}
+	transport.DialContext = dialer.DialContext
+
+	copied := *client
+	copied.Transport = transport
+	copied.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	hs.client = &copied
+	return hs
+}
+
+// checkAddress is the dialer's Control function, called with the resolved
+// address of every connection before it is made.
+func (hs *HTTPWebhookSender) checkAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	addr := addrPort.Addr().Unmap()
+	if slices.ContainsFunc(hs.allowed, func(prefix netip.Prefix) bool { return prefix.Contains(addr) }) {
+		return nil
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() || addr.IsLinkLocalUnicast() ||
+		addr.IsMulticast() || slices.ContainsFunc(nonPublicPrefixes, func(prefix netip.Prefix) bool { return prefix.Contains(addr) }) {
+		return fmt.Errorf("webhook target %s is not a public address", addr)
+	}
+	return nil
+}
+
+func (hs *HTTPWebhookSender) Send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-manager-webhooks")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, time.Now(), body))
+
+	res, err := hs.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// Drain a little of the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	return res.StatusCode, nil
+}
+
+// SignWebhookPayload computes the signature header for body: an
+// HMAC-SHA256, keyed with secret, of the Unix time of signing, a dot and
+// the body. Receivers recompute it to check that the request came from us,
+// and can refuse old timestamps to stop replays.
+func SignWebhookPayload(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,41 @@
+package infrastructure_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	domain "task_manager/Domain"
+	infrastructure "task_manager/Infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPWebhookSender_RefusesNonPublicTargets(t *testing.T) {
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	delivery := &domain.WebhookDelivery{ID: "d1", EventType: domain.EventTaskCreated, Payload: `{}`}
+	sender := infrastructure.NewHTTPWebhookSender(nil, nil)
+	for _, target := range []string{server.URL, "http://localhost:" + serverURL.Port()} {
+		_, err := sender.Send(context.Background(), &domain.Webhook{URL: target, Secret: "s"}, delivery)
+		assert.ErrorContains(t, err, "not a public address", target)
+	}
+	assert.Zero(t, received)
+
+	sender = infrastructure.NewHTTPWebhookSender(nil, []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")})
+	status, err := sender.Send(context.Background(), &domain.Webhook{URL: server.URL, Secret: "s"}, delivery)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, 1, received)
+}
@@ -0,0 +1,33 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"os"
+
+	domain "task_manager/Domain"
+)
+
+// LoadWorkflow reads a task workflow definition from a JSON file, e.g.
+//
+//	{
+//	  "initial": "todo",
+//	  "statuses": [{"name": "todo"}, {"name": "doing", "starts_work": true}, {"name": "done", "completes_work": true}],
+//	  "transitions": {"todo": ["doing"], "doing": ["done", "todo"], "done": []},
+//	  "aliases": {"pending": "todo"}
+//	}
+//
+// An empty path yields the default workflow.
+func LoadWorkflow(path string) (*domain.Workflow, error) {
+	if path == "" {
+		return domain.DefaultWorkflow(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var def domain.Workflow
+	if err := json.Unmarshal(raw, &def); err != nil {
+		return nil, err
+	}
+	return domain.NewWorkflow(def)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	domain "task_manager/Domain"
+)
+
+// Audit entries are listed by sequence, so the cursor is simply the last
+// sequence on the page.
+
+func encodeAuditCursor(last *domain.AuditEntry) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(last.Sequence, 10)))
+}
+
+// decodeAuditCursor returns 0 for an empty cursor and
+// domain.ErrInvalidCursor for one that is malformed.
+func decodeAuditCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, domain.ErrInvalidCursor
+	}
+	sequence, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || sequence <= 0 {
+		return 0, domain.ErrInvalidCursor
+	}
+	return sequence, nil
+}
+
+// matchesAuditQuery is the in-process form of the filters every backend
+// applies.
+func matchesAuditQuery(entry *domain.AuditEntry, query domain.AuditQuery) bool {
+	return (query.ActorID == "" || entry.ActorID == query.ActorID) &&
+		(query.TargetType == "" || entry.TargetType == query.TargetType) &&
+		(query.TargetID == "" || entry.TargetID == query.TargetID) &&
+		(query.From.IsZero() || !entry.OccurredAt.Before(query.From)) &&
+		(query.To.IsZero() || entry.OccurredAt.Before(query.To))
+}
+
+// newAuditPage trims a result fetched with one extra entry (limit+1) down
+// to the page and sets NextCursor if that extra entry was there.
+func newAuditPage(entries []*domain.AuditEntry, limit int) *domain.AuditPage {
+	page := &domain.AuditPage{Entries: entries}
+	if limit > 0 && len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = encodeAuditCursor(page.Entries[limit-1])
+	}
+	return page
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// auditHeadID names the document that holds the newest entry's
+	// sequence and hash.
+	auditHeadID = "audit"
+	// auditAppendAttempts bounds how often an append retries after losing
+	// the race for the head to another one.
+	auditAppendAttempts = 10
+)
+
+type auditRepository struct {
+	database       *mongo.Database
+	collection     string
+	headCollection string
+}
+
+// NewAuditRepository chains entries through a head document holding the
+// newest entry's sequence and hash. An append only moves the head on from
+// where it read it, so concurrent appends cannot fork the chain; in a
+// transaction, the loser's write conflict makes the driver run the
+// transaction again.
+func NewAuditRepository(db *mongo.Database, collection string, headCollection string) domain.AuditRepository {
+	return &auditRepository{
+		database:       db,
+		collection:     collection,
+		headCollection: headCollection,
+	}
+}
+
+// EnsureAuditIndexes makes entry IDs and sequences unique and indexes the
+// filters of the audit query. The head document's ID is made unique too,
+// which is what turns a second head into a lost race.
+func EnsureAuditIndexes(c context.Context, db *mongo.Database, collection string, headCollection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "sequence", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "actorid", Value: 1}, {Key: "sequence", Value: 1}}},
+		{Keys: bson.D{{Key: "targettype", Value: 1}, {Key: "targetid", Value: 1}, {Key: "sequence", Value: 1}}},
+		{Keys: bson.D{{Key: "occurredat", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection(headCollection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (ar *auditRepository) AppendAuditEntry(c context.Context, entry *domain.AuditEntry) error {
+	heads := ar.database.Collection(ar.headCollection)
+
+	for attempt := 0; attempt < auditAppendAttempts; attempt++ {
+		var head struct {
+			Sequence int64  `bson:"sequence"`
+			Hash     string `bson:"hash"`
+		}
+		err := heads.FindOne(c, bson.M{"id": auditHeadID}).Decode(&head)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+		entry.Sequence = head.Sequence + 1
+		entry.PrevHash = head.Hash
+		entry.Hash = entry.ComputeHash()
+
+		// When the head has moved on since it was read, the filter matches
+		// nothing and the upsert's insert fails on the unique ID.
+		_, err = heads.UpdateOne(c,
+			bson.M{"id": auditHeadID, "sequence": head.Sequence},
+			bson.M{"$set": bson.M{"sequence": entry.Sequence, "hash": entry.Hash}},
+			options.Update().SetUpsert(true))
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = ar.database.Collection(ar.collection).InsertOne(c, entry)
+		return err
+	}
+	return domain.ErrAuditLogBusy
+}
+
+func (ar *auditRepository) GetAuditEntries(c context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	after, err := decodeAuditCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"sequence": bson.M{"$gt": after}}
+	if query.ActorID != "" {
+		filter["actorid"] = query.ActorID
+	}
+	if query.TargetType != "" {
+		filter["targettype"] = query.TargetType
+	}
+	if query.TargetID != "" {
+		filter["targetid"] = query.TargetID
+	}
+	occurredAt := bson.M{}
+	if !query.From.IsZero() {
+		occurredAt["$gte"] = query.From
+	}
+	if !query.To.IsZero() {
+		occurredAt["$lt"] = query.To
+	}
+	if len(occurredAt) > 0 {
+		filter["occurredat"] = occurredAt
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit + 1))
+	}
+	cursor, err := ar.database.Collection(ar.collection).Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var entries []*domain.AuditEntry
+	for cursor.Next(c) {
+		var entry domain.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return newAuditPage(entries, query.Limit), nil
+}
@@ -0,0 +1,164 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuditLog is the contract every AuditRepository must meet.
+func testAuditLog(t *testing.T, repo domain.AuditRepository) {
+	ctx := context.Background()
+	occurred := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	entries := []*domain.AuditEntry{
+		{ID: "a1", ActorID: "u1", ActorName: "alice", Action: domain.AuditTaskCreate, TargetType: domain.AuditTargetTask, TargetID: "t1",
+			After: json.RawMessage(`{"id":"t1","title":"Write report"}`), IP: "10.0.0.1", RequestID: "req-1"},
+		{ID: "a2", ActorID: "u1", ActorName: "alice", Action: domain.AuditTaskUpdate, TargetType: domain.AuditTargetTask, TargetID: "t1",
+			Before: json.RawMessage(`{"id":"t1","title":"Write report"}`), After: json.RawMessage(`{"id":"t1","title":"Send report"}`)},
+		{ID: "a3", ActorName: "bob@example.com", Action: domain.AuditUserLoginFailed, TargetType: domain.AuditTargetUser, TargetID: "u2",
+			Reason: "invalid credentials", IP: "10.0.0.2"},
+	}
+	for i, entry := range entries {
+		entry.OccurredAt = occurred.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, repo.AppendAuditEntry(ctx, entry))
+		assert.Equal(t, int64(i+1), entry.Sequence)
+		assert.Equal(t, entry.ComputeHash(), entry.Hash)
+		if i > 0 {
+			assert.Equal(t, entries[i-1].Hash, entry.PrevHash)
+		}
+	}
+	assert.Empty(t, entries[0].PrevHash)
+
+	page, err := repo.GetAuditEntries(ctx, domain.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+	assert.Empty(t, page.NextCursor)
+	for i, found := range page.Entries {
+		// Everything the hash covers comes back as it went in.
+		assert.Equal(t, entries[i].Hash, found.ComputeHash())
+		assert.Equal(t, entries[i].Hash, found.Hash)
+		assert.Equal(t, entries[i].PrevHash, found.PrevHash)
+	}
+	assert.JSONEq(t, `{"id":"t1","title":"Send report"}`, string(page.Entries[1].After))
+	assert.Empty(t, page.Entries[0].Before)
+	assert.Equal(t, "invalid credentials", page.Entries[2].Reason)
+
+	page, err = repo.GetAuditEntries(ctx, domain.AuditQuery{ActorID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	page, err = repo.GetAuditEntries(ctx, domain.AuditQuery{TargetType: domain.AuditTargetUser, TargetID: "u2"})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, "a3", page.Entries[0].ID)
+	// From is inclusive and To exclusive.
+	page, err = repo.GetAuditEntries(ctx, domain.AuditQuery{From: occurred.Add(time.Hour), To: occurred.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, "a2", page.Entries[0].ID)
+
+	page, err = repo.GetAuditEntries(ctx, domain.AuditQuery{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	require.NotEmpty(t, page.NextCursor)
+	page, err = repo.GetAuditEntries(ctx, domain.AuditQuery{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, "a3", page.Entries[0].ID)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = repo.GetAuditEntries(ctx, domain.AuditQuery{Cursor: "not a cursor"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+// testAuditLogConcurrency checks that concurrent appends still form a single
+// chain.
+func testAuditLogConcurrency(t *testing.T, repo domain.AuditRepository) {
+	ctx := context.Background()
+	const appenders = 8
+
+	var wg sync.WaitGroup
+	errs := make(chan error, appenders)
+	for i := 0; i < appenders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.AppendAuditEntry(ctx, &domain.AuditEntry{
+				ID: fmt.Sprintf("c%d", i), Action: domain.AuditTaskCreate,
+				OccurredAt: time.Now().UTC().Truncate(time.Millisecond),
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	page, err := repo.GetAuditEntries(ctx, domain.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, appenders)
+	prevHash := ""
+	for i, entry := range page.Entries {
+		assert.Equal(t, int64(i+1), entry.Sequence)
+		assert.Equal(t, prevHash, entry.PrevHash)
+		prevHash = entry.Hash
+	}
+}
+
+func TestInMemoryAuditRepository(t *testing.T) {
+	testAuditLog(t, repository.NewInMemoryAuditRepository())
+	testAuditLogConcurrency(t, repository.NewInMemoryAuditRepository())
+}
+
+func TestSQLiteAuditRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "audit.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testAuditLog(t, repository.NewSQLiteAuditRepository(db))
+
+	// The table refuses edits and removals outright.
+	_, err = db.Exec(`UPDATE audit_log SET actor_id = 'mallory' WHERE sequence = 1`)
+	assert.ErrorContains(t, err, "cannot be changed")
+	_, err = db.Exec(`DELETE FROM audit_log WHERE sequence = 3`)
+	assert.ErrorContains(t, err, "cannot be deleted")
+
+	concurrent, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "concurrent.db"))
+	require.NoError(t, err)
+	defer concurrent.Close()
+	testAuditLogConcurrency(t, repository.NewSQLiteAuditRepository(concurrent))
+}
+
+func TestMongoAuditRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const entries, heads = "test_audit_log", "test_audit_head"
+	reset := func() {
+		for _, collection := range []string{entries, heads} {
+			require.NoError(t, db.Collection(collection).Drop(ctx))
+		}
+		require.NoError(t, repository.EnsureAuditIndexes(ctx, db, entries, heads))
+	}
+	t.Cleanup(func() {
+		for _, collection := range []string{entries, heads} {
+			_ = db.Collection(collection).Drop(context.Background())
+		}
+	})
+
+	reset()
+	testAuditLog(t, repository.NewAuditRepository(db, entries, heads))
+	reset()
+	testAuditLogConcurrency(t, repository.NewAuditRepository(db, entries, heads))
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type authorizationCodeRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewAuthorizationCodeRepository(db *mongo.Database, collection string) domain.AuthorizationCodeRepository {
+	return &authorizationCodeRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureAuthorizationCodeIndexes makes code hashes unique and lets MongoDB
+// expire codes once they can no longer be redeemed.
+func EnsureAuthorizationCodeIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "codehash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (ar *authorizationCodeRepository) CreateAuthorizationCode(c context.Context, code *domain.AuthorizationCode) error {
+	collection := ar.database.Collection(ar.collection)
+
+	_, err := collection.InsertOne(c, code)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTokenAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (ar *authorizationCodeRepository) GetAuthorizationCodeByHash(c context.Context, codeHash string) (*domain.AuthorizationCode, error) {
+	collection := ar.database.Collection(ar.collection)
+
+	var code domain.AuthorizationCode
+	err := collection.FindOne(c, bson.M{"codehash": codeHash}).Decode(&code)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (ar *authorizationCodeRepository) MarkAuthorizationCodeUsed(c context.Context, codeId string, usedAt time.Time) (bool, error) {
+	collection := ar.database.Collection(ar.collection)
+
+	filter := bson.M{"id": codeId, "usedat": nil}
+	result, err := collection.UpdateOne(c, filter, bson.M{"$set": bson.M{"usedat": usedAt}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
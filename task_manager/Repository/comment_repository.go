@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type commentRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewCommentRepository(db *mongo.Database, collection string) domain.CommentRepository {
+	return &commentRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureCommentIndexes makes comment IDs unique and backs listing and
+// counting a task's comments.
+func EnsureCommentIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "taskid", Value: 1}, {Key: "createdat", Value: 1}}},
+	})
+	return err
+}
+
+func (cr *commentRepository) CreateComment(c context.Context, comment *domain.Comment) error {
+	collection := cr.database.Collection(cr.collection)
+
+	_, err := collection.InsertOne(c, comment)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrCommentAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (cr *commentRepository) GetCommentByID(c context.Context, commentId string) (*domain.Comment, error) {
+	collection := cr.database.Collection(cr.collection)
+
+	var comment domain.Comment
+	err := collection.FindOne(c, bson.M{"id": commentId}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (cr *commentRepository) GetCommentsByTask(c context.Context, taskId string) ([]*domain.Comment, error) {
+	collection := cr.database.Collection(cr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}})
+	cursor, err := collection.Find(c, bson.M{"taskid": taskId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var comments []*domain.Comment
+	for cursor.Next(c) {
+		var comment domain.Comment
+		if err := cursor.Decode(&comment); err != nil {
+			return nil, err
+		}
+		comments = append(comments, &comment)
+	}
+	return comments, cursor.Err()
+}
+
+// EditComment uses an update pipeline so the body being replaced is read
+// and archived by the same write that replaces it.
+func (cr *commentRepository) EditComment(c context.Context, commentId string, body string, editedAt time.Time) (*domain.Comment, error) {
+	collection := cr.database.Collection(cr.collection)
+
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"revisions": bson.M{"$concatArrays": bson.A{
+			bson.M{"$ifNull": bson.A{"$revisions", bson.A{}}},
+			bson.A{bson.M{
+				"body":      "$body",
+				"writtenat": bson.M{"$ifNull": bson.A{"$editedat", "$createdat"}},
+			}},
+		}},
+		"body":     body,
+		"editedat": editedAt,
+	}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var comment domain.Comment
+	err := collection.FindOneAndUpdate(c, bson.M{"id": commentId, "deletedat": nil}, update, opts).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (cr *commentRepository) DeleteComment(c context.Context, commentId string, deletedBy string, deletedAt time.Time) error {
+	collection := cr.database.Collection(cr.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"id": commentId, "deletedat": nil}, bson.M{"$set": bson.M{
+		"body":      "",
+		"revisions": bson.A{},
+		"deletedat": deletedAt,
+		"deletedby": deletedBy,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrCommentNotFound
+	}
+	return nil
+}
+
+func (cr *commentRepository) CountCommentsByTask(c context.Context, taskIds []string) (map[string]int, error) {
+	counts := make(map[string]int, len(taskIds))
+	if len(taskIds) == 0 {
+		return counts, nil
+	}
+	collection := cr.database.Collection(cr.collection)
+
+	cursor, err := collection.Aggregate(c, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"taskid": bson.M{"$in": taskIds}, "deletedat": nil}}},
+		{{Key: "$group", Value: bson.M{"_id": "$taskid", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	for cursor.Next(c) {
+		var group struct {
+			TaskID string `bson:"_id"`
+			Count  int    `bson:"count"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return nil, err
+		}
+		counts[group.TaskID] = group.Count
+	}
+	return counts, cursor.Err()
+}
+
+func (cr *commentRepository) DeleteCommentsByTask(c context.Context, taskId string) error {
+	collection := cr.database.Collection(cr.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{"taskid": taskId})
+	return err
+}
+
+func (cr *commentRepository) DeleteCommentsByTasks(c context.Context, taskIds []string) error {
+	if len(taskIds) == 0 {
+		return nil
+	}
+	collection := cr.database.Collection(cr.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{"taskid": bson.M{"$in": taskIds}})
+	return err
+}
@@ -0,0 +1,137 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testComments is the contract every CommentRepository must meet.
+func testComments(t *testing.T, repo domain.CommentRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	first := &domain.Comment{ID: "c1", TaskID: "t1", AuthorID: "u1", Body: "first", CreatedAt: now.Add(-time.Hour)}
+	reply := &domain.Comment{ID: "c2", TaskID: "t1", ParentID: "c1", AuthorID: "u2", Body: "reply", CreatedAt: now.Add(-time.Minute)}
+	other := &domain.Comment{ID: "c3", TaskID: "t2", AuthorID: "u1", Body: "elsewhere", CreatedAt: now}
+	for _, comment := range []*domain.Comment{reply, first, other} {
+		require.NoError(t, repo.CreateComment(ctx, comment))
+	}
+	assert.ErrorIs(t, repo.CreateComment(ctx, &domain.Comment{ID: "c1", TaskID: "t1", CreatedAt: now}), domain.ErrCommentAlreadyExists)
+
+	found, err := repo.GetCommentByID(ctx, "c2")
+	require.NoError(t, err)
+	assert.Equal(t, "c1", found.ParentID)
+	assert.Equal(t, "u2", found.AuthorID)
+	assert.Equal(t, "reply", found.Body)
+	assert.True(t, found.CreatedAt.Equal(now.Add(-time.Minute)))
+	assert.Nil(t, found.EditedAt)
+	assert.Empty(t, found.Revisions)
+	assert.False(t, found.Deleted())
+
+	_, err = repo.GetCommentByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrCommentNotFound)
+
+	// Oldest first, whatever the insertion order.
+	comments, err := repo.GetCommentsByTask(ctx, "t1")
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "c1", comments[0].ID)
+	assert.Equal(t, "c2", comments[1].ID)
+
+	// Every edit archives the body it replaces.
+	edited, err := repo.EditComment(ctx, "c1", "second", now)
+	require.NoError(t, err)
+	assert.Equal(t, "second", edited.Body)
+	require.NotNil(t, edited.EditedAt)
+	assert.True(t, edited.EditedAt.Equal(now))
+	edited, err = repo.EditComment(ctx, "c1", "third", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, edited.Revisions, 2)
+	assert.Equal(t, "first", edited.Revisions[0].Body)
+	assert.True(t, edited.Revisions[0].WrittenAt.Equal(now.Add(-time.Hour)))
+	assert.Equal(t, "second", edited.Revisions[1].Body)
+	assert.True(t, edited.Revisions[1].WrittenAt.Equal(now))
+
+	found, err = repo.GetCommentByID(ctx, "c1")
+	require.NoError(t, err)
+	assert.Equal(t, "third", found.Body)
+	assert.Len(t, found.Revisions, 2)
+
+	_, err = repo.EditComment(ctx, "missing", "body", now)
+	assert.ErrorIs(t, err, domain.ErrCommentNotFound)
+
+	counts, err := repo.CountCommentsByTask(ctx, []string{"t1", "t2", "t3"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, counts["t1"])
+	assert.Equal(t, 1, counts["t2"])
+	assert.Zero(t, counts["t3"])
+
+	// Deleting leaves a tombstone that no longer counts and cannot change.
+	require.NoError(t, repo.DeleteComment(ctx, "c1", "moderator", now))
+	assert.ErrorIs(t, repo.DeleteComment(ctx, "c1", "moderator", now), domain.ErrCommentNotFound)
+	assert.ErrorIs(t, repo.DeleteComment(ctx, "missing", "moderator", now), domain.ErrCommentNotFound)
+	_, err = repo.EditComment(ctx, "c1", "revived", now)
+	assert.ErrorIs(t, err, domain.ErrCommentNotFound)
+
+	found, err = repo.GetCommentByID(ctx, "c1")
+	require.NoError(t, err)
+	assert.True(t, found.Deleted())
+	assert.Equal(t, "moderator", found.DeletedBy)
+	assert.Empty(t, found.Body)
+	assert.Empty(t, found.Revisions)
+
+	counts, err = repo.CountCommentsByTask(ctx, []string{"t1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, counts["t1"])
+
+	counts, err = repo.CountCommentsByTask(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+
+	require.NoError(t, repo.DeleteCommentsByTask(ctx, "t1"))
+	comments, err = repo.GetCommentsByTask(ctx, "t1")
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+	comments, err = repo.GetCommentsByTask(ctx, "t2")
+	require.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	require.NoError(t, repo.DeleteCommentsByTasks(ctx, nil))
+	require.NoError(t, repo.CreateComment(ctx, &domain.Comment{ID: "c9", TaskID: "t3", AuthorID: "u1", Body: "later", CreatedAt: now}))
+	require.NoError(t, repo.DeleteCommentsByTasks(ctx, []string{"t2", "t3"}))
+	counts, err = repo.CountCommentsByTask(ctx, []string{"t2", "t3"})
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestInMemoryCommentRepository(t *testing.T) {
+	testComments(t, repository.NewInMemoryCommentRepository())
+}
+
+func TestSQLiteCommentRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "comments.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testComments(t, repository.NewSQLiteCommentRepository(db))
+}
+
+func TestMongoCommentRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_task_comments"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsureCommentIndexes(ctx, db, collection))
+
+	testComments(t, repository.NewCommentRepository(db, collection))
+}
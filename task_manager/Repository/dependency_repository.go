@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type dependencyRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewDependencyRepository(db *mongo.Database, collection string) domain.DependencyRepository {
+	return &dependencyRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureDependencyIndexes makes each dependency unique and indexes the
+// graph from both ends and by owner.
+func EnsureDependencyIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "taskid", Value: 1}, {Key: "blockerid", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "blockerid", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}}},
+	})
+	return err
+}
+
+func (dr *dependencyRepository) AddDependency(c context.Context, dependency *domain.TaskDependency) error {
+	collection := dr.database.Collection(dr.collection)
+
+	_, err := collection.InsertOne(c, dependency)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrDependencyAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (dr *dependencyRepository) RemoveDependency(c context.Context, taskId string, blockerId string) error {
+	collection := dr.database.Collection(dr.collection)
+
+	_, err := collection.DeleteOne(c, bson.M{"taskid": taskId, "blockerid": blockerId})
+	return err
+}
+
+func (dr *dependencyRepository) GetBlockers(c context.Context, taskId string) ([]*domain.TaskDependency, error) {
+	return dr.find(c, bson.M{"taskid": taskId})
+}
+
+func (dr *dependencyRepository) GetDependents(c context.Context, blockerId string) ([]*domain.TaskDependency, error) {
+	return dr.find(c, bson.M{"blockerid": blockerId})
+}
+
+func (dr *dependencyRepository) GetDependenciesByUser(c context.Context, userId string) ([]*domain.TaskDependency, error) {
+	return dr.find(c, bson.M{"userid": userId})
+}
+
+func (dr *dependencyRepository) DeleteDependenciesByTask(c context.Context, taskId string) error {
+	collection := dr.database.Collection(dr.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{"$or": bson.A{
+		bson.M{"taskid": taskId},
+		bson.M{"blockerid": taskId},
+	}})
+	return err
+}
+
+func (dr *dependencyRepository) DeleteDependenciesByUser(c context.Context, userId string) error {
+	collection := dr.database.Collection(dr.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{"userid": userId})
+	return err
+}
+
+func (dr *dependencyRepository) find(c context.Context, filter bson.M) ([]*domain.TaskDependency, error) {
+	collection := dr.database.Collection(dr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var dependencies []*domain.TaskDependency
+	for cursor.Next(c) {
+		var dependency domain.TaskDependency
+		if err := cursor.Decode(&dependency); err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, &dependency)
+	}
+	return dependencies, cursor.Err()
+}
@@ -0,0 +1,88 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDependencies is the contract every DependencyRepository must meet.
+func testDependencies(t *testing.T, repo domain.DependencyRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for i, dependency := range []*domain.TaskDependency{
+		{TaskID: "a", BlockerID: "b", UserID: "u1"},
+		{TaskID: "a", BlockerID: "c", UserID: "u1"},
+		{TaskID: "c", BlockerID: "b", UserID: "u1"},
+		{TaskID: "x", BlockerID: "y", UserID: "u2"},
+	} {
+		dependency.CreatedAt = now.Add(time.Duration(i) * time.Second)
+		require.NoError(t, repo.AddDependency(ctx, dependency))
+	}
+	err := repo.AddDependency(ctx, &domain.TaskDependency{TaskID: "a", BlockerID: "b", UserID: "u1", CreatedAt: now})
+	assert.ErrorIs(t, err, domain.ErrDependencyAlreadyExists)
+
+	pairs := func(dependencies []*domain.TaskDependency, err error) []string {
+		require.NoError(t, err)
+		var pairs []string
+		for _, dependency := range dependencies {
+			pairs = append(pairs, dependency.TaskID+"<"+dependency.BlockerID)
+		}
+		return pairs
+	}
+	assert.Equal(t, []string{"a<b", "a<c"}, pairs(repo.GetBlockers(ctx, "a")))
+	assert.Equal(t, []string{"a<b", "c<b"}, pairs(repo.GetDependents(ctx, "b")))
+	assert.Equal(t, []string{"a<b", "a<c", "c<b"}, pairs(repo.GetDependenciesByUser(ctx, "u1")))
+
+	blockers, err := repo.GetBlockers(ctx, "x")
+	require.NoError(t, err)
+	require.Len(t, blockers, 1)
+	assert.Equal(t, "u2", blockers[0].UserID)
+	assert.True(t, blockers[0].CreatedAt.Equal(now.Add(3*time.Second)))
+
+	require.NoError(t, repo.RemoveDependency(ctx, "a", "c"))
+	require.NoError(t, repo.RemoveDependency(ctx, "a", "c"))
+	assert.Equal(t, []string{"a<b"}, pairs(repo.GetBlockers(ctx, "a")))
+
+	// Both directions go when a task is deleted.
+	require.NoError(t, repo.DeleteDependenciesByTask(ctx, "b"))
+	assert.Empty(t, pairs(repo.GetDependenciesByUser(ctx, "u1")))
+	assert.Equal(t, []string{"x<y"}, pairs(repo.GetDependenciesByUser(ctx, "u2")))
+
+	require.NoError(t, repo.AddDependency(ctx, &domain.TaskDependency{TaskID: "a", BlockerID: "c", UserID: "u1", CreatedAt: now}))
+	require.NoError(t, repo.DeleteDependenciesByUser(ctx, "u1"))
+	assert.Empty(t, pairs(repo.GetDependenciesByUser(ctx, "u1")))
+	assert.Equal(t, []string{"x<y"}, pairs(repo.GetDependenciesByUser(ctx, "u2")))
+}
+
+func TestInMemoryDependencyRepository(t *testing.T) {
+	testDependencies(t, repository.NewInMemoryDependencyRepository())
+}
+
+func TestSQLiteDependencyRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "dependencies.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testDependencies(t, repository.NewSQLiteDependencyRepository(db))
+}
+
+func TestMongoDependencyRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_task_dependencies"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsureDependencyIndexes(ctx, db, collection))
+
+	testDependencies(t, repository.NewDependencyRepository(db, collection))
+}
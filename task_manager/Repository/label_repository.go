@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type labelRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewLabelRepository(db *mongo.Database, collection string) domain.LabelRepository {
+	return &labelRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// labelNameCollation compares names ignoring case, for both the uniqueness
+// of names within a catalogue and the order catalogues are listed in.
+var labelNameCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// EnsureLabelIndexes makes label IDs unique, and label names unique within
+// each user's catalogue regardless of case.
+func EnsureLabelIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{
+			Keys:    bson.D{{Key: "userid", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true).SetCollation(labelNameCollation),
+		},
+	})
+	return err
+}
+
+func (lr *labelRepository) CreateLabel(c context.Context, label *domain.Label) error {
+	collection := lr.database.Collection(lr.collection)
+
+	_, err := collection.InsertOne(c, label)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrLabelAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (lr *labelRepository) GetLabelByID(c context.Context, labelId string) (*domain.Label, error) {
+	collection := lr.database.Collection(lr.collection)
+
+	var label domain.Label
+	err := collection.FindOne(c, bson.M{"id": labelId}).Decode(&label)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrLabelNotFound
+		}
+		return nil, err
+	}
+	return &label, nil
+}
+
+func (lr *labelRepository) GetLabelsByUser(c context.Context, userId string) ([]*domain.Label, error) {
+	collection := lr.database.Collection(lr.collection)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}}).
+		SetCollation(labelNameCollation)
+	cursor, err := collection.Find(c, bson.M{"userid": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var labels []*domain.Label
+	for cursor.Next(c) {
+		var label domain.Label
+		if err := cursor.Decode(&label); err != nil {
+			return nil, err
+		}
+		labels = append(labels, &label)
+	}
+	return labels, cursor.Err()
+}
+
+func (lr *labelRepository) UpdateLabel(c context.Context, label *domain.Label) error {
+	collection := lr.database.Collection(lr.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"id": label.ID}, bson.M{"$set": bson.M{
+		"name":  label.Name,
+		"color": label.Color,
+	}})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrLabelAlreadyExists
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrLabelNotFound
+	}
+	return nil
+}
+
+func (lr *labelRepository) DeleteLabel(c context.Context, labelId string) error {
+	collection := lr.database.Collection(lr.collection)
+
+	result, err := collection.DeleteOne(c, bson.M{"id": labelId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrLabelNotFound
+	}
+	return nil
+}
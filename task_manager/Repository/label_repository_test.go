@@ -0,0 +1,135 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLabels is the contract every LabelRepository must meet.
+func testLabels(t *testing.T, repo domain.LabelRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, label := range []*domain.Label{
+		{ID: "l1", UserID: "u1", Name: "urgent", Color: "#ff0000", CreatedAt: now},
+		{ID: "l2", UserID: "u1", Name: "Backend", Color: "#0000ff", CreatedAt: now},
+		{ID: "l3", UserID: "u2", Name: "Urgent", Color: "#ff0000", CreatedAt: now},
+	} {
+		require.NoError(t, repo.CreateLabel(ctx, label))
+	}
+	// Names are unique per user, ignoring case.
+	err := repo.CreateLabel(ctx, &domain.Label{ID: "l4", UserID: "u1", Name: "URGENT", Color: "#ff0000", CreatedAt: now})
+	assert.ErrorIs(t, err, domain.ErrLabelAlreadyExists)
+
+	found, err := repo.GetLabelByID(ctx, "l2")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", found.UserID)
+	assert.Equal(t, "Backend", found.Name)
+	assert.Equal(t, "#0000ff", found.Color)
+	assert.True(t, found.CreatedAt.Equal(now))
+
+	_, err = repo.GetLabelByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrLabelNotFound)
+
+	labels, err := repo.GetLabelsByUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, labels, 2)
+	assert.Equal(t, "Backend", labels[0].Name)
+	assert.Equal(t, "urgent", labels[1].Name)
+
+	// Changing only the case of a label's own name is not a clash.
+	require.NoError(t, repo.UpdateLabel(ctx, &domain.Label{ID: "l1", Name: "Urgent", Color: "#ff8800"}))
+	found, err = repo.GetLabelByID(ctx, "l1")
+	require.NoError(t, err)
+	assert.Equal(t, "Urgent", found.Name)
+	assert.Equal(t, "#ff8800", found.Color)
+	assert.Equal(t, "u1", found.UserID)
+
+	assert.ErrorIs(t, repo.UpdateLabel(ctx, &domain.Label{ID: "l2", Name: "urgent", Color: "#0000ff"}), domain.ErrLabelAlreadyExists)
+	assert.ErrorIs(t, repo.UpdateLabel(ctx, &domain.Label{ID: "missing", Name: "x", Color: "#000000"}), domain.ErrLabelNotFound)
+
+	require.NoError(t, repo.DeleteLabel(ctx, "l1"))
+	assert.ErrorIs(t, repo.DeleteLabel(ctx, "l1"), domain.ErrLabelNotFound)
+	labels, err = repo.GetLabelsByUser(ctx, "u1")
+	require.NoError(t, err)
+	assert.Len(t, labels, 1)
+}
+
+// testTaskLabels is the contract for the label operations of a
+// TaskRepository.
+func testTaskLabels(t *testing.T, repo domain.TaskRepository) {
+	ctx := context.Background()
+	labels := func(taskID string) []string {
+		task, err := repo.GetTaskByID(ctx, taskID)
+		require.NoError(t, err)
+		return task.LabelIDs
+	}
+
+	require.NoError(t, repo.CreateTask(ctx, &domain.Task{ID: "t1", UserID: "u1", Title: "one"}))
+	require.NoError(t, repo.CreateTask(ctx, &domain.Task{ID: "t2", UserID: "u1", Title: "two"}))
+	require.NoError(t, repo.CreateTask(ctx, &domain.Task{ID: "t3", UserID: "u2", Title: "three"}))
+	assert.Empty(t, labels("t1"))
+
+	// Attaching is idempotent and keeps the order labels were added in.
+	for _, label := range []string{"b", "a", "b"} {
+		require.NoError(t, repo.AddTaskLabel(ctx, "t1", label))
+	}
+	assert.Equal(t, []string{"b", "a"}, labels("t1"))
+	assert.ErrorIs(t, repo.AddTaskLabel(ctx, "missing", "a"), domain.ErrTaskNotFound)
+
+	// Updating the task leaves its labels alone.
+	_, err := repo.UpdateTask(ctx, "t1", &domain.Task{ID: "t1", UserID: "u1", Title: "renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, labels("t1"))
+
+	require.NoError(t, repo.RemoveTaskLabel(ctx, "t1", "b"))
+	require.NoError(t, repo.RemoveTaskLabel(ctx, "t1", "b"))
+	assert.Equal(t, []string{"a"}, labels("t1"))
+	assert.ErrorIs(t, repo.RemoveTaskLabel(ctx, "missing", "a"), domain.ErrTaskNotFound)
+
+	// Removing a label from a user's tasks does not touch other users'.
+	require.NoError(t, repo.AddTaskLabel(ctx, "t2", "a"))
+	require.NoError(t, repo.AddTaskLabel(ctx, "t3", "a"))
+	require.NoError(t, repo.RemoveLabelFromTasks(ctx, "u1", "a"))
+	assert.Empty(t, labels("t1"))
+	assert.Empty(t, labels("t2"))
+	assert.Equal(t, []string{"a"}, labels("t3"))
+}
+
+func TestInMemoryLabelRepository(t *testing.T) {
+	testLabels(t, repository.NewInMemoryLabelRepository())
+	testTaskLabels(t, repository.NewInMemoryTaskRepository())
+}
+
+func TestSQLiteLabelRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "labels.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLabels(t, repository.NewSQLiteLabelRepository(db))
+	testTaskLabels(t, repository.NewSQLiteTaskRepository(db))
+}
+
+func TestMongoLabelRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection, taskCollection = "test_labels", "test_labelled_tasks"
+	for _, name := range []string{collection, taskCollection} {
+		require.NoError(t, db.Collection(name).Drop(ctx))
+		t.Cleanup(func() { _ = db.Collection(name).Drop(context.Background()) })
+	}
+	require.NoError(t, repository.EnsureLabelIndexes(ctx, db, collection))
+	require.NoError(t, repository.EnsureTaskIndexes(ctx, db, taskCollection))
+
+	testLabels(t, repository.NewLabelRepository(db, collection))
+	testTaskLabels(t, repository.NewTaskRepository(db, taskCollection))
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type loginAttemptRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewLoginAttemptRepository(db *mongo.Database, collection string) domain.LoginAttemptRepository {
+	return &loginAttemptRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureLoginAttemptIndexes keys counters by key and lets MongoDB drop them
+// once they have expired.
+func EnsureLoginAttemptIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (lr *loginAttemptRepository) GetLoginAttempts(c context.Context, key string) (*domain.LoginAttempts, error) {
+	collection := lr.database.Collection(lr.collection)
+
+	var attempts domain.LoginAttempts
+	// The TTL monitor only runs once a minute, so expired counters are
+	// filtered out here as well.
+	filter := bson.M{"key": key, "expiresat": bson.M{"$gt": time.Now()}}
+	err := collection.FindOne(c, filter).Decode(&attempts)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+func (lr *loginAttemptRepository) RecordLoginFailure(c context.Context, key string, at time.Time, window time.Duration) (*domain.LoginAttempts, error) {
+	collection := lr.database.Collection(lr.collection)
+
+	// An update pipeline does the expiry check and the increment in one
+	// atomic write, so concurrent failures are all counted. A missing
+	// expiresat sorts before any date and so also starts a new count.
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"key": key,
+		"failures": bson.M{"$cond": bson.A{
+			bson.M{"$lte": bson.A{"$expiresat", at}},
+			1,
+			bson.M{"$add": bson.A{"$failures", 1}},
+		}},
+		"previousfailure": bson.M{"$cond": bson.A{
+			bson.M{"$lte": bson.A{"$expiresat", at}},
+			"$$REMOVE",
+			"$lastfailure",
+		}},
+		"lastfailure": at,
+		"expiresat":   at.Add(window),
+	}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var attempts domain.LoginAttempts
+	if err := collection.FindOneAndUpdate(c, bson.M{"key": key}, update, opts).Decode(&attempts); err != nil {
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+func (lr *loginAttemptRepository) ReleaseLoginAttempt(c context.Context, key string, recorded *domain.LoginAttempts) error {
+	collection := lr.database.Collection(lr.collection)
+
+	// The counter is only put back as it was if it is still the one
+	// recorded; a failure counted since must not be lost.
+	unchanged := bson.M{"key": key, "failures": recorded.Failures, "lastfailure": recorded.LastFailure}
+	if recorded.Failures == 1 {
+		deleted, err := collection.DeleteOne(c, unchanged)
+		if err != nil || deleted.DeletedCount == 1 {
+			return err
+		}
+	} else {
+		restored, err := collection.UpdateOne(c, unchanged, bson.M{
+			"$set":   bson.M{"failures": recorded.Failures - 1, "lastfailure": recorded.PreviousFailure},
+			"$unset": bson.M{"previousfailure": ""},
+		})
+		if err != nil || restored.MatchedCount == 1 {
+			return err
+		}
+	}
+	_, err := collection.UpdateOne(c, bson.M{"key": key, "failures": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"failures": -1}})
+	return err
+}
+
+func (lr *loginAttemptRepository) ResetLoginAttempts(c context.Context, key string) error {
+	collection := lr.database.Collection(lr.collection)
+
+	_, err := collection.DeleteOne(c, bson.M{"key": key})
+	return err
+}
@@ -0,0 +1,118 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLoginAttempts is the contract every LoginAttemptRepository must meet.
+func testLoginAttempts(t *testing.T, repo domain.LoginAttemptRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	attempts, err := repo.GetLoginAttempts(ctx, "account:john@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, attempts)
+
+	for i := 1; i <= 3; i++ {
+		attempts, err = repo.RecordLoginFailure(ctx, "account:john@example.com", now, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, i, attempts.Failures)
+	}
+	assert.True(t, attempts.LastFailure.Equal(now))
+	assert.True(t, attempts.PreviousFailure.Equal(now))
+	assert.True(t, attempts.ExpiresAt.Equal(now.Add(time.Hour)))
+
+	stored, err := repo.GetLoginAttempts(ctx, "account:john@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 3, stored.Failures)
+
+	// Once a counter has expired, the next failure starts a new one.
+	_, err = repo.RecordLoginFailure(ctx, "ip:192.0.2.1", now.Add(-2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	expired, err := repo.GetLoginAttempts(ctx, "ip:192.0.2.1")
+	require.NoError(t, err)
+	assert.Nil(t, expired)
+	attempts, err = repo.RecordLoginFailure(ctx, "ip:192.0.2.1", now, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts.Failures)
+
+	// Concurrent failures are all counted.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.RecordLoginFailure(ctx, "ip:192.0.2.2", now, time.Hour)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	stored, err = repo.GetLoginAttempts(ctx, "ip:192.0.2.2")
+	require.NoError(t, err)
+	assert.Equal(t, 10, stored.Failures)
+
+	// A released attempt puts the counter back as it was, unless another
+	// failure was counted since.
+	first, err := repo.RecordLoginFailure(ctx, "account:jane@example.com", now.Add(-time.Minute), time.Hour)
+	require.NoError(t, err)
+	assert.True(t, first.PreviousFailure.IsZero())
+	reserved, err := repo.RecordLoginFailure(ctx, "account:jane@example.com", now, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, reserved.PreviousFailure.Equal(now.Add(-time.Minute)))
+	require.NoError(t, repo.ReleaseLoginAttempt(ctx, "account:jane@example.com", reserved))
+	stored, err = repo.GetLoginAttempts(ctx, "account:jane@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 1, stored.Failures)
+	assert.True(t, stored.LastFailure.Equal(now.Add(-time.Minute)))
+
+	reserved, err = repo.RecordLoginFailure(ctx, "account:jane@example.com", now, time.Hour)
+	require.NoError(t, err)
+	_, err = repo.RecordLoginFailure(ctx, "account:jane@example.com", now.Add(time.Second), time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, repo.ReleaseLoginAttempt(ctx, "account:jane@example.com", reserved))
+	stored, err = repo.GetLoginAttempts(ctx, "account:jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.Failures)
+	assert.True(t, stored.LastFailure.Equal(now.Add(time.Second)))
+
+	// Releasing the only failure drops the counter.
+	reserved, err = repo.RecordLoginFailure(ctx, "account:joe@example.com", now, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, repo.ReleaseLoginAttempt(ctx, "account:joe@example.com", reserved))
+	stored, err = repo.GetLoginAttempts(ctx, "account:joe@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	require.NoError(t, repo.ResetLoginAttempts(ctx, "account:john@example.com"))
+	require.NoError(t, repo.ResetLoginAttempts(ctx, "account:nobody@example.com"))
+	attempts, err = repo.GetLoginAttempts(ctx, "account:john@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, attempts)
+}
+
+func TestInMemoryLoginAttemptRepository(t *testing.T) {
+	testLoginAttempts(t, repository.NewInMemoryLoginAttemptRepository())
+}
+
+func TestMongoLoginAttemptRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_login_attempts"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsureLoginAttemptIndexes(ctx, db, collection))
+
+	testLoginAttempts(t, repository.NewLoginAttemptRepository(db, collection))
+}
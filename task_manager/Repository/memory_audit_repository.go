@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryAuditRepository is the slice-backed counterpart of
+// auditRepository.
+type inMemoryAuditRepository struct {
+	mu      sync.Mutex
+	entries []*domain.AuditEntry
+}
+
+func NewInMemoryAuditRepository() domain.AuditRepository {
+	return &inMemoryAuditRepository{}
+}
+
+func copyAuditEntry(entry *domain.AuditEntry) *domain.AuditEntry {
+	copied := *entry
+	copied.Before = slices.Clone(entry.Before)
+	copied.After = slices.Clone(entry.After)
+	return &copied
+}
+
+func (ar *inMemoryAuditRepository) AppendAuditEntry(c context.Context, entry *domain.AuditEntry) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	entry.Sequence = 1
+	entry.PrevHash = ""
+	if n := len(ar.entries); n > 0 {
+		entry.Sequence = ar.entries[n-1].Sequence + 1
+		entry.PrevHash = ar.entries[n-1].Hash
+	}
+	entry.Hash = entry.ComputeHash()
+	ar.entries = append(ar.entries, copyAuditEntry(entry))
+	return nil
+}
+
+func (ar *inMemoryAuditRepository) GetAuditEntries(c context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	after, err := decodeAuditCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	var entries []*domain.AuditEntry
+	for _, entry := range ar.entries {
+		if query.Limit > 0 && len(entries) > query.Limit {
+			break
+		}
+		if entry.Sequence > after && matchesAuditQuery(entry, query) {
+			entries = append(entries, copyAuditEntry(entry))
+		}
+	}
+	return newAuditPage(entries, query.Limit), nil
+}
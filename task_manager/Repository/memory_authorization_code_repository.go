@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryAuthorizationCodeRepository is the map-backed counterpart of
+// authorizationCodeRepository. Expired codes are dropped on insert.
+type inMemoryAuthorizationCodeRepository struct {
+	mu     sync.Mutex
+	codes  map[string]*domain.AuthorizationCode
+	byHash map[string]string
+}
+
+func NewInMemoryAuthorizationCodeRepository() domain.AuthorizationCodeRepository {
+	return &inMemoryAuthorizationCodeRepository{
+		codes:  make(map[string]*domain.AuthorizationCode),
+		byHash: make(map[string]string),
+	}
+}
+
+func (ar *inMemoryAuthorizationCodeRepository) CreateAuthorizationCode(c context.Context, code *domain.AuthorizationCode) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	ar.purgeExpired(time.Now())
+	if _, ok := ar.byHash[code.CodeHash]; ok {
+		return domain.ErrTokenAlreadyExists
+	}
+	if _, ok := ar.codes[code.ID]; ok {
+		return domain.ErrTokenAlreadyExists
+	}
+	ar.codes[code.ID] = copyAuthorizationCode(code)
+	ar.byHash[code.CodeHash] = code.ID
+	return nil
+}
+
+func (ar *inMemoryAuthorizationCodeRepository) GetAuthorizationCodeByHash(c context.Context, codeHash string) (*domain.AuthorizationCode, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	id, ok := ar.byHash[codeHash]
+	if !ok {
+		return nil, domain.ErrTokenNotFound
+	}
+	return copyAuthorizationCode(ar.codes[id]), nil
+}
+
+func (ar *inMemoryAuthorizationCodeRepository) MarkAuthorizationCodeUsed(c context.Context, codeId string, usedAt time.Time) (bool, error) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	code, ok := ar.codes[codeId]
+	if !ok || code.UsedAt != nil {
+		return false, nil
+	}
+	code.UsedAt = &usedAt
+	return true, nil
+}
+
+func (ar *inMemoryAuthorizationCodeRepository) purgeExpired(now time.Time) {
+	for id, code := range ar.codes {
+		if !now.Before(code.ExpiresAt) {
+			delete(ar.byHash, code.CodeHash)
+			delete(ar.codes, id)
+		}
+	}
+}
+
+func copyAuthorizationCode(code *domain.AuthorizationCode) *domain.AuthorizationCode {
+	copied := *code
+	copied.Scopes = slices.Clone(code.Scopes)
+	if code.UsedAt != nil {
+		usedAt := *code.UsedAt
+		copied.UsedAt = &usedAt
+	}
+	return &copied
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryCommentRepository is the map-backed counterpart of
+// commentRepository.
+type inMemoryCommentRepository struct {
+	mu       sync.Mutex
+	comments map[string]*domain.Comment
+}
+
+func NewInMemoryCommentRepository() domain.CommentRepository {
+	return &inMemoryCommentRepository{
+		comments: make(map[string]*domain.Comment),
+	}
+}
+
+func (cr *inMemoryCommentRepository) CreateComment(c context.Context, comment *domain.Comment) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if _, ok := cr.comments[comment.ID]; ok {
+		return domain.ErrCommentAlreadyExists
+	}
+	cr.comments[comment.ID] = copyComment(comment)
+	return nil
+}
+
+func (cr *inMemoryCommentRepository) GetCommentByID(c context.Context, commentId string) (*domain.Comment, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	comment, ok := cr.comments[commentId]
+	if !ok {
+		return nil, domain.ErrCommentNotFound
+	}
+	return copyComment(comment), nil
+}
+
+func (cr *inMemoryCommentRepository) GetCommentsByTask(c context.Context, taskId string) ([]*domain.Comment, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	var comments []*domain.Comment
+	for _, comment := range cr.comments {
+		if comment.TaskID == taskId {
+			comments = append(comments, copyComment(comment))
+		}
+	}
+	slices.SortFunc(comments, func(a, b *domain.Comment) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return comments, nil
+}
+
+func (cr *inMemoryCommentRepository) EditComment(c context.Context, commentId string, body string, editedAt time.Time) (*domain.Comment, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	comment, ok := cr.comments[commentId]
+	if !ok || comment.Deleted() {
+		return nil, domain.ErrCommentNotFound
+	}
+	writtenAt := comment.CreatedAt
+	if comment.EditedAt != nil {
+		writtenAt = *comment.EditedAt
+	}
+	comment.Revisions = append(comment.Revisions, domain.CommentRevision{Body: comment.Body, WrittenAt: writtenAt})
+	comment.Body = body
+	comment.EditedAt = &editedAt
+	return copyComment(comment), nil
+}
+
+func (cr *inMemoryCommentRepository) DeleteComment(c context.Context, commentId string, deletedBy string, deletedAt time.Time) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	comment, ok := cr.comments[commentId]
+	if !ok || comment.Deleted() {
+		return domain.ErrCommentNotFound
+	}
+	comment.Body = ""
+	comment.Revisions = nil
+	comment.DeletedAt = &deletedAt
+	comment.DeletedBy = deletedBy
+	return nil
+}
+
+func (cr *inMemoryCommentRepository) CountCommentsByTask(c context.Context, taskIds []string) (map[string]int, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	counts := make(map[string]int, len(taskIds))
+	for _, comment := range cr.comments {
+		if !comment.Deleted() && slices.Contains(taskIds, comment.TaskID) {
+			counts[comment.TaskID]++
+		}
+	}
+	return counts, nil
+}
+
+func (cr *inMemoryCommentRepository) DeleteCommentsByTask(c context.Context, taskId string) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	for id, comment := range cr.comments {
+		if comment.TaskID == taskId {
+			delete(cr.comments, id)
+		}
+	}
+	return nil
+}
+
+func (cr *inMemoryCommentRepository) DeleteCommentsByTasks(c context.Context, taskIds []string) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	for id, comment := range cr.comments {
+		if slices.Contains(taskIds, comment.TaskID) {
+			delete(cr.comments, id)
+		}
+	}
+	return nil
+}
+
+func copyComment(comment *domain.Comment) *domain.Comment {
+	copied := *comment
+	copied.Revisions = slices.Clone(comment.Revisions)
+	return &copied
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryDependencyRepository is the slice-backed counterpart of
+// dependencyRepository. Dependencies are kept in the order they were added.
+type inMemoryDependencyRepository struct {
+	mu           sync.Mutex
+	dependencies []domain.TaskDependency
+}
+
+func NewInMemoryDependencyRepository() domain.DependencyRepository {
+	return &inMemoryDependencyRepository{}
+}
+
+func (dr *inMemoryDependencyRepository) AddDependency(c context.Context, dependency *domain.TaskDependency) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	for _, existing := range dr.dependencies {
+		if existing.TaskID == dependency.TaskID && existing.BlockerID == dependency.BlockerID {
+			return domain.ErrDependencyAlreadyExists
+		}
+	}
+	dr.dependencies = append(dr.dependencies, *dependency)
+	return nil
+}
+
+func (dr *inMemoryDependencyRepository) RemoveDependency(c context.Context, taskId string, blockerId string) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	dr.dependencies = slices.DeleteFunc(dr.dependencies, func(dependency domain.TaskDependency) bool {
+		return dependency.TaskID == taskId && dependency.BlockerID == blockerId
+	})
+	return nil
+}
+
+func (dr *inMemoryDependencyRepository) GetBlockers(c context.Context, taskId string) ([]*domain.TaskDependency, error) {
+	return dr.find(func(dependency domain.TaskDependency) bool { return dependency.TaskID == taskId }), nil
+}
+
+func (dr *inMemoryDependencyRepository) GetDependents(c context.Context, blockerId string) ([]*domain.TaskDependency, error) {
+	return dr.find(func(dependency domain.TaskDependency) bool { return dependency.BlockerID == blockerId }), nil
+}
+
+func (dr *inMemoryDependencyRepository) GetDependenciesByUser(c context.Context, userId string) ([]*domain.TaskDependency, error) {
+	return dr.find(func(dependency domain.TaskDependency) bool { return dependency.UserID == userId }), nil
+}
+
+func (dr *inMemoryDependencyRepository) DeleteDependenciesByTask(c context.Context, taskId string) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	dr.dependencies = slices.DeleteFunc(dr.dependencies, func(dependency domain.TaskDependency) bool {
+		return dependency.TaskID == taskId || dependency.BlockerID == taskId
+	})
+	return nil
+}
+
+func (dr *inMemoryDependencyRepository) DeleteDependenciesByUser(c context.Context, userId string) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	dr.dependencies = slices.DeleteFunc(dr.dependencies, func(dependency domain.TaskDependency) bool {
+		return dependency.UserID == userId
+	})
+	return nil
+}
+
+func (dr *inMemoryDependencyRepository) find(match func(domain.TaskDependency) bool) []*domain.TaskDependency {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	var found []*domain.TaskDependency
+	for _, dependency := range dr.dependencies {
+		if match(dependency) {
+			copied := dependency
+			found = append(found, &copied)
+		}
+	}
+	return found
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryLabelRepository is the map-backed counterpart of labelRepository.
+type inMemoryLabelRepository struct {
+	mu     sync.Mutex
+	labels map[string]*domain.Label
+}
+
+func NewInMemoryLabelRepository() domain.LabelRepository {
+	return &inMemoryLabelRepository{
+		labels: make(map[string]*domain.Label),
+	}
+}
+
+func (lr *inMemoryLabelRepository) CreateLabel(c context.Context, label *domain.Label) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if _, ok := lr.labels[label.ID]; ok || lr.nameTaken(label) {
+		return domain.ErrLabelAlreadyExists
+	}
+	stored := *label
+	lr.labels[label.ID] = &stored
+	return nil
+}
+
+func (lr *inMemoryLabelRepository) GetLabelByID(c context.Context, labelId string) (*domain.Label, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	label, ok := lr.labels[labelId]
+	if !ok {
+		return nil, domain.ErrLabelNotFound
+	}
+	found := *label
+	return &found, nil
+}
+
+func (lr *inMemoryLabelRepository) GetLabelsByUser(c context.Context, userId string) ([]*domain.Label, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	var labels []*domain.Label
+	for _, label := range lr.labels {
+		if label.UserID == userId {
+			found := *label
+			labels = append(labels, &found)
+		}
+	}
+	slices.SortFunc(labels, func(a, b *domain.Label) int {
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return labels, nil
+}
+
+func (lr *inMemoryLabelRepository) UpdateLabel(c context.Context, label *domain.Label) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	stored, ok := lr.labels[label.ID]
+	if !ok {
+		return domain.ErrLabelNotFound
+	}
+	if lr.nameTaken(&domain.Label{ID: stored.ID, UserID: stored.UserID, Name: label.Name}) {
+		return domain.ErrLabelAlreadyExists
+	}
+	stored.Name = label.Name
+	stored.Color = label.Color
+	return nil
+}
+
+func (lr *inMemoryLabelRepository) DeleteLabel(c context.Context, labelId string) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if _, ok := lr.labels[labelId]; !ok {
+		return domain.ErrLabelNotFound
+	}
+	delete(lr.labels, labelId)
+	return nil
+}
+
+// nameTaken reports whether another label of the same user already has
+// label's name, ignoring case. The caller holds mu.
+func (lr *inMemoryLabelRepository) nameTaken(label *domain.Label) bool {
+	for _, other := range lr.labels {
+		if other.ID != label.ID && other.UserID == label.UserID && strings.EqualFold(other.Name, label.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryLoginAttemptRepository is the map-backed counterpart of
+// loginAttemptRepository. Expired counters are dropped on write.
+type inMemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]*domain.LoginAttempts
+}
+
+func NewInMemoryLoginAttemptRepository() domain.LoginAttemptRepository {
+	return &inMemoryLoginAttemptRepository{
+		attempts: make(map[string]*domain.LoginAttempts),
+	}
+}
+
+func (lr *inMemoryLoginAttemptRepository) GetLoginAttempts(c context.Context, key string) (*domain.LoginAttempts, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	attempts, ok := lr.attempts[key]
+	if !ok || !time.Now().Before(attempts.ExpiresAt) {
+		return nil, nil
+	}
+	copied := *attempts
+	return &copied, nil
+}
+
+func (lr *inMemoryLoginAttemptRepository) RecordLoginFailure(c context.Context, key string, at time.Time, window time.Duration) (*domain.LoginAttempts, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	for k, attempts := range lr.attempts {
+		if !at.Before(attempts.ExpiresAt) {
+			delete(lr.attempts, k)
+		}
+	}
+	attempts, ok := lr.attempts[key]
+	if !ok {
+		attempts = &domain.LoginAttempts{Key: key}
+		lr.attempts[key] = attempts
+	}
+	attempts.Failures++
+	attempts.PreviousFailure = attempts.LastFailure
+	attempts.LastFailure = at
+	attempts.ExpiresAt = at.Add(window)
+
+	copied := *attempts
+	return &copied, nil
+}
+
+func (lr *inMemoryLoginAttemptRepository) ReleaseLoginAttempt(c context.Context, key string, recorded *domain.LoginAttempts) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	attempts, ok := lr.attempts[key]
+	if !ok || attempts.Failures == 0 {
+		return nil
+	}
+	if attempts.Failures == recorded.Failures && attempts.LastFailure.Equal(recorded.LastFailure) {
+		if attempts.Failures == 1 {
+			delete(lr.attempts, key)
+			return nil
+		}
+		attempts.LastFailure = recorded.PreviousFailure
+		attempts.PreviousFailure = time.Time{}
+	}
+	attempts.Failures--
+	return nil
+}
+
+func (lr *inMemoryLoginAttemptRepository) ResetLoginAttempts(c context.Context, key string) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	delete(lr.attempts, key)
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryOIDCClientRepository is the map-backed counterpart of
+// oidcClientRepository.
+type inMemoryOIDCClientRepository struct {
+	mu      sync.Mutex
+	clients map[string]*domain.OIDCClient
+}
+
+func NewInMemoryOIDCClientRepository() domain.OIDCClientRepository {
+	return &inMemoryOIDCClientRepository{
+		clients: make(map[string]*domain.OIDCClient),
+	}
+}
+
+func (or *inMemoryOIDCClientRepository) CreateOIDCClient(c context.Context, client *domain.OIDCClient) error {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	if _, ok := or.clients[client.ID]; ok {
+		return domain.ErrOIDCClientAlreadyExists
+	}
+	or.clients[client.ID] = copyOIDCClient(client)
+	return nil
+}
+
+func (or *inMemoryOIDCClientRepository) GetOIDCClientByID(c context.Context, clientId string) (*domain.OIDCClient, error) {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	client, ok := or.clients[clientId]
+	if !ok {
+		return nil, domain.ErrOIDCClientNotFound
+	}
+	return copyOIDCClient(client), nil
+}
+
+func (or *inMemoryOIDCClientRepository) GetOIDCClients(c context.Context) ([]*domain.OIDCClient, error) {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	var clients []*domain.OIDCClient
+	for _, client := range or.clients {
+		clients = append(clients, copyOIDCClient(client))
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if !clients[i].CreatedAt.Equal(clients[j].CreatedAt) {
+			return clients[i].CreatedAt.Before(clients[j].CreatedAt)
+		}
+		return clients[i].ID < clients[j].ID
+	})
+	return clients, nil
+}
+
+func (or *inMemoryOIDCClientRepository) DeleteOIDCClient(c context.Context, clientId string) error {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	if _, ok := or.clients[clientId]; !ok {
+		return domain.ErrOIDCClientNotFound
+	}
+	delete(or.clients, clientId)
+	return nil
+}
+
+func copyOIDCClient(client *domain.OIDCClient) *domain.OIDCClient {
+	copied := *client
+	copied.RedirectURIs = slices.Clone(client.RedirectURIs)
+	return &copied
+}
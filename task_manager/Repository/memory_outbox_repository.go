@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryOutboxRepository is the slice-backed counterpart of
+// outboxRepository. The in-memory store has no transactions, so events
+// are numbered as they are appended.
+type inMemoryOutboxRepository struct {
+	mu       sync.Mutex
+	events   []*domain.Event
+	sequence int64
+}
+
+func NewInMemoryOutboxRepository() domain.OutboxRepository {
+	return &inMemoryOutboxRepository{}
+}
+
+func copyEvent(event *domain.Event) *domain.Event {
+	copied := *event
+	if event.Task != nil {
+		task := *event.Task
+		task.Recurrence = cloneRecurrence(event.Task.Recurrence)
+		copied.Task = &task
+	}
+	if event.User != nil {
+		user := *event.User
+		copied.User = &user
+	}
+	return &copied
+}
+
+func (or *inMemoryOutboxRepository) AppendEvent(c context.Context, event *domain.Event) error {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	or.sequence++
+	event.Sequence = or.sequence
+	or.events = append(or.events, copyEvent(event))
+	return nil
+}
+
+func (or *inMemoryOutboxRepository) GetEventsAfter(c context.Context, sequence int64, limit int) ([]*domain.Event, error) {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	var events []*domain.Event
+	for _, event := range or.events {
+		if len(events) == limit {
+			break
+		}
+		if event.Sequence > sequence {
+			events = append(events, copyEvent(event))
+		}
+	}
+	return events, nil
+}
+
+func (or *inMemoryOutboxRepository) DeleteEvents(c context.Context, through int64, before time.Time) error {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	or.events = slices.DeleteFunc(or.events, func(event *domain.Event) bool {
+		return event.Sequence <= through && event.OccurredAt.Before(before)
+	})
+	return nil
+}
+
+// inMemoryOutboxCheckpointRepository is the map-backed counterpart of
+// outboxCheckpointRepository.
+type inMemoryOutboxCheckpointRepository struct {
+	mu          sync.Mutex
+	checkpoints map[string]*domain.OutboxCheckpoint
+}
+
+func NewInMemoryOutboxCheckpointRepository() domain.OutboxCheckpointRepository {
+	return &inMemoryOutboxCheckpointRepository{
+		checkpoints: make(map[string]*domain.OutboxCheckpoint),
+	}
+}
+
+func (cr *inMemoryOutboxCheckpointRepository) ClaimCheckpoint(c context.Context, consumer string, owner string, now time.Time, leaseUntil time.Time) (*domain.OutboxCheckpoint, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	checkpoint, ok := cr.checkpoints[consumer]
+	if !ok {
+		checkpoint = &domain.OutboxCheckpoint{Consumer: consumer, UpdatedAt: now}
+		cr.checkpoints[consumer] = checkpoint
+	} else if checkpoint.LeaseOwner != owner && checkpoint.LeaseUntil.After(now) {
+		return nil, domain.ErrOutboxLeaseHeld
+	}
+	checkpoint.LeaseOwner = owner
+	checkpoint.LeaseUntil = leaseUntil
+	claimed := *checkpoint
+	return &claimed, nil
+}
+
+func (cr *inMemoryOutboxCheckpointRepository) AdvanceCheckpoint(c context.Context, consumer string, owner string, sequence int64) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	checkpoint, ok := cr.checkpoints[consumer]
+	if !ok || checkpoint.LeaseOwner != owner {
+		return domain.ErrOutboxLeaseLost
+	}
+	checkpoint.Sequence = sequence
+	checkpoint.UpdatedAt = time.Now()
+	return nil
+}
+
+func (cr *inMemoryOutboxCheckpointRepository) GetCheckpoints(c context.Context) ([]*domain.OutboxCheckpoint, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	checkpoints := make([]*domain.OutboxCheckpoint, 0, len(cr.checkpoints))
+	for _, checkpoint := range cr.checkpoints {
+		copied := *checkpoint
+		checkpoints = append(checkpoints, &copied)
+	}
+	slices.SortFunc(checkpoints, func(a, b *domain.OutboxCheckpoint) int {
+		return strings.Compare(a.Consumer, b.Consumer)
+	})
+	return checkpoints, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryPasswordResetTokenRepository is the map-backed counterpart of
+// passwordResetTokenRepository. Expired tokens are dropped on insert.
+type inMemoryPasswordResetTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.PasswordResetToken
+	byHash map[string]string
+}
+
+func NewInMemoryPasswordResetTokenRepository() domain.PasswordResetTokenRepository {
+	return &inMemoryPasswordResetTokenRepository{
+		tokens: make(map[string]*domain.PasswordResetToken),
+		byHash: make(map[string]string),
+	}
+}
+
+func (pr *inMemoryPasswordResetTokenRepository) CreatePasswordResetToken(c context.Context, token *domain.PasswordResetToken) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.purgeExpired(time.Now())
+	if _, ok := pr.byHash[token.TokenHash]; ok {
+		return domain.ErrTokenAlreadyExists
+	}
+	if _, ok := pr.tokens[token.ID]; ok {
+		return domain.ErrTokenAlreadyExists
+	}
+	stored := *token
+	pr.tokens[token.ID] = &stored
+	pr.byHash[token.TokenHash] = token.ID
+	return nil
+}
+
+func (pr *inMemoryPasswordResetTokenRepository) GetPasswordResetTokenByHash(c context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	id, ok := pr.byHash[tokenHash]
+	if !ok {
+		return nil, domain.ErrTokenNotFound
+	}
+	token := *pr.tokens[id]
+	return &token, nil
+}
+
+func (pr *inMemoryPasswordResetTokenRepository) MarkPasswordResetTokenUsed(c context.Context, tokenId string, usedAt time.Time) (bool, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	token, ok := pr.tokens[tokenId]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &usedAt
+	return true, nil
+}
+
+func (pr *inMemoryPasswordResetTokenRepository) DeletePasswordResetTokensByUser(c context.Context, userId string) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	for id, token := range pr.tokens {
+		if token.UserID == userId {
+			delete(pr.byHash, token.TokenHash)
+			delete(pr.tokens, id)
+		}
+	}
+	return nil
+}
+
+func (pr *inMemoryPasswordResetTokenRepository) purgeExpired(now time.Time) {
+	for id, token := range pr.tokens {
+		if !now.Before(token.ExpiresAt) {
+			delete(pr.byHash, token.TokenHash)
+			delete(pr.tokens, id)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryPersonalAccessTokenRepository is the map-backed counterpart of
+// personalAccessTokenRepository.
+type inMemoryPersonalAccessTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.PersonalAccessToken
+}
+
+func NewInMemoryPersonalAccessTokenRepository() domain.PersonalAccessTokenRepository {
+	return &inMemoryPersonalAccessTokenRepository{
+		tokens: make(map[string]*domain.PersonalAccessToken),
+	}
+}
+
+func (pr *inMemoryPersonalAccessTokenRepository) CreatePersonalAccessToken(c context.Context, token *domain.PersonalAccessToken) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if _, ok := pr.tokens[token.ID]; ok {
+		return domain.ErrTokenAlreadyExists
+	}
+	for _, stored := range pr.tokens {
+		if stored.TokenHash == token.TokenHash {
+			return domain.ErrTokenAlreadyExists
+		}
+	}
+	pr.tokens[token.ID] = copyPersonalAccessToken(token)
+	return nil
+}
+
+func (pr *inMemoryPersonalAccessTokenRepository) GetPersonalAccessTokenByHash(c context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	for _, token := range pr.tokens {
+		if token.TokenHash == tokenHash {
+			return copyPersonalAccessToken(token), nil
+		}
+	}
+	return nil, domain.ErrTokenNotFound
+}
+
+func (pr *inMemoryPersonalAccessTokenRepository) GetPersonalAccessTokensByUser(c context.Context, userId string) ([]*domain.PersonalAccessToken, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	var tokens []*domain.PersonalAccessToken
+	for _, token := range pr.tokens {
+		if token.UserID == userId {
+			tokens = append(tokens, copyPersonalAccessToken(token))
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if !tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+		}
+		return tokens[i].ID < tokens[j].ID
+	})
+	return tokens, nil
+}
+
+func (pr *inMemoryPersonalAccessTokenRepository) DeletePersonalAccessToken(c context.Context, userId string, tokenId string) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	token, ok := pr.tokens[tokenId]
+	if !ok || token.UserID != userId {
+		return domain.ErrTokenNotFound
+	}
+	delete(pr.tokens, tokenId)
+	return nil
+}
+
+func (pr *inMemoryPersonalAccessTokenRepository) DeletePersonalAccessTokensByUser(c context.Context, userId string) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	for id, token := range pr.tokens {
+		if token.UserID == userId {
+			delete(pr.tokens, id)
+		}
+	}
+	return nil
+}
+
+func (pr *inMemoryPersonalAccessTokenRepository) TouchPersonalAccessToken(c context.Context, tokenId string, usedAt time.Time) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if token, ok := pr.tokens[tokenId]; ok {
+		token.LastUsedAt = &usedAt
+	}
+	return nil
+}
+
+func copyPersonalAccessToken(token *domain.PersonalAccessToken) *domain.PersonalAccessToken {
+	copied := *token
+	copied.Scopes = slices.Clone(token.Scopes)
+	if token.LastUsedAt != nil {
+		usedAt := *token.LastUsedAt
+		copied.LastUsedAt = &usedAt
+	}
+	return &copied
+}
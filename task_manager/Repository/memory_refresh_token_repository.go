@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryRefreshTokenRepository is the map-backed counterpart of
+// refreshTokenRepository. Expired tokens are dropped on insert.
+type inMemoryRefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.RefreshToken
+	byHash map[string]string
+}
+
+func NewInMemoryRefreshTokenRepository() domain.RefreshTokenRepository {
+	return &inMemoryRefreshTokenRepository{
+		tokens: make(map[string]*domain.RefreshToken),
+		byHash: make(map[string]string),
+	}
+}
+
+func (rr *inMemoryRefreshTokenRepository) CreateRefreshToken(c context.Context, token *domain.RefreshToken) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	rr.purgeExpired(time.Now())
+	if _, ok := rr.byHash[token.TokenHash]; ok {
+		return domain.ErrTokenAlreadyExists
+	}
+	if _, ok := rr.tokens[token.ID]; ok {
+		return domain.ErrTokenAlreadyExists
+	}
+	stored := *token
+	rr.tokens[token.ID] = &stored
+	rr.byHash[token.TokenHash] = token.ID
+	return nil
+}
+
+func (rr *inMemoryRefreshTokenRepository) GetRefreshTokenByHash(c context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	id, ok := rr.byHash[tokenHash]
+	if !ok {
+		return nil, domain.ErrTokenNotFound
+	}
+	token := *rr.tokens[id]
+	return &token, nil
+}
+
+func (rr *inMemoryRefreshTokenRepository) MarkRefreshTokenUsed(c context.Context, tokenId string, usedAt time.Time) (bool, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	token, ok := rr.tokens[tokenId]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &usedAt
+	return true, nil
+}
+
+func (rr *inMemoryRefreshTokenRepository) RevokeRefreshTokenFamily(c context.Context, familyId string, revokedAt time.Time) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	for _, token := range rr.tokens {
+		if token.FamilyID == familyId && token.RevokedAt == nil {
+			at := revokedAt
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (rr *inMemoryRefreshTokenRepository) purgeExpired(now time.Time) {
+	for id, token := range rr.tokens {
+		if !now.Before(token.ExpiresAt) {
+			delete(rr.byHash, token.TokenHash)
+			delete(rr.tokens, id)
+		}
+	}
+}
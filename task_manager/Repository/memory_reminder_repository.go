@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryReminderRepository is the map-backed counterpart of
+// reminderRepository.
+type inMemoryReminderRepository struct {
+	mu        sync.Mutex
+	reminders map[string]*domain.Reminder
+}
+
+func NewInMemoryReminderRepository() domain.ReminderRepository {
+	return &inMemoryReminderRepository{
+		reminders: make(map[string]*domain.Reminder),
+	}
+}
+
+func (rr *inMemoryReminderRepository) CreateReminder(c context.Context, reminder *domain.Reminder) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	for _, other := range rr.reminders {
+		if other.ID == reminder.ID ||
+			other.UserID == reminder.UserID && other.Lead == reminder.Lead && other.Channel == reminder.Channel {
+			return domain.ErrReminderAlreadyExists
+		}
+	}
+	stored := *reminder
+	rr.reminders[reminder.ID] = &stored
+	return nil
+}
+
+func (rr *inMemoryReminderRepository) GetReminderByID(c context.Context, reminderId string) (*domain.Reminder, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	reminder, ok := rr.reminders[reminderId]
+	if !ok {
+		return nil, domain.ErrReminderNotFound
+	}
+	found := *reminder
+	return &found, nil
+}
+
+func (rr *inMemoryReminderRepository) GetRemindersByUser(c context.Context, userId string) ([]*domain.Reminder, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	var reminders []*domain.Reminder
+	for _, reminder := range rr.reminders {
+		if reminder.UserID == userId {
+			found := *reminder
+			reminders = append(reminders, &found)
+		}
+	}
+	slices.SortFunc(reminders, func(a, b *domain.Reminder) int {
+		if order := cmp.Compare(b.Lead, a.Lead); order != 0 {
+			return order
+		}
+		return strings.Compare(a.Channel, b.Channel)
+	})
+	return reminders, nil
+}
+
+func (rr *inMemoryReminderRepository) GetReminderLeads(c context.Context) ([]time.Duration, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	var leads []time.Duration
+	for _, reminder := range rr.reminders {
+		if !slices.Contains(leads, reminder.Lead) {
+			leads = append(leads, reminder.Lead)
+		}
+	}
+	return leads, nil
+}
+
+func (rr *inMemoryReminderRepository) DeleteReminder(c context.Context, reminderId string) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if _, ok := rr.reminders[reminderId]; !ok {
+		return domain.ErrReminderNotFound
+	}
+	delete(rr.reminders, reminderId)
+	return nil
+}
+
+// inMemoryReminderDeliveryRepository is the map-backed counterpart of
+// reminderDeliveryRepository. Its mutex makes claims atomic, but only
+// within one process.
+type inMemoryReminderDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries map[string]*domain.ReminderDelivery
+}
+
+func NewInMemoryReminderDeliveryRepository() domain.ReminderDeliveryRepository {
+	return &inMemoryReminderDeliveryRepository{
+		deliveries: make(map[string]*domain.ReminderDelivery),
+	}
+}
+
+func (dr *inMemoryReminderDeliveryRepository) EnqueueReminderDelivery(c context.Context, delivery *domain.ReminderDelivery) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	if _, ok := dr.deliveries[delivery.ID]; !ok {
+		stored := *delivery
+		dr.deliveries[delivery.ID] = &stored
+	}
+	return nil
+}
+
+func (dr *inMemoryReminderDeliveryRepository) ClaimReminderDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.ReminderDelivery, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	var due []*domain.ReminderDelivery
+	for _, delivery := range dr.deliveries {
+		if delivery.Status == domain.ReminderPending && !delivery.SendAt.After(now) && !delivery.LeaseUntil.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sortReminderDeliveries(due)
+
+	var claimed []*domain.ReminderDelivery
+	for _, delivery := range due[:min(limit, len(due))] {
+		delivery.LeaseOwner = owner
+		delivery.LeaseUntil = leaseUntil
+		delivery.Attempts++
+		found := *delivery
+		claimed = append(claimed, &found)
+	}
+	return claimed, nil
+}
+
+func (dr *inMemoryReminderDeliveryRepository) FinishReminderDelivery(c context.Context, delivery *domain.ReminderDelivery, owner string) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	stored, ok := dr.deliveries[delivery.ID]
+	if !ok || stored.LeaseOwner != owner {
+		return domain.ErrReminderLeaseLost
+	}
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.LeaseOwner = ""
+	stored.LeaseUntil = delivery.LeaseUntil
+	stored.LastError = delivery.LastError
+	stored.SentAt = nil
+	if delivery.SentAt != nil {
+		sentAt := *delivery.SentAt
+		stored.SentAt = &sentAt
+	}
+	return nil
+}
+
+// sortReminderDeliveries orders deliveries oldest first, the order they are
+// claimed in.
+func sortReminderDeliveries(deliveries []*domain.ReminderDelivery) {
+	slices.SortFunc(deliveries, func(a, b *domain.ReminderDelivery) int {
+		if order := a.SendAt.Compare(b.SendAt); order != 0 {
+			return order
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
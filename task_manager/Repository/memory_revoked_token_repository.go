@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryRevokedTokenRepository is the map-backed counterpart of
+// revokedTokenRepository, mapping jti to the token's expiry.
+type inMemoryRevokedTokenRepository struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+func NewInMemoryRevokedTokenRepository() domain.RevokedTokenRepository {
+	return &inMemoryRevokedTokenRepository{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+func (rr *inMemoryRevokedTokenRepository) RevokeToken(c context.Context, tokenId string, expiresAt time.Time) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	now := time.Now()
+	for id, exp := range rr.revoked {
+		if !now.Before(exp) {
+			delete(rr.revoked, id)
+		}
+	}
+	rr.revoked[tokenId] = expiresAt
+	return nil
+}
+
+func (rr *inMemoryRevokedTokenRepository) IsTokenRevoked(c context.Context, tokenId string) (bool, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	_, ok := rr.revoked[tokenId]
+	return ok, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryRoleRepository is the map-backed counterpart of roleRepository.
+type inMemoryRoleRepository struct {
+	mu    sync.RWMutex
+	roles map[string]*domain.Role
+}
+
+func NewInMemoryRoleRepository() domain.RoleRepository {
+	return &inMemoryRoleRepository{
+		roles: make(map[string]*domain.Role),
+	}
+}
+
+func (rr *inMemoryRoleRepository) CreateRole(c context.Context, role *domain.Role) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if _, ok := rr.roles[role.Name]; ok {
+		return domain.ErrRoleAlreadyExists
+	}
+	rr.roles[role.Name] = copyRole(role)
+	return nil
+}
+
+func (rr *inMemoryRoleRepository) GetRoleByName(c context.Context, name string) (*domain.Role, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	role, ok := rr.roles[name]
+	if !ok {
+		return nil, domain.ErrRoleNotFound
+	}
+	return copyRole(role), nil
+}
+
+func (rr *inMemoryRoleRepository) GetAllRoles(c context.Context) ([]*domain.Role, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	var roles []*domain.Role
+	for _, role := range rr.roles {
+		roles = append(roles, copyRole(role))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func copyRole(role *domain.Role) *domain.Role {
+	r := *role
+	r.Permissions = slices.Clone(role.Permissions)
+	return &r
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemorySigningKeyRepository is the slice-backed counterpart of
+// signingKeyRepository. Expired keys are dropped on insert.
+type inMemorySigningKeyRepository struct {
+	mu   sync.Mutex
+	keys []*domain.SigningKey
+}
+
+func NewInMemorySigningKeyRepository() domain.SigningKeyRepository {
+	return &inMemorySigningKeyRepository{}
+}
+
+func (kr *inMemorySigningKeyRepository) CreateSigningKey(c context.Context, key *domain.SigningKey) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	now := time.Now()
+	kr.keys = slices.DeleteFunc(kr.keys, func(stored *domain.SigningKey) bool {
+		return !now.Before(stored.ExpiresAt)
+	})
+	for _, stored := range kr.keys {
+		if stored.ID == key.ID {
+			return domain.ErrTokenAlreadyExists
+		}
+	}
+	kr.keys = append(kr.keys, copySigningKey(key))
+	return nil
+}
+
+func (kr *inMemorySigningKeyRepository) GetSigningKeys(c context.Context, now time.Time) ([]*domain.SigningKey, error) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	var keys []*domain.SigningKey
+	for _, key := range kr.keys {
+		if now.Before(key.ExpiresAt) {
+			keys = append(keys, copySigningKey(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].NotBefore.Equal(keys[j].NotBefore) {
+			return keys[i].NotBefore.Before(keys[j].NotBefore)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+func copySigningKey(key *domain.SigningKey) *domain.SigningKey {
+	copied := *key
+	copied.PrivateKey = slices.Clone(key.PrivateKey)
+	return &copied
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryTaskRepository keeps tasks in a map guarded by a RWMutex. It is
+// meant for local runs and tests where no MongoDB instance is available.
+type inMemoryTaskRepository struct {
+	mu    sync.RWMutex
+	tasks map[string]*domain.Task
+	order []string
+}
+
+func NewInMemoryTaskRepository() domain.TaskRepository {
+	return &inMemoryTaskRepository{
+		tasks: make(map[string]*domain.Task),
+	}
+}
+
+func (tr *inMemoryTaskRepository) GetAllTasks(c context.Context, query domain.TaskQuery) (*domain.TaskPage, error) {
+	tr.mu.RLock()
+	tasks := make([]*domain.Task, 0, len(tr.order))
+	for _, taskID := range tr.order {
+		task := *tr.tasks[taskID]
+		tasks = append(tasks, &task)
+	}
+	tr.mu.RUnlock()
+
+	return pageTasks(tasks, query)
+}
+
+func (tr *inMemoryTaskRepository) GetTaskByID(c context.Context, id string) (*domain.Task, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	t, ok := tr.tasks[id]
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	task := *t
+	return &task, nil
+}
+
+func (tr *inMemoryTaskRepository) CreateTask(c context.Context, task *domain.Task) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if _, ok := tr.tasks[task.ID]; ok {
+		return domain.ErrTaskAlreadyExists
+	}
+	stored := *task
+	stored.Recurrence = cloneRecurrence(task.Recurrence)
+	tr.tasks[task.ID] = &stored
+	tr.order = append(tr.order, task.ID)
+	return nil
+}
+
+func (tr *inMemoryTaskRepository) UpdateTask(c context.Context, id string, task *domain.Task) (*domain.Task, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	existing, ok := tr.tasks[id]
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	stored := *task
+	stored.ID = id
+	stored.ParentID = existing.ParentID
+	stored.Checklist = existing.Checklist
+	stored.LabelIDs = existing.LabelIDs
+	stored.Recurrence = existing.Recurrence
+	tr.tasks[id] = &stored
+	return task, nil
+}
+
+func (tr *inMemoryTaskRepository) DeleteTask(c context.Context, id string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if _, ok := tr.tasks[id]; !ok {
+		return domain.ErrTaskNotFound
+	}
+	delete(tr.tasks, id)
+	for i, taskID := range tr.order {
+		if taskID == id {
+			tr.order = append(tr.order[:i], tr.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (tr *inMemoryTaskRepository) DeleteTasksByUser(c context.Context, userId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	kept := tr.order[:0]
+	for _, id := range tr.order {
+		if tr.tasks[id].UserID == userId {
+			delete(tr.tasks, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	tr.order = kept
+	return nil
+}
+
+// Label slices are shared with the copies handed out by the getters, so
+// they are replaced rather than modified in place.
+
+func (tr *inMemoryTaskRepository) AddTaskLabel(c context.Context, taskId string, labelId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task, ok := tr.tasks[taskId]
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	if !slices.Contains(task.LabelIDs, labelId) {
+		task.LabelIDs = append(slices.Clip(task.LabelIDs), labelId)
+	}
+	return nil
+}
+
+func (tr *inMemoryTaskRepository) RemoveTaskLabel(c context.Context, taskId string, labelId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task, ok := tr.tasks[taskId]
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	task.LabelIDs = withoutLabel(task.LabelIDs, labelId)
+	return nil
+}
+
+func (tr *inMemoryTaskRepository) RemoveLabelFromTasks(c context.Context, userId string, labelId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	for _, task := range tr.tasks {
+		if task.UserID == userId {
+			task.LabelIDs = withoutLabel(task.LabelIDs, labelId)
+		}
+	}
+	return nil
+}
+
+func withoutLabel(labelIDs []string, labelId string) []string {
+	if !slices.Contains(labelIDs, labelId) {
+		return labelIDs
+	}
+	return slices.DeleteFunc(slices.Clone(labelIDs), func(id string) bool { return id == labelId })
+}
+
+func (tr *inMemoryTaskRepository) GetSubtasks(c context.Context, parentId string) ([]*domain.Task, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	var subtasks []*domain.Task
+	for _, id := range tr.order {
+		if task := tr.tasks[id]; task.ParentID == parentId {
+			subtask := *task
+			subtasks = append(subtasks, &subtask)
+		}
+	}
+	slices.SortStableFunc(subtasks, func(a, b *domain.Task) int {
+		return compareTasks(a, b, domain.TaskSortDueDate)
+	})
+	return subtasks, nil
+}
+
+func (tr *inMemoryTaskRepository) CountSubtasksByStatus(c context.Context, parentIds []string) (map[string]map[string]int, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	counts := make(map[string]map[string]int)
+	for _, task := range tr.tasks {
+		if task.ParentID == "" || !slices.Contains(parentIds, task.ParentID) {
+			continue
+		}
+		if counts[task.ParentID] == nil {
+			counts[task.ParentID] = make(map[string]int)
+		}
+		counts[task.ParentID][task.Status]++
+	}
+	return counts, nil
+}
+
+func (tr *inMemoryTaskRepository) DetachSubtasks(c context.Context, parentId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	for _, task := range tr.tasks {
+		if task.ParentID == parentId {
+			task.ParentID = ""
+		}
+	}
+	return nil
+}
+
+func (tr *inMemoryTaskRepository) SetTaskChecklist(c context.Context, taskId string, checklist []domain.ChecklistItem) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task, ok := tr.tasks[taskId]
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	task.Checklist = slices.Clone(checklist)
+	return nil
+}
+
+func (tr *inMemoryTaskRepository) GetTasksBySeries(c context.Context, seriesId string) ([]*domain.Task, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	var series []*domain.Task
+	for _, id := range tr.order {
+		if task := tr.tasks[id]; task.Recurrence != nil && task.Recurrence.SeriesID == seriesId {
+			occurrence := *task
+			series = append(series, &occurrence)
+		}
+	}
+	slices.SortStableFunc(series, func(a, b *domain.Task) int {
+		if order := a.Recurrence.Occurrence.Compare(b.Recurrence.Occurrence); order != 0 {
+			return order
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return series, nil
+}
+
+func (tr *inMemoryTaskRepository) GetTasksDueBetween(c context.Context, from time.Time, to time.Time) ([]*domain.Task, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	var due []*domain.Task
+	for _, id := range tr.order {
+		if task := tr.tasks[id]; !task.DueDate.Before(from) && task.DueDate.Before(to) {
+			found := *task
+			due = append(due, &found)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *domain.Task) int {
+		if order := a.DueDate.Compare(b.DueDate); order != 0 {
+			return order
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return due, nil
+}
+
+// Like label slices, a stored recurrence is shared with the copies handed
+// out, so it is replaced rather than modified in place.
+
+func (tr *inMemoryTaskRepository) SetTaskRecurrence(c context.Context, taskId string, recurrence *domain.Recurrence) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task, ok := tr.tasks[taskId]
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	task.Recurrence = cloneRecurrence(recurrence)
+	return nil
+}
+
+func cloneRecurrence(recurrence *domain.Recurrence) *domain.Recurrence {
+	if recurrence == nil {
+		return nil
+	}
+	clone := *recurrence
+	return &clone
+}
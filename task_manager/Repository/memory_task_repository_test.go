@@ -0,0 +1,142 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type inMemoryTaskRepositoryTestSuite struct {
+	suite.Suite
+	taskRepo domain.TaskRepository
+	ctx      context.Context
+}
+
+func TestInMemoryTaskRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(inMemoryTaskRepositoryTestSuite))
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) SetupTest() {
+	s.taskRepo = repository.NewInMemoryTaskRepository()
+	s.ctx = context.Background()
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestCreateAndGetTaskByID() {
+	assert := assert.New(s.T())
+
+	task := &domain.Task{ID: "task-1", UserID: "user-1", Title: "Test Task", Status: "pending"}
+	assert.NoError(s.taskRepo.CreateTask(s.ctx, task))
+
+	found, err := s.taskRepo.GetTaskByID(s.ctx, "task-1")
+	assert.NoError(err)
+	assert.Equal(task, found)
+
+	// Mutating the returned copy must not leak into the store.
+	found.Title = "Changed"
+	again, _ := s.taskRepo.GetTaskByID(s.ctx, "task-1")
+	assert.Equal("Test Task", again.Title)
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestCreateTask_Duplicate() {
+	task := &domain.Task{ID: "dup", UserID: "user-1"}
+	s.Require().NoError(s.taskRepo.CreateTask(s.ctx, task))
+
+	err := s.taskRepo.CreateTask(s.ctx, task)
+	assert.ErrorIs(s.T(), err, domain.ErrTaskAlreadyExists)
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestGetAllTasks() {
+	assert := assert.New(s.T())
+
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "1", UserID: "user-1", Title: "Task 1"})
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "2", UserID: "user-2", Title: "Task 2"})
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "3", UserID: "user-1", Title: "Task 3"})
+
+	page, err := s.taskRepo.GetAllTasks(s.ctx, domain.TaskQuery{UserID: "user-1"})
+	assert.NoError(err)
+	assert.Len(page.Tasks, 2)
+	assert.Equal("1", page.Tasks[0].ID)
+	assert.Equal("3", page.Tasks[1].ID)
+
+	page, err = s.taskRepo.GetAllTasks(s.ctx, domain.TaskQuery{UserID: "nobody"})
+	assert.NoError(err)
+	assert.Empty(page.Tasks)
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestGetAllTasks_Query() {
+	testTaskQueries(s.T(), s.taskRepo)
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestUpdateTask() {
+	assert := assert.New(s.T())
+
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "task-update", UserID: "user-x", Title: "Old"})
+
+	updated, err := s.taskRepo.UpdateTask(s.ctx, "task-update", &domain.Task{UserID: "user-x", Title: "Updated"})
+	assert.NoError(err)
+	assert.Equal("Updated", updated.Title)
+
+	found, err := s.taskRepo.GetTaskByID(s.ctx, "task-update")
+	assert.NoError(err)
+	assert.Equal("task-update", found.ID)
+	assert.Equal("Updated", found.Title)
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestUpdateTask_NotFound() {
+	_, err := s.taskRepo.UpdateTask(s.ctx, "missing", &domain.Task{Title: "x"})
+	assert.ErrorIs(s.T(), err, domain.ErrTaskNotFound)
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestDeleteTask() {
+	assert := assert.New(s.T())
+
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "delete-me", UserID: "user-x"})
+
+	assert.NoError(s.taskRepo.DeleteTask(s.ctx, "delete-me"))
+
+	result, err := s.taskRepo.GetTaskByID(s.ctx, "delete-me")
+	assert.Nil(result)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+	assert.ErrorIs(s.taskRepo.DeleteTask(s.ctx, "delete-me"), domain.ErrTaskNotFound)
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestConcurrentAccess() {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: fmt.Sprintf("task-%d", i), UserID: "user-1"})
+			_, _ = s.taskRepo.GetAllTasks(s.ctx, domain.TaskQuery{UserID: "user-1"})
+		}(i)
+	}
+	wg.Wait()
+
+	page, err := s.taskRepo.GetAllTasks(s.ctx, domain.TaskQuery{UserID: "user-1"})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Tasks, 50)
+}
+
+func (s *inMemoryTaskRepositoryTestSuite) TestDeleteTasksByUser() {
+	assert := assert.New(s.T())
+
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "gone-1", UserID: "leaver", Title: "One"})
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "gone-2", UserID: "leaver", Title: "Two"})
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "kept", UserID: "stayer", Title: "Three"})
+
+	assert.NoError(s.taskRepo.DeleteTasksByUser(s.ctx, "leaver"))
+	assert.NoError(s.taskRepo.DeleteTasksByUser(s.ctx, "leaver"), "deleting nothing is not an error")
+
+	page, err := s.taskRepo.GetAllTasks(s.ctx, domain.TaskQuery{UserID: "leaver"})
+	assert.NoError(err)
+	assert.Empty(page.Tasks)
+	_, err = s.taskRepo.GetTaskByID(s.ctx, "kept")
+	assert.NoError(err)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryTwoFactorRepository is the map-backed counterpart of
+// twoFactorRepository.
+type inMemoryTwoFactorRepository struct {
+	mu         sync.Mutex
+	twoFactors map[string]*domain.TwoFactor
+}
+
+func NewInMemoryTwoFactorRepository() domain.TwoFactorRepository {
+	return &inMemoryTwoFactorRepository{
+		twoFactors: make(map[string]*domain.TwoFactor),
+	}
+}
+
+func (tr *inMemoryTwoFactorRepository) GetTwoFactor(c context.Context, userId string) (*domain.TwoFactor, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	twoFactor, ok := tr.twoFactors[userId]
+	if !ok {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	return copyTwoFactor(twoFactor), nil
+}
+
+func (tr *inMemoryTwoFactorRepository) SaveTwoFactor(c context.Context, twoFactor *domain.TwoFactor) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.twoFactors[twoFactor.UserID] = copyTwoFactor(twoFactor)
+	return nil
+}
+
+func (tr *inMemoryTwoFactorRepository) DeleteTwoFactor(c context.Context, userId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	delete(tr.twoFactors, userId)
+	return nil
+}
+
+func (tr *inMemoryTwoFactorRepository) UseTOTPStep(c context.Context, userId string, step int64) (bool, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	twoFactor, ok := tr.twoFactors[userId]
+	if !ok || step <= twoFactor.LastUsedStep {
+		return false, nil
+	}
+	twoFactor.LastUsedStep = step
+	return true, nil
+}
+
+func (tr *inMemoryTwoFactorRepository) UseRecoveryCode(c context.Context, userId string, codeHash string) (bool, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	twoFactor, ok := tr.twoFactors[userId]
+	if !ok {
+		return false, nil
+	}
+	i := slices.Index(twoFactor.RecoveryCodes, codeHash)
+	if i < 0 {
+		return false, nil
+	}
+	twoFactor.RecoveryCodes = slices.Delete(twoFactor.RecoveryCodes, i, i+1)
+	return true, nil
+}
+
+func copyTwoFactor(twoFactor *domain.TwoFactor) *domain.TwoFactor {
+	copied := *twoFactor
+	copied.RecoveryCodes = slices.Clone(twoFactor.RecoveryCodes)
+	if twoFactor.EnabledAt != nil {
+		enabledAt := *twoFactor.EnabledAt
+		copied.EnabledAt = &enabledAt
+	}
+	return &copied
+}
+
+// inMemoryTwoFactorRoleRepository is the map-backed counterpart of
+// twoFactorRoleRepository.
+type inMemoryTwoFactorRoleRepository struct {
+	mu    sync.Mutex
+	roles map[string]bool
+}
+
+func NewInMemoryTwoFactorRoleRepository() domain.TwoFactorRoleRepository {
+	return &inMemoryTwoFactorRoleRepository{
+		roles: make(map[string]bool),
+	}
+}
+
+func (rr *inMemoryTwoFactorRoleRepository) GetTwoFactorRoles(c context.Context) ([]string, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	var roles []string
+	for role := range rr.roles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles, nil
+}
+
+func (rr *inMemoryTwoFactorRoleRepository) SetTwoFactorRequired(c context.Context, role string, required bool) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if required {
+		rr.roles[role] = true
+	} else {
+		delete(rr.roles, role)
+	}
+	return nil
+}
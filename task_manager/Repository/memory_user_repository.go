@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryUserRepository is the map-backed counterpart of userRepository.
+type inMemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]*domain.User
+	order []string
+}
+
+func NewInMemoryUserRepository() domain.UserRepository {
+	return &inMemoryUserRepository{
+		users: make(map[string]*domain.User),
+	}
+}
+
+func (ur *inMemoryUserRepository) GetAllUsers(c context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	after, err := decodeUserCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	var users []*domain.User
+	for _, id := range ur.order {
+		u := ur.users[id]
+		if (after == "" || u.Username > after) && matchesUserSearch(u, query.Search) {
+			user := *u
+			users = append(users, &user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	if query.Limit > 0 && len(users) > query.Limit+1 {
+		users = users[:query.Limit+1]
+	}
+	return newUserPage(users, query.Limit), nil
+}
+
+func (ur *inMemoryUserRepository) GetUserByID(c context.Context, id string) (*domain.User, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	user := *u
+	return &user, nil
+}
+
+func (ur *inMemoryUserRepository) GetUserByEmail(c context.Context, email string) (*domain.User, error) {
+	return ur.findFirst(func(u *domain.User) bool { return u.Email == email })
+}
+
+func (ur *inMemoryUserRepository) GetUserByUsername(c context.Context, username string) (*domain.User, error) {
+	return ur.findFirst(func(u *domain.User) bool { return u.Username == username })
+}
+
+func (ur *inMemoryUserRepository) CreateUser(c context.Context, user *domain.User) (*domain.User, error) {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	if _, ok := ur.users[user.ID]; ok {
+		return nil, domain.ErrUserAlreadyExists
+	}
+	stored := *user
+	ur.users[user.ID] = &stored
+	ur.order = append(ur.order, user.ID)
+	return user, nil
+}
+
+func (ur *inMemoryUserRepository) PromoteUserToAdmin(c context.Context, id string) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	u.Role = domain.RoleAdmin
+	u.TokenVersion++
+	return nil
+}
+
+func (ur *inMemoryUserRepository) UpdateUserRole(c context.Context, id string, role string) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	if role != domain.RoleAdmin && ur.isLastAdmin(u) {
+		return domain.ErrLastAdmin
+	}
+	u.Role = role
+	u.TokenVersion++
+	return nil
+}
+
+func (ur *inMemoryUserRepository) SetUserDisabled(c context.Context, id string, disabled bool) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	if disabled && ur.isLastAdmin(u) {
+		return domain.ErrLastAdmin
+	}
+	u.Disabled = disabled
+	u.TokenVersion++
+	return nil
+}
+
+func (ur *inMemoryUserRepository) MarkEmailVerified(c context.Context, id string) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	u.EmailUnverified = false
+	return nil
+}
+
+func (ur *inMemoryUserRepository) UpdateUserPassword(c context.Context, id string, passwordHash string) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	u.Password = passwordHash
+	u.TokenVersion++
+	return nil
+}
+
+func (ur *inMemoryUserRepository) DeleteUser(c context.Context, id string) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	if ur.isLastAdmin(u) {
+		return domain.ErrLastAdmin
+	}
+	delete(ur.users, id)
+	ur.order = slices.DeleteFunc(ur.order, func(other string) bool { return other == id })
+	return nil
+}
+
+// isLastAdmin tells whether u is the only active admin. Callers hold ur.mu
+// for writing, so the check and their change are one step.
+func (ur *inMemoryUserRepository) isLastAdmin(u *domain.User) bool {
+	if u.Role != domain.RoleAdmin || u.Disabled {
+		return false
+	}
+	for _, other := range ur.users {
+		if other.ID != u.ID && other.Role == domain.RoleAdmin && !other.Disabled {
+			return false
+		}
+	}
+	return true
+}
+
+func (ur *inMemoryUserRepository) CountActiveAdmins(c context.Context) (int, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	n := 0
+	for _, u := range ur.users {
+		if u.Role == domain.RoleAdmin && !u.Disabled {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (ur *inMemoryUserRepository) GetUsersByRole(c context.Context, role string) ([]*domain.User, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	var users []*domain.User
+	for _, u := range ur.users {
+		if u.Role == role {
+			user := *u
+			users = append(users, &user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users, nil
+}
+
+func (ur *inMemoryUserRepository) BumpTokenVersion(c context.Context, id string) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	u.TokenVersion++
+	return nil
+}
+
+func (ur *inMemoryUserRepository) UserExists(c context.Context) (bool, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	return len(ur.users) > 0, nil
+}
+
+// findFirst returns a copy of the first user, in insertion order, matching match.
+func (ur *inMemoryUserRepository) findFirst(match func(*domain.User) bool) (*domain.User, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	for _, id := range ur.order {
+		if u := ur.users[id]; match(u) {
+			user := *u
+			return &user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
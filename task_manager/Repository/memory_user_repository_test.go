@@ -0,0 +1,123 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type inMemoryUserRepositoryTestSuite struct {
+	suite.Suite
+	repo domain.UserRepository
+	ctx  context.Context
+}
+
+func TestInMemoryUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(inMemoryUserRepositoryTestSuite))
+}
+
+func (s *inMemoryUserRepositoryTestSuite) SetupTest() {
+	s.repo = repository.NewInMemoryUserRepository()
+	s.ctx = context.Background()
+}
+
+func (s *inMemoryUserRepositoryTestSuite) TestCreateAndGetUser() {
+	assert := assert.New(s.T())
+	user := &domain.User{
+		ID:       "user-123",
+		Username: "johndoe",
+		Email:    "john@example.com",
+		Password: "hashed-password",
+		Role:     "user",
+	}
+
+	created, err := s.repo.CreateUser(s.ctx, user)
+	assert.NoError(err)
+	assert.Equal("johndoe", created.Username)
+
+	found, err := s.repo.GetUserByID(s.ctx, "user-123")
+	assert.NoError(err)
+	assert.Equal("john@example.com", found.Email)
+
+	foundByEmail, err := s.repo.GetUserByEmail(s.ctx, "john@example.com")
+	assert.NoError(err)
+	assert.Equal("johndoe", foundByEmail.Username)
+
+	foundByUsername, err := s.repo.GetUserByUsername(s.ctx, "johndoe")
+	assert.NoError(err)
+	assert.Equal("john@example.com", foundByUsername.Email)
+}
+
+func (s *inMemoryUserRepositoryTestSuite) TestGetUser_NotFound() {
+	assert := assert.New(s.T())
+
+	_, err := s.repo.GetUserByID(s.ctx, "missing")
+	assert.ErrorIs(err, domain.ErrUserNotFound)
+	_, err = s.repo.GetUserByEmail(s.ctx, "missing@example.com")
+	assert.ErrorIs(err, domain.ErrUserNotFound)
+	_, err = s.repo.GetUserByUsername(s.ctx, "missing")
+	assert.ErrorIs(err, domain.ErrUserNotFound)
+}
+
+func (s *inMemoryUserRepositoryTestSuite) TestPromoteUserToAdmin() {
+	_, err := s.repo.CreateUser(s.ctx, &domain.User{ID: "user-456", Username: "janedoe", Email: "jane@example.com", Role: "user"})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.PromoteUserToAdmin(s.ctx, "user-456"))
+
+	updated, err := s.repo.GetUserByID(s.ctx, "user-456")
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "admin", updated.Role)
+	assert.Equal(s.T(), 1, updated.TokenVersion, "promotion must invalidate existing tokens")
+
+	assert.ErrorIs(s.T(), s.repo.PromoteUserToAdmin(s.ctx, "missing"), domain.ErrUserNotFound)
+}
+
+func (s *inMemoryUserRepositoryTestSuite) TestUserExists() {
+	exists, err := s.repo.UserExists(s.ctx)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), exists)
+
+	_, err = s.repo.CreateUser(s.ctx, &domain.User{ID: "id-1", Username: "existtest", Email: "exist@test.com"})
+	s.Require().NoError(err)
+
+	exists, err = s.repo.UserExists(s.ctx)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), exists)
+}
+
+func (s *inMemoryUserRepositoryTestSuite) TestGetAllUsers() {
+	s.repo.CreateUser(s.ctx, &domain.User{ID: "1", Username: "a", Email: "a@a.com", Role: "user"})
+	s.repo.CreateUser(s.ctx, &domain.User{ID: "2", Username: "b", Email: "b@b.com", Role: "admin"})
+
+	page, err := s.repo.GetAllUsers(s.ctx, domain.UserQuery{})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Users, 2)
+}
+
+func (s *inMemoryUserRepositoryTestSuite) TestUpdateUserRole() {
+	_, err := s.repo.CreateUser(s.ctx, &domain.User{ID: "user-789", Username: "sam", Email: "sam@example.com", Role: domain.RoleUser})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.UpdateUserRole(s.ctx, "user-789", "editor"))
+
+	updated, err := s.repo.GetUserByID(s.ctx, "user-789")
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "editor", updated.Role)
+	assert.Equal(s.T(), 1, updated.TokenVersion)
+
+	assert.ErrorIs(s.T(), s.repo.UpdateUserRole(s.ctx, "missing", "editor"), domain.ErrUserNotFound)
+}
+
+func (s *inMemoryUserRepositoryTestSuite) TestUserManagement() {
+	testUserManagement(s.T(), s.repo)
+}
+
+func (s *inMemoryUserRepositoryTestSuite) TestLastAdmin() {
+	testLastAdmin(s.T(), s.repo, nil)
+}
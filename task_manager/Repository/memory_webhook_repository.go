@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// inMemoryWebhookRepository is the map-backed counterpart of
+// webhookRepository.
+type inMemoryWebhookRepository struct {
+	mu       sync.Mutex
+	webhooks map[string]*domain.Webhook
+}
+
+func NewInMemoryWebhookRepository() domain.WebhookRepository {
+	return &inMemoryWebhookRepository{
+		webhooks: make(map[string]*domain.Webhook),
+	}
+}
+
+func copyWebhook(webhook *domain.Webhook) *domain.Webhook {
+	copied := *webhook
+	copied.Events = slices.Clone(webhook.Events)
+	return &copied
+}
+
+func (wr *inMemoryWebhookRepository) CreateWebhook(c context.Context, webhook *domain.Webhook) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	if _, ok := wr.webhooks[webhook.ID]; ok {
+		return domain.ErrWebhookAlreadyExists
+	}
+	wr.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+func (wr *inMemoryWebhookRepository) GetWebhookByID(c context.Context, webhookId string) (*domain.Webhook, error) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	webhook, ok := wr.webhooks[webhookId]
+	if !ok {
+		return nil, domain.ErrWebhookNotFound
+	}
+	return copyWebhook(webhook), nil
+}
+
+func (wr *inMemoryWebhookRepository) GetWebhooks(c context.Context) ([]*domain.Webhook, error) {
+	return wr.find(func(*domain.Webhook) bool { return true }), nil
+}
+
+func (wr *inMemoryWebhookRepository) GetWebhooksForEvent(c context.Context, eventType string) ([]*domain.Webhook, error) {
+	return wr.find(func(webhook *domain.Webhook) bool {
+		return webhook.Active && webhook.Subscribes(eventType)
+	}), nil
+}
+
+// find returns copies of the webhooks that match, oldest first.
+func (wr *inMemoryWebhookRepository) find(match func(*domain.Webhook) bool) []*domain.Webhook {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	var webhooks []*domain.Webhook
+	for _, webhook := range wr.webhooks {
+		if match(webhook) {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	slices.SortFunc(webhooks, func(a, b *domain.Webhook) int {
+		if order := a.CreatedAt.Compare(b.CreatedAt); order != 0 {
+			return order
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return webhooks
+}
+
+func (wr *inMemoryWebhookRepository) UpdateWebhook(c context.Context, webhook *domain.Webhook) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	stored, ok := wr.webhooks[webhook.ID]
+	if !ok {
+		return domain.ErrWebhookNotFound
+	}
+	updated := copyWebhook(webhook)
+	updated.CreatedBy = stored.CreatedBy
+	updated.CreatedAt = stored.CreatedAt
+	wr.webhooks[webhook.ID] = updated
+	return nil
+}
+
+func (wr *inMemoryWebhookRepository) DeleteWebhook(c context.Context, webhookId string) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	if _, ok := wr.webhooks[webhookId]; !ok {
+		return domain.ErrWebhookNotFound
+	}
+	delete(wr.webhooks, webhookId)
+	return nil
+}
+
+// inMemoryWebhookDeliveryRepository is the map-backed counterpart of
+// webhookDeliveryRepository. Its mutex makes claims atomic, but only within
+// one process.
+type inMemoryWebhookDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries map[string]*domain.WebhookDelivery
+}
+
+func NewInMemoryWebhookDeliveryRepository() domain.WebhookDeliveryRepository {
+	return &inMemoryWebhookDeliveryRepository{
+		deliveries: make(map[string]*domain.WebhookDelivery),
+	}
+}
+
+func copyWebhookDelivery(delivery *domain.WebhookDelivery) *domain.WebhookDelivery {
+	copied := *delivery
+	if delivery.DeliveredAt != nil {
+		deliveredAt := *delivery.DeliveredAt
+		copied.DeliveredAt = &deliveredAt
+	}
+	return &copied
+}
+
+func (dr *inMemoryWebhookDeliveryRepository) CreateWebhookDelivery(c context.Context, delivery *domain.WebhookDelivery) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	if _, ok := dr.deliveries[delivery.ID]; !ok {
+		dr.deliveries[delivery.ID] = copyWebhookDelivery(delivery)
+	}
+	return nil
+}
+
+func (dr *inMemoryWebhookDeliveryRepository) GetWebhookDeliveryByID(c context.Context, deliveryId string) (*domain.WebhookDelivery, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	delivery, ok := dr.deliveries[deliveryId]
+	if !ok {
+		return nil, domain.ErrWebhookDeliveryNotFound
+	}
+	return copyWebhookDelivery(delivery), nil
+}
+
+func (dr *inMemoryWebhookDeliveryRepository) GetWebhookDeliveries(c context.Context, webhookId string, limit int) ([]*domain.WebhookDelivery, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	var deliveries []*domain.WebhookDelivery
+	for _, delivery := range dr.deliveries {
+		if delivery.WebhookID == webhookId {
+			deliveries = append(deliveries, copyWebhookDelivery(delivery))
+		}
+	}
+	sortWebhookDeliveries(deliveries)
+	slices.Reverse(deliveries)
+	return deliveries[:min(limit, len(deliveries))], nil
+}
+
+func (dr *inMemoryWebhookDeliveryRepository) ClaimWebhookDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	var due []*domain.WebhookDelivery
+	for _, delivery := range dr.deliveries {
+		if delivery.Status == domain.WebhookPending && !delivery.LeaseUntil.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sortWebhookDeliveries(due)
+
+	var claimed []*domain.WebhookDelivery
+	for _, delivery := range due[:min(limit, len(due))] {
+		delivery.LeaseOwner = owner
+		delivery.LeaseUntil = leaseUntil
+		delivery.Attempts++
+		claimed = append(claimed, copyWebhookDelivery(delivery))
+	}
+	return claimed, nil
+}
+
+func (dr *inMemoryWebhookDeliveryRepository) FinishWebhookDelivery(c context.Context, delivery *domain.WebhookDelivery, owner string) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	stored, ok := dr.deliveries[delivery.ID]
+	if !ok || stored.LeaseOwner != owner {
+		return domain.ErrWebhookLeaseLost
+	}
+	finished := copyWebhookDelivery(delivery)
+	stored.Status = finished.Status
+	stored.Attempts = finished.Attempts
+	stored.LeaseOwner = ""
+	stored.LeaseUntil = finished.LeaseUntil
+	stored.LastError = finished.LastError
+	stored.ResponseStatus = finished.ResponseStatus
+	stored.DeliveredAt = finished.DeliveredAt
+	return nil
+}
+
+func (dr *inMemoryWebhookDeliveryRepository) DeleteWebhookDeliveries(c context.Context, webhookId string) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	for id, delivery := range dr.deliveries {
+		if delivery.WebhookID == webhookId {
+			delete(dr.deliveries, id)
+		}
+	}
+	return nil
+}
+
+// sortWebhookDeliveries orders deliveries oldest first, the order they are
+// claimed in.
+func sortWebhookDeliveries(deliveries []*domain.WebhookDelivery) {
+	slices.SortFunc(deliveries, func(a, b *domain.WebhookDelivery) int {
+		if order := a.CreatedAt.Compare(b.CreatedAt); order != 0 {
+			return order
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type oidcClientRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewOIDCClientRepository(db *mongo.Database, collection string) domain.OIDCClientRepository {
+	return &oidcClientRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureOIDCClientIndexes makes client ids unique.
+func EnsureOIDCClientIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	return err
+}
+
+func (or *oidcClientRepository) CreateOIDCClient(c context.Context, client *domain.OIDCClient) error {
+	collection := or.database.Collection(or.collection)
+
+	_, err := collection.InsertOne(c, client)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrOIDCClientAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (or *oidcClientRepository) GetOIDCClientByID(c context.Context, clientId string) (*domain.OIDCClient, error) {
+	collection := or.database.Collection(or.collection)
+
+	var client domain.OIDCClient
+	err := collection.FindOne(c, bson.M{"id": clientId}).Decode(&client)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrOIDCClientNotFound
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (or *oidcClientRepository) GetOIDCClients(c context.Context) ([]*domain.OIDCClient, error) {
+	collection := or.database.Collection(or.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}})
+	cursor, err := collection.Find(c, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var clients []*domain.OIDCClient
+	for cursor.Next(c) {
+		var client domain.OIDCClient
+		if err := cursor.Decode(&client); err != nil {
+			return nil, err
+		}
+		clients = append(clients, &client)
+	}
+	return clients, cursor.Err()
+}
+
+func (or *oidcClientRepository) DeleteOIDCClient(c context.Context, clientId string) error {
+	collection := or.database.Collection(or.collection)
+
+	result, err := collection.DeleteOne(c, bson.M{"id": clientId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrOIDCClientNotFound
+	}
+	return nil
+}
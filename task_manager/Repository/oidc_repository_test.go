@@ -0,0 +1,130 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOIDCClients is the contract every OIDCClientRepository must meet.
+func testOIDCClients(t *testing.T, repo domain.OIDCClientRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	wiki := &domain.OIDCClient{ID: "wiki", Name: "Wiki", SecretHash: "hash-1",
+		RedirectURIs: []string{"https://wiki.example.com/callback"}, CreatedAt: now.Add(-time.Hour)}
+	spa := &domain.OIDCClient{ID: "dashboard", Name: "Dashboard",
+		RedirectURIs: []string{"https://dash.example.com/cb", "http://localhost:3000/cb"}, CreatedAt: now}
+	require.NoError(t, repo.CreateOIDCClient(ctx, wiki))
+	require.NoError(t, repo.CreateOIDCClient(ctx, spa))
+	assert.ErrorIs(t, repo.CreateOIDCClient(ctx, &domain.OIDCClient{ID: "wiki", Name: "Other", CreatedAt: now}), domain.ErrOIDCClientAlreadyExists)
+
+	found, err := repo.GetOIDCClientByID(ctx, "dashboard")
+	require.NoError(t, err)
+	assert.Equal(t, "Dashboard", found.Name)
+	assert.True(t, found.Public())
+	assert.Equal(t, []string{"https://dash.example.com/cb", "http://localhost:3000/cb"}, found.RedirectURIs)
+	assert.True(t, found.CreatedAt.Equal(now))
+
+	_, err = repo.GetOIDCClientByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrOIDCClientNotFound)
+
+	// Oldest first.
+	clients, err := repo.GetOIDCClients(ctx)
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+	assert.Equal(t, "wiki", clients[0].ID)
+	assert.Equal(t, "hash-1", clients[0].SecretHash)
+	assert.Equal(t, "dashboard", clients[1].ID)
+
+	require.NoError(t, repo.DeleteOIDCClient(ctx, "wiki"))
+	assert.ErrorIs(t, repo.DeleteOIDCClient(ctx, "wiki"), domain.ErrOIDCClientNotFound)
+	_, err = repo.GetOIDCClientByID(ctx, "wiki")
+	assert.ErrorIs(t, err, domain.ErrOIDCClientNotFound)
+}
+
+// testAuthorizationCodes is the contract every AuthorizationCodeRepository
+// must meet.
+func testAuthorizationCodes(t *testing.T, repo domain.AuthorizationCodeRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	code := &domain.AuthorizationCode{ID: "c1", CodeHash: "hash-1", ClientID: "wiki", UserID: "u1",
+		RedirectURI: "https://wiki.example.com/callback", Scopes: []string{domain.OIDCScopeOpenID, domain.OIDCScopeEmail},
+		Nonce: "n-0S6_WzA2Mj", CodeChallenge: "challenge", AuthTime: now, CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	require.NoError(t, repo.CreateAuthorizationCode(ctx, code))
+	assert.ErrorIs(t, repo.CreateAuthorizationCode(ctx, &domain.AuthorizationCode{ID: "c2", CodeHash: "hash-1",
+		CreatedAt: now, ExpiresAt: now.Add(time.Minute)}), domain.ErrTokenAlreadyExists)
+
+	found, err := repo.GetAuthorizationCodeByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "c1", found.ID)
+	assert.Equal(t, "wiki", found.ClientID)
+	assert.Equal(t, []string{domain.OIDCScopeOpenID, domain.OIDCScopeEmail}, found.Scopes)
+	assert.Equal(t, "n-0S6_WzA2Mj", found.Nonce)
+	assert.True(t, found.AuthTime.Equal(now))
+	assert.Nil(t, found.UsedAt)
+
+	_, err = repo.GetAuthorizationCodeByHash(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+	// A code can only be redeemed once.
+	marked, err := repo.MarkAuthorizationCodeUsed(ctx, "c1", now)
+	require.NoError(t, err)
+	assert.True(t, marked)
+	marked, err = repo.MarkAuthorizationCodeUsed(ctx, "c1", now)
+	require.NoError(t, err)
+	assert.False(t, marked)
+
+	found, err = repo.GetAuthorizationCodeByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, found.UsedAt)
+	assert.True(t, found.UsedAt.Equal(now))
+}
+
+func TestInMemoryOIDCRepositories(t *testing.T) {
+	t.Run("clients", func(t *testing.T) {
+		testOIDCClients(t, repository.NewInMemoryOIDCClientRepository())
+	})
+	t.Run("codes", func(t *testing.T) {
+		testAuthorizationCodes(t, repository.NewInMemoryAuthorizationCodeRepository())
+	})
+}
+
+func TestSQLiteOIDCClientRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "oidc.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testOIDCClients(t, repository.NewSQLiteOIDCClientRepository(db))
+}
+
+func TestMongoOIDCRepositories(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const clientCollection, codeCollection = "test_oidc_clients", "test_oidc_authorization_codes"
+	for _, name := range []string{clientCollection, codeCollection} {
+		require.NoError(t, db.Collection(name).Drop(ctx))
+	}
+	t.Cleanup(func() {
+		_ = db.Collection(clientCollection).Drop(context.Background())
+		_ = db.Collection(codeCollection).Drop(context.Background())
+	})
+	require.NoError(t, repository.EnsureOIDCClientIndexes(ctx, db, clientCollection))
+	require.NoError(t, repository.EnsureAuthorizationCodeIndexes(ctx, db, codeCollection))
+
+	t.Run("clients", func(t *testing.T) {
+		testOIDCClients(t, repository.NewOIDCClientRepository(db, clientCollection))
+	})
+	t.Run("codes", func(t *testing.T) {
+		testAuthorizationCodes(t, repository.NewAuthorizationCodeRepository(db, codeCollection))
+	})
+}
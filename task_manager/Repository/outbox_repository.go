@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outboxCounterID names the counter document that numbers outbox events.
+const outboxCounterID = "outbox"
+
+type outboxRepository struct {
+	database          *mongo.Database
+	collection        string
+	counterCollection string
+}
+
+// NewOutboxRepository numbers events from a counter document incremented
+// in the appending transaction. Concurrent appends write the same counter,
+// so their transactions commit one after the other, and events are
+// numbered in the order their changes committed.
+func NewOutboxRepository(db *mongo.Database, collection string, counterCollection string) domain.OutboxRepository {
+	return &outboxRepository{
+		database:          db,
+		collection:        collection,
+		counterCollection: counterCollection,
+	}
+}
+
+// EnsureOutboxIndexes makes event IDs and sequences unique; the sequence
+// index also serves the relay's reads.
+func EnsureOutboxIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "sequence", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	return err
+}
+
+func (or *outboxRepository) AppendEvent(c context.Context, event *domain.Event) error {
+	counters := or.database.Collection(or.counterCollection)
+
+	var counter struct {
+		Sequence int64 `bson:"sequence"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := counters.FindOneAndUpdate(c, bson.M{"id": outboxCounterID}, bson.M{"$inc": bson.M{"sequence": int64(1)}}, opts).Decode(&counter)
+	if err != nil {
+		return err
+	}
+
+	stored := *event
+	stored.Sequence = counter.Sequence
+	if _, err := or.database.Collection(or.collection).InsertOne(c, &stored); err != nil {
+		return err
+	}
+	event.Sequence = stored.Sequence
+	return nil
+}
+
+func (or *outboxRepository) GetEventsAfter(c context.Context, sequence int64, limit int) ([]*domain.Event, error) {
+	collection := or.database.Collection(or.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := collection.Find(c, bson.M{"sequence": bson.M{"$gt": sequence}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var events []*domain.Event
+	for cursor.Next(c) {
+		var event domain.Event
+		if err := cursor.Decode(&event); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	return events, cursor.Err()
+}
+
+func (or *outboxRepository) DeleteEvents(c context.Context, through int64, before time.Time) error {
+	collection := or.database.Collection(or.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{
+		"sequence":   bson.M{"$lte": through},
+		"occurredat": bson.M{"$lt": before},
+	})
+	return err
+}
+
+type outboxCheckpointRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewOutboxCheckpointRepository(db *mongo.Database, collection string) domain.OutboxCheckpointRepository {
+	return &outboxCheckpointRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureOutboxCheckpointIndexes makes consumer names unique, which is what
+// keeps a second relay from creating a checkpoint another already holds.
+func EnsureOutboxCheckpointIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys: bson.D{{Key: "consumer", Value: 1}}, Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// ClaimCheckpoint upserts the checkpoint where it is free or already
+// owner's. When another owner holds it the filter matches nothing, and the
+// insert the upsert falls back to fails on the unique consumer index.
+func (cr *outboxCheckpointRepository) ClaimCheckpoint(c context.Context, consumer string, owner string, now time.Time, leaseUntil time.Time) (*domain.OutboxCheckpoint, error) {
+	collection := cr.database.Collection(cr.collection)
+
+	filter := bson.M{
+		"consumer": consumer,
+		"$or": bson.A{
+			bson.M{"leaseowner": owner},
+			bson.M{"leaseuntil": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set":         bson.M{"leaseowner": owner, "leaseuntil": leaseUntil},
+		"$setOnInsert": bson.M{"sequence": int64(0), "updatedat": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var checkpoint domain.OutboxCheckpoint
+	err := collection.FindOneAndUpdate(c, filter, update, opts).Decode(&checkpoint)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrOutboxLeaseHeld
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (cr *outboxCheckpointRepository) AdvanceCheckpoint(c context.Context, consumer string, owner string, sequence int64) error {
+	collection := cr.database.Collection(cr.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"consumer": consumer, "leaseowner": owner}, bson.M{"$set": bson.M{
+		"sequence":  sequence,
+		"updatedat": time.Now(),
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrOutboxLeaseLost
+	}
+	return nil
+}
+
+func (cr *outboxCheckpointRepository) GetCheckpoints(c context.Context) ([]*domain.OutboxCheckpoint, error) {
+	collection := cr.database.Collection(cr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "consumer", Value: 1}})
+	cursor, err := collection.Find(c, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var checkpoints []*domain.OutboxCheckpoint
+	for cursor.Next(c) {
+		var checkpoint domain.OutboxCheckpoint
+		if err := cursor.Decode(&checkpoint); err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, &checkpoint)
+	}
+	return checkpoints, cursor.Err()
+}
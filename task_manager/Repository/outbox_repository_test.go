@@ -0,0 +1,181 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOutbox is the contract every OutboxRepository must meet.
+func testOutbox(t *testing.T, repo domain.OutboxRepository) {
+	ctx := context.Background()
+	occurred := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	events := []*domain.Event{
+		{ID: "e1", Type: domain.EventTaskCreated, Task: &domain.Task{ID: "t1", Title: "Write report"}},
+		{ID: "e2", Type: domain.EventUserPromoted, User: &domain.User{ID: "u1", Username: "alice", Role: "admin"}},
+		{ID: "e3", Type: domain.EventTaskDeleted, Task: &domain.Task{ID: "t1"}},
+	}
+	for i, event := range events {
+		event.OccurredAt = occurred.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, repo.AppendEvent(ctx, event))
+	}
+	assert.Less(t, events[0].Sequence, events[1].Sequence)
+	assert.Less(t, events[1].Sequence, events[2].Sequence)
+
+	found, err := repo.GetEventsAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+	assert.Equal(t, "e1", found[0].ID)
+	assert.Equal(t, events[0].Sequence, found[0].Sequence)
+	assert.Equal(t, domain.EventTaskCreated, found[0].Type)
+	assert.True(t, found[0].OccurredAt.Equal(occurred))
+	require.NotNil(t, found[0].Task)
+	assert.Equal(t, "Write report", found[0].Task.Title)
+	assert.Nil(t, found[0].User)
+	require.NotNil(t, found[1].User)
+	assert.Equal(t, "alice", found[1].User.Username)
+	assert.Nil(t, found[1].Task)
+
+	found, err = repo.GetEventsAfter(ctx, events[0].Sequence, 1)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "e2", found[0].ID)
+	found, err = repo.GetEventsAfter(ctx, events[2].Sequence, 10)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// Only events both handled and old enough go.
+	require.NoError(t, repo.DeleteEvents(ctx, events[1].Sequence, occurred.Add(30*time.Minute)))
+	found, err = repo.GetEventsAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "e2", found[0].ID)
+	assert.Equal(t, "e3", found[1].ID)
+}
+
+// testOutboxCheckpoints is the contract every OutboxCheckpointRepository
+// must meet.
+func testOutboxCheckpoints(t *testing.T, repo domain.OutboxCheckpointRepository) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	checkpoint, err := repo.ClaimCheckpoint(ctx, "webhooks", "relay-a", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "webhooks", checkpoint.Consumer)
+	assert.Zero(t, checkpoint.Sequence)
+	assert.Equal(t, "relay-a", checkpoint.LeaseOwner)
+	assert.True(t, checkpoint.LeaseUntil.Equal(now.Add(time.Minute)))
+
+	// The lease keeps other relays out until it runs out; its owner renews it.
+	_, err = repo.ClaimCheckpoint(ctx, "webhooks", "relay-b", now.Add(30*time.Second), now.Add(90*time.Second))
+	assert.ErrorIs(t, err, domain.ErrOutboxLeaseHeld)
+	require.NoError(t, repo.AdvanceCheckpoint(ctx, "webhooks", "relay-a", 7))
+	checkpoint, err = repo.ClaimCheckpoint(ctx, "webhooks", "relay-a", now.Add(30*time.Second), now.Add(90*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), checkpoint.Sequence)
+
+	checkpoint, err = repo.ClaimCheckpoint(ctx, "webhooks", "relay-b", now.Add(90*time.Second), now.Add(150*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), checkpoint.Sequence)
+	assert.Equal(t, "relay-b", checkpoint.LeaseOwner)
+	assert.ErrorIs(t, repo.AdvanceCheckpoint(ctx, "webhooks", "relay-a", 8), domain.ErrOutboxLeaseLost)
+	assert.ErrorIs(t, repo.AdvanceCheckpoint(ctx, "missing", "relay-a", 8), domain.ErrOutboxLeaseLost)
+
+	_, err = repo.ClaimCheckpoint(ctx, "audit", "relay-a", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	checkpoints, err := repo.GetCheckpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+	assert.Equal(t, "audit", checkpoints[0].Consumer)
+	assert.Equal(t, "webhooks", checkpoints[1].Consumer)
+	assert.Equal(t, int64(7), checkpoints[1].Sequence)
+}
+
+// testOutboxTransactions checks that a transaction stores a change and its
+// event together or not at all.
+func testOutboxTransactions(t *testing.T, transactor domain.Transactor, tasks domain.TaskRepository, outbox domain.OutboxRepository) {
+	ctx := context.Background()
+	store := func(id string, fail error) error {
+		return transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := tasks.CreateTask(ctx, &domain.Task{ID: id, UserID: "u1", Title: id}); err != nil {
+				return err
+			}
+			// A nested call joins the transaction under way.
+			err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				return outbox.AppendEvent(ctx, &domain.Event{ID: "event-" + id, Type: domain.EventTaskCreated, OccurredAt: time.Now()})
+			})
+			if err != nil {
+				return err
+			}
+			return fail
+		})
+	}
+
+	failure := errors.New("publish failed")
+	assert.ErrorIs(t, store("rolled-back", failure), failure)
+	require.NoError(t, store("committed", nil))
+
+	_, err := tasks.GetTaskByID(ctx, "rolled-back")
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	_, err = tasks.GetTaskByID(ctx, "committed")
+	assert.NoError(t, err)
+	events, err := outbox.GetEventsAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "event-committed", events[0].ID)
+}
+
+func TestInMemoryOutbox(t *testing.T) {
+	testOutbox(t, repository.NewInMemoryOutboxRepository())
+	testOutboxCheckpoints(t, repository.NewInMemoryOutboxCheckpointRepository())
+}
+
+func TestSQLiteOutbox(t *testing.T) {
+	open := func(name string) *sql.DB {
+		db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), name))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+
+	testOutbox(t, repository.NewSQLiteOutboxRepository(open("outbox.db")))
+	testOutboxCheckpoints(t, repository.NewSQLiteOutboxCheckpointRepository(open("checkpoints.db")))
+	db := open("transactions.db")
+	testOutboxTransactions(t, repository.NewSQLiteTransactor(db), repository.NewSQLiteTaskRepository(db), repository.NewSQLiteOutboxRepository(db))
+}
+
+func TestMongoOutbox(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const outbox, counters, checkpoints, tasks = "test_outbox", "test_outbox_counters", "test_outbox_checkpoints", "test_outbox_tasks"
+	for _, collection := range []string{outbox, counters, checkpoints, tasks} {
+		require.NoError(t, db.Collection(collection).Drop(ctx))
+		t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	}
+	require.NoError(t, repository.EnsureOutboxIndexes(ctx, db, outbox))
+	require.NoError(t, repository.EnsureOutboxCheckpointIndexes(ctx, db, checkpoints))
+	require.NoError(t, repository.EnsureTaskIndexes(ctx, db, tasks))
+
+	testOutbox(t, repository.NewOutboxRepository(db, outbox, counters))
+	testOutboxCheckpoints(t, repository.NewOutboxCheckpointRepository(db, checkpoints))
+
+	supportsTransactions, err := repository.MongoSupportsTransactions(ctx, db.Client())
+	require.NoError(t, err)
+	if !supportsTransactions {
+		t.Skip("MongoDB at DATABASE_URL is a standalone server without transactions")
+	}
+	require.NoError(t, db.Collection(outbox).Drop(ctx))
+	require.NoError(t, repository.EnsureOutboxIndexes(ctx, db, outbox))
+	testOutboxTransactions(t, repository.NewMongoTransactor(db.Client()), repository.NewTaskRepository(db, tasks), repository.NewOutboxRepository(db, outbox, counters))
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type passwordResetTokenRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewPasswordResetTokenRepository(db *mongo.Database, collection string) domain.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsurePasswordResetIndexes makes token hashes unique and lets MongoDB drop
+// tokens once they have expired.
+func EnsurePasswordResetIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tokenhash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "userid", Value: 1}}},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (pr *passwordResetTokenRepository) CreatePasswordResetToken(c context.Context, token *domain.PasswordResetToken) error {
+	collection := pr.database.Collection(pr.collection)
+
+	_, err := collection.InsertOne(c, token)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTokenAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (pr *passwordResetTokenRepository) GetPasswordResetTokenByHash(c context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	var token domain.PasswordResetToken
+	err := collection.FindOne(c, bson.M{"tokenhash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (pr *passwordResetTokenRepository) MarkPasswordResetTokenUsed(c context.Context, tokenId string, usedAt time.Time) (bool, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	filter := bson.M{"id": tokenId, "usedat": nil}
+	result, err := collection.UpdateOne(c, filter, bson.M{"$set": bson.M{"usedat": usedAt}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (pr *passwordResetTokenRepository) DeletePasswordResetTokensByUser(c context.Context, userId string) error {
+	collection := pr.database.Collection(pr.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{"userid": userId})
+	return err
+}
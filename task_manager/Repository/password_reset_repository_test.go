@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPasswordResetTokens is the contract every PasswordResetTokenRepository
+// must meet.
+func testPasswordResetTokens(t *testing.T, repo domain.PasswordResetTokenRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	first := &domain.PasswordResetToken{ID: "pr1", UserID: "u1", TokenHash: "hash-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	second := &domain.PasswordResetToken{ID: "pr2", UserID: "u1", TokenHash: "hash-2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	other := &domain.PasswordResetToken{ID: "pr3", UserID: "u2", TokenHash: "hash-3", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	for _, token := range []*domain.PasswordResetToken{first, second, other} {
+		require.NoError(t, repo.CreatePasswordResetToken(ctx, token))
+	}
+	assert.ErrorIs(t, repo.CreatePasswordResetToken(ctx, &domain.PasswordResetToken{ID: "pr4", TokenHash: "hash-1", ExpiresAt: now.Add(time.Hour)}),
+		domain.ErrTokenAlreadyExists)
+
+	found, err := repo.GetPasswordResetTokenByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "pr1", found.ID)
+	assert.Equal(t, "u1", found.UserID)
+	assert.Nil(t, found.UsedAt)
+
+	_, err = repo.GetPasswordResetTokenByHash(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+	marked, err := repo.MarkPasswordResetTokenUsed(ctx, "pr1", now)
+	require.NoError(t, err)
+	assert.True(t, marked)
+	marked, err = repo.MarkPasswordResetTokenUsed(ctx, "pr1", now.Add(time.Second))
+	require.NoError(t, err)
+	assert.False(t, marked, "a reset token works once")
+
+	require.NoError(t, repo.DeletePasswordResetTokensByUser(ctx, "u1"))
+	for hash, deleted := range map[string]bool{"hash-1": true, "hash-2": true, "hash-3": false} {
+		_, err := repo.GetPasswordResetTokenByHash(ctx, hash)
+		if deleted {
+			assert.ErrorIs(t, err, domain.ErrTokenNotFound, hash)
+		} else {
+			assert.NoError(t, err, hash)
+		}
+	}
+}
+
+func TestInMemoryPasswordResetTokenRepository(t *testing.T) {
+	testPasswordResetTokens(t, repository.NewInMemoryPasswordResetTokenRepository())
+}
+
+func TestMongoPasswordResetTokenRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_password_resets"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsurePasswordResetIndexes(ctx, db, collection))
+
+	testPasswordResetTokens(t, repository.NewPasswordResetTokenRepository(db, collection))
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type personalAccessTokenRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewPersonalAccessTokenRepository(db *mongo.Database, collection string) domain.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsurePersonalAccessTokenIndexes makes token hashes unique and backs the
+// per-user listing.
+func EnsurePersonalAccessTokenIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tokenhash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "createdat", Value: -1}}},
+	})
+	return err
+}
+
+func (pr *personalAccessTokenRepository) CreatePersonalAccessToken(c context.Context, token *domain.PersonalAccessToken) error {
+	collection := pr.database.Collection(pr.collection)
+
+	_, err := collection.InsertOne(c, token)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTokenAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (pr *personalAccessTokenRepository) GetPersonalAccessTokenByHash(c context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	var token domain.PersonalAccessToken
+	err := collection.FindOne(c, bson.M{"tokenhash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (pr *personalAccessTokenRepository) GetPersonalAccessTokensByUser(c context.Context, userId string) ([]*domain.PersonalAccessToken, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdat", Value: -1}, {Key: "id", Value: 1}})
+	cursor, err := collection.Find(c, bson.M{"userid": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var tokens []*domain.PersonalAccessToken
+	for cursor.Next(c) {
+		var token domain.PersonalAccessToken
+		if err := cursor.Decode(&token); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+	return tokens, cursor.Err()
+}
+
+func (pr *personalAccessTokenRepository) DeletePersonalAccessToken(c context.Context, userId string, tokenId string) error {
+	collection := pr.database.Collection(pr.collection)
+
+	result, err := collection.DeleteOne(c, bson.M{"id": tokenId, "userid": userId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+func (pr *personalAccessTokenRepository) DeletePersonalAccessTokensByUser(c context.Context, userId string) error {
+	collection := pr.database.Collection(pr.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{"userid": userId})
+	return err
+}
+
+func (pr *personalAccessTokenRepository) TouchPersonalAccessToken(c context.Context, tokenId string, usedAt time.Time) error {
+	collection := pr.database.Collection(pr.collection)
+
+	_, err := collection.UpdateOne(c, bson.M{"id": tokenId}, bson.M{"$set": bson.M{"lastusedat": usedAt}})
+	return err
+}
@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPersonalAccessTokens is the contract every
+// PersonalAccessTokenRepository must meet.
+func testPersonalAccessTokens(t *testing.T, repo domain.PersonalAccessTokenRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	older := &domain.PersonalAccessToken{ID: "pat1", UserID: "u1", Name: "ci", TokenHash: "hash-1",
+		Scopes: []string{domain.PermTaskRead}, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+	newer := &domain.PersonalAccessToken{ID: "pat2", UserID: "u1", Name: "deploy", TokenHash: "hash-2",
+		Scopes: []string{domain.PermTaskRead, domain.PermTaskCreate}, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	other := &domain.PersonalAccessToken{ID: "pat3", UserID: "u2", Name: "ci", TokenHash: "hash-3",
+		Scopes: []string{}, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	for _, token := range []*domain.PersonalAccessToken{older, newer, other} {
+		require.NoError(t, repo.CreatePersonalAccessToken(ctx, token))
+	}
+	assert.ErrorIs(t, repo.CreatePersonalAccessToken(ctx, &domain.PersonalAccessToken{ID: "pat4", UserID: "u1", TokenHash: "hash-1",
+		CreatedAt: now, ExpiresAt: now.Add(time.Hour)}), domain.ErrTokenAlreadyExists)
+
+	found, err := repo.GetPersonalAccessTokenByHash(ctx, "hash-2")
+	require.NoError(t, err)
+	assert.Equal(t, "pat2", found.ID)
+	assert.Equal(t, "deploy", found.Name)
+	assert.Equal(t, []string{domain.PermTaskRead, domain.PermTaskCreate}, found.Scopes)
+	assert.True(t, found.ExpiresAt.Equal(now.Add(time.Hour)))
+	assert.Nil(t, found.LastUsedAt)
+
+	_, err = repo.GetPersonalAccessTokenByHash(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+	require.NoError(t, repo.TouchPersonalAccessToken(ctx, "pat2", now))
+	found, err = repo.GetPersonalAccessTokenByHash(ctx, "hash-2")
+	require.NoError(t, err)
+	require.NotNil(t, found.LastUsedAt)
+	assert.True(t, found.LastUsedAt.Equal(now))
+
+	// Newest first, and only the user's own.
+	tokens, err := repo.GetPersonalAccessTokensByUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "pat2", tokens[0].ID)
+	assert.Equal(t, "pat1", tokens[1].ID)
+
+	// A user cannot delete someone else's token.
+	assert.ErrorIs(t, repo.DeletePersonalAccessToken(ctx, "u1", "pat3"), domain.ErrTokenNotFound)
+	require.NoError(t, repo.DeletePersonalAccessToken(ctx, "u1", "pat1"))
+	assert.ErrorIs(t, repo.DeletePersonalAccessToken(ctx, "u1", "pat1"), domain.ErrTokenNotFound)
+	_, err = repo.GetPersonalAccessTokenByHash(ctx, "hash-1")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+	tokens, err = repo.GetPersonalAccessTokensByUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "pat2", tokens[0].ID)
+
+	// Revoking all of a user's tokens leaves everyone else's alone.
+	require.NoError(t, repo.DeletePersonalAccessTokensByUser(ctx, "u1"))
+	tokens, err = repo.GetPersonalAccessTokensByUser(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+	_, err = repo.GetPersonalAccessTokenByHash(ctx, "hash-3")
+	assert.NoError(t, err)
+}
+
+func TestInMemoryPersonalAccessTokenRepository(t *testing.T) {
+	testPersonalAccessTokens(t, repository.NewInMemoryPersonalAccessTokenRepository())
+}
+
+func TestSQLitePersonalAccessTokenRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "tokens.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testPersonalAccessTokens(t, repository.NewSQLitePersonalAccessTokenRepository(db))
+}
+
+func TestMongoPersonalAccessTokenRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_personal_access_tokens"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsurePersonalAccessTokenIndexes(ctx, db, collection))
+
+	testPersonalAccessTokens(t, repository.NewPersonalAccessTokenRepository(db, collection))
+}
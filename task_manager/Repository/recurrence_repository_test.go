@@ -0,0 +1,105 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRecurrence is the contract every TaskRepository must meet for
+// recurring tasks.
+func testRecurrence(t *testing.T, repo domain.TaskRepository) {
+	ctx := context.Background()
+	day := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	occurrence := func(n int) *domain.Recurrence {
+		return &domain.Recurrence{Rule: "FREQ=WEEKLY", TimeZone: "Europe/Berlin", SeriesID: "s1",
+			Start: day, Occurrence: day.AddDate(0, 0, 7*n)}
+	}
+
+	for _, task := range []*domain.Task{
+		{ID: "w3", UserID: "u1", Title: "report", Status: "todo", DueDate: day.AddDate(0, 0, 14), Recurrence: occurrence(2)},
+		{ID: "w1", UserID: "u1", Title: "report", Status: "done", DueDate: day, Recurrence: occurrence(0)},
+		// Moving an occurrence's due date does not move its slot.
+		{ID: "w2", UserID: "u1", Title: "report", Status: "done", DueDate: day.AddDate(0, 0, 30), Recurrence: occurrence(1)},
+		{ID: "once", UserID: "u1", Title: "one-off", Status: "todo", DueDate: day},
+	} {
+		require.NoError(t, repo.CreateTask(ctx, task))
+	}
+
+	found, err := repo.GetTaskByID(ctx, "w2")
+	require.NoError(t, err)
+	require.NotNil(t, found.Recurrence)
+	assert.Equal(t, "FREQ=WEEKLY", found.Recurrence.Rule)
+	assert.Equal(t, "Europe/Berlin", found.Recurrence.TimeZone)
+	assert.True(t, found.Recurrence.Start.Equal(day))
+	assert.True(t, found.Recurrence.Occurrence.Equal(day.AddDate(0, 0, 7)))
+	found, err = repo.GetTaskByID(ctx, "once")
+	require.NoError(t, err)
+	assert.Nil(t, found.Recurrence)
+
+	ids := func(tasks []*domain.Task, err error) []string {
+		require.NoError(t, err)
+		var ids []string
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"w1", "w2", "w3"}, ids(repo.GetTasksBySeries(ctx, "s1")))
+	assert.Empty(t, ids(repo.GetTasksBySeries(ctx, "s2")))
+
+	// UpdateTask leaves the recurrence alone.
+	_, err = repo.UpdateTask(ctx, "w3", &domain.Task{ID: "w3", UserID: "u1", Title: "renamed", Status: "todo", DueDate: day})
+	require.NoError(t, err)
+	found, err = repo.GetTaskByID(ctx, "w3")
+	require.NoError(t, err)
+	require.NotNil(t, found.Recurrence)
+	assert.Equal(t, "s1", found.Recurrence.SeriesID)
+
+	moved := occurrence(2)
+	moved.SeriesID = "s2"
+	moved.Rule = "FREQ=WEEKLY;BYDAY=MO,TH"
+	require.NoError(t, repo.SetTaskRecurrence(ctx, "w3", moved))
+	assert.Equal(t, []string{"w1", "w2"}, ids(repo.GetTasksBySeries(ctx, "s1")))
+	assert.Equal(t, []string{"w3"}, ids(repo.GetTasksBySeries(ctx, "s2")))
+	found, err = repo.GetTaskByID(ctx, "w3")
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,TH", found.Recurrence.Rule)
+
+	require.NoError(t, repo.SetTaskRecurrence(ctx, "w3", nil))
+	found, err = repo.GetTaskByID(ctx, "w3")
+	require.NoError(t, err)
+	assert.Nil(t, found.Recurrence)
+	assert.ErrorIs(t, repo.SetTaskRecurrence(ctx, "missing", moved), domain.ErrTaskNotFound)
+}
+
+func TestInMemoryRecurrence(t *testing.T) {
+	testRecurrence(t, repository.NewInMemoryTaskRepository())
+}
+
+func TestSQLiteRecurrence(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "recurrence.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testRecurrence(t, repository.NewSQLiteTaskRepository(db))
+}
+
+func TestMongoRecurrence(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_recurrence"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsureTaskIndexes(ctx, db, collection))
+
+	testRecurrence(t, repository.NewTaskRepository(db, collection))
+}
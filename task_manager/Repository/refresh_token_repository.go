@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type refreshTokenRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewRefreshTokenRepository(db *mongo.Database, collection string) domain.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureRefreshTokenIndexes makes token hashes unique, indexes families for
+// revocation and lets MongoDB expire tokens once they can no longer be used.
+func EnsureRefreshTokenIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tokenhash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "familyid", Value: 1}}},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (rr *refreshTokenRepository) CreateRefreshToken(c context.Context, token *domain.RefreshToken) error {
+	collection := rr.database.Collection(rr.collection)
+
+	_, err := collection.InsertOne(c, token)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTokenAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (rr *refreshTokenRepository) GetRefreshTokenByHash(c context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	var token domain.RefreshToken
+	err := collection.FindOne(c, bson.M{"tokenhash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (rr *refreshTokenRepository) MarkRefreshTokenUsed(c context.Context, tokenId string, usedAt time.Time) (bool, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	// Matching on usedat makes the update a compare-and-set: of two
+	// concurrent refreshes with the same token only one can win.
+	filter := bson.M{"id": tokenId, "usedat": nil}
+	result, err := collection.UpdateOne(c, filter, bson.M{"$set": bson.M{"usedat": usedAt}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (rr *refreshTokenRepository) RevokeRefreshTokenFamily(c context.Context, familyId string, revokedAt time.Time) error {
+	collection := rr.database.Collection(rr.collection)
+
+	filter := bson.M{"familyid": familyId, "revokedat": nil}
+	_, err := collection.UpdateMany(c, filter, bson.M{"$set": bson.M{"revokedat": revokedAt}})
+	return err
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type reminderRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewReminderRepository(db *mongo.Database, collection string) domain.ReminderRepository {
+	return &reminderRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureReminderIndexes makes reminder IDs unique, and a lead unique per
+// user and channel.
+func EnsureReminderIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{
+			Keys:    bson.D{{Key: "userid", Value: 1}, {Key: "lead", Value: 1}, {Key: "channel", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "lead", Value: 1}}},
+	})
+	return err
+}
+
+func (rr *reminderRepository) CreateReminder(c context.Context, reminder *domain.Reminder) error {
+	collection := rr.database.Collection(rr.collection)
+
+	_, err := collection.InsertOne(c, reminder)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrReminderAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (rr *reminderRepository) GetReminderByID(c context.Context, reminderId string) (*domain.Reminder, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	var reminder domain.Reminder
+	err := collection.FindOne(c, bson.M{"id": reminderId}).Decode(&reminder)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrReminderNotFound
+		}
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+func (rr *reminderRepository) GetRemindersByUser(c context.Context, userId string) ([]*domain.Reminder, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "lead", Value: -1}, {Key: "channel", Value: 1}})
+	cursor, err := collection.Find(c, bson.M{"userid": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var reminders []*domain.Reminder
+	for cursor.Next(c) {
+		var reminder domain.Reminder
+		if err := cursor.Decode(&reminder); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &reminder)
+	}
+	return reminders, cursor.Err()
+}
+
+func (rr *reminderRepository) GetReminderLeads(c context.Context) ([]time.Duration, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	values, err := collection.Distinct(c, "lead", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	leads := make([]time.Duration, 0, len(values))
+	for _, value := range values {
+		if lead, ok := value.(int64); ok {
+			leads = append(leads, time.Duration(lead))
+		}
+	}
+	return leads, nil
+}
+
+func (rr *reminderRepository) DeleteReminder(c context.Context, reminderId string) error {
+	collection := rr.database.Collection(rr.collection)
+
+	result, err := collection.DeleteOne(c, bson.M{"id": reminderId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrReminderNotFound
+	}
+	return nil
+}
+
+type reminderDeliveryRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewReminderDeliveryRepository(db *mongo.Database, collection string) domain.ReminderDeliveryRepository {
+	return &reminderDeliveryRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureReminderDeliveryIndexes makes delivery IDs unique and indexes the
+// pending deliveries schedulers claim from.
+func EnsureReminderDeliveryIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "sendat", Value: 1}, {Key: "id", Value: 1}}},
+	})
+	return err
+}
+
+func (dr *reminderDeliveryRepository) EnqueueReminderDelivery(c context.Context, delivery *domain.ReminderDelivery) error {
+	collection := dr.database.Collection(dr.collection)
+
+	_, err := collection.UpdateOne(c, bson.M{"id": delivery.ID}, bson.M{"$setOnInsert": delivery},
+		options.Update().SetUpsert(true))
+	// Two schedulers upserting the same delivery at once can both miss it
+	// and race to insert; the loser's duplicate key means it is stored.
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+// ClaimReminderDeliveries claims one delivery at a time; each
+// FindOneAndUpdate is atomic, so a delivery is leased to one claimant only.
+func (dr *reminderDeliveryRepository) ClaimReminderDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.ReminderDelivery, error) {
+	collection := dr.database.Collection(dr.collection)
+
+	filter := bson.M{
+		"status":     domain.ReminderPending,
+		"sendat":     bson.M{"$lte": now},
+		"leaseuntil": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"leaseowner": owner, "leaseuntil": leaseUntil},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "sendat", Value: 1}, {Key: "id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var claimed []*domain.ReminderDelivery
+	for len(claimed) < limit {
+		var delivery domain.ReminderDelivery
+		err := collection.FindOneAndUpdate(c, filter, update, opts).Decode(&delivery)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, &delivery)
+	}
+	return claimed, nil
+}
+
+func (dr *reminderDeliveryRepository) FinishReminderDelivery(c context.Context, delivery *domain.ReminderDelivery, owner string) error {
+	collection := dr.database.Collection(dr.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"id": delivery.ID, "leaseowner": owner}, bson.M{"$set": bson.M{
+		"status":     delivery.Status,
+		"attempts":   delivery.Attempts,
+		"leaseowner": "",
+		"leaseuntil": delivery.LeaseUntil,
+		"lasterror":  delivery.LastError,
+		"sentat":     delivery.SentAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrReminderLeaseLost
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testReminders is the contract every ReminderRepository must meet.
+func testReminders(t *testing.T, repo domain.ReminderRepository) {
+	ctx := context.Background()
+	created := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	for _, reminder := range []*domain.Reminder{
+		{ID: "r1", UserID: "u1", Lead: 0, Channel: "email", CreatedAt: created},
+		{ID: "r2", UserID: "u1", Lead: 24 * time.Hour, Channel: "email", CreatedAt: created},
+		{ID: "r3", UserID: "u1", Lead: 24 * time.Hour, Channel: "log", CreatedAt: created},
+		{ID: "r4", UserID: "u2", Lead: 90 * time.Minute, Channel: "email", CreatedAt: created},
+	} {
+		require.NoError(t, repo.CreateReminder(ctx, reminder))
+	}
+	assert.ErrorIs(t, repo.CreateReminder(ctx, &domain.Reminder{ID: "r5", UserID: "u1", Lead: 24 * time.Hour, Channel: "email", CreatedAt: created}),
+		domain.ErrReminderAlreadyExists)
+
+	found, err := repo.GetReminderByID(ctx, "r4")
+	require.NoError(t, err)
+	assert.Equal(t, "u2", found.UserID)
+	assert.Equal(t, 90*time.Minute, found.Lead)
+	assert.Equal(t, "email", found.Channel)
+	assert.True(t, found.CreatedAt.Equal(created))
+	_, err = repo.GetReminderByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrReminderNotFound)
+
+	reminders, err := repo.GetRemindersByUser(ctx, "u1")
+	require.NoError(t, err)
+	var ids []string
+	for _, reminder := range reminders {
+		ids = append(ids, reminder.ID)
+	}
+	assert.Equal(t, []string{"r2", "r3", "r1"}, ids)
+
+	leads, err := repo.GetReminderLeads(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []time.Duration{0, 24 * time.Hour, 90 * time.Minute}, leads)
+
+	require.NoError(t, repo.DeleteReminder(ctx, "r4"))
+	assert.ErrorIs(t, repo.DeleteReminder(ctx, "r4"), domain.ErrReminderNotFound)
+	leads, err = repo.GetReminderLeads(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []time.Duration{0, 24 * time.Hour}, leads)
+}
+
+// testReminderDeliveries is the contract every ReminderDeliveryRepository
+// must meet.
+func testReminderDeliveries(t *testing.T, repo domain.ReminderDeliveryRepository) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	delivery := func(id string, sendAt time.Time) *domain.ReminderDelivery {
+		return &domain.ReminderDelivery{ID: id, ReminderID: "r1", TaskID: "t-" + id, UserID: "u1", Channel: "email",
+			SendAt: sendAt, Status: domain.ReminderPending, CreatedAt: now}
+	}
+	require.NoError(t, repo.EnqueueReminderDelivery(ctx, delivery("d2", now.Add(-time.Minute))))
+	require.NoError(t, repo.EnqueueReminderDelivery(ctx, delivery("d1", now.Add(-time.Hour))))
+	require.NoError(t, repo.EnqueueReminderDelivery(ctx, delivery("d3", now.Add(time.Minute))))
+	// Enqueueing again changes nothing.
+	again := delivery("d1", now.Add(-time.Hour))
+	again.Channel = "log"
+	require.NoError(t, repo.EnqueueReminderDelivery(ctx, again))
+
+	claim := func(owner string, at time.Time, limit int) []string {
+		claimed, err := repo.ClaimReminderDeliveries(ctx, owner, at, at.Add(time.Minute), limit)
+		require.NoError(t, err)
+		var ids []string
+		for _, d := range claimed {
+			assert.Equal(t, owner, d.LeaseOwner)
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+
+	first, err := repo.ClaimReminderDeliveries(ctx, "a", now, now.Add(time.Minute), 1)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, "d1", first[0].ID)
+	assert.Equal(t, "email", first[0].Channel)
+	assert.Equal(t, 1, first[0].Attempts)
+	assert.True(t, first[0].SendAt.Equal(now.Add(-time.Hour)))
+	assert.True(t, first[0].LeaseUntil.Equal(now.Add(time.Minute)))
+
+	// A leased delivery cannot be claimed by anyone else, and one not yet
+	// due is not claimed at all.
+	assert.Equal(t, []string{"d2"}, claim("b", now, 10))
+	assert.Empty(t, claim("c", now, 10))
+
+	// Only the holder can finish a delivery.
+	sentAt := now.Add(time.Second)
+	sent := *first[0]
+	sent.Status = domain.ReminderSent
+	sent.SentAt = &sentAt
+	assert.ErrorIs(t, repo.FinishReminderDelivery(ctx, &sent, "b"), domain.ErrReminderLeaseLost)
+	require.NoError(t, repo.FinishReminderDelivery(ctx, &sent, "a"))
+	assert.ErrorIs(t, repo.FinishReminderDelivery(ctx, &sent, "a"), domain.ErrReminderLeaseLost)
+
+	// Once b's lease runs out d2 can be taken over; d1 was sent and d3 is
+	// now due.
+	later := now.Add(2 * time.Minute)
+	assert.Equal(t, []string{"d2", "d3"}, claim("c", later, 10))
+
+	sent = *delivery("d2", now.Add(-time.Minute))
+	sent.Status = domain.ReminderSent
+	sent.SentAt = &sentAt
+	require.NoError(t, repo.FinishReminderDelivery(ctx, &sent, "c"))
+
+	// A failed attempt is retried from LeaseUntil.
+	retried := delivery("d3", now.Add(time.Minute))
+	retried.Attempts = 1
+	retried.LeaseUntil = later.Add(5 * time.Minute)
+	retried.LastError = "smtp: 451"
+	require.NoError(t, repo.FinishReminderDelivery(ctx, retried, "c"))
+	assert.Empty(t, claim("d", later.Add(4*time.Minute), 10))
+	retry, err := repo.ClaimReminderDeliveries(ctx, "d", later.Add(5*time.Minute), later.Add(6*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, retry, 1)
+	assert.Equal(t, "d3", retry[0].ID)
+	assert.Equal(t, 2, retry[0].Attempts)
+	assert.Equal(t, "smtp: 451", retry[0].LastError)
+}
+
+// testTasksDueBetween is the contract every TaskRepository must meet for
+// listing the tasks due in a window.
+func testTasksDueBetween(t *testing.T, repo domain.TaskRepository) {
+	ctx := context.Background()
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	for _, task := range []*domain.Task{
+		{ID: "late", UserID: "u2", Title: "late", Status: "todo", DueDate: day.Add(20 * time.Hour)},
+		{ID: "early", UserID: "u1", Title: "early", Status: "todo", DueDate: day.Add(9 * time.Hour)},
+		{ID: "edge", UserID: "u1", Title: "edge", Status: "done", DueDate: day.Add(24 * time.Hour)},
+		{ID: "before", UserID: "u1", Title: "before", Status: "todo", DueDate: day.Add(-time.Second)},
+		{ID: "undated", UserID: "u1", Title: "undated", Status: "todo"},
+	} {
+		require.NoError(t, repo.CreateTask(ctx, task))
+	}
+
+	tasks, err := repo.GetTasksDueBetween(ctx, day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	assert.Equal(t, []string{"early", "late"}, ids)
+}
+
+func TestInMemoryReminders(t *testing.T) {
+	testReminders(t, repository.NewInMemoryReminderRepository())
+	testReminderDeliveries(t, repository.NewInMemoryReminderDeliveryRepository())
+	testTasksDueBetween(t, repository.NewInMemoryTaskRepository())
+}
+
+func TestSQLiteReminders(t *testing.T) {
+	open := func(name string) *sql.DB {
+		db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), name))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+
+	testReminders(t, repository.NewSQLiteReminderRepository(open("reminders.db")))
+	testReminderDeliveries(t, repository.NewSQLiteReminderDeliveryRepository(open("deliveries.db")))
+	testTasksDueBetween(t, repository.NewSQLiteTaskRepository(open("tasks.db")))
+}
+
+func TestMongoReminders(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const reminders, deliveries, tasks = "test_reminders", "test_reminder_deliveries", "test_reminder_tasks"
+	for _, collection := range []string{reminders, deliveries, tasks} {
+		require.NoError(t, db.Collection(collection).Drop(ctx))
+		t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	}
+	require.NoError(t, repository.EnsureReminderIndexes(ctx, db, reminders))
+	require.NoError(t, repository.EnsureReminderDeliveryIndexes(ctx, db, deliveries))
+	require.NoError(t, repository.EnsureTaskIndexes(ctx, db, tasks))
+
+	testReminders(t, repository.NewReminderRepository(db, reminders))
+	testReminderDeliveries(t, repository.NewReminderDeliveryRepository(db, deliveries))
+	testTasksDueBetween(t, repository.NewTaskRepository(db, tasks))
+}
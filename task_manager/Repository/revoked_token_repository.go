@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type revokedTokenRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewRevokedTokenRepository(db *mongo.Database, collection string) domain.RevokedTokenRepository {
+	return &revokedTokenRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureRevokedTokenIndexes keys the list by jti and drops entries once the
+// token they revoke would have expired anyway.
+func EnsureRevokedTokenIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tokenid", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (rr *revokedTokenRepository) RevokeToken(c context.Context, tokenId string, expiresAt time.Time) error {
+	collection := rr.database.Collection(rr.collection)
+
+	filter := bson.M{"tokenid": tokenId}
+	update := bson.M{"$set": bson.M{"tokenid": tokenId, "expiresat": expiresAt}}
+	_, err := collection.UpdateOne(c, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (rr *revokedTokenRepository) IsTokenRevoked(c context.Context, tokenId string) (bool, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	// The TTL monitor runs about once a minute, so expired entries may
+	// linger; they are harmless because the token itself has expired.
+	count, err := collection.CountDocuments(c, bson.M{"tokenid": tokenId}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
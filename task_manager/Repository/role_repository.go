@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type roleRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewRoleRepository(db *mongo.Database, collection string) domain.RoleRepository {
+	return &roleRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureRoleIndexes makes role names unique.
+func EnsureRoleIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (rr *roleRepository) CreateRole(c context.Context, role *domain.Role) error {
+	collection := rr.database.Collection(rr.collection)
+
+	_, err := collection.InsertOne(c, role)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrRoleAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (rr *roleRepository) GetRoleByName(c context.Context, name string) (*domain.Role, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	var role domain.Role
+	err := collection.FindOne(c, bson.M{"name": name}).Decode(&role)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (rr *roleRepository) GetAllRoles(c context.Context) ([]*domain.Role, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	cursor, err := collection.Find(c, bson.D{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var roles []*domain.Role
+	for cursor.Next(c) {
+		var role domain.Role
+		if err := cursor.Decode(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, &role)
+	}
+	return roles, cursor.Err()
+}
@@ -0,0 +1,66 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRoles is the contract every RoleRepository must meet.
+func testRoles(t *testing.T, repo domain.RoleRepository) {
+	ctx := context.Background()
+
+	roles, err := repo.GetAllRoles(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+
+	editor := &domain.Role{Name: "editor", Description: "Edits tasks", Permissions: []string{domain.PermTaskRead, domain.PermTaskUpdate}}
+	auditor := &domain.Role{Name: "auditor", Permissions: []string{domain.PermTaskReadAny}}
+	require.NoError(t, repo.CreateRole(ctx, editor))
+	require.NoError(t, repo.CreateRole(ctx, auditor))
+	assert.ErrorIs(t, repo.CreateRole(ctx, &domain.Role{Name: "editor"}), domain.ErrRoleAlreadyExists)
+
+	found, err := repo.GetRoleByName(ctx, "editor")
+	require.NoError(t, err)
+	assert.Equal(t, "Edits tasks", found.Description)
+	assert.Equal(t, []string{domain.PermTaskRead, domain.PermTaskUpdate}, found.Permissions)
+
+	_, err = repo.GetRoleByName(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrRoleNotFound)
+
+	roles, err = repo.GetAllRoles(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+	assert.Equal(t, "auditor", roles[0].Name)
+	assert.Equal(t, "editor", roles[1].Name)
+}
+
+func TestInMemoryRoleRepository(t *testing.T) {
+	testRoles(t, repository.NewInMemoryRoleRepository())
+}
+
+func TestSQLiteRoleRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "roles.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testRoles(t, repository.NewSQLiteRoleRepository(db))
+}
+
+func TestMongoRoleRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_roles"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsureRoleIndexes(ctx, db, collection))
+
+	testRoles(t, repository.NewRoleRepository(db, collection))
+}
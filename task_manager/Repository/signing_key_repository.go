@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type signingKeyRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewSigningKeyRepository(db *mongo.Database, collection string) domain.SigningKeyRepository {
+	return &signingKeyRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureSigningKeyIndexes makes key ids unique and lets MongoDB drop keys
+// once they can no longer verify anything.
+func EnsureSigningKeyIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (kr *signingKeyRepository) CreateSigningKey(c context.Context, key *domain.SigningKey) error {
+	collection := kr.database.Collection(kr.collection)
+
+	_, err := collection.InsertOne(c, key)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTokenAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (kr *signingKeyRepository) GetSigningKeys(c context.Context, now time.Time) ([]*domain.SigningKey, error) {
+	collection := kr.database.Collection(kr.collection)
+
+	// The TTL monitor only runs once a minute, so filter on expiry too.
+	opts := options.Find().SetSort(bson.D{{Key: "notbefore", Value: 1}, {Key: "id", Value: 1}})
+	cursor, err := collection.Find(c, bson.M{"expiresat": bson.M{"$gt": now}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var keys []*domain.SigningKey
+	for cursor.Next(c) {
+		var key domain.SigningKey
+		if err := cursor.Decode(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+	return keys, cursor.Err()
+}
@@ -0,0 +1,75 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSigningKeys is the contract every SigningKeyRepository must meet.
+func testSigningKeys(t *testing.T, repo domain.SigningKeyRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	keys, err := repo.GetSigningKeys(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	next := &domain.SigningKey{ID: "k2", Algorithm: domain.SigningAlgorithmEdDSA, PrivateKey: []byte("sealed-2"), CreatedAt: now,
+		NotBefore: now.Add(time.Hour), RetiresAt: now.Add(3 * time.Hour), ExpiresAt: now.Add(4 * time.Hour)}
+	current := &domain.SigningKey{ID: "k1", Algorithm: domain.SigningAlgorithmRS256, PrivateKey: []byte("sealed-1"), CreatedAt: now,
+		NotBefore: now.Add(-time.Hour), RetiresAt: now.Add(time.Hour), ExpiresAt: now.Add(2 * time.Hour)}
+	require.NoError(t, repo.CreateSigningKey(ctx, next))
+	require.NoError(t, repo.CreateSigningKey(ctx, current))
+	assert.ErrorIs(t, repo.CreateSigningKey(ctx, &domain.SigningKey{ID: "k1", Algorithm: domain.SigningAlgorithmRS256,
+		PrivateKey: []byte("sealed-3"), CreatedAt: now, NotBefore: now, RetiresAt: now.Add(time.Hour), ExpiresAt: now.Add(time.Hour)}), domain.ErrTokenAlreadyExists)
+
+	// Ordered by when they start signing.
+	keys, err = repo.GetSigningKeys(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "k1", keys[0].ID)
+	assert.Equal(t, domain.SigningAlgorithmRS256, keys[0].Algorithm)
+	assert.Equal(t, []byte("sealed-1"), keys[0].PrivateKey)
+	assert.True(t, keys[0].NotBefore.Equal(now.Add(-time.Hour)))
+	assert.True(t, keys[0].RetiresAt.Equal(now.Add(time.Hour)))
+	assert.True(t, keys[0].ExpiresAt.Equal(now.Add(2*time.Hour)))
+	assert.Equal(t, "k2", keys[1].ID)
+
+	// Expired keys are left out.
+	keys, err = repo.GetSigningKeys(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "k2", keys[0].ID)
+}
+
+func TestInMemorySigningKeyRepository(t *testing.T) {
+	testSigningKeys(t, repository.NewInMemorySigningKeyRepository())
+}
+
+func TestSQLiteSigningKeyRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "keys.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testSigningKeys(t, repository.NewSQLiteSigningKeyRepository(db))
+}
+
+func TestMongoSigningKeyRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_signing_keys"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsureSigningKeyIndexes(ctx, db, collection))
+
+	testSigningKeys(t, repository.NewSigningKeyRepository(db, collection))
+}
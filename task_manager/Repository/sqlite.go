@@ -0,0 +1,471 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// migration is one versioned step of the SQLite schema. Migrations are
+// applied in order and each version is recorded in schema_migrations, so a
+// database is only ever moved forward from the version it is already at.
+type migration struct {
+	version    int
+	statements []string
+}
+
+// Never edit a migration that has shipped; append a new one instead.
+var migrations = []migration{
+	{
+		version: 1,
+		statements: []string{
+			`CREATE TABLE users (
+				id       TEXT PRIMARY KEY,
+				username TEXT NOT NULL,
+				email    TEXT NOT NULL,
+				password TEXT NOT NULL,
+				role     TEXT NOT NULL,
+				CONSTRAINT users_email_unique UNIQUE (email),
+				CONSTRAINT users_username_unique UNIQUE (username)
+			)`,
+			`CREATE TABLE tasks (
+				id          TEXT PRIMARY KEY,
+				user_id     TEXT NOT NULL,
+				title       TEXT NOT NULL,
+				description TEXT NOT NULL,
+				due_date    TEXT NOT NULL,
+				status      TEXT NOT NULL
+			)`,
+			`CREATE INDEX tasks_user_id_idx ON tasks (user_id)`,
+		},
+	},
+	{
+		// Indexes backing filtered and sorted task listings.
+		version: 2,
+		statements: []string{
+			`CREATE INDEX tasks_user_status_due_idx ON tasks (user_id, status, due_date, id)`,
+			`CREATE INDEX tasks_user_due_idx ON tasks (user_id, due_date, id)`,
+			`CREATE INDEX tasks_user_title_idx ON tasks (user_id, title, id)`,
+		},
+	},
+	{
+		// Workflow timestamps; NULL until work starts or completes.
+		version: 3,
+		statements: []string{
+			`ALTER TABLE tasks ADD COLUMN started_at TEXT`,
+			`ALTER TABLE tasks ADD COLUMN completed_at TEXT`,
+		},
+	},
+	{
+		// Bumped whenever a user's existing tokens must stop working.
+		version: 4,
+		statements: []string{
+			`ALTER TABLE users ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		// Custom roles; permissions is a JSON array of permission names.
+		version: 5,
+		statements: []string{
+			`CREATE TABLE roles (
+				name        TEXT PRIMARY KEY,
+				description TEXT NOT NULL,
+				permissions TEXT NOT NULL
+			)`,
+		},
+	},
+	{
+		// Account disablement, and the index behind the last-admin check.
+		version: 6,
+		statements: []string{
+			`ALTER TABLE users ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0`,
+			`CREATE INDEX users_role_idx ON users (role, disabled)`,
+		},
+	},
+	{
+		// Email verification; existing accounts count as verified.
+		version: 7,
+		statements: []string{
+			`ALTER TABLE users ADD COLUMN email_unverified INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		// TOTP enrollments, recovery_codes being a JSON array of hashes, and
+		// the roles that require them.
+		version: 8,
+		statements: []string{
+			`CREATE TABLE two_factor (
+				user_id        TEXT PRIMARY KEY,
+				secret         TEXT NOT NULL,
+				enabled        INTEGER NOT NULL,
+				recovery_codes TEXT NOT NULL,
+				last_used_step INTEGER NOT NULL,
+				created_at     TEXT NOT NULL,
+				enabled_at     TEXT
+			)`,
+			`CREATE TABLE two_factor_roles (
+				role TEXT PRIMARY KEY
+			)`,
+		},
+	},
+	{
+		// Personal access tokens; scopes is a JSON array of permission names.
+		version: 9,
+		statements: []string{
+			`CREATE TABLE personal_access_tokens (
+				id           TEXT PRIMARY KEY,
+				user_id      TEXT NOT NULL,
+				name         TEXT NOT NULL,
+				token_hash   TEXT NOT NULL,
+				scopes       TEXT NOT NULL,
+				created_at   TEXT NOT NULL,
+				expires_at   TEXT NOT NULL,
+				last_used_at TEXT,
+				CONSTRAINT personal_access_tokens_hash_unique UNIQUE (token_hash)
+			)`,
+			`CREATE INDEX personal_access_tokens_user_idx ON personal_access_tokens (user_id, created_at)`,
+		},
+	},
+	{
+		// The access token key ring; private_key is sealed by the key ring.
+		version: 10,
+		statements: []string{
+			`CREATE TABLE signing_keys (
+				id          TEXT PRIMARY KEY,
+				algorithm   TEXT NOT NULL,
+				private_key BLOB NOT NULL,
+				created_at  TEXT NOT NULL,
+				not_before  TEXT NOT NULL,
+				retires_at  TEXT NOT NULL,
+				expires_at  TEXT NOT NULL
+			)`,
+		},
+	},
+	{
+		// Registered OIDC clients; redirect_uris is a JSON array.
+		version: 11,
+		statements: []string{
+			`CREATE TABLE oidc_clients (
+				id            TEXT PRIMARY KEY,
+				name          TEXT NOT NULL,
+				secret_hash   TEXT NOT NULL,
+				redirect_uris TEXT NOT NULL,
+				created_at    TEXT NOT NULL
+			)`,
+		},
+	},
+	{
+		// Task comments; revisions is a JSON array of earlier bodies, each
+		// with the time it was written.
+		version: 12,
+		statements: []string{
+			`CREATE TABLE task_comments (
+				id         TEXT PRIMARY KEY,
+				task_id    TEXT NOT NULL,
+				parent_id  TEXT NOT NULL,
+				author_id  TEXT NOT NULL,
+				body       TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				edited_at  TEXT,
+				revisions  TEXT NOT NULL,
+				deleted_at TEXT,
+				deleted_by TEXT NOT NULL
+			)`,
+			`CREATE INDEX task_comments_task_idx ON task_comments (task_id, created_at)`,
+		},
+	},
+	{
+		// Label catalogues, and the labels attached to each task in the
+		// order they were attached (rowid).
+		version: 13,
+		statements: []string{
+			`CREATE TABLE labels (
+				id         TEXT PRIMARY KEY,
+				user_id    TEXT NOT NULL,
+				name       TEXT NOT NULL COLLATE NOCASE,
+				color      TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				CONSTRAINT labels_user_name_unique UNIQUE (user_id, name)
+			)`,
+			`CREATE TABLE task_labels (
+				task_id  TEXT NOT NULL,
+				label_id TEXT NOT NULL,
+				PRIMARY KEY (task_id, label_id)
+			)`,
+			`CREATE INDEX task_labels_label_idx ON task_labels (label_id, task_id)`,
+		},
+	},
+	{
+		// Subtasks, and checklists as a JSON array of items.
+		version: 14,
+		statements: []string{
+			`ALTER TABLE tasks ADD COLUMN parent_id TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE tasks ADD COLUMN checklist TEXT NOT NULL DEFAULT '[]'`,
+			`CREATE INDEX tasks_parent_idx ON tasks (parent_id, due_date, id)`,
+		},
+	},
+	{
+		// Task dependencies: task_id is blocked by blocker_id.
+		version: 15,
+		statements: []string{
+			`CREATE TABLE task_dependencies (
+				task_id    TEXT NOT NULL,
+				blocker_id TEXT NOT NULL,
+				user_id    TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				PRIMARY KEY (task_id, blocker_id)
+			)`,
+			`CREATE INDEX task_dependencies_blocker_idx ON task_dependencies (blocker_id)`,
+			`CREATE INDEX task_dependencies_user_idx ON task_dependencies (user_id)`,
+		},
+	},
+	{
+		// Recurring tasks: the recurrence as a JSON object, with its series
+		// ID copied out to be indexed.
+		version: 16,
+		statements: []string{
+			`ALTER TABLE tasks ADD COLUMN series_id TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE tasks ADD COLUMN recurrence TEXT`,
+			`CREATE INDEX tasks_series_idx ON tasks (series_id) WHERE series_id != ''`,
+		},
+	},
+	{
+		// Due-date reminders and their send state. Leads are stored in
+		// minutes; lease_until doubles as the retry time of a failed send.
+		version: 17,
+		statements: []string{
+			`CREATE INDEX tasks_due_idx ON tasks (due_date, id)`,
+			`CREATE TABLE reminders (
+				id           TEXT PRIMARY KEY,
+				user_id      TEXT NOT NULL,
+				lead_minutes INTEGER NOT NULL,
+				channel      TEXT NOT NULL,
+				created_at   TEXT NOT NULL,
+				CONSTRAINT reminders_user_lead_unique UNIQUE (user_id, lead_minutes, channel)
+			)`,
+			`CREATE INDEX reminders_lead_idx ON reminders (lead_minutes)`,
+			`CREATE TABLE reminder_deliveries (
+				id          TEXT PRIMARY KEY,
+				reminder_id TEXT NOT NULL,
+				task_id     TEXT NOT NULL,
+				user_id     TEXT NOT NULL,
+				channel     TEXT NOT NULL,
+				send_at     TEXT NOT NULL,
+				status      TEXT NOT NULL,
+				attempts    INTEGER NOT NULL,
+				lease_owner TEXT NOT NULL,
+				lease_until TEXT NOT NULL,
+				last_error  TEXT NOT NULL,
+				sent_at     TEXT,
+				created_at  TEXT NOT NULL
+			)`,
+			`CREATE INDEX reminder_deliveries_pending_idx ON reminder_deliveries (send_at, id) WHERE status = 'pending'`,
+		},
+	},
+	{
+		// Webhooks, with their event types as a JSON array, and the
+		// delivery log. As for reminders, lease_until doubles as the retry
+		// time.
+		version: 18,
+		statements: []string{
+			`CREATE TABLE webhooks (
+				id         TEXT PRIMARY KEY,
+				url        TEXT NOT NULL,
+				events     TEXT NOT NULL,
+				secret     TEXT NOT NULL,
+				active     INTEGER NOT NULL,
+				created_by TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			)`,
+			`CREATE TABLE webhook_deliveries (
+				id              TEXT PRIMARY KEY,
+				webhook_id      TEXT NOT NULL,
+				event_id        TEXT NOT NULL,
+				event_type      TEXT NOT NULL,
+				payload         TEXT NOT NULL,
+				status          TEXT NOT NULL,
+				attempts        INTEGER NOT NULL,
+				lease_owner     TEXT NOT NULL,
+				lease_until     TEXT NOT NULL,
+				last_error      TEXT NOT NULL,
+				response_status INTEGER NOT NULL,
+				redelivery_of   TEXT NOT NULL,
+				created_at      TEXT NOT NULL,
+				delivered_at    TEXT
+			)`,
+			`CREATE INDEX webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at, id)`,
+			`CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (created_at, id) WHERE status = 'pending'`,
+		},
+	},
+	{
+		// The event outbox. AUTOINCREMENT keeps sequences from being reused
+		// once the newest events have been pruned.
+		version: 19,
+		statements: []string{
+			`CREATE TABLE outbox_events (
+				sequence    INTEGER PRIMARY KEY AUTOINCREMENT,
+				id          TEXT NOT NULL,
+				type        TEXT NOT NULL,
+				occurred_at TEXT NOT NULL,
+				task        TEXT,
+				user        TEXT,
+				CONSTRAINT outbox_events_id_unique UNIQUE (id)
+			)`,
+			`CREATE TABLE outbox_checkpoints (
+				consumer    TEXT PRIMARY KEY,
+				sequence    INTEGER NOT NULL,
+				lease_owner TEXT NOT NULL,
+				lease_until TEXT NOT NULL,
+				updated_at  TEXT NOT NULL
+			)`,
+		},
+	},
+	{
+		// The audit log. Triggers refuse changes to entries once written.
+		version: 20,
+		statements: []string{
+			`CREATE TABLE audit_log (
+				sequence    INTEGER PRIMARY KEY,
+				id          TEXT NOT NULL,
+				occurred_at TEXT NOT NULL,
+				actor_id    TEXT NOT NULL,
+				actor_name  TEXT NOT NULL,
+				action      TEXT NOT NULL,
+				target_type TEXT NOT NULL,
+				target_id   TEXT NOT NULL,
+				before      TEXT,
+				after       TEXT,
+				reason      TEXT NOT NULL,
+				ip          TEXT NOT NULL,
+				request_id  TEXT NOT NULL,
+				prev_hash   TEXT NOT NULL,
+				hash        TEXT NOT NULL,
+				CONSTRAINT audit_log_id_unique UNIQUE (id)
+			)`,
+			`CREATE INDEX audit_log_actor_idx ON audit_log (actor_id, sequence)`,
+			`CREATE INDEX audit_log_target_idx ON audit_log (target_type, target_id, sequence)`,
+			`CREATE INDEX audit_log_occurred_at_idx ON audit_log (occurred_at)`,
+			`CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+			BEGIN SELECT RAISE(ABORT, 'audit log entries cannot be changed'); END`,
+			`CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+			BEGIN SELECT RAISE(ABORT, 'audit log entries cannot be deleted'); END`,
+		},
+	},
+}
+
+// sqlitePragmas are applied by the driver to every connection it opens.
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+// OpenSQLite opens (creating if needed) the SQLite database at path and
+// brings its schema up to date. Use ":memory:" for a throwaway database.
+func OpenSQLite(path string) (*sql.DB, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	db, err := sql.Open("sqlite", path+separator+sqlitePragmas)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite serialises writers anyway; a single connection avoids
+	// SQLITE_BUSY errors and keeps ":memory:" databases shared.
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return err
+	}
+
+	var current int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("sqlite migration %d: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range m.statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+		m.version, formatSQLiteTime(time.Now())); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Times are stored as fixed-width UTC text so that they compare and sort
+// correctly as plain strings.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func parseSQLiteTime(s string) (time.Time, error) {
+	return time.Parse(sqliteTimeLayout, s)
+}
+
+// nullableSQLiteTime maps a nil time to NULL.
+func nullableSQLiteTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatSQLiteTime(*t), Valid: true}
+}
+
+func parseNullableSQLiteTime(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	t, err := parseSQLiteTime(s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// isUniqueViolation reports whether err is a UNIQUE or PRIMARY KEY
+// constraint failure.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteAuditRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteAuditRepository(db *sql.DB) domain.AuditRepository {
+	return &sqliteAuditRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const sqliteAuditColumns = `sequence, id, occurred_at, actor_id, actor_name, action, target_type, target_id,
+	before, after, reason, ip, request_id, prev_hash, hash`
+
+// AppendAuditEntry reads the newest entry and writes the next in one
+// transaction; SQLite runs one writing transaction at a time, so no other
+// entry can be chained to the same one.
+func (ar *sqliteAuditRepository) AppendAuditEntry(c context.Context, entry *domain.AuditEntry) error {
+	tx, err := ar.db.BeginTx(c, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	entry.Sequence = 1
+	entry.PrevHash = ""
+	var last int64
+	var lastHash string
+	err = tx.QueryRowContext(c, `SELECT sequence, hash FROM audit_log ORDER BY sequence DESC LIMIT 1`).Scan(&last, &lastHash)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		entry.Sequence = last + 1
+		entry.PrevHash = lastHash
+	}
+	entry.Hash = entry.ComputeHash()
+
+	if _, err := tx.ExecContext(c, `INSERT INTO audit_log (`+sqliteAuditColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Sequence, entry.ID, formatSQLiteTime(entry.OccurredAt), entry.ActorID, entry.ActorName,
+		entry.Action, entry.TargetType, entry.TargetID, nullableRawJSON(entry.Before), nullableRawJSON(entry.After),
+		entry.Reason, entry.IP, entry.RequestID, entry.PrevHash, entry.Hash); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// nullableRawJSON maps an empty snapshot to NULL.
+func nullableRawJSON(raw []byte) sql.NullString {
+	if len(raw) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(raw), Valid: true}
+}
+
+func (ar *sqliteAuditRepository) GetAuditEntries(c context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	after, err := decodeAuditCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	where := []string{"sequence > ?"}
+	args := []any{after}
+	if query.ActorID != "" {
+		where = append(where, "actor_id = ?")
+		args = append(args, query.ActorID)
+	}
+	if query.TargetType != "" {
+		where = append(where, "target_type = ?")
+		args = append(args, query.TargetType)
+	}
+	if query.TargetID != "" {
+		where = append(where, "target_id = ?")
+		args = append(args, query.TargetID)
+	}
+	if !query.From.IsZero() {
+		where = append(where, "occurred_at >= ?")
+		args = append(args, formatSQLiteTime(query.From))
+	}
+	if !query.To.IsZero() {
+		where = append(where, "occurred_at < ?")
+		args = append(args, formatSQLiteTime(query.To))
+	}
+	stmt := `SELECT ` + sqliteAuditColumns + ` FROM audit_log WHERE ` + strings.Join(where, " AND ") + ` ORDER BY sequence`
+	if query.Limit > 0 {
+		stmt += " LIMIT ?"
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := ar.db.QueryContext(c, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var occurredAt string
+		var before, after sql.NullString
+		if err := rows.Scan(&entry.Sequence, &entry.ID, &occurredAt, &entry.ActorID, &entry.ActorName,
+			&entry.Action, &entry.TargetType, &entry.TargetID, &before, &after,
+			&entry.Reason, &entry.IP, &entry.RequestID, &entry.PrevHash, &entry.Hash); err != nil {
+			return nil, err
+		}
+		if entry.OccurredAt, err = parseSQLiteTime(occurredAt); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			entry.Before = []byte(before.String)
+		}
+		if after.Valid {
+			entry.After = []byte(after.String)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return newAuditPage(entries, query.Limit), nil
+}
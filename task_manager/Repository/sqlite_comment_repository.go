@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteCommentRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteCommentRepository(db *sql.DB) domain.CommentRepository {
+	return &sqliteCommentRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const commentColumns = `id, task_id, parent_id, author_id, body, created_at, edited_at, revisions, deleted_at, deleted_by`
+
+// sqliteCommentRevision is how a revision is stored in the revisions JSON
+// column; written_at uses the same layout as every other time column.
+type sqliteCommentRevision struct {
+	Body      string `json:"body"`
+	WrittenAt string `json:"written_at"`
+}
+
+func (cr *sqliteCommentRepository) CreateComment(c context.Context, comment *domain.Comment) error {
+	revisions := make([]sqliteCommentRevision, 0, len(comment.Revisions))
+	for _, revision := range comment.Revisions {
+		revisions = append(revisions, sqliteCommentRevision{Body: revision.Body, WrittenAt: formatSQLiteTime(revision.WrittenAt)})
+	}
+	encoded, err := json.Marshal(revisions)
+	if err != nil {
+		return err
+	}
+	_, err = cr.db.ExecContext(c, `INSERT INTO task_comments (`+commentColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		comment.ID, comment.TaskID, comment.ParentID, comment.AuthorID, comment.Body,
+		formatSQLiteTime(comment.CreatedAt), nullableSQLiteTime(comment.EditedAt), string(encoded),
+		nullableSQLiteTime(comment.DeletedAt), comment.DeletedBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrCommentAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (cr *sqliteCommentRepository) GetCommentByID(c context.Context, commentId string) (*domain.Comment, error) {
+	row := cr.db.QueryRowContext(c, `SELECT `+commentColumns+` FROM task_comments WHERE id = ?`, commentId)
+	comment, err := scanSQLiteComment(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (cr *sqliteCommentRepository) GetCommentsByTask(c context.Context, taskId string) ([]*domain.Comment, error) {
+	rows, err := cr.db.QueryContext(c, `SELECT `+commentColumns+` FROM task_comments
+		WHERE task_id = ? ORDER BY created_at, id`, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*domain.Comment
+	for rows.Next() {
+		comment, err := scanSQLiteComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+// EditComment archives the old body in the same statement: the right-hand
+// sides of an UPDATE all see the row as it was before it.
+func (cr *sqliteCommentRepository) EditComment(c context.Context, commentId string, body string, editedAt time.Time) (*domain.Comment, error) {
+	row := cr.db.QueryRowContext(c, `UPDATE task_comments
+		SET revisions = json_insert(revisions, '$[#]', json_object('body', body, 'written_at', COALESCE(edited_at, created_at))),
+			body = ?, edited_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+		RETURNING `+commentColumns, body, formatSQLiteTime(editedAt), commentId)
+	comment, err := scanSQLiteComment(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (cr *sqliteCommentRepository) DeleteComment(c context.Context, commentId string, deletedBy string, deletedAt time.Time) error {
+	result, err := cr.db.ExecContext(c, `UPDATE task_comments SET body = '', revisions = '[]', deleted_at = ?, deleted_by = ?
+		WHERE id = ? AND deleted_at IS NULL`, formatSQLiteTime(deletedAt), deletedBy, commentId)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrCommentNotFound
+	}
+	return nil
+}
+
+func (cr *sqliteCommentRepository) CountCommentsByTask(c context.Context, taskIds []string) (map[string]int, error) {
+	counts := make(map[string]int, len(taskIds))
+	if len(taskIds) == 0 {
+		return counts, nil
+	}
+	args := make([]any, len(taskIds))
+	for i, id := range taskIds {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(taskIds)), ", ")
+	rows, err := cr.db.QueryContext(c, `SELECT task_id, COUNT(*) FROM task_comments
+		WHERE task_id IN (`+placeholders+`) AND deleted_at IS NULL GROUP BY task_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskId string
+		var count int
+		if err := rows.Scan(&taskId, &count); err != nil {
+			return nil, err
+		}
+		counts[taskId] = count
+	}
+	return counts, rows.Err()
+}
+
+func (cr *sqliteCommentRepository) DeleteCommentsByTask(c context.Context, taskId string) error {
+	_, err := cr.db.ExecContext(c, `DELETE FROM task_comments WHERE task_id = ?`, taskId)
+	return err
+}
+
+func (cr *sqliteCommentRepository) DeleteCommentsByTasks(c context.Context, taskIds []string) error {
+	if len(taskIds) == 0 {
+		return nil
+	}
+	args := make([]any, len(taskIds))
+	for i, id := range taskIds {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(taskIds)), ", ")
+	_, err := cr.db.ExecContext(c, `DELETE FROM task_comments WHERE task_id IN (`+placeholders+`)`, args...)
+	return err
+}
+
+func scanSQLiteComment(row rowScanner) (*domain.Comment, error) {
+	var comment domain.Comment
+	var createdAt, revisions string
+	var editedAt, deletedAt sql.NullString
+	err := row.Scan(&comment.ID, &comment.TaskID, &comment.ParentID, &comment.AuthorID, &comment.Body,
+		&createdAt, &editedAt, &revisions, &deletedAt, &comment.DeletedBy)
+	if err != nil {
+		return nil, err
+	}
+	if comment.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	if comment.EditedAt, err = parseNullableSQLiteTime(editedAt); err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt, err = parseNullableSQLiteTime(deletedAt); err != nil {
+		return nil, err
+	}
+	var stored []sqliteCommentRevision
+	if err := json.Unmarshal([]byte(revisions), &stored); err != nil {
+		return nil, err
+	}
+	for _, revision := range stored {
+		writtenAt, err := parseSQLiteTime(revision.WrittenAt)
+		if err != nil {
+			return nil, err
+		}
+		comment.Revisions = append(comment.Revisions, domain.CommentRevision{Body: revision.Body, WrittenAt: writtenAt})
+	}
+	return &comment, nil
+}
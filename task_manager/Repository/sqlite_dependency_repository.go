@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteDependencyRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteDependencyRepository(db *sql.DB) domain.DependencyRepository {
+	return &sqliteDependencyRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const dependencyColumns = `task_id, blocker_id, user_id, created_at`
+
+func (dr *sqliteDependencyRepository) AddDependency(c context.Context, dependency *domain.TaskDependency) error {
+	_, err := dr.db.ExecContext(c, `INSERT INTO task_dependencies (`+dependencyColumns+`) VALUES (?, ?, ?, ?)`,
+		dependency.TaskID, dependency.BlockerID, dependency.UserID, formatSQLiteTime(dependency.CreatedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDependencyAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (dr *sqliteDependencyRepository) RemoveDependency(c context.Context, taskId string, blockerId string) error {
+	_, err := dr.db.ExecContext(c, `DELETE FROM task_dependencies WHERE task_id = ? AND blocker_id = ?`, taskId, blockerId)
+	return err
+}
+
+func (dr *sqliteDependencyRepository) GetBlockers(c context.Context, taskId string) ([]*domain.TaskDependency, error) {
+	return dr.query(c, `WHERE task_id = ?`, taskId)
+}
+
+func (dr *sqliteDependencyRepository) GetDependents(c context.Context, blockerId string) ([]*domain.TaskDependency, error) {
+	return dr.query(c, `WHERE blocker_id = ?`, blockerId)
+}
+
+func (dr *sqliteDependencyRepository) GetDependenciesByUser(c context.Context, userId string) ([]*domain.TaskDependency, error) {
+	return dr.query(c, `WHERE user_id = ?`, userId)
+}
+
+func (dr *sqliteDependencyRepository) DeleteDependenciesByTask(c context.Context, taskId string) error {
+	_, err := dr.db.ExecContext(c, `DELETE FROM task_dependencies WHERE task_id = ? OR blocker_id = ?`, taskId, taskId)
+	return err
+}
+
+func (dr *sqliteDependencyRepository) DeleteDependenciesByUser(c context.Context, userId string) error {
+	_, err := dr.db.ExecContext(c, `DELETE FROM task_dependencies WHERE user_id = ?`, userId)
+	return err
+}
+
+func (dr *sqliteDependencyRepository) query(c context.Context, where string, args ...any) ([]*domain.TaskDependency, error) {
+	rows, err := dr.db.QueryContext(c, `SELECT `+dependencyColumns+` FROM task_dependencies `+where+` ORDER BY created_at, rowid`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dependencies []*domain.TaskDependency
+	for rows.Next() {
+		var dependency domain.TaskDependency
+		var createdAt string
+		if err := rows.Scan(&dependency.TaskID, &dependency.BlockerID, &dependency.UserID, &createdAt); err != nil {
+			return nil, err
+		}
+		if dependency.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, &dependency)
+	}
+	return dependencies, rows.Err()
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteLabelRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteLabelRepository(db *sql.DB) domain.LabelRepository {
+	return &sqliteLabelRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const labelColumns = `id, user_id, name, color, created_at`
+
+func (lr *sqliteLabelRepository) CreateLabel(c context.Context, label *domain.Label) error {
+	_, err := lr.db.ExecContext(c, `INSERT INTO labels (`+labelColumns+`) VALUES (?, ?, ?, ?, ?)`,
+		label.ID, label.UserID, label.Name, label.Color, formatSQLiteTime(label.CreatedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrLabelAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (lr *sqliteLabelRepository) GetLabelByID(c context.Context, labelId string) (*domain.Label, error) {
+	row := lr.db.QueryRowContext(c, `SELECT `+labelColumns+` FROM labels WHERE id = ?`, labelId)
+	label, err := scanSQLiteLabel(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrLabelNotFound
+		}
+		return nil, err
+	}
+	return label, nil
+}
+
+func (lr *sqliteLabelRepository) GetLabelsByUser(c context.Context, userId string) ([]*domain.Label, error) {
+	rows, err := lr.db.QueryContext(c, `SELECT `+labelColumns+` FROM labels WHERE user_id = ? ORDER BY name, id`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []*domain.Label
+	for rows.Next() {
+		label, err := scanSQLiteLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+func (lr *sqliteLabelRepository) UpdateLabel(c context.Context, label *domain.Label) error {
+	result, err := lr.db.ExecContext(c, `UPDATE labels SET name = ?, color = ? WHERE id = ?`,
+		label.Name, label.Color, label.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrLabelAlreadyExists
+		}
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrLabelNotFound
+	}
+	return nil
+}
+
+func (lr *sqliteLabelRepository) DeleteLabel(c context.Context, labelId string) error {
+	result, err := lr.db.ExecContext(c, `DELETE FROM labels WHERE id = ?`, labelId)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrLabelNotFound
+	}
+	return nil
+}
+
+func scanSQLiteLabel(row rowScanner) (*domain.Label, error) {
+	var label domain.Label
+	var createdAt string
+	if err := row.Scan(&label.ID, &label.UserID, &label.Name, &label.Color, &createdAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if label.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
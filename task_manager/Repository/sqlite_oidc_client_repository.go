@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteOIDCClientRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteOIDCClientRepository(db *sql.DB) domain.OIDCClientRepository {
+	return &sqliteOIDCClientRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const oidcClientColumns = `id, name, secret_hash, redirect_uris, created_at`
+
+func (or *sqliteOIDCClientRepository) CreateOIDCClient(c context.Context, client *domain.OIDCClient) error {
+	redirectURIs := client.RedirectURIs
+	if redirectURIs == nil {
+		redirectURIs = []string{}
+	}
+	encoded, err := json.Marshal(redirectURIs)
+	if err != nil {
+		return err
+	}
+	_, err = or.db.ExecContext(c, `INSERT INTO oidc_clients (`+oidcClientColumns+`) VALUES (?, ?, ?, ?, ?)`,
+		client.ID, client.Name, client.SecretHash, string(encoded), formatSQLiteTime(client.CreatedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrOIDCClientAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (or *sqliteOIDCClientRepository) GetOIDCClientByID(c context.Context, clientId string) (*domain.OIDCClient, error) {
+	row := or.db.QueryRowContext(c, `SELECT `+oidcClientColumns+` FROM oidc_clients WHERE id = ?`, clientId)
+	client, err := scanSQLiteOIDCClient(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrOIDCClientNotFound
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+func (or *sqliteOIDCClientRepository) GetOIDCClients(c context.Context) ([]*domain.OIDCClient, error) {
+	rows, err := or.db.QueryContext(c, `SELECT `+oidcClientColumns+` FROM oidc_clients ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []*domain.OIDCClient
+	for rows.Next() {
+		client, err := scanSQLiteOIDCClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return clients, rows.Err()
+}
+
+func (or *sqliteOIDCClientRepository) DeleteOIDCClient(c context.Context, clientId string) error {
+	result, err := or.db.ExecContext(c, `DELETE FROM oidc_clients WHERE id = ?`, clientId)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrOIDCClientNotFound
+	}
+	return nil
+}
+
+func scanSQLiteOIDCClient(row rowScanner) (*domain.OIDCClient, error) {
+	var client domain.OIDCClient
+	var redirectURIs, createdAt string
+	err := row.Scan(&client.ID, &client.Name, &client.SecretHash, &redirectURIs, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(redirectURIs), &client.RedirectURIs); err != nil {
+		return nil, err
+	}
+	if client.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
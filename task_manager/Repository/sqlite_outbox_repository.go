@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteOutboxRepository struct {
+	db sqliteDB
+}
+
+// NewSQLiteOutboxRepository numbers events with the table's AUTOINCREMENT
+// key. SQLite runs one writing transaction at a time, so events are
+// numbered in the order their transactions commit.
+func NewSQLiteOutboxRepository(db *sql.DB) domain.OutboxRepository {
+	return &sqliteOutboxRepository{
+		db: sqliteDB{db},
+	}
+}
+
+// nullableJSON encodes v as JSON, or NULL when it is nil.
+func nullableJSON[T any](v *T) (sql.NullString, error) {
+	if v == nil {
+		return sql.NullString{}, nil
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
+func (or *sqliteOutboxRepository) AppendEvent(c context.Context, event *domain.Event) error {
+	task, err := nullableJSON(event.Task)
+	if err != nil {
+		return err
+	}
+	user, err := nullableJSON(event.User)
+	if err != nil {
+		return err
+	}
+	return or.db.QueryRowContext(c, `INSERT INTO outbox_events (id, type, occurred_at, task, user)
+		VALUES (?, ?, ?, ?, ?) RETURNING sequence`,
+		event.ID, event.Type, formatSQLiteTime(event.OccurredAt), task, user).Scan(&event.Sequence)
+}
+
+func (or *sqliteOutboxRepository) GetEventsAfter(c context.Context, sequence int64, limit int) ([]*domain.Event, error) {
+	rows, err := or.db.QueryContext(c, `SELECT sequence, id, type, occurred_at, task, user FROM outbox_events
+		WHERE sequence > ? ORDER BY sequence LIMIT ?`, sequence, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		var event domain.Event
+		var occurredAt string
+		var task, user sql.NullString
+		if err := rows.Scan(&event.Sequence, &event.ID, &event.Type, &occurredAt, &task, &user); err != nil {
+			return nil, err
+		}
+		if event.OccurredAt, err = parseSQLiteTime(occurredAt); err != nil {
+			return nil, err
+		}
+		if task.Valid {
+			if err := json.Unmarshal([]byte(task.String), &event.Task); err != nil {
+				return nil, err
+			}
+		}
+		if user.Valid {
+			if err := json.Unmarshal([]byte(user.String), &event.User); err != nil {
+				return nil, err
+			}
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+func (or *sqliteOutboxRepository) DeleteEvents(c context.Context, through int64, before time.Time) error {
+	_, err := or.db.ExecContext(c, `DELETE FROM outbox_events WHERE sequence <= ? AND occurred_at < ?`,
+		through, formatSQLiteTime(before))
+	return err
+}
+
+type sqliteOutboxCheckpointRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteOutboxCheckpointRepository(db *sql.DB) domain.OutboxCheckpointRepository {
+	return &sqliteOutboxCheckpointRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const outboxCheckpointColumns = `consumer, sequence, lease_owner, lease_until, updated_at`
+
+// ClaimCheckpoint creates or takes over the checkpoint in one upsert; it
+// returns no row when another owner's lease still runs.
+func (cr *sqliteOutboxCheckpointRepository) ClaimCheckpoint(c context.Context, consumer string, owner string, now time.Time, leaseUntil time.Time) (*domain.OutboxCheckpoint, error) {
+	row := cr.db.QueryRowContext(c, `INSERT INTO outbox_checkpoints (`+outboxCheckpointColumns+`) VALUES (?, 0, ?, ?, ?)
+		ON CONFLICT (consumer) DO UPDATE SET lease_owner = excluded.lease_owner, lease_until = excluded.lease_until
+		WHERE outbox_checkpoints.lease_owner = excluded.lease_owner OR outbox_checkpoints.lease_until <= ?
+		RETURNING `+outboxCheckpointColumns,
+		consumer, owner, formatSQLiteTime(leaseUntil), formatSQLiteTime(now), formatSQLiteTime(now))
+	checkpoint, err := scanSQLiteOutboxCheckpoint(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrOutboxLeaseHeld
+		}
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+func (cr *sqliteOutboxCheckpointRepository) AdvanceCheckpoint(c context.Context, consumer string, owner string, sequence int64) error {
+	result, err := cr.db.ExecContext(c, `UPDATE outbox_checkpoints SET sequence = ?, updated_at = ?
+		WHERE consumer = ? AND lease_owner = ?`,
+		sequence, formatSQLiteTime(time.Now()), consumer, owner)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrOutboxLeaseLost
+	}
+	return nil
+}
+
+func (cr *sqliteOutboxCheckpointRepository) GetCheckpoints(c context.Context) ([]*domain.OutboxCheckpoint, error) {
+	rows, err := cr.db.QueryContext(c, `SELECT `+outboxCheckpointColumns+` FROM outbox_checkpoints ORDER BY consumer`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checkpoints []*domain.OutboxCheckpoint
+	for rows.Next() {
+		checkpoint, err := scanSQLiteOutboxCheckpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+func scanSQLiteOutboxCheckpoint(row rowScanner) (*domain.OutboxCheckpoint, error) {
+	var checkpoint domain.OutboxCheckpoint
+	var leaseUntil, updatedAt string
+	if err := row.Scan(&checkpoint.Consumer, &checkpoint.Sequence, &checkpoint.LeaseOwner, &leaseUntil, &updatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if checkpoint.LeaseUntil, err = parseSQLiteTime(leaseUntil); err != nil {
+		return nil, err
+	}
+	if checkpoint.UpdatedAt, err = parseSQLiteTime(updatedAt); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type sqlitePersonalAccessTokenRepository struct {
+	db sqliteDB
+}
+
+func NewSQLitePersonalAccessTokenRepository(db *sql.DB) domain.PersonalAccessTokenRepository {
+	return &sqlitePersonalAccessTokenRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const personalAccessTokenColumns = `id, user_id, name, token_hash, scopes, created_at, expires_at, last_used_at`
+
+func (pr *sqlitePersonalAccessTokenRepository) CreatePersonalAccessToken(c context.Context, token *domain.PersonalAccessToken) error {
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	encoded, err := json.Marshal(scopes)
+	if err != nil {
+		return err
+	}
+	_, err = pr.db.ExecContext(c, `INSERT INTO personal_access_tokens (`+personalAccessTokenColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		token.ID, token.UserID, token.Name, token.TokenHash, string(encoded),
+		formatSQLiteTime(token.CreatedAt), formatSQLiteTime(token.ExpiresAt), nullableSQLiteTime(token.LastUsedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrTokenAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (pr *sqlitePersonalAccessTokenRepository) GetPersonalAccessTokenByHash(c context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	row := pr.db.QueryRowContext(c, `SELECT `+personalAccessTokenColumns+` FROM personal_access_tokens WHERE token_hash = ?`, tokenHash)
+	token, err := scanSQLitePersonalAccessToken(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func (pr *sqlitePersonalAccessTokenRepository) GetPersonalAccessTokensByUser(c context.Context, userId string) ([]*domain.PersonalAccessToken, error) {
+	rows, err := pr.db.QueryContext(c, `SELECT `+personalAccessTokenColumns+` FROM personal_access_tokens
+		WHERE user_id = ? ORDER BY created_at DESC, id`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*domain.PersonalAccessToken
+	for rows.Next() {
+		token, err := scanSQLitePersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (pr *sqlitePersonalAccessTokenRepository) DeletePersonalAccessToken(c context.Context, userId string, tokenId string) error {
+	result, err := pr.db.ExecContext(c, `DELETE FROM personal_access_tokens WHERE id = ? AND user_id = ?`, tokenId, userId)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+func (pr *sqlitePersonalAccessTokenRepository) DeletePersonalAccessTokensByUser(c context.Context, userId string) error {
+	_, err := pr.db.ExecContext(c, `DELETE FROM personal_access_tokens WHERE user_id = ?`, userId)
+	return err
+}
+
+func (pr *sqlitePersonalAccessTokenRepository) TouchPersonalAccessToken(c context.Context, tokenId string, usedAt time.Time) error {
+	_, err := pr.db.ExecContext(c, `UPDATE personal_access_tokens SET last_used_at = ? WHERE id = ?`, formatSQLiteTime(usedAt), tokenId)
+	return err
+}
+
+func scanSQLitePersonalAccessToken(row rowScanner) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	var scopes, createdAt, expiresAt string
+	var lastUsedAt sql.NullString
+	err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenHash, &scopes, &createdAt, &expiresAt, &lastUsedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &token.Scopes); err != nil {
+		return nil, err
+	}
+	if token.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	if token.ExpiresAt, err = parseSQLiteTime(expiresAt); err != nil {
+		return nil, err
+	}
+	if token.LastUsedAt, err = parseNullableSQLiteTime(lastUsedAt); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteReminderRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteReminderRepository(db *sql.DB) domain.ReminderRepository {
+	return &sqliteReminderRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const reminderColumns = `id, user_id, lead_minutes, channel, created_at`
+
+func (rr *sqliteReminderRepository) CreateReminder(c context.Context, reminder *domain.Reminder) error {
+	_, err := rr.db.ExecContext(c, `INSERT INTO reminders (`+reminderColumns+`) VALUES (?, ?, ?, ?, ?)`,
+		reminder.ID, reminder.UserID, int64(reminder.Lead/time.Minute), reminder.Channel, formatSQLiteTime(reminder.CreatedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrReminderAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (rr *sqliteReminderRepository) GetReminderByID(c context.Context, reminderId string) (*domain.Reminder, error) {
+	row := rr.db.QueryRowContext(c, `SELECT `+reminderColumns+` FROM reminders WHERE id = ?`, reminderId)
+	reminder, err := scanSQLiteReminder(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrReminderNotFound
+		}
+		return nil, err
+	}
+	return reminder, nil
+}
+
+func (rr *sqliteReminderRepository) GetRemindersByUser(c context.Context, userId string) ([]*domain.Reminder, error) {
+	rows, err := rr.db.QueryContext(c, `SELECT `+reminderColumns+` FROM reminders WHERE user_id = ?
+		ORDER BY lead_minutes DESC, channel`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*domain.Reminder
+	for rows.Next() {
+		reminder, err := scanSQLiteReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+	return reminders, rows.Err()
+}
+
+func (rr *sqliteReminderRepository) GetReminderLeads(c context.Context) ([]time.Duration, error) {
+	rows, err := rr.db.QueryContext(c, `SELECT DISTINCT lead_minutes FROM reminders`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leads []time.Duration
+	for rows.Next() {
+		var minutes int64
+		if err := rows.Scan(&minutes); err != nil {
+			return nil, err
+		}
+		leads = append(leads, time.Duration(minutes)*time.Minute)
+	}
+	return leads, rows.Err()
+}
+
+func (rr *sqliteReminderRepository) DeleteReminder(c context.Context, reminderId string) error {
+	result, err := rr.db.ExecContext(c, `DELETE FROM reminders WHERE id = ?`, reminderId)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrReminderNotFound
+	}
+	return nil
+}
+
+func scanSQLiteReminder(row rowScanner) (*domain.Reminder, error) {
+	var reminder domain.Reminder
+	var minutes int64
+	var createdAt string
+	if err := row.Scan(&reminder.ID, &reminder.UserID, &minutes, &reminder.Channel, &createdAt); err != nil {
+		return nil, err
+	}
+	reminder.Lead = time.Duration(minutes) * time.Minute
+	var err error
+	if reminder.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+type sqliteReminderDeliveryRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteReminderDeliveryRepository(db *sql.DB) domain.ReminderDeliveryRepository {
+	return &sqliteReminderDeliveryRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const reminderDeliveryColumns = `id, reminder_id, task_id, user_id, channel, send_at, status, attempts,
+	lease_owner, lease_until, last_error, sent_at, created_at`
+
+func (dr *sqliteReminderDeliveryRepository) EnqueueReminderDelivery(c context.Context, delivery *domain.ReminderDelivery) error {
+	_, err := dr.db.ExecContext(c, `INSERT INTO reminder_deliveries (`+reminderDeliveryColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING`,
+		delivery.ID, delivery.ReminderID, delivery.TaskID, delivery.UserID, delivery.Channel,
+		formatSQLiteTime(delivery.SendAt), delivery.Status, delivery.Attempts, delivery.LeaseOwner,
+		formatSQLiteTime(delivery.LeaseUntil), delivery.LastError, nullableSQLiteTime(delivery.SentAt),
+		formatSQLiteTime(delivery.CreatedAt))
+	return err
+}
+
+// ClaimReminderDeliveries picks and leases the deliveries in one UPDATE, so
+// no other connection can claim them in between.
+func (dr *sqliteReminderDeliveryRepository) ClaimReminderDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.ReminderDelivery, error) {
+	rows, err := dr.db.QueryContext(c, `UPDATE reminder_deliveries
+		SET lease_owner = ?, lease_until = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM reminder_deliveries
+			WHERE status = ? AND send_at <= ? AND lease_until <= ?
+			ORDER BY send_at, id LIMIT ?
+		)
+		RETURNING `+reminderDeliveryColumns,
+		owner, formatSQLiteTime(leaseUntil), domain.ReminderPending, formatSQLiteTime(now), formatSQLiteTime(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claimed []*domain.ReminderDelivery
+	for rows.Next() {
+		delivery, err := scanSQLiteReminderDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING gives no order guarantee.
+	sortReminderDeliveries(claimed)
+	return claimed, nil
+}
+
+func (dr *sqliteReminderDeliveryRepository) FinishReminderDelivery(c context.Context, delivery *domain.ReminderDelivery, owner string) error {
+	result, err := dr.db.ExecContext(c, `UPDATE reminder_deliveries
+		SET status = ?, attempts = ?, lease_owner = '', lease_until = ?, last_error = ?, sent_at = ?
+		WHERE id = ? AND lease_owner = ?`,
+		delivery.Status, delivery.Attempts, formatSQLiteTime(delivery.LeaseUntil), delivery.LastError, nullableSQLiteTime(delivery.SentAt),
+		delivery.ID, owner)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrReminderLeaseLost
+	}
+	return nil
+}
+
+func scanSQLiteReminderDelivery(row rowScanner) (*domain.ReminderDelivery, error) {
+	var delivery domain.ReminderDelivery
+	var sendAt, leaseUntil, createdAt string
+	var sentAt sql.NullString
+	if err := row.Scan(&delivery.ID, &delivery.ReminderID, &delivery.TaskID, &delivery.UserID, &delivery.Channel,
+		&sendAt, &delivery.Status, &delivery.Attempts, &delivery.LeaseOwner, &leaseUntil, &delivery.LastError,
+		&sentAt, &createdAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if delivery.SendAt, err = parseSQLiteTime(sendAt); err != nil {
+		return nil, err
+	}
+	if delivery.LeaseUntil, err = parseSQLiteTime(leaseUntil); err != nil {
+		return nil, err
+	}
+	if delivery.SentAt, err = parseNullableSQLiteTime(sentAt); err != nil {
+		return nil, err
+	}
+	if delivery.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
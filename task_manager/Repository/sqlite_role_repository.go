@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteRoleRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteRoleRepository(db *sql.DB) domain.RoleRepository {
+	return &sqliteRoleRepository{
+		db: sqliteDB{db},
+	}
+}
+
+func (rr *sqliteRoleRepository) CreateRole(c context.Context, role *domain.Role) error {
+	permissions, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return err
+	}
+	_, err = rr.db.ExecContext(c, `INSERT INTO roles (name, description, permissions) VALUES (?, ?, ?)`,
+		role.Name, role.Description, string(permissions))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrRoleAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (rr *sqliteRoleRepository) GetRoleByName(c context.Context, name string) (*domain.Role, error) {
+	row := rr.db.QueryRowContext(c, `SELECT name, description, permissions FROM roles WHERE name = ?`, name)
+
+	role, err := scanSQLiteRole(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (rr *sqliteRoleRepository) GetAllRoles(c context.Context) ([]*domain.Role, error) {
+	rows, err := rr.db.QueryContext(c, `SELECT name, description, permissions FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []*domain.Role
+	for rows.Next() {
+		role, err := scanSQLiteRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func scanSQLiteRole(row rowScanner) (*domain.Role, error) {
+	var role domain.Role
+	var permissions string
+	if err := row.Scan(&role.Name, &role.Description, &permissions); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(permissions), &role.Permissions); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteSigningKeyRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteSigningKeyRepository(db *sql.DB) domain.SigningKeyRepository {
+	return &sqliteSigningKeyRepository{
+		db: sqliteDB{db},
+	}
+}
+
+// CreateSigningKey also deletes keys that have expired.
+func (kr *sqliteSigningKeyRepository) CreateSigningKey(c context.Context, key *domain.SigningKey) error {
+	tx, err := kr.db.BeginTx(c, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(c, `DELETE FROM signing_keys WHERE expires_at <= ?`, formatSQLiteTime(time.Now())); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(c, `INSERT INTO signing_keys (id, algorithm, private_key, created_at, not_before, retires_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		key.ID, key.Algorithm, key.PrivateKey, formatSQLiteTime(key.CreatedAt),
+		formatSQLiteTime(key.NotBefore), formatSQLiteTime(key.RetiresAt), formatSQLiteTime(key.ExpiresAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrTokenAlreadyExists
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (kr *sqliteSigningKeyRepository) GetSigningKeys(c context.Context, now time.Time) ([]*domain.SigningKey, error) {
+	rows, err := kr.db.QueryContext(c, `SELECT id, algorithm, private_key, created_at, not_before, retires_at, expires_at
+		FROM signing_keys WHERE expires_at > ? ORDER BY not_before, id`, formatSQLiteTime(now))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domain.SigningKey
+	for rows.Next() {
+		var key domain.SigningKey
+		var createdAt, notBefore, retiresAt, expiresAt string
+		if err := rows.Scan(&key.ID, &key.Algorithm, &key.PrivateKey, &createdAt, &notBefore, &retiresAt, &expiresAt); err != nil {
+			return nil, err
+		}
+		if key.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+			return nil, err
+		}
+		if key.NotBefore, err = parseSQLiteTime(notBefore); err != nil {
+			return nil, err
+		}
+		if key.RetiresAt, err = parseSQLiteTime(retiresAt); err != nil {
+			return nil, err
+		}
+		if key.ExpiresAt, err = parseSQLiteTime(expiresAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+	return keys, rows.Err()
+}
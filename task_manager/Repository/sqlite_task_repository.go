@@ -0,0 +1,547 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteTaskRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteTaskRepository(db *sql.DB) domain.TaskRepository {
+	return &sqliteTaskRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const sqliteTaskColumns = `id, user_id, title, description, due_date, status, started_at, completed_at, parent_id, checklist, recurrence`
+
+// sqliteChecklistItem is how a checklist item is stored in the checklist
+// JSON column.
+type sqliteChecklistItem struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// sqliteRecurrence is how a recurrence is stored in the recurrence JSON
+// column, with times in the sortable SQLite layout.
+type sqliteRecurrence struct {
+	Rule       string `json:"rule"`
+	TimeZone   string `json:"time_zone"`
+	SeriesID   string `json:"series_id"`
+	Start      string `json:"start"`
+	Occurrence string `json:"occurrence"`
+}
+
+// taskSQLColumns maps domain sort fields to task table columns.
+var taskSQLColumns = map[string]string{
+	domain.TaskSortID:          "id",
+	domain.TaskSortUserID:      "user_id",
+	domain.TaskSortTitle:       "title",
+	domain.TaskSortDescription: "description",
+	domain.TaskSortDueDate:     "due_date",
+	domain.TaskSortStatus:      "status",
+}
+
+func (tr *sqliteTaskRepository) GetAllTasks(c context.Context, query domain.TaskQuery) (*domain.TaskPage, error) {
+	query = withDefaultSort(query)
+	column, ok := taskSQLColumns[query.SortBy]
+	if !ok {
+		return nil, domain.ErrInvalidTaskQuery
+	}
+	cursor, err := decodeTaskCursor(query)
+	if err != nil {
+		return nil, err
+	}
+
+	where := []string{"user_id = ?"}
+	args := []any{query.UserID}
+	if query.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, query.Status)
+	}
+	if !query.DueFrom.IsZero() {
+		where = append(where, "due_date >= ?")
+		args = append(args, formatSQLiteTime(query.DueFrom))
+	}
+	if !query.DueTo.IsZero() {
+		where = append(where, "due_date < ?")
+		args = append(args, formatSQLiteTime(query.DueTo))
+	}
+	if len(query.Labels) > 0 {
+		labels := slices.Compact(slices.Sorted(slices.Values(query.Labels)))
+		placeholders, labelArgs := sqliteInList(labels)
+		match := `id IN (SELECT task_id FROM task_labels WHERE label_id IN (` + placeholders + `)`
+		args = append(args, labelArgs...)
+		if query.MatchAllLabels {
+			match += ` GROUP BY task_id HAVING COUNT(*) = ?`
+			args = append(args, len(labels))
+		}
+		where = append(where, match+`)`)
+	}
+
+	direction, op := "ASC", ">"
+	if query.SortDesc {
+		direction, op = "DESC", "<"
+	}
+	if cursor != nil {
+		value := cursor.Value
+		if query.SortBy == domain.TaskSortDueDate {
+			at, _ := time.Parse(time.RFC3339Nano, cursor.Value)
+			value = formatSQLiteTime(at)
+		}
+		where = append(where, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, op))
+		args = append(args, value, value, cursor.ID)
+	}
+
+	stmt := `SELECT ` + sqliteTaskColumns + ` FROM tasks WHERE ` + strings.Join(where, " AND ") +
+		fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+	if query.Limit > 0 {
+		stmt += " LIMIT ?"
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := tr.db.QueryContext(c, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*domain.Task
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := tr.loadLabels(c, tasks); err != nil {
+		return nil, err
+	}
+
+	return newTaskPage(tasks, query), nil
+}
+
+func (tr *sqliteTaskRepository) GetTaskByID(c context.Context, id string) (*domain.Task, error) {
+	row := tr.db.QueryRowContext(c, `SELECT `+sqliteTaskColumns+` FROM tasks WHERE id = ?`, id)
+
+	task, err := scanSQLiteTask(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+	if err := tr.loadLabels(c, []*domain.Task{task}); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (tr *sqliteTaskRepository) CreateTask(c context.Context, task *domain.Task) error {
+	checklist, err := marshalChecklist(task.Checklist)
+	if err != nil {
+		return err
+	}
+	recurrence, err := marshalRecurrence(task.Recurrence)
+	if err != nil {
+		return err
+	}
+	_, err = tr.db.ExecContext(c,
+		`INSERT INTO tasks (`+sqliteTaskColumns+`, series_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		task.ID, task.UserID, task.Title, task.Description, formatSQLiteTime(task.DueDate), task.Status,
+		nullableSQLiteTime(task.StartedAt), nullableSQLiteTime(task.CompletedAt), task.ParentID, checklist,
+		recurrence, seriesID(task.Recurrence))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrTaskAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (tr *sqliteTaskRepository) UpdateTask(c context.Context, id string, task *domain.Task) (*domain.Task, error) {
+	result, err := tr.db.ExecContext(c,
+		`UPDATE tasks SET user_id = ?, title = ?, description = ?, due_date = ?, status = ?, started_at = ?, completed_at = ? WHERE id = ?`,
+		task.UserID, task.Title, task.Description, formatSQLiteTime(task.DueDate), task.Status,
+		nullableSQLiteTime(task.StartedAt), nullableSQLiteTime(task.CompletedAt), id)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	return task, nil
+}
+
+func (tr *sqliteTaskRepository) DeleteTask(c context.Context, id string) error {
+	tx, err := tr.db.BeginTx(c, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(c, `DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrTaskNotFound
+	}
+	if _, err := tx.ExecContext(c, `DELETE FROM task_labels WHERE task_id = ?`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSQLiteTask(row rowScanner) (*domain.Task, error) {
+	var task domain.Task
+	var dueDate string
+	var startedAt, completedAt sql.NullString
+	var checklist string
+	var recurrence sql.NullString
+	if err := row.Scan(&task.ID, &task.UserID, &task.Title, &task.Description, &dueDate, &task.Status,
+		&startedAt, &completedAt, &task.ParentID, &checklist, &recurrence); err != nil {
+		return nil, err
+	}
+	var items []sqliteChecklistItem
+	if err := json.Unmarshal([]byte(checklist), &items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		task.Checklist = append(task.Checklist, domain.ChecklistItem{ID: item.ID, Text: item.Text, Done: item.Done})
+	}
+	var err error
+	if task.Recurrence, err = unmarshalRecurrence(recurrence); err != nil {
+		return nil, err
+	}
+	if task.DueDate, err = parseSQLiteTime(dueDate); err != nil {
+		return nil, err
+	}
+	if task.StartedAt, err = parseNullableSQLiteTime(startedAt); err != nil {
+		return nil, err
+	}
+	if task.CompletedAt, err = parseNullableSQLiteTime(completedAt); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (tr *sqliteTaskRepository) DeleteTasksByUser(c context.Context, userId string) error {
+	tx, err := tr.db.BeginTx(c, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(c,
+		`DELETE FROM task_labels WHERE task_id IN (SELECT id FROM tasks WHERE user_id = ?)`, userId); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(c, `DELETE FROM tasks WHERE user_id = ?`, userId); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (tr *sqliteTaskRepository) AddTaskLabel(c context.Context, taskId string, labelId string) error {
+	result, err := tr.db.ExecContext(c, `INSERT INTO task_labels (task_id, label_id)
+		SELECT id, ? FROM tasks WHERE id = ? ON CONFLICT DO NOTHING`, labelId, taskId)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return tr.ensureTaskExists(c, taskId)
+	}
+	return nil
+}
+
+func (tr *sqliteTaskRepository) RemoveTaskLabel(c context.Context, taskId string, labelId string) error {
+	result, err := tr.db.ExecContext(c, `DELETE FROM task_labels WHERE task_id = ? AND label_id = ?`, taskId, labelId)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return tr.ensureTaskExists(c, taskId)
+	}
+	return nil
+}
+
+func (tr *sqliteTaskRepository) RemoveLabelFromTasks(c context.Context, userId string, labelId string) error {
+	_, err := tr.db.ExecContext(c, `DELETE FROM task_labels
+		WHERE label_id = ? AND task_id IN (SELECT id FROM tasks WHERE user_id = ?)`, labelId, userId)
+	return err
+}
+
+func (tr *sqliteTaskRepository) GetSubtasks(c context.Context, parentId string) ([]*domain.Task, error) {
+	rows, err := tr.db.QueryContext(c, `SELECT `+sqliteTaskColumns+` FROM tasks WHERE parent_id = ? ORDER BY due_date, id`, parentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subtasks []*domain.Task
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		subtasks = append(subtasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := tr.loadLabels(c, subtasks); err != nil {
+		return nil, err
+	}
+	return subtasks, nil
+}
+
+func (tr *sqliteTaskRepository) CountSubtasksByStatus(c context.Context, parentIds []string) (map[string]map[string]int, error) {
+	counts := make(map[string]map[string]int)
+	if len(parentIds) == 0 {
+		return counts, nil
+	}
+	placeholders, args := sqliteInList(parentIds)
+	rows, err := tr.db.QueryContext(c, `SELECT parent_id, status, COUNT(*) FROM tasks
+		WHERE parent_id IN (`+placeholders+`) GROUP BY parent_id, status`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentId, status string
+		var count int
+		if err := rows.Scan(&parentId, &status, &count); err != nil {
+			return nil, err
+		}
+		if counts[parentId] == nil {
+			counts[parentId] = make(map[string]int)
+		}
+		counts[parentId][status] = count
+	}
+	return counts, rows.Err()
+}
+
+func (tr *sqliteTaskRepository) DetachSubtasks(c context.Context, parentId string) error {
+	_, err := tr.db.ExecContext(c, `UPDATE tasks SET parent_id = '' WHERE parent_id = ?`, parentId)
+	return err
+}
+
+func (tr *sqliteTaskRepository) SetTaskChecklist(c context.Context, taskId string, checklist []domain.ChecklistItem) error {
+	encoded, err := marshalChecklist(checklist)
+	if err != nil {
+		return err
+	}
+	result, err := tr.db.ExecContext(c, `UPDATE tasks SET checklist = ? WHERE id = ?`, encoded, taskId)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// marshalChecklist stores an empty checklist as "[]" rather than "null".
+func marshalChecklist(checklist []domain.ChecklistItem) (string, error) {
+	items := make([]sqliteChecklistItem, 0, len(checklist))
+	for _, item := range checklist {
+		items = append(items, sqliteChecklistItem{ID: item.ID, Text: item.Text, Done: item.Done})
+	}
+	encoded, err := json.Marshal(items)
+	return string(encoded), err
+}
+
+func (tr *sqliteTaskRepository) GetTasksBySeries(c context.Context, seriesId string) ([]*domain.Task, error) {
+	rows, err := tr.db.QueryContext(c, `SELECT `+sqliteTaskColumns+` FROM tasks WHERE series_id = ?
+		ORDER BY json_extract(recurrence, '$.occurrence'), id`, seriesId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []*domain.Task
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := tr.loadLabels(c, series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (tr *sqliteTaskRepository) GetTasksDueBetween(c context.Context, from time.Time, to time.Time) ([]*domain.Task, error) {
+	rows, err := tr.db.QueryContext(c, `SELECT `+sqliteTaskColumns+` FROM tasks WHERE due_date >= ? AND due_date < ?
+		ORDER BY due_date, id`, formatSQLiteTime(from), formatSQLiteTime(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []*domain.Task
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := tr.loadLabels(c, due); err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+func (tr *sqliteTaskRepository) SetTaskRecurrence(c context.Context, taskId string, recurrence *domain.Recurrence) error {
+	encoded, err := marshalRecurrence(recurrence)
+	if err != nil {
+		return err
+	}
+	result, err := tr.db.ExecContext(c, `UPDATE tasks SET recurrence = ?, series_id = ? WHERE id = ?`,
+		encoded, seriesID(recurrence), taskId)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// marshalRecurrence stores a missing recurrence as NULL.
+func marshalRecurrence(recurrence *domain.Recurrence) (sql.NullString, error) {
+	if recurrence == nil {
+		return sql.NullString{}, nil
+	}
+	encoded, err := json.Marshal(sqliteRecurrence{
+		Rule:       recurrence.Rule,
+		TimeZone:   recurrence.TimeZone,
+		SeriesID:   recurrence.SeriesID,
+		Start:      formatSQLiteTime(recurrence.Start),
+		Occurrence: formatSQLiteTime(recurrence.Occurrence),
+	})
+	return sql.NullString{String: string(encoded), Valid: true}, err
+}
+
+func unmarshalRecurrence(encoded sql.NullString) (*domain.Recurrence, error) {
+	if !encoded.Valid {
+		return nil, nil
+	}
+	var stored sqliteRecurrence
+	if err := json.Unmarshal([]byte(encoded.String), &stored); err != nil {
+		return nil, err
+	}
+	recurrence := &domain.Recurrence{Rule: stored.Rule, TimeZone: stored.TimeZone, SeriesID: stored.SeriesID}
+	var err error
+	if recurrence.Start, err = parseSQLiteTime(stored.Start); err != nil {
+		return nil, err
+	}
+	if recurrence.Occurrence, err = parseSQLiteTime(stored.Occurrence); err != nil {
+		return nil, err
+	}
+	return recurrence, nil
+}
+
+func seriesID(recurrence *domain.Recurrence) string {
+	if recurrence == nil {
+		return ""
+	}
+	return recurrence.SeriesID
+}
+
+// ensureTaskExists tells a label change that had nothing to do apart from
+// one made on a task that does not exist.
+func (tr *sqliteTaskRepository) ensureTaskExists(c context.Context, taskId string) error {
+	var exists bool
+	err := tr.db.QueryRowContext(c, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ?)`, taskId).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// loadLabels fills in the LabelIDs of tasks with one query.
+func (tr *sqliteTaskRepository) loadLabels(c context.Context, tasks []*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	byID := make(map[string]*domain.Task, len(tasks))
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		byID[task.ID] = task
+		ids[i] = task.ID
+	}
+	placeholders, args := sqliteInList(ids)
+	rows, err := tr.db.QueryContext(c, `SELECT task_id, label_id FROM task_labels
+		WHERE task_id IN (`+placeholders+`) ORDER BY rowid`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskId, labelId string
+		if err := rows.Scan(&taskId, &labelId); err != nil {
+			return err
+		}
+		task := byID[taskId]
+		task.LabelIDs = append(task.LabelIDs, labelId)
+	}
+	return rows.Err()
+}
+
+// sqliteInList returns the placeholders and arguments for an IN (...) list.
+func sqliteInList(values []string) (string, []any) {
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", "), args
+}
@@ -0,0 +1,127 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type sqliteTaskRepositoryTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	taskRepo domain.TaskRepository
+	ctx      context.Context
+}
+
+func TestSQLiteTaskRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(sqliteTaskRepositoryTestSuite))
+}
+
+func (s *sqliteTaskRepositoryTestSuite) SetupTest() {
+	db, err := repository.OpenSQLite(filepath.Join(s.T().TempDir(), "tasks.db"))
+	s.Require().NoError(err)
+
+	s.db = db
+	s.taskRepo = repository.NewSQLiteTaskRepository(db)
+	s.ctx = context.Background()
+}
+
+func (s *sqliteTaskRepositoryTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *sqliteTaskRepositoryTestSuite) TestCreateAndGetTaskByID() {
+	assert := assert.New(s.T())
+
+	task := &domain.Task{
+		ID:          "task-1",
+		UserID:      "user-1",
+		Title:       "Test Task",
+		Description: "This is a test",
+		DueDate:     time.Date(2025, 8, 1, 12, 30, 0, 0, time.UTC),
+		Status:      "pending",
+	}
+	assert.NoError(s.taskRepo.CreateTask(s.ctx, task))
+
+	found, err := s.taskRepo.GetTaskByID(s.ctx, "task-1")
+	assert.NoError(err)
+	assert.Equal(task, found)
+
+	assert.ErrorIs(s.taskRepo.CreateTask(s.ctx, task), domain.ErrTaskAlreadyExists)
+}
+
+func (s *sqliteTaskRepositoryTestSuite) TestGetAllTasks() {
+	assert := assert.New(s.T())
+
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "1", UserID: "user-1", Title: "Task 1"})
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "2", UserID: "user-2", Title: "Task 2"})
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "3", UserID: "user-1", Title: "Task 3"})
+
+	page, err := s.taskRepo.GetAllTasks(s.ctx, domain.TaskQuery{UserID: "user-1"})
+	assert.NoError(err)
+	assert.Len(page.Tasks, 2)
+	assert.Equal("1", page.Tasks[0].ID)
+	assert.Equal("3", page.Tasks[1].ID)
+}
+
+func (s *sqliteTaskRepositoryTestSuite) TestGetAllTasks_Query() {
+	testTaskQueries(s.T(), s.taskRepo)
+}
+
+func (s *sqliteTaskRepositoryTestSuite) TestUpdateTask() {
+	assert := assert.New(s.T())
+
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "task-update", UserID: "user-x", Title: "Old"})
+
+	startedAt := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	updated, err := s.taskRepo.UpdateTask(s.ctx, "task-update", &domain.Task{UserID: "user-x", Title: "Updated", StartedAt: &startedAt})
+	assert.NoError(err)
+	assert.Equal("Updated", updated.Title)
+
+	found, err := s.taskRepo.GetTaskByID(s.ctx, "task-update")
+	assert.NoError(err)
+	assert.Equal("Updated", found.Title)
+	assert.Equal(&startedAt, found.StartedAt)
+	assert.Nil(found.CompletedAt)
+
+	_, err = s.taskRepo.UpdateTask(s.ctx, "missing", &domain.Task{Title: "x"})
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+}
+
+func (s *sqliteTaskRepositoryTestSuite) TestDeleteTask() {
+	assert := assert.New(s.T())
+
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "delete-me", UserID: "user-x"})
+
+	assert.NoError(s.taskRepo.DeleteTask(s.ctx, "delete-me"))
+
+	result, err := s.taskRepo.GetTaskByID(s.ctx, "delete-me")
+	assert.Nil(result)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+	assert.ErrorIs(s.taskRepo.DeleteTask(s.ctx, "delete-me"), domain.ErrTaskNotFound)
+}
+
+func (s *sqliteTaskRepositoryTestSuite) TestDeleteTasksByUser() {
+	assert := assert.New(s.T())
+
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "gone-1", UserID: "leaver", Title: "One"})
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "gone-2", UserID: "leaver", Title: "Two"})
+	_ = s.taskRepo.CreateTask(s.ctx, &domain.Task{ID: "kept", UserID: "stayer", Title: "Three"})
+
+	assert.NoError(s.taskRepo.DeleteTasksByUser(s.ctx, "leaver"))
+	assert.NoError(s.taskRepo.DeleteTasksByUser(s.ctx, "leaver"), "deleting nothing is not an error")
+
+	page, err := s.taskRepo.GetAllTasks(s.ctx, domain.TaskQuery{UserID: "leaver"})
+	assert.NoError(err)
+	assert.Empty(page.Tasks)
+	_, err = s.taskRepo.GetTaskByID(s.ctx, "kept")
+	assert.NoError(err)
+}
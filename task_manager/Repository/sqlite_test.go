@@ -0,0 +1,55 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSQLite_MigratesOnceAndReopens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	db, err := repository.OpenSQLite(path)
+	require.NoError(t, err)
+
+	var version, applied int
+	require.NoError(t, db.QueryRow(`SELECT MAX(version), COUNT(*) FROM schema_migrations`).Scan(&version, &applied))
+	assert.Equal(t, 20, version)
+	assert.Equal(t, 20, applied)
+
+	var indexes int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'tasks_user_id_idx'`).Scan(&indexes))
+	assert.Equal(t, 1, indexes)
+	require.NoError(t, db.Close())
+
+	// Re-opening an up-to-date database must not re-run any migration.
+	db, err = repository.OpenSQLite(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied))
+	assert.Equal(t, 20, applied)
+}
+
+func TestOpenSQLite_PragmasApplyToEveryConnection(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	// Without idle connections, every query below runs on a new one.
+	db.SetMaxIdleConns(0)
+
+	for i := 0; i < 2; i++ {
+		var foreignKeys, busyTimeout int
+		var journalMode string
+		require.NoError(t, db.QueryRow(`PRAGMA foreign_keys`).Scan(&foreignKeys))
+		require.NoError(t, db.QueryRow(`PRAGMA busy_timeout`).Scan(&busyTimeout))
+		require.NoError(t, db.QueryRow(`PRAGMA journal_mode`).Scan(&journalMode))
+		assert.Equal(t, 1, foreignKeys)
+		assert.Equal(t, 5000, busyTimeout)
+		assert.Equal(t, "wal", journalMode)
+	}
+}
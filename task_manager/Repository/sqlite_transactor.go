@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteTxKey struct{}
+
+func sqliteTxFrom(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(sqliteTxKey{}).(*sql.Tx)
+	return tx
+}
+
+type sqliteTransactor struct {
+	db *sql.DB
+}
+
+// NewSQLiteTransactor runs transactions on db. The SQLite repositories find
+// the transaction in the context they are called with and run their
+// statements in it.
+func NewSQLiteTransactor(db *sql.DB) domain.Transactor {
+	return &sqliteTransactor{
+		db: db,
+	}
+}
+
+func (st *sqliteTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if sqliteTxFrom(ctx) != nil {
+		return fn(ctx)
+	}
+	tx, err := st.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, sqliteTxKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqliteDB is the database as the SQLite repositories see it: statements
+// run in the transaction of the context when there is one. With a single
+// connection, a statement run outside it would wait for it forever.
+type sqliteDB struct {
+	*sql.DB
+}
+
+func (db sqliteDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if tx := sqliteTxFrom(ctx); tx != nil {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+func (db sqliteDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if tx := sqliteTxFrom(ctx); tx != nil {
+		return tx.QueryContext(ctx, query, args...)
+	}
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+func (db sqliteDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if tx := sqliteTxFrom(ctx); tx != nil {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx starts a transaction, or carries on the one of the context:
+// committing or rolling that one back is left to whoever started it.
+func (db sqliteDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sqliteTx, error) {
+	if tx := sqliteTxFrom(ctx); tx != nil {
+		return &sqliteTx{Tx: tx, joined: true}, nil
+	}
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteTx{Tx: tx}, nil
+}
+
+type sqliteTx struct {
+	*sql.Tx
+	joined bool
+}
+
+func (tx *sqliteTx) Commit() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+func (tx *sqliteTx) Rollback() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteTwoFactorRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteTwoFactorRepository(db *sql.DB) domain.TwoFactorRepository {
+	return &sqliteTwoFactorRepository{
+		db: sqliteDB{db},
+	}
+}
+
+func (tr *sqliteTwoFactorRepository) GetTwoFactor(c context.Context, userId string) (*domain.TwoFactor, error) {
+	row := tr.db.QueryRowContext(c, `SELECT user_id, secret, enabled, recovery_codes, last_used_step, created_at, enabled_at
+		FROM two_factor WHERE user_id = ?`, userId)
+
+	var twoFactor domain.TwoFactor
+	var codes, createdAt string
+	var enabledAt sql.NullString
+	err := row.Scan(&twoFactor.UserID, &twoFactor.Secret, &twoFactor.Enabled, &codes, &twoFactor.LastUsedStep, &createdAt, &enabledAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(codes), &twoFactor.RecoveryCodes); err != nil {
+		return nil, err
+	}
+	if twoFactor.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	if twoFactor.EnabledAt, err = parseNullableSQLiteTime(enabledAt); err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+func (tr *sqliteTwoFactorRepository) SaveTwoFactor(c context.Context, twoFactor *domain.TwoFactor) error {
+	codes, err := marshalRecoveryCodes(twoFactor.RecoveryCodes)
+	if err != nil {
+		return err
+	}
+	_, err = tr.db.ExecContext(c, `INSERT OR REPLACE INTO two_factor
+		(user_id, secret, enabled, recovery_codes, last_used_step, created_at, enabled_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		twoFactor.UserID, twoFactor.Secret, twoFactor.Enabled, codes, twoFactor.LastUsedStep,
+		formatSQLiteTime(twoFactor.CreatedAt), nullableSQLiteTime(twoFactor.EnabledAt))
+	return err
+}
+
+func (tr *sqliteTwoFactorRepository) DeleteTwoFactor(c context.Context, userId string) error {
+	_, err := tr.db.ExecContext(c, `DELETE FROM two_factor WHERE user_id = ?`, userId)
+	return err
+}
+
+func (tr *sqliteTwoFactorRepository) UseTOTPStep(c context.Context, userId string, step int64) (bool, error) {
+	result, err := tr.db.ExecContext(c, `UPDATE two_factor SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?`, step, userId, step)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (tr *sqliteTwoFactorRepository) UseRecoveryCode(c context.Context, userId string, codeHash string) (bool, error) {
+	tx, err := tr.db.BeginTx(c, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var stored string
+	err = tx.QueryRowContext(c, `SELECT recovery_codes FROM two_factor WHERE user_id = ?`, userId).Scan(&stored)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	var codes []string
+	if err := json.Unmarshal([]byte(stored), &codes); err != nil {
+		return false, err
+	}
+	i := slices.Index(codes, codeHash)
+	if i < 0 {
+		return false, nil
+	}
+	remaining, err := marshalRecoveryCodes(slices.Delete(codes, i, i+1))
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(c, `UPDATE two_factor SET recovery_codes = ? WHERE user_id = ?`, remaining, userId); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// marshalRecoveryCodes stores no codes as "[]" rather than "null".
+func marshalRecoveryCodes(codes []string) (string, error) {
+	if codes == nil {
+		codes = []string{}
+	}
+	b, err := json.Marshal(codes)
+	return string(b), err
+}
+
+type sqliteTwoFactorRoleRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteTwoFactorRoleRepository(db *sql.DB) domain.TwoFactorRoleRepository {
+	return &sqliteTwoFactorRoleRepository{
+		db: sqliteDB{db},
+	}
+}
+
+func (rr *sqliteTwoFactorRoleRepository) GetTwoFactorRoles(c context.Context) ([]string, error) {
+	rows, err := rr.db.QueryContext(c, `SELECT role FROM two_factor_roles ORDER BY role`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (rr *sqliteTwoFactorRoleRepository) SetTwoFactorRequired(c context.Context, role string, required bool) error {
+	var err error
+	if required {
+		_, err = rr.db.ExecContext(c, `INSERT OR IGNORE INTO two_factor_roles (role) VALUES (?)`, role)
+	} else {
+		_, err = rr.db.ExecContext(c, `DELETE FROM two_factor_roles WHERE role = ?`, role)
+	}
+	return err
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteUserRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteUserRepository(db *sql.DB) domain.UserRepository {
+	return &sqliteUserRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const sqliteUserColumns = `id, username, email, password, role, token_version, disabled, email_unverified`
+
+func (ur *sqliteUserRepository) GetAllUsers(c context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	after, err := decodeUserCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	where := []string{"1 = 1"}
+	var args []any
+	if after != "" {
+		where = append(where, "username > ?")
+		args = append(args, after)
+	}
+	if query.Search != "" {
+		// LIKE is case-insensitive for ASCII; escape its wildcards so the
+		// search is a plain substring match.
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query.Search) + "%"
+		where = append(where, `(username LIKE ? ESCAPE '\' OR email LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+	stmt := `SELECT ` + sqliteUserColumns + ` FROM users WHERE ` + strings.Join(where, " AND ") + ` ORDER BY username`
+	if query.Limit > 0 {
+		stmt += " LIMIT ?"
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := ur.db.QueryContext(c, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		u, err := scanSQLiteUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return newUserPage(users, query.Limit), nil
+}
+
+func (ur *sqliteUserRepository) GetUserByID(c context.Context, id string) (*domain.User, error) {
+	return ur.getUserBy(c, "id", id)
+}
+
+func (ur *sqliteUserRepository) GetUserByEmail(c context.Context, email string) (*domain.User, error) {
+	return ur.getUserBy(c, "email", email)
+}
+
+func (ur *sqliteUserRepository) GetUserByUsername(c context.Context, username string) (*domain.User, error) {
+	return ur.getUserBy(c, "username", username)
+}
+
+func (ur *sqliteUserRepository) CreateUser(c context.Context, user *domain.User) (*domain.User, error) {
+	_, err := ur.db.ExecContext(c,
+		`INSERT INTO users (`+sqliteUserColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Username, user.Email, user.Password, user.Role, user.TokenVersion, user.Disabled, user.EmailUnverified)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrUserAlreadyExists
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (ur *sqliteUserRepository) PromoteUserToAdmin(c context.Context, id string) error {
+	return ur.UpdateUserRole(c, id, domain.RoleAdmin)
+}
+
+// sqliteAdminRemains matches the rows of users whose admin rights can go:
+// those that are not active admins, and any while another active admin
+// exists. Its two parameters are the admin role. Being part of the
+// statement that changes the row, the check cannot be raced.
+const sqliteAdminRemains = `(role != ? OR disabled != 0 OR EXISTS (
+	SELECT 1 FROM users AS other WHERE other.role = ? AND other.disabled = 0 AND other.id != users.id))`
+
+func (ur *sqliteUserRepository) UpdateUserRole(c context.Context, id string, role string) error {
+	result, err := ur.db.ExecContext(c, `UPDATE users SET role = ?, token_version = token_version + 1
+		WHERE id = ? AND (? = ? OR `+sqliteAdminRemains+`)`,
+		role, id, role, domain.RoleAdmin, domain.RoleAdmin, domain.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	return ur.changedOrLastAdmin(c, result, id)
+}
+
+func (ur *sqliteUserRepository) SetUserDisabled(c context.Context, id string, disabled bool) error {
+	result, err := ur.db.ExecContext(c, `UPDATE users SET disabled = ?, token_version = token_version + 1
+		WHERE id = ? AND (? = 0 OR `+sqliteAdminRemains+`)`,
+		disabled, id, disabled, domain.RoleAdmin, domain.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	return ur.changedOrLastAdmin(c, result, id)
+}
+
+// changedOrLastAdmin tells why a statement guarded by sqliteAdminRemains
+// changed no row: the user is missing, or is the last active admin.
+func (ur *sqliteUserRepository) changedOrLastAdmin(c context.Context, result sql.Result, id string) error {
+	if n, err := result.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	var exists bool
+	if err := ur.db.QueryRowContext(c, `SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrUserNotFound
+	}
+	return domain.ErrLastAdmin
+}
+
+func (ur *sqliteUserRepository) MarkEmailVerified(c context.Context, id string) error {
+	result, err := ur.db.ExecContext(c, `UPDATE users SET email_unverified = 0 WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *sqliteUserRepository) UpdateUserPassword(c context.Context, id string, passwordHash string) error {
+	result, err := ur.db.ExecContext(c, `UPDATE users SET password = ?, token_version = token_version + 1 WHERE id = ?`, passwordHash, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *sqliteUserRepository) DeleteUser(c context.Context, id string) error {
+	result, err := ur.db.ExecContext(c, `DELETE FROM users WHERE id = ? AND `+sqliteAdminRemains, id, domain.RoleAdmin, domain.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	return ur.changedOrLastAdmin(c, result, id)
+}
+
+func (ur *sqliteUserRepository) CountActiveAdmins(c context.Context) (int, error) {
+	var n int
+	err := ur.db.QueryRowContext(c, `SELECT COUNT(*) FROM users WHERE role = ? AND disabled = 0`, domain.RoleAdmin).Scan(&n)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (ur *sqliteUserRepository) GetUsersByRole(c context.Context, role string) ([]*domain.User, error) {
+	rows, err := ur.db.QueryContext(c, `SELECT `+sqliteUserColumns+` FROM users WHERE role = ? ORDER BY username`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		u, err := scanSQLiteUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (ur *sqliteUserRepository) BumpTokenVersion(c context.Context, id string) error {
+	result, err := ur.db.ExecContext(c, `UPDATE users SET token_version = token_version + 1 WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *sqliteUserRepository) UserExists(c context.Context) (bool, error) {
+	var exists bool
+	err := ur.db.QueryRowContext(c, `SELECT EXISTS (SELECT 1 FROM users)`).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// getUserBy looks a user up by one of the uniquely indexed columns. column
+// is always a literal from this file, never user input.
+func (ur *sqliteUserRepository) getUserBy(c context.Context, column, value string) (*domain.User, error) {
+	u, err := scanSQLiteUser(ur.db.QueryRowContext(c, `SELECT `+sqliteUserColumns+` FROM users WHERE `+column+` = ?`, value))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func scanSQLiteUser(row rowScanner) (*domain.User, error) {
+	var u domain.User
+	if err := row.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.Role, &u.TokenVersion, &u.Disabled, &u.EmailUnverified); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,133 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type sqliteUserRepositoryTestSuite struct {
+	suite.Suite
+	db   *sql.DB
+	repo domain.UserRepository
+	ctx  context.Context
+}
+
+func TestSQLiteUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(sqliteUserRepositoryTestSuite))
+}
+
+func (s *sqliteUserRepositoryTestSuite) SetupTest() {
+	db, err := repository.OpenSQLite(filepath.Join(s.T().TempDir(), "users.db"))
+	s.Require().NoError(err)
+
+	s.db = db
+	s.repo = repository.NewSQLiteUserRepository(db)
+	s.ctx = context.Background()
+}
+
+func (s *sqliteUserRepositoryTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *sqliteUserRepositoryTestSuite) TestCreateAndGetUser() {
+	assert := assert.New(s.T())
+	user := &domain.User{
+		ID:       "user-123",
+		Username: "johndoe",
+		Email:    "john@example.com",
+		Password: "hashed-password",
+		Role:     "user",
+	}
+
+	created, err := s.repo.CreateUser(s.ctx, user)
+	assert.NoError(err)
+	assert.Equal("johndoe", created.Username)
+
+	found, err := s.repo.GetUserByID(s.ctx, "user-123")
+	assert.NoError(err)
+	assert.Equal(user, found)
+
+	foundByEmail, err := s.repo.GetUserByEmail(s.ctx, "john@example.com")
+	assert.NoError(err)
+	assert.Equal("johndoe", foundByEmail.Username)
+
+	foundByUsername, err := s.repo.GetUserByUsername(s.ctx, "johndoe")
+	assert.NoError(err)
+	assert.Equal("john@example.com", foundByUsername.Email)
+
+	_, err = s.repo.GetUserByEmail(s.ctx, "missing@example.com")
+	assert.ErrorIs(err, domain.ErrUserNotFound)
+}
+
+func (s *sqliteUserRepositoryTestSuite) TestCreateUser_UniqueEmailAndUsername() {
+	assert := assert.New(s.T())
+	_, err := s.repo.CreateUser(s.ctx, &domain.User{ID: "1", Username: "a", Email: "a@a.com", Password: "pw", Role: "user"})
+	s.Require().NoError(err)
+
+	_, err = s.repo.CreateUser(s.ctx, &domain.User{ID: "2", Username: "b", Email: "a@a.com", Password: "pw", Role: "user"})
+	assert.ErrorIs(err, domain.ErrUserAlreadyExists)
+
+	_, err = s.repo.CreateUser(s.ctx, &domain.User{ID: "3", Username: "a", Email: "c@c.com", Password: "pw", Role: "user"})
+	assert.ErrorIs(err, domain.ErrUserAlreadyExists)
+}
+
+func (s *sqliteUserRepositoryTestSuite) TestPromoteUserToAdmin() {
+	_, err := s.repo.CreateUser(s.ctx, &domain.User{ID: "user-456", Username: "janedoe", Email: "jane@example.com", Password: "pw", Role: "user"})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.PromoteUserToAdmin(s.ctx, "user-456"))
+
+	updated, err := s.repo.GetUserByID(s.ctx, "user-456")
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "admin", updated.Role)
+	assert.Equal(s.T(), 1, updated.TokenVersion, "promotion must invalidate existing tokens")
+
+	assert.ErrorIs(s.T(), s.repo.PromoteUserToAdmin(s.ctx, "missing"), domain.ErrUserNotFound)
+}
+
+func (s *sqliteUserRepositoryTestSuite) TestUserExistsAndGetAllUsers() {
+	exists, err := s.repo.UserExists(s.ctx)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), exists)
+
+	s.repo.CreateUser(s.ctx, &domain.User{ID: "1", Username: "a", Email: "a@a.com", Password: "pw", Role: "user"})
+	s.repo.CreateUser(s.ctx, &domain.User{ID: "2", Username: "b", Email: "b@b.com", Password: "pw", Role: "admin"})
+
+	exists, err = s.repo.UserExists(s.ctx)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), exists)
+
+	page, err := s.repo.GetAllUsers(s.ctx, domain.UserQuery{})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Users, 2)
+}
+
+func (s *sqliteUserRepositoryTestSuite) TestUpdateUserRole() {
+	_, err := s.repo.CreateUser(s.ctx, &domain.User{ID: "user-789", Username: "sam", Email: "sam@example.com", Password: "pw", Role: domain.RoleUser})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.repo.UpdateUserRole(s.ctx, "user-789", "editor"))
+
+	updated, err := s.repo.GetUserByID(s.ctx, "user-789")
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "editor", updated.Role)
+	assert.Equal(s.T(), 1, updated.TokenVersion)
+
+	assert.ErrorIs(s.T(), s.repo.UpdateUserRole(s.ctx, "missing", "editor"), domain.ErrUserNotFound)
+}
+
+func (s *sqliteUserRepositoryTestSuite) TestUserManagement() {
+	testUserManagement(s.T(), s.repo)
+}
+
+func (s *sqliteUserRepositoryTestSuite) TestLastAdmin() {
+	testLastAdmin(s.T(), s.repo, repository.NewSQLiteTransactor(s.db))
+}
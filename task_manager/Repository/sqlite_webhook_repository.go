@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type sqliteWebhookRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteWebhookRepository(db *sql.DB) domain.WebhookRepository {
+	return &sqliteWebhookRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const webhookColumns = `id, url, events, secret, active, created_by, created_at, updated_at`
+
+func encodeWebhookEvents(events []string) (string, error) {
+	if events == nil {
+		events = []string{}
+	}
+	encoded, err := json.Marshal(events)
+	return string(encoded), err
+}
+
+func (wr *sqliteWebhookRepository) CreateWebhook(c context.Context, webhook *domain.Webhook) error {
+	events, err := encodeWebhookEvents(webhook.Events)
+	if err != nil {
+		return err
+	}
+	_, err = wr.db.ExecContext(c, `INSERT INTO webhooks (`+webhookColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		webhook.ID, webhook.URL, events, webhook.Secret, webhook.Active, webhook.CreatedBy,
+		formatSQLiteTime(webhook.CreatedAt), formatSQLiteTime(webhook.UpdatedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrWebhookAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (wr *sqliteWebhookRepository) GetWebhookByID(c context.Context, webhookId string) (*domain.Webhook, error) {
+	row := wr.db.QueryRowContext(c, `SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`, webhookId)
+	webhook, err := scanSQLiteWebhook(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (wr *sqliteWebhookRepository) GetWebhooks(c context.Context) ([]*domain.Webhook, error) {
+	return wr.find(c, `SELECT `+webhookColumns+` FROM webhooks ORDER BY created_at, id`)
+}
+
+func (wr *sqliteWebhookRepository) GetWebhooksForEvent(c context.Context, eventType string) ([]*domain.Webhook, error) {
+	return wr.find(c, `SELECT `+webhookColumns+` FROM webhooks
+		WHERE active = 1 AND EXISTS (SELECT 1 FROM json_each(webhooks.events) WHERE value IN (?, ?))
+		ORDER BY created_at, id`, eventType, domain.WebhookAllEvents)
+}
+
+func (wr *sqliteWebhookRepository) find(c context.Context, query string, args ...any) ([]*domain.Webhook, error) {
+	rows, err := wr.db.QueryContext(c, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*domain.Webhook
+	for rows.Next() {
+		webhook, err := scanSQLiteWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (wr *sqliteWebhookRepository) UpdateWebhook(c context.Context, webhook *domain.Webhook) error {
+	events, err := encodeWebhookEvents(webhook.Events)
+	if err != nil {
+		return err
+	}
+	result, err := wr.db.ExecContext(c, `UPDATE webhooks SET url = ?, events = ?, secret = ?, active = ?, updated_at = ?
+		WHERE id = ?`,
+		webhook.URL, events, webhook.Secret, webhook.Active, formatSQLiteTime(webhook.UpdatedAt), webhook.ID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (wr *sqliteWebhookRepository) DeleteWebhook(c context.Context, webhookId string) error {
+	result, err := wr.db.ExecContext(c, `DELETE FROM webhooks WHERE id = ?`, webhookId)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func scanSQLiteWebhook(row rowScanner) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	var events, createdAt, updatedAt string
+	if err := row.Scan(&webhook.ID, &webhook.URL, &events, &webhook.Secret, &webhook.Active, &webhook.CreatedBy,
+		&createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+		return nil, err
+	}
+	var err error
+	if webhook.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	if webhook.UpdatedAt, err = parseSQLiteTime(updatedAt); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+type sqliteWebhookDeliveryRepository struct {
+	db sqliteDB
+}
+
+func NewSQLiteWebhookDeliveryRepository(db *sql.DB) domain.WebhookDeliveryRepository {
+	return &sqliteWebhookDeliveryRepository{
+		db: sqliteDB{db},
+	}
+}
+
+const webhookDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, lease_owner,
+	lease_until, last_error, response_status, redelivery_of, created_at, delivered_at`
+
+func (dr *sqliteWebhookDeliveryRepository) CreateWebhookDelivery(c context.Context, delivery *domain.WebhookDelivery) error {
+	_, err := dr.db.ExecContext(c, `INSERT INTO webhook_deliveries (`+webhookDeliveryColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING`,
+		delivery.ID, delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.Status,
+		delivery.Attempts, delivery.LeaseOwner, formatSQLiteTime(delivery.LeaseUntil), delivery.LastError,
+		delivery.ResponseStatus, delivery.RedeliveryOf, formatSQLiteTime(delivery.CreatedAt),
+		nullableSQLiteTime(delivery.DeliveredAt))
+	return err
+}
+
+func (dr *sqliteWebhookDeliveryRepository) GetWebhookDeliveryByID(c context.Context, deliveryId string) (*domain.WebhookDelivery, error) {
+	row := dr.db.QueryRowContext(c, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = ?`, deliveryId)
+	delivery, err := scanSQLiteWebhookDelivery(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (dr *sqliteWebhookDeliveryRepository) GetWebhookDeliveries(c context.Context, webhookId string, limit int) ([]*domain.WebhookDelivery, error) {
+	rows, err := dr.db.QueryContext(c, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE webhook_id = ?
+		ORDER BY created_at DESC, id DESC LIMIT ?`, webhookId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSQLiteWebhookDeliveries(rows)
+}
+
+// ClaimWebhookDeliveries picks and leases the deliveries in one UPDATE, so
+// no other connection can claim them in between.
+func (dr *sqliteWebhookDeliveryRepository) ClaimWebhookDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	rows, err := dr.db.QueryContext(c, `UPDATE webhook_deliveries
+		SET lease_owner = ?, lease_until = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND lease_until <= ?
+			ORDER BY created_at, id LIMIT ?
+		)
+		RETURNING `+webhookDeliveryColumns,
+		owner, formatSQLiteTime(leaseUntil), domain.WebhookPending, formatSQLiteTime(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := scanSQLiteWebhookDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING gives no order guarantee.
+	sortWebhookDeliveries(claimed)
+	return claimed, nil
+}
+
+func (dr *sqliteWebhookDeliveryRepository) FinishWebhookDelivery(c context.Context, delivery *domain.WebhookDelivery, owner string) error {
+	result, err := dr.db.ExecContext(c, `UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, lease_owner = '', lease_until = ?, last_error = ?, response_status = ?, delivered_at = ?
+		WHERE id = ? AND lease_owner = ?`,
+		delivery.Status, delivery.Attempts, formatSQLiteTime(delivery.LeaseUntil), delivery.LastError,
+		delivery.ResponseStatus, nullableSQLiteTime(delivery.DeliveredAt),
+		delivery.ID, owner)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrWebhookLeaseLost
+	}
+	return nil
+}
+
+func (dr *sqliteWebhookDeliveryRepository) DeleteWebhookDeliveries(c context.Context, webhookId string) error {
+	_, err := dr.db.ExecContext(c, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, webhookId)
+	return err
+}
+
+func scanSQLiteWebhookDeliveries(rows *sql.Rows) ([]*domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanSQLiteWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanSQLiteWebhookDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var leaseUntil, createdAt string
+	var deliveredAt sql.NullString
+	if err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &delivery.Payload,
+		&delivery.Status, &delivery.Attempts, &delivery.LeaseOwner, &leaseUntil, &delivery.LastError,
+		&delivery.ResponseStatus, &delivery.RedeliveryOf, &createdAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if delivery.LeaseUntil, err = parseSQLiteTime(leaseUntil); err != nil {
+		return nil, err
+	}
+	if delivery.CreatedAt, err = parseSQLiteTime(createdAt); err != nil {
+		return nil, err
+	}
+	if delivery.DeliveredAt, err = parseNullableSQLiteTime(deliveredAt); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSubtasks is the contract every TaskRepository must meet for subtasks
+// and checklists.
+func testSubtasks(t *testing.T, repo domain.TaskRepository) {
+	ctx := context.Background()
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, task := range []*domain.Task{
+		{ID: "p1", UserID: "u1", Title: "parent", Status: "todo", DueDate: day},
+		{ID: "c2", UserID: "u1", ParentID: "p1", Title: "later", Status: "todo", DueDate: day.AddDate(0, 0, 2)},
+		{ID: "c1", UserID: "u1", ParentID: "p1", Title: "sooner", Status: "done", DueDate: day.AddDate(0, 0, 1)},
+		{ID: "c3", UserID: "u1", ParentID: "p1", Title: "also done", Status: "done", DueDate: day.AddDate(0, 0, 3)},
+		{ID: "g1", UserID: "u1", ParentID: "c1", Title: "grandchild", Status: "todo", DueDate: day},
+		{ID: "p2", UserID: "u1", Title: "other", Status: "todo", DueDate: day,
+			Checklist: []domain.ChecklistItem{{ID: "i1", Text: "first", Done: true}}},
+	} {
+		require.NoError(t, repo.CreateTask(ctx, task))
+	}
+
+	found, err := repo.GetTaskByID(ctx, "p2")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ChecklistItem{{ID: "i1", Text: "first", Done: true}}, found.Checklist)
+	found, err = repo.GetTaskByID(ctx, "c1")
+	require.NoError(t, err)
+	assert.Equal(t, "p1", found.ParentID)
+
+	// Only direct subtasks, by due date.
+	subtasks, err := repo.GetSubtasks(ctx, "p1")
+	require.NoError(t, err)
+	var ids []string
+	for _, subtask := range subtasks {
+		ids = append(ids, subtask.ID)
+	}
+	assert.Equal(t, []string{"c1", "c2", "c3"}, ids)
+
+	counts, err := repo.CountSubtasksByStatus(ctx, []string{"p1", "c1", "p2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"todo": 1, "done": 2}, counts["p1"])
+	assert.Equal(t, map[string]int{"todo": 1}, counts["c1"])
+	assert.Empty(t, counts["p2"])
+
+	// UpdateTask leaves the parent and checklist alone.
+	_, err = repo.UpdateTask(ctx, "p2", &domain.Task{ID: "p2", UserID: "u1", Title: "renamed", Status: "todo", DueDate: day, ParentID: "p1"})
+	require.NoError(t, err)
+	found, err = repo.GetTaskByID(ctx, "p2")
+	require.NoError(t, err)
+	assert.Empty(t, found.ParentID)
+	assert.Len(t, found.Checklist, 1)
+
+	checklist := []domain.ChecklistItem{{ID: "i1", Text: "first", Done: false}, {ID: "i2", Text: "second"}}
+	require.NoError(t, repo.SetTaskChecklist(ctx, "p2", checklist))
+	found, err = repo.GetTaskByID(ctx, "p2")
+	require.NoError(t, err)
+	assert.Equal(t, checklist, found.Checklist)
+	require.NoError(t, repo.SetTaskChecklist(ctx, "p2", nil))
+	found, err = repo.GetTaskByID(ctx, "p2")
+	require.NoError(t, err)
+	assert.Empty(t, found.Checklist)
+	assert.ErrorIs(t, repo.SetTaskChecklist(ctx, "missing", checklist), domain.ErrTaskNotFound)
+
+	require.NoError(t, repo.DetachSubtasks(ctx, "p1"))
+	subtasks, err = repo.GetSubtasks(ctx, "p1")
+	require.NoError(t, err)
+	assert.Empty(t, subtasks)
+	found, err = repo.GetTaskByID(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, "c1", found.ParentID)
+}
+
+func TestInMemorySubtasks(t *testing.T) {
+	testSubtasks(t, repository.NewInMemoryTaskRepository())
+}
+
+func TestSQLiteSubtasks(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "subtasks.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testSubtasks(t, repository.NewSQLiteTaskRepository(db))
+}
+
+func TestMongoSubtasks(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection = "test_subtasks"
+	require.NoError(t, db.Collection(collection).Drop(ctx))
+	t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	require.NoError(t, repository.EnsureTaskIndexes(ctx, db, collection))
+
+	testSubtasks(t, repository.NewTaskRepository(db, collection))
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// taskCursor is the decoded form of TaskPage.NextCursor. It records the sort
+// key and ID of the last task on a page so the next page can resume right
+// after it (keyset pagination). The sort is embedded so a cursor cannot be
+// replayed against a different ordering.
+type taskCursor struct {
+	SortBy string `json:"s"`
+	Desc   bool   `json:"d"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+func encodeTaskCursor(query domain.TaskQuery, last *domain.Task) string {
+	raw, _ := json.Marshal(taskCursor{
+		SortBy: query.SortBy,
+		Desc:   query.SortDesc,
+		Value:  taskSortValue(last, query.SortBy),
+		ID:     last.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeTaskCursor returns nil for an empty cursor and domain.ErrInvalidCursor
+// for one that is malformed or was issued for a different sort.
+func decodeTaskCursor(query domain.TaskQuery) (*taskCursor, error) {
+	if query.Cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	var cursor taskCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	if cursor.SortBy != query.SortBy || cursor.Desc != query.SortDesc || cursor.ID == "" {
+		return nil, domain.ErrInvalidCursor
+	}
+	if query.SortBy == domain.TaskSortDueDate {
+		if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+	}
+	return &cursor, nil
+}
+
+// taskSortValue renders the field a task is sorted on as a string. Due dates
+// use RFC 3339 so they survive the round trip through a cursor.
+func taskSortValue(task *domain.Task, field string) string {
+	switch field {
+	case domain.TaskSortID:
+		return task.ID
+	case domain.TaskSortUserID:
+		return task.UserID
+	case domain.TaskSortTitle:
+		return task.Title
+	case domain.TaskSortDescription:
+		return task.Description
+	case domain.TaskSortStatus:
+		return task.Status
+	case domain.TaskSortDueDate:
+		return task.DueDate.UTC().Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+// compareTasks orders a and b by field, breaking ties on ID, ascending.
+func compareTasks(a, b *domain.Task, field string) int {
+	var c int
+	if field == domain.TaskSortDueDate {
+		c = a.DueDate.Compare(b.DueDate)
+	} else {
+		c = strings.Compare(taskSortValue(a, field), taskSortValue(b, field))
+	}
+	if c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// afterCursor reports whether task sorts strictly after the cursor position.
+func afterCursor(task *domain.Task, cursor *taskCursor) bool {
+	var c int
+	if cursor.SortBy == domain.TaskSortDueDate {
+		at, _ := time.Parse(time.RFC3339Nano, cursor.Value)
+		c = task.DueDate.Compare(at)
+	} else {
+		c = strings.Compare(taskSortValue(task, cursor.SortBy), cursor.Value)
+	}
+	if c == 0 {
+		c = strings.Compare(task.ID, cursor.ID)
+	}
+	if cursor.Desc {
+		return c < 0
+	}
+	return c > 0
+}
+
+// matchesTaskQuery applies the filter part of query to a single task.
+func matchesTaskQuery(task *domain.Task, query domain.TaskQuery) bool {
+	if task.UserID != query.UserID {
+		return false
+	}
+	if query.Status != "" && task.Status != query.Status {
+		return false
+	}
+	if !query.DueFrom.IsZero() && task.DueDate.Before(query.DueFrom) {
+		return false
+	}
+	if !query.DueTo.IsZero() && !task.DueDate.Before(query.DueTo) {
+		return false
+	}
+	if len(query.Labels) > 0 {
+		has := func(labelID string) bool { return slices.Contains(task.LabelIDs, labelID) }
+		if query.MatchAllLabels {
+			return !slices.ContainsFunc(query.Labels, func(labelID string) bool { return !has(labelID) })
+		}
+		return slices.ContainsFunc(query.Labels, has)
+	}
+	return true
+}
+
+// withDefaultSort makes a query without SortBy sort by ID, so that callers
+// bypassing the usecase layer still get a deterministic order.
+func withDefaultSort(query domain.TaskQuery) domain.TaskQuery {
+	if query.SortBy == "" {
+		query.SortBy = domain.TaskSortID
+	}
+	return query
+}
+
+// pageTasks evaluates query over an unfiltered set of tasks. It is used by
+// stores that cannot push the query down, such as the in-memory repository.
+func pageTasks(tasks []*domain.Task, query domain.TaskQuery) (*domain.TaskPage, error) {
+	query = withDefaultSort(query)
+	cursor, err := decodeTaskCursor(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*domain.Task
+	for _, t := range tasks {
+		if matchesTaskQuery(t, query) && (cursor == nil || afterCursor(t, cursor)) {
+			matched = append(matched, t)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		c := compareTasks(matched[i], matched[j], query.SortBy)
+		if query.SortDesc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	return newTaskPage(matched, query), nil
+}
+
+// newTaskPage trims a result that was fetched with one extra row (limit+1)
+// and derives the cursor for the next page from the last task kept.
+func newTaskPage(tasks []*domain.Task, query domain.TaskQuery) *domain.TaskPage {
+	page := &domain.TaskPage{Tasks: tasks}
+	if query.Limit > 0 && len(tasks) > query.Limit {
+		page.Tasks = tasks[:query.Limit]
+		page.NextCursor = encodeTaskCursor(query, page.Tasks[len(page.Tasks)-1])
+	}
+	return page
+}
@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTaskQueries exercises filtering, sorting and cursor pagination of
+// GetAllTasks. Every TaskRepository implementation runs it so that they all
+// answer a TaskQuery identically.
+func testTaskQueries(t *testing.T, repo domain.TaskRepository) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 9, 0, 0, 0, time.UTC) }
+
+	seed := []*domain.Task{
+		{ID: "t1", UserID: "owner", Title: "delta", DueDate: day(4), Status: "pending"},
+		{ID: "t2", UserID: "owner", Title: "alpha", DueDate: day(2), Status: "completed"},
+		{ID: "t3", UserID: "owner", Title: "charlie", DueDate: day(2), Status: "pending"},
+		{ID: "t4", UserID: "owner", Title: "bravo", DueDate: day(1), Status: "pending"},
+		{ID: "t5", UserID: "owner", Title: "echo", DueDate: day(5), Status: "pending"},
+		{ID: "x1", UserID: "someone-else", Title: "alpha", DueDate: day(1), Status: "pending"},
+	}
+	for _, task := range seed {
+		require.NoError(t, repo.CreateTask(ctx, task))
+	}
+
+	ids := func(page *domain.TaskPage) []string {
+		var out []string
+		for _, task := range page.Tasks {
+			out = append(out, task.ID)
+		}
+		return out
+	}
+
+	t.Run("filters by owner and status", func(t *testing.T) {
+		page, err := repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", Status: "pending", SortBy: domain.TaskSortID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t1", "t3", "t4", "t5"}, ids(page))
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("filters by due date range", func(t *testing.T) {
+		page, err := repo.GetAllTasks(ctx, domain.TaskQuery{
+			UserID: "owner", DueFrom: day(2), DueTo: day(5), SortBy: domain.TaskSortDueDate,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t2", "t3", "t1"}, ids(page))
+	})
+
+	t.Run("sorts descending", func(t *testing.T) {
+		page, err := repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", SortBy: domain.TaskSortTitle, SortDesc: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t5", "t1", "t3", "t4", "t2"}, ids(page))
+	})
+
+	t.Run("paginates with cursors", func(t *testing.T) {
+		for _, desc := range []bool{false, true} {
+			query := domain.TaskQuery{UserID: "owner", SortBy: domain.TaskSortDueDate, SortDesc: desc, Limit: 2}
+			var got []string
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, 5, "pagination did not terminate")
+				page, err := repo.GetAllTasks(ctx, query)
+				require.NoError(t, err)
+				got = append(got, ids(page)...)
+				if page.NextCursor == "" {
+					break
+				}
+				query.Cursor = page.NextCursor
+			}
+			want := []string{"t4", "t2", "t3", "t1", "t5"}
+			if desc {
+				want = []string{"t5", "t1", "t3", "t2", "t4"}
+			}
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("rejects a cursor from another sort", func(t *testing.T) {
+		page, err := repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", SortBy: domain.TaskSortTitle, Limit: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, page.NextCursor)
+
+		_, err = repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", SortBy: domain.TaskSortStatus, Limit: 1, Cursor: page.NextCursor})
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+
+		_, err = repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", Cursor: "not-a-cursor"})
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+
+	t.Run("filters by any or all labels", func(t *testing.T) {
+		for taskID, labels := range map[string][]string{"t1": {"a", "b"}, "t3": {"a"}, "t5": {"b", "c"}, "x1": {"a", "b"}} {
+			for _, label := range labels {
+				require.NoError(t, repo.AddTaskLabel(ctx, taskID, label))
+			}
+		}
+
+		page, err := repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", Labels: []string{"a", "b"}, SortBy: domain.TaskSortID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t1", "t3", "t5"}, ids(page))
+
+		page, err = repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", Labels: []string{"a", "b"}, MatchAllLabels: true, SortBy: domain.TaskSortID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t1"}, ids(page))
+		assert.Equal(t, []string{"a", "b"}, page.Tasks[0].LabelIDs)
+
+		page, err = repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", Labels: []string{"b"}, Status: "pending", SortBy: domain.TaskSortDueDate, SortDesc: true, Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t5"}, ids(page))
+		page, err = repo.GetAllTasks(ctx, domain.TaskQuery{UserID: "owner", Labels: []string{"b"}, Status: "pending", SortBy: domain.TaskSortDueDate, SortDesc: true, Limit: 1, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t1"}, ids(page))
+		assert.Empty(t, page.NextCursor)
+	})
+}
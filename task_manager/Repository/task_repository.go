@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	domain "task_manager/Domain"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type taskRepository struct {
@@ -20,29 +23,118 @@ func NewTaskRepository(db *mongo.Database, collection string) domain.TaskReposit
 	}
 }
 
-func (tr *taskRepository) GetAllTasks(c context.Context, id string) ([]*domain.Task, error) {
+// taskBSONFields maps domain sort fields to the document keys the driver
+// derives from domain.Task (lower-cased field names).
+var taskBSONFields = map[string]string{
+	domain.TaskSortID:          "id",
+	domain.TaskSortUserID:      "userid",
+	domain.TaskSortTitle:       "title",
+	domain.TaskSortDescription: "description",
+	domain.TaskSortDueDate:     "duedate",
+	domain.TaskSortStatus:      "status",
+}
+
+// EnsureTaskIndexes creates the indexes GetAllTasks relies on: one per sort
+// field scoped to the owner, with id as the keyset tie-breaker, plus one for
+// the common "status filter, due date order" listing, a multikey index on
+// labelids for label filters, one on parentid for subtasks and one on the
+// series of recurring tasks.
+func EnsureTaskIndexes(c context.Context, db *mongo.Database, collection string) error {
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "status", Value: 1}, {Key: "duedate", Value: 1}, {Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "labelids", Value: 1}, {Key: "duedate", Value: 1}, {Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "parentid", Value: 1}, {Key: "duedate", Value: 1}, {Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "recurrence.seriesid", Value: 1}, {Key: "recurrence.occurrence", Value: 1}, {Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "duedate", Value: 1}, {Key: "id", Value: 1}}},
+	}
+	for _, field := range domain.TaskSortFields {
+		key := taskBSONFields[field]
+		if key == "id" || key == "userid" {
+			continue
+		}
+		models = append(models, mongo.IndexModel{
+			Keys: bson.D{{Key: "userid", Value: 1}, {Key: key, Value: 1}, {Key: "id", Value: 1}},
+		})
+	}
+	_, err := db.Collection(collection).Indexes().CreateMany(c, models)
+	return err
+}
+
+func (tr *taskRepository) GetAllTasks(c context.Context, query domain.TaskQuery) (*domain.TaskPage, error) {
 	collection := tr.database.Collection(tr.collection)
-	filter := bson.M{"userid": id}
-	cursor, err := collection.Find(c, filter)
 
+	query = withDefaultSort(query)
+	sortKey, ok := taskBSONFields[query.SortBy]
+	if !ok {
+		return nil, domain.ErrInvalidTaskQuery
+	}
+	cursor, err := decodeTaskCursor(query)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(c)
+
+	filter := bson.M{"userid": query.UserID}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	due := bson.M{}
+	if !query.DueFrom.IsZero() {
+		due["$gte"] = query.DueFrom
+	}
+	if !query.DueTo.IsZero() {
+		due["$lt"] = query.DueTo
+	}
+	if len(due) > 0 {
+		filter["duedate"] = due
+	}
+	if len(query.Labels) > 0 {
+		match := "$in"
+		if query.MatchAllLabels {
+			match = "$all"
+		}
+		filter["labelids"] = bson.M{match: query.Labels}
+	}
+
+	direction, op := 1, "$gt"
+	if query.SortDesc {
+		direction, op = -1, "$lt"
+	}
+	if cursor != nil {
+		var value interface{} = cursor.Value
+		if query.SortBy == domain.TaskSortDueDate {
+			value, _ = time.Parse(time.RFC3339Nano, cursor.Value)
+		}
+		filter["$or"] = bson.A{
+			bson.M{sortKey: bson.M{op: value}},
+			bson.M{sortKey: value, "id": bson.M{op: cursor.ID}},
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: sortKey, Value: direction}, {Key: "id", Value: direction}})
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit) + 1)
+	}
+
+	results, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close(c)
 
 	var tasks []*domain.Task
-	for cursor.Next(c) {
+	for results.Next(c) {
 		var t domain.Task
-		if err := cursor.Decode(&t); err != nil {
+		if err := results.Decode(&t); err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, &t)
 	}
-	if err := cursor.Err(); err != nil {
+	if err := results.Err(); err != nil {
 		return nil, err
 	}
 
-	return tasks, nil
+	return newTaskPage(tasks, query), nil
 }
 
 func (tr *taskRepository) GetTaskByID(c context.Context, id string) (*domain.Task, error) {
@@ -53,6 +145,9 @@ func (tr *taskRepository) GetTaskByID(c context.Context, id string) (*domain.Tas
 	var task domain.Task
 	err := collection.FindOne(c, filter).Decode(&task)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskNotFound
+		}
 		return nil, err
 	}
 
@@ -64,6 +159,9 @@ func (tr *taskRepository) CreateTask(c context.Context, task *domain.Task) error
 
 	_, err := collection.InsertOne(c, task)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTaskAlreadyExists
+		}
 		return err
 	}
 
@@ -75,10 +173,29 @@ func (tr *taskRepository) UpdateTask(c context.Context, id string, task *domain.
 
 	filter := bson.M{"id": id}
 
-	_, err := collection.UpdateOne(c, filter, bson.M{"$set": task})
+	// The parent, checklist, labels and recurrence have their own updates;
+	// writing back what was read before this update could undo a concurrent
+	// one.
+	raw, err := bson.Marshal(task)
 	if err != nil {
 		return nil, err
 	}
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "parentid")
+	delete(fields, "checklist")
+	delete(fields, "labelids")
+	delete(fields, "recurrence")
+
+	result, err := collection.UpdateOne(c, filter, bson.M{"$set": fields})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrTaskNotFound
+	}
 
 	return task, nil
 }
@@ -86,10 +203,208 @@ func (tr *taskRepository) UpdateTask(c context.Context, id string, task *domain.
 func (tr *taskRepository) DeleteTask(c context.Context, id string) error {
 	collection := tr.database.Collection(tr.collection)
 
-	_, err := collection.DeleteOne(c, bson.M{"id": id})
+	result, err := collection.DeleteOne(c, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+func (tr *taskRepository) DeleteTasksByUser(c context.Context, userId string) error {
+	collection := tr.database.Collection(tr.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{"userid": userId})
+	return err
+}
+
+// The label updates are pipelines because tasks stored without labels have
+// a null labelids, which $addToSet and $pull refuse to touch.
+
+func (tr *taskRepository) AddTaskLabel(c context.Context, taskId string, labelId string) error {
+	collection := tr.database.Collection(tr.collection)
+
+	labelIDs := bson.M{"$ifNull": bson.A{"$labelids", bson.A{}}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"labelids": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{labelId, labelIDs}},
+			labelIDs,
+			bson.M{"$concatArrays": bson.A{labelIDs, bson.A{labelId}}},
+		}},
+	}}}}
+	result, err := collection.UpdateOne(c, bson.M{"id": taskId}, update)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (tr *taskRepository) RemoveTaskLabel(c context.Context, taskId string, labelId string) error {
+	collection := tr.database.Collection(tr.collection)
+
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"labelids": bson.M{"$filter": bson.M{
+			"input": bson.M{"$ifNull": bson.A{"$labelids", bson.A{}}},
+			"cond":  bson.M{"$ne": bson.A{"$$this", labelId}},
+		}},
+	}}}}
+	result, err := collection.UpdateOne(c, bson.M{"id": taskId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (tr *taskRepository) RemoveLabelFromTasks(c context.Context, userId string, labelId string) error {
+	collection := tr.database.Collection(tr.collection)
+
+	_, err := collection.UpdateMany(c, bson.M{"userid": userId, "labelids": labelId},
+		bson.M{"$pull": bson.M{"labelids": labelId}})
+	return err
+}
+
+func (tr *taskRepository) GetSubtasks(c context.Context, parentId string) ([]*domain.Task, error) {
+	collection := tr.database.Collection(tr.collection)
 
+	opts := options.Find().SetSort(bson.D{{Key: "duedate", Value: 1}, {Key: "id", Value: 1}})
+	results, err := collection.Find(c, bson.M{"parentid": parentId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close(c)
+
+	var subtasks []*domain.Task
+	for results.Next(c) {
+		var t domain.Task
+		if err := results.Decode(&t); err != nil {
+			return nil, err
+		}
+		subtasks = append(subtasks, &t)
+	}
+	return subtasks, results.Err()
+}
+
+func (tr *taskRepository) CountSubtasksByStatus(c context.Context, parentIds []string) (map[string]map[string]int, error) {
+	counts := make(map[string]map[string]int)
+	if len(parentIds) == 0 {
+		return counts, nil
+	}
+	collection := tr.database.Collection(tr.collection)
+
+	cursor, err := collection.Aggregate(c, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"parentid": bson.M{"$in": parentIds}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"parent": "$parentid", "status": "$status"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	for cursor.Next(c) {
+		var group struct {
+			Key struct {
+				Parent string `bson:"parent"`
+				Status string `bson:"status"`
+			} `bson:"_id"`
+			Count int `bson:"count"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return nil, err
+		}
+		if counts[group.Key.Parent] == nil {
+			counts[group.Key.Parent] = make(map[string]int)
+		}
+		counts[group.Key.Parent][group.Key.Status] = group.Count
+	}
+	return counts, cursor.Err()
+}
+
+func (tr *taskRepository) DetachSubtasks(c context.Context, parentId string) error {
+	collection := tr.database.Collection(tr.collection)
+
+	_, err := collection.UpdateMany(c, bson.M{"parentid": parentId}, bson.M{"$set": bson.M{"parentid": ""}})
+	return err
+}
+
+func (tr *taskRepository) SetTaskChecklist(c context.Context, taskId string, checklist []domain.ChecklistItem) error {
+	collection := tr.database.Collection(tr.collection)
+
+	if checklist == nil {
+		checklist = []domain.ChecklistItem{}
+	}
+	result, err := collection.UpdateOne(c, bson.M{"id": taskId}, bson.M{"$set": bson.M{"checklist": checklist}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (tr *taskRepository) GetTasksBySeries(c context.Context, seriesId string) ([]*domain.Task, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "recurrence.occurrence", Value: 1}, {Key: "id", Value: 1}})
+	results, err := collection.Find(c, bson.M{"recurrence.seriesid": seriesId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close(c)
+
+	var series []*domain.Task
+	for results.Next(c) {
+		var t domain.Task
+		if err := results.Decode(&t); err != nil {
+			return nil, err
+		}
+		series = append(series, &t)
+	}
+	return series, results.Err()
+}
+
+func (tr *taskRepository) GetTasksDueBetween(c context.Context, from time.Time, to time.Time) ([]*domain.Task, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "duedate", Value: 1}, {Key: "id", Value: 1}})
+	results, err := collection.Find(c, bson.M{"duedate": bson.M{"$gte": from, "$lt": to}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close(c)
+
+	var due []*domain.Task
+	for results.Next(c) {
+		var t domain.Task
+		if err := results.Decode(&t); err != nil {
+			return nil, err
+		}
+		due = append(due, &t)
+	}
+	return due, results.Err()
+}
+
+func (tr *taskRepository) SetTaskRecurrence(c context.Context, taskId string, recurrence *domain.Recurrence) error {
+	collection := tr.database.Collection(tr.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"id": taskId}, bson.M{"$set": bson.M{"recurrence": recurrence}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTaskNotFound
+	}
 	return nil
 }
@@ -0,0 +1,141 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testRefreshTokens is the contract every RefreshTokenRepository must meet.
+func testRefreshTokens(t *testing.T, repo domain.RefreshTokenRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	first := &domain.RefreshToken{ID: "rt1", FamilyID: "fam", UserID: "u1", TokenHash: "hash-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	second := &domain.RefreshToken{ID: "rt2", FamilyID: "fam", UserID: "u1", TokenHash: "hash-2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	other := &domain.RefreshToken{ID: "rt3", FamilyID: "other", UserID: "u1", TokenHash: "hash-3", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	for _, token := range []*domain.RefreshToken{first, second, other} {
+		require.NoError(t, repo.CreateRefreshToken(ctx, token))
+	}
+	assert.ErrorIs(t, repo.CreateRefreshToken(ctx, &domain.RefreshToken{ID: "rt4", TokenHash: "hash-1", ExpiresAt: now.Add(time.Hour)}),
+		domain.ErrTokenAlreadyExists)
+
+	found, err := repo.GetRefreshTokenByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "rt1", found.ID)
+	assert.Equal(t, "fam", found.FamilyID)
+	assert.Nil(t, found.UsedAt)
+
+	_, err = repo.GetRefreshTokenByHash(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+	// Only the first rotation of a token succeeds.
+	marked, err := repo.MarkRefreshTokenUsed(ctx, "rt1", now)
+	require.NoError(t, err)
+	assert.True(t, marked)
+	marked, err = repo.MarkRefreshTokenUsed(ctx, "rt1", now.Add(time.Second))
+	require.NoError(t, err)
+	assert.False(t, marked)
+
+	found, err = repo.GetRefreshTokenByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, found.UsedAt)
+	assert.True(t, found.UsedAt.Equal(now))
+
+	require.NoError(t, repo.RevokeRefreshTokenFamily(ctx, "fam", now))
+	for hash, revoked := range map[string]bool{"hash-1": true, "hash-2": true, "hash-3": false} {
+		found, err := repo.GetRefreshTokenByHash(ctx, hash)
+		require.NoError(t, err)
+		assert.Equal(t, revoked, found.RevokedAt != nil, hash)
+	}
+}
+
+// testRevokedTokens is the contract every RevokedTokenRepository must meet.
+func testRevokedTokens(t *testing.T, repo domain.RevokedTokenRepository) {
+	ctx := context.Background()
+
+	revoked, err := repo.IsTokenRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, repo.RevokeToken(ctx, "jti-1", time.Now().Add(time.Hour)))
+	// Revoking twice, e.g. a repeated logout, is harmless.
+	require.NoError(t, repo.RevokeToken(ctx, "jti-1", time.Now().Add(time.Hour)))
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti-2")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestInMemoryRefreshTokenRepository(t *testing.T) {
+	testRefreshTokens(t, repository.NewInMemoryRefreshTokenRepository())
+}
+
+func TestInMemoryRevokedTokenRepository(t *testing.T) {
+	testRevokedTokens(t, repository.NewInMemoryRevokedTokenRepository())
+}
+
+func TestInMemoryRefreshTokenRepository_PurgesExpired(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryRefreshTokenRepository()
+
+	expired := &domain.RefreshToken{ID: "old", TokenHash: "old-hash", ExpiresAt: time.Now().Add(-time.Minute)}
+	require.NoError(t, repo.CreateRefreshToken(ctx, expired))
+	require.NoError(t, repo.CreateRefreshToken(ctx, &domain.RefreshToken{ID: "new", TokenHash: "new-hash", ExpiresAt: time.Now().Add(time.Hour)}))
+
+	_, err := repo.GetRefreshTokenByHash(ctx, "old-hash")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+}
+
+func TestMongoTokenRepositories(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const refreshCollection, revokedCollection = "test_refresh_tokens", "test_revoked_tokens"
+	for _, name := range []string{refreshCollection, revokedCollection} {
+		require.NoError(t, db.Collection(name).Drop(ctx))
+	}
+	t.Cleanup(func() {
+		_ = db.Collection(refreshCollection).Drop(context.Background())
+		_ = db.Collection(revokedCollection).Drop(context.Background())
+	})
+	require.NoError(t, repository.EnsureRefreshTokenIndexes(ctx, db, refreshCollection))
+	require.NoError(t, repository.EnsureRevokedTokenIndexes(ctx, db, revokedCollection))
+
+	t.Run("refresh", func(t *testing.T) {
+		testRefreshTokens(t, repository.NewRefreshTokenRepository(db, refreshCollection))
+	})
+	t.Run("revoked", func(t *testing.T) {
+		testRevokedTokens(t, repository.NewRevokedTokenRepository(db, revokedCollection))
+	})
+}
+
+// newMongoTestDatabase connects to DATABASE_URL, skipping the test when it
+// is not configured.
+func newMongoTestDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+	_ = godotenv.Load("../.env")
+
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL environment variable is not set")
+	}
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(url))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+	return client.Database("test_task_db")
+}
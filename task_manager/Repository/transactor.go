@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoTransactor struct {
+	client *mongo.Client
+}
+
+// NewMongoTransactor runs transactions in sessions of client. The Mongo
+// repositories take part simply by being called with the context the
+// transaction hands them. Transactions need a replica set or sharded
+// cluster; see MongoSupportsTransactions.
+func NewMongoTransactor(client *mongo.Client) domain.Transactor {
+	return &mongoTransactor{
+		client: client,
+	}
+}
+
+// WithinTransaction retries fn as the driver sees fit when the transaction
+// hits a write conflict or a transient error.
+func (mt *mongoTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	session, err := mt.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// MongoSupportsTransactions reports whether the server client is connected
+// to is a replica set member or a mongos; a standalone server has no
+// transactions.
+func MongoSupportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
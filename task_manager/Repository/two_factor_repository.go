@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type twoFactorRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewTwoFactorRepository(db *mongo.Database, collection string) domain.TwoFactorRepository {
+	return &twoFactorRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureTwoFactorIndexes allows one enrollment per user.
+func EnsureTwoFactorIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userid", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (tr *twoFactorRepository) GetTwoFactor(c context.Context, userId string) (*domain.TwoFactor, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	var twoFactor domain.TwoFactor
+	err := collection.FindOne(c, bson.M{"userid": userId}).Decode(&twoFactor)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+func (tr *twoFactorRepository) SaveTwoFactor(c context.Context, twoFactor *domain.TwoFactor) error {
+	collection := tr.database.Collection(tr.collection)
+
+	stored := *twoFactor
+	if stored.RecoveryCodes == nil {
+		// $pull needs an array to work on.
+		stored.RecoveryCodes = []string{}
+	}
+	_, err := collection.ReplaceOne(c, bson.M{"userid": twoFactor.UserID}, &stored, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (tr *twoFactorRepository) DeleteTwoFactor(c context.Context, userId string) error {
+	collection := tr.database.Collection(tr.collection)
+
+	_, err := collection.DeleteOne(c, bson.M{"userid": userId})
+	return err
+}
+
+func (tr *twoFactorRepository) UseTOTPStep(c context.Context, userId string, step int64) (bool, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	filter := bson.M{"userid": userId, "lastusedstep": bson.M{"$lt": step}}
+	result, err := collection.UpdateOne(c, filter, bson.M{"$set": bson.M{"lastusedstep": step}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (tr *twoFactorRepository) UseRecoveryCode(c context.Context, userId string, codeHash string) (bool, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	filter := bson.M{"userid": userId, "recoverycodes": codeHash}
+	result, err := collection.UpdateOne(c, filter, bson.M{"$pull": bson.M{"recoverycodes": codeHash}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+type twoFactorRoleRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewTwoFactorRoleRepository(db *mongo.Database, collection string) domain.TwoFactorRoleRepository {
+	return &twoFactorRoleRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureTwoFactorRoleIndexes lists each role at most once.
+func EnsureTwoFactorRoleIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys:    bson.D{{Key: "role", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (rr *twoFactorRoleRepository) GetTwoFactorRoles(c context.Context) ([]string, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	cursor, err := collection.Find(c, bson.D{}, options.Find().SetSort(bson.D{{Key: "role", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var roles []string
+	for cursor.Next(c) {
+		var doc struct {
+			Role string `bson:"role"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		roles = append(roles, doc.Role)
+	}
+	return roles, cursor.Err()
+}
+
+func (rr *twoFactorRoleRepository) SetTwoFactorRequired(c context.Context, role string, required bool) error {
+	collection := rr.database.Collection(rr.collection)
+
+	if !required {
+		_, err := collection.DeleteOne(c, bson.M{"role": role})
+		return err
+	}
+	_, err := collection.UpdateOne(c, bson.M{"role": role}, bson.M{"$set": bson.M{"role": role}}, options.Update().SetUpsert(true))
+	return err
+}
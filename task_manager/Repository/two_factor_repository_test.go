@@ -0,0 +1,145 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTwoFactor is the contract every TwoFactorRepository must meet.
+func testTwoFactor(t *testing.T, repo domain.TwoFactorRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	_, err := repo.GetTwoFactor(ctx, "u1")
+	assert.ErrorIs(t, err, domain.ErrTwoFactorNotEnrolled)
+
+	pending := &domain.TwoFactor{UserID: "u1", Secret: "SECRET1", CreatedAt: now}
+	require.NoError(t, repo.SaveTwoFactor(ctx, pending))
+	found, err := repo.GetTwoFactor(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET1", found.Secret)
+	assert.False(t, found.Enabled)
+	assert.Empty(t, found.RecoveryCodes)
+	assert.Nil(t, found.EnabledAt)
+	assert.True(t, found.CreatedAt.Equal(now))
+
+	// Saving again replaces the enrollment.
+	enabled := &domain.TwoFactor{UserID: "u1", Secret: "SECRET2", Enabled: true, RecoveryCodes: []string{"h1", "h2"},
+		LastUsedStep: 100, CreatedAt: now, EnabledAt: &now}
+	require.NoError(t, repo.SaveTwoFactor(ctx, enabled))
+	found, err = repo.GetTwoFactor(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET2", found.Secret)
+	assert.True(t, found.Enabled)
+	assert.Equal(t, []string{"h1", "h2"}, found.RecoveryCodes)
+	assert.Equal(t, int64(100), found.LastUsedStep)
+	require.NotNil(t, found.EnabledAt)
+	assert.True(t, found.EnabledAt.Equal(now))
+
+	// A step is accepted once, and never one older than the last.
+	used, err := repo.UseTOTPStep(ctx, "u1", 100)
+	require.NoError(t, err)
+	assert.False(t, used)
+	used, err = repo.UseTOTPStep(ctx, "u1", 101)
+	require.NoError(t, err)
+	assert.True(t, used)
+	used, err = repo.UseTOTPStep(ctx, "missing", 101)
+	require.NoError(t, err)
+	assert.False(t, used)
+
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			used, err := repo.UseTOTPStep(ctx, "u1", 102)
+			assert.NoError(t, err)
+			if used {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), accepted.Load(), "a code works once even under concurrency")
+
+	used, err = repo.UseRecoveryCode(ctx, "u1", "h1")
+	require.NoError(t, err)
+	assert.True(t, used)
+	used, err = repo.UseRecoveryCode(ctx, "u1", "h1")
+	require.NoError(t, err)
+	assert.False(t, used)
+	used, err = repo.UseRecoveryCode(ctx, "missing", "h2")
+	require.NoError(t, err)
+	assert.False(t, used)
+	found, err = repo.GetTwoFactor(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"h2"}, found.RecoveryCodes)
+
+	require.NoError(t, repo.DeleteTwoFactor(ctx, "u1"))
+	require.NoError(t, repo.DeleteTwoFactor(ctx, "u1"))
+	_, err = repo.GetTwoFactor(ctx, "u1")
+	assert.ErrorIs(t, err, domain.ErrTwoFactorNotEnrolled)
+}
+
+// testTwoFactorRoles is the contract every TwoFactorRoleRepository must meet.
+func testTwoFactorRoles(t *testing.T, repo domain.TwoFactorRoleRepository) {
+	ctx := context.Background()
+
+	roles, err := repo.GetTwoFactorRoles(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+
+	require.NoError(t, repo.SetTwoFactorRequired(ctx, domain.RoleAdmin, true))
+	require.NoError(t, repo.SetTwoFactorRequired(ctx, domain.RoleAdmin, true))
+	require.NoError(t, repo.SetTwoFactorRequired(ctx, "editor", true))
+	roles, err = repo.GetTwoFactorRoles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.RoleAdmin, "editor"}, roles)
+
+	require.NoError(t, repo.SetTwoFactorRequired(ctx, "editor", false))
+	require.NoError(t, repo.SetTwoFactorRequired(ctx, "unknown", false))
+	roles, err = repo.GetTwoFactorRoles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.RoleAdmin}, roles)
+}
+
+func TestInMemoryTwoFactorRepository(t *testing.T) {
+	testTwoFactor(t, repository.NewInMemoryTwoFactorRepository())
+	testTwoFactorRoles(t, repository.NewInMemoryTwoFactorRoleRepository())
+}
+
+func TestSQLiteTwoFactorRepository(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "two_factor.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testTwoFactor(t, repository.NewSQLiteTwoFactorRepository(db))
+	testTwoFactorRoles(t, repository.NewSQLiteTwoFactorRoleRepository(db))
+}
+
+func TestMongoTwoFactorRepository(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const collection, roleCollection = "test_two_factor", "test_two_factor_roles"
+	for _, name := range []string{collection, roleCollection} {
+		require.NoError(t, db.Collection(name).Drop(ctx))
+		t.Cleanup(func() { _ = db.Collection(name).Drop(context.Background()) })
+	}
+	require.NoError(t, repository.EnsureTwoFactorIndexes(ctx, db, collection))
+	require.NoError(t, repository.EnsureTwoFactorRoleIndexes(ctx, db, roleCollection))
+
+	testTwoFactor(t, repository.NewTwoFactorRepository(db, collection))
+	testTwoFactorRoles(t, repository.NewTwoFactorRoleRepository(db, roleCollection))
+}
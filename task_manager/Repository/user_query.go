@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strings"
+
+	domain "task_manager/Domain"
+)
+
+// User listings are ordered by username, which is unique, so the cursor is
+// simply the last username on the page.
+
+func encodeUserCursor(last *domain.User) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(last.Username))
+}
+
+// decodeUserCursor returns "" for an empty cursor and domain.ErrInvalidCursor
+// for one that is malformed.
+func decodeUserCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) == 0 {
+		return "", domain.ErrInvalidCursor
+	}
+	return string(raw), nil
+}
+
+// matchesUserSearch is the in-process form of the search every backend
+// applies: a case-insensitive substring of the username or email.
+func matchesUserSearch(user *domain.User, search string) bool {
+	if search == "" {
+		return true
+	}
+	search = strings.ToLower(search)
+	return strings.Contains(strings.ToLower(user.Username), search) ||
+		strings.Contains(strings.ToLower(user.Email), search)
+}
+
+// newUserPage trims a result fetched with one extra row (limit+1) down to
+// the page and sets NextCursor if that extra row was there.
+func newUserPage(users []*domain.User, limit int) *domain.UserPage {
+	page := &domain.UserPage{Users: users}
+	if limit > 0 && len(users) > limit {
+		page.Users = users[:limit]
+		page.NextCursor = encodeUserCursor(page.Users[limit-1])
+	}
+	return page
+}
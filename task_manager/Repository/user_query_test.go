@@ -0,0 +1,194 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	domain "task_manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUserManagement covers the account-management UserRepository methods:
+// search, cursor pagination, disabling, email verification, password
+// changes, deletion, listing a role's members, bumping token versions and
+// counting active admins. Every implementation runs it against an empty
+// repository.
+func testUserManagement(t *testing.T, repo domain.UserRepository) {
+	ctx := context.Background()
+
+	seed := []*domain.User{
+		{ID: "u1", Username: "carol", Email: "carol@example.com", Password: "pw", Role: domain.RoleAdmin},
+		{ID: "u2", Username: "alice", Email: "alice@example.com", Password: "pw", Role: domain.RoleUser},
+		{ID: "u3", Username: "bob", Email: "bob@corp.test", Password: "pw", Role: domain.RoleAdmin},
+		{ID: "u4", Username: "dave_100%", Email: "dave@example.com", Password: "pw", Role: domain.RoleUser},
+	}
+	for _, user := range seed {
+		_, err := repo.CreateUser(ctx, user)
+		require.NoError(t, err)
+	}
+
+	names := func(page *domain.UserPage) []string {
+		var out []string
+		for _, user := range page.Users {
+			out = append(out, user.Username)
+		}
+		return out
+	}
+
+	page, err := repo.GetAllUsers(ctx, domain.UserQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, names(page))
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = repo.GetAllUsers(ctx, domain.UserQuery{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol", "dave_100%"}, names(page))
+	assert.Empty(t, page.NextCursor)
+
+	_, err = repo.GetAllUsers(ctx, domain.UserQuery{Limit: 2, Cursor: "%%%"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+
+	page, err = repo.GetAllUsers(ctx, domain.UserQuery{Search: "EXAMPLE", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "carol", "dave_100%"}, names(page))
+
+	// Search terms are literal: pattern characters must not act as wildcards.
+	page, err = repo.GetAllUsers(ctx, domain.UserQuery{Search: "0%", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dave_100%"}, names(page))
+	page, err = repo.GetAllUsers(ctx, domain.UserQuery{Search: ".*", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, page.Users)
+
+	admins, err := repo.CountActiveAdmins(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, admins)
+
+	members, err := repo.GetUsersByRole(ctx, domain.RoleAdmin)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "bob", members[0].Username)
+	assert.Equal(t, "carol", members[1].Username)
+
+	require.NoError(t, repo.BumpTokenVersion(ctx, "u4"))
+	bumped, err := repo.GetUserByID(ctx, "u4")
+	require.NoError(t, err)
+	assert.Equal(t, 1, bumped.TokenVersion)
+	assert.ErrorIs(t, repo.BumpTokenVersion(ctx, "missing"), domain.ErrUserNotFound)
+
+	require.NoError(t, repo.SetUserDisabled(ctx, "u3", true))
+	disabled, err := repo.GetUserByID(ctx, "u3")
+	require.NoError(t, err)
+	assert.True(t, disabled.Disabled)
+	assert.Equal(t, 1, disabled.TokenVersion, "disabling must invalidate existing tokens")
+
+	admins, err = repo.CountActiveAdmins(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, admins, "disabled admins are not active")
+
+	require.NoError(t, repo.SetUserDisabled(ctx, "u3", false))
+	enabled, err := repo.GetUserByID(ctx, "u3")
+	require.NoError(t, err)
+	assert.False(t, enabled.Disabled)
+	assert.ErrorIs(t, repo.SetUserDisabled(ctx, "missing", true), domain.ErrUserNotFound)
+
+	_, err = repo.CreateUser(ctx, &domain.User{ID: "u5", Username: "erin", Email: "erin@example.com", Password: "pw", Role: domain.RoleUser, EmailUnverified: true})
+	require.NoError(t, err)
+	unverified, err := repo.GetUserByID(ctx, "u5")
+	require.NoError(t, err)
+	assert.True(t, unverified.EmailUnverified)
+	require.NoError(t, repo.MarkEmailVerified(ctx, "u5"))
+	verified, err := repo.GetUserByID(ctx, "u5")
+	require.NoError(t, err)
+	assert.False(t, verified.EmailUnverified)
+	assert.ErrorIs(t, repo.MarkEmailVerified(ctx, "missing"), domain.ErrUserNotFound)
+
+	require.NoError(t, repo.UpdateUserPassword(ctx, "u2", "new-hash"))
+	changed, err := repo.GetUserByID(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", changed.Password)
+	assert.Equal(t, 1, changed.TokenVersion, "a password change must invalidate existing tokens")
+	assert.ErrorIs(t, repo.UpdateUserPassword(ctx, "missing", "new-hash"), domain.ErrUserNotFound)
+
+	require.NoError(t, repo.DeleteUser(ctx, "u2"))
+	_, err = repo.GetUserByID(ctx, "u2")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.ErrorIs(t, repo.DeleteUser(ctx, "u2"), domain.ErrUserNotFound)
+}
+
+// testLastAdmin covers the last-admin guard of UpdateUserRole,
+// SetUserDisabled and DeleteUser, including two admins taking each
+// other's rights away at once. Changes run in transactions of transactor,
+// which may be nil. Every implementation runs it against an empty
+// repository.
+func testLastAdmin(t *testing.T, repo domain.UserRepository, transactor domain.Transactor) {
+	ctx := context.Background()
+	inTransaction := func(fn func(ctx context.Context) error) error {
+		if transactor == nil {
+			return fn(ctx)
+		}
+		return transactor.WithinTransaction(ctx, fn)
+	}
+
+	for _, user := range []*domain.User{
+		{ID: "a1", Username: "ann", Email: "ann@example.com", Password: "pw", Role: domain.RoleAdmin},
+		{ID: "a2", Username: "ben", Email: "ben@example.com", Password: "pw", Role: domain.RoleAdmin},
+		{ID: "u1", Username: "cat", Email: "cat@example.com", Password: "pw", Role: domain.RoleUser},
+	} {
+		_, err := repo.CreateUser(ctx, user)
+		require.NoError(t, err)
+	}
+
+	// Both admins try to demote the other at once: one of them must stay.
+	errs := make(chan error, 2)
+	for _, id := range []string{"a1", "a2"} {
+		go func() {
+			errs <- inTransaction(func(ctx context.Context) error {
+				return repo.UpdateUserRole(ctx, id, domain.RoleUser)
+			})
+		}()
+	}
+	var refused int
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			require.ErrorIs(t, err, domain.ErrLastAdmin)
+			refused++
+		}
+	}
+	assert.Equal(t, 1, refused)
+	admins, err := repo.CountActiveAdmins(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, admins)
+
+	members, err := repo.GetUsersByRole(ctx, domain.RoleAdmin)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	last := members[0].ID
+
+	assert.ErrorIs(t, repo.UpdateUserRole(ctx, last, domain.RoleUser), domain.ErrLastAdmin)
+	assert.ErrorIs(t, repo.SetUserDisabled(ctx, last, true), domain.ErrLastAdmin)
+	assert.ErrorIs(t, repo.DeleteUser(ctx, last), domain.ErrLastAdmin)
+	stored, err := repo.GetUserByID(ctx, last)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleAdmin, stored.Role)
+	assert.False(t, stored.Disabled)
+
+	// Other users, and re-enabling or keeping admins, are not held back.
+	require.NoError(t, repo.UpdateUserRole(ctx, last, domain.RoleAdmin))
+	require.NoError(t, repo.SetUserDisabled(ctx, last, false))
+	require.NoError(t, repo.SetUserDisabled(ctx, "u1", true))
+	require.NoError(t, repo.DeleteUser(ctx, "u1"))
+
+	// With a second active admin, the first can go.
+	other := "a1"
+	if last == "a1" {
+		other = "a2"
+	}
+	require.NoError(t, repo.PromoteUserToAdmin(ctx, other))
+	require.NoError(t, repo.DeleteUser(ctx, last))
+	assert.ErrorIs(t, repo.DeleteUser(ctx, last), domain.ErrUserNotFound)
+	assert.ErrorIs(t, repo.SetUserDisabled(ctx, "missing", true), domain.ErrUserNotFound)
+	assert.ErrorIs(t, repo.UpdateUserRole(ctx, "missing", domain.RoleUser), domain.ErrUserNotFound)
+}
@@ -2,28 +2,109 @@ package repository
 
 import (
 	"context"
+	"regexp"
 	domain "task_manager/Domain"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type userRepository struct {
 	database *mongo.Database
 	collection string
+	guardCollection string
 }
 
-func NewUserRepository(db *mongo.Database, collection string) domain.UserRepository {
+// adminGuardID is the ID of the one document in the admin guard collection.
+const adminGuardID = "admins"
+
+// NewUserRepository keeps the last-admin check of UpdateUserRole,
+// SetUserDisabled and DeleteUser race-free with the admin guard document
+// in guardCollection, when they run in a transaction.
+func NewUserRepository(db *mongo.Database, collection string, guardCollection string) domain.UserRepository {
 	return &userRepository{
 		database: db,
 		collection: collection,
+		guardCollection: guardCollection,
+	}
+}
+
+// EnsureUserIndexes creates the indexes behind user listings and the
+// last-admin check, and the admin guard document, which transactions
+// must not have to create.
+func EnsureUserIndexes(c context.Context, db *mongo.Database, collection string, guardCollection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}},
+		{Keys: bson.D{{Key: "role", Value: 1}, {Key: "disabled", Value: 1}}},
+	})
+	if err != nil {
+		return err
 	}
+	_, err = db.Collection(guardCollection).UpdateOne(c, bson.M{"id": adminGuardID},
+		bson.M{"$setOnInsert": bson.M{"id": adminGuardID, "version": 0}}, options.Update().SetUpsert(true))
+	return err
 }
 
-func (ur *userRepository) GetAllUsers(c context.Context, user *domain.User) ([]*domain.User, error) {
+// ensureAdminRemains fails with ErrLastAdmin if user id is the only active
+// admin. In a transaction it first writes the admin guard document: of two
+// transactions taking away the rights of different admins, one then hits a
+// write conflict and is retried by the driver, seeing the other's change.
+// Outside a transaction, against a standalone server, the check and the
+// change that follows can still race.
+func (ur *userRepository) ensureAdminRemains(c context.Context, id string) error {
 	collection := ur.database.Collection(ur.collection)
 
-	cursor, err := collection.Find(c, bson.D{})
+	var user domain.User
+	err := collection.FindOne(c, bson.M{"id": id}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return domain.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if user.Role != domain.RoleAdmin || user.Disabled {
+		return nil
+	}
+	if mongo.SessionFromContext(c) != nil {
+		_, err := ur.database.Collection(ur.guardCollection).UpdateOne(c, bson.M{"id": adminGuardID},
+			bson.M{"$inc": bson.M{"version": 1}}, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+	others, err := collection.CountDocuments(c, bson.M{"role": domain.RoleAdmin, "disabled": bson.M{"$ne": true}, "id": bson.M{"$ne": id}})
+	if err != nil {
+		return err
+	}
+	if others == 0 {
+		return domain.ErrLastAdmin
+	}
+	return nil
+}
+
+func (ur *userRepository) GetAllUsers(c context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	collection := ur.database.Collection(ur.collection)
+
+	after, err := decodeUserCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{}
+	if after != "" {
+		filter["username"] = bson.M{"$gt": after}
+	}
+	if query.Search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query.Search), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"username": pattern}, bson.M{"email": pattern}}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "username", Value: 1}})
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit) + 1)
+	}
+
+	cursor, err := collection.Find(c, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +117,10 @@ func (ur *userRepository) GetAllUsers(c context.Context, user *domain.User) ([]*
 		}
 		users = append(users, &user)
 	}
-	return users, nil
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return newUserPage(users, query.Limit), nil
 }
 
 func (ur *userRepository) GetUserByID(c context.Context, id string) (*domain.User, error) {
@@ -47,6 +131,9 @@ func (ur *userRepository) GetUserByID(c context.Context, id string) (*domain.Use
 	var user domain.User
 	err := collection.FindOne(c, filter).Decode(&user)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUserNotFound
+		}
 		return nil, err
 	}
 
@@ -98,10 +185,151 @@ func (ur *userRepository) PromoteUserToAdmin(c context.Context, id string) error
 	collection := ur.database.Collection(ur.collection)
 
 	filter := bson.M{"id": id}
-	update := bson.M{"$set": bson.M{"role": "admin"}}
+	update := bson.M{"$set": bson.M{"role": domain.RoleAdmin}, "$inc": bson.M{"tokenversion": 1}}
 
-	_, err := collection.UpdateOne(c, filter, update)
-	return err
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *userRepository) UpdateUserRole(c context.Context, id string, role string) error {
+	collection := ur.database.Collection(ur.collection)
+
+	if role != domain.RoleAdmin {
+		if err := ur.ensureAdminRemains(c, id); err != nil {
+			return err
+		}
+	}
+
+	filter := bson.M{"id": id}
+	update := bson.M{"$set": bson.M{"role": role}, "$inc": bson.M{"tokenversion": 1}}
+
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *userRepository) SetUserDisabled(c context.Context, id string, disabled bool) error {
+	collection := ur.database.Collection(ur.collection)
+
+	if disabled {
+		if err := ur.ensureAdminRemains(c, id); err != nil {
+			return err
+		}
+	}
+
+	filter := bson.M{"id": id}
+	update := bson.M{"$set": bson.M{"disabled": disabled}, "$inc": bson.M{"tokenversion": 1}}
+
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *userRepository) MarkEmailVerified(c context.Context, id string) error {
+	collection := ur.database.Collection(ur.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"id": id}, bson.M{"$set": bson.M{"emailunverified": false}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *userRepository) UpdateUserPassword(c context.Context, id string, passwordHash string) error {
+	collection := ur.database.Collection(ur.collection)
+
+	filter := bson.M{"id": id}
+	update := bson.M{"$set": bson.M{"password": passwordHash}, "$inc": bson.M{"tokenversion": 1}}
+
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *userRepository) DeleteUser(c context.Context, id string) error {
+	collection := ur.database.Collection(ur.collection)
+
+	if err := ur.ensureAdminRemains(c, id); err != nil {
+		return err
+	}
+
+	result, err := collection.DeleteOne(c, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *userRepository) CountActiveAdmins(c context.Context) (int, error) {
+	collection := ur.database.Collection(ur.collection)
+
+	// Documents written before accounts could be disabled have no field.
+	filter := bson.M{"role": domain.RoleAdmin, "disabled": bson.M{"$ne": true}}
+	count, err := collection.CountDocuments(c, filter)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (ur *userRepository) GetUsersByRole(c context.Context, role string) ([]*domain.User, error) {
+	collection := ur.database.Collection(ur.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "username", Value: 1}})
+	cursor, err := collection.Find(c, bson.M{"role": role}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+	var users []*domain.User
+	for cursor.Next(c) {
+		var user domain.User
+		if err := cursor.Decode(&user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, cursor.Err()
+}
+
+func (ur *userRepository) BumpTokenVersion(c context.Context, id string) error {
+	collection := ur.database.Collection(ur.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"id": id}, bson.M{"$inc": bson.M{"tokenversion": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
 }
 
 func (ur *userRepository) UserExists(c context.Context) (bool, error) {
@@ -18,7 +18,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const testUserCollection = "test_users"
+const (
+	testUserCollection       = "test_users"
+	testAdminGuardCollection = "test_admin_guard"
+)
 
 type userRepositoryTestSuite struct {
 	suite.Suite
@@ -30,14 +33,13 @@ type userRepositoryTestSuite struct {
 }
 
 func (s *userRepositoryTestSuite) SetupSuite() {
-	err := godotenv.Load("../.env")
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
+	// The .env file is optional; without a DATABASE_URL the Mongo-backed
+	// suite is skipped and only the in-memory repositories are exercised.
+	_ = godotenv.Load("../.env")
 
 	testMongoURL := os.Getenv("DATABASE_URL")
 	if testMongoURL == "" {
-		log.Fatal("DATABASE_URL environment variable is not set")
+		s.T().Skip("DATABASE_URL environment variable is not set")
 	}
 
 	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(testMongoURL))
@@ -48,12 +50,13 @@ func (s *userRepositoryTestSuite) SetupSuite() {
 	s.client = client
 
 	s.db = client.Database("test_task_db")
-	s.repo = repository.NewUserRepository(s.db, testUserCollection)
+	s.repo = repository.NewUserRepository(s.db, testUserCollection, testAdminGuardCollection)
 	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
 }
 
 func (s *userRepositoryTestSuite) TearDownSuite() {
 	s.db.Collection(testUserCollection).Drop(s.ctx)
+	s.db.Collection(testAdminGuardCollection).Drop(s.ctx)
 	s.cancel()
 	s.client.Disconnect(s.ctx)
 }
@@ -137,9 +140,24 @@ func (s *userRepositoryTestSuite) TestGetAllUsers() {
 	s.repo.CreateUser(s.ctx, &domain.User{ID: "1", Username: "a", Email: "a@a.com", Password: "pw", Role: "user"})
 	s.repo.CreateUser(s.ctx, &domain.User{ID: "2", Username: "b", Email: "b@b.com", Password: "pw", Role: "admin"})
 
-	users, err := s.repo.GetAllUsers(s.ctx, nil)
+	page, err := s.repo.GetAllUsers(s.ctx, domain.UserQuery{})
 	assert.NoError(s.T(), err)
-	assert.Len(s.T(), users, 2)
+	assert.Len(s.T(), page.Users, 2)
+}
+
+func (s *userRepositoryTestSuite) TestUserManagement() {
+	testUserManagement(s.T(), s.repo)
+}
+
+// The guard against racing admin changes needs transactions.
+func (s *userRepositoryTestSuite) TestLastAdmin() {
+	supported, err := repository.MongoSupportsTransactions(s.ctx, s.client)
+	s.Require().NoError(err)
+	if !supported {
+		s.T().Skip("MongoDB server does not support transactions")
+	}
+	s.Require().NoError(repository.EnsureUserIndexes(s.ctx, s.db, testUserCollection, testAdminGuardCollection))
+	testLastAdmin(s.T(), s.repo, repository.NewMongoTransactor(s.client))
 }
 
 func TestUserRepositoryTestSuite(t *testing.T) {
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type webhookRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewWebhookRepository(db *mongo.Database, collection string) domain.WebhookRepository {
+	return &webhookRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureWebhookIndexes makes webhook IDs unique and indexes the event types
+// webhooks subscribe to.
+func EnsureWebhookIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "events", Value: 1}}},
+	})
+	return err
+}
+
+func (wr *webhookRepository) CreateWebhook(c context.Context, webhook *domain.Webhook) error {
+	collection := wr.database.Collection(wr.collection)
+
+	_, err := collection.InsertOne(c, webhook)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrWebhookAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (wr *webhookRepository) GetWebhookByID(c context.Context, webhookId string) (*domain.Webhook, error) {
+	collection := wr.database.Collection(wr.collection)
+
+	var webhook domain.Webhook
+	err := collection.FindOne(c, bson.M{"id": webhookId}).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (wr *webhookRepository) GetWebhooks(c context.Context) ([]*domain.Webhook, error) {
+	return wr.find(c, bson.M{})
+}
+
+func (wr *webhookRepository) GetWebhooksForEvent(c context.Context, eventType string) ([]*domain.Webhook, error) {
+	return wr.find(c, bson.M{
+		"active": true,
+		"events": bson.M{"$in": bson.A{eventType, domain.WebhookAllEvents}},
+	})
+}
+
+func (wr *webhookRepository) find(c context.Context, filter bson.M) ([]*domain.Webhook, error) {
+	collection := wr.database.Collection(wr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}})
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var webhooks []*domain.Webhook
+	for cursor.Next(c) {
+		var webhook domain.Webhook
+		if err := cursor.Decode(&webhook); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+	return webhooks, cursor.Err()
+}
+
+func (wr *webhookRepository) UpdateWebhook(c context.Context, webhook *domain.Webhook) error {
+	collection := wr.database.Collection(wr.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"id": webhook.ID}, bson.M{"$set": bson.M{
+		"url":       webhook.URL,
+		"events":    webhook.Events,
+		"secret":    webhook.Secret,
+		"active":    webhook.Active,
+		"updatedat": webhook.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (wr *webhookRepository) DeleteWebhook(c context.Context, webhookId string) error {
+	collection := wr.database.Collection(wr.collection)
+
+	result, err := collection.DeleteOne(c, bson.M{"id": webhookId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+type webhookDeliveryRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewWebhookDeliveryRepository(db *mongo.Database, collection string) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// EnsureWebhookDeliveryIndexes makes delivery IDs unique and indexes each
+// webhook's log and the pending deliveries dispatchers claim from.
+func EnsureWebhookDeliveryIndexes(c context.Context, db *mongo.Database, collection string) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "webhookid", Value: 1}, {Key: "createdat", Value: -1}, {Key: "id", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdat", Value: 1}, {Key: "id", Value: 1}}},
+	})
+	return err
+}
+
+func (dr *webhookDeliveryRepository) CreateWebhookDelivery(c context.Context, delivery *domain.WebhookDelivery) error {
+	collection := dr.database.Collection(dr.collection)
+
+	_, err := collection.UpdateOne(c, bson.M{"id": delivery.ID}, bson.M{"$setOnInsert": delivery},
+		options.Update().SetUpsert(true))
+	// As with reminder deliveries, the loser of a race to insert the same
+	// delivery sees a duplicate key, which means it is stored.
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+func (dr *webhookDeliveryRepository) GetWebhookDeliveryByID(c context.Context, deliveryId string) (*domain.WebhookDelivery, error) {
+	collection := dr.database.Collection(dr.collection)
+
+	var delivery domain.WebhookDelivery
+	err := collection.FindOne(c, bson.M{"id": deliveryId}).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (dr *webhookDeliveryRepository) GetWebhookDeliveries(c context.Context, webhookId string, limit int) ([]*domain.WebhookDelivery, error) {
+	collection := dr.database.Collection(dr.collection)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdat", Value: -1}, {Key: "id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := collection.Find(c, bson.M{"webhookid": webhookId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var deliveries []*domain.WebhookDelivery
+	for cursor.Next(c) {
+		var delivery domain.WebhookDelivery
+		if err := cursor.Decode(&delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, cursor.Err()
+}
+
+// ClaimWebhookDeliveries claims one delivery at a time; each
+// FindOneAndUpdate is atomic, so a delivery is leased to one claimant only.
+func (dr *webhookDeliveryRepository) ClaimWebhookDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	collection := dr.database.Collection(dr.collection)
+
+	filter := bson.M{
+		"status":     domain.WebhookPending,
+		"leaseuntil": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"leaseowner": owner, "leaseuntil": leaseUntil},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var claimed []*domain.WebhookDelivery
+	for len(claimed) < limit {
+		var delivery domain.WebhookDelivery
+		err := collection.FindOneAndUpdate(c, filter, update, opts).Decode(&delivery)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, &delivery)
+	}
+	return claimed, nil
+}
+
+func (dr *webhookDeliveryRepository) FinishWebhookDelivery(c context.Context, delivery *domain.WebhookDelivery, owner string) error {
+	collection := dr.database.Collection(dr.collection)
+
+	result, err := collection.UpdateOne(c, bson.M{"id": delivery.ID, "leaseowner": owner}, bson.M{"$set": bson.M{
+		"status":         delivery.Status,
+		"attempts":       delivery.Attempts,
+		"leaseowner":     "",
+		"leaseuntil":     delivery.LeaseUntil,
+		"lasterror":      delivery.LastError,
+		"responsestatus": delivery.ResponseStatus,
+		"deliveredat":    delivery.DeliveredAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrWebhookLeaseLost
+	}
+	return nil
+}
+
+func (dr *webhookDeliveryRepository) DeleteWebhookDeliveries(c context.Context, webhookId string) error {
+	collection := dr.database.Collection(dr.collection)
+
+	_, err := collection.DeleteMany(c, bson.M{"webhookid": webhookId})
+	return err
+}
@@ -0,0 +1,215 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWebhooks is the contract every WebhookRepository must meet.
+func testWebhooks(t *testing.T, repo domain.WebhookRepository) {
+	ctx := context.Background()
+	created := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	for i, webhook := range []*domain.Webhook{
+		{ID: "w1", URL: "https://a.example.com/hook", Events: []string{domain.EventTaskCreated, domain.EventTaskDeleted}, Active: true},
+		{ID: "w2", URL: "https://b.example.com/hook", Events: []string{domain.WebhookAllEvents}, Active: true},
+		{ID: "w3", URL: "https://c.example.com/hook", Events: []string{domain.EventTaskCreated}, Active: false},
+		{ID: "w4", URL: "https://d.example.com/hook", Events: []string{domain.EventUserCreated}, Active: true},
+	} {
+		webhook.Secret = "secret-" + webhook.ID
+		webhook.CreatedBy = "admin"
+		webhook.CreatedAt = created.Add(time.Duration(i) * time.Minute)
+		webhook.UpdatedAt = webhook.CreatedAt
+		require.NoError(t, repo.CreateWebhook(ctx, webhook))
+	}
+	assert.ErrorIs(t, repo.CreateWebhook(ctx, &domain.Webhook{ID: "w1", CreatedAt: created}), domain.ErrWebhookAlreadyExists)
+
+	found, err := repo.GetWebhookByID(ctx, "w1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example.com/hook", found.URL)
+	assert.Equal(t, []string{domain.EventTaskCreated, domain.EventTaskDeleted}, found.Events)
+	assert.Equal(t, "secret-w1", found.Secret)
+	assert.True(t, found.Active)
+	assert.Equal(t, "admin", found.CreatedBy)
+	assert.True(t, found.CreatedAt.Equal(created))
+	_, err = repo.GetWebhookByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+
+	ids := func(webhooks []*domain.Webhook, err error) []string {
+		require.NoError(t, err)
+		var ids []string
+		for _, webhook := range webhooks {
+			ids = append(ids, webhook.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"w1", "w2", "w3", "w4"}, ids(repo.GetWebhooks(ctx)))
+	// Inactive webhooks are left out, and "*" matches every event.
+	assert.Equal(t, []string{"w1", "w2"}, ids(repo.GetWebhooksForEvent(ctx, domain.EventTaskCreated)))
+	assert.Equal(t, []string{"w2"}, ids(repo.GetWebhooksForEvent(ctx, domain.EventTaskUpdated)))
+
+	updated := *found
+	updated.URL = "https://a.example.com/v2"
+	updated.Events = []string{domain.EventTaskUpdated}
+	updated.Secret = "rotated"
+	updated.Active = false
+	updated.CreatedBy = "someone-else"
+	updated.UpdatedAt = created.Add(time.Hour)
+	require.NoError(t, repo.UpdateWebhook(ctx, &updated))
+	found, err = repo.GetWebhookByID(ctx, "w1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example.com/v2", found.URL)
+	assert.Equal(t, []string{domain.EventTaskUpdated}, found.Events)
+	assert.Equal(t, "rotated", found.Secret)
+	assert.False(t, found.Active)
+	assert.Equal(t, "admin", found.CreatedBy)
+	assert.True(t, found.UpdatedAt.Equal(created.Add(time.Hour)))
+	assert.Equal(t, []string{"w2"}, ids(repo.GetWebhooksForEvent(ctx, domain.EventTaskCreated)))
+	assert.ErrorIs(t, repo.UpdateWebhook(ctx, &domain.Webhook{ID: "missing"}), domain.ErrWebhookNotFound)
+
+	require.NoError(t, repo.DeleteWebhook(ctx, "w2"))
+	assert.ErrorIs(t, repo.DeleteWebhook(ctx, "w2"), domain.ErrWebhookNotFound)
+	assert.Empty(t, ids(repo.GetWebhooksForEvent(ctx, domain.EventTaskCreated)))
+}
+
+// testWebhookDeliveries is the contract every WebhookDeliveryRepository
+// must meet.
+func testWebhookDeliveries(t *testing.T, repo domain.WebhookDeliveryRepository) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	delivery := func(id, webhookId string, createdAt time.Time) *domain.WebhookDelivery {
+		return &domain.WebhookDelivery{ID: id, WebhookID: webhookId, EventID: "e-" + id, EventType: domain.EventTaskCreated,
+			Payload: `{"id":"e-` + id + `"}`, Status: domain.WebhookPending, CreatedAt: createdAt}
+	}
+	require.NoError(t, repo.CreateWebhookDelivery(ctx, delivery("d2", "w1", now.Add(-time.Minute))))
+	require.NoError(t, repo.CreateWebhookDelivery(ctx, delivery("d1", "w1", now.Add(-time.Hour))))
+	require.NoError(t, repo.CreateWebhookDelivery(ctx, delivery("d3", "w2", now.Add(-time.Second))))
+	// A delivery stored again is left as it was.
+	again := delivery("d1", "w9", now)
+	require.NoError(t, repo.CreateWebhookDelivery(ctx, again))
+
+	found, err := repo.GetWebhookDeliveryByID(ctx, "d1")
+	require.NoError(t, err)
+	assert.Equal(t, "w1", found.WebhookID)
+	assert.Equal(t, `{"id":"e-d1"}`, found.Payload)
+	assert.Nil(t, found.DeliveredAt)
+	_, err = repo.GetWebhookDeliveryByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrWebhookDeliveryNotFound)
+
+	claim := func(owner string, at time.Time, limit int) []string {
+		claimed, err := repo.ClaimWebhookDeliveries(ctx, owner, at, at.Add(time.Minute), limit)
+		require.NoError(t, err)
+		var ids []string
+		for _, d := range claimed {
+			assert.Equal(t, owner, d.LeaseOwner)
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+
+	first, err := repo.ClaimWebhookDeliveries(ctx, "a", now, now.Add(time.Minute), 1)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, "d1", first[0].ID)
+	assert.Equal(t, 1, first[0].Attempts)
+	assert.True(t, first[0].LeaseUntil.Equal(now.Add(time.Minute)))
+
+	// A leased delivery cannot be claimed by anyone else.
+	assert.Equal(t, []string{"d2", "d3"}, claim("b", now, 10))
+	assert.Empty(t, claim("c", now, 10))
+
+	// Only the holder can finish a delivery.
+	deliveredAt := now.Add(time.Second)
+	delivered := *first[0]
+	delivered.Status = domain.WebhookDelivered
+	delivered.ResponseStatus = 204
+	delivered.DeliveredAt = &deliveredAt
+	assert.ErrorIs(t, repo.FinishWebhookDelivery(ctx, &delivered, "b"), domain.ErrWebhookLeaseLost)
+	require.NoError(t, repo.FinishWebhookDelivery(ctx, &delivered, "a"))
+	assert.ErrorIs(t, repo.FinishWebhookDelivery(ctx, &delivered, "a"), domain.ErrWebhookLeaseLost)
+	found, err = repo.GetWebhookDeliveryByID(ctx, "d1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDelivered, found.Status)
+	assert.Equal(t, 204, found.ResponseStatus)
+	require.NotNil(t, found.DeliveredAt)
+	assert.True(t, found.DeliveredAt.Equal(deliveredAt))
+
+	// A failed attempt is retried from LeaseUntil, and a dead one never.
+	later := now.Add(2 * time.Minute)
+	retried := delivery("d2", "w1", now.Add(-time.Minute))
+	retried.Attempts = 1
+	retried.LeaseUntil = later.Add(5 * time.Minute)
+	retried.LastError = "unexpected status 503"
+	retried.ResponseStatus = 503
+	require.NoError(t, repo.FinishWebhookDelivery(ctx, retried, "b"))
+	dead := delivery("d3", "w2", now.Add(-time.Second))
+	dead.Status = domain.WebhookDead
+	dead.Attempts = 1
+	require.NoError(t, repo.FinishWebhookDelivery(ctx, dead, "b"))
+	assert.Empty(t, claim("c", later.Add(4*time.Minute), 10))
+	retry, err := repo.ClaimWebhookDeliveries(ctx, "c", later.Add(5*time.Minute), later.Add(6*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, retry, 1)
+	assert.Equal(t, "d2", retry[0].ID)
+	assert.Equal(t, 2, retry[0].Attempts)
+	assert.Equal(t, "unexpected status 503", retry[0].LastError)
+	assert.Equal(t, 503, retry[0].ResponseStatus)
+
+	log, err := repo.GetWebhookDeliveries(ctx, "w1", 10)
+	require.NoError(t, err)
+	require.Len(t, log, 2)
+	assert.Equal(t, "d2", log[0].ID)
+	assert.Equal(t, "d1", log[1].ID)
+	log, err = repo.GetWebhookDeliveries(ctx, "w1", 1)
+	require.NoError(t, err)
+	assert.Len(t, log, 1)
+
+	require.NoError(t, repo.DeleteWebhookDeliveries(ctx, "w1"))
+	log, err = repo.GetWebhookDeliveries(ctx, "w1", 10)
+	require.NoError(t, err)
+	assert.Empty(t, log)
+	_, err = repo.GetWebhookDeliveryByID(ctx, "d3")
+	assert.NoError(t, err)
+}
+
+func TestInMemoryWebhooks(t *testing.T) {
+	testWebhooks(t, repository.NewInMemoryWebhookRepository())
+	testWebhookDeliveries(t, repository.NewInMemoryWebhookDeliveryRepository())
+}
+
+func TestSQLiteWebhooks(t *testing.T) {
+	open := func(name string) *sql.DB {
+		db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), name))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+
+	testWebhooks(t, repository.NewSQLiteWebhookRepository(open("webhooks.db")))
+	testWebhookDeliveries(t, repository.NewSQLiteWebhookDeliveryRepository(open("deliveries.db")))
+}
+
+func TestMongoWebhooks(t *testing.T) {
+	db := newMongoTestDatabase(t)
+	ctx := context.Background()
+
+	const webhooks, deliveries = "test_webhooks", "test_webhook_deliveries"
+	for _, collection := range []string{webhooks, deliveries} {
+		require.NoError(t, db.Collection(collection).Drop(ctx))
+		t.Cleanup(func() { _ = db.Collection(collection).Drop(context.Background()) })
+	}
+	require.NoError(t, repository.EnsureWebhookIndexes(ctx, db, webhooks))
+	require.NoError(t, repository.EnsureWebhookDeliveryIndexes(ctx, db, deliveries))
+
+	testWebhooks(t, repository.NewWebhookRepository(db, webhooks))
+	testWebhookDeliveries(t, repository.NewWebhookDeliveryRepository(db, deliveries))
+}
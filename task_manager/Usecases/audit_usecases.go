@@ -0,0 +1,210 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type auditUsecases struct {
+	auditRepository domain.AuditRepository
+	contextTimeout  time.Duration
+}
+
+func NewAuditUsecases(auditRepository domain.AuditRepository, contextTimeout time.Duration) domain.AuditUsecases {
+	return &auditUsecases{
+		auditRepository: auditRepository,
+		contextTimeout:  contextTimeout,
+	}
+}
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+func (au *auditUsecases) QueryAuditLog(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, au.contextTimeout)
+	defer cancel()
+
+	if query.Limit < 0 || query.Limit > maxAuditPageSize {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidAuditQuery, maxAuditPageSize)
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultAuditPageSize
+	}
+	if err := checkAuditRange(query); err != nil {
+		return nil, err
+	}
+	return au.auditRepository.GetAuditEntries(ctx, query)
+}
+
+func checkAuditRange(query domain.AuditQuery) error {
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return fmt.Errorf("%w: from must be before to", domain.ErrInvalidAuditQuery)
+	}
+	return nil
+}
+
+// ExportAuditLog reads the log a page at a time, each page with a timeout
+// of its own, so an export may take as long as write needs.
+func (au *auditUsecases) ExportAuditLog(ctx context.Context, query domain.AuditQuery, write func(*domain.AuditEntry) error) error {
+	if err := checkAuditRange(query); err != nil {
+		return err
+	}
+	query.Limit = maxAuditPageSize
+	query.Cursor = ""
+	return au.eachPage(ctx, query, func(entries []*domain.AuditEntry) error {
+		for _, entry := range entries {
+			if err := write(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// VerifyAuditLog checks that the entries are numbered from 1 without gaps,
+// that each links to the hash of the one before, and that each still
+// matches its own hash.
+func (au *auditUsecases) VerifyAuditLog(ctx context.Context) (*domain.AuditVerification, error) {
+	result := &domain.AuditVerification{Valid: true}
+	prevHash := ""
+	err := au.eachPage(ctx, domain.AuditQuery{Limit: maxAuditPageSize}, func(entries []*domain.AuditEntry) error {
+		for _, entry := range entries {
+			result.Entries++
+			var problem string
+			switch {
+			case entry.Sequence != result.Entries:
+				problem = fmt.Sprintf("expected entry %d; entries are missing", result.Entries)
+			case entry.PrevHash != prevHash:
+				problem = "does not link to the hash of the entry before it"
+			case entry.Hash != entry.ComputeHash():
+				problem = "does not match its hash; it was changed after it was written"
+			}
+			if problem != "" {
+				result.Valid = false
+				result.BrokenAt = entry.Sequence
+				result.Problem = problem
+				return errStopPaging
+			}
+			prevHash = entry.Hash
+		}
+		return nil
+	})
+	if err != nil && err != errStopPaging {
+		return nil, err
+	}
+	return result, nil
+}
+
+// errStopPaging ends eachPage early without being an error.
+var errStopPaging = errors.New("stop paging")
+
+func (au *auditUsecases) eachPage(ctx context.Context, query domain.AuditQuery, handle func([]*domain.AuditEntry) error) error {
+	for {
+		pageCtx, cancel := context.WithTimeout(ctx, au.contextTimeout)
+		page, err := au.auditRepository.GetAuditEntries(pageCtx, query)
+		cancel()
+		if err != nil {
+			return err
+		}
+		if err := handle(page.Entries); err != nil {
+			return err
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// recordAudit appends entry, which names the action and its target, to
+// auditLog, filling in the time, the actor and the request it came from.
+// before and after are the target as it was and as it is, or nil; users are
+// recorded without their password, and tokens, webhooks and OIDC clients
+// without their secrets. A nil actor means the user signed in to
+// ctx. It is called in the transaction storing the change, so a failure to
+// record undoes it. A nil auditLog records nothing.
+func recordAudit(ctx context.Context, auditLog domain.AuditRepository, entry *domain.AuditEntry, actor *domain.User, before, after any) error {
+	if auditLog == nil {
+		return nil
+	}
+	entry.ID = uuid.New().String()
+	entry.OccurredAt = time.Now().UTC().Truncate(time.Millisecond)
+	if actor == nil {
+		actor, _ = ctx.Value("user").(*domain.User)
+	}
+	if actor != nil {
+		entry.ActorID = actor.ID
+		entry.ActorName = actor.Username
+	}
+	if entry.IP == "" {
+		entry.IP, _ = ctx.Value(domain.ClientIPContextKey).(string)
+	}
+	entry.RequestID, _ = ctx.Value(domain.RequestIDContextKey).(string)
+	var err error
+	if entry.Before, err = auditSnapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = auditSnapshot(after); err != nil {
+		return err
+	}
+	return auditLog.AppendAuditEntry(ctx, entry)
+}
+
+// isRefusedLogin tells whether a login failed because of what was given
+// or the state of the account, rather than an error of the server.
+func isRefusedLogin(err error) bool {
+	var lockout *domain.LockoutError
+	return errors.Is(err, domain.ErrInvalidCredentials) || errors.Is(err, domain.ErrInvalidTwoFactorCode) ||
+		errors.Is(err, domain.ErrAccountDisabled) || errors.As(err, &lockout)
+}
+
+// recordFailedLogin records a login refused by err, if isRefusedLogin.
+// userID is the account it was for, or empty when there is none. The login
+// has failed already, so a failure to record it is only logged.
+func recordFailedLogin(ctx context.Context, auditLog domain.AuditRepository, email, userID, clientIP string, err error) {
+	if !isRefusedLogin(err) {
+		return
+	}
+	entry := &domain.AuditEntry{
+		ActorName: email, Action: domain.AuditUserLoginFailed, TargetType: domain.AuditTargetUser, TargetID: userID,
+		Reason: err.Error(), IP: clientIP,
+	}
+	if err := recordAudit(ctx, auditLog, entry, nil, nil, nil); err != nil {
+		log.Printf("Audit of failed login for %s: %v", email, err)
+	}
+}
+
+func auditSnapshot(target any) (json.RawMessage, error) {
+	switch target := target.(type) {
+	case nil:
+		return nil, nil
+	case *domain.User:
+		user := *target
+		user.Password = ""
+		return json.Marshal(&user)
+	case *domain.PersonalAccessToken:
+		token := *target
+		token.TokenHash = ""
+		return json.Marshal(&token)
+	case *domain.Webhook:
+		webhook := *target
+		webhook.Secret = ""
+		return json.Marshal(&webhook)
+	case *domain.OIDCClient:
+		client := *target
+		client.SecretHash = ""
+		return json.Marshal(&client)
+	default:
+		return json.Marshal(target)
+	}
+}
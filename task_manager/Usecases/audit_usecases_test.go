@@ -0,0 +1,545 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	taskUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// tamperedAuditLog hands out the entries of an AuditRepository after
+// passing them through tamper, standing in for a store edited behind the
+// application's back.
+type tamperedAuditLog struct {
+	domain.AuditRepository
+	tamper func([]*domain.AuditEntry) []*domain.AuditEntry
+}
+
+func (tl *tamperedAuditLog) GetAuditEntries(c context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	page, err := tl.AuditRepository.GetAuditEntries(c, query)
+	if err != nil {
+		return nil, err
+	}
+	page.Entries = tl.tamper(page.Entries)
+	return page, nil
+}
+
+type AuditUsecaseSuite struct {
+	suite.Suite
+	auditLog domain.AuditRepository
+	tasks    domain.TaskRepository
+	taskUC   domain.TaskUsecases
+	auditUC  domain.AuditUsecases
+	ctx      context.Context
+}
+
+func (s *AuditUsecaseSuite) SetupTest() {
+	s.auditLog = repository.NewInMemoryAuditRepository()
+	s.tasks = repository.NewInMemoryTaskRepository()
+	s.taskUC = taskUsecases.NewTaskUsecases(s.tasks, repository.NewInMemoryCommentRepository(), repository.NewInMemoryLabelRepository(),
+		repository.NewInMemoryDependencyRepository(), domain.DefaultWorkflow(), domain.DefaultSubtaskRules(), nil, nil, s.auditLog, 2*time.Second)
+	s.auditUC = taskUsecases.NewAuditUsecases(s.auditLog, 2*time.Second)
+
+	// What the request middleware and the auth middleware leave in a
+	// request's context.
+	ctx := context.WithValue(context.Background(), domain.RequestIDContextKey, "req-1")
+	ctx = context.WithValue(ctx, domain.ClientIPContextKey, "192.0.2.1")
+	s.ctx = context.WithValue(ctx, "user", owner.User)
+}
+
+func TestAuditUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(AuditUsecaseSuite))
+}
+
+// appendEntries records n task creations directly in the audit log.
+func (s *AuditUsecaseSuite) appendEntries(n int) {
+	for i := 0; i < n; i++ {
+		require.NoError(s.T(), s.auditLog.AppendAuditEntry(s.ctx, &domain.AuditEntry{
+			ID: fmt.Sprintf("e%d", i), OccurredAt: time.Now().UTC().Truncate(time.Millisecond), ActorID: "user-id",
+			Action: domain.AuditTaskCreate, TargetType: domain.AuditTargetTask, TargetID: fmt.Sprintf("t%d", i),
+		}))
+	}
+}
+
+func (s *AuditUsecaseSuite) TestTaskChanges_AreRecorded() {
+	task := &domain.Task{Title: "Write report"}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, task, "user-id"))
+	_, err := s.taskUC.UpdateTask(s.ctx, task.ID, &domain.Task{Title: "Send report"}, owner)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.taskUC.DeleteTask(s.ctx, task.ID, owner))
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetTask, TargetID: task.ID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 3)
+	created, updated, deleted := page.Entries[0], page.Entries[1], page.Entries[2]
+
+	assert.Equal(s.T(), domain.AuditTaskCreate, created.Action)
+	assert.Empty(s.T(), created.Before)
+	assert.Contains(s.T(), string(created.After), `"Title":"Write report"`)
+	assert.Equal(s.T(), domain.AuditTaskUpdate, updated.Action)
+	assert.Contains(s.T(), string(updated.Before), `"Title":"Write report"`)
+	assert.Contains(s.T(), string(updated.After), `"Title":"Send report"`)
+	assert.Equal(s.T(), domain.AuditTaskDelete, deleted.Action)
+	assert.Contains(s.T(), string(deleted.Before), `"Title":"Send report"`)
+	assert.Empty(s.T(), deleted.After)
+	for _, entry := range page.Entries {
+		assert.Equal(s.T(), "user-id", entry.ActorID)
+		assert.Equal(s.T(), "192.0.2.1", entry.IP)
+		assert.Equal(s.T(), "req-1", entry.RequestID)
+	}
+}
+
+func (s *AuditUsecaseSuite) TestInPlaceChanges_AreRecorded() {
+	task := &domain.Task{Title: "Write report"}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, task, "user-id"))
+	_, err := s.taskUC.AddChecklistItem(s.ctx, task.ID, "Outline", owner)
+	require.NoError(s.T(), err)
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetTask, TargetID: task.ID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 2)
+	updated := page.Entries[1]
+	assert.Equal(s.T(), domain.AuditTaskUpdate, updated.Action)
+	assert.Equal(s.T(), "user-id", updated.ActorID)
+	assert.NotContains(s.T(), string(updated.Before), "Outline")
+	assert.Contains(s.T(), string(updated.After), `"Text":"Outline"`)
+}
+
+func (s *AuditUsecaseSuite) TestCascadedSubtaskChanges_AreRecorded() {
+	taskUC := taskUsecases.NewTaskUsecases(s.tasks, repository.NewInMemoryCommentRepository(), repository.NewInMemoryLabelRepository(),
+		repository.NewInMemoryDependencyRepository(), domain.DefaultWorkflow(),
+		domain.SubtaskRules{OnDelete: domain.SubtasksDelete, OnComplete: domain.SubtasksComplete}, nil, nil, s.auditLog, 2*time.Second)
+	parent := &domain.Task{Title: "Release"}
+	require.NoError(s.T(), taskUC.CreateTask(s.ctx, parent, "user-id"))
+	completed := &domain.Task{Title: "Tag", ParentID: parent.ID}
+	require.NoError(s.T(), taskUC.CreateTask(s.ctx, completed, "user-id"))
+
+	_, err := taskUC.UpdateTask(s.ctx, parent.ID, &domain.Task{Title: "Release", Status: domain.StatusInProgress}, owner)
+	require.NoError(s.T(), err)
+	_, err = taskUC.UpdateTask(s.ctx, parent.ID, &domain.Task{Title: "Release", Status: domain.StatusDone}, owner)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), taskUC.DeleteTask(s.ctx, parent.ID, owner))
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetTask, TargetID: completed.ID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 3)
+	assert.Equal(s.T(), domain.AuditTaskCreate, page.Entries[0].Action)
+	assert.Equal(s.T(), domain.AuditTaskUpdate, page.Entries[1].Action)
+	assert.Contains(s.T(), string(page.Entries[1].After), `"Status":"done"`)
+	assert.Equal(s.T(), domain.AuditTaskDelete, page.Entries[2].Action)
+	for _, entry := range page.Entries {
+		assert.Equal(s.T(), "user-id", entry.ActorID)
+	}
+}
+
+func (s *AuditUsecaseSuite) TestWebhookChanges_AreRecorded() {
+	webhookUC := taskUsecases.NewWebhookUsecases(repository.NewInMemoryWebhookRepository(), repository.NewInMemoryWebhookDeliveryRepository(),
+		nil, s.auditLog, 2*time.Second)
+	created, err := webhookUC.CreateWebhook(s.ctx, "https://hooks.example.com/tasks", []string{domain.EventTaskCreated}, "", owner)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), webhookUC.DeleteWebhook(s.ctx, created.Webhook.ID))
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetWebhook})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 2)
+	assert.Equal(s.T(), domain.AuditWebhookCreate, page.Entries[0].Action)
+	assert.Equal(s.T(), domain.AuditWebhookDelete, page.Entries[1].Action)
+	for _, entry := range page.Entries {
+		assert.Equal(s.T(), "user-id", entry.ActorID)
+		// The signing secret never reaches the log.
+		assert.NotContains(s.T(), string(entry.Before)+string(entry.After), created.Secret)
+	}
+}
+
+func (s *AuditUsecaseSuite) TestNextOccurrence_IsRecorded() {
+	task := &domain.Task{
+		Title:      "weekly report",
+		DueDate:    time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC),
+		Recurrence: &domain.Recurrence{Rule: "FREQ=WEEKLY", TimeZone: "UTC"},
+	}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, task, "user-id"))
+	for _, status := range []string{domain.StatusInProgress, domain.StatusDone} {
+		_, err := s.taskUC.UpdateTask(s.ctx, task.ID, &domain.Task{Title: task.Title, DueDate: task.DueDate, Status: status}, owner)
+		require.NoError(s.T(), err)
+	}
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetTask})
+	require.NoError(s.T(), err)
+	var created []*domain.AuditEntry
+	for _, entry := range page.Entries {
+		if entry.Action == domain.AuditTaskCreate {
+			created = append(created, entry)
+		}
+	}
+	require.Len(s.T(), created, 2)
+	assert.NotEqual(s.T(), task.ID, created[1].TargetID)
+	assert.Equal(s.T(), "user-id", created[1].ActorID)
+	assert.Contains(s.T(), string(created[1].After), `"Title":"weekly report"`)
+}
+
+func (s *AuditUsecaseSuite) TestBlockerChanges_AreRecorded() {
+	task := &domain.Task{Title: "Ship"}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, task, "user-id"))
+	blocker := &domain.Task{Title: "Test"}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, blocker, "user-id"))
+
+	require.NoError(s.T(), s.taskUC.AddTaskBlocker(s.ctx, task.ID, blocker.ID, owner))
+	// Linking them again and unlinking twice changes nothing more.
+	require.NoError(s.T(), s.taskUC.AddTaskBlocker(s.ctx, task.ID, blocker.ID, owner))
+	require.NoError(s.T(), s.taskUC.RemoveTaskBlocker(s.ctx, task.ID, blocker.ID, owner))
+	require.NoError(s.T(), s.taskUC.RemoveTaskBlocker(s.ctx, task.ID, blocker.ID, owner))
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetTask, TargetID: task.ID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 3)
+	added, removed := page.Entries[1], page.Entries[2]
+	assert.Equal(s.T(), domain.AuditTaskBlockerAdd, added.Action)
+	assert.Contains(s.T(), string(added.After), blocker.ID)
+	assert.Equal(s.T(), domain.AuditTaskBlockerRemove, removed.Action)
+	assert.Contains(s.T(), string(removed.Before), blocker.ID)
+	assert.Empty(s.T(), removed.After)
+}
+
+func (s *AuditUsecaseSuite) TestCommentChanges_AreRecorded() {
+	commentUC := taskUsecases.NewCommentUsecases(repository.NewInMemoryCommentRepository(), s.tasks, nil, s.auditLog, 2*time.Second)
+	task := &domain.Task{Title: "Write report"}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, task, "user-id"))
+
+	comment, err := commentUC.AddComment(s.ctx, task.ID, "", "First draft is up", owner)
+	require.NoError(s.T(), err)
+	_, err = commentUC.EditComment(s.ctx, task.ID, comment.ID, "Second draft is up", owner)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), commentUC.DeleteComment(s.ctx, task.ID, comment.ID, owner))
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetComment, TargetID: comment.ID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 3)
+	created, edited, deleted := page.Entries[0], page.Entries[1], page.Entries[2]
+	assert.Equal(s.T(), domain.AuditCommentCreate, created.Action)
+	assert.Contains(s.T(), string(created.After), "First draft")
+	assert.Equal(s.T(), domain.AuditCommentEdit, edited.Action)
+	assert.Contains(s.T(), string(edited.Before), "First draft")
+	assert.Contains(s.T(), string(edited.After), "Second draft")
+	assert.Equal(s.T(), domain.AuditCommentDelete, deleted.Action)
+	assert.Empty(s.T(), deleted.After)
+	for _, entry := range page.Entries {
+		assert.Equal(s.T(), "user-id", entry.ActorID)
+		assert.Equal(s.T(), "req-1", entry.RequestID)
+	}
+}
+
+func (s *AuditUsecaseSuite) TestLabelChanges_AreRecorded() {
+	labelUC := taskUsecases.NewLabelUsecases(repository.NewInMemoryLabelRepository(), s.tasks, nil, s.auditLog, 2*time.Second)
+	label, err := labelUC.CreateLabel(s.ctx, "urgent", "", owner)
+	require.NoError(s.T(), err)
+	name := "critical"
+	_, err = labelUC.UpdateLabel(s.ctx, label.ID, &name, nil, owner)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), labelUC.DeleteLabel(s.ctx, label.ID, owner))
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetLabel, TargetID: label.ID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 3)
+	created, updated, deleted := page.Entries[0], page.Entries[1], page.Entries[2]
+	assert.Equal(s.T(), domain.AuditLabelCreate, created.Action)
+	assert.Equal(s.T(), domain.AuditLabelUpdate, updated.Action)
+	assert.Contains(s.T(), string(updated.Before), `"Name":"urgent"`)
+	assert.Contains(s.T(), string(updated.After), `"Name":"critical"`)
+	assert.Equal(s.T(), domain.AuditLabelDelete, deleted.Action)
+	assert.Contains(s.T(), string(deleted.Before), `"Name":"critical"`)
+}
+
+func (s *AuditUsecaseSuite) TestReminderChanges_AreRecorded() {
+	reminderUC := taskUsecases.NewReminderUsecases(repository.NewInMemoryReminderRepository(), []string{domain.ReminderChannelEmail}, nil, s.auditLog, 2*time.Second)
+	reminder, err := reminderUC.CreateReminder(s.ctx, time.Hour, "", owner)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), reminderUC.DeleteReminder(s.ctx, reminder.ID, owner))
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{TargetType: domain.AuditTargetReminder, TargetID: reminder.ID})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 2)
+	assert.Equal(s.T(), domain.AuditReminderCreate, page.Entries[0].Action)
+	assert.Equal(s.T(), domain.AuditReminderDelete, page.Entries[1].Action)
+	for _, entry := range page.Entries {
+		assert.Equal(s.T(), "user-id", entry.ActorID)
+	}
+}
+
+func (s *AuditUsecaseSuite) TestTaskChanges_NotRecordedWhenRefused() {
+	task := &domain.Task{ID: "t1", UserID: "other-user", Title: "Not yours"}
+	require.NoError(s.T(), s.tasks.CreateTask(s.ctx, task))
+
+	assert.ErrorIs(s.T(), s.taskUC.DeleteTask(s.ctx, "t1", owner), domain.ErrTaskNotFound)
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{})
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), page.Entries)
+}
+
+func (s *AuditUsecaseSuite) TestQueryAuditLog_Limits() {
+	s.appendEntries(60)
+
+	page, err := s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{})
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), page.Entries, 50)
+	assert.NotEmpty(s.T(), page.NextCursor)
+
+	_, err = s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{Limit: 501})
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidAuditQuery)
+	now := time.Now()
+	_, err = s.auditUC.QueryAuditLog(s.ctx, domain.AuditQuery{From: now, To: now.Add(-time.Hour)})
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidAuditQuery)
+}
+
+func (s *AuditUsecaseSuite) TestExportAuditLog_WritesEveryPage() {
+	s.appendEntries(501)
+
+	var ids []string
+	err := s.auditUC.ExportAuditLog(s.ctx, domain.AuditQuery{Limit: 10}, func(entry *domain.AuditEntry) error {
+		ids = append(ids, entry.ID)
+		return nil
+	})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), ids, 501)
+	assert.Equal(s.T(), "e0", ids[0])
+	assert.Equal(s.T(), "e500", ids[500])
+
+	ids = nil
+	err = s.auditUC.ExportAuditLog(s.ctx, domain.AuditQuery{TargetID: "t7"}, func(entry *domain.AuditEntry) error {
+		ids = append(ids, entry.ID)
+		return nil
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"e7"}, ids)
+}
+
+func (s *AuditUsecaseSuite) TestVerifyAuditLog_Valid() {
+	s.appendEntries(501)
+
+	result, err := s.auditUC.VerifyAuditLog(s.ctx)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), &domain.AuditVerification{Entries: 501, Valid: true}, result)
+}
+
+func (s *AuditUsecaseSuite) TestVerifyAuditLog_DetectsTampering() {
+	s.appendEntries(5)
+
+	cases := map[string]struct {
+		tamper   func([]*domain.AuditEntry) []*domain.AuditEntry
+		brokenAt int64
+		problem  string
+	}{
+		"edited": {
+			tamper: func(entries []*domain.AuditEntry) []*domain.AuditEntry {
+				entries[2].ActorID = "someone-else"
+				return entries
+			},
+			brokenAt: 3,
+			problem:  "does not match its hash",
+		},
+		"edited and rehashed": {
+			tamper: func(entries []*domain.AuditEntry) []*domain.AuditEntry {
+				entries[2].After = json.RawMessage(`{"Title":"Forged"}`)
+				entries[2].Hash = entries[2].ComputeHash()
+				return entries
+			},
+			brokenAt: 4,
+			problem:  "does not link",
+		},
+		"removed": {
+			tamper: func(entries []*domain.AuditEntry) []*domain.AuditEntry {
+				return append(entries[:1], entries[2:]...)
+			},
+			brokenAt: 3,
+			problem:  "entries are missing",
+		},
+	}
+	for name, tc := range cases {
+		s.Run(name, func() {
+			auditUC := taskUsecases.NewAuditUsecases(&tamperedAuditLog{AuditRepository: s.auditLog, tamper: tc.tamper}, 2*time.Second)
+			result, err := auditUC.VerifyAuditLog(s.ctx)
+			require.NoError(s.T(), err)
+			assert.False(s.T(), result.Valid)
+			assert.Equal(s.T(), tc.brokenAt, result.BrokenAt)
+			assert.Contains(s.T(), result.Problem, tc.problem)
+		})
+	}
+}
+
+func TestLogin_RecordsSuccessAndFailure(t *testing.T) {
+	users := new(mocks.UserRepository)
+	passwords := new(mocks.IPasswordService)
+	tokens := new(mocks.TokenUsecases)
+	twoFactor := new(mocks.TwoFactorUsecases)
+	auditLog := repository.NewInMemoryAuditRepository()
+	uc := taskUsecases.NewUserUsecases(users, nil, nil, nil, passwords, tokens, twoFactor, repository.NewInMemoryLoginAttemptRepository(),
+		testAccountLockout, testIPLockout, nil, nil, auditLog, time.Second)
+	ctx := context.WithValue(context.Background(), domain.RequestIDContextKey, "req-1")
+
+	user := &domain.User{ID: "u1", Username: "john", Email: "john@example.com", Password: "hashed"}
+	users.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	users.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(nil, domain.ErrUserNotFound)
+	passwords.On("VerifyPassword", user, "secret").Return(true)
+	passwords.On("VerifyPassword", user, "wrong").Return(false)
+	twoFactor.On("BeginLogin", mock.Anything, user).Return(nil, nil)
+	tokens.On("IssueTokens", mock.Anything, user).Return(&domain.TokenPair{AccessToken: "jwt"}, nil)
+
+	_, err := uc.Login(ctx, "john@example.com", "wrong", "192.0.2.1")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	_, err = uc.Login(ctx, "nobody@example.com", "secret", "192.0.2.1")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	_, err = uc.Login(ctx, "john@example.com", "secret", "192.0.2.1")
+	require.NoError(t, err)
+
+	page, err := auditLog.GetAuditEntries(ctx, domain.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+
+	wrongPassword, unknown, success := page.Entries[0], page.Entries[1], page.Entries[2]
+	assert.Equal(t, domain.AuditUserLoginFailed, wrongPassword.Action)
+	assert.Empty(t, wrongPassword.ActorID)
+	assert.Equal(t, "john@example.com", wrongPassword.ActorName)
+	assert.Equal(t, "u1", wrongPassword.TargetID)
+	assert.Equal(t, domain.ErrInvalidCredentials.Error(), wrongPassword.Reason)
+	assert.Equal(t, "192.0.2.1", wrongPassword.IP)
+	assert.Equal(t, "req-1", wrongPassword.RequestID)
+
+	assert.Equal(t, domain.AuditUserLoginFailed, unknown.Action)
+	assert.Empty(t, unknown.TargetID)
+
+	assert.Equal(t, domain.AuditUserLogin, success.Action)
+	assert.Equal(t, "u1", success.ActorID)
+	assert.Equal(t, "u1", success.TargetID)
+	assert.Empty(t, success.Reason)
+}
+
+func TestCreateAndPromoteUser_AreRecordedWithoutPasswords(t *testing.T) {
+	users := repository.NewInMemoryUserRepository()
+	passwords := new(mocks.IPasswordService)
+	tokens := new(mocks.TokenUsecases)
+	auditLog := repository.NewInMemoryAuditRepository()
+	uc := taskUsecases.NewUserUsecases(users, nil, nil, nil, passwords, tokens, nil, repository.NewInMemoryLoginAttemptRepository(),
+		testAccountLockout, testIPLockout, nil, nil, auditLog, time.Second)
+	ctx := context.Background()
+
+	passwords.On("HashPassword", mock.Anything).Return("hashed", nil)
+	tokens.On("ForgetTokenVersion", mock.Anything).Return()
+	admin, err := uc.CreateUser(ctx, &domain.User{Username: "admin", Email: "admin@example.com", Password: "secret"})
+	require.NoError(t, err)
+	user, err := uc.CreateUser(ctx, &domain.User{Username: "john", Email: "john@example.com", Password: "secret"})
+	require.NoError(t, err)
+	require.NoError(t, uc.PromoteUserToAdmin(context.WithValue(ctx, "user", admin), user.ID))
+
+	page, err := auditLog.GetAuditEntries(ctx, domain.AuditQuery{TargetID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	created, promoted := page.Entries[0], page.Entries[1]
+
+	// Someone signing up is their own actor.
+	assert.Equal(t, domain.AuditUserCreate, created.Action)
+	assert.Equal(t, user.ID, created.ActorID)
+	assert.NotContains(t, string(created.After), "hashed")
+
+	assert.Equal(t, domain.AuditUserPromote, promoted.Action)
+	assert.Equal(t, admin.ID, promoted.ActorID)
+	assert.Contains(t, string(promoted.Before), `"Role":"user"`)
+	assert.Contains(t, string(promoted.After), `"Role":"admin"`)
+	assert.NotContains(t, string(promoted.Before), "hashed")
+}
+
+func TestDemoteAndDisableUser_AreRecorded(t *testing.T) {
+	users := repository.NewInMemoryUserRepository()
+	passwords := new(mocks.IPasswordService)
+	tokens := new(mocks.TokenUsecases)
+	auditLog := repository.NewInMemoryAuditRepository()
+	uc := taskUsecases.NewUserUsecases(users, nil, nil, nil, passwords, tokens, nil, repository.NewInMemoryLoginAttemptRepository(),
+		testAccountLockout, testIPLockout, nil, nil, auditLog, time.Second)
+	ctx := context.Background()
+
+	passwords.On("HashPassword", mock.Anything).Return("hashed", nil)
+	tokens.On("ForgetTokenVersion", mock.Anything).Return()
+	admin, err := uc.CreateUser(ctx, &domain.User{Username: "admin", Email: "admin@example.com", Password: "secret"})
+	require.NoError(t, err)
+	user, err := uc.CreateUser(ctx, &domain.User{Username: "john", Email: "john@example.com", Password: "secret"})
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, "user", admin)
+	require.NoError(t, uc.PromoteUserToAdmin(ctx, user.ID))
+	require.NoError(t, uc.DemoteUser(ctx, user.ID))
+	require.NoError(t, uc.SetUserDisabled(ctx, user.ID, true))
+
+	page, err := auditLog.GetAuditEntries(ctx, domain.AuditQuery{TargetID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 4)
+	demoted, disabled := page.Entries[2], page.Entries[3]
+
+	assert.Equal(t, domain.AuditUserDemote, demoted.Action)
+	assert.Equal(t, admin.ID, demoted.ActorID)
+	assert.Contains(t, string(demoted.Before), `"Role":"admin"`)
+	assert.Contains(t, string(demoted.After), `"Role":"user"`)
+	assert.Equal(t, domain.AuditUserDisable, disabled.Action)
+	assert.Equal(t, admin.ID, disabled.ActorID)
+	assert.Contains(t, string(disabled.After), `"Disabled":true`)
+}
+
+func TestLogin_NoTokensWhenNotRecorded(t *testing.T) {
+	users := new(mocks.UserRepository)
+	passwords := new(mocks.IPasswordService)
+	tokens := new(mocks.TokenUsecases)
+	twoFactor := new(mocks.TwoFactorUsecases)
+	auditLog := new(mocks.AuditRepository)
+	uc := taskUsecases.NewUserUsecases(users, nil, nil, nil, passwords, tokens, twoFactor, repository.NewInMemoryLoginAttemptRepository(),
+		testAccountLockout, testIPLockout, nil, nil, auditLog, time.Second)
+
+	user := &domain.User{ID: "u1", Email: "john@example.com", Password: "hashed"}
+	users.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	passwords.On("VerifyPassword", user, "secret").Return(true)
+	twoFactor.On("BeginLogin", mock.Anything, user).Return(nil, nil)
+	auditLog.On("AppendAuditEntry", mock.Anything, mock.Anything).Return(errors.New("audit log unavailable"))
+
+	_, err := uc.Login(context.Background(), "john@example.com", "secret", "192.0.2.1")
+	assert.Error(t, err)
+	// A sign-in that could not be recorded hands out no tokens.
+	tokens.AssertNotCalled(t, "IssueTokens", mock.Anything, mock.Anything)
+}
+
+func TestVerifyEmail_IsRecorded(t *testing.T) {
+	users := repository.NewInMemoryUserRepository()
+	signer := new(mocks.IVerificationTokenService)
+	tokens := new(mocks.TokenUsecases)
+	auditLog := repository.NewInMemoryAuditRepository()
+	uc := taskUsecases.NewEmailVerificationUsecases(signer, users, nil, tokens, "http://localhost:8080/verify", nil, auditLog, time.Second)
+	// The link is opened without signing in.
+	ctx := context.WithValue(context.Background(), domain.RequestIDContextKey, "req-1")
+
+	_, err := users.CreateUser(ctx, &domain.User{ID: "u1", Username: "john", Email: "john@example.com", Password: "hashed", EmailUnverified: true})
+	require.NoError(t, err)
+	signer.On("ParseVerificationToken", "good").Return(&domain.VerificationClaims{UserID: "u1", Email: "john@example.com"}, nil)
+	tokens.On("ForgetTokenVersion", "u1").Return()
+
+	require.NoError(t, uc.VerifyEmail(ctx, "good"))
+	// A second click changes nothing.
+	require.NoError(t, uc.VerifyEmail(ctx, "good"))
+
+	page, err := auditLog.GetAuditEntries(ctx, domain.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	entry := page.Entries[0]
+	assert.Equal(t, domain.AuditEmailVerify, entry.Action)
+	assert.Equal(t, "u1", entry.ActorID)
+	assert.Equal(t, "u1", entry.TargetID)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.Contains(t, string(entry.Before), `"EmailUnverified":true`)
+	assert.NotContains(t, string(entry.After), "hashed")
+}
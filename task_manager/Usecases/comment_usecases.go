@@ -0,0 +1,209 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type commentUsecases struct {
+	commentRepository domain.CommentRepository
+	taskRepository    domain.TaskRepository
+	transactor        domain.Transactor
+	auditLog          domain.AuditRepository
+	contextTimeout    time.Duration
+}
+
+// NewCommentUsecases records new, edited and deleted comments in auditLog,
+// in the same transaction of transactor as the change; either may be nil.
+func NewCommentUsecases(commentRepository domain.CommentRepository, taskRepository domain.TaskRepository, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.CommentUsecases {
+	return &commentUsecases{
+		commentRepository: commentRepository,
+		taskRepository:    taskRepository,
+		transactor:        transactor,
+		auditLog:          auditLog,
+		contextTimeout:    contextTimeout,
+	}
+}
+
+// ListComments drops tombstones that no remaining comment replies to.
+// Replies are always newer than what they answer, so walking from the
+// newest comment back marks every tombstone that is still needed before it
+// is reached.
+func (cu *commentUsecases) ListComments(ctx context.Context, taskId string, actor *domain.Actor) ([]*domain.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, cu.contextTimeout)
+	defer cancel()
+
+	if _, err := cu.getTask(ctx, taskId, actor); err != nil {
+		return nil, err
+	}
+	comments, err := cu.commentRepository.GetCommentsByTask(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	replied := make(map[string]bool)
+	kept := make([]*domain.Comment, 0, len(comments))
+	for i := len(comments) - 1; i >= 0; i-- {
+		comment := comments[i]
+		if comment.Deleted() && !replied[comment.ID] {
+			continue
+		}
+		if comment.ParentID != "" {
+			replied[comment.ParentID] = true
+		}
+		kept = append(kept, comment)
+	}
+	slices.Reverse(kept)
+	return kept, nil
+}
+
+func (cu *commentUsecases) AddComment(ctx context.Context, taskId string, parentId string, body string, actor *domain.Actor) (*domain.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, cu.contextTimeout)
+	defer cancel()
+
+	if _, err := cu.getTask(ctx, taskId, actor); err != nil {
+		return nil, err
+	}
+	body, err := validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if parentId != "" {
+		parent, err := cu.commentRepository.GetCommentByID(ctx, parentId)
+		if err == domain.ErrCommentNotFound || (err == nil && (parent.TaskID != taskId || parent.Deleted())) {
+			return nil, fmt.Errorf("%w: the comment being replied to does not exist", domain.ErrInvalidComment)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	comment := &domain.Comment{
+		ID:        uuid.New().String(),
+		TaskID:    taskId,
+		ParentID:  parentId,
+		AuthorID:  actor.User.ID,
+		Body:      body,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = inTransaction(ctx, cu.transactor, func(ctx context.Context) error {
+		if err := cu.commentRepository.CreateComment(ctx, comment); err != nil {
+			return err
+		}
+		return recordAudit(ctx, cu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditCommentCreate, TargetType: domain.AuditTargetComment, TargetID: comment.ID,
+		}, actor.User, nil, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// EditComment is reserved to the author; moderators can delete a comment
+// but not put words in someone's mouth.
+func (cu *commentUsecases) EditComment(ctx context.Context, taskId string, commentId string, body string, actor *domain.Actor) (*domain.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, cu.contextTimeout)
+	defer cancel()
+
+	comment, err := cu.getComment(ctx, taskId, commentId, actor)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != actor.User.ID {
+		return nil, domain.ErrNotCommentAuthor
+	}
+	body, err = validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if body == comment.Body {
+		return comment, nil
+	}
+	var edited *domain.Comment
+	err = inTransaction(ctx, cu.transactor, func(ctx context.Context) error {
+		var err error
+		if edited, err = cu.commentRepository.EditComment(ctx, commentId, body, time.Now().UTC()); err != nil {
+			return err
+		}
+		return recordAudit(ctx, cu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditCommentEdit, TargetType: domain.AuditTargetComment, TargetID: commentId,
+		}, actor.User, comment, edited)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return edited, nil
+}
+
+func (cu *commentUsecases) DeleteComment(ctx context.Context, taskId string, commentId string, actor *domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, cu.contextTimeout)
+	defer cancel()
+
+	comment, err := cu.getComment(ctx, taskId, commentId, actor)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != actor.User.ID && !actor.Can(domain.PermCommentModerate) {
+		return domain.ErrNotCommentAuthor
+	}
+	return inTransaction(ctx, cu.transactor, func(ctx context.Context) error {
+		if err := cu.commentRepository.DeleteComment(ctx, commentId, actor.User.ID, time.Now().UTC()); err != nil {
+			return err
+		}
+		return recordAudit(ctx, cu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditCommentDelete, TargetType: domain.AuditTargetComment, TargetID: commentId,
+		}, actor.User, comment, nil)
+	})
+}
+
+// getTask loads a task and checks the actor may read it. Comments are
+// visible to, and may be written by, everyone who can see the task.
+func (cu *commentUsecases) getTask(ctx context.Context, taskId string, actor *domain.Actor) (*domain.Task, error) {
+	task, err := cu.taskRepository.GetTaskByID(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := authorizeTaskOwner(actor, task.UserID, domain.PermTaskReadAny); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// getComment loads a live comment of a task the actor can read. A comment
+// addressed through another task's URL does not exist.
+func (cu *commentUsecases) getComment(ctx context.Context, taskId string, commentId string, actor *domain.Actor) (*domain.Comment, error) {
+	if _, err := cu.getTask(ctx, taskId, actor); err != nil {
+		return nil, err
+	}
+	comment, err := cu.commentRepository.GetCommentByID(ctx, commentId)
+	if err != nil {
+		return nil, err
+	}
+	if comment.TaskID != taskId || comment.Deleted() {
+		return nil, domain.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body is required", domain.ErrInvalidComment)
+	}
+	if utf8.RuneCountInString(body) > domain.MaxCommentLength {
+		return "", fmt.Errorf("%w: body must be at most %d characters", domain.ErrInvalidComment, domain.MaxCommentLength)
+	}
+	return body, nil
+}
@@ -0,0 +1,163 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	commentUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CommentUsecaseSuite struct {
+	suite.Suite
+	commentRepo *mocks.CommentRepository
+	taskRepo    *mocks.TaskRepository
+	commentUC   domain.CommentUsecases
+}
+
+func (s *CommentUsecaseSuite) SetupTest() {
+	s.commentRepo = new(mocks.CommentRepository)
+	s.taskRepo = new(mocks.TaskRepository)
+	s.commentUC = commentUsecases.NewCommentUsecases(s.commentRepo, s.taskRepo, nil, nil, 2*time.Second)
+	s.taskRepo.On("GetTaskByID", mock.Anything, "task-1").Return(&domain.Task{ID: "task-1", UserID: "user-id"}, nil).Maybe()
+}
+
+func TestCommentUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(CommentUsecaseSuite))
+}
+
+func (s *CommentUsecaseSuite) TestAddComment() {
+	assert := assert.New(s.T())
+	s.commentRepo.On("CreateComment", mock.Anything, mock.MatchedBy(func(c *domain.Comment) bool {
+		return c.ID != "" && c.TaskID == "task-1" && c.ParentID == "" && c.AuthorID == "user-id" && c.Body == "Looks good"
+	})).Return(nil).Once()
+
+	comment, err := s.commentUC.AddComment(context.Background(), "task-1", "", "  Looks good \n", owner)
+
+	assert.NoError(err)
+	assert.Equal("Looks good", comment.Body)
+	assert.WithinDuration(time.Now(), comment.CreatedAt, time.Minute)
+	s.commentRepo.AssertExpectations(s.T())
+}
+
+func (s *CommentUsecaseSuite) TestAddComment_Reply() {
+	s.commentRepo.On("GetCommentByID", mock.Anything, "parent").Return(&domain.Comment{ID: "parent", TaskID: "task-1"}, nil).Once()
+	s.commentRepo.On("CreateComment", mock.Anything, mock.MatchedBy(func(c *domain.Comment) bool {
+		return c.ParentID == "parent"
+	})).Return(nil).Once()
+
+	_, err := s.commentUC.AddComment(context.Background(), "task-1", "parent", "Agreed", owner)
+
+	assert.NoError(s.T(), err)
+	s.commentRepo.AssertExpectations(s.T())
+}
+
+func (s *CommentUsecaseSuite) TestAddComment_ReplyToUnusableParent() {
+	deletedAt := time.Now()
+	s.commentRepo.On("GetCommentByID", mock.Anything, "missing").Return(nil, domain.ErrCommentNotFound)
+	s.commentRepo.On("GetCommentByID", mock.Anything, "elsewhere").Return(&domain.Comment{ID: "elsewhere", TaskID: "task-2"}, nil)
+	s.commentRepo.On("GetCommentByID", mock.Anything, "deleted").Return(&domain.Comment{ID: "deleted", TaskID: "task-1", DeletedAt: &deletedAt}, nil)
+
+	for _, parent := range []string{"missing", "elsewhere", "deleted"} {
+		_, err := s.commentUC.AddComment(context.Background(), "task-1", parent, "Agreed", owner)
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidComment, parent)
+	}
+	s.commentRepo.AssertNotCalled(s.T(), "CreateComment", mock.Anything, mock.Anything)
+}
+
+func (s *CommentUsecaseSuite) TestAddComment_InvalidBody() {
+	for _, body := range []string{"", "   ", strings.Repeat("x", domain.MaxCommentLength+1)} {
+		_, err := s.commentUC.AddComment(context.Background(), "task-1", "", body, owner)
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidComment)
+	}
+	s.commentRepo.AssertNotCalled(s.T(), "CreateComment", mock.Anything, mock.Anything)
+}
+
+func (s *CommentUsecaseSuite) TestAddComment_FollowsTaskOwnership() {
+	_, err := s.commentUC.AddComment(context.Background(), "task-1", "", "Hi", stranger)
+	assert.ErrorIs(s.T(), err, domain.ErrTaskNotFound)
+
+	_, err = s.commentUC.AddComment(context.Background(), "task-1", "", "Hi", admin)
+	assert.ErrorIs(s.T(), err, domain.ErrForbidden)
+
+	s.commentRepo.On("CreateComment", mock.Anything, mock.Anything).Return(nil).Once()
+	_, err = s.commentUC.AddComment(context.Background(), "task-1", "", "Hi", override)
+	assert.NoError(s.T(), err)
+}
+
+func (s *CommentUsecaseSuite) TestListComments_KeepsTombstonesWithReplies() {
+	deletedAt := time.Now()
+	s.commentRepo.On("GetCommentsByTask", mock.Anything, "task-1").Return([]*domain.Comment{
+		{ID: "a", TaskID: "task-1", DeletedAt: &deletedAt},
+		{ID: "b", TaskID: "task-1", ParentID: "a"},
+		{ID: "c", TaskID: "task-1", DeletedAt: &deletedAt},
+		{ID: "d", TaskID: "task-1", ParentID: "c", DeletedAt: &deletedAt},
+		{ID: "e", TaskID: "task-1"},
+	}, nil).Once()
+
+	comments, err := s.commentUC.ListComments(context.Background(), "task-1", owner)
+
+	assert.NoError(s.T(), err)
+	var ids []string
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	// "a" still has a reply; "c" only had a reply that was deleted too.
+	assert.Equal(s.T(), []string{"a", "b", "e"}, ids)
+}
+
+func (s *CommentUsecaseSuite) TestEditComment() {
+	assert := assert.New(s.T())
+	s.commentRepo.On("GetCommentByID", mock.Anything, "c1").Return(&domain.Comment{ID: "c1", TaskID: "task-1", AuthorID: "user-id", Body: "old"}, nil)
+	s.commentRepo.On("EditComment", mock.Anything, "c1", "new", mock.Anything).Return(&domain.Comment{ID: "c1", Body: "new"}, nil).Once()
+
+	comment, err := s.commentUC.EditComment(context.Background(), "task-1", "c1", "new", owner)
+	assert.NoError(err)
+	assert.Equal("new", comment.Body)
+
+	// An unchanged body is not a new revision.
+	_, err = s.commentUC.EditComment(context.Background(), "task-1", "c1", "old", owner)
+	assert.NoError(err)
+	s.commentRepo.AssertNumberOfCalls(s.T(), "EditComment", 1)
+}
+
+func (s *CommentUsecaseSuite) TestEditComment_OnlyTheAuthor() {
+	s.commentRepo.On("GetCommentByID", mock.Anything, "c1").Return(&domain.Comment{ID: "c1", TaskID: "task-1", AuthorID: "admin-id", Body: "old"}, nil)
+
+	_, err := s.commentUC.EditComment(context.Background(), "task-1", "c1", "new", owner)
+	assert.ErrorIs(s.T(), err, domain.ErrNotCommentAuthor)
+	s.commentRepo.AssertNotCalled(s.T(), "EditComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *CommentUsecaseSuite) TestEditComment_WrongTask() {
+	s.commentRepo.On("GetCommentByID", mock.Anything, "c1").Return(&domain.Comment{ID: "c1", TaskID: "task-2", AuthorID: "user-id"}, nil)
+
+	_, err := s.commentUC.EditComment(context.Background(), "task-1", "c1", "new", owner)
+	assert.ErrorIs(s.T(), err, domain.ErrCommentNotFound)
+}
+
+func (s *CommentUsecaseSuite) TestDeleteComment_ByAuthor() {
+	s.commentRepo.On("GetCommentByID", mock.Anything, "c1").Return(&domain.Comment{ID: "c1", TaskID: "task-1", AuthorID: "user-id"}, nil)
+	s.commentRepo.On("DeleteComment", mock.Anything, "c1", "user-id", mock.Anything).Return(nil).Once()
+
+	assert.NoError(s.T(), s.commentUC.DeleteComment(context.Background(), "task-1", "c1", owner))
+	s.commentRepo.AssertExpectations(s.T())
+}
+
+func (s *CommentUsecaseSuite) TestDeleteComment_Moderation() {
+	// The task owner cannot delete an admin's comment; a moderator can,
+	// and the tombstone records who did.
+	s.commentRepo.On("GetCommentByID", mock.Anything, "c1").Return(&domain.Comment{ID: "c1", TaskID: "task-1", AuthorID: "someone"}, nil)
+	s.commentRepo.On("DeleteComment", mock.Anything, "c1", "admin-id", mock.Anything).Return(nil).Once()
+
+	assert.ErrorIs(s.T(), s.commentUC.DeleteComment(context.Background(), "task-1", "c1", owner), domain.ErrNotCommentAuthor)
+	assert.NoError(s.T(), s.commentUC.DeleteComment(context.Background(), "task-1", "c1", override))
+	s.commentRepo.AssertExpectations(s.T())
+}
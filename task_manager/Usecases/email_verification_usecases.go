@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type emailVerificationUsecases struct {
+	verificationTokens domain.IVerificationTokenService
+	userRepository     domain.UserRepository
+	mailer             domain.IMailer
+	tokenUsecases      domain.TokenUsecases
+	verifyURL          string
+	transactor         domain.Transactor
+	auditLog           domain.AuditRepository
+	contextTimeout     time.Duration
+}
+
+// NewEmailVerificationUsecases mails links to verifyURL, which should serve
+// GET /verify, with the token appended as the "token" query parameter.
+// Verified addresses are recorded in auditLog, in the same transaction of
+// transactor as the change; either may be nil.
+func NewEmailVerificationUsecases(verificationTokens domain.IVerificationTokenService, userRepository domain.UserRepository, mailer domain.IMailer, tokens domain.TokenUsecases, verifyURL string, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.EmailVerificationUsecases {
+	return &emailVerificationUsecases{
+		verificationTokens: verificationTokens,
+		userRepository:     userRepository,
+		mailer:             mailer,
+		tokenUsecases:      tokens,
+		verifyURL:          verifyURL,
+		transactor:         transactor,
+		auditLog:           auditLog,
+		contextTimeout:     contextTimeout,
+	}
+}
+
+func (eu *emailVerificationUsecases) SendVerificationEmail(ctx context.Context, user *domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, eu.contextTimeout)
+	defer cancel()
+
+	token, err := eu.verificationTokens.GenerateVerificationToken(user)
+	if err != nil {
+		return err
+	}
+	return eu.mailer.Send(ctx, &domain.MailMessage{
+		To:      user.Email,
+		Subject: "Verify your Task Manager email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm that this is your email address by opening this link:\n\n%s\n\n"+
+			"If you did not create a Task Manager account, you can ignore this email.\n",
+			user.Username, withQueryParam(eu.verifyURL, "token", token)),
+	})
+}
+
+func (eu *emailVerificationUsecases) ResendVerificationEmail(ctx context.Context, userId string) error {
+	ctx, cancel := context.WithTimeout(ctx, eu.contextTimeout)
+	defer cancel()
+
+	user, err := eu.userRepository.GetUserByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !user.EmailUnverified {
+		return domain.ErrEmailAlreadyVerified
+	}
+	return eu.SendVerificationEmail(ctx, user)
+}
+
+func (eu *emailVerificationUsecases) VerifyEmail(ctx context.Context, verificationToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, eu.contextTimeout)
+	defer cancel()
+
+	claims, err := eu.verificationTokens.ParseVerificationToken(verificationToken)
+	if err != nil {
+		return err
+	}
+	user, err := eu.userRepository.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	// A token proves control of the address it was sent to, nothing more.
+	if user.Email != claims.Email {
+		return domain.ErrInvalidToken
+	}
+	if !user.EmailUnverified {
+		return nil
+	}
+
+	// The link is opened signed out; the user proved who they are with it.
+	err = inTransaction(ctx, eu.transactor, func(ctx context.Context) error {
+		if err := eu.userRepository.MarkEmailVerified(ctx, user.ID); err != nil {
+			return err
+		}
+		verified := *user
+		verified.EmailUnverified = false
+		return recordAudit(ctx, eu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditEmailVerify, TargetType: domain.AuditTargetUser, TargetID: user.ID,
+		}, user, user, &verified)
+	})
+	if err != nil {
+		return err
+	}
+	// The cached user state carries the flag; drop it so this instance lets
+	// the user in at once.
+	eu.tokenUsecases.ForgetTokenVersion(user.ID)
+	return nil
+}
@@ -0,0 +1,91 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	verificationUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EmailVerificationUsecaseSuite struct {
+	suite.Suite
+	signer   *mocks.IVerificationTokenService
+	userRepo *mocks.UserRepository
+	mailer   *mocks.IMailer
+	tokens   *mocks.TokenUsecases
+	uc       domain.EmailVerificationUsecases
+	user     *domain.User
+}
+
+func (s *EmailVerificationUsecaseSuite) SetupTest() {
+	s.signer = new(mocks.IVerificationTokenService)
+	s.userRepo = new(mocks.UserRepository)
+	s.mailer = new(mocks.IMailer)
+	s.tokens = new(mocks.TokenUsecases)
+	s.uc = verificationUsecases.NewEmailVerificationUsecases(s.signer, s.userRepo, s.mailer, s.tokens, "http://localhost:8080/verify", nil, nil, 2*time.Second)
+	s.user = &domain.User{ID: "u1", Username: "john", Email: "john@example.com", EmailUnverified: true}
+}
+
+func TestEmailVerificationUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationUsecaseSuite))
+}
+
+func (s *EmailVerificationUsecaseSuite) TestSendVerificationEmail_MailsLink() {
+	s.signer.On("GenerateVerificationToken", s.user).Return("signed.token", nil).Once()
+	s.mailer.On("Send", mock.Anything, mock.MatchedBy(func(m *domain.MailMessage) bool {
+		return m.To == "john@example.com"
+	})).Return(nil).Once().Run(func(args mock.Arguments) {
+		body := args.Get(1).(*domain.MailMessage).Body
+		assert.Contains(s.T(), body, "http://localhost:8080/verify?token=signed.token")
+	})
+
+	assert.NoError(s.T(), s.uc.SendVerificationEmail(context.Background(), s.user))
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *EmailVerificationUsecaseSuite) TestResendVerificationEmail_AlreadyVerified() {
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1"}, nil).Once()
+
+	assert.ErrorIs(s.T(), s.uc.ResendVerificationEmail(context.Background(), "u1"), domain.ErrEmailAlreadyVerified)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (s *EmailVerificationUsecaseSuite) TestVerifyEmail_Success() {
+	s.signer.On("ParseVerificationToken", "good").Return(&domain.VerificationClaims{UserID: "u1", Email: "john@example.com"}, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+	s.userRepo.On("MarkEmailVerified", mock.Anything, "u1").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+
+	assert.NoError(s.T(), s.uc.VerifyEmail(context.Background(), "good"))
+	s.userRepo.AssertExpectations(s.T())
+	s.tokens.AssertExpectations(s.T())
+}
+
+func (s *EmailVerificationUsecaseSuite) TestVerifyEmail_AlreadyVerifiedIsNoop() {
+	s.signer.On("ParseVerificationToken", "good").Return(&domain.VerificationClaims{UserID: "u1", Email: "john@example.com"}, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Email: "john@example.com"}, nil).Once()
+
+	assert.NoError(s.T(), s.uc.VerifyEmail(context.Background(), "good"))
+	s.userRepo.AssertNotCalled(s.T(), "MarkEmailVerified", mock.Anything, mock.Anything)
+}
+
+func (s *EmailVerificationUsecaseSuite) TestVerifyEmail_EmailMismatch() {
+	s.signer.On("ParseVerificationToken", "old").Return(&domain.VerificationClaims{UserID: "u1", Email: "old@example.com"}, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+
+	assert.ErrorIs(s.T(), s.uc.VerifyEmail(context.Background(), "old"), domain.ErrInvalidToken)
+	s.userRepo.AssertNotCalled(s.T(), "MarkEmailVerified", mock.Anything, mock.Anything)
+}
+
+func (s *EmailVerificationUsecaseSuite) TestVerifyEmail_InvalidToken() {
+	s.signer.On("ParseVerificationToken", "forged").Return(nil, domain.ErrInvalidToken).Once()
+
+	assert.ErrorIs(s.T(), s.uc.VerifyEmail(context.Background(), "forged"), domain.ErrInvalidToken)
+}
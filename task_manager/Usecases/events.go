@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+// publishEvent tells subscribers about a change. It is called in the
+// transaction that stores the change, so that a failure to publish undoes
+// it. A nil publisher publishes nothing.
+func publishEvent(ctx context.Context, events domain.EventPublisher, eventType string, task *domain.Task, user *domain.User) error {
+	if events == nil {
+		return nil
+	}
+	return events.Publish(ctx, &domain.Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Task:       task,
+		User:       user,
+	})
+}
+
+// publishTaskUpdate publishes task.updated for a stored task, followed by
+// task.completed if the update completed it.
+func (tu *taskUsecases) publishTaskUpdate(ctx context.Context, task *domain.Task, completed bool) error {
+	if err := publishEvent(ctx, tu.events, domain.EventTaskUpdated, task, nil); err != nil || !completed {
+		return err
+	}
+	return publishEvent(ctx, tu.events, domain.EventTaskCompleted, task, nil)
+}
+
+// inTransaction runs fn, which stores a change and publishes it, in a
+// transaction of transactor, or simply calls it when there is none. fn
+// must be safe to run again: it should read what it changes within the
+// transaction rather than before it.
+func inTransaction(ctx context.Context, transactor domain.Transactor, fn func(ctx context.Context) error) error {
+	if transactor == nil {
+		return fn(ctx)
+	}
+	return transactor.WithinTransaction(ctx, fn)
+}
@@ -0,0 +1,183 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type labelUsecases struct {
+	labelRepository domain.LabelRepository
+	taskRepository  domain.TaskRepository
+	transactor      domain.Transactor
+	auditLog        domain.AuditRepository
+	contextTimeout  time.Duration
+}
+
+// NewLabelUsecases records label changes in auditLog, in the same
+// transaction of transactor as the change; either may be nil.
+func NewLabelUsecases(labelRepository domain.LabelRepository, taskRepository domain.TaskRepository, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.LabelUsecases {
+	return &labelUsecases{
+		labelRepository: labelRepository,
+		taskRepository:  taskRepository,
+		transactor:      transactor,
+		auditLog:        auditLog,
+		contextTimeout:  contextTimeout,
+	}
+}
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func (lu *labelUsecases) ListLabels(ctx context.Context, actor *domain.Actor) ([]*domain.Label, error) {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	if actor == nil || actor.User == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	return lu.labelRepository.GetLabelsByUser(ctx, actor.User.ID)
+}
+
+func (lu *labelUsecases) CreateLabel(ctx context.Context, name string, color string, actor *domain.Actor) (*domain.Label, error) {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	if actor == nil || actor.User == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	name, err := validateLabelName(name)
+	if err != nil {
+		return nil, err
+	}
+	if color == "" {
+		color = domain.DefaultLabelColor
+	}
+	if color, err = validateLabelColor(color); err != nil {
+		return nil, err
+	}
+
+	label := &domain.Label{
+		ID:        uuid.New().String(),
+		UserID:    actor.User.ID,
+		Name:      name,
+		Color:     color,
+		CreatedAt: time.Now(),
+	}
+	err = inTransaction(ctx, lu.transactor, func(ctx context.Context) error {
+		if err := lu.labelRepository.CreateLabel(ctx, label); err != nil {
+			return err
+		}
+		return recordAudit(ctx, lu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditLabelCreate, TargetType: domain.AuditTargetLabel, TargetID: label.ID,
+		}, actor.User, nil, label)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+func (lu *labelUsecases) UpdateLabel(ctx context.Context, labelId string, name *string, color *string, actor *domain.Actor) (*domain.Label, error) {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	existing, err := lu.getOwnLabel(ctx, labelId, actor)
+	if err != nil {
+		return nil, err
+	}
+	label := *existing
+	if name != nil {
+		if label.Name, err = validateLabelName(*name); err != nil {
+			return nil, err
+		}
+	}
+	if color != nil {
+		if label.Color, err = validateLabelColor(*color); err != nil {
+			return nil, err
+		}
+	}
+
+	err = inTransaction(ctx, lu.transactor, func(ctx context.Context) error {
+		if err := lu.labelRepository.UpdateLabel(ctx, &label); err != nil {
+			return err
+		}
+		return recordAudit(ctx, lu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditLabelUpdate, TargetType: domain.AuditTargetLabel, TargetID: label.ID,
+		}, actor.User, existing, &label)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// DeleteLabel drops the label before detaching it, so a task cannot pick
+// it up again in between.
+func (lu *labelUsecases) DeleteLabel(ctx context.Context, labelId string, actor *domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	label, err := lu.getOwnLabel(ctx, labelId, actor)
+	if err != nil {
+		return err
+	}
+	return inTransaction(ctx, lu.transactor, func(ctx context.Context) error {
+		if err := lu.labelRepository.DeleteLabel(ctx, label.ID); err != nil {
+			return err
+		}
+		if err := lu.taskRepository.RemoveLabelFromTasks(ctx, label.UserID, label.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, lu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditLabelDelete, TargetType: domain.AuditTargetLabel, TargetID: label.ID,
+		}, actor.User, label, nil)
+	})
+}
+
+// getOwnLabel loads a label from the actor's catalogue. Other users'
+// labels are reported as not found rather than forbidden.
+func (lu *labelUsecases) getOwnLabel(ctx context.Context, labelId string, actor *domain.Actor) (*domain.Label, error) {
+	if actor == nil || actor.User == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	label, err := lu.labelRepository.GetLabelByID(ctx, labelId)
+	if err != nil {
+		return nil, err
+	}
+	if label.UserID != actor.User.ID {
+		return nil, domain.ErrLabelNotFound
+	}
+	return label, nil
+}
+
+// validateLabelName trims the name and rejects ones that could not be used
+// in a comma-separated labels filter.
+func validateLabelName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", domain.ErrInvalidLabel)
+	}
+	if utf8.RuneCountInString(name) > domain.MaxLabelNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", domain.ErrInvalidLabel, domain.MaxLabelNameLength)
+	}
+	if strings.Contains(name, ",") {
+		return "", fmt.Errorf("%w: name cannot contain commas", domain.ErrInvalidLabel)
+	}
+	return name, nil
+}
+
+// validateLabelColor accepts "#rrggbb" in either case and stores it lower
+// case.
+func validateLabelColor(color string) (string, error) {
+	if !labelColorPattern.MatchString(color) {
+		return "", fmt.Errorf("%w: color must be a hex colour like #1e90ff", domain.ErrInvalidLabel)
+	}
+	return strings.ToLower(color), nil
+}
@@ -0,0 +1,97 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	labelUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LabelUsecaseSuite struct {
+	suite.Suite
+	labelRepo *mocks.LabelRepository
+	taskRepo  *mocks.TaskRepository
+	labelUC   domain.LabelUsecases
+}
+
+func (s *LabelUsecaseSuite) SetupTest() {
+	s.labelRepo = new(mocks.LabelRepository)
+	s.taskRepo = new(mocks.TaskRepository)
+	s.labelUC = labelUsecases.NewLabelUsecases(s.labelRepo, s.taskRepo, nil, nil, 2*time.Second)
+	s.labelRepo.On("GetLabelByID", mock.Anything, "l1").Return(&domain.Label{ID: "l1", UserID: "user-id", Name: "urgent", Color: "#ff0000"}, nil).Maybe()
+}
+
+func TestLabelUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(LabelUsecaseSuite))
+}
+
+func (s *LabelUsecaseSuite) TestCreateLabel() {
+	assert := assert.New(s.T())
+	s.labelRepo.On("CreateLabel", mock.Anything, mock.MatchedBy(func(l *domain.Label) bool {
+		return l.ID != "" && l.UserID == "user-id" && l.Name == "Backend" && l.Color == "#1e90ff"
+	})).Return(nil).Once()
+	s.labelRepo.On("CreateLabel", mock.Anything, mock.MatchedBy(func(l *domain.Label) bool {
+		return l.Color == domain.DefaultLabelColor
+	})).Return(nil).Once()
+
+	label, err := s.labelUC.CreateLabel(context.Background(), " Backend ", "#1E90FF", owner)
+	assert.NoError(err)
+	assert.Equal("Backend", label.Name)
+	assert.WithinDuration(time.Now(), label.CreatedAt, time.Minute)
+
+	_, err = s.labelUC.CreateLabel(context.Background(), "Plain", "", owner)
+	assert.NoError(err)
+	s.labelRepo.AssertExpectations(s.T())
+}
+
+func (s *LabelUsecaseSuite) TestCreateLabel_Invalid() {
+	cases := []struct{ name, color string }{
+		{"", "#ffffff"},
+		{"   ", "#ffffff"},
+		{strings.Repeat("x", domain.MaxLabelNameLength+1), "#ffffff"},
+		{"a,b", "#ffffff"},
+		{"ok", "red"},
+		{"ok", "#fff"},
+	}
+	for _, c := range cases {
+		_, err := s.labelUC.CreateLabel(context.Background(), c.name, c.color, owner)
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidLabel, c)
+	}
+	s.labelRepo.AssertNotCalled(s.T(), "CreateLabel", mock.Anything, mock.Anything)
+}
+
+func (s *LabelUsecaseSuite) TestUpdateLabel_OnlyGivenFields() {
+	s.labelRepo.On("UpdateLabel", mock.Anything, &domain.Label{ID: "l1", UserID: "user-id", Name: "urgent", Color: "#00ff00"}).Return(nil).Once()
+
+	color := "#00FF00"
+	label, err := s.labelUC.UpdateLabel(context.Background(), "l1", nil, &color, owner)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "#00ff00", label.Color)
+	s.labelRepo.AssertExpectations(s.T())
+}
+
+func (s *LabelUsecaseSuite) TestOtherUsersLabelsAreNotFound() {
+	name := "mine now"
+	_, err := s.labelUC.UpdateLabel(context.Background(), "l1", &name, nil, stranger)
+	assert.ErrorIs(s.T(), err, domain.ErrLabelNotFound)
+	assert.ErrorIs(s.T(), s.labelUC.DeleteLabel(context.Background(), "l1", override), domain.ErrLabelNotFound)
+	s.labelRepo.AssertNotCalled(s.T(), "DeleteLabel", mock.Anything, mock.Anything)
+}
+
+func (s *LabelUsecaseSuite) TestDeleteLabel_DetachesFromTasks() {
+	s.labelRepo.On("DeleteLabel", mock.Anything, "l1").Return(nil).Once()
+	s.taskRepo.On("RemoveLabelFromTasks", mock.Anything, "user-id", "l1").Return(nil).Once()
+
+	assert.NoError(s.T(), s.labelUC.DeleteLabel(context.Background(), "l1", owner))
+	s.labelRepo.AssertExpectations(s.T())
+	s.taskRepo.AssertExpectations(s.T())
+}
@@ -0,0 +1,123 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// loginThrottle tracks failed logins per account and per client IP and
+// refuses further attempts while either is locked.
+type loginThrottle struct {
+	attempts      domain.LoginAttemptRepository
+	accountPolicy domain.LockoutPolicy
+	ipPolicy      domain.LockoutPolicy
+}
+
+// Counters are keyed by kind so accounts and IPs share one store. Emails
+// are lowercased so that case variants of an address share a counter.
+func accountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginReservation is an attempt counted against the client IP and the
+// account before it is made.
+type loginReservation struct {
+	account    *domain.LoginAttempts
+	ip         *domain.LoginAttempts
+	accountKey string
+	ipKey      string
+}
+
+// reserve counts an attempt as failed before it is made, so that
+// concurrent guesses cannot all get in under the threshold: each one is
+// let through only if the count before it did not lock the key. It fails
+// with a *domain.LockoutError if the IP or the account was locked; the
+// refused attempt stays counted. An attempt that does not fail must be
+// given back with release. An empty ip is not throttled.
+func (lt *loginThrottle) reserve(ctx context.Context, email, ip string, now time.Time) (*loginReservation, error) {
+	reservation := &loginReservation{accountKey: accountAttemptKey(email)}
+	// The IP goes first, so that a locked IP cannot run up the counters of
+	// the accounts it guesses at.
+	if ip != "" {
+		reservation.ipKey = ipAttemptKey(ip)
+		attempts, err := lt.reserveKey(ctx, reservation.ipKey, lt.ipPolicy, domain.ErrTooManyLoginAttempts, now)
+		if err != nil {
+			return nil, err
+		}
+		reservation.ip = attempts
+	}
+	attempts, err := lt.reserveKey(ctx, reservation.accountKey, lt.accountPolicy, domain.ErrAccountLocked, now)
+	if err != nil {
+		return nil, err
+	}
+	reservation.account = attempts
+	return reservation, nil
+}
+
+func (lt *loginThrottle) reserveKey(ctx context.Context, key string, policy domain.LockoutPolicy, reason error, now time.Time) (*domain.LoginAttempts, error) {
+	attempts, err := lt.attempts.RecordLoginFailure(ctx, key, now, policy.Window)
+	if err != nil {
+		return nil, err
+	}
+	before := &domain.LoginAttempts{Failures: attempts.Failures - 1, LastFailure: attempts.PreviousFailure}
+	if until := policy.LockedUntil(before); until.After(now) {
+		return nil, &domain.LockoutError{Err: reason, RetryAfter: until.Sub(now)}
+	}
+	return attempts, nil
+}
+
+// release takes back a reservation for an attempt that did not fail.
+func (lt *loginThrottle) release(ctx context.Context, reservation *loginReservation) error {
+	if err := lt.attempts.ReleaseLoginAttempt(ctx, reservation.accountKey, reservation.account); err != nil {
+		return err
+	}
+	if reservation.ip == nil {
+		return nil
+	}
+	return lt.attempts.ReleaseLoginAttempt(ctx, reservation.ipKey, reservation.ip)
+}
+
+// reset clears the account's counter after a successful login. The IP's
+// counter is left to expire, so one valid account cannot be used to keep
+// guessing at others from the same address.
+func (lt *loginThrottle) reset(ctx context.Context, email string) error {
+	return lt.attempts.ResetLoginAttempts(ctx, accountAttemptKey(email))
+}
+
+// checkPassword looks up the account by email and checks its password,
+// counting failures. Both Login and sign-ins at the OIDC authorization
+// endpoint go through it, so they share one lockout.
+func (lt *loginThrottle) checkPassword(ctx context.Context, users domain.UserRepository, passwords domain.IPasswordService, email, password, clientIP string) (*domain.User, error) {
+	// A locked account is refused before the password is looked at, so
+	// guesses made during the lockout learn nothing.
+	reservation, err := lt.reserve(ctx, email, clientIP, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := users.GetUserByEmail(ctx, email)
+	if err == domain.ErrUserNotFound || err == nil && !passwords.VerifyPassword(user, password) {
+		// The reservation stands as the failure. Unknown addresses are
+		// counted too, so lockouts do not tell which accounts exist.
+		return nil, domain.ErrInvalidCredentials
+	}
+	if releaseErr := lt.release(ctx, reservation); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Only reveal that the account is disabled to someone who knows the
+	// password.
+	if user.Disabled {
+		return nil, domain.ErrAccountDisabled
+	}
+	return user, nil
+}
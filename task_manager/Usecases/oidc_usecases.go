@@ -0,0 +1,440 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+// authorizationCodeTTL is how long a client has to redeem a code. Codes
+// travel through the browser, so they are kept short-lived.
+const authorizationCodeTTL = time.Minute
+
+const maxClientNameLength = 100
+
+// pkceVerifierPattern is the code_verifier syntax of RFC 7636. An S256
+// code_challenge is a SHA-256 sum, which is always 43 characters encoded.
+var (
+	pkceVerifierPattern  = regexp.MustCompile(`^[A-Za-z0-9._~-]{43,128}$`)
+	pkceChallengePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+)
+
+type oidcUsecases struct {
+	clientRepository domain.OIDCClientRepository
+	codeRepository   domain.AuthorizationCodeRepository
+	userRepository   domain.UserRepository
+	passwordService  domain.IPasswordService
+	twoFactor        domain.TwoFactorUsecases
+	tokenService     domain.IOIDCTokenService
+	throttle         *loginThrottle
+	issuer           string
+	transactor       domain.Transactor
+	auditLog         domain.AuditRepository
+	contextTimeout   time.Duration
+}
+
+// NewOIDCUsecases builds the OpenID Connect provider for issuer, the
+// public base URL of this server. Sign-ins share the login lockout of
+// attempts, accountPolicy and ipPolicy with Login. Client registrations and
+// deletions are recorded in auditLog, in the same transaction of transactor;
+// either may be nil.
+func NewOIDCUsecases(clientRepository domain.OIDCClientRepository, codeRepository domain.AuthorizationCodeRepository, userRepository domain.UserRepository, ps domain.IPasswordService, twoFactor domain.TwoFactorUsecases, tokenService domain.IOIDCTokenService, attempts domain.LoginAttemptRepository, accountPolicy, ipPolicy domain.LockoutPolicy, issuer string, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.OIDCUsecases {
+	return &oidcUsecases{
+		clientRepository: clientRepository,
+		codeRepository:   codeRepository,
+		userRepository:   userRepository,
+		passwordService:  ps,
+		twoFactor:        twoFactor,
+		tokenService:     tokenService,
+		throttle: &loginThrottle{
+			attempts:      attempts,
+			accountPolicy: accountPolicy,
+			ipPolicy:      ipPolicy,
+		},
+		issuer:         strings.TrimSuffix(issuer, "/"),
+		transactor:     transactor,
+		auditLog:       auditLog,
+		contextTimeout: contextTimeout,
+	}
+}
+
+func (ou *oidcUsecases) RegisterClient(ctx context.Context, name string, redirectURIs []string, public bool) (*domain.NewOIDCClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, ou.contextTimeout)
+	defer cancel()
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxClientNameLength {
+		return nil, fmt.Errorf("%w: name must be 1-%d characters", domain.ErrInvalidOIDCClient, maxClientNameLength)
+	}
+	if len(redirectURIs) == 0 {
+		return nil, fmt.Errorf("%w: at least one redirect URI is required", domain.ErrInvalidOIDCClient)
+	}
+	var unique []string
+	for _, uri := range redirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(unique, uri) {
+			unique = append(unique, uri)
+		}
+	}
+
+	client := &domain.OIDCClient{
+		ID:           uuid.New().String(),
+		Name:         name,
+		RedirectURIs: unique,
+		CreatedAt:    time.Now(),
+	}
+	var secret string
+	if !public {
+		var err error
+		if secret, err = newOpaqueToken(); err != nil {
+			return nil, err
+		}
+		client.SecretHash = hashToken(secret)
+	}
+	err := inTransaction(ctx, ou.transactor, func(ctx context.Context) error {
+		if err := ou.clientRepository.CreateOIDCClient(ctx, client); err != nil {
+			return err
+		}
+		return recordAudit(ctx, ou.auditLog, &domain.AuditEntry{
+			Action: domain.AuditClientCreate, TargetType: domain.AuditTargetClient, TargetID: client.ID,
+		}, nil, nil, client)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.NewOIDCClient{Secret: secret, Client: client}, nil
+}
+
+func (ou *oidcUsecases) ListClients(ctx context.Context) ([]*domain.OIDCClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, ou.contextTimeout)
+	defer cancel()
+
+	return ou.clientRepository.GetOIDCClients(ctx)
+}
+
+// DeleteClient also ends the client's outstanding codes and access tokens,
+// which are checked against the registered clients when used.
+func (ou *oidcUsecases) DeleteClient(ctx context.Context, clientId string) error {
+	ctx, cancel := context.WithTimeout(ctx, ou.contextTimeout)
+	defer cancel()
+
+	client, err := ou.clientRepository.GetOIDCClientByID(ctx, clientId)
+	if err != nil {
+		return err
+	}
+	return inTransaction(ctx, ou.transactor, func(ctx context.Context) error {
+		if err := ou.clientRepository.DeleteOIDCClient(ctx, clientId); err != nil {
+			return err
+		}
+		return recordAudit(ctx, ou.auditLog, &domain.AuditEntry{
+			Action: domain.AuditClientDelete, TargetType: domain.AuditTargetClient, TargetID: clientId,
+		}, nil, client, nil)
+	})
+}
+
+func (ou *oidcUsecases) Discovery() *domain.OIDCProviderMetadata {
+	return &domain.OIDCProviderMetadata{
+		Issuer:                            ou.issuer,
+		AuthorizationEndpoint:             ou.issuer + domain.OIDCAuthorizationPath,
+		TokenEndpoint:                     ou.issuer + domain.OIDCTokenPath,
+		UserInfoEndpoint:                  ou.issuer + domain.OIDCUserInfoPath,
+		JWKSURI:                           ou.issuer + domain.OIDCJWKSPath,
+		ScopesSupported:                   domain.OIDCScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{ou.tokenService.SigningAlgorithm()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "preferred_username", "email", "email_verified"},
+	}
+}
+
+func (ou *oidcUsecases) ValidateAuthorization(ctx context.Context, request *domain.AuthorizationRequest) (*domain.OIDCClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, ou.contextTimeout)
+	defer cancel()
+
+	return ou.validate(ctx, request)
+}
+
+func (ou *oidcUsecases) Authorize(ctx context.Context, request *domain.AuthorizationRequest, email, password, clientIP string) (*domain.Authorization, error) {
+	ctx, cancel := context.WithTimeout(ctx, ou.contextTimeout)
+	defer cancel()
+
+	client, err := ou.validate(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	user, err := ou.throttle.checkPassword(ctx, ou.userRepository, ou.passwordService, email, password, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := ou.twoFactor.BeginLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		// Enrolling needs the task_manager API; a third-party login page
+		// is the wrong place to show a TOTP secret.
+		if challenge.SetupRequired {
+			return nil, domain.ErrTwoFactorSetupFirst
+		}
+		return &domain.Authorization{Challenge: challenge}, nil
+	}
+	if err := ou.throttle.reset(ctx, email); err != nil {
+		return nil, err
+	}
+	return ou.issueCode(ctx, client, request, user)
+}
+
+func (ou *oidcUsecases) AuthorizeTwoFactor(ctx context.Context, request *domain.AuthorizationRequest, challengeToken, code, clientIP string) (*domain.Authorization, error) {
+	ctx, cancel := context.WithTimeout(ctx, ou.contextTimeout)
+	defer cancel()
+
+	client, err := ou.validate(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	user, err := ou.twoFactor.VerifyLogin(ctx, challengeToken, code, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	return ou.issueCode(ctx, client, request, user)
+}
+
+func (ou *oidcUsecases) Exchange(ctx context.Context, request *domain.TokenRequest) (*domain.OIDCTokens, error) {
+	ctx, cancel := context.WithTimeout(ctx, ou.contextTimeout)
+	defer cancel()
+
+	if request.GrantType != "authorization_code" {
+		return nil, &domain.OIDCError{Code: domain.OIDCUnsupportedGrantType, Description: "only authorization_code is supported"}
+	}
+	client, err := ou.authenticateClient(ctx, request.ClientID, request.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if request.Code == "" || request.CodeVerifier == "" {
+		return nil, &domain.OIDCError{Code: domain.OIDCInvalidRequest, Description: "code and code_verifier are required"}
+	}
+
+	invalidGrant := &domain.OIDCError{Code: domain.OIDCInvalidGrant, Description: "authorization code is invalid, expired or already used"}
+	stored, err := ou.codeRepository.GetAuthorizationCodeByHash(ctx, hashToken(request.Code))
+	if err != nil {
+		if err == domain.ErrTokenNotFound {
+			return nil, invalidGrant
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if stored.ClientID != client.ID || stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+		return nil, invalidGrant
+	}
+	if stored.RedirectURI != request.RedirectURI {
+		return nil, &domain.OIDCError{Code: domain.OIDCInvalidGrant, Description: "redirect_uri does not match the authorization request"}
+	}
+	if !verifyPKCE(request.CodeVerifier, stored.CodeChallenge) {
+		return nil, &domain.OIDCError{Code: domain.OIDCInvalidGrant, Description: "code_verifier does not match the code_challenge"}
+	}
+	marked, err := ou.codeRepository.MarkAuthorizationCodeUsed(ctx, stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		// Another request redeemed this code between our read and write.
+		return nil, invalidGrant
+	}
+
+	user, err := ou.userRepository.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, invalidGrant
+		}
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, invalidGrant
+	}
+
+	idToken, err := ou.tokenService.GenerateIDToken(releasedUserInfo(user, stored.Scopes), client.ID, stored.Nonce, stored.AuthTime)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, expiresIn, err := ou.tokenService.GenerateAccessToken(user, client.ID, stored.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OIDCTokens{AccessToken: accessToken, IDToken: idToken, ExpiresIn: expiresIn, Scopes: stored.Scopes}, nil
+}
+
+// UserInfo rejects tokens of deleted clients, and tokens issued before the
+// user's TokenVersion last changed, as Authenticate does.
+func (ou *oidcUsecases) UserInfo(ctx context.Context, accessToken string) (*domain.OIDCUserInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, ou.contextTimeout)
+	defer cancel()
+
+	claims, err := ou.tokenService.ParseAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ou.clientRepository.GetOIDCClientByID(ctx, claims.ClientID); err != nil {
+		if err == domain.ErrOIDCClientNotFound {
+			return nil, domain.ErrTokenRevoked
+		}
+		return nil, err
+	}
+	user, err := ou.userRepository.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrTokenRevoked
+		}
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, domain.ErrAccountDisabled
+	}
+	if user.TokenVersion != claims.TokenVersion {
+		return nil, domain.ErrTokenRevoked
+	}
+	return releasedUserInfo(user, claims.Scopes), nil
+}
+
+// validate checks the client and redirect URI first: until both are known
+// good, errors must not be sent to the redirect URI.
+func (ou *oidcUsecases) validate(ctx context.Context, request *domain.AuthorizationRequest) (*domain.OIDCClient, error) {
+	client, err := ou.clientRepository.GetOIDCClientByID(ctx, request.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	// Redirect URIs are matched exactly, never by prefix.
+	if !slices.Contains(client.RedirectURIs, request.RedirectURI) {
+		return nil, domain.ErrInvalidRedirectURI
+	}
+
+	if request.ResponseType != "code" {
+		return nil, &domain.OIDCError{Code: domain.OIDCUnsupportedResponseType, Description: "only the code response type is supported"}
+	}
+	if !slices.Contains(strings.Fields(request.Scope), domain.OIDCScopeOpenID) {
+		return nil, &domain.OIDCError{Code: domain.OIDCInvalidScope, Description: "the openid scope is required"}
+	}
+	if request.CodeChallengeMethod != "S256" || !pkceChallengePattern.MatchString(request.CodeChallenge) {
+		return nil, &domain.OIDCError{Code: domain.OIDCInvalidRequest, Description: "an S256 code_challenge is required"}
+	}
+	// There are no sessions to sign in silently with.
+	if slices.Contains(strings.Fields(request.Prompt), "none") {
+		return nil, &domain.OIDCError{Code: domain.OIDCLoginRequired, Description: "the user must sign in"}
+	}
+	return client, nil
+}
+
+// authenticateClient checks a confidential client's secret. Public clients
+// have none to check; PKCE stands in for it.
+func (ou *oidcUsecases) authenticateClient(ctx context.Context, clientId, secret string) (*domain.OIDCClient, error) {
+	invalidClient := &domain.OIDCError{Code: domain.OIDCInvalidClient, Description: "client authentication failed"}
+	if clientId == "" {
+		return nil, invalidClient
+	}
+	client, err := ou.clientRepository.GetOIDCClientByID(ctx, clientId)
+	if err != nil {
+		if err == domain.ErrOIDCClientNotFound {
+			return nil, invalidClient
+		}
+		return nil, err
+	}
+	if client.Public() {
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, invalidClient
+	}
+	return client, nil
+}
+
+func (ou *oidcUsecases) issueCode(ctx context.Context, client *domain.OIDCClient, request *domain.AuthorizationRequest, user *domain.User) (*domain.Authorization, error) {
+	code, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	// Unknown scopes are ignored, as OpenID Connect asks.
+	var scopes []string
+	for _, scope := range strings.Fields(request.Scope) {
+		if slices.Contains(domain.OIDCScopes, scope) && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	now := time.Now()
+	err = ou.codeRepository.CreateAuthorizationCode(ctx, &domain.AuthorizationCode{
+		ID:            uuid.New().String(),
+		CodeHash:      hashToken(code),
+		ClientID:      client.ID,
+		UserID:        user.ID,
+		RedirectURI:   request.RedirectURI,
+		Scopes:        scopes,
+		Nonce:         request.Nonce,
+		CodeChallenge: request.CodeChallenge,
+		AuthTime:      now,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(authorizationCodeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Authorization{RedirectURI: request.Redirect(url.Values{"code": {code}})}, nil
+}
+
+// releasedUserInfo is what the scopes let a client know about user.
+func releasedUserInfo(user *domain.User, scopes []string) *domain.OIDCUserInfo {
+	info := &domain.OIDCUserInfo{Subject: user.ID}
+	if slices.Contains(scopes, domain.OIDCScopeProfile) {
+		info.Username = user.Username
+	}
+	if slices.Contains(scopes, domain.OIDCScopeEmail) {
+		verified := !user.EmailUnverified
+		info.Email = user.Email
+		info.EmailVerified = &verified
+	}
+	return info
+}
+
+// validateRedirectURI accepts absolute https URIs, and http ones on the
+// loopback interface for clients under development. Fragments are not
+// allowed, as the code is added to the query.
+func validateRedirectURI(uri string) error {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Host == "" || parsed.Fragment != "" || parsed.User != nil {
+		return fmt.Errorf("%w: %q is not an absolute URI without a fragment", domain.ErrInvalidOIDCClient, uri)
+	}
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := parsed.Hostname()
+		if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q must use https, or http on localhost", domain.ErrInvalidOIDCClient, uri)
+}
+
+func verifyPKCE(verifier, challenge string) bool {
+	if !pkceVerifierPattern.MatchString(verifier) {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
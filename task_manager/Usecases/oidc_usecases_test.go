@@ -0,0 +1,305 @@
+package usecases_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	oidcUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testIssuer       = "https://tasks.example.com"
+	testRedirectURI  = "https://wiki.example.com/callback"
+	testCodeVerifier = "dBjftJeZ4CVP-mJ92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+type OIDCUsecaseSuite struct {
+	suite.Suite
+	clients   domain.OIDCClientRepository
+	users     domain.UserRepository
+	ps        *mocks.IPasswordService
+	twoFactor *mocks.TwoFactorUsecases
+	tokens    *mocks.IOIDCTokenService
+	uc        domain.OIDCUsecases
+	user      *domain.User
+	client    *domain.NewOIDCClient
+}
+
+func (s *OIDCUsecaseSuite) SetupTest() {
+	ctx := context.Background()
+	s.clients = repository.NewInMemoryOIDCClientRepository()
+	s.users = repository.NewInMemoryUserRepository()
+	s.ps = new(mocks.IPasswordService)
+	s.twoFactor = new(mocks.TwoFactorUsecases)
+	s.tokens = new(mocks.IOIDCTokenService)
+	s.uc = oidcUsecases.NewOIDCUsecases(s.clients, repository.NewInMemoryAuthorizationCodeRepository(), s.users, s.ps, s.twoFactor, s.tokens,
+		repository.NewInMemoryLoginAttemptRepository(), testAccountLockout, testIPLockout, testIssuer+"/", nil, nil, 2*time.Second)
+
+	var err error
+	s.user, err = s.users.CreateUser(ctx, &domain.User{ID: "u1", Username: "john", Email: "john@example.com", Password: "hashed", Role: domain.RoleUser})
+	require.NoError(s.T(), err)
+	s.client, err = s.uc.RegisterClient(ctx, "Wiki", []string{testRedirectURI}, false)
+	require.NoError(s.T(), err)
+
+	s.ps.On("VerifyPassword", mock.Anything, "secret").Return(true)
+	s.ps.On("VerifyPassword", mock.Anything, mock.Anything).Return(false)
+	s.twoFactor.On("BeginLogin", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	s.tokens.On("GenerateIDToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("id-token", nil).Maybe()
+	s.tokens.On("GenerateAccessToken", mock.Anything, mock.Anything, mock.Anything).Return("access-token", 15*time.Minute, nil).Maybe()
+}
+
+func TestOIDCUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(OIDCUsecaseSuite))
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (s *OIDCUsecaseSuite) request() *domain.AuthorizationRequest {
+	return &domain.AuthorizationRequest{
+		ClientID:            s.client.Client.ID,
+		RedirectURI:         testRedirectURI,
+		ResponseType:        "code",
+		Scope:               "openid email offline_access",
+		State:               "af0ifjsldkj",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       pkceChallenge(testCodeVerifier),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+// authorize signs in and returns the code from the redirect.
+func (s *OIDCUsecaseSuite) authorize(request *domain.AuthorizationRequest) string {
+	authorization, err := s.uc.Authorize(context.Background(), request, "john@example.com", "secret", "192.0.2.1")
+	require.NoError(s.T(), err)
+	redirect, err := url.Parse(authorization.RedirectURI)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), request.State, redirect.Query().Get("state"))
+	return redirect.Query().Get("code")
+}
+
+func (s *OIDCUsecaseSuite) exchange(code string) *domain.TokenRequest {
+	return &domain.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         code,
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testCodeVerifier,
+		ClientID:     s.client.Client.ID,
+		ClientSecret: s.client.Secret,
+	}
+}
+
+func assertOIDCError(t *testing.T, err error, code string) {
+	t.Helper()
+	var oidcErr *domain.OIDCError
+	if assert.True(t, errors.As(err, &oidcErr), "want an OIDCError, got %v", err) {
+		assert.Equal(t, code, oidcErr.Code)
+	}
+}
+
+func (s *OIDCUsecaseSuite) TestRegisterClient_StoresOnlyTheSecretHash() {
+	clients, err := s.uc.ListClients(context.Background())
+	require.NoError(s.T(), err)
+	require.Len(s.T(), clients, 1)
+	assert.NotEmpty(s.T(), s.client.Secret)
+	assert.False(s.T(), clients[0].Public())
+	assert.NotEqual(s.T(), s.client.Secret, clients[0].SecretHash)
+
+	public, err := s.uc.RegisterClient(context.Background(), "Dashboard", []string{"http://localhost:3000/cb", "http://localhost:3000/cb"}, true)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), public.Secret)
+	assert.True(s.T(), public.Client.Public())
+	assert.Equal(s.T(), []string{"http://localhost:3000/cb"}, public.Client.RedirectURIs)
+}
+
+func (s *OIDCUsecaseSuite) TestRegisterClient_Invalid() {
+	cases := map[string]struct {
+		name         string
+		redirectURIs []string
+	}{
+		"blank name":       {" ", []string{testRedirectURI}},
+		"no redirect URIs": {"Wiki", nil},
+		"relative URI":     {"Wiki", []string{"/callback"}},
+		"plain http":       {"Wiki", []string{"http://wiki.example.com/callback"}},
+		"fragment":         {"Wiki", []string{"https://wiki.example.com/callback#x"}},
+		"custom scheme":    {"Wiki", []string{"javascript://wiki.example.com/%0aalert(1)"}},
+	}
+	for name, c := range cases {
+		_, err := s.uc.RegisterClient(context.Background(), c.name, c.redirectURIs, false)
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidOIDCClient, name)
+	}
+}
+
+func (s *OIDCUsecaseSuite) TestDiscovery() {
+	s.tokens.On("SigningAlgorithm").Return(domain.SigningAlgorithmRS256)
+
+	metadata := s.uc.Discovery()
+	assert.Equal(s.T(), testIssuer, metadata.Issuer)
+	assert.Equal(s.T(), testIssuer+"/oauth/authorize", metadata.AuthorizationEndpoint)
+	assert.Equal(s.T(), testIssuer+"/.well-known/jwks.json", metadata.JWKSURI)
+	assert.Equal(s.T(), []string{domain.SigningAlgorithmRS256}, metadata.IDTokenSigningAlgValuesSupported)
+	assert.Equal(s.T(), []string{"S256"}, metadata.CodeChallengeMethodsSupported)
+}
+
+func (s *OIDCUsecaseSuite) TestValidateAuthorization() {
+	ctx := context.Background()
+
+	request := s.request()
+	request.ClientID = "unknown"
+	_, err := s.uc.ValidateAuthorization(ctx, request)
+	assert.ErrorIs(s.T(), err, domain.ErrOIDCClientNotFound)
+
+	// Redirect URIs must match exactly.
+	request = s.request()
+	request.RedirectURI = testRedirectURI + "/../evil"
+	_, err = s.uc.ValidateAuthorization(ctx, request)
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidRedirectURI)
+
+	cases := map[string]struct {
+		edit func(*domain.AuthorizationRequest)
+		code string
+	}{
+		"implicit flow":  {func(r *domain.AuthorizationRequest) { r.ResponseType = "token" }, domain.OIDCUnsupportedResponseType},
+		"no openid":      {func(r *domain.AuthorizationRequest) { r.Scope = "email" }, domain.OIDCInvalidScope},
+		"no PKCE":        {func(r *domain.AuthorizationRequest) { r.CodeChallenge, r.CodeChallengeMethod = "", "" }, domain.OIDCInvalidRequest},
+		"plain PKCE":     {func(r *domain.AuthorizationRequest) { r.CodeChallengeMethod = "plain" }, domain.OIDCInvalidRequest},
+		"silent sign-in": {func(r *domain.AuthorizationRequest) { r.Prompt = "none" }, domain.OIDCLoginRequired},
+	}
+	for name, c := range cases {
+		request := s.request()
+		c.edit(request)
+		_, err := s.uc.ValidateAuthorization(ctx, request)
+		s.Run(name, func() { assertOIDCError(s.T(), err, c.code) })
+	}
+
+	client, err := s.uc.ValidateAuthorization(ctx, s.request())
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "Wiki", client.Name)
+}
+
+func (s *OIDCUsecaseSuite) TestAuthorizeAndExchange() {
+	ctx := context.Background()
+	code := s.authorize(s.request())
+	require.NotEmpty(s.T(), code)
+
+	tokens, err := s.uc.Exchange(ctx, s.exchange(code))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "id-token", tokens.IDToken)
+	assert.Equal(s.T(), "access-token", tokens.AccessToken)
+	// Unknown scopes are dropped.
+	assert.Equal(s.T(), []string{domain.OIDCScopeOpenID, domain.OIDCScopeEmail}, tokens.Scopes)
+
+	verified := true
+	s.tokens.AssertCalled(s.T(), "GenerateIDToken",
+		&domain.OIDCUserInfo{Subject: "u1", Email: "john@example.com", EmailVerified: &verified},
+		s.client.Client.ID, "n-0S6_WzA2Mj", mock.AnythingOfType("time.Time"))
+
+	// A code works once.
+	_, err = s.uc.Exchange(ctx, s.exchange(code))
+	assertOIDCError(s.T(), err, domain.OIDCInvalidGrant)
+}
+
+func (s *OIDCUsecaseSuite) TestExchange_Rejected() {
+	ctx := context.Background()
+	other, err := s.uc.RegisterClient(ctx, "Other", []string{testRedirectURI}, true)
+	require.NoError(s.T(), err)
+
+	cases := map[string]struct {
+		edit func(*domain.TokenRequest)
+		code string
+	}{
+		"wrong grant type":   {func(r *domain.TokenRequest) { r.GrantType = "password" }, domain.OIDCUnsupportedGrantType},
+		"wrong secret":       {func(r *domain.TokenRequest) { r.ClientSecret = "guess" }, domain.OIDCInvalidClient},
+		"no client":          {func(r *domain.TokenRequest) { r.ClientID, r.ClientSecret = "", "" }, domain.OIDCInvalidClient},
+		"another client":     {func(r *domain.TokenRequest) { r.ClientID, r.ClientSecret = other.Client.ID, "" }, domain.OIDCInvalidGrant},
+		"wrong redirect URI": {func(r *domain.TokenRequest) { r.RedirectURI = "https://wiki.example.com/other" }, domain.OIDCInvalidGrant},
+		"wrong verifier":     {func(r *domain.TokenRequest) { r.CodeVerifier = strings.Repeat("a", 43) }, domain.OIDCInvalidGrant},
+		"no verifier":        {func(r *domain.TokenRequest) { r.CodeVerifier = "" }, domain.OIDCInvalidRequest},
+		"unknown code":       {func(r *domain.TokenRequest) { r.Code = "made-up" }, domain.OIDCInvalidGrant},
+	}
+	for name, c := range cases {
+		request := s.exchange(s.authorize(s.request()))
+		c.edit(request)
+		_, err := s.uc.Exchange(ctx, request)
+		s.Run(name, func() { assertOIDCError(s.T(), err, c.code) })
+	}
+}
+
+func (s *OIDCUsecaseSuite) TestAuthorize_WrongPasswordIsCountedLikeLogin() {
+	ctx := context.Background()
+	for i := 0; i < testAccountLockout.Threshold; i++ {
+		_, err := s.uc.Authorize(ctx, s.request(), "john@example.com", "wrong", "192.0.2.1")
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidCredentials)
+	}
+	_, err := s.uc.Authorize(ctx, s.request(), "john@example.com", "secret", "192.0.2.1")
+	var lockout *domain.LockoutError
+	assert.ErrorAs(s.T(), err, &lockout)
+}
+
+func (s *OIDCUsecaseSuite) TestAuthorize_TwoFactor() {
+	ctx := context.Background()
+	s.twoFactor.ExpectedCalls = nil
+	s.twoFactor.On("BeginLogin", mock.Anything, mock.Anything).Return(&domain.TwoFactorChallenge{Token: "challenge"}, nil)
+	s.twoFactor.On("VerifyLogin", mock.Anything, "challenge", "123456", "192.0.2.1").Return(s.user, nil)
+
+	authorization, err := s.uc.Authorize(ctx, s.request(), "john@example.com", "secret", "192.0.2.1")
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), authorization.Challenge)
+	assert.Empty(s.T(), authorization.RedirectURI)
+
+	authorization, err = s.uc.AuthorizeTwoFactor(ctx, s.request(), "challenge", "123456", "192.0.2.1")
+	require.NoError(s.T(), err)
+	redirect, err := url.Parse(authorization.RedirectURI)
+	require.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), redirect.Query().Get("code"))
+}
+
+func (s *OIDCUsecaseSuite) TestAuthorize_TwoFactorSetupRequired() {
+	s.twoFactor.ExpectedCalls = nil
+	s.twoFactor.On("BeginLogin", mock.Anything, mock.Anything).Return(&domain.TwoFactorChallenge{Token: "challenge", SetupRequired: true}, nil)
+
+	_, err := s.uc.Authorize(context.Background(), s.request(), "john@example.com", "secret", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrTwoFactorSetupFirst)
+}
+
+func (s *OIDCUsecaseSuite) TestUserInfo() {
+	ctx := context.Background()
+	claims := &domain.OIDCAccessClaims{UserID: "u1", ClientID: s.client.Client.ID, Scopes: []string{domain.OIDCScopeOpenID, domain.OIDCScopeProfile}}
+	s.tokens.On("ParseAccessToken", "access-token").Return(claims, nil)
+
+	info, err := s.uc.UserInfo(ctx, "access-token")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), &domain.OIDCUserInfo{Subject: "u1", Username: "john"}, info)
+
+	// Signing the user out everywhere ends the client's token too.
+	require.NoError(s.T(), s.users.UpdateUserPassword(ctx, "u1", "rehashed"))
+	_, err = s.uc.UserInfo(ctx, "access-token")
+	assert.ErrorIs(s.T(), err, domain.ErrTokenRevoked)
+}
+
+func (s *OIDCUsecaseSuite) TestUserInfo_DeletedClient() {
+	ctx := context.Background()
+	claims := &domain.OIDCAccessClaims{UserID: "u1", ClientID: s.client.Client.ID, Scopes: []string{domain.OIDCScopeOpenID}}
+	s.tokens.On("ParseAccessToken", "access-token").Return(claims, nil)
+
+	require.NoError(s.T(), s.uc.DeleteClient(ctx, s.client.Client.ID))
+	_, err := s.uc.UserInfo(ctx, "access-token")
+	assert.ErrorIs(s.T(), err, domain.ErrTokenRevoked)
+	assert.ErrorIs(s.T(), s.uc.DeleteClient(ctx, s.client.Client.ID), domain.ErrOIDCClientNotFound)
+}
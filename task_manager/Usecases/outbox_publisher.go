@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+
+	domain "task_manager/Domain"
+)
+
+type outboxPublisher struct {
+	outboxRepository domain.OutboxRepository
+}
+
+// NewOutboxPublisher publishes events by appending them to the outbox, from
+// which the outbox relay hands them to consumers. It is called within the
+// transaction, and deadline, of the change being published.
+func NewOutboxPublisher(outboxRepository domain.OutboxRepository) domain.EventPublisher {
+	return &outboxPublisher{
+		outboxRepository: outboxRepository,
+	}
+}
+
+func (op *outboxPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	if event.User != nil {
+		// Consumers have no use for the password hash; keep it out of the
+		// outbox.
+		user := *event.User
+		user.Password = ""
+		stored := *event
+		stored.User = &user
+		if err := op.outboxRepository.AppendEvent(ctx, &stored); err != nil {
+			return err
+		}
+		event.Sequence = stored.Sequence
+		return nil
+	}
+	return op.outboxRepository.AppendEvent(ctx, event)
+}
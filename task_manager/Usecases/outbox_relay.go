@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type outboxRelay struct {
+	outboxRepository     domain.OutboxRepository
+	checkpointRepository domain.OutboxCheckpointRepository
+	transactor           domain.Transactor
+	// consumers are keyed by the name their checkpoint is stored under.
+	consumers map[string]domain.EventPublisher
+	config    domain.OutboxRelayConfig
+	// owner identifies this relay in the leases it takes.
+	owner          string
+	contextTimeout time.Duration
+}
+
+// NewOutboxRelay relays the outbox to consumers, each under its own name.
+// A consumer's name must not change, since its checkpoint is stored under
+// it; a consumer given a new name starts again from the oldest event kept.
+// Without a transactor, the consumer's writes and its checkpoint are not
+// stored together, and an event can reach it twice.
+func NewOutboxRelay(outboxRepository domain.OutboxRepository, checkpointRepository domain.OutboxCheckpointRepository, transactor domain.Transactor, consumers map[string]domain.EventPublisher, config domain.OutboxRelayConfig, contextTimeout time.Duration) domain.OutboxRelay {
+	return &outboxRelay{
+		outboxRepository:     outboxRepository,
+		checkpointRepository: checkpointRepository,
+		transactor:           transactor,
+		consumers:            consumers,
+		config:               config,
+		owner:                uuid.New().String(),
+		contextTimeout:       contextTimeout,
+	}
+}
+
+func (rl *outboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(rl.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := rl.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Outbox relay: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending carries on with the other consumers when one fails; the
+// failed one is retried from the same event on the next call.
+func (rl *outboxRelay) RelayPending(ctx context.Context) error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(rl.consumers)) {
+		if err := rl.relay(ctx, name); err != nil && !errors.Is(err, domain.ErrOutboxLeaseHeld) {
+			errs = append(errs, fmt.Errorf("consumer %s: %w", name, err))
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := rl.prune(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// relay hands one consumer its new events, renewing its lease before each
+// batch.
+func (rl *outboxRelay) relay(ctx context.Context, name string) error {
+	for ctx.Err() == nil {
+		now := time.Now()
+		readCtx, cancel := context.WithTimeout(ctx, rl.contextTimeout)
+		checkpoint, err := rl.checkpointRepository.ClaimCheckpoint(readCtx, name, rl.owner, now, now.Add(rl.config.Lease))
+		var events []*domain.Event
+		if err == nil {
+			events, err = rl.outboxRepository.GetEventsAfter(readCtx, checkpoint.Sequence, rl.config.BatchSize)
+		}
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := rl.handle(ctx, name, event); err != nil {
+				return fmt.Errorf("event %d: %w", event.Sequence, err)
+			}
+		}
+		if len(events) < rl.config.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// handle publishes event to the consumer and moves its checkpoint past the
+// event in one transaction, so that the consumer's writes are kept exactly
+// when the checkpoint says they were made.
+func (rl *outboxRelay) handle(ctx context.Context, name string, event *domain.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, rl.contextTimeout)
+	defer cancel()
+
+	return inTransaction(ctx, rl.transactor, func(ctx context.Context) error {
+		if err := rl.consumers[name].Publish(ctx, event); err != nil {
+			return err
+		}
+		return rl.checkpointRepository.AdvanceCheckpoint(ctx, name, rl.owner, event.Sequence)
+	})
+}
+
+// prune deletes the events past the retention that every consumer has
+// handled: this relay's, and those of other relays sharing the store. A
+// checkpoint that has not been claimed for longer than the retention is
+// taken to belong to a consumer that was removed, and no longer holds
+// events back.
+func (rl *outboxRelay) prune(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, rl.contextTimeout)
+	defer cancel()
+
+	checkpoints, err := rl.checkpointRepository.GetCheckpoints(ctx)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-rl.config.Retention)
+	handled := make(map[string]int64, len(checkpoints))
+	through := int64(math.MaxInt64)
+	for _, checkpoint := range checkpoints {
+		handled[checkpoint.Consumer] = checkpoint.Sequence
+		if checkpoint.LeaseUntil.After(cutoff) {
+			through = min(through, checkpoint.Sequence)
+		}
+	}
+	for name := range rl.consumers {
+		through = min(through, handled[name])
+	}
+	if through == 0 || through == math.MaxInt64 {
+		return nil
+	}
+	return rl.outboxRepository.DeleteEvents(ctx, through, cutoff)
+}
@@ -0,0 +1,261 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	taskUsecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingConsumer records the IDs of the events it is handed, failing
+// the first failures calls.
+type recordingConsumer struct {
+	mu       sync.Mutex
+	failures int
+	handled  []string
+}
+
+func (rc *recordingConsumer) Publish(ctx context.Context, event *domain.Event) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.failures > 0 {
+		rc.failures--
+		return errors.New("consumer down")
+	}
+	rc.handled = append(rc.handled, event.ID)
+	return nil
+}
+
+func (rc *recordingConsumer) Handled() []string {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]string(nil), rc.handled...)
+}
+
+type OutboxRelaySuite struct {
+	suite.Suite
+	outbox      domain.OutboxRepository
+	checkpoints domain.OutboxCheckpointRepository
+	config      domain.OutboxRelayConfig
+	ctx         context.Context
+}
+
+func (s *OutboxRelaySuite) SetupTest() {
+	s.outbox = repository.NewInMemoryOutboxRepository()
+	s.checkpoints = repository.NewInMemoryOutboxCheckpointRepository()
+	s.config = domain.OutboxRelayConfig{
+		PollInterval: time.Second,
+		Lease:        time.Minute,
+		Retention:    time.Hour,
+		BatchSize:    2,
+	}
+	s.ctx = context.Background()
+}
+
+func TestOutboxRelaySuite(t *testing.T) {
+	suite.Run(t, new(OutboxRelaySuite))
+}
+
+func (s *OutboxRelaySuite) newRelay(consumers map[string]domain.EventPublisher) domain.OutboxRelay {
+	return taskUsecases.NewOutboxRelay(s.outbox, s.checkpoints, nil, consumers, s.config, time.Second)
+}
+
+// append adds events with the given IDs to the outbox.
+func (s *OutboxRelaySuite) append(occurredAt time.Time, ids ...string) {
+	for _, id := range ids {
+		require.NoError(s.T(), s.outbox.AppendEvent(s.ctx, &domain.Event{ID: id, Type: domain.EventTaskCreated, OccurredAt: occurredAt}))
+	}
+}
+
+func (s *OutboxRelaySuite) checkpoint(consumer string) int64 {
+	checkpoints, err := s.checkpoints.GetCheckpoints(s.ctx)
+	require.NoError(s.T(), err)
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Consumer == consumer {
+			return checkpoint.Sequence
+		}
+	}
+	return 0
+}
+
+func (s *OutboxRelaySuite) TestRelayPending_HandsEachConsumerEveryEventOnce() {
+	s.append(time.Now(), "e1", "e2", "e3")
+	webhooks, audit := &recordingConsumer{}, &recordingConsumer{}
+	relay := s.newRelay(map[string]domain.EventPublisher{"webhooks": webhooks, "audit": audit})
+
+	require.NoError(s.T(), relay.RelayPending(s.ctx))
+	s.append(time.Now(), "e4")
+	require.NoError(s.T(), relay.RelayPending(s.ctx))
+	require.NoError(s.T(), relay.RelayPending(s.ctx))
+
+	// Three events take two batches of two.
+	assert.Equal(s.T(), []string{"e1", "e2", "e3", "e4"}, webhooks.Handled())
+	assert.Equal(s.T(), []string{"e1", "e2", "e3", "e4"}, audit.Handled())
+	assert.Equal(s.T(), int64(4), s.checkpoint("webhooks"))
+}
+
+func (s *OutboxRelaySuite) TestRelayPending_ResumesFromCheckpoint() {
+	// Leases run out at once, so a second relay can take over.
+	s.config.Lease = 0
+	s.append(time.Now(), "e1", "e2")
+	first := &recordingConsumer{}
+	require.NoError(s.T(), s.newRelay(map[string]domain.EventPublisher{"webhooks": first}).RelayPending(s.ctx))
+
+	s.append(time.Now(), "e3")
+	second := &recordingConsumer{}
+	require.NoError(s.T(), s.newRelay(map[string]domain.EventPublisher{"webhooks": second}).RelayPending(s.ctx))
+
+	assert.Equal(s.T(), []string{"e1", "e2"}, first.Handled())
+	assert.Equal(s.T(), []string{"e3"}, second.Handled())
+}
+
+func (s *OutboxRelaySuite) TestRelayPending_SkipsConsumerLeasedByAnotherRelay() {
+	s.append(time.Now(), "e1")
+	_, err := s.checkpoints.ClaimCheckpoint(s.ctx, "webhooks", "other-relay", time.Now(), time.Now().Add(time.Minute))
+	require.NoError(s.T(), err)
+	webhooks, audit := &recordingConsumer{}, &recordingConsumer{}
+
+	require.NoError(s.T(), s.newRelay(map[string]domain.EventPublisher{"webhooks": webhooks, "audit": audit}).RelayPending(s.ctx))
+
+	assert.Empty(s.T(), webhooks.Handled())
+	assert.Equal(s.T(), []string{"e1"}, audit.Handled())
+}
+
+func (s *OutboxRelaySuite) TestRelayPending_RetriesFailedEvent() {
+	s.append(time.Now(), "e1", "e2")
+	failing := &recordingConsumer{failures: 1}
+	healthy := &recordingConsumer{}
+	relay := s.newRelay(map[string]domain.EventPublisher{"failing": failing, "healthy": healthy})
+
+	err := relay.RelayPending(s.ctx)
+	assert.ErrorContains(s.T(), err, "consumer failing: event 1: consumer down")
+	assert.Equal(s.T(), []string{"e1", "e2"}, healthy.Handled())
+	assert.Zero(s.T(), s.checkpoint("failing"))
+
+	require.NoError(s.T(), relay.RelayPending(s.ctx))
+	assert.Equal(s.T(), []string{"e1", "e2"}, failing.Handled())
+}
+
+func (s *OutboxRelaySuite) TestRelayPending_PrunesHandledEvents() {
+	old := time.Now().Add(-2 * s.config.Retention)
+	s.append(old, "e1", "e2")
+	s.append(time.Now(), "e3")
+	relay := s.newRelay(map[string]domain.EventPublisher{"webhooks": &recordingConsumer{}})
+
+	require.NoError(s.T(), relay.RelayPending(s.ctx))
+
+	events, err := s.outbox.GetEventsAfter(s.ctx, 0, 10)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), events, 1)
+	assert.Equal(s.T(), "e3", events[0].ID)
+}
+
+func (s *OutboxRelaySuite) TestRelayPending_KeepsEventsAnotherConsumerNeeds() {
+	s.append(time.Now().Add(-2*s.config.Retention), "e1")
+	// A consumer served by another relay has not handled e1 yet.
+	_, err := s.checkpoints.ClaimCheckpoint(s.ctx, "elsewhere", "other-relay", time.Now(), time.Now().Add(time.Minute))
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), s.newRelay(map[string]domain.EventPublisher{"webhooks": &recordingConsumer{}}).RelayPending(s.ctx))
+
+	events, err := s.outbox.GetEventsAfter(s.ctx, 0, 10)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), events, 1)
+}
+
+func (s *OutboxRelaySuite) TestRun_StopsOnCancel() {
+	s.append(time.Now(), "e1")
+	consumer := &recordingConsumer{}
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	go func() {
+		s.newRelay(map[string]domain.EventPublisher{"webhooks": consumer}).Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(s.T(), func() bool { return len(consumer.Handled()) == 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.T().Fatal("relay did not stop")
+	}
+}
+
+// taskWritingConsumer stores a task for each event it is handed, then
+// fails while fail is set.
+type taskWritingConsumer struct {
+	tasks domain.TaskRepository
+	fail  bool
+}
+
+func (tc *taskWritingConsumer) Publish(ctx context.Context, event *domain.Event) error {
+	if err := tc.tasks.CreateTask(ctx, &domain.Task{ID: "from-" + event.ID, UserID: "u1"}); err != nil {
+		return err
+	}
+	if tc.fail {
+		return errors.New("consumer down")
+	}
+	return nil
+}
+
+// TestOutboxRelay_ConsumerWritesAndCheckpointCommitTogether runs the relay
+// on SQLite, where a consumer's writes and its checkpoint share a
+// transaction: a failed event leaves neither behind, and its retry stores
+// the consumer's writes exactly once.
+func TestOutboxRelay_ConsumerWritesAndCheckpointCommitTogether(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "relay.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+	outbox := repository.NewSQLiteOutboxRepository(db)
+	checkpoints := repository.NewSQLiteOutboxCheckpointRepository(db)
+	tasks := repository.NewSQLiteTaskRepository(db)
+	consumer := &taskWritingConsumer{tasks: tasks, fail: true}
+	relay := taskUsecases.NewOutboxRelay(outbox, checkpoints, repository.NewSQLiteTransactor(db),
+		map[string]domain.EventPublisher{"tasks": consumer}, domain.DefaultOutboxRelay, time.Second)
+	require.NoError(t, outbox.AppendEvent(ctx, &domain.Event{ID: "e1", Type: domain.EventTaskCreated, OccurredAt: time.Now()}))
+
+	assert.Error(t, relay.RelayPending(ctx))
+	_, err = tasks.GetTaskByID(ctx, "from-e1")
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	consumer.fail = false
+	require.NoError(t, relay.RelayPending(ctx))
+	require.NoError(t, relay.RelayPending(ctx))
+	_, err = tasks.GetTaskByID(ctx, "from-e1")
+	assert.NoError(t, err)
+	saved, err := checkpoints.GetCheckpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, int64(1), saved[0].Sequence)
+}
+
+func TestOutboxPublisher_KeepsPasswordsOutOfTheOutbox(t *testing.T) {
+	ctx := context.Background()
+	outbox := repository.NewInMemoryOutboxRepository()
+	publisher := taskUsecases.NewOutboxPublisher(outbox)
+	user := &domain.User{ID: "u1", Username: "alice", Password: "hash"}
+	event := &domain.Event{ID: "e1", Type: domain.EventUserCreated, OccurredAt: time.Now(), User: user}
+
+	require.NoError(t, publisher.Publish(ctx, event))
+
+	assert.Equal(t, int64(1), event.Sequence)
+	assert.Equal(t, "hash", user.Password)
+	events, err := outbox.GetEventsAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "alice", events[0].User.Username)
+	assert.Empty(t, events[0].User.Password)
+}
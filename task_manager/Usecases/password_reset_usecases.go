@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type passwordResetUsecases struct {
+	resetTokenRepository domain.PasswordResetTokenRepository
+	userRepository       domain.UserRepository
+	personalTokens       domain.PersonalAccessTokenRepository
+	passwordService      domain.IPasswordService
+	mailer               domain.IMailer
+	tokenUsecases        domain.TokenUsecases
+	resetURL             string
+	resetTTL             time.Duration
+	transactor           domain.Transactor
+	auditLog             domain.AuditRepository
+	contextTimeout       time.Duration
+}
+
+// NewPasswordResetUsecases mails reset tokens that expire after resetTTL.
+// When resetURL is set, the mail links to it with the token appended as the
+// "token" query parameter; otherwise it contains the bare token. A reset
+// revokes the user's personal access tokens from personalTokens, as it
+// does their sessions, and is recorded in auditLog in the same transaction
+// of transactor; either may be nil.
+func NewPasswordResetUsecases(resetTokenRepository domain.PasswordResetTokenRepository, userRepository domain.UserRepository, personalTokens domain.PersonalAccessTokenRepository, ps domain.IPasswordService, mailer domain.IMailer, tokens domain.TokenUsecases, resetURL string, resetTTL time.Duration, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.PasswordResetUsecases {
+	return &passwordResetUsecases{
+		resetTokenRepository: resetTokenRepository,
+		userRepository:       userRepository,
+		personalTokens:       personalTokens,
+		passwordService:      ps,
+		mailer:               mailer,
+		tokenUsecases:        tokens,
+		resetURL:             resetURL,
+		resetTTL:             resetTTL,
+		transactor:           transactor,
+		auditLog:             auditLog,
+		contextTimeout:       contextTimeout,
+	}
+}
+
+func (pu *passwordResetUsecases) RequestPasswordReset(ctx context.Context, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, pu.contextTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+	if user.Disabled {
+		return nil
+	}
+
+	resetToken, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = pu.resetTokenRepository.CreatePasswordResetToken(ctx, &domain.PasswordResetToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		TokenHash: hashToken(resetToken),
+		CreatedAt: now,
+		ExpiresAt: now.Add(pu.resetTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return pu.mailer.Send(ctx, &domain.MailMessage{
+		To:      user.Email,
+		Subject: "Reset your Task Manager password",
+		Body:    pu.resetMailBody(user, resetToken),
+	})
+}
+
+func (pu *passwordResetUsecases) ResetPassword(ctx context.Context, resetToken string, newPassword string) error {
+	ctx, cancel := context.WithTimeout(ctx, pu.contextTimeout)
+	defer cancel()
+
+	stored, err := pu.resetTokenRepository.GetPasswordResetTokenByHash(ctx, hashToken(resetToken))
+	if err != nil {
+		if err == domain.ErrTokenNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	now := time.Now()
+	if stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+		return domain.ErrInvalidToken
+	}
+
+	user, err := pu.userRepository.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	if user.Disabled {
+		return domain.ErrAccountDisabled
+	}
+
+	// Hash before spending the token, so a hashing failure leaves it usable.
+	hashed, err := pu.passwordService.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	err = inTransaction(ctx, pu.transactor, func(ctx context.Context) error {
+		marked, err := pu.resetTokenRepository.MarkPasswordResetTokenUsed(ctx, stored.ID, now)
+		if err != nil {
+			return err
+		}
+		if !marked {
+			return domain.ErrInvalidToken
+		}
+
+		if err := pu.userRepository.UpdateUserPassword(ctx, user.ID, hashed); err != nil {
+			return err
+		}
+		// Personal access tokens survive token version bumps, so whoever
+		// knew the old password could have minted one; they go too.
+		if err := pu.personalTokens.DeletePersonalAccessTokensByUser(ctx, user.ID); err != nil {
+			return err
+		}
+		// Any other links mailed to the user are now stale.
+		if err := pu.resetTokenRepository.DeletePasswordResetTokensByUser(ctx, user.ID); err != nil {
+			return err
+		}
+		// Whoever holds the token acts as the user.
+		return recordAudit(ctx, pu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditPasswordReset, TargetType: domain.AuditTargetUser, TargetID: user.ID,
+		}, user, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+	pu.tokenUsecases.ForgetTokenVersion(user.ID)
+	return nil
+}
+
+func (pu *passwordResetUsecases) resetMailBody(user *domain.User, resetToken string) string {
+	instructions := "Use this token with POST /password/reset:\n\n" + resetToken
+	if pu.resetURL != "" {
+		instructions = "Open this link to choose a new password:\n\n" + withQueryParam(pu.resetURL, "token", resetToken)
+	}
+	return fmt.Sprintf("Hello %s,\n\nSomeone asked to reset the password of your Task Manager account. %s\n\n"+
+		"It expires in %s and works only once. If you did not ask for this, you can ignore this email.\n",
+		user.Username, instructions, pu.resetTTL)
+}
+
+// withQueryParam appends key=value to rawURL, keeping any query it has.
+func withQueryParam(rawURL, key, value string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL + "?" + url.Values{key: {value}}.Encode()
+	}
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,175 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	resetUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordResetUsecaseSuite struct {
+	suite.Suite
+	resets         domain.PasswordResetTokenRepository
+	userRepo       *mocks.UserRepository
+	ps             *mocks.IPasswordService
+	mailer         *mocks.IMailer
+	tokens         *mocks.TokenUsecases
+	personalTokens domain.PersonalAccessTokenRepository
+	auditLog       domain.AuditRepository
+	uc             domain.PasswordResetUsecases
+	user           *domain.User
+	sent           []*domain.MailMessage
+}
+
+func (s *PasswordResetUsecaseSuite) SetupTest() {
+	s.resets = repository.NewInMemoryPasswordResetTokenRepository()
+	s.userRepo = new(mocks.UserRepository)
+	s.ps = new(mocks.IPasswordService)
+	s.mailer = new(mocks.IMailer)
+	s.tokens = new(mocks.TokenUsecases)
+	s.personalTokens = repository.NewInMemoryPersonalAccessTokenRepository()
+	s.auditLog = repository.NewInMemoryAuditRepository()
+	s.uc = resetUsecases.NewPasswordResetUsecases(s.resets, s.userRepo, s.personalTokens, s.ps, s.mailer, s.tokens, "https://tasks.example.com/reset?lang=en", time.Hour, nil, s.auditLog, 2*time.Second)
+	s.user = &domain.User{ID: "u1", Username: "john", Email: "john@example.com"}
+	s.sent = nil
+	s.mailer.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		s.sent = append(s.sent, args.Get(1).(*domain.MailMessage))
+	}).Return(nil).Maybe()
+}
+
+func TestPasswordResetUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetUsecaseSuite))
+}
+
+var resetLinkPattern = regexp.MustCompile(`https://tasks\.example\.com/reset\?\S+`)
+
+// requestToken asks for a reset of s.user and returns the token from the
+// mailed link.
+func (s *PasswordResetUsecaseSuite) requestToken() string {
+	s.userRepo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(s.user, nil).Once()
+	s.Require().NoError(s.uc.RequestPasswordReset(context.Background(), "john@example.com"))
+	s.Require().NotEmpty(s.sent)
+
+	link, err := url.Parse(resetLinkPattern.FindString(s.sent[len(s.sent)-1].Body))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "en", link.Query().Get("lang"))
+	return link.Query().Get("token")
+}
+
+func (s *PasswordResetUsecaseSuite) TestRequestPasswordReset_MailsLink() {
+	token := s.requestToken()
+
+	assert.NotEmpty(s.T(), token)
+	assert.Equal(s.T(), "john@example.com", s.sent[0].To)
+	assert.Contains(s.T(), s.sent[0].Body, "Hello john")
+}
+
+func (s *PasswordResetUsecaseSuite) TestRequestPasswordReset_UnknownEmailIsSilent() {
+	s.userRepo.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(nil, domain.ErrUserNotFound).Once()
+
+	assert.NoError(s.T(), s.uc.RequestPasswordReset(context.Background(), "nobody@example.com"))
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (s *PasswordResetUsecaseSuite) TestRequestPasswordReset_DisabledAccountIsSilent() {
+	s.user.Disabled = true
+	s.userRepo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(s.user, nil).Once()
+
+	assert.NoError(s.T(), s.uc.RequestPasswordReset(context.Background(), "john@example.com"))
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_Success() {
+	token := s.requestToken()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+	s.ps.On("HashPassword", "n3w-secret").Return("new-hash", nil).Once()
+	s.userRepo.On("UpdateUserPassword", mock.Anything, "u1", "new-hash").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+
+	assert.NoError(s.T(), s.uc.ResetPassword(context.Background(), token, "n3w-secret"))
+	s.userRepo.AssertExpectations(s.T())
+	s.ps.AssertExpectations(s.T())
+	s.tokens.AssertExpectations(s.T())
+
+	page, err := s.auditLog.GetAuditEntries(context.Background(), domain.AuditQuery{TargetID: "u1"})
+	s.Require().NoError(err)
+	s.Require().Len(page.Entries, 1)
+	assert.Equal(s.T(), domain.AuditPasswordReset, page.Entries[0].Action)
+	assert.Equal(s.T(), "u1", page.Entries[0].ActorID)
+
+	// The token is spent.
+	assert.ErrorIs(s.T(), s.uc.ResetPassword(context.Background(), token, "again"), domain.ErrInvalidToken)
+}
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_RevokesPersonalAccessTokens() {
+	ctx := context.Background()
+	expires := time.Now().Add(time.Hour)
+	s.Require().NoError(s.personalTokens.CreatePersonalAccessToken(ctx, &domain.PersonalAccessToken{
+		ID: "pat1", UserID: "u1", Name: "ci", TokenHash: "hash-1", ExpiresAt: expires}))
+	s.Require().NoError(s.personalTokens.CreatePersonalAccessToken(ctx, &domain.PersonalAccessToken{
+		ID: "pat2", UserID: "u2", Name: "ci", TokenHash: "hash-2", ExpiresAt: expires}))
+	token := s.requestToken()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+	s.ps.On("HashPassword", "n3w-secret").Return("new-hash", nil).Once()
+	s.userRepo.On("UpdateUserPassword", mock.Anything, "u1", "new-hash").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+
+	s.Require().NoError(s.uc.ResetPassword(ctx, token, "n3w-secret"))
+
+	_, err := s.personalTokens.GetPersonalAccessTokenByHash(ctx, "hash-1")
+	assert.ErrorIs(s.T(), err, domain.ErrTokenNotFound)
+	_, err = s.personalTokens.GetPersonalAccessTokenByHash(ctx, "hash-2")
+	assert.NoError(s.T(), err, "other users keep their tokens")
+}
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_OtherLinksStopWorking() {
+	first := s.requestToken()
+	second := s.requestToken()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+	s.ps.On("HashPassword", "n3w-secret").Return("new-hash", nil).Once()
+	s.userRepo.On("UpdateUserPassword", mock.Anything, "u1", "new-hash").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+
+	s.Require().NoError(s.uc.ResetPassword(context.Background(), second, "n3w-secret"))
+	assert.ErrorIs(s.T(), s.uc.ResetPassword(context.Background(), first, "n3w-secret"), domain.ErrInvalidToken)
+}
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_UnknownToken() {
+	assert.ErrorIs(s.T(), s.uc.ResetPassword(context.Background(), "made-up", "n3w-secret"), domain.ErrInvalidToken)
+	s.ps.AssertNotCalled(s.T(), "HashPassword", mock.Anything)
+}
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_Expired() {
+	uc := resetUsecases.NewPasswordResetUsecases(s.resets, s.userRepo, s.personalTokens, s.ps, s.mailer, s.tokens, "https://tasks.example.com/reset", time.Nanosecond, nil, nil, 2*time.Second)
+	s.userRepo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(s.user, nil).Once()
+	s.Require().NoError(uc.RequestPasswordReset(context.Background(), "john@example.com"))
+	link, err := url.Parse(resetLinkPattern.FindString(s.sent[0].Body))
+	s.Require().NoError(err)
+
+	time.Sleep(time.Millisecond)
+	assert.ErrorIs(s.T(), uc.ResetPassword(context.Background(), link.Query().Get("token"), "n3w-secret"), domain.ErrInvalidToken)
+}
+
+func (s *PasswordResetUsecaseSuite) TestResetPassword_HashFailureKeepsToken() {
+	token := s.requestToken()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil)
+	s.ps.On("HashPassword", "n3w-secret").Return("", errors.New("hash error")).Once()
+
+	assert.Error(s.T(), s.uc.ResetPassword(context.Background(), token, "n3w-secret"))
+
+	s.ps.On("HashPassword", "n3w-secret").Return("new-hash", nil).Once()
+	s.userRepo.On("UpdateUserPassword", mock.Anything, "u1", "new-hash").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+	assert.NoError(s.T(), s.uc.ResetPassword(context.Background(), token, "n3w-secret"))
+}
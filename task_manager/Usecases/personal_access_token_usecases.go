@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+// lastUsedGranularity is how stale a token's LastUsedAt may get before a
+// request refreshes it, so that busy scripts do not write on every call.
+const lastUsedGranularity = time.Minute
+
+const maxTokenNameLength = 100
+
+type personalAccessTokenUsecases struct {
+	tokenRepository domain.PersonalAccessTokenRepository
+	userRepository  domain.UserRepository
+	roles           domain.RoleUsecases
+	maxTTL          time.Duration
+	transactor      domain.Transactor
+	auditLog        domain.AuditRepository
+	contextTimeout  time.Duration
+}
+
+// NewPersonalAccessTokenUsecases refuses tokens that would outlive maxTTL.
+// Tokens created and revoked are recorded in auditLog, in the same
+// transaction of transactor as the change; either may be nil.
+func NewPersonalAccessTokenUsecases(tokenRepository domain.PersonalAccessTokenRepository, userRepository domain.UserRepository, roles domain.RoleUsecases, maxTTL time.Duration, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.PersonalAccessTokenUsecases {
+	return &personalAccessTokenUsecases{
+		tokenRepository: tokenRepository,
+		userRepository:  userRepository,
+		roles:           roles,
+		maxTTL:          maxTTL,
+		transactor:      transactor,
+		auditLog:        auditLog,
+		contextTimeout:  contextTimeout,
+	}
+}
+
+func (pu *personalAccessTokenUsecases) CreateToken(ctx context.Context, userId string, name string, scopes []string, expiresAt time.Time) (*domain.NewPersonalAccessToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, pu.contextTimeout)
+	defer cancel()
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxTokenNameLength {
+		return nil, fmt.Errorf("%w: name must be 1-%d characters", domain.ErrInvalidPersonalAccessToken, maxTokenNameLength)
+	}
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expiry must be in the future", domain.ErrInvalidPersonalAccessToken)
+	}
+	if expiresAt.After(now.Add(pu.maxTTL)) {
+		return nil, fmt.Errorf("%w: expiry must be within %s", domain.ErrInvalidPersonalAccessToken, pu.maxTTL)
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", domain.ErrInvalidPersonalAccessToken)
+	}
+
+	user, err := pu.userRepository.GetUserByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	granted, err := pu.roles.GetPermissions(ctx, user.Role)
+	if err != nil && !errors.Is(err, domain.ErrRoleNotFound) {
+		return nil, err
+	}
+	var unique []string
+	for _, scope := range scopes {
+		if !slices.Contains(domain.Permissions, scope) {
+			return nil, fmt.Errorf("%w: unknown scope %q", domain.ErrInvalidPersonalAccessToken, scope)
+		}
+		// A token can never do more than its owner.
+		if !slices.Contains(granted, scope) {
+			return nil, fmt.Errorf("%w: your role does not grant %q", domain.ErrInvalidPersonalAccessToken, scope)
+		}
+		if !slices.Contains(unique, scope) {
+			unique = append(unique, scope)
+		}
+	}
+
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	token := domain.PersonalAccessTokenPrefix + secret
+	record := &domain.PersonalAccessToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Name:      name,
+		TokenHash: hashToken(token),
+		Scopes:    unique,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	err = inTransaction(ctx, pu.transactor, func(ctx context.Context) error {
+		if err := pu.tokenRepository.CreatePersonalAccessToken(ctx, record); err != nil {
+			return err
+		}
+		return recordAudit(ctx, pu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTokenCreate, TargetType: domain.AuditTargetToken, TargetID: record.ID,
+		}, nil, nil, record)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.NewPersonalAccessToken{Token: token, Record: record}, nil
+}
+
+func (pu *personalAccessTokenUsecases) ListTokens(ctx context.Context, userId string) ([]*domain.PersonalAccessToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, pu.contextTimeout)
+	defer cancel()
+
+	return pu.tokenRepository.GetPersonalAccessTokensByUser(ctx, userId)
+}
+
+func (pu *personalAccessTokenUsecases) RevokeToken(ctx context.Context, userId string, tokenId string) error {
+	ctx, cancel := context.WithTimeout(ctx, pu.contextTimeout)
+	defer cancel()
+
+	return inTransaction(ctx, pu.transactor, func(ctx context.Context) error {
+		if err := pu.tokenRepository.DeletePersonalAccessToken(ctx, userId, tokenId); err != nil {
+			return err
+		}
+		return recordAudit(ctx, pu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTokenRevoke, TargetType: domain.AuditTargetToken, TargetID: tokenId,
+		}, nil, nil, nil)
+	})
+}
+
+// Authenticate looks the token up by hash. Unlike access tokens, personal
+// access tokens survive a bump of the user's TokenVersion: they are meant
+// for automation, and their scopes are intersected with the role's
+// permissions on every request anyway. Disabling the account stops them,
+// and a password reset deletes them.
+func (pu *personalAccessTokenUsecases) Authenticate(ctx context.Context, token string) (*domain.User, *domain.PersonalAccessToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, pu.contextTimeout)
+	defer cancel()
+
+	if !strings.HasPrefix(token, domain.PersonalAccessTokenPrefix) {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	stored, err := pu.tokenRepository.GetPersonalAccessTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			return nil, nil, domain.ErrInvalidToken
+		}
+		return nil, nil, err
+	}
+	now := time.Now()
+	if !now.Before(stored.ExpiresAt) {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	user, err := pu.userRepository.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, nil, domain.ErrTokenRevoked
+		}
+		return nil, nil, err
+	}
+	if user.Disabled {
+		return nil, nil, domain.ErrAccountDisabled
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= lastUsedGranularity {
+		if err := pu.tokenRepository.TouchPersonalAccessToken(ctx, stored.ID, now); err != nil {
+			return nil, nil, err
+		}
+		stored.LastUsedAt = &now
+	}
+	return user, stored, nil
+}
@@ -0,0 +1,145 @@
+package usecases_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	personalTokenUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PersonalAccessTokenUsecaseSuite struct {
+	suite.Suite
+	tokenRepo domain.PersonalAccessTokenRepository
+	userRepo  *mocks.UserRepository
+	roles     *mocks.RoleUsecases
+	uc        domain.PersonalAccessTokenUsecases
+	user      *domain.User
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) SetupTest() {
+	s.tokenRepo = repository.NewInMemoryPersonalAccessTokenRepository()
+	s.userRepo = new(mocks.UserRepository)
+	s.roles = new(mocks.RoleUsecases)
+	s.uc = personalTokenUsecases.NewPersonalAccessTokenUsecases(s.tokenRepo, s.userRepo, s.roles, 30*24*time.Hour, nil, nil, 2*time.Second)
+
+	s.user = &domain.User{ID: "u1", Role: "editor"}
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil)
+	s.roles.On("GetPermissions", mock.Anything, "editor").Return([]string{domain.PermTaskRead, domain.PermTaskCreate}, nil)
+}
+
+func TestPersonalAccessTokenUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(PersonalAccessTokenUsecaseSuite))
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) create(scopes ...string) *domain.NewPersonalAccessToken {
+	created, err := s.uc.CreateToken(context.Background(), "u1", "ci", scopes, time.Now().Add(24*time.Hour))
+	require.NoError(s.T(), err)
+	return created
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestCreateToken_StoresOnlyTheHash() {
+	created := s.create(domain.PermTaskRead, domain.PermTaskRead)
+
+	assert.True(s.T(), strings.HasPrefix(created.Token, domain.PersonalAccessTokenPrefix))
+	assert.Equal(s.T(), []string{domain.PermTaskRead}, created.Record.Scopes)
+
+	tokens, err := s.uc.ListTokens(context.Background(), "u1")
+	require.NoError(s.T(), err)
+	require.Len(s.T(), tokens, 1)
+	assert.Equal(s.T(), "ci", tokens[0].Name)
+	assert.NotEqual(s.T(), created.Token, tokens[0].TokenHash)
+	assert.NotContains(s.T(), tokens[0].TokenHash, strings.TrimPrefix(created.Token, domain.PersonalAccessTokenPrefix))
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestCreateToken_Invalid() {
+	ctx := context.Background()
+	tomorrow := time.Now().Add(24 * time.Hour)
+	cases := map[string]struct {
+		name      string
+		scopes    []string
+		expiresAt time.Time
+	}{
+		"blank name":        {"  ", []string{domain.PermTaskRead}, tomorrow},
+		"no scopes":         {"ci", nil, tomorrow},
+		"unknown scope":     {"ci", []string{"task:launch"}, tomorrow},
+		"scope not granted": {"ci", []string{domain.PermTaskDelete}, tomorrow},
+		"already expired":   {"ci", []string{domain.PermTaskRead}, time.Now().Add(-time.Minute)},
+		"beyond max ttl":    {"ci", []string{domain.PermTaskRead}, time.Now().Add(31 * 24 * time.Hour)},
+	}
+	for name, tc := range cases {
+		_, err := s.uc.CreateToken(ctx, "u1", tc.name, tc.scopes, tc.expiresAt)
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidPersonalAccessToken, name)
+	}
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate() {
+	created := s.create(domain.PermTaskRead)
+
+	user, token, err := s.uc.Authenticate(context.Background(), created.Token)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "u1", user.ID)
+	assert.Equal(s.T(), created.Record.ID, token.ID)
+	require.NotNil(s.T(), token.LastUsedAt)
+
+	tokens, err := s.uc.ListTokens(context.Background(), "u1")
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), tokens[0].LastUsedAt)
+	assert.True(s.T(), tokens[0].LastUsedAt.Equal(*token.LastUsedAt))
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate_Rejected() {
+	ctx := context.Background()
+	created := s.create(domain.PermTaskRead)
+
+	_, _, err := s.uc.Authenticate(ctx, created.Token+"x")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+	_, _, err = s.uc.Authenticate(ctx, "not-a-personal-token")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+
+	s.user.Disabled = true
+	_, _, err = s.uc.Authenticate(ctx, created.Token)
+	assert.ErrorIs(s.T(), err, domain.ErrAccountDisabled)
+	s.user.Disabled = false
+
+	require.NoError(s.T(), s.uc.RevokeToken(ctx, "u1", created.Record.ID))
+	_, _, err = s.uc.Authenticate(ctx, created.Token)
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+	assert.ErrorIs(s.T(), s.uc.RevokeToken(ctx, "u1", created.Record.ID), domain.ErrTokenNotFound)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate_Expired() {
+	expired := &domain.PersonalAccessToken{ID: "old", UserID: "u1", Name: "old", TokenHash: hashForTest("pat_old"),
+		Scopes: []string{domain.PermTaskRead}, CreatedAt: time.Now().Add(-48 * time.Hour), ExpiresAt: time.Now().Add(-time.Hour)}
+	require.NoError(s.T(), s.tokenRepo.CreatePersonalAccessToken(context.Background(), expired))
+
+	_, _, err := s.uc.Authenticate(context.Background(), "pat_old")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+}
+
+func (s *PersonalAccessTokenUsecaseSuite) TestAuthenticate_DeletedUser() {
+	other := &domain.PersonalAccessToken{ID: "gone", UserID: "u2", Name: "gone", TokenHash: hashForTest("pat_gone"),
+		Scopes: []string{domain.PermTaskRead}, CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(s.T(), s.tokenRepo.CreatePersonalAccessToken(context.Background(), other))
+	s.userRepo.On("GetUserByID", mock.Anything, "u2").Return(nil, domain.ErrUserNotFound)
+
+	_, _, err := s.uc.Authenticate(context.Background(), "pat_gone")
+	assert.ErrorIs(s.T(), err, domain.ErrTokenRevoked)
+}
+
+// hashForTest mirrors how the usecases store opaque tokens.
+func hashForTest(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
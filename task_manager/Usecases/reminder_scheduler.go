@@ -0,0 +1,232 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type reminderScheduler struct {
+	reminderRepository domain.ReminderRepository
+	deliveryRepository domain.ReminderDeliveryRepository
+	taskRepository     domain.TaskRepository
+	userRepository     domain.UserRepository
+	workflow           *domain.Workflow
+	notifiers          map[string]domain.Notifier
+	config             domain.ReminderSchedulerConfig
+	// owner identifies this scheduler in the leases it takes.
+	owner          string
+	contextTimeout time.Duration
+}
+
+// NewReminderScheduler sends each reminder through the notifier registered
+// for its channel.
+func NewReminderScheduler(reminderRepository domain.ReminderRepository, deliveryRepository domain.ReminderDeliveryRepository, taskRepository domain.TaskRepository, userRepository domain.UserRepository, workflow *domain.Workflow, notifiers map[string]domain.Notifier, config domain.ReminderSchedulerConfig, contextTimeout time.Duration) domain.ReminderScheduler {
+	return &reminderScheduler{
+		reminderRepository: reminderRepository,
+		deliveryRepository: deliveryRepository,
+		taskRepository:     taskRepository,
+		userRepository:     userRepository,
+		workflow:           workflow,
+		notifiers:          notifiers,
+		config:             config,
+		owner:              uuid.New().String(),
+		contextTimeout:     contextTimeout,
+	}
+}
+
+// errReminderObsolete marks a delivery that is no longer wanted.
+var errReminderObsolete = errors.New("reminder no longer applies")
+
+func (rs *reminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rs.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := rs.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Reminder scheduler: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue stops claiming once ctx is cancelled. Sends already under way
+// are finished, and deliveries claimed but not yet started are released for
+// the next scheduler.
+func (rs *reminderScheduler) DispatchDue(ctx context.Context) error {
+	if err := rs.enqueueDue(ctx, time.Now()); err != nil {
+		return err
+	}
+	for ctx.Err() == nil {
+		now := time.Now()
+		claimed, err := rs.deliveryRepository.ClaimReminderDeliveries(ctx, rs.owner, now, now.Add(rs.config.Lease), rs.config.BatchSize)
+		// A failed claim may still have leased some deliveries; they are
+		// sent, or released, rather than left until their lease runs out.
+		rs.deliverAll(ctx, claimed)
+		if err != nil {
+			return err
+		}
+		if len(claimed) < rs.config.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// enqueueDue records a pending delivery for every reminder whose time came
+// in the last MaxLateness. Each lead in use is one window of due dates, and
+// enqueueing is idempotent, so overlapping runs and instances are harmless.
+func (rs *reminderScheduler) enqueueDue(ctx context.Context, now time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, rs.contextTimeout)
+	defer cancel()
+
+	leads, err := rs.reminderRepository.GetReminderLeads(ctx)
+	if err != nil {
+		return err
+	}
+	byUser := make(map[string][]*domain.Reminder)
+	for _, lead := range leads {
+		tasks, err := rs.taskRepository.GetTasksDueBetween(ctx, now.Add(lead-rs.config.MaxLateness), now.Add(lead))
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if !rs.workflow.IsOpen(task.Status) {
+				continue
+			}
+			reminders, ok := byUser[task.UserID]
+			if !ok {
+				if reminders, err = rs.reminderRepository.GetRemindersByUser(ctx, task.UserID); err != nil {
+					return err
+				}
+				byUser[task.UserID] = reminders
+			}
+			for _, reminder := range reminders {
+				if reminder.Lead != lead {
+					continue
+				}
+				sendAt := task.DueDate.Add(-lead)
+				slot := reminder.ID + "/" + task.ID + "/" + sendAt.UTC().Format(time.RFC3339Nano)
+				if err := rs.deliveryRepository.EnqueueReminderDelivery(ctx, &domain.ReminderDelivery{
+					ID:         uuid.NewSHA1(uuid.NameSpaceURL, []byte(slot)).String(),
+					ReminderID: reminder.ID,
+					TaskID:     task.ID,
+					UserID:     task.UserID,
+					Channel:    reminder.Channel,
+					SendAt:     sendAt,
+					Status:     domain.ReminderPending,
+					CreatedAt:  now,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// deliverAll sends the claimed deliveries on up to Workers goroutines and
+// waits for them.
+func (rs *reminderScheduler) deliverAll(ctx context.Context, claimed []*domain.ReminderDelivery) {
+	jobs := make(chan *domain.ReminderDelivery)
+	var wg sync.WaitGroup
+	for range min(max(rs.config.Workers, 1), len(claimed)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range jobs {
+				rs.deliver(ctx, delivery)
+			}
+		}()
+	}
+	for _, delivery := range claimed {
+		jobs <- delivery
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// deliver sends one claimed delivery and records the outcome. The send and
+// the record are not cut short by ctx, so that shutting down does not lose
+// a reminder that has gone out. A delivery whose send could outlast its
+// lease is not started, since another scheduler could claim and send it
+// meanwhile.
+func (rs *reminderScheduler) deliver(ctx context.Context, delivery *domain.ReminderDelivery) {
+	sendTimeout := rs.config.Lease / 2
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	defer cancel()
+
+	if ctx.Err() != nil || time.Now().Add(sendTimeout).After(delivery.LeaseUntil) {
+		// Not started: hand it back without counting the attempt.
+		delivery.Attempts--
+		delivery.LeaseUntil = time.Time{}
+	} else if notice, err := rs.notice(sendCtx, delivery); errors.Is(err, errReminderObsolete) {
+		delivery.Status = domain.ReminderCancelled
+	} else if err != nil {
+		rs.retryLater(delivery, err)
+	} else if notifier, ok := rs.notifiers[delivery.Channel]; !ok {
+		delivery.Status = domain.ReminderFailed
+		delivery.LastError = "no notifier for channel " + delivery.Channel
+	} else if err := notifier.Notify(sendCtx, notice); err != nil {
+		rs.retryLater(delivery, err)
+	} else {
+		sentAt := time.Now()
+		delivery.Status = domain.ReminderSent
+		delivery.SentAt = &sentAt
+		delivery.LastError = ""
+	}
+
+	if err := rs.deliveryRepository.FinishReminderDelivery(sendCtx, delivery, rs.owner); err != nil {
+		log.Printf("Reminder delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// notice loads what a delivery is about, failing with errReminderObsolete if
+// the reminder, task or user is gone, the task has been closed, or its due
+// date has moved since the delivery was recorded.
+func (rs *reminderScheduler) notice(ctx context.Context, delivery *domain.ReminderDelivery) (*domain.ReminderNotice, error) {
+	reminder, err := rs.reminderRepository.GetReminderByID(ctx, delivery.ReminderID)
+	if errors.Is(err, domain.ErrReminderNotFound) {
+		return nil, errReminderObsolete
+	} else if err != nil {
+		return nil, err
+	}
+	task, err := rs.taskRepository.GetTaskByID(ctx, delivery.TaskID)
+	if errors.Is(err, domain.ErrTaskNotFound) || err == nil && task == nil {
+		return nil, errReminderObsolete
+	} else if err != nil {
+		return nil, err
+	}
+	if !rs.workflow.IsOpen(task.Status) || !task.DueDate.Add(-reminder.Lead).Equal(delivery.SendAt) {
+		return nil, errReminderObsolete
+	}
+	user, err := rs.userRepository.GetUserByID(ctx, delivery.UserID)
+	if errors.Is(err, domain.ErrUserNotFound) || err == nil && user.Disabled {
+		return nil, errReminderObsolete
+	} else if err != nil {
+		return nil, err
+	}
+	return &domain.ReminderNotice{User: user, Task: task, Reminder: reminder}, nil
+}
+
+// retryLater leaves a delivery pending until its backoff has passed, or
+// fails it once it is out of attempts.
+func (rs *reminderScheduler) retryLater(delivery *domain.ReminderDelivery, err error) {
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= rs.config.MaxAttempts {
+		delivery.Status = domain.ReminderFailed
+		return
+	}
+	backoff := rs.config.RetryBackoff << (delivery.Attempts - 1)
+	delivery.LeaseUntil = time.Now().Add(backoff)
+}
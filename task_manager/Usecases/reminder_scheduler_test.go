@@ -0,0 +1,266 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	taskUsecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingNotifier records the tasks it is asked to remind about, failing
+// the first failures calls. Each call takes delay.
+type recordingNotifier struct {
+	mu       sync.Mutex
+	delay    time.Duration
+	failures int
+	sent     []string
+}
+
+func (rn *recordingNotifier) Notify(ctx context.Context, notice *domain.ReminderNotice) error {
+	time.Sleep(rn.delay)
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+
+	if rn.failures > 0 {
+		rn.failures--
+		return errors.New("mail server unavailable")
+	}
+	rn.sent = append(rn.sent, notice.Task.ID)
+	return nil
+}
+
+func (rn *recordingNotifier) Sent() []string {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	return append([]string(nil), rn.sent...)
+}
+
+type ReminderSchedulerSuite struct {
+	suite.Suite
+	reminders  domain.ReminderRepository
+	deliveries domain.ReminderDeliveryRepository
+	tasks      domain.TaskRepository
+	users      domain.UserRepository
+	notifier   *recordingNotifier
+	config     domain.ReminderSchedulerConfig
+	ctx        context.Context
+	now        time.Time
+}
+
+func (s *ReminderSchedulerSuite) SetupTest() {
+	s.reminders = repository.NewInMemoryReminderRepository()
+	s.deliveries = repository.NewInMemoryReminderDeliveryRepository()
+	s.tasks = repository.NewInMemoryTaskRepository()
+	s.users = repository.NewInMemoryUserRepository()
+	s.notifier = &recordingNotifier{}
+	s.config = domain.ReminderSchedulerConfig{
+		PollInterval: time.Second,
+		MaxLateness:  time.Hour,
+		Lease:        time.Minute,
+		MaxAttempts:  2,
+		RetryBackoff: 20 * time.Millisecond,
+		Workers:      2,
+		BatchSize:    2,
+	}
+	s.ctx = context.Background()
+	s.now = time.Now()
+
+	for _, user := range []*domain.User{
+		{ID: "user-id", Username: "owner", Email: "owner@example.com", Role: domain.RoleUser},
+		{ID: "other-id", Username: "stranger", Email: "stranger@example.com", Role: domain.RoleUser},
+	} {
+		_, err := s.users.CreateUser(s.ctx, user)
+		require.NoError(s.T(), err)
+	}
+	for _, reminder := range []*domain.Reminder{
+		{ID: "day-before", UserID: "user-id", Lead: 24 * time.Hour, Channel: "test"},
+		{ID: "at-due", UserID: "user-id", Lead: 0, Channel: "test"},
+		{ID: "hour-before", UserID: "other-id", Lead: time.Hour, Channel: "test"},
+	} {
+		require.NoError(s.T(), s.reminders.CreateReminder(s.ctx, reminder))
+	}
+}
+
+func TestReminderSchedulerSuite(t *testing.T) {
+	suite.Run(t, new(ReminderSchedulerSuite))
+}
+
+func (s *ReminderSchedulerSuite) scheduler() domain.ReminderScheduler {
+	return taskUsecases.NewReminderScheduler(s.reminders, s.deliveries, s.tasks, s.users, domain.DefaultWorkflow(),
+		map[string]domain.Notifier{"test": s.notifier}, s.config, 2*time.Second)
+}
+
+func (s *ReminderSchedulerSuite) createTask(id, userId, status string, due time.Time) {
+	require.NoError(s.T(), s.tasks.CreateTask(s.ctx, &domain.Task{ID: id, UserID: userId, Title: id, Status: status, DueDate: due}))
+}
+
+func (s *ReminderSchedulerSuite) TestDispatchDue() {
+	assert := assert.New(s.T())
+	// Its day-before reminder came due a minute ago.
+	s.createTask("tomorrow", "user-id", "todo", s.now.Add(24*time.Hour-time.Minute))
+	// Due a minute ago, so its at-due reminder is sent; its day-before one
+	// was too long ago.
+	s.createTask("now", "user-id", "in_progress", s.now.Add(-time.Minute))
+	s.createTask("later", "user-id", "todo", s.now.Add(2*time.Hour))
+	s.createTask("closed", "user-id", "done", s.now.Add(-time.Minute))
+	s.createTask("missed", "user-id", "todo", s.now.Add(-2*time.Hour))
+	// other-id only has an hour-before reminder.
+	s.createTask("theirs", "other-id", "todo", s.now.Add(30*time.Minute))
+	s.createTask("not-yet", "other-id", "todo", s.now.Add(24*time.Hour-time.Minute))
+
+	scheduler := s.scheduler()
+	require.NoError(s.T(), scheduler.DispatchDue(s.ctx))
+	assert.ElementsMatch([]string{"tomorrow", "now", "theirs"}, s.notifier.Sent())
+
+	// Nothing is sent twice.
+	require.NoError(s.T(), scheduler.DispatchDue(s.ctx))
+	assert.Len(s.notifier.Sent(), 3)
+}
+
+func (s *ReminderSchedulerSuite) TestDispatchDue_ConcurrentSchedulers() {
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		s.createTask(id, "user-id", "todo", s.now.Add(-time.Minute))
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		scheduler := s.scheduler()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(s.T(), scheduler.DispatchDue(s.ctx))
+		}()
+	}
+	wg.Wait()
+	assert.ElementsMatch(s.T(), []string{"a", "b", "c", "d", "e"}, s.notifier.Sent())
+}
+
+// A batch that takes longer to send than its lease must not be sent again
+// by a scheduler that claims what looks abandoned.
+func (s *ReminderSchedulerSuite) TestDispatchDue_SlowSendsKeepWithinLease() {
+	ids := []string{"a", "b", "c", "d", "e"}
+	for _, id := range ids {
+		s.createTask(id, "user-id", "todo", s.now.Add(-time.Minute))
+	}
+	s.notifier.delay = 100 * time.Millisecond
+	s.config.Lease = 300 * time.Millisecond
+	s.config.Workers = 1
+	s.config.BatchSize = len(ids)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(s.T(), s.scheduler().DispatchDue(s.ctx))
+	}()
+	other := s.scheduler()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		case <-time.After(10 * time.Millisecond):
+			assert.NoError(s.T(), other.DispatchDue(s.ctx))
+		}
+	}
+	require.NoError(s.T(), other.DispatchDue(s.ctx))
+	assert.ElementsMatch(s.T(), ids, s.notifier.Sent())
+}
+
+func (s *ReminderSchedulerSuite) TestDispatchDue_RetriesAfterBackoff() {
+	assert := assert.New(s.T())
+	s.notifier.failures = 1
+	s.createTask("flaky", "user-id", "todo", s.now.Add(-time.Minute))
+	scheduler := s.scheduler()
+
+	require.NoError(s.T(), scheduler.DispatchDue(s.ctx))
+	assert.Empty(s.notifier.Sent())
+	time.Sleep(s.config.RetryBackoff)
+	require.NoError(s.T(), scheduler.DispatchDue(s.ctx))
+	assert.Equal([]string{"flaky"}, s.notifier.Sent())
+}
+
+func (s *ReminderSchedulerSuite) TestDispatchDue_GivesUp() {
+	s.notifier.failures = s.config.MaxAttempts
+	s.createTask("down", "user-id", "todo", s.now.Add(-time.Minute))
+	scheduler := s.scheduler()
+
+	for range s.config.MaxAttempts + 1 {
+		require.NoError(s.T(), scheduler.DispatchDue(s.ctx))
+		time.Sleep(4 * s.config.RetryBackoff)
+	}
+	assert.Empty(s.T(), s.notifier.Sent())
+	assert.Zero(s.T(), s.notifier.failures)
+}
+
+func (s *ReminderSchedulerSuite) TestDispatchDue_CancelsObsoleteReminders() {
+	s.notifier.failures = 2
+	s.createTask("finished", "user-id", "todo", s.now.Add(-time.Minute))
+	s.createTask("moved", "user-id", "todo", s.now.Add(-time.Minute))
+	s.config.MaxAttempts = 3
+	scheduler := s.scheduler()
+	require.NoError(s.T(), scheduler.DispatchDue(s.ctx))
+
+	// Closing a task or moving its due date cancels the pending retry.
+	_, err := s.tasks.UpdateTask(s.ctx, "finished", &domain.Task{ID: "finished", UserID: "user-id", Title: "finished", Status: "done", DueDate: s.now.Add(-time.Minute)})
+	require.NoError(s.T(), err)
+	_, err = s.tasks.UpdateTask(s.ctx, "moved", &domain.Task{ID: "moved", UserID: "user-id", Title: "moved", Status: "todo", DueDate: s.now.Add(time.Hour)})
+	require.NoError(s.T(), err)
+	time.Sleep(s.config.RetryBackoff)
+	require.NoError(s.T(), scheduler.DispatchDue(s.ctx))
+	assert.Empty(s.T(), s.notifier.Sent())
+}
+
+// failingClaims claims one delivery and then fails, as a store that goes
+// away partway through a batch would.
+type failingClaims struct {
+	domain.ReminderDeliveryRepository
+}
+
+func (fc failingClaims) ClaimReminderDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.ReminderDelivery, error) {
+	claimed, err := fc.ReminderDeliveryRepository.ClaimReminderDeliveries(c, owner, now, leaseUntil, 1)
+	if err != nil {
+		return nil, err
+	}
+	return claimed, errors.New("connection reset")
+}
+
+func (s *ReminderSchedulerSuite) TestDispatchDue_SendsPartialClaims() {
+	s.createTask("a", "user-id", "todo", s.now.Add(-time.Minute))
+	s.createTask("b", "user-id", "todo", s.now.Add(-time.Minute))
+	deliveries := s.deliveries
+	s.deliveries = failingClaims{deliveries}
+
+	assert.Error(s.T(), s.scheduler().DispatchDue(s.ctx))
+	assert.Len(s.T(), s.notifier.Sent(), 1)
+
+	// The other delivery was never claimed and goes out on the next run.
+	s.deliveries = deliveries
+	require.NoError(s.T(), s.scheduler().DispatchDue(s.ctx))
+	assert.ElementsMatch(s.T(), []string{"a", "b"}, s.notifier.Sent())
+}
+
+func (s *ReminderSchedulerSuite) TestRun_StopsOnCancel() {
+	s.createTask("now", "user-id", "todo", s.now.Add(-time.Minute))
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	go func() {
+		s.scheduler().Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(s.T(), func() bool { return len(s.notifier.Sent()) == 1 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.T().Fatal("Run did not return after cancellation")
+	}
+}
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type reminderUsecases struct {
+	reminderRepository domain.ReminderRepository
+	channels           []string
+	transactor         domain.Transactor
+	auditLog           domain.AuditRepository
+	contextTimeout     time.Duration
+}
+
+// NewReminderUsecases accepts reminders on the given channels, the ones the
+// scheduler has notifiers for. New and deleted reminders are recorded in
+// auditLog, in the same transaction of transactor as the change; either
+// may be nil.
+func NewReminderUsecases(reminderRepository domain.ReminderRepository, channels []string, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.ReminderUsecases {
+	return &reminderUsecases{
+		reminderRepository: reminderRepository,
+		channels:           channels,
+		transactor:         transactor,
+		auditLog:           auditLog,
+		contextTimeout:     contextTimeout,
+	}
+}
+
+func (ru *reminderUsecases) ListReminders(ctx context.Context, actor *domain.Actor) ([]*domain.Reminder, error) {
+	ctx, cancel := context.WithTimeout(ctx, ru.contextTimeout)
+	defer cancel()
+
+	if actor == nil || actor.User == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	return ru.reminderRepository.GetRemindersByUser(ctx, actor.User.ID)
+}
+
+func (ru *reminderUsecases) CreateReminder(ctx context.Context, lead time.Duration, channel string, actor *domain.Actor) (*domain.Reminder, error) {
+	ctx, cancel := context.WithTimeout(ctx, ru.contextTimeout)
+	defer cancel()
+
+	if actor == nil || actor.User == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	if lead < 0 || lead > domain.MaxReminderLead || lead%time.Minute != 0 {
+		return nil, fmt.Errorf("%w: the lead must be whole minutes between 0 and %d days", domain.ErrInvalidReminder, domain.MaxReminderLead/(24*time.Hour))
+	}
+	if channel == "" {
+		channel = domain.ReminderChannelEmail
+	}
+	if !slices.Contains(ru.channels, channel) {
+		return nil, fmt.Errorf("%w: unknown channel %q", domain.ErrInvalidReminder, channel)
+	}
+	existing, err := ru.reminderRepository.GetRemindersByUser(ctx, actor.User.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= domain.MaxRemindersPerUser {
+		return nil, domain.ErrTooManyReminders
+	}
+
+	reminder := &domain.Reminder{
+		ID:        uuid.New().String(),
+		UserID:    actor.User.ID,
+		Lead:      lead,
+		Channel:   channel,
+		CreatedAt: time.Now(),
+	}
+	err = inTransaction(ctx, ru.transactor, func(ctx context.Context) error {
+		if err := ru.reminderRepository.CreateReminder(ctx, reminder); err != nil {
+			return err
+		}
+		return recordAudit(ctx, ru.auditLog, &domain.AuditEntry{
+			Action: domain.AuditReminderCreate, TargetType: domain.AuditTargetReminder, TargetID: reminder.ID,
+		}, actor.User, nil, reminder)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
+
+// DeleteReminder removes one of the actor's reminders. Deliveries of it that
+// have not been sent yet are cancelled when they come up.
+func (ru *reminderUsecases) DeleteReminder(ctx context.Context, reminderId string, actor *domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, ru.contextTimeout)
+	defer cancel()
+
+	if actor == nil || actor.User == nil {
+		return domain.ErrUnauthorized
+	}
+	reminder, err := ru.reminderRepository.GetReminderByID(ctx, reminderId)
+	if err != nil {
+		return err
+	}
+	if reminder.UserID != actor.User.ID {
+		return domain.ErrReminderNotFound
+	}
+	return inTransaction(ctx, ru.transactor, func(ctx context.Context) error {
+		if err := ru.reminderRepository.DeleteReminder(ctx, reminderId); err != nil {
+			return err
+		}
+		return recordAudit(ctx, ru.auditLog, &domain.AuditEntry{
+			Action: domain.AuditReminderDelete, TargetType: domain.AuditTargetReminder, TargetID: reminderId,
+		}, actor.User, reminder, nil)
+	})
+}
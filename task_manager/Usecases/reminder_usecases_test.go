@@ -0,0 +1,82 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	taskUsecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReminderUsecaseSuite struct {
+	suite.Suite
+	reminders  domain.ReminderRepository
+	reminderUC domain.ReminderUsecases
+	ctx        context.Context
+}
+
+func (s *ReminderUsecaseSuite) SetupTest() {
+	s.reminders = repository.NewInMemoryReminderRepository()
+	s.reminderUC = taskUsecases.NewReminderUsecases(s.reminders, []string{domain.ReminderChannelEmail, "log"}, nil, nil, 2*time.Second)
+	s.ctx = context.Background()
+}
+
+func TestReminderUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(ReminderUsecaseSuite))
+}
+
+func (s *ReminderUsecaseSuite) TestCreateReminder() {
+	assert := assert.New(s.T())
+	reminder, err := s.reminderUC.CreateReminder(s.ctx, 24*time.Hour, "", owner)
+	require.NoError(s.T(), err)
+	assert.Equal("user-id", reminder.UserID)
+	assert.Equal(domain.ReminderChannelEmail, reminder.Channel)
+	assert.WithinDuration(time.Now(), reminder.CreatedAt, time.Minute)
+
+	_, err = s.reminderUC.CreateReminder(s.ctx, 0, "log", owner)
+	assert.NoError(err)
+	_, err = s.reminderUC.CreateReminder(s.ctx, 24*time.Hour, domain.ReminderChannelEmail, owner)
+	assert.ErrorIs(err, domain.ErrReminderAlreadyExists)
+
+	reminders, err := s.reminderUC.ListReminders(s.ctx, owner)
+	require.NoError(s.T(), err)
+	assert.Len(reminders, 2)
+	reminders, err = s.reminderUC.ListReminders(s.ctx, stranger)
+	require.NoError(s.T(), err)
+	assert.Empty(reminders)
+}
+
+func (s *ReminderUsecaseSuite) TestCreateReminder_Invalid() {
+	for _, lead := range []time.Duration{-time.Minute, 90 * time.Second, domain.MaxReminderLead + time.Minute} {
+		_, err := s.reminderUC.CreateReminder(s.ctx, lead, "", owner)
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidReminder, lead)
+	}
+	_, err := s.reminderUC.CreateReminder(s.ctx, time.Hour, "pager", owner)
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidReminder)
+	_, err = s.reminderUC.CreateReminder(s.ctx, time.Hour, "", nil)
+	assert.ErrorIs(s.T(), err, domain.ErrUnauthorized)
+}
+
+func (s *ReminderUsecaseSuite) TestCreateReminder_Limit() {
+	for i := range domain.MaxRemindersPerUser {
+		_, err := s.reminderUC.CreateReminder(s.ctx, time.Duration(i)*time.Hour, "", owner)
+		require.NoError(s.T(), err)
+	}
+	_, err := s.reminderUC.CreateReminder(s.ctx, 48*time.Hour, "", owner)
+	assert.ErrorIs(s.T(), err, domain.ErrTooManyReminders)
+}
+
+func (s *ReminderUsecaseSuite) TestDeleteReminder() {
+	reminder, err := s.reminderUC.CreateReminder(s.ctx, time.Hour, "", owner)
+	require.NoError(s.T(), err)
+
+	assert.ErrorIs(s.T(), s.reminderUC.DeleteReminder(s.ctx, reminder.ID, stranger), domain.ErrReminderNotFound)
+	assert.NoError(s.T(), s.reminderUC.DeleteReminder(s.ctx, reminder.ID, owner))
+	assert.ErrorIs(s.T(), s.reminderUC.DeleteReminder(s.ctx, reminder.ID, owner), domain.ErrReminderNotFound)
+}
@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+type roleUsecases struct {
+	roleRepository domain.RoleRepository
+	userRepository domain.UserRepository
+	tokenUsecases  domain.TokenUsecases
+	transactor     domain.Transactor
+	auditLog       domain.AuditRepository
+	contextTimeout time.Duration
+
+	// Roles cannot be changed once created, so their permissions are
+	// cached for the life of the process.
+	mu          sync.RWMutex
+	permissions map[string][]string
+}
+
+// NewRoleUsecases records new roles and role assignments in auditLog, in the
+// same transaction of transactor as the change; either may be nil.
+func NewRoleUsecases(roleRepository domain.RoleRepository, userRepository domain.UserRepository, tokens domain.TokenUsecases, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.RoleUsecases {
+	return &roleUsecases{
+		roleRepository: roleRepository,
+		userRepository: userRepository,
+		tokenUsecases:  tokens,
+		transactor:     transactor,
+		auditLog:       auditLog,
+		contextTimeout: contextTimeout,
+		permissions:    make(map[string][]string),
+	}
+}
+
+func (ru *roleUsecases) GetRoles(ctx context.Context) ([]*domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, ru.contextTimeout)
+	defer cancel()
+
+	custom, err := ru.roleRepository.GetAllRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(domain.BuiltInRoles(), custom...), nil
+}
+
+func (ru *roleUsecases) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, ru.contextTimeout)
+	defer cancel()
+
+	role.BuiltIn = false
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+	err := inTransaction(ctx, ru.transactor, func(ctx context.Context) error {
+		if err := ru.roleRepository.CreateRole(ctx, role); err != nil {
+			return err
+		}
+		return recordAudit(ctx, ru.auditLog, &domain.AuditEntry{
+			Action: domain.AuditRoleCreate, TargetType: domain.AuditTargetRole, TargetID: role.Name,
+		}, nil, nil, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (ru *roleUsecases) GetPermissions(ctx context.Context, role string) ([]string, error) {
+	if builtIn := domain.BuiltInRole(role); builtIn != nil {
+		return builtIn.Permissions, nil
+	}
+
+	ru.mu.RLock()
+	perms, ok := ru.permissions[role]
+	ru.mu.RUnlock()
+	if ok {
+		return perms, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ru.contextTimeout)
+	defer cancel()
+
+	stored, err := ru.roleRepository.GetRoleByName(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	perms = slices.Clone(stored.Permissions)
+
+	ru.mu.Lock()
+	ru.permissions[role] = perms
+	ru.mu.Unlock()
+	return perms, nil
+}
+
+func (ru *roleUsecases) AssignRole(ctx context.Context, userId string, role string) error {
+	if _, err := ru.GetPermissions(ctx, role); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ru.contextTimeout)
+	defer cancel()
+
+	user, err := ru.userRepository.GetUserByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+	err = inTransaction(ctx, ru.transactor, func(ctx context.Context) error {
+		if err := ru.userRepository.UpdateUserRole(ctx, userId, role); err != nil {
+			return err
+		}
+		assigned := *user
+		assigned.Role = role
+		assigned.TokenVersion++
+		return recordAudit(ctx, ru.auditLog, &domain.AuditEntry{
+			Action: domain.AuditRoleAssign, TargetType: domain.AuditTargetUser, TargetID: userId,
+		}, nil, user, &assigned)
+	})
+	if err != nil {
+		return err
+	}
+	ru.tokenUsecases.ForgetTokenVersion(userId)
+	return nil
+}
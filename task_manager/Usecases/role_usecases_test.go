@@ -0,0 +1,112 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	roleUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleUsecaseSuite struct {
+	suite.Suite
+	roleRepo *mocks.RoleRepository
+	userRepo *mocks.UserRepository
+	tokens   *mocks.TokenUsecases
+	uc       domain.RoleUsecases
+}
+
+func (s *RoleUsecaseSuite) SetupTest() {
+	s.roleRepo = new(mocks.RoleRepository)
+	s.userRepo = new(mocks.UserRepository)
+	s.tokens = new(mocks.TokenUsecases)
+	s.uc = roleUsecases.NewRoleUsecases(s.roleRepo, s.userRepo, s.tokens, nil, nil, 2*time.Second)
+}
+
+func TestRoleUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(RoleUsecaseSuite))
+}
+
+func (s *RoleUsecaseSuite) TestGetRoles_BuiltInFirst() {
+	assert := assert.New(s.T())
+	s.roleRepo.On("GetAllRoles", mock.Anything).Return([]*domain.Role{{Name: "editor"}}, nil).Once()
+
+	roles, err := s.uc.GetRoles(context.Background())
+	assert.NoError(err)
+	if assert.Len(roles, 3) {
+		assert.Equal(domain.RoleAdmin, roles[0].Name)
+		assert.Equal(domain.RoleUser, roles[1].Name)
+		assert.Equal("editor", roles[2].Name)
+	}
+}
+
+func (s *RoleUsecaseSuite) TestCreateRole_Success() {
+	assert := assert.New(s.T())
+	s.roleRepo.On("CreateRole", mock.Anything, mock.MatchedBy(func(r *domain.Role) bool {
+		return r.Name == "editor" && !r.BuiltIn
+	})).Return(nil).Once()
+
+	role, err := s.uc.CreateRole(context.Background(), &domain.Role{Name: "editor", Permissions: []string{domain.PermTaskUpdate}, BuiltIn: true})
+	assert.NoError(err)
+	assert.False(role.BuiltIn)
+	s.roleRepo.AssertExpectations(s.T())
+}
+
+func (s *RoleUsecaseSuite) TestCreateRole_Invalid() {
+	_, err := s.uc.CreateRole(context.Background(), &domain.Role{Name: "editor", Permissions: []string{"everything"}})
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidRole)
+	s.roleRepo.AssertNotCalled(s.T(), "CreateRole", mock.Anything, mock.Anything)
+}
+
+func (s *RoleUsecaseSuite) TestGetPermissions_BuiltInWithoutLookup() {
+	perms, err := s.uc.GetPermissions(context.Background(), domain.RoleAdmin)
+	assert.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), domain.Permissions, perms)
+	s.roleRepo.AssertNotCalled(s.T(), "GetRoleByName", mock.Anything, mock.Anything)
+}
+
+func (s *RoleUsecaseSuite) TestGetPermissions_CustomRoleCached() {
+	assert := assert.New(s.T())
+	s.roleRepo.On("GetRoleByName", mock.Anything, "editor").
+		Return(&domain.Role{Name: "editor", Permissions: []string{domain.PermTaskUpdate}}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		perms, err := s.uc.GetPermissions(context.Background(), "editor")
+		assert.NoError(err)
+		assert.Equal([]string{domain.PermTaskUpdate}, perms)
+	}
+	s.roleRepo.AssertExpectations(s.T())
+}
+
+func (s *RoleUsecaseSuite) TestAssignRole_Success() {
+	s.roleRepo.On("GetRoleByName", mock.Anything, "editor").Return(&domain.Role{Name: "editor"}, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleUser}, nil).Once()
+	s.userRepo.On("UpdateUserRole", mock.Anything, "u1", "editor").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+
+	assert.NoError(s.T(), s.uc.AssignRole(context.Background(), "u1", "editor"))
+	s.userRepo.AssertExpectations(s.T())
+	s.tokens.AssertExpectations(s.T())
+}
+
+func (s *RoleUsecaseSuite) TestAssignRole_UnknownRole() {
+	s.roleRepo.On("GetRoleByName", mock.Anything, "ghost").Return(nil, domain.ErrRoleNotFound).Once()
+
+	assert.ErrorIs(s.T(), s.uc.AssignRole(context.Background(), "u1", "ghost"), domain.ErrRoleNotFound)
+	s.userRepo.AssertNotCalled(s.T(), "UpdateUserRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *RoleUsecaseSuite) TestAssignRole_LastAdmin() {
+	s.roleRepo.On("GetRoleByName", mock.Anything, "editor").Return(&domain.Role{Name: "editor"}, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleAdmin}, nil).Once()
+	s.userRepo.On("UpdateUserRole", mock.Anything, "u1", "editor").Return(domain.ErrLastAdmin).Once()
+
+	assert.ErrorIs(s.T(), s.uc.AssignRole(context.Background(), "u1", "editor"), domain.ErrLastAdmin)
+	s.tokens.AssertNotCalled(s.T(), "ForgetTokenVersion", mock.Anything)
+}
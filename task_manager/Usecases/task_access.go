@@ -0,0 +1,27 @@
+package usecases
+
+import (
+	domain "task_manager/Domain"
+)
+
+// authorizeTaskOwner decides whether actor may operate on data owned by
+// ownerID. Owners always may. An actor holding anyPermission (one of the
+// ":any" task permissions) may only with an explicit override; without one
+// they get ErrForbidden, since they are allowed to know the task exists.
+// Everyone else gets ErrTaskNotFound so that task IDs belonging to other
+// users cannot be probed.
+func authorizeTaskOwner(actor *domain.Actor, ownerID string, anyPermission string) error {
+	if actor == nil || actor.User == nil || actor.User.ID == "" {
+		return domain.ErrUnauthorized
+	}
+	if actor.User.ID == ownerID {
+		return nil
+	}
+	if actor.Can(anyPermission) {
+		if actor.AdminOverride {
+			return nil
+		}
+		return domain.ErrForbidden
+	}
+	return domain.ErrTaskNotFound
+}
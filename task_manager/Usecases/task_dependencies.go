@@ -0,0 +1,293 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+func (tu *taskUsecases) AddTaskBlocker(ctx context.Context, id string, blockerId string, actor *domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny)
+	if err != nil {
+		return err
+	}
+	if blockerId == id {
+		return &domain.DependencyCycleError{Path: []string{id, id}}
+	}
+	// Only the owner's own tasks can block a task, whoever links them.
+	blocker, err := tu.taskRepository.GetTaskByID(ctx, blockerId)
+	if errors.Is(err, domain.ErrTaskNotFound) || (err == nil && (blocker == nil || blocker.UserID != task.UserID)) {
+		return fmt.Errorf("%w: task %q not found", domain.ErrInvalidDependency, blockerId)
+	}
+	if err != nil {
+		return err
+	}
+
+	graph, err := tu.dependencyRepository.GetDependenciesByUser(ctx, task.UserID)
+	if err != nil {
+		return err
+	}
+	if path := blockingPath(graph, blockerId, id); path != nil {
+		return &domain.DependencyCycleError{Path: append([]string{id}, path...)}
+	}
+	if findDependency(graph, id, blockerId) != nil {
+		return nil
+	}
+
+	dependency := &domain.TaskDependency{
+		TaskID:    id,
+		BlockerID: blockerId,
+		UserID:    task.UserID,
+		CreatedAt: time.Now(),
+	}
+	return inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		err := tu.dependencyRepository.AddDependency(ctx, dependency)
+		if errors.Is(err, domain.ErrDependencyAlreadyExists) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTaskBlockerAdd, TargetType: domain.AuditTargetTask, TargetID: id,
+		}, actor.User, nil, dependency)
+	})
+}
+
+// RemoveTaskBlocker unlinks a blocker from the task. Removing one that does
+// not block the task is not an error.
+func (tu *taskUsecases) RemoveTaskBlocker(ctx context.Context, id string, blockerId string, actor *domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	if _, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny); err != nil {
+		return err
+	}
+	blockers, err := tu.dependencyRepository.GetBlockers(ctx, id)
+	if err != nil {
+		return err
+	}
+	dependency := findDependency(blockers, id, blockerId)
+	if dependency == nil {
+		return nil
+	}
+	return inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		if err := tu.dependencyRepository.RemoveDependency(ctx, id, blockerId); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTaskBlockerRemove, TargetType: domain.AuditTargetTask, TargetID: id,
+		}, actor.User, dependency, nil)
+	})
+}
+
+// findDependency returns the dependency of taskId on blockerId among
+// dependencies, or nil.
+func findDependency(dependencies []*domain.TaskDependency, taskId, blockerId string) *domain.TaskDependency {
+	for _, dependency := range dependencies {
+		if dependency.TaskID == taskId && dependency.BlockerID == blockerId {
+			return dependency
+		}
+	}
+	return nil
+}
+
+func (tu *taskUsecases) GetTaskBlockers(ctx context.Context, id string, actor *domain.Actor) ([]*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	if _, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskReadAny); err != nil {
+		return nil, err
+	}
+	dependencies, err := tu.dependencyRepository.GetBlockers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tu.loadLinkedTasks(ctx, dependencies, func(dependency *domain.TaskDependency) string { return dependency.BlockerID })
+}
+
+func (tu *taskUsecases) GetTaskDependents(ctx context.Context, id string, actor *domain.Actor) ([]*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	if _, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskReadAny); err != nil {
+		return nil, err
+	}
+	dependencies, err := tu.dependencyRepository.GetDependents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tu.loadLinkedTasks(ctx, dependencies, func(dependency *domain.TaskDependency) string { return dependency.TaskID })
+}
+
+// GetReadyTasks orders the open tasks in layers: first those that can be
+// started now, then those whose open blockers are all in the first layer,
+// and so on, each layer by due date. Closed blockers do not hold a task
+// back. Tasks caught in a cycle, which AddTaskBlocker refuses but
+// concurrent requests could still create, never become ready and are left
+// out.
+func (tu *taskUsecases) GetReadyTasks(ctx context.Context, userId string, actor *domain.Actor) ([]*domain.ReadyTask, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	if userId == "" && actor != nil && actor.User != nil {
+		userId = actor.User.ID
+	}
+	if err := authorizeTaskOwner(actor, userId, domain.PermTaskReadAny); err != nil {
+		if err == domain.ErrTaskNotFound {
+			return nil, domain.ErrForbidden
+		}
+		return nil, err
+	}
+
+	var open []*domain.Task
+	query := domain.TaskQuery{UserID: userId, SortBy: domain.TaskSortDueDate, Limit: maxTaskPageSize}
+	for {
+		page, err := tu.taskRepository.GetAllTasks(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range page.Tasks {
+			if tu.workflow.IsOpen(task.Status) {
+				open = append(open, task)
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	graph, err := tu.dependencyRepository.GetDependenciesByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	position := make(map[string]int, len(open))
+	for i, task := range open {
+		position[task.ID] = i
+	}
+	waitingOn := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, dependency := range graph {
+		_, taskOpen := position[dependency.TaskID]
+		_, blockerOpen := position[dependency.BlockerID]
+		if taskOpen && blockerOpen {
+			waitingOn[dependency.TaskID]++
+			dependents[dependency.BlockerID] = append(dependents[dependency.BlockerID], dependency.TaskID)
+		}
+	}
+
+	var layer []*domain.Task
+	for _, task := range open {
+		if waitingOn[task.ID] == 0 {
+			layer = append(layer, task)
+		}
+	}
+	var ready []*domain.ReadyTask
+	for depth := 0; len(layer) > 0; depth++ {
+		var next []*domain.Task
+		for _, task := range layer {
+			ready = append(ready, &domain.ReadyTask{Task: task, Depth: depth})
+			for _, dependent := range dependents[task.ID] {
+				if waitingOn[dependent]--; waitingOn[dependent] == 0 {
+					next = append(next, open[position[dependent]])
+				}
+			}
+		}
+		slices.SortFunc(next, func(a, b *domain.Task) int { return position[a.ID] - position[b.ID] })
+		layer = next
+	}
+
+	tasks := make([]*domain.Task, len(ready))
+	for i, entry := range ready {
+		tasks[i] = entry.Task
+	}
+	if err := tu.annotate(ctx, tasks...); err != nil {
+		return nil, err
+	}
+	return ready, nil
+}
+
+// checkBlockers fails with an *OpenBlockersError if any task blocking task
+// is still open.
+func (tu *taskUsecases) checkBlockers(ctx context.Context, task *domain.Task) error {
+	dependencies, err := tu.dependencyRepository.GetBlockers(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	var open []string
+	for _, dependency := range dependencies {
+		blocker, err := tu.taskRepository.GetTaskByID(ctx, dependency.BlockerID)
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if tu.workflow.IsOpen(blocker.Status) {
+			open = append(open, blocker.ID)
+		}
+	}
+	if len(open) > 0 {
+		return &domain.OpenBlockersError{TaskID: task.ID, Blockers: open}
+	}
+	return nil
+}
+
+// loadLinkedTasks loads the task at the other end of each dependency,
+// skipping any deleted in the meantime.
+func (tu *taskUsecases) loadLinkedTasks(ctx context.Context, dependencies []*domain.TaskDependency, other func(*domain.TaskDependency) string) ([]*domain.Task, error) {
+	tasks := make([]*domain.Task, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		task, err := tu.taskRepository.GetTaskByID(ctx, other(dependency))
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := tu.annotate(ctx, tasks...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// blockingPath finds a chain of dependencies along which from is blocked,
+// directly or through other tasks, by to. It returns the chain from from to
+// to, or nil if there is none.
+func blockingPath(graph []*domain.TaskDependency, from string, to string) []string {
+	blockers := make(map[string][]string)
+	for _, dependency := range graph {
+		blockers[dependency.TaskID] = append(blockers[dependency.TaskID], dependency.BlockerID)
+	}
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			var path []string
+			for id := to; id != ""; id = previous[id] {
+				path = append(path, id)
+			}
+			slices.Reverse(path)
+			return path
+		}
+		for _, blocker := range blockers[current] {
+			if _, seen := previous[blocker]; !seen {
+				previous[blocker] = current
+				queue = append(queue, blocker)
+			}
+		}
+	}
+	return nil
+}
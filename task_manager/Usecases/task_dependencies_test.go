@@ -0,0 +1,185 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	taskUsecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DependencyUsecaseSuite struct {
+	suite.Suite
+	tasks        domain.TaskRepository
+	dependencies domain.DependencyRepository
+	taskUC       domain.TaskUsecases
+	ctx          context.Context
+	day          time.Time
+}
+
+func (s *DependencyUsecaseSuite) SetupTest() {
+	s.tasks = repository.NewInMemoryTaskRepository()
+	s.dependencies = repository.NewInMemoryDependencyRepository()
+	s.taskUC = taskUsecases.NewTaskUsecases(s.tasks, repository.NewInMemoryCommentRepository(), repository.NewInMemoryLabelRepository(),
+		s.dependencies, domain.DefaultWorkflow(), domain.DefaultSubtaskRules(), nil, nil, nil, 2*time.Second)
+	s.ctx = context.Background()
+	s.day = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDependencyUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(DependencyUsecaseSuite))
+}
+
+// create stores a task of user-id due the given number of days after s.day.
+func (s *DependencyUsecaseSuite) create(id string, status string, days int) {
+	if status == "" {
+		status = domain.StatusTodo
+	}
+	task := &domain.Task{ID: id, UserID: "user-id", Title: id, Status: status, DueDate: s.day.AddDate(0, 0, days)}
+	require.NoError(s.T(), s.tasks.CreateTask(s.ctx, task))
+}
+
+func (s *DependencyUsecaseSuite) block(taskId, blockerId string) {
+	require.NoError(s.T(), s.taskUC.AddTaskBlocker(s.ctx, taskId, blockerId, owner))
+}
+
+func taskIDs(tasks []*domain.Task) []string {
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func (s *DependencyUsecaseSuite) TestAddTaskBlocker_RejectsCycles() {
+	assert := assert.New(s.T())
+	s.create("a", "", 0)
+	s.create("b", "", 0)
+	s.create("c", "", 0)
+	s.block("a", "b")
+	s.block("b", "c")
+	// Adding the same dependency again is not an error.
+	s.block("a", "b")
+
+	var cycle *domain.DependencyCycleError
+	err := s.taskUC.AddTaskBlocker(s.ctx, "c", "a", owner)
+	if assert.ErrorAs(err, &cycle) {
+		assert.Equal([]string{"c", "a", "b", "c"}, cycle.Path)
+	}
+	assert.ErrorIs(err, domain.ErrDependencyCycle)
+	err = s.taskUC.AddTaskBlocker(s.ctx, "a", "a", owner)
+	if assert.ErrorAs(err, &cycle) {
+		assert.Equal([]string{"a", "a"}, cycle.Path)
+	}
+
+	blockers, err := s.taskUC.GetTaskBlockers(s.ctx, "a", owner)
+	require.NoError(s.T(), err)
+	assert.Equal([]string{"b"}, taskIDs(blockers))
+}
+
+func (s *DependencyUsecaseSuite) TestAddTaskBlocker_Ownership() {
+	assert := assert.New(s.T())
+	s.create("a", "", 0)
+	theirs := &domain.Task{ID: "theirs", UserID: "other-id", Title: "theirs", Status: "todo"}
+	require.NoError(s.T(), s.tasks.CreateTask(s.ctx, theirs))
+
+	assert.ErrorIs(s.taskUC.AddTaskBlocker(s.ctx, "a", "theirs", owner), domain.ErrInvalidDependency)
+	assert.ErrorIs(s.taskUC.AddTaskBlocker(s.ctx, "a", "missing", owner), domain.ErrInvalidDependency)
+	assert.ErrorIs(s.taskUC.AddTaskBlocker(s.ctx, "theirs", "a", owner), domain.ErrTaskNotFound)
+	_, err := s.taskUC.GetTaskDependents(s.ctx, "a", stranger)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+}
+
+func (s *DependencyUsecaseSuite) TestCannotStartWhileBlocked() {
+	assert := assert.New(s.T())
+	s.create("a", "", 0)
+	s.create("b", "in_progress", 0)
+	s.create("c", "", 0)
+	s.block("a", "b")
+	s.block("a", "c")
+
+	_, err := s.taskUC.UpdateTask(s.ctx, "a", &domain.Task{Title: "a", Status: "in_progress"}, owner)
+	var blocked *domain.OpenBlockersError
+	if assert.ErrorAs(err, &blocked) {
+		assert.Equal([]string{"b", "c"}, blocked.Blockers)
+	}
+	assert.True(errors.Is(err, domain.ErrOpenBlockers))
+	// Other moves are still allowed.
+	_, err = s.taskUC.UpdateTask(s.ctx, "a", &domain.Task{Title: "a", Status: "blocked"}, owner)
+	assert.NoError(err)
+
+	_, err = s.taskUC.UpdateTask(s.ctx, "b", &domain.Task{Title: "b", Status: "done"}, owner)
+	require.NoError(s.T(), err)
+	_, err = s.taskUC.UpdateTask(s.ctx, "c", &domain.Task{Title: "c", Status: "cancelled"}, owner)
+	require.NoError(s.T(), err)
+	_, err = s.taskUC.UpdateTask(s.ctx, "a", &domain.Task{Title: "a", Status: "in_progress"}, owner)
+	assert.NoError(err)
+}
+
+func (s *DependencyUsecaseSuite) TestBlockersAndDependents() {
+	assert := assert.New(s.T())
+	s.create("a", "", 0)
+	s.create("b", "", 0)
+	s.create("c", "", 0)
+	s.block("b", "a")
+	s.block("c", "a")
+
+	dependents, err := s.taskUC.GetTaskDependents(s.ctx, "a", owner)
+	require.NoError(s.T(), err)
+	assert.Equal([]string{"b", "c"}, taskIDs(dependents))
+
+	require.NoError(s.T(), s.taskUC.RemoveTaskBlocker(s.ctx, "b", "a", owner))
+	require.NoError(s.T(), s.taskUC.RemoveTaskBlocker(s.ctx, "b", "a", owner))
+	dependents, err = s.taskUC.GetTaskDependents(s.ctx, "a", owner)
+	require.NoError(s.T(), err)
+	assert.Equal([]string{"c"}, taskIDs(dependents))
+
+	require.NoError(s.T(), s.taskUC.DeleteTask(s.ctx, "a", owner))
+	blockers, err := s.taskUC.GetTaskBlockers(s.ctx, "c", owner)
+	require.NoError(s.T(), err)
+	assert.Empty(blockers)
+}
+
+func (s *DependencyUsecaseSuite) TestGetReadyTasks() {
+	assert := assert.New(s.T())
+	s.create("design", "", 3)
+	s.create("build", "", 1)
+	s.create("test", "", 0)
+	s.create("chores", "", 2)
+	s.create("shipped", "done", 0)
+	s.create("docs", "", 0)
+	s.create("dropped", "cancelled", 0)
+	s.block("build", "design")
+	s.block("test", "build")
+	s.block("test", "design")
+	// Closed blockers do not hold a task back.
+	s.block("docs", "shipped")
+	s.block("chores", "dropped")
+
+	ready, err := s.taskUC.GetReadyTasks(s.ctx, "", owner)
+	require.NoError(s.T(), err)
+	var order []string
+	depths := map[string]int{}
+	for _, entry := range ready {
+		order = append(order, entry.ID)
+		depths[entry.ID] = entry.Depth
+	}
+	assert.Equal([]string{"docs", "chores", "design", "build", "test"}, order)
+	assert.Equal(map[string]int{"docs": 0, "chores": 0, "design": 0, "build": 1, "test": 2}, depths)
+
+	// A cycle that slipped in is left out rather than looping.
+	require.NoError(s.T(), s.dependencies.AddDependency(s.ctx, &domain.TaskDependency{TaskID: "design", BlockerID: "test", UserID: "user-id"}))
+	ready, err = s.taskUC.GetReadyTasks(s.ctx, "", owner)
+	require.NoError(s.T(), err)
+	assert.Len(ready, 2)
+
+	_, err = s.taskUC.GetReadyTasks(s.ctx, "user-id", stranger)
+	assert.ErrorIs(err, domain.ErrForbidden)
+}
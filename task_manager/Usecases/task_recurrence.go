@@ -0,0 +1,248 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+const defaultOccurrencePreview = 10
+
+// UpdateTaskSeries updates an occurrence of a recurring task. The this scope
+// is a plain UpdateTask. With following or all, the title and description
+// are copied to the other occurrences in scope, and a rule or time zone in
+// the payload's Recurrence replaces the series'; status and due date only
+// ever change on the occurrence itself. Following splits the occurrence
+// and the ones after it off into a new series, ending the old one before it.
+// The occurrence's own update is checked before anything is stored, and
+// the series and the occurrence change in one transaction.
+func (tu *taskUsecases) UpdateTaskSeries(ctx context.Context, id string, task *domain.Task, scope string, actor *domain.Actor) (*domain.Task, error) {
+	switch scope {
+	case "", domain.SeriesScopeThis:
+		return tu.UpdateTask(ctx, id, task, actor)
+	case domain.SeriesScopeFollowing, domain.SeriesScopeAll:
+	default:
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidSeriesScope, scope)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	existing, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Recurrence == nil {
+		return nil, domain.ErrNotRecurring
+	}
+	current := *existing.Recurrence
+	changed := current
+	if task.Recurrence != nil {
+		changed.Rule = task.Recurrence.Rule
+		changed.TimeZone = task.Recurrence.TimeZone
+		if err := canonicalizeRecurrence(&changed); err != nil {
+			return nil, err
+		}
+	}
+	var earlier *domain.Recurrence
+	if scope == domain.SeriesScopeFollowing {
+		rule, loc, err := current.Parse()
+		if err != nil {
+			return nil, err
+		}
+		before, rest := rule.Split(current.Start.In(loc), current.Occurrence)
+		earlier = &current
+		earlier.Rule = before.String()
+		if task.Recurrence == nil {
+			changed.Rule = rest.String()
+		}
+		changed.SeriesID = uuid.New().String()
+		changed.Start = current.Occurrence
+	}
+	now := time.Now()
+	if err := tu.prepareUpdate(ctx, existing, task, now); err != nil {
+		return nil, err
+	}
+
+	var updated *domain.Task
+	err = inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		series, err := tu.taskRepository.GetTasksBySeries(ctx, current.SeriesID)
+		if err != nil {
+			return err
+		}
+		for _, occurrence := range series {
+			before := *occurrence
+			recurrence := changed
+			if earlier != nil && occurrence.Recurrence.Occurrence.Before(current.Occurrence) {
+				recurrence = *earlier
+			}
+			recurrence.Occurrence = occurrence.Recurrence.Occurrence
+			changedOccurrence := false
+			if recurrence != *occurrence.Recurrence {
+				if err := tu.taskRepository.SetTaskRecurrence(ctx, occurrence.ID, &recurrence); err != nil {
+					return err
+				}
+				occurrence.Recurrence = &recurrence
+				changedOccurrence = true
+			}
+			if occurrence.ID == id {
+				// applyUpdate stores and publishes the occurrence itself.
+				task.Recurrence = occurrence.Recurrence
+				continue
+			}
+			if recurrence.SeriesID == changed.SeriesID && (occurrence.Title != task.Title || occurrence.Description != task.Description) {
+				occurrence.Title = task.Title
+				occurrence.Description = task.Description
+				if _, err := tu.taskRepository.UpdateTask(ctx, occurrence.ID, occurrence); err != nil {
+					return err
+				}
+				changedOccurrence = true
+			}
+			if changedOccurrence {
+				if err := tu.publishTaskUpdate(ctx, occurrence, false); err != nil {
+					return err
+				}
+				err := recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+					Action: domain.AuditTaskUpdate, TargetType: domain.AuditTargetTask, TargetID: occurrence.ID,
+				}, actor.User, &before, occurrence)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		updated, err = tu.applyUpdate(ctx, existing, task, now, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// PreviewOccurrences lists the next occurrences of a recurring task's
+// series after the task itself, up to limit, in the series' time zone.
+func (tu *taskUsecases) PreviewOccurrences(ctx context.Context, id string, limit int, actor *domain.Actor) ([]time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskReadAny)
+	if err != nil {
+		return nil, err
+	}
+	if task.Recurrence == nil {
+		return nil, domain.ErrNotRecurring
+	}
+	if limit <= 0 {
+		limit = defaultOccurrencePreview
+	}
+	return task.Recurrence.Upcoming(min(limit, domain.MaxOccurrencePreview))
+}
+
+// newRecurrence validates the recurrence a task is created with and starts
+// a new series at the task's due date.
+func newRecurrence(task *domain.Task) (*domain.Recurrence, error) {
+	if task.Recurrence == nil {
+		return nil, nil
+	}
+	if task.DueDate.IsZero() {
+		return nil, fmt.Errorf("%w: a recurring task needs a due date", domain.ErrInvalidRecurrence)
+	}
+	recurrence := &domain.Recurrence{
+		Rule:       task.Recurrence.Rule,
+		TimeZone:   task.Recurrence.TimeZone,
+		SeriesID:   uuid.New().String(),
+		Start:      task.DueDate,
+		Occurrence: task.DueDate,
+	}
+	if err := canonicalizeRecurrence(recurrence); err != nil {
+		return nil, err
+	}
+	return recurrence, nil
+}
+
+// canonicalizeRecurrence checks a recurrence's rule and time zone and
+// stores the rule in its canonical form.
+func canonicalizeRecurrence(recurrence *domain.Recurrence) error {
+	if recurrence.TimeZone == "" {
+		recurrence.TimeZone = "UTC"
+	}
+	rule, _, err := recurrence.Parse()
+	if err != nil {
+		return err
+	}
+	recurrence.Rule = rule.String()
+	return nil
+}
+
+// scheduleNextOccurrence creates the occurrence that follows a recurring
+// task once the task is closed, carrying over its title, description,
+// parent, labels and an unchecked copy of its checklist. Nothing is created
+// when the series has ended or already has a later occurrence, so closing
+// a reopened task again does not create a second one. The new task's ID is
+// derived from its slot in the series, so two instances closing the same
+// task at once cannot both create it. The new task is recorded in the
+// audit log as created by actor.
+func (tu *taskUsecases) scheduleNextOccurrence(ctx context.Context, task *domain.Task, now time.Time, actor *domain.User) error {
+	if task.Recurrence == nil {
+		return nil
+	}
+	next, err := task.Recurrence.Upcoming(1)
+	if err != nil {
+		return err
+	}
+	if len(next) == 0 {
+		return nil
+	}
+	series, err := tu.taskRepository.GetTasksBySeries(ctx, task.Recurrence.SeriesID)
+	if err != nil {
+		return err
+	}
+	for _, occurrence := range series {
+		if !occurrence.Recurrence.Occurrence.Before(next[0]) {
+			return nil
+		}
+	}
+
+	recurrence := *task.Recurrence
+	recurrence.Occurrence = next[0]
+	slot := recurrence.SeriesID + "/" + next[0].UTC().Format(time.RFC3339)
+	occurrence := &domain.Task{
+		ID:          uuid.NewSHA1(uuid.NameSpaceURL, []byte(slot)).String(),
+		UserID:      task.UserID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     next[0],
+		ParentID:    task.ParentID,
+		Recurrence:  &recurrence,
+	}
+	for _, item := range task.Checklist {
+		occurrence.Checklist = append(occurrence.Checklist, domain.ChecklistItem{ID: uuid.New().String(), Text: item.Text})
+	}
+	if err := tu.workflow.Enter(occurrence, "", now); err != nil {
+		return err
+	}
+	if err := tu.taskRepository.CreateTask(ctx, occurrence); err != nil {
+		if errors.Is(err, domain.ErrTaskAlreadyExists) {
+			return nil
+		}
+		return err
+	}
+	for _, labelId := range task.LabelIDs {
+		if err := tu.taskRepository.AddTaskLabel(ctx, occurrence.ID, labelId); err != nil {
+			return err
+		}
+	}
+	occurrence.LabelIDs = slices.Clone(task.LabelIDs)
+	if err := publishEvent(ctx, tu.events, domain.EventTaskCreated, occurrence, nil); err != nil {
+		return err
+	}
+	return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+		Action: domain.AuditTaskCreate, TargetType: domain.AuditTargetTask, TargetID: occurrence.ID,
+	}, actor, nil, occurrence)
+}
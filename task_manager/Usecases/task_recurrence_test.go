@@ -0,0 +1,241 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	taskUsecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecurrenceUsecaseSuite struct {
+	suite.Suite
+	tasks  domain.TaskRepository
+	taskUC domain.TaskUsecases
+	ctx    context.Context
+	monday time.Time
+}
+
+func (s *RecurrenceUsecaseSuite) SetupTest() {
+	s.tasks = repository.NewInMemoryTaskRepository()
+	s.taskUC = taskUsecases.NewTaskUsecases(s.tasks, repository.NewInMemoryCommentRepository(), repository.NewInMemoryLabelRepository(),
+		repository.NewInMemoryDependencyRepository(), domain.DefaultWorkflow(), domain.DefaultSubtaskRules(), nil, nil, nil, 2*time.Second)
+	s.ctx = context.Background()
+	// 09:00 in Berlin.
+	s.monday = time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrenceUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(RecurrenceUsecaseSuite))
+}
+
+// createWeekly creates a weekly report of user-id starting s.monday.
+func (s *RecurrenceUsecaseSuite) createWeekly(rule string) *domain.Task {
+	task := &domain.Task{
+		Title:      "weekly report",
+		DueDate:    s.monday,
+		Checklist:  []domain.ChecklistItem{{Text: "gather numbers", Done: true}},
+		Recurrence: &domain.Recurrence{Rule: rule, TimeZone: "Europe/Berlin"},
+	}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, task, "user-id"))
+	return task
+}
+
+// series lists the occurrences of a series by slot.
+func (s *RecurrenceUsecaseSuite) series(seriesId string) []*domain.Task {
+	series, err := s.tasks.GetTasksBySeries(s.ctx, seriesId)
+	require.NoError(s.T(), err)
+	return series
+}
+
+// setStatus moves a task through the given statuses in turn.
+func (s *RecurrenceUsecaseSuite) setStatus(id string, statuses ...string) {
+	for _, status := range statuses {
+		_, err := s.taskUC.UpdateTask(s.ctx, id, &domain.Task{Title: "weekly report", DueDate: s.monday, Status: status}, owner)
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *RecurrenceUsecaseSuite) TestCreateTask_StartsSeries() {
+	assert := assert.New(s.T())
+	task := s.createWeekly("rrule:freq=weekly;byday=mo")
+
+	require.NotNil(s.T(), task.Recurrence)
+	assert.Equal("FREQ=WEEKLY;BYDAY=MO", task.Recurrence.Rule)
+	assert.NotEmpty(task.Recurrence.SeriesID)
+	assert.True(task.Recurrence.Start.Equal(s.monday))
+	assert.True(task.Recurrence.Occurrence.Equal(s.monday))
+
+	err := s.taskUC.CreateTask(s.ctx, &domain.Task{DueDate: s.monday, Recurrence: &domain.Recurrence{Rule: "FREQ=HOURLY"}}, "user-id")
+	assert.ErrorIs(err, domain.ErrInvalidRecurrence)
+	err = s.taskUC.CreateTask(s.ctx, &domain.Task{Recurrence: &domain.Recurrence{Rule: "FREQ=DAILY"}}, "user-id")
+	assert.ErrorIs(err, domain.ErrInvalidRecurrence)
+	err = s.taskUC.CreateTask(s.ctx, &domain.Task{DueDate: s.monday, Recurrence: &domain.Recurrence{Rule: "FREQ=DAILY", TimeZone: "Nowhere/Special"}}, "user-id")
+	assert.ErrorIs(err, domain.ErrInvalidRecurrence)
+}
+
+func (s *RecurrenceUsecaseSuite) TestClosingSchedulesNextOccurrence() {
+	assert := assert.New(s.T())
+	task := s.createWeekly("FREQ=WEEKLY;COUNT=3")
+	require.NoError(s.T(), s.tasks.AddTaskLabel(s.ctx, task.ID, "label-1"))
+
+	s.setStatus(task.ID, domain.StatusInProgress, domain.StatusDone)
+	series := s.series(task.Recurrence.SeriesID)
+	require.Len(s.T(), series, 2)
+	next := series[1]
+	assert.True(next.DueDate.Equal(s.monday.AddDate(0, 0, 7)))
+	assert.Equal(domain.StatusTodo, next.Status)
+	assert.Equal("weekly report", next.Title)
+	assert.Equal([]string{"label-1"}, next.LabelIDs)
+	require.Len(s.T(), next.Checklist, 1)
+	assert.False(next.Checklist[0].Done)
+
+	// Reopening and closing again does not schedule a second one.
+	s.setStatus(task.ID, domain.StatusInProgress, domain.StatusDone)
+	assert.Len(s.series(task.Recurrence.SeriesID), 2)
+
+	// Cancelling skips to the next one too, until the series runs out.
+	s.setStatus(next.ID, domain.StatusCancelled)
+	series = s.series(task.Recurrence.SeriesID)
+	require.Len(s.T(), series, 3)
+	s.setStatus(series[2].ID, domain.StatusInProgress, domain.StatusDone)
+	assert.Len(s.series(task.Recurrence.SeriesID), 3)
+}
+
+func (s *RecurrenceUsecaseSuite) TestMovedOccurrenceKeepsToTheRule() {
+	task := s.createWeekly("FREQ=WEEKLY")
+	s.setStatus(task.ID, domain.StatusInProgress)
+	_, err := s.taskUC.UpdateTask(s.ctx, task.ID, &domain.Task{Title: "weekly report", DueDate: s.monday.AddDate(0, 0, 3), Status: domain.StatusDone}, owner)
+	require.NoError(s.T(), err)
+
+	series := s.series(task.Recurrence.SeriesID)
+	require.Len(s.T(), series, 2)
+	assert.True(s.T(), series[1].DueDate.Equal(s.monday.AddDate(0, 0, 7)))
+}
+
+func (s *RecurrenceUsecaseSuite) TestUpdateTaskSeries_All() {
+	assert := assert.New(s.T())
+	task := s.createWeekly("FREQ=WEEKLY")
+	s.setStatus(task.ID, domain.StatusInProgress, domain.StatusDone)
+	next := s.series(task.Recurrence.SeriesID)[1]
+
+	update := &domain.Task{Title: "team report", Description: "for the whole team", DueDate: next.DueDate,
+		Recurrence: &domain.Recurrence{Rule: "FREQ=WEEKLY;INTERVAL=2", TimeZone: "Europe/Berlin"}}
+	updated, err := s.taskUC.UpdateTaskSeries(s.ctx, next.ID, update, domain.SeriesScopeAll, owner)
+	require.NoError(s.T(), err)
+	assert.Equal("team report", updated.Title)
+
+	for _, occurrence := range s.series(task.Recurrence.SeriesID) {
+		assert.Equal("team report", occurrence.Title)
+		assert.Equal("for the whole team", occurrence.Description)
+		assert.Equal("FREQ=WEEKLY;INTERVAL=2", occurrence.Recurrence.Rule)
+		assert.True(occurrence.Recurrence.Start.Equal(s.monday))
+	}
+	// The series now skips a week after the first occurrence.
+	upcoming, err := s.taskUC.PreviewOccurrences(s.ctx, task.ID, 1, owner)
+	require.NoError(s.T(), err)
+	assert.Equal([]string{"2025-06-16"}, dates(upcoming))
+}
+
+func (s *RecurrenceUsecaseSuite) TestUpdateTaskSeries_Following() {
+	assert := assert.New(s.T())
+	task := s.createWeekly("FREQ=WEEKLY;COUNT=4")
+	s.setStatus(task.ID, domain.StatusInProgress, domain.StatusDone)
+	second := s.series(task.Recurrence.SeriesID)[1]
+
+	_, err := s.taskUC.UpdateTaskSeries(s.ctx, second.ID, &domain.Task{Title: "short report", DueDate: second.DueDate}, domain.SeriesScopeFollowing, owner)
+	require.NoError(s.T(), err)
+
+	old := s.series(task.Recurrence.SeriesID)
+	require.Len(s.T(), old, 1)
+	assert.Equal("weekly report", old[0].Title)
+	assert.Equal("FREQ=WEEKLY;UNTIL=20250609T065959Z", old[0].Recurrence.Rule)
+
+	moved, err := s.tasks.GetTaskByID(s.ctx, second.ID)
+	require.NoError(s.T(), err)
+	assert.Equal("short report", moved.Title)
+	assert.NotEqual(task.Recurrence.SeriesID, moved.Recurrence.SeriesID)
+	assert.Equal("FREQ=WEEKLY;COUNT=3", moved.Recurrence.Rule)
+	assert.True(moved.Recurrence.Start.Equal(second.Recurrence.Occurrence))
+
+	// Closing the earlier occurrence again cannot revive the old series.
+	s.setStatus(task.ID, domain.StatusInProgress, domain.StatusDone)
+	assert.Len(s.series(task.Recurrence.SeriesID), 1)
+	upcoming, err := s.taskUC.PreviewOccurrences(s.ctx, second.ID, 10, owner)
+	require.NoError(s.T(), err)
+	assert.Equal([]string{"2025-06-16", "2025-06-23"}, dates(upcoming))
+}
+
+func (s *RecurrenceUsecaseSuite) TestUpdateTaskSeries_Errors() {
+	assert := assert.New(s.T())
+	task := s.createWeekly("FREQ=WEEKLY")
+	oneOff := &domain.Task{Title: "once", DueDate: s.monday}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, oneOff, "user-id"))
+
+	_, err := s.taskUC.UpdateTaskSeries(s.ctx, task.ID, &domain.Task{Title: "x"}, "some", owner)
+	assert.ErrorIs(err, domain.ErrInvalidSeriesScope)
+	_, err = s.taskUC.UpdateTaskSeries(s.ctx, oneOff.ID, &domain.Task{Title: "x"}, domain.SeriesScopeAll, owner)
+	assert.ErrorIs(err, domain.ErrNotRecurring)
+	_, err = s.taskUC.UpdateTaskSeries(s.ctx, task.ID, &domain.Task{Title: "x", Recurrence: &domain.Recurrence{Rule: "FREQ=SOMETIMES"}}, domain.SeriesScopeAll, owner)
+	assert.ErrorIs(err, domain.ErrInvalidRecurrence)
+	_, err = s.taskUC.UpdateTaskSeries(s.ctx, task.ID, &domain.Task{Title: "x"}, domain.SeriesScopeAll, stranger)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+
+	// A this-scoped update is a plain update and leaves the series alone.
+	_, err = s.taskUC.UpdateTaskSeries(s.ctx, task.ID, &domain.Task{Title: "x", Recurrence: &domain.Recurrence{Rule: "FREQ=DAILY"}}, domain.SeriesScopeThis, owner)
+	require.NoError(s.T(), err)
+	stored, err := s.tasks.GetTaskByID(s.ctx, task.ID)
+	require.NoError(s.T(), err)
+	assert.Equal("FREQ=WEEKLY", stored.Recurrence.Rule)
+}
+
+func (s *RecurrenceUsecaseSuite) TestUpdateTaskSeries_RefusedUpdateLeavesSeries() {
+	task := s.createWeekly("FREQ=WEEKLY")
+	s.setStatus(task.ID, domain.StatusInProgress, domain.StatusDone)
+	next := s.series(task.Recurrence.SeriesID)[1]
+
+	// todo cannot go straight to done.
+	update := &domain.Task{Title: "team report", DueDate: next.DueDate, Status: domain.StatusDone,
+		Recurrence: &domain.Recurrence{Rule: "FREQ=DAILY", TimeZone: "Europe/Berlin"}}
+	_, err := s.taskUC.UpdateTaskSeries(s.ctx, next.ID, update, domain.SeriesScopeAll, owner)
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTransition)
+
+	for _, occurrence := range s.series(task.Recurrence.SeriesID) {
+		assert.Equal(s.T(), "weekly report", occurrence.Title)
+		assert.Equal(s.T(), "FREQ=WEEKLY", occurrence.Recurrence.Rule)
+	}
+}
+
+func (s *RecurrenceUsecaseSuite) TestPreviewOccurrences() {
+	assert := assert.New(s.T())
+	task := s.createWeekly("FREQ=WEEKLY;BYDAY=MO,TH")
+
+	upcoming, err := s.taskUC.PreviewOccurrences(s.ctx, task.ID, 3, owner)
+	require.NoError(s.T(), err)
+	assert.Equal([]string{"2025-06-05", "2025-06-09", "2025-06-12"}, dates(upcoming))
+	assert.Equal(9, upcoming[0].Hour())
+	upcoming, err = s.taskUC.PreviewOccurrences(s.ctx, task.ID, 0, owner)
+	require.NoError(s.T(), err)
+	assert.Len(upcoming, 10)
+
+	_, err = s.taskUC.PreviewOccurrences(s.ctx, task.ID, 3, stranger)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+	oneOff := &domain.Task{Title: "once", DueDate: s.monday}
+	require.NoError(s.T(), s.taskUC.CreateTask(s.ctx, oneOff, "user-id"))
+	_, err = s.taskUC.PreviewOccurrences(s.ctx, oneOff.ID, 3, owner)
+	assert.ErrorIs(err, domain.ErrNotRecurring)
+}
+
+func dates(times []time.Time) []string {
+	var days []string
+	for _, at := range times {
+		days = append(days, at.Format("2006-01-02"))
+	}
+	return days
+}
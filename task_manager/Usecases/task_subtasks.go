@@ -0,0 +1,275 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+// GetSubtasks lists a task's direct subtasks, with their own progress.
+func (tu *taskUsecases) GetSubtasks(ctx context.Context, id string, actor *domain.Actor) ([]*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	if _, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskReadAny); err != nil {
+		return nil, err
+	}
+	subtasks, err := tu.taskRepository.GetSubtasks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.annotate(ctx, subtasks...); err != nil {
+		return nil, err
+	}
+	return subtasks, nil
+}
+
+func (tu *taskUsecases) AddChecklistItem(ctx context.Context, id string, text string, actor *domain.Actor) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	text, err = validateChecklistText(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(task.Checklist) >= domain.MaxChecklistItems {
+		return nil, fmt.Errorf("%w: a task can have at most %d checklist items", domain.ErrInvalidChecklistItem, domain.MaxChecklistItems)
+	}
+
+	checklist := append(slices.Clone(task.Checklist), domain.ChecklistItem{ID: uuid.New().String(), Text: text})
+	return tu.changeInPlace(ctx, task, actor, func(ctx context.Context) error {
+		return tu.taskRepository.SetTaskChecklist(ctx, id, checklist)
+	})
+}
+
+func (tu *taskUsecases) UpdateChecklistItem(ctx context.Context, id string, itemId string, text *string, done *bool, actor *domain.Actor) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(task.Checklist, func(item domain.ChecklistItem) bool { return item.ID == itemId })
+	if i < 0 {
+		return nil, domain.ErrChecklistItemNotFound
+	}
+
+	checklist := slices.Clone(task.Checklist)
+	if text != nil {
+		if checklist[i].Text, err = validateChecklistText(*text); err != nil {
+			return nil, err
+		}
+	}
+	if done != nil {
+		checklist[i].Done = *done
+	}
+	return tu.changeInPlace(ctx, task, actor, func(ctx context.Context) error {
+		return tu.taskRepository.SetTaskChecklist(ctx, id, checklist)
+	})
+}
+
+func (tu *taskUsecases) RemoveChecklistItem(ctx context.Context, id string, itemId string, actor *domain.Actor) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(task.Checklist, func(item domain.ChecklistItem) bool { return item.ID == itemId })
+	if i < 0 {
+		return nil, domain.ErrChecklistItemNotFound
+	}
+
+	checklist := slices.Delete(slices.Clone(task.Checklist), i, i+1)
+	return tu.changeInPlace(ctx, task, actor, func(ctx context.Context) error {
+		return tu.taskRepository.SetTaskChecklist(ctx, id, checklist)
+	})
+}
+
+// checkParent makes sure a new subtask of parentId belongs to the parent's
+// owner and does not nest deeper than MaxSubtaskDepth. A parent that does
+// not exist and one owned by someone else are reported the same way.
+func (tu *taskUsecases) checkParent(ctx context.Context, parentId string, userId string) error {
+	depth := 1
+	for id := parentId; id != ""; depth++ {
+		if depth >= domain.MaxSubtaskDepth {
+			return fmt.Errorf("%w: subtasks can nest at most %d deep", domain.ErrInvalidParentTask, domain.MaxSubtaskDepth)
+		}
+		parent, err := tu.taskRepository.GetTaskByID(ctx, id)
+		if errors.Is(err, domain.ErrTaskNotFound) || (err == nil && (parent == nil || parent.UserID != userId)) {
+			return fmt.Errorf("%w: task %q not found", domain.ErrInvalidParentTask, parentId)
+		}
+		if err != nil {
+			return err
+		}
+		id = parent.ParentID
+	}
+	return nil
+}
+
+// completeSubtasks applies the OnComplete rule to the subtasks of parent,
+// which is about to be stored as completed by actor.
+func (tu *taskUsecases) completeSubtasks(ctx context.Context, parent *domain.Task, now time.Time, actor *domain.User) error {
+	if tu.subtaskRules.OnComplete == domain.SubtasksIgnore {
+		return nil
+	}
+	subtasks, err := tu.taskRepository.GetSubtasks(ctx, parent.ID)
+	if err != nil {
+		return err
+	}
+	for _, subtask := range subtasks {
+		if !tu.workflow.IsOpen(subtask.Status) {
+			continue
+		}
+		if tu.subtaskRules.OnComplete == domain.SubtasksRestrict {
+			return domain.ErrOpenSubtasks
+		}
+		before := *subtask
+		if err := tu.workflow.Force(subtask, parent.Status, now); err != nil {
+			return err
+		}
+		if err := tu.completeSubtasks(ctx, subtask, now, actor); err != nil {
+			return err
+		}
+		updated, err := tu.taskRepository.UpdateTask(ctx, subtask.ID, subtask)
+		if err != nil {
+			return err
+		}
+		if err := tu.publishTaskUpdate(ctx, updated, updated.CompletedAt != nil); err != nil {
+			return err
+		}
+		err = recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTaskUpdate, TargetType: domain.AuditTargetTask, TargetID: subtask.ID,
+		}, actor, &before, updated)
+		if err != nil {
+			return err
+		}
+		if err := tu.scheduleNextOccurrence(ctx, subtask, now, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSubtasks applies the OnDelete rule to the subtasks of a task that
+// is about to be deleted by actor.
+func (tu *taskUsecases) deleteSubtasks(ctx context.Context, id string, actor *domain.User) error {
+	if tu.subtaskRules.OnDelete == domain.SubtasksDetach {
+		return tu.taskRepository.DetachSubtasks(ctx, id)
+	}
+	subtasks, err := tu.taskRepository.GetSubtasks(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(subtasks) > 0 && tu.subtaskRules.OnDelete == domain.SubtasksRestrict {
+		return domain.ErrTaskHasSubtasks
+	}
+	for _, subtask := range subtasks {
+		if err := tu.deleteSubtasks(ctx, subtask.ID, actor); err != nil {
+			return err
+		}
+		if err := tu.taskRepository.DeleteTask(ctx, subtask.ID); err == nil {
+			if err := publishEvent(ctx, tu.events, domain.EventTaskDeleted, subtask, nil); err != nil {
+				return err
+			}
+			err := recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+				Action: domain.AuditTaskDelete, TargetType: domain.AuditTargetTask, TargetID: subtask.ID,
+			}, actor, subtask, nil)
+			if err != nil {
+				return err
+			}
+		} else if !errors.Is(err, domain.ErrTaskNotFound) {
+			return err
+		}
+		if err := tu.dependencyRepository.DeleteDependenciesByTask(ctx, subtask.ID); err != nil {
+			return err
+		}
+		if err := tu.commentRepository.DeleteCommentsByTask(ctx, subtask.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollUpProgress fills in the Progress of each task, counting subtasks for
+// the whole batch with one query.
+func (tu *taskUsecases) rollUpProgress(ctx context.Context, tasks ...*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	counts, err := tu.taskRepository.CountSubtasksByStatus(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		var progress domain.TaskProgress
+		for status, n := range counts[task.ID] {
+			if tu.workflow.IsComplete(status) {
+				progress.Subtasks += n
+				progress.CompletedSubtasks += n
+			} else if tu.workflow.IsOpen(status) {
+				progress.Subtasks += n
+			}
+		}
+		progress.ChecklistItems = len(task.Checklist)
+		for _, item := range task.Checklist {
+			if item.Done {
+				progress.CheckedItems++
+			}
+		}
+		switch total := progress.Subtasks + progress.ChecklistItems; {
+		case total > 0:
+			progress.Percent = (progress.CompletedSubtasks + progress.CheckedItems) * 100 / total
+		case task.CompletedAt != nil:
+			progress.Percent = 100
+		}
+		task.Progress = progress
+	}
+	return nil
+}
+
+// newChecklist validates the checklist a task is created with and gives
+// its items fresh IDs.
+func newChecklist(items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+	if len(items) > domain.MaxChecklistItems {
+		return nil, fmt.Errorf("%w: a task can have at most %d checklist items", domain.ErrInvalidChecklistItem, domain.MaxChecklistItems)
+	}
+	checklist := make([]domain.ChecklistItem, 0, len(items))
+	for _, item := range items {
+		text, err := validateChecklistText(item.Text)
+		if err != nil {
+			return nil, err
+		}
+		checklist = append(checklist, domain.ChecklistItem{ID: uuid.New().String(), Text: text, Done: item.Done})
+	}
+	return checklist, nil
+}
+
+func validateChecklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("%w: text is required", domain.ErrInvalidChecklistItem)
+	}
+	if utf8.RuneCountInString(text) > domain.MaxChecklistItemLength {
+		return "", fmt.Errorf("%w: text must be at most %d characters", domain.ErrInvalidChecklistItem, domain.MaxChecklistItemLength)
+	}
+	return text, nil
+}
@@ -0,0 +1,228 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	taskUsecases "task_manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SubtaskUsecaseSuite struct {
+	suite.Suite
+	tasks    domain.TaskRepository
+	comments domain.CommentRepository
+	ctx      context.Context
+}
+
+func (s *SubtaskUsecaseSuite) SetupTest() {
+	s.tasks = repository.NewInMemoryTaskRepository()
+	s.comments = repository.NewInMemoryCommentRepository()
+	s.ctx = context.Background()
+}
+
+func TestSubtaskUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(SubtaskUsecaseSuite))
+}
+
+func (s *SubtaskUsecaseSuite) usecases(onDelete, onComplete string) domain.TaskUsecases {
+	rules, err := domain.NewSubtaskRules(onDelete, onComplete)
+	require.NoError(s.T(), err)
+	return taskUsecases.NewTaskUsecases(s.tasks, s.comments, repository.NewInMemoryLabelRepository(), repository.NewInMemoryDependencyRepository(), domain.DefaultWorkflow(), rules, nil, nil, nil, 2*time.Second)
+}
+
+func (s *SubtaskUsecaseSuite) create(tu domain.TaskUsecases, title, parentId, status string) *domain.Task {
+	task := &domain.Task{Title: title, ParentID: parentId, Status: status, DueDate: time.Now()}
+	require.NoError(s.T(), tu.CreateTask(s.ctx, task, "user-id"))
+	return task
+}
+
+func (s *SubtaskUsecaseSuite) exists(id string) bool {
+	_, err := s.tasks.GetTaskByID(s.ctx, id)
+	return err == nil
+}
+
+func (s *SubtaskUsecaseSuite) TestCreateTask_ChecksParent() {
+	assert := assert.New(s.T())
+	tu := s.usecases("", "")
+	parent := s.create(tu, "parent", "", "")
+
+	theirs := &domain.Task{Title: "theirs", ParentID: parent.ID}
+	assert.ErrorIs(tu.CreateTask(s.ctx, theirs, "other-id"), domain.ErrInvalidParentTask)
+	missing := &domain.Task{Title: "missing", ParentID: "nope"}
+	assert.ErrorIs(tu.CreateTask(s.ctx, missing, "user-id"), domain.ErrInvalidParentTask)
+
+	id := parent.ID
+	for depth := 2; depth <= domain.MaxSubtaskDepth; depth++ {
+		id = s.create(tu, "nested", id, "").ID
+	}
+	tooDeep := &domain.Task{Title: "too deep", ParentID: id}
+	assert.ErrorIs(tu.CreateTask(s.ctx, tooDeep, "user-id"), domain.ErrInvalidParentTask)
+}
+
+func (s *SubtaskUsecaseSuite) TestProgress() {
+	assert := assert.New(s.T())
+	tu := s.usecases("", "")
+	parent := &domain.Task{Title: "parent", Checklist: []domain.ChecklistItem{{Text: "one", Done: true}, {Text: "two"}}}
+	require.NoError(s.T(), tu.CreateTask(s.ctx, parent, "user-id"))
+	s.create(tu, "done", parent.ID, "done")
+	s.create(tu, "open", parent.ID, "in_progress")
+	// Cancelled subtasks do not count either way.
+	s.create(tu, "dropped", parent.ID, "cancelled")
+
+	task, err := tu.GetTaskByID(s.ctx, parent.ID, owner)
+	require.NoError(s.T(), err)
+	assert.Equal(domain.TaskProgress{Subtasks: 2, CompletedSubtasks: 1, ChecklistItems: 2, CheckedItems: 1, Percent: 50}, task.Progress)
+
+	subtasks, err := tu.GetSubtasks(s.ctx, parent.ID, owner)
+	require.NoError(s.T(), err)
+	assert.Len(subtasks, 3)
+	for _, subtask := range subtasks {
+		if subtask.Status == "done" {
+			assert.Equal(100, subtask.Progress.Percent)
+		} else {
+			assert.Zero(subtask.Progress.Percent)
+		}
+	}
+	_, err = tu.GetSubtasks(s.ctx, parent.ID, stranger)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+}
+
+func (s *SubtaskUsecaseSuite) TestChecklist() {
+	assert := assert.New(s.T())
+	tu := s.usecases("", "")
+	task := s.create(tu, "task", "", "")
+
+	task, err := tu.AddChecklistItem(s.ctx, task.ID, "  write tests ", owner)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), task.Checklist, 1)
+	item := task.Checklist[0]
+	assert.Equal("write tests", item.Text)
+	assert.NotEmpty(item.ID)
+
+	done := true
+	task, err = tu.UpdateChecklistItem(s.ctx, task.ID, item.ID, nil, &done, owner)
+	require.NoError(s.T(), err)
+	assert.True(task.Checklist[0].Done)
+	assert.Equal(100, task.Progress.Percent)
+
+	_, err = tu.AddChecklistItem(s.ctx, task.ID, "nope", stranger)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+	_, err = tu.AddChecklistItem(s.ctx, task.ID, " ", owner)
+	assert.ErrorIs(err, domain.ErrInvalidChecklistItem)
+	long := strings.Repeat("x", domain.MaxChecklistItemLength+1)
+	_, err = tu.UpdateChecklistItem(s.ctx, task.ID, item.ID, &long, nil, owner)
+	assert.ErrorIs(err, domain.ErrInvalidChecklistItem)
+	_, err = tu.RemoveChecklistItem(s.ctx, task.ID, "missing", owner)
+	assert.ErrorIs(err, domain.ErrChecklistItemNotFound)
+
+	task, err = tu.RemoveChecklistItem(s.ctx, task.ID, item.ID, owner)
+	require.NoError(s.T(), err)
+	assert.Empty(task.Checklist)
+}
+
+func (s *SubtaskUsecaseSuite) TestUpdateTask_KeepsChecklistAndParent() {
+	assert := assert.New(s.T())
+	tu := s.usecases("", "")
+	parent := s.create(tu, "parent", "", "")
+	child := &domain.Task{Title: "child", ParentID: parent.ID, Checklist: []domain.ChecklistItem{{Text: "step"}}}
+	require.NoError(s.T(), tu.CreateTask(s.ctx, child, "user-id"))
+
+	updated, err := tu.UpdateTask(s.ctx, child.ID, &domain.Task{Title: "renamed"}, owner)
+	require.NoError(s.T(), err)
+	assert.Equal(parent.ID, updated.ParentID)
+	assert.Len(updated.Checklist, 1)
+}
+
+func (s *SubtaskUsecaseSuite) TestComplete_Restrict() {
+	assert := assert.New(s.T())
+	tu := s.usecases("", domain.SubtasksRestrict)
+	parent := s.create(tu, "parent", "", "in_progress")
+	child := s.create(tu, "child", parent.ID, "in_progress")
+
+	_, err := tu.UpdateTask(s.ctx, parent.ID, &domain.Task{Title: "parent", Status: "done"}, owner)
+	assert.ErrorIs(err, domain.ErrOpenSubtasks)
+
+	_, err = tu.UpdateTask(s.ctx, child.ID, &domain.Task{Title: "child", Status: "done"}, owner)
+	require.NoError(s.T(), err)
+	_, err = tu.UpdateTask(s.ctx, parent.ID, &domain.Task{Title: "parent", Status: "done"}, owner)
+	assert.NoError(err)
+}
+
+func (s *SubtaskUsecaseSuite) TestComplete_Cascades() {
+	assert := assert.New(s.T())
+	tu := s.usecases("", domain.SubtasksComplete)
+	parent := s.create(tu, "parent", "", "in_progress")
+	// todo cannot normally go straight to done.
+	child := s.create(tu, "child", parent.ID, "todo")
+	grandchild := s.create(tu, "grandchild", child.ID, "blocked")
+	dropped := s.create(tu, "dropped", parent.ID, "cancelled")
+
+	updated, err := tu.UpdateTask(s.ctx, parent.ID, &domain.Task{Title: "parent", Status: "done"}, owner)
+	require.NoError(s.T(), err)
+	assert.Equal(100, updated.Progress.Percent)
+
+	for id, want := range map[string]string{child.ID: "done", grandchild.ID: "done", dropped.ID: "cancelled"} {
+		task, err := s.tasks.GetTaskByID(s.ctx, id)
+		require.NoError(s.T(), err)
+		assert.Equal(want, task.Status)
+	}
+}
+
+func (s *SubtaskUsecaseSuite) TestComplete_Ignore() {
+	tu := s.usecases("", domain.SubtasksIgnore)
+	parent := s.create(tu, "parent", "", "in_progress")
+	child := s.create(tu, "child", parent.ID, "todo")
+
+	_, err := tu.UpdateTask(s.ctx, parent.ID, &domain.Task{Title: "parent", Status: "done"}, owner)
+	require.NoError(s.T(), err)
+	task, _ := s.tasks.GetTaskByID(s.ctx, child.ID)
+	assert.Equal(s.T(), "todo", task.Status)
+}
+
+func (s *SubtaskUsecaseSuite) TestDelete_Cascades() {
+	assert := assert.New(s.T())
+	tu := s.usecases(domain.SubtasksDelete, "")
+	parent := s.create(tu, "parent", "", "")
+	child := s.create(tu, "child", parent.ID, "")
+	grandchild := s.create(tu, "grandchild", child.ID, "")
+	other := s.create(tu, "other", "", "")
+
+	require.NoError(s.T(), tu.DeleteTask(s.ctx, parent.ID, owner))
+	assert.False(s.exists(parent.ID))
+	assert.False(s.exists(child.ID))
+	assert.False(s.exists(grandchild.ID))
+	assert.True(s.exists(other.ID))
+}
+
+func (s *SubtaskUsecaseSuite) TestDelete_Detach() {
+	assert := assert.New(s.T())
+	tu := s.usecases(domain.SubtasksDetach, "")
+	parent := s.create(tu, "parent", "", "")
+	child := s.create(tu, "child", parent.ID, "")
+
+	require.NoError(s.T(), tu.DeleteTask(s.ctx, parent.ID, owner))
+	task, err := s.tasks.GetTaskByID(s.ctx, child.ID)
+	require.NoError(s.T(), err)
+	assert.Empty(task.ParentID)
+}
+
+func (s *SubtaskUsecaseSuite) TestDelete_Restrict() {
+	assert := assert.New(s.T())
+	tu := s.usecases(domain.SubtasksRestrict, "")
+	parent := s.create(tu, "parent", "", "")
+	child := s.create(tu, "child", parent.ID, "")
+
+	assert.ErrorIs(tu.DeleteTask(s.ctx, parent.ID, owner), domain.ErrTaskHasSubtasks)
+	assert.True(s.exists(parent.ID))
+
+	require.NoError(s.T(), tu.DeleteTask(s.ctx, child.ID, owner))
+	assert.NoError(tu.DeleteTask(s.ctx, parent.ID, owner))
+}
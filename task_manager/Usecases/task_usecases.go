@@ -2,45 +2,153 @@ package usecases
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	domain "task_manager/Domain"
 
 	"github.com/google/uuid"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type taskUsecases struct {
-	taskRepository domain.TaskRepository
-	contextTimeout time.Duration
+	taskRepository       domain.TaskRepository
+	commentRepository    domain.CommentRepository
+	labelRepository      domain.LabelRepository
+	dependencyRepository domain.DependencyRepository
+	workflow             *domain.Workflow
+	subtaskRules         domain.SubtaskRules
+	transactor           domain.Transactor
+	events               domain.EventPublisher
+	auditLog             domain.AuditRepository
+	contextTimeout       time.Duration
 }
 
-func NewTaskUsecases(taskRepository domain.TaskRepository, contextTimeout time.Duration) domain.TaskUsecases {
+// NewTaskUsecases builds the task usecases. Every stored change to a task is
+// published to events in the same transaction of transactor; nil publishes
+// nothing, and a nil transactor stores changes without transactions.
+// Creating, updating and deleting a task is also recorded in auditLog, in
+// the same transaction; nil records nothing.
+func NewTaskUsecases(taskRepository domain.TaskRepository, commentRepository domain.CommentRepository, labelRepository domain.LabelRepository, dependencyRepository domain.DependencyRepository, workflow *domain.Workflow, subtaskRules domain.SubtaskRules, transactor domain.Transactor, events domain.EventPublisher, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.TaskUsecases {
 	return &taskUsecases{
-		taskRepository: taskRepository,
-		contextTimeout: contextTimeout,
+		taskRepository:       taskRepository,
+		commentRepository:    commentRepository,
+		labelRepository:      labelRepository,
+		dependencyRepository: dependencyRepository,
+		workflow:             workflow,
+		subtaskRules:         subtaskRules,
+		transactor:           transactor,
+		events:               events,
+		auditLog:             auditLog,
+		contextTimeout:       contextTimeout,
 	}
 }
 
-func (tu *taskUsecases) GetAllTasks(ctx context.Context, id string) ([]*domain.Task, error) {
+const (
+	defaultTaskPageSize = 20
+	maxTaskPageSize     = 100
+)
+
+// GetAllTasks lists the actor's own tasks. Listing another user's tasks,
+// by setting query.UserID, needs task:read:any and an admin override.
+func (tu *taskUsecases) GetAllTasks(ctx context.Context, query domain.TaskQuery, actor *domain.Actor) (*domain.TaskPage, error) {
 	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
 	defer cancel()
 
-	tasks, err := tu.taskRepository.GetAllTasks(ctx, id)
+	if query.UserID == "" && actor != nil && actor.User != nil {
+		query.UserID = actor.User.ID
+	}
+	if err := authorizeTaskOwner(actor, query.UserID, domain.PermTaskReadAny); err != nil {
+		if err == domain.ErrTaskNotFound {
+			return nil, domain.ErrForbidden
+		}
+		return nil, err
+	}
+
+	query, err := normalizeTaskQuery(query)
 	if err != nil {
 		return nil, err
 	}
-	if len(tasks) == 0 {
-		return nil, mongo.ErrNoDocuments
+	if query.Status != "" {
+		if query.Status, err = tu.workflow.Normalize(query.Status); err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidTaskQuery, err)
+		}
+	}
+	if len(query.Labels) > 0 {
+		if query.Labels, err = tu.resolveLabels(ctx, query.UserID, query.Labels); err != nil {
+			return nil, err
+		}
 	}
-	return tasks, nil
+	page, err := tu.taskRepository.GetAllTasks(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.annotate(ctx, page.Tasks...); err != nil {
+		return nil, err
+	}
+	return page, nil
 }
 
-func (tu *taskUsecases) GetTaskByID(ctx context.Context, id string) (*domain.Task, error) {
+// normalizeTaskQuery fills in the default sort and page size and rejects
+// queries no repository could answer.
+func normalizeTaskQuery(query domain.TaskQuery) (domain.TaskQuery, error) {
+	if query.SortBy == "" {
+		query.SortBy = domain.TaskSortDueDate
+	}
+	if !slices.Contains(domain.TaskSortFields, query.SortBy) {
+		return query, domain.ErrInvalidTaskQuery
+	}
+	if query.Limit < 0 {
+		return query, domain.ErrInvalidTaskQuery
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultTaskPageSize
+	}
+	if query.Limit > maxTaskPageSize {
+		query.Limit = maxTaskPageSize
+	}
+	if !query.DueFrom.IsZero() && !query.DueTo.IsZero() && !query.DueFrom.Before(query.DueTo) {
+		return query, domain.ErrInvalidTaskQuery
+	}
+	return query, nil
+}
+
+// resolveLabels turns the label IDs or names of a query into the IDs of
+// labels in the owner's catalogue, dropping duplicates.
+func (tu *taskUsecases) resolveLabels(ctx context.Context, userId string, wanted []string) ([]string, error) {
+	catalogue, err := tu.labelRepository.GetLabelsByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, value := range wanted {
+		i := slices.IndexFunc(catalogue, func(label *domain.Label) bool { return label.ID == value })
+		if i < 0 {
+			i = slices.IndexFunc(catalogue, func(label *domain.Label) bool { return strings.EqualFold(label.Name, value) })
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("%w: unknown label %q", domain.ErrInvalidTaskQuery, value)
+		}
+		if !slices.Contains(ids, catalogue[i].ID) {
+			ids = append(ids, catalogue[i].ID)
+		}
+	}
+	return ids, nil
+}
+
+func (tu *taskUsecases) GetTaskByID(ctx context.Context, id string, actor *domain.Actor) (*domain.Task, error) {
 	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
 	defer cancel()
 
-	return tu.taskRepository.GetTaskByID(ctx, id)
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskReadAny)
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.annotate(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
 func (tu *taskUsecases) CreateTask(ctx context.Context, newTask *domain.Task, user_id string) error {
@@ -49,20 +157,275 @@ func (tu *taskUsecases) CreateTask(ctx context.Context, newTask *domain.Task, us
 
 	newTask.ID = uuid.New().String()
 	newTask.UserID = user_id
-	
-	return tu.taskRepository.CreateTask(ctx, newTask)
+	newTask.LabelIDs = nil
+	if newTask.ParentID != "" {
+		if err := tu.checkParent(ctx, newTask.ParentID, user_id); err != nil {
+			return err
+		}
+	}
+	checklist, err := newChecklist(newTask.Checklist)
+	if err != nil {
+		return err
+	}
+	newTask.Checklist = checklist
+	if newTask.Recurrence, err = newRecurrence(newTask); err != nil {
+		return err
+	}
+	if err := tu.workflow.Enter(newTask, newTask.Status, time.Now()); err != nil {
+		return err
+	}
+
+	// Tasks are created for the signed-in user; without one in ctx, the
+	// owner is taken to be the actor.
+	actor, _ := ctx.Value("user").(*domain.User)
+	if actor == nil {
+		actor = &domain.User{ID: user_id}
+	}
+	return inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		if err := tu.taskRepository.CreateTask(ctx, newTask); err != nil {
+			return err
+		}
+		if err := publishEvent(ctx, tu.events, domain.EventTaskCreated, newTask, nil); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTaskCreate, TargetType: domain.AuditTargetTask, TargetID: newTask.ID,
+		}, actor, nil, newTask)
+	})
 }
 
-func (tu *taskUsecases) UpdateTask(ctx context.Context, id string, task *domain.Task) (*domain.Task, error) {
+// UpdateTask replaces the task's fields. The ID, owner, parent, checklist,
+// labels, recurrence and workflow timestamps always come from the stored
+// task, never from the payload, and a status change must be a transition
+// the workflow allows. An empty status leaves the status unchanged. A task
+// cannot start while any of its blockers are open, completing it applies
+// the OnComplete subtask rule, and closing an occurrence of a recurring
+// task schedules the next one.
+func (tu *taskUsecases) UpdateTask(ctx context.Context, id string, task *domain.Task, actor *domain.Actor) (*domain.Task, error) {
 	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
 	defer cancel()
 
-	return tu.taskRepository.UpdateTask(ctx, id, task)
+	existing, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := tu.prepareUpdate(ctx, existing, task, now); err != nil {
+		return nil, err
+	}
+	var updated *domain.Task
+	err = inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		var err error
+		updated, err = tu.applyUpdate(ctx, existing, task, now, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// prepareUpdate fills in the fields of task that come from existing and
+// checks the status change against the workflow and the task's blockers,
+// without storing anything.
+func (tu *taskUsecases) prepareUpdate(ctx context.Context, existing *domain.Task, task *domain.Task, now time.Time) error {
+	requested := task.Status
+	task.ID = existing.ID
+	task.UserID = existing.UserID
+	task.Status = existing.Status
+	task.StartedAt = existing.StartedAt
+	task.CompletedAt = existing.CompletedAt
+	task.ParentID = existing.ParentID
+	task.Checklist = existing.Checklist
+	task.LabelIDs = existing.LabelIDs
+	task.Recurrence = existing.Recurrence
+	if requested != "" {
+		if err := tu.workflow.Transition(task, requested, now); err != nil {
+			return err
+		}
+	}
+	if task.Status != existing.Status && tu.workflow.StartsWork(task.Status) {
+		return tu.checkBlockers(ctx, task)
+	}
+	return nil
 }
 
-func (tu *taskUsecases) DeleteTask(ctx context.Context, id string) error {
+// applyUpdate stores task, prepared by prepareUpdate, over existing, with
+// what follows from it: the OnComplete subtask rule, the next occurrence,
+// the event and the audit entry. It runs in the caller's transaction.
+func (tu *taskUsecases) applyUpdate(ctx context.Context, existing *domain.Task, task *domain.Task, now time.Time, actor *domain.Actor) (*domain.Task, error) {
+	completed := task.CompletedAt != nil && existing.CompletedAt == nil
+	if completed {
+		if err := tu.completeSubtasks(ctx, task, now, actor.User); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := tu.taskRepository.UpdateTask(ctx, existing.ID, task)
+	if err != nil {
+		return nil, err
+	}
+	if tu.workflow.IsOpen(existing.Status) && !tu.workflow.IsOpen(updated.Status) {
+		if err := tu.scheduleNextOccurrence(ctx, updated, now, actor.User); err != nil {
+			return nil, err
+		}
+	}
+	if err := tu.annotate(ctx, updated); err != nil {
+		return nil, err
+	}
+	if err := tu.publishTaskUpdate(ctx, updated, completed); err != nil {
+		return nil, err
+	}
+	err = recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+		Action: domain.AuditTaskUpdate, TargetType: domain.AuditTargetTask, TargetID: existing.ID,
+	}, actor.User, existing, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteTask deletes the task with its comments and dependencies, applying
+// the OnDelete subtask rule to its subtasks first.
+func (tu *taskUsecases) DeleteTask(ctx context.Context, id string, actor *domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskDeleteAny)
+	if err != nil {
+		return err
+	}
+	return inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		if err := tu.deleteSubtasks(ctx, id, actor.User); err != nil {
+			return err
+		}
+		if err := tu.taskRepository.DeleteTask(ctx, id); err != nil {
+			return err
+		}
+		if err := tu.dependencyRepository.DeleteDependenciesByTask(ctx, id); err != nil {
+			return err
+		}
+		if err := tu.commentRepository.DeleteCommentsByTask(ctx, id); err != nil {
+			return err
+		}
+		if err := publishEvent(ctx, tu.events, domain.EventTaskDeleted, task, nil); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTaskDelete, TargetType: domain.AuditTargetTask, TargetID: id,
+		}, actor.User, task, nil)
+	})
+}
+
+func (tu *taskUsecases) AddTaskLabel(ctx context.Context, id string, labelId string, actor *domain.Actor) (*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	// Only the owner's own labels can go on a task, whoever attaches them.
+	label, err := tu.labelRepository.GetLabelByID(ctx, labelId)
+	if err != nil {
+		return nil, err
+	}
+	if label.UserID != task.UserID {
+		return nil, domain.ErrLabelNotFound
+	}
+	if !slices.Contains(task.LabelIDs, labelId) && len(task.LabelIDs) >= domain.MaxTaskLabels {
+		return nil, domain.ErrTooManyLabels
+	}
+
+	return tu.changeInPlace(ctx, task, actor, func(ctx context.Context) error {
+		return tu.taskRepository.AddTaskLabel(ctx, id, labelId)
+	})
+}
+
+// RemoveTaskLabel detaches a label from the task. Removing a label the
+// task does not carry is not an error.
+func (tu *taskUsecases) RemoveTaskLabel(ctx context.Context, id string, labelId string, actor *domain.Actor) (*domain.Task, error) {
 	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
 	defer cancel()
 
-	return tu.taskRepository.DeleteTask(ctx, id)
+	task, err := tu.getAuthorizedTask(ctx, id, actor, domain.PermTaskUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	return tu.changeInPlace(ctx, task, actor, func(ctx context.Context) error {
+		return tu.taskRepository.RemoveTaskLabel(ctx, id, labelId)
+	})
+}
+
+// changeInPlace makes a change to existing with change, which does not
+// return the task, then reads the task back, publishes the change and
+// records it as actor's, all in one transaction.
+func (tu *taskUsecases) changeInPlace(ctx context.Context, existing *domain.Task, actor *domain.Actor, change func(ctx context.Context) error) (*domain.Task, error) {
+	id := existing.ID
+	var task *domain.Task
+	err := inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		if err := change(ctx); err != nil {
+			return err
+		}
+		var err error
+		if task, err = tu.taskRepository.GetTaskByID(ctx, id); err != nil {
+			return err
+		}
+		if err := tu.annotate(ctx, task); err != nil {
+			return err
+		}
+		if err := tu.publishTaskUpdate(ctx, task, false); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTaskUpdate, TargetType: domain.AuditTargetTask, TargetID: id,
+		}, actor.User, existing, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// annotate fills in the fields of tasks that are computed rather than
+// stored.
+func (tu *taskUsecases) annotate(ctx context.Context, tasks ...*domain.Task) error {
+	if err := tu.countComments(ctx, tasks...); err != nil {
+		return err
+	}
+	return tu.rollUpProgress(ctx, tasks...)
+}
+
+// countComments fills in the CommentCount of each task with one query.
+func (tu *taskUsecases) countComments(ctx context.Context, tasks ...*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	counts, err := tu.commentRepository.CountCommentsByTask(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		task.CommentCount = counts[task.ID]
+	}
+	return nil
+}
+
+// getAuthorizedTask loads a task and checks the actor may operate on it,
+// using anyPermission when the actor is not the owner.
+func (tu *taskUsecases) getAuthorizedTask(ctx context.Context, id string, actor *domain.Actor, anyPermission string) (*domain.Task, error) {
+	task, err := tu.taskRepository.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := authorizeTaskOwner(actor, task.UserID, anyPermission); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
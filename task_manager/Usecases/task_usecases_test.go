@@ -3,6 +3,8 @@ package usecases_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -17,61 +19,194 @@ import (
 
 type TaskUsecaseSuite struct {
 	suite.Suite
-	taskRepo *mocks.TaskRepository
-	timeout  time.Duration
-	taskUC   domain.TaskUsecases
+	taskRepo       *mocks.TaskRepository
+	commentRepo    *mocks.CommentRepository
+	labelRepo      *mocks.LabelRepository
+	dependencyRepo *mocks.DependencyRepository
+	events         *mocks.EventPublisher
+	timeout        time.Duration
+	taskUC         domain.TaskUsecases
 }
 
 func (s *TaskUsecaseSuite) SetupTest() {
 	s.taskRepo = new(mocks.TaskRepository)
+	s.commentRepo = new(mocks.CommentRepository)
+	s.labelRepo = new(mocks.LabelRepository)
+	s.dependencyRepo = new(mocks.DependencyRepository)
+	s.dependencyRepo.On("GetBlockers", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	s.dependencyRepo.On("DeleteDependenciesByTask", mock.Anything, mock.Anything).Return(nil).Maybe()
+	s.commentRepo.On("CountCommentsByTask", mock.Anything, mock.Anything).Return(map[string]int{}, nil).Maybe()
+	s.commentRepo.On("DeleteCommentsByTask", mock.Anything, mock.Anything).Return(nil).Maybe()
+	s.taskRepo.On("CountSubtasksByStatus", mock.Anything, mock.Anything).Return(map[string]map[string]int{}, nil).Maybe()
+	s.taskRepo.On("GetSubtasks", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	s.events = new(mocks.EventPublisher)
+	s.events.On("Publish", mock.Anything, mock.Anything).Return(nil).Maybe()
 	s.timeout = time.Second * 2
-	s.taskUC = taskUsecases.NewTaskUsecases(s.taskRepo, s.timeout)
+	s.taskUC = taskUsecases.NewTaskUsecases(s.taskRepo, s.commentRepo, s.labelRepo, s.dependencyRepo, domain.DefaultWorkflow(), domain.DefaultSubtaskRules(), nil, s.events, nil, s.timeout)
 }
 
 func TestTaskUsecaseSuite(t *testing.T) {
 	suite.Run(t, new(TaskUsecaseSuite))
 }
 
+var (
+	owner    = &domain.Actor{User: &domain.User{ID: "user-id", Role: domain.RoleUser}, Permissions: domain.BuiltInRole(domain.RoleUser).Permissions}
+	stranger = &domain.Actor{User: &domain.User{ID: "other-id", Role: domain.RoleUser}, Permissions: domain.BuiltInRole(domain.RoleUser).Permissions}
+	admin    = &domain.Actor{User: &domain.User{ID: "admin-id", Role: domain.RoleAdmin}, Permissions: domain.Permissions}
+	override = &domain.Actor{User: &domain.User{ID: "admin-id", Role: domain.RoleAdmin}, Permissions: domain.Permissions, AdminOverride: true}
+)
+
 func (s *TaskUsecaseSuite) TestGetAllTasks_Success() {
 	assert := assert.New(s.T())
-	tasks := []*domain.Task{{ID: "1", Title: "Task 1"}}
+	page := &domain.TaskPage{Tasks: []*domain.Task{{ID: "1", Title: "Task 1"}}}
 
-	s.taskRepo.On("GetAllTasks", mock.Anything, "user-id").Return(tasks, nil).Once()
-	result, err := s.taskUC.GetAllTasks(context.Background(), "user-id")
+	s.taskRepo.On("GetAllTasks", mock.Anything, domain.TaskQuery{
+		UserID: "user-id", SortBy: domain.TaskSortDueDate, Limit: 20,
+	}).Return(page, nil).Once()
+	result, err := s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{UserID: "user-id"}, owner)
 
 	assert.NoError(err)
-	assert.Len(result, 1)
+	assert.Len(result.Tasks, 1)
 	s.taskRepo.AssertExpectations(s.T())
 }
 
 func (s *TaskUsecaseSuite) TestGetAllTasks_NoTasks() {
 	assert := assert.New(s.T())
-	s.taskRepo.On("GetAllTasks", mock.Anything, "user-id").Return([]*domain.Task{}, nil).Once()
-	result, err := s.taskUC.GetAllTasks(context.Background(), "user-id")
+	s.taskRepo.On("GetAllTasks", mock.Anything, mock.Anything).Return(&domain.TaskPage{}, nil).Once()
+	result, err := s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{UserID: "user-id"}, owner)
 
-	assert.Error(err)
-	assert.Nil(result)
+	// An empty page is a valid answer to a filtered or paginated query.
+	assert.NoError(err)
+	assert.Empty(result.Tasks)
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestGetAllTasks_ClampsLimit() {
+	s.taskRepo.On("GetAllTasks", mock.Anything, mock.MatchedBy(func(q domain.TaskQuery) bool {
+		return q.Limit == 100 && q.SortBy == domain.TaskSortTitle && q.SortDesc
+	})).Return(&domain.TaskPage{}, nil).Once()
+
+	_, err := s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{
+		UserID: "user-id", SortBy: domain.TaskSortTitle, SortDesc: true, Limit: 1000,
+	}, owner)
+
+	assert.NoError(s.T(), err)
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestGetAllTasks_InvalidQuery() {
+	assert := assert.New(s.T())
+	due := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, query := range []domain.TaskQuery{
+		{UserID: "user-id", SortBy: "password"},
+		{UserID: "user-id", Limit: -1},
+		{UserID: "user-id", DueFrom: due, DueTo: due},
+	} {
+		_, err := s.taskUC.GetAllTasks(context.Background(), query, owner)
+		assert.ErrorIs(err, domain.ErrInvalidTaskQuery)
+	}
+	s.taskRepo.AssertNotCalled(s.T(), "GetAllTasks", mock.Anything, mock.Anything)
+}
+
+func (s *TaskUsecaseSuite) TestGetAllTasks_NormalizesStatusFilter() {
+	assert := assert.New(s.T())
+	s.taskRepo.On("GetAllTasks", mock.Anything, mock.MatchedBy(func(q domain.TaskQuery) bool {
+		return q.Status == domain.StatusDone
+	})).Return(&domain.TaskPage{}, nil).Once()
+
+	_, err := s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{Status: "Completed"}, owner)
+	assert.NoError(err)
+
+	_, err = s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{Status: "someday"}, owner)
+	assert.ErrorIs(err, domain.ErrInvalidTaskQuery)
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestGetAllTasks_DefaultsToActor() {
+	s.taskRepo.On("GetAllTasks", mock.Anything, mock.MatchedBy(func(q domain.TaskQuery) bool {
+		return q.UserID == "user-id"
+	})).Return(&domain.TaskPage{}, nil).Once()
+
+	_, err := s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{}, owner)
+
+	assert.NoError(s.T(), err)
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestGetAllTasks_OtherUser() {
+	assert := assert.New(s.T())
+	query := domain.TaskQuery{UserID: "user-id"}
+
+	_, err := s.taskUC.GetAllTasks(context.Background(), query, stranger)
+	assert.ErrorIs(err, domain.ErrForbidden)
+	_, err = s.taskUC.GetAllTasks(context.Background(), query, admin)
+	assert.ErrorIs(err, domain.ErrForbidden)
+	_, err = s.taskUC.GetAllTasks(context.Background(), query, nil)
+	assert.ErrorIs(err, domain.ErrUnauthorized)
+
+	s.taskRepo.On("GetAllTasks", mock.Anything, mock.Anything).Return(&domain.TaskPage{}, nil).Once()
+	_, err = s.taskUC.GetAllTasks(context.Background(), query, override)
+	assert.NoError(err)
 	s.taskRepo.AssertExpectations(s.T())
 }
 
 func (s *TaskUsecaseSuite) TestGetTaskByID_Success() {
 	assert := assert.New(s.T())
-	task := &domain.Task{ID: "task-id", Title: "Task Title"}
+	task := &domain.Task{ID: "task-id", UserID: "user-id", Title: "Task Title"}
 	s.taskRepo.On("GetTaskByID", mock.Anything, "task-id").Return(task, nil).Once()
 
-	result, err := s.taskUC.GetTaskByID(context.Background(), "task-id")
+	result, err := s.taskUC.GetTaskByID(context.Background(), "task-id", owner)
 
 	assert.NoError(err)
 	assert.Equal("task-id", result.ID)
 	s.taskRepo.AssertExpectations(s.T())
 }
 
+func (s *TaskUsecaseSuite) TestGetTaskByID_Ownership() {
+	assert := assert.New(s.T())
+	task := &domain.Task{ID: "task-id", UserID: "user-id"}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "task-id").Return(task, nil)
+
+	// Other users cannot tell the task exists; admins are told to override.
+	_, err := s.taskUC.GetTaskByID(context.Background(), "task-id", stranger)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+
+	_, err = s.taskUC.GetTaskByID(context.Background(), "task-id", admin)
+	assert.ErrorIs(err, domain.ErrForbidden)
+
+	_, err = s.taskUC.GetTaskByID(context.Background(), "task-id", nil)
+	assert.ErrorIs(err, domain.ErrUnauthorized)
+
+	result, err := s.taskUC.GetTaskByID(context.Background(), "task-id", override)
+	assert.NoError(err)
+	assert.Equal("task-id", result.ID)
+}
+
+func (s *TaskUsecaseSuite) TestGetTaskByID_OverrideIgnoredForNonAdmins() {
+	task := &domain.Task{ID: "task-id", UserID: "user-id"}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "task-id").Return(task, nil).Once()
+
+	actor := &domain.Actor{User: stranger.User, AdminOverride: true}
+	_, err := s.taskUC.GetTaskByID(context.Background(), "task-id", actor)
+
+	assert.ErrorIs(s.T(), err, domain.ErrTaskNotFound)
+}
+
+func (s *TaskUsecaseSuite) TestGetTaskByID_NotFound() {
+	s.taskRepo.On("GetTaskByID", mock.Anything, "missing").Return(nil, domain.ErrTaskNotFound).Once()
+
+	_, err := s.taskUC.GetTaskByID(context.Background(), "missing", owner)
+
+	assert.ErrorIs(s.T(), err, domain.ErrTaskNotFound)
+}
+
 func (s *TaskUsecaseSuite) TestCreateTask_Success() {
 	assert := assert.New(s.T())
 	task := &domain.Task{Title: "Create Me"}
 
 	s.taskRepo.On("CreateTask", mock.Anything, mock.MatchedBy(func(t *domain.Task) bool {
-		return t.ID != "" && t.UserID == "user-id"
+		return t.ID != "" && t.UserID == "user-id" && t.Status == domain.StatusTodo
 	})).Return(nil).Once()
 
 	err := s.taskUC.CreateTask(context.Background(), task, "user-id")
@@ -80,36 +215,327 @@ func (s *TaskUsecaseSuite) TestCreateTask_Success() {
 	s.taskRepo.AssertExpectations(s.T())
 }
 
+func (s *TaskUsecaseSuite) TestCreateTask_NormalizesStatus() {
+	task := &domain.Task{Title: "Started", Status: "In Progress"}
+	s.taskRepo.On("CreateTask", mock.Anything, mock.Anything).Return(nil).Once()
+
+	err := s.taskUC.CreateTask(context.Background(), task, "user-id")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), domain.StatusInProgress, task.Status)
+	assert.NotNil(s.T(), task.StartedAt)
+}
+
+func (s *TaskUsecaseSuite) TestCreateTask_InvalidStatus() {
+	err := s.taskUC.CreateTask(context.Background(), &domain.Task{Status: "someday"}, "user-id")
+
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidStatus)
+	s.taskRepo.AssertNotCalled(s.T(), "CreateTask", mock.Anything, mock.Anything)
+}
+
 func (s *TaskUsecaseSuite) TestUpdateTask_Success() {
 	assert := assert.New(s.T())
-	updated := &domain.Task{ID: "1", Title: "Updated Title"}
+	existing := &domain.Task{ID: "1", UserID: "user-id", Title: "Old Title"}
+	updated := &domain.Task{Title: "Updated Title", UserID: "someone-else"}
 
-	s.taskRepo.On("UpdateTask", mock.Anything, "1", updated).Return(updated, nil).Once()
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(existing, nil).Once()
+	s.taskRepo.On("UpdateTask", mock.Anything, "1", mock.MatchedBy(func(t *domain.Task) bool {
+		// The payload cannot move the task to another owner or ID.
+		return t.ID == "1" && t.UserID == "user-id"
+	})).Return(updated, nil).Once()
 
-	result, err := s.taskUC.UpdateTask(context.Background(), "1", updated)
+	result, err := s.taskUC.UpdateTask(context.Background(), "1", updated, owner)
 
 	assert.NoError(err)
 	assert.Equal("Updated Title", result.Title)
 	s.taskRepo.AssertExpectations(s.T())
 }
 
+func (s *TaskUsecaseSuite) TestUpdateTask_Transition() {
+	assert := assert.New(s.T())
+	existing := &domain.Task{ID: "1", UserID: "user-id", Status: domain.StatusTodo}
+	payload := &domain.Task{Title: "Working", Status: "in_progress", CompletedAt: &time.Time{}}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(existing, nil).Once()
+	s.taskRepo.On("UpdateTask", mock.Anything, "1", mock.Anything).Return(payload, nil).Once()
+
+	_, err := s.taskUC.UpdateTask(context.Background(), "1", payload, owner)
+
+	assert.NoError(err)
+	assert.Equal(domain.StatusInProgress, payload.Status)
+	if assert.NotNil(payload.StartedAt) {
+		assert.WithinDuration(time.Now(), *payload.StartedAt, time.Minute)
+	}
+	assert.Nil(payload.CompletedAt, "timestamps cannot be set through the payload")
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestUpdateTask_KeepsStatusWhenOmitted() {
+	started := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	existing := &domain.Task{ID: "1", UserID: "user-id", Status: domain.StatusInProgress, StartedAt: &started}
+	payload := &domain.Task{Title: "Renamed"}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(existing, nil).Once()
+	s.taskRepo.On("UpdateTask", mock.Anything, "1", mock.Anything).Return(payload, nil).Once()
+
+	_, err := s.taskUC.UpdateTask(context.Background(), "1", payload, owner)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), domain.StatusInProgress, payload.Status)
+	assert.Equal(s.T(), &started, payload.StartedAt)
+}
+
+func (s *TaskUsecaseSuite) TestUpdateTask_IllegalTransition() {
+	existing := &domain.Task{ID: "1", UserID: "user-id", Status: domain.StatusTodo}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(existing, nil).Once()
+
+	_, err := s.taskUC.UpdateTask(context.Background(), "1", &domain.Task{Status: domain.StatusDone}, owner)
+
+	var transitionErr *domain.InvalidTransitionError
+	assert.ErrorAs(s.T(), err, &transitionErr)
+	assert.Equal(s.T(), domain.StatusTodo, transitionErr.From)
+	s.taskRepo.AssertNotCalled(s.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TaskUsecaseSuite) TestUpdateTask_NotOwner() {
+	assert := assert.New(s.T())
+	existing := &domain.Task{ID: "1", UserID: "user-id"}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(existing, nil)
+
+	_, err := s.taskUC.UpdateTask(context.Background(), "1", &domain.Task{}, stranger)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+
+	_, err = s.taskUC.UpdateTask(context.Background(), "1", &domain.Task{}, admin)
+	assert.ErrorIs(err, domain.ErrForbidden)
+
+	s.taskRepo.AssertNotCalled(s.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (s *TaskUsecaseSuite) TestDeleteTask_Success() {
 	assert := assert.New(s.T())
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil).Once()
 	s.taskRepo.On("DeleteTask", mock.Anything, "1").Return(nil).Once()
 
-	err := s.taskUC.DeleteTask(context.Background(), "1")
+	err := s.taskUC.DeleteTask(context.Background(), "1", owner)
 
 	assert.NoError(err)
 	s.taskRepo.AssertExpectations(s.T())
 }
 
+func (s *TaskUsecaseSuite) TestDeleteTask_DeletesComments() {
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil).Once()
+	s.taskRepo.On("DeleteTask", mock.Anything, "1").Return(nil).Once()
+
+	assert.NoError(s.T(), s.taskUC.DeleteTask(context.Background(), "1", owner))
+	s.commentRepo.AssertCalled(s.T(), "DeleteCommentsByTask", mock.Anything, "1")
+}
+
+func (s *TaskUsecaseSuite) TestDeleteTask_AdminOverride() {
+	assert := assert.New(s.T())
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil)
+	s.taskRepo.On("DeleteTask", mock.Anything, "1").Return(nil).Once()
+
+	assert.ErrorIs(s.taskUC.DeleteTask(context.Background(), "1", admin), domain.ErrForbidden)
+	assert.ErrorIs(s.taskUC.DeleteTask(context.Background(), "1", stranger), domain.ErrTaskNotFound)
+	assert.NoError(s.taskUC.DeleteTask(context.Background(), "1", override))
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestDeleteTask_CustomRoleAnyPermission() {
+	assert := assert.New(s.T())
+	// A moderator may delete anyone's tasks but edit only their own.
+	moderator := &domain.Actor{
+		User:          &domain.User{ID: "mod-id", Role: "moderator"},
+		Permissions:   []string{domain.PermTaskRead, domain.PermTaskDelete, domain.PermTaskDeleteAny},
+		AdminOverride: true,
+	}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil)
+	s.taskRepo.On("DeleteTask", mock.Anything, "1").Return(nil).Once()
+
+	assert.NoError(s.taskUC.DeleteTask(context.Background(), "1", moderator))
+	_, err := s.taskUC.UpdateTask(context.Background(), "1", &domain.Task{Title: "x"}, moderator)
+	assert.ErrorIs(err, domain.ErrTaskNotFound)
+	s.taskRepo.AssertNotCalled(s.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (s *TaskUsecaseSuite) TestDeleteTask_Error() {
 	assert := assert.New(s.T())
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil).Once()
 	s.taskRepo.On("DeleteTask", mock.Anything, "1").Return(errors.New("delete failed")).Once()
 
-	err := s.taskUC.DeleteTask(context.Background(), "1")
+	err := s.taskUC.DeleteTask(context.Background(), "1", owner)
 
 	assert.Error(err)
 	assert.EqualError(err, "delete failed")
 	s.taskRepo.AssertExpectations(s.T())
 }
+
+func (s *TaskUsecaseSuite) TestCommentCounts() {
+	assert := assert.New(s.T())
+	s.commentRepo = new(mocks.CommentRepository)
+	s.taskUC = taskUsecases.NewTaskUsecases(s.taskRepo, s.commentRepo, s.labelRepo, s.dependencyRepo, domain.DefaultWorkflow(), domain.DefaultSubtaskRules(), nil, s.events, nil, s.timeout)
+
+	page := &domain.TaskPage{Tasks: []*domain.Task{{ID: "1", UserID: "user-id"}, {ID: "2", UserID: "user-id"}}}
+	s.taskRepo.On("GetAllTasks", mock.Anything, mock.Anything).Return(page, nil).Once()
+	// One query for the whole page.
+	s.commentRepo.On("CountCommentsByTask", mock.Anything, []string{"1", "2"}).Return(map[string]int{"2": 3}, nil).Once()
+
+	result, err := s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{}, owner)
+	assert.NoError(err)
+	assert.Equal(0, result.Tasks[0].CommentCount)
+	assert.Equal(3, result.Tasks[1].CommentCount)
+
+	s.taskRepo.On("GetTaskByID", mock.Anything, "2").Return(&domain.Task{ID: "2", UserID: "user-id"}, nil).Once()
+	s.commentRepo.On("CountCommentsByTask", mock.Anything, []string{"2"}).Return(map[string]int{"2": 3}, nil).Once()
+
+	task, err := s.taskUC.GetTaskByID(context.Background(), "2", owner)
+	assert.NoError(err)
+	assert.Equal(3, task.CommentCount)
+	s.commentRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestCommentCounts_Error() {
+	s.commentRepo = new(mocks.CommentRepository)
+	s.taskUC = taskUsecases.NewTaskUsecases(s.taskRepo, s.commentRepo, s.labelRepo, s.dependencyRepo, domain.DefaultWorkflow(), domain.DefaultSubtaskRules(), nil, s.events, nil, s.timeout)
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil).Once()
+	s.commentRepo.On("CountCommentsByTask", mock.Anything, mock.Anything).Return(nil, errors.New("database down")).Once()
+
+	_, err := s.taskUC.GetTaskByID(context.Background(), "1", owner)
+	assert.EqualError(s.T(), err, "database down")
+}
+
+func (s *TaskUsecaseSuite) TestGetAllTasks_ResolvesLabelNames() {
+	s.labelRepo.On("GetLabelsByUser", mock.Anything, "user-id").Return([]*domain.Label{
+		{ID: "l1", UserID: "user-id", Name: "Backend"},
+		{ID: "l2", UserID: "user-id", Name: "Urgent"},
+	}, nil)
+	s.taskRepo.On("GetAllTasks", mock.Anything, mock.MatchedBy(func(q domain.TaskQuery) bool {
+		return slices.Equal(q.Labels, []string{"l2", "l1"}) && q.MatchAllLabels
+	})).Return(&domain.TaskPage{}, nil).Once()
+
+	// Names match regardless of case, IDs work too, and duplicates collapse.
+	_, err := s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{Labels: []string{"urgent", "l1", "BACKEND"}, MatchAllLabels: true}, owner)
+	assert.NoError(s.T(), err)
+
+	_, err = s.taskUC.GetAllTasks(context.Background(), domain.TaskQuery{Labels: []string{"frontend"}}, owner)
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTaskQuery)
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestAddTaskLabel() {
+	assert := assert.New(s.T())
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil).Once()
+	s.labelRepo.On("GetLabelByID", mock.Anything, "l1").Return(&domain.Label{ID: "l1", UserID: "user-id"}, nil)
+	s.taskRepo.On("AddTaskLabel", mock.Anything, "1", "l1").Return(nil).Once()
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id", LabelIDs: []string{"l1"}}, nil).Once()
+
+	task, err := s.taskUC.AddTaskLabel(context.Background(), "1", "l1", owner)
+
+	assert.NoError(err)
+	assert.Equal([]string{"l1"}, task.LabelIDs)
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestAddTaskLabel_OnlyTheOwnersLabels() {
+	// An admin acting on someone else's task still uses the owner's
+	// catalogue, not their own.
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil)
+	s.labelRepo.On("GetLabelByID", mock.Anything, "admins").Return(&domain.Label{ID: "admins", UserID: "admin-id"}, nil)
+
+	_, err := s.taskUC.AddTaskLabel(context.Background(), "1", "admins", override)
+
+	assert.ErrorIs(s.T(), err, domain.ErrLabelNotFound)
+	s.taskRepo.AssertNotCalled(s.T(), "AddTaskLabel", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TaskUsecaseSuite) TestAddTaskLabel_TooMany() {
+	full := make([]string, domain.MaxTaskLabels)
+	for i := range full {
+		full[i] = fmt.Sprint("label-", i)
+	}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id", LabelIDs: full}, nil)
+	s.labelRepo.On("GetLabelByID", mock.Anything, "l1").Return(&domain.Label{ID: "l1", UserID: "user-id"}, nil)
+
+	_, err := s.taskUC.AddTaskLabel(context.Background(), "1", "l1", owner)
+
+	assert.ErrorIs(s.T(), err, domain.ErrTooManyLabels)
+}
+
+func (s *TaskUsecaseSuite) TestUpdateTask_KeepsLabels() {
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id", Status: domain.StatusTodo, LabelIDs: []string{"l1"}}, nil)
+	s.taskRepo.On("UpdateTask", mock.Anything, "1", mock.MatchedBy(func(t *domain.Task) bool {
+		return slices.Equal(t.LabelIDs, []string{"l1"})
+	})).Return(func(_ context.Context, _ string, t *domain.Task) *domain.Task { return t }, nil).Once()
+
+	_, err := s.taskUC.UpdateTask(context.Background(), "1", &domain.Task{Title: "Renamed", LabelIDs: []string{"smuggled"}}, owner)
+
+	assert.NoError(s.T(), err)
+	s.taskRepo.AssertExpectations(s.T())
+}
+
+// published returns the types of the events published so far, in order.
+func (s *TaskUsecaseSuite) published() []string {
+	var types []string
+	for _, call := range s.events.Calls {
+		types = append(types, call.Arguments.Get(1).(*domain.Event).Type)
+	}
+	return types
+}
+
+func (s *TaskUsecaseSuite) TestCreateTask_PublishesEvent() {
+	s.taskRepo.On("CreateTask", mock.Anything, mock.Anything).Return(nil).Once()
+
+	task := &domain.Task{Title: "Announce Me"}
+	assert.NoError(s.T(), s.taskUC.CreateTask(context.Background(), task, "user-id"))
+
+	s.events.AssertCalled(s.T(), "Publish", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventTaskCreated && e.ID != "" && e.Task == task
+	}))
+}
+
+func (s *TaskUsecaseSuite) TestUpdateTask_PublishesCompletion() {
+	existing := &domain.Task{ID: "1", UserID: "user-id", Status: domain.StatusInProgress}
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(existing, nil).Once()
+	s.taskRepo.On("UpdateTask", mock.Anything, "1", mock.Anything).Return(func(_ context.Context, _ string, t *domain.Task) *domain.Task { return t }, nil).Once()
+
+	_, err := s.taskUC.UpdateTask(context.Background(), "1", &domain.Task{Title: "Done", Status: domain.StatusDone}, owner)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{domain.EventTaskUpdated, domain.EventTaskCompleted}, s.published())
+}
+
+func (s *TaskUsecaseSuite) TestUpdateTask_PublishFailureFailsTheUpdate() {
+	s.events = new(mocks.EventPublisher)
+	s.events.On("Publish", mock.Anything, mock.Anything).Return(errors.New("outbox down"))
+	// The update and its event are stored in one transaction, which the
+	// failure to publish rolls back.
+	transactor := new(mocks.Transactor)
+	transactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }).Once()
+	s.taskUC = taskUsecases.NewTaskUsecases(s.taskRepo, s.commentRepo, s.labelRepo, s.dependencyRepo, domain.DefaultWorkflow(), domain.DefaultSubtaskRules(), transactor, s.events, nil, s.timeout)
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil).Once()
+	s.taskRepo.On("UpdateTask", mock.Anything, "1", mock.Anything).Return(func(_ context.Context, _ string, t *domain.Task) *domain.Task { return t }, nil).Once()
+
+	task, err := s.taskUC.UpdateTask(context.Background(), "1", &domain.Task{Title: "Renamed"}, owner)
+
+	assert.EqualError(s.T(), err, "outbox down")
+	assert.Nil(s.T(), task)
+	transactor.AssertExpectations(s.T())
+}
+
+func (s *TaskUsecaseSuite) TestDeleteTask_PublishesEvent() {
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id", Title: "Gone"}, nil).Once()
+	s.taskRepo.On("DeleteTask", mock.Anything, "1").Return(nil).Once()
+
+	assert.NoError(s.T(), s.taskUC.DeleteTask(context.Background(), "1", owner))
+
+	// Subscribers get the task as it was.
+	s.events.AssertCalled(s.T(), "Publish", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventTaskDeleted && e.Task.Title == "Gone"
+	}))
+}
+
+func (s *TaskUsecaseSuite) TestDeleteTask_FailureIsNotPublished() {
+	s.taskRepo.On("GetTaskByID", mock.Anything, "1").Return(&domain.Task{ID: "1", UserID: "user-id"}, nil).Once()
+	s.taskRepo.On("DeleteTask", mock.Anything, "1").Return(errors.New("delete failed")).Once()
+
+	assert.Error(s.T(), s.taskUC.DeleteTask(context.Background(), "1", owner))
+	assert.Empty(s.T(), s.published())
+}
@@ -0,0 +1,241 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type tokenUsecases struct {
+	jwtService             domain.IJWTService
+	refreshTokenRepository domain.RefreshTokenRepository
+	revokedTokenRepository domain.RevokedTokenRepository
+	userRepository         domain.UserRepository
+	refreshTTL             time.Duration
+	versions               *tokenVersionCache
+	contextTimeout         time.Duration
+}
+
+// NewTokenUsecases issues refresh tokens valid for refreshTTL. Users' token
+// versions are cached for versionCacheTTL; zero disables the cache.
+func NewTokenUsecases(js domain.IJWTService, refreshTokenRepository domain.RefreshTokenRepository, revokedTokenRepository domain.RevokedTokenRepository, userRepository domain.UserRepository, refreshTTL time.Duration, versionCacheTTL time.Duration, contextTimeout time.Duration) domain.TokenUsecases {
+	return &tokenUsecases{
+		jwtService:             js,
+		refreshTokenRepository: refreshTokenRepository,
+		revokedTokenRepository: revokedTokenRepository,
+		userRepository:         userRepository,
+		refreshTTL:             refreshTTL,
+		versions:               newTokenVersionCache(versionCacheTTL),
+		contextTimeout:         contextTimeout,
+	}
+}
+
+// IssueTokens starts a new refresh token family for a fresh sign-in.
+func (tu *tokenUsecases) IssueTokens(ctx context.Context, user *domain.User) (*domain.TokenPair, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	return tu.issue(ctx, user, uuid.New().String())
+}
+
+// RefreshTokens exchanges a refresh token for a new pair. Each refresh token
+// works once: presenting one that was already rotated means it leaked, so
+// the whole family is revoked and the caller must sign in again.
+func (tu *tokenUsecases) RefreshTokens(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	stored, err := tu.refreshTokenRepository.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if err == domain.ErrTokenNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if stored.RevokedAt != nil {
+		return nil, domain.ErrTokenRevoked
+	}
+	if stored.UsedAt != nil {
+		return nil, tu.revokeReusedFamily(ctx, stored, now)
+	}
+	if !now.Before(stored.ExpiresAt) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	marked, err := tu.refreshTokenRepository.MarkRefreshTokenUsed(ctx, stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		// Another request rotated this token between our read and write.
+		return nil, tu.revokeReusedFamily(ctx, stored, now)
+	}
+
+	user, err := tu.userRepository.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if user.Disabled || user.TokenVersion != stored.TokenVersion {
+		// The user's sessions were invalidated after this token was issued.
+		if err := tu.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, stored.FamilyID, now); err != nil {
+			return nil, err
+		}
+		if user.Disabled {
+			return nil, domain.ErrAccountDisabled
+		}
+		return nil, domain.ErrTokenRevoked
+	}
+	tu.versions.put(user, now)
+	return tu.issue(ctx, user, stored.FamilyID)
+}
+
+func (tu *tokenUsecases) Logout(ctx context.Context, refreshToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	claims, ok := ctx.Value("claims").(*domain.AccessClaims)
+	if !ok || claims == nil {
+		return domain.ErrUnauthorized
+	}
+	if err := tu.revokedTokenRepository.RevokeToken(ctx, claims.TokenID, claims.ExpiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	stored, err := tu.refreshTokenRepository.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if err == domain.ErrTokenNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	// A user may only end their own sessions.
+	if stored.UserID != claims.UserID {
+		return domain.ErrInvalidToken
+	}
+	return tu.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, stored.FamilyID, time.Now())
+}
+
+// Authenticate verifies an access token and rejects it if it was revoked,
+// either on its own or by a bump of the user's token version, or if the
+// account has been disabled.
+func (tu *tokenUsecases) Authenticate(ctx context.Context, accessToken string) (*domain.AccessClaims, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	claims, err := tu.jwtService.ParseToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := tu.revokedTokenRepository.IsTokenRevoked(ctx, claims.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, domain.ErrTokenRevoked
+	}
+
+	current, err := tu.currentTokenVersion(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if current.disabled {
+		return nil, domain.ErrAccountDisabled
+	}
+	if claims.TokenVersion != current.version {
+		return nil, domain.ErrTokenRevoked
+	}
+	claims.EmailUnverified = current.unverified
+	return claims, nil
+}
+
+func (tu *tokenUsecases) ForgetTokenVersion(userId string) {
+	tu.versions.forget(userId)
+}
+
+func (tu *tokenUsecases) PublicKeys(ctx context.Context) ([]*domain.JSONWebKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	return tu.jwtService.PublicKeys(ctx)
+}
+
+func (tu *tokenUsecases) currentTokenVersion(ctx context.Context, userID string) (cachedTokenVersion, error) {
+	now := time.Now()
+	if current, ok := tu.versions.get(userID, now); ok {
+		return current, nil
+	}
+	user, err := tu.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			// The account is gone; so is every token issued to it.
+			return cachedTokenVersion{}, domain.ErrTokenRevoked
+		}
+		return cachedTokenVersion{}, err
+	}
+	tu.versions.put(user, now)
+	return newCachedTokenVersion(user), nil
+}
+
+func (tu *tokenUsecases) issue(ctx context.Context, user *domain.User, familyID string) (*domain.TokenPair, error) {
+	accessToken, err := tu.jwtService.GenerateToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = tu.refreshTokenRepository.CreateRefreshToken(ctx, &domain.RefreshToken{
+		ID:           uuid.New().String(),
+		FamilyID:     familyID,
+		UserID:       user.ID,
+		TokenHash:    hashToken(refreshToken),
+		TokenVersion: user.TokenVersion,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(tu.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func (tu *tokenUsecases) revokeReusedFamily(ctx context.Context, stored *domain.RefreshToken, now time.Time) error {
+	if err := tu.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, stored.FamilyID, now); err != nil {
+		return err
+	}
+	return domain.ErrTokenReused
+}
+
+// newOpaqueToken returns 32 random bytes, URL-safe encoded.
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is how opaque tokens are stored and looked up. A fast hash is
+// enough here: the tokens are long and random, unlike passwords.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
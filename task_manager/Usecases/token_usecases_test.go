@@ -0,0 +1,218 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	tokenUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenUsecaseSuite struct {
+	suite.Suite
+	jwt      *mocks.IJWTService
+	userRepo *mocks.UserRepository
+	refresh  domain.RefreshTokenRepository
+	revoked  domain.RevokedTokenRepository
+	uc       domain.TokenUsecases
+	user     *domain.User
+}
+
+func (s *TokenUsecaseSuite) SetupTest() {
+	s.jwt = new(mocks.IJWTService)
+	s.userRepo = new(mocks.UserRepository)
+	s.refresh = repository.NewInMemoryRefreshTokenRepository()
+	s.revoked = repository.NewInMemoryRevokedTokenRepository()
+	s.uc = tokenUsecases.NewTokenUsecases(s.jwt, s.refresh, s.revoked, s.userRepo, time.Hour, time.Minute, 2*time.Second)
+	s.user = &domain.User{ID: "u1", Email: "john@example.com", Role: "user"}
+	s.jwt.On("GenerateToken", mock.Anything).Return("access-token", nil)
+}
+
+func TestTokenUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(TokenUsecaseSuite))
+}
+
+func (s *TokenUsecaseSuite) TestRefreshTokens_Rotates() {
+	assert := assert.New(s.T())
+	ctx := context.Background()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+
+	first, err := s.uc.IssueTokens(ctx, s.user)
+	s.Require().NoError(err)
+	assert.Equal("access-token", first.AccessToken)
+	assert.NotEmpty(first.RefreshToken)
+
+	second, err := s.uc.RefreshTokens(ctx, first.RefreshToken)
+	s.Require().NoError(err)
+	assert.NotEqual(first.RefreshToken, second.RefreshToken)
+	s.userRepo.AssertExpectations(s.T())
+}
+
+func (s *TokenUsecaseSuite) TestRefreshTokens_ReuseRevokesFamily() {
+	assert := assert.New(s.T())
+	ctx := context.Background()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+
+	first, err := s.uc.IssueTokens(ctx, s.user)
+	s.Require().NoError(err)
+	second, err := s.uc.RefreshTokens(ctx, first.RefreshToken)
+	s.Require().NoError(err)
+
+	// Replaying the rotated token is treated as theft...
+	_, err = s.uc.RefreshTokens(ctx, first.RefreshToken)
+	assert.ErrorIs(err, domain.ErrTokenReused)
+
+	// ...so the legitimate successor stops working too.
+	_, err = s.uc.RefreshTokens(ctx, second.RefreshToken)
+	assert.ErrorIs(err, domain.ErrTokenRevoked)
+}
+
+func (s *TokenUsecaseSuite) TestRefreshTokens_Unknown() {
+	_, err := s.uc.RefreshTokens(context.Background(), "never-issued")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+}
+
+func (s *TokenUsecaseSuite) TestRefreshTokens_Expired() {
+	uc := tokenUsecases.NewTokenUsecases(s.jwt, s.refresh, s.revoked, s.userRepo, time.Millisecond, time.Minute, 2*time.Second)
+	pair, err := uc.IssueTokens(context.Background(), s.user)
+	s.Require().NoError(err)
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = uc.RefreshTokens(context.Background(), pair.RefreshToken)
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+}
+
+func (s *TokenUsecaseSuite) TestLogout_RevokesAccessAndRefresh() {
+	assert := assert.New(s.T())
+	pair, err := s.uc.IssueTokens(context.Background(), s.user)
+	s.Require().NoError(err)
+
+	claims := &domain.AccessClaims{TokenID: "jti-1", UserID: "u1", ExpiresAt: time.Now().Add(time.Minute)}
+	ctx := context.WithValue(context.Background(), "claims", claims)
+	s.Require().NoError(s.uc.Logout(ctx, pair.RefreshToken))
+
+	s.jwt.On("ParseToken", "access-token").Return(claims, nil).Once()
+	_, err = s.uc.Authenticate(context.Background(), "access-token")
+	assert.ErrorIs(err, domain.ErrTokenRevoked)
+
+	_, err = s.uc.RefreshTokens(context.Background(), pair.RefreshToken)
+	assert.ErrorIs(err, domain.ErrTokenRevoked)
+}
+
+func (s *TokenUsecaseSuite) TestLogout_OtherUsersRefreshToken() {
+	pair, err := s.uc.IssueTokens(context.Background(), s.user)
+	s.Require().NoError(err)
+
+	claims := &domain.AccessClaims{TokenID: "jti-2", UserID: "someone-else", ExpiresAt: time.Now().Add(time.Minute)}
+	ctx := context.WithValue(context.Background(), "claims", claims)
+	assert.ErrorIs(s.T(), s.uc.Logout(ctx, pair.RefreshToken), domain.ErrInvalidToken)
+}
+
+func (s *TokenUsecaseSuite) TestLogout_Unauthenticated() {
+	assert.ErrorIs(s.T(), s.uc.Logout(context.Background(), ""), domain.ErrUnauthorized)
+}
+
+func (s *TokenUsecaseSuite) TestAuthenticate_Valid() {
+	claims := &domain.AccessClaims{TokenID: "jti-3", UserID: "u1"}
+	s.jwt.On("ParseToken", "good").Return(claims, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+
+	got, err := s.uc.Authenticate(context.Background(), "good")
+	s.Require().NoError(err)
+	assert.Equal(s.T(), claims, got)
+}
+
+func (s *TokenUsecaseSuite) TestAuthenticate_CachesTokenVersion() {
+	claims := &domain.AccessClaims{TokenID: "jti-4", UserID: "u1"}
+	s.jwt.On("ParseToken", "good").Return(claims, nil).Twice()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		_, err := s.uc.Authenticate(context.Background(), "good")
+		s.Require().NoError(err)
+	}
+	s.userRepo.AssertNumberOfCalls(s.T(), "GetUserByID", 1)
+}
+
+func (s *TokenUsecaseSuite) TestAuthenticate_StaleTokenVersion() {
+	assert := assert.New(s.T())
+	claims := &domain.AccessClaims{TokenID: "jti-5", UserID: "u1", Role: "user", TokenVersion: 0}
+	s.jwt.On("ParseToken", "old-role").Return(claims, nil)
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil).Once()
+
+	_, err := s.uc.Authenticate(context.Background(), "old-role")
+	s.Require().NoError(err)
+
+	// The user is promoted: the version is bumped and the cache dropped.
+	promoted := &domain.User{ID: "u1", Role: "admin", TokenVersion: 1}
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(promoted, nil).Once()
+	s.uc.ForgetTokenVersion("u1")
+
+	_, err = s.uc.Authenticate(context.Background(), "old-role")
+	assert.ErrorIs(err, domain.ErrTokenRevoked)
+	s.userRepo.AssertExpectations(s.T())
+}
+
+func (s *TokenUsecaseSuite) TestAuthenticate_DeletedUser() {
+	claims := &domain.AccessClaims{TokenID: "jti-6", UserID: "gone"}
+	s.jwt.On("ParseToken", "orphan").Return(claims, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "gone").Return(nil, domain.ErrUserNotFound).Once()
+
+	_, err := s.uc.Authenticate(context.Background(), "orphan")
+	assert.ErrorIs(s.T(), err, domain.ErrTokenRevoked)
+}
+
+func (s *TokenUsecaseSuite) TestRefreshTokens_StaleTokenVersion() {
+	assert := assert.New(s.T())
+	pair, err := s.uc.IssueTokens(context.Background(), s.user)
+	s.Require().NoError(err)
+
+	bumped := &domain.User{ID: "u1", Role: "admin", TokenVersion: 1}
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(bumped, nil).Once()
+
+	_, err = s.uc.RefreshTokens(context.Background(), pair.RefreshToken)
+	assert.ErrorIs(err, domain.ErrTokenRevoked)
+}
+
+func (s *TokenUsecaseSuite) TestAuthenticate_Invalid() {
+	s.jwt.On("ParseToken", "bad").Return(nil, domain.ErrInvalidToken).Once()
+
+	_, err := s.uc.Authenticate(context.Background(), "bad")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+}
+
+func (s *TokenUsecaseSuite) TestAuthenticate_DisabledUser() {
+	claims := &domain.AccessClaims{TokenID: "jti-7", UserID: "u1"}
+	s.jwt.On("ParseToken", "good").Return(claims, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Disabled: true}, nil).Once()
+
+	_, err := s.uc.Authenticate(context.Background(), "good")
+	assert.ErrorIs(s.T(), err, domain.ErrAccountDisabled)
+}
+
+func (s *TokenUsecaseSuite) TestRefreshTokens_DisabledUser() {
+	pair, err := s.uc.IssueTokens(context.Background(), s.user)
+	s.Require().NoError(err)
+
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Disabled: true}, nil).Once()
+
+	_, err = s.uc.RefreshTokens(context.Background(), pair.RefreshToken)
+	assert.ErrorIs(s.T(), err, domain.ErrAccountDisabled)
+}
+
+func (s *TokenUsecaseSuite) TestAuthenticate_ReportsUnverifiedEmail() {
+	claims := &domain.AccessClaims{TokenID: "jti-8", UserID: "u1"}
+	s.jwt.On("ParseToken", "good").Return(claims, nil).Once()
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", EmailUnverified: true}, nil).Once()
+
+	got, err := s.uc.Authenticate(context.Background(), "good")
+	s.Require().NoError(err)
+	assert.True(s.T(), got.EmailUnverified)
+}
@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+// tokenVersionCache remembers users' current TokenVersion, and whether the
+// account is disabled or unverified, for a short while so authenticating a
+// request does not always cost a user lookup. Entries
+// are dropped explicitly when this process bumps a version; changes made by
+// other instances are picked up once the entry expires.
+type tokenVersionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedTokenVersion
+}
+
+type cachedTokenVersion struct {
+	version    int
+	disabled   bool
+	unverified bool
+	expiresAt  time.Time
+}
+
+func newTokenVersionCache(ttl time.Duration) *tokenVersionCache {
+	return &tokenVersionCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedTokenVersion),
+	}
+}
+
+func (c *tokenVersionCache) get(userID string, now time.Time) (cachedTokenVersion, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok {
+		return cachedTokenVersion{}, false
+	}
+	if !now.Before(entry.expiresAt) {
+		delete(c.entries, userID)
+		return cachedTokenVersion{}, false
+	}
+	return entry, true
+}
+
+func (c *tokenVersionCache) put(user *domain.User, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Expired entries are swept on write so the map stays bounded by the
+	// number of users active within one TTL.
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	entry := newCachedTokenVersion(user)
+	entry.expiresAt = now.Add(c.ttl)
+	c.entries[user.ID] = entry
+}
+
+func newCachedTokenVersion(user *domain.User) cachedTokenVersion {
+	return cachedTokenVersion{version: user.TokenVersion, disabled: user.Disabled, unverified: user.EmailUnverified}
+}
+
+func (c *tokenVersionCache) forget(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, userID)
+}
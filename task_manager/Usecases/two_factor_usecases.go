@@ -0,0 +1,488 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"slices"
+	"strings"
+	"time"
+
+	domain "task_manager/Domain"
+)
+
+const (
+	recoveryCodeCount = 10
+	// recoveryCodeLength base32 characters encode 48 random bits, shown in
+	// two groups of five.
+	recoveryCodeLength = 10
+)
+
+type twoFactorUsecases struct {
+	twoFactorRepository     domain.TwoFactorRepository
+	twoFactorRoleRepository domain.TwoFactorRoleRepository
+	userRepository          domain.UserRepository
+	roleUsecases            domain.RoleUsecases
+	totp                    domain.ITOTPService
+	challenges              domain.ITwoFactorChallengeService
+	tokenUsecases           domain.TokenUsecases
+	throttle                *loginThrottle
+	transactor              domain.Transactor
+	auditLog                domain.AuditRepository
+	contextTimeout          time.Duration
+}
+
+// NewTwoFactorUsecases builds the TOTP usecases. Wrong codes count towards
+// the same lockouts as wrong passwords, so attempts and the policies should
+// be the ones the user usecases get. Logins finished with a code, codes
+// refused, and 2FA being turned on or off for a user or a role are recorded
+// in auditLog, in the same transaction of transactor as the change; either
+// may be nil.
+func NewTwoFactorUsecases(twoFactorRepository domain.TwoFactorRepository, twoFactorRoleRepository domain.TwoFactorRoleRepository, userRepository domain.UserRepository, roles domain.RoleUsecases, totp domain.ITOTPService, challenges domain.ITwoFactorChallengeService, tokens domain.TokenUsecases, attempts domain.LoginAttemptRepository, accountPolicy, ipPolicy domain.LockoutPolicy, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.TwoFactorUsecases {
+	return &twoFactorUsecases{
+		twoFactorRepository:     twoFactorRepository,
+		twoFactorRoleRepository: twoFactorRoleRepository,
+		userRepository:          userRepository,
+		roleUsecases:            roles,
+		totp:                    totp,
+		challenges:              challenges,
+		tokenUsecases:           tokens,
+		throttle: &loginThrottle{
+			attempts:      attempts,
+			accountPolicy: accountPolicy,
+			ipPolicy:      ipPolicy,
+		},
+		transactor:     transactor,
+		auditLog:       auditLog,
+		contextTimeout: contextTimeout,
+	}
+}
+
+func (tu *twoFactorUsecases) Enroll(ctx context.Context, userId string) (*domain.TwoFactorEnrollment, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	user, err := tu.userRepository.GetUserByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return tu.startEnrollment(ctx, user)
+}
+
+func (tu *twoFactorUsecases) Confirm(ctx context.Context, userId string, code string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	twoFactor, err := tu.twoFactorRepository.GetTwoFactor(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return tu.enable(ctx, twoFactor, code, nil)
+}
+
+func (tu *twoFactorUsecases) Disable(ctx context.Context, userId string, code string) error {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	user, twoFactor, err := tu.enabledTwoFactor(ctx, userId)
+	if err != nil {
+		return err
+	}
+	required, err := tu.roleRequiresTwoFactor(ctx, user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return domain.ErrTwoFactorRequired
+	}
+	if err := tu.verifyCode(ctx, user, twoFactor, code, ""); err != nil {
+		return err
+	}
+	return inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		if err := tu.twoFactorRepository.DeleteTwoFactor(ctx, userId); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTwoFactorDisable, TargetType: domain.AuditTargetUser, TargetID: userId,
+		}, nil, nil, nil)
+	})
+}
+
+func (tu *twoFactorUsecases) RegenerateRecoveryCodes(ctx context.Context, userId string, code string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	user, twoFactor, err := tu.enabledTwoFactor(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.verifyCode(ctx, user, twoFactor, code, ""); err != nil {
+		return nil, err
+	}
+	// Reload so the step or recovery code just used stays spent.
+	twoFactor, err = tu.twoFactorRepository.GetTwoFactor(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	twoFactor.RecoveryCodes = hashes
+	if err := tu.twoFactorRepository.SaveTwoFactor(ctx, twoFactor); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (tu *twoFactorUsecases) BeginLogin(ctx context.Context, user *domain.User) (*domain.TwoFactorChallenge, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	twoFactor, err := tu.twoFactorRepository.GetTwoFactor(ctx, user.ID)
+	if err != nil && err != domain.ErrTwoFactorNotEnrolled {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return tu.challenge(user, false)
+	}
+	required, err := tu.roleRequiresTwoFactor(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return tu.challenge(user, true)
+	}
+	return nil, nil
+}
+
+func (tu *twoFactorUsecases) SetupLogin(ctx context.Context, challengeToken string) (*domain.TwoFactorEnrollment, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	user, claims, err := tu.challengedUser(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.SetupRequired {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	return tu.startEnrollment(ctx, user)
+}
+
+func (tu *twoFactorUsecases) CompleteLogin(ctx context.Context, challengeToken, code, clientIP string) (*domain.TwoFactorLogin, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	user, claims, err := tu.challengedUser(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := tu.twoFactorRepository.GetTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	login := &domain.TwoFactorLogin{}
+	if !twoFactor.Enabled {
+		if !claims.SetupRequired {
+			return nil, domain.ErrTwoFactorNotEnabled
+		}
+		// The user was made to enroll while signing in; their first code
+		// confirms the enrollment.
+		reservation, err := tu.throttle.reserve(ctx, user.Email, clientIP, time.Now())
+		if err != nil {
+			recordFailedLogin(ctx, tu.auditLog, user.Email, user.ID, clientIP, err)
+			return nil, err
+		}
+		codes, err := tu.enable(ctx, twoFactor, code, user)
+		if err != domain.ErrInvalidTwoFactorCode {
+			if releaseErr := tu.throttle.release(ctx, reservation); releaseErr != nil && err == nil {
+				err = releaseErr
+			}
+		}
+		if err != nil {
+			recordFailedLogin(ctx, tu.auditLog, user.Email, user.ID, clientIP, err)
+			return nil, err
+		}
+		login.RecoveryCodes = codes
+	} else if err := tu.verifyCode(ctx, user, twoFactor, code, clientIP); err != nil {
+		recordFailedLogin(ctx, tu.auditLog, user.Email, user.ID, clientIP, err)
+		return nil, err
+	}
+
+	if err := tu.throttle.reset(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	// As in Login, the audit log learns of the session before it exists.
+	err = recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+		Action: domain.AuditUserLogin, TargetType: domain.AuditTargetUser, TargetID: user.ID, IP: clientIP,
+	}, user, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	login.Tokens, err = tu.tokenUsecases.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return login, nil
+}
+
+func (tu *twoFactorUsecases) VerifyLogin(ctx context.Context, challengeToken, code, clientIP string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	user, claims, err := tu.challengedUser(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.SetupRequired {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+	twoFactor, err := tu.twoFactorRepository.GetTwoFactor(ctx, user.ID)
+	if err == domain.ErrTwoFactorNotEnrolled || (err == nil && !twoFactor.Enabled) {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.verifyCode(ctx, user, twoFactor, code, clientIP); err != nil {
+		return nil, err
+	}
+	if err := tu.throttle.reset(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (tu *twoFactorUsecases) GetTwoFactorRoles(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	return tu.twoFactorRoleRepository.GetTwoFactorRoles(ctx)
+}
+
+// SetRoleTwoFactorRequired applies from the role's members' next sign-in.
+// Turning it on also signs out every member who has not enabled 2FA, so
+// that their sessions cannot outlive the requirement.
+func (tu *twoFactorUsecases) SetRoleTwoFactorRequired(ctx context.Context, role string, required bool) error {
+	if _, err := tu.roleUsecases.GetPermissions(ctx, role); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tu.contextTimeout)
+	defer cancel()
+
+	var signedOut []string
+	err := inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		signedOut = nil
+		if err := tu.twoFactorRoleRepository.SetTwoFactorRequired(ctx, role, required); err != nil {
+			return err
+		}
+		if required {
+			members, err := tu.userRepository.GetUsersByRole(ctx, role)
+			if err != nil {
+				return err
+			}
+			for _, member := range members {
+				twoFactor, err := tu.twoFactorRepository.GetTwoFactor(ctx, member.ID)
+				if err != nil && err != domain.ErrTwoFactorNotEnrolled {
+					return err
+				}
+				if err == nil && twoFactor.Enabled {
+					continue
+				}
+				if err := tu.userRepository.BumpTokenVersion(ctx, member.ID); err != nil && err != domain.ErrUserNotFound {
+					return err
+				}
+				signedOut = append(signedOut, member.ID)
+			}
+		}
+		return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditRoleTwoFactor, TargetType: domain.AuditTargetRole, TargetID: role,
+		}, nil, nil, map[string]any{"two_factor_required": required, "signed_out": signedOut})
+	})
+	if err != nil {
+		return err
+	}
+	for _, userId := range signedOut {
+		tu.tokenUsecases.ForgetTokenVersion(userId)
+	}
+	return nil
+}
+
+// startEnrollment stores a fresh pending secret, replacing any earlier
+// pending one.
+func (tu *twoFactorUsecases) startEnrollment(ctx context.Context, user *domain.User) (*domain.TwoFactorEnrollment, error) {
+	existing, err := tu.twoFactorRepository.GetTwoFactor(ctx, user.ID)
+	if err != nil && err != domain.ErrTwoFactorNotEnrolled {
+		return nil, err
+	}
+	if existing != nil && existing.Enabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := tu.totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	uri := tu.totp.KeyURI(user.Email, secret)
+	qrCode, err := tu.totp.QRCode(uri)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor := &domain.TwoFactor{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+	if err := tu.twoFactorRepository.SaveTwoFactor(ctx, twoFactor); err != nil {
+		return nil, err
+	}
+	return &domain.TwoFactorEnrollment{Secret: secret, URI: uri, QRCode: qrCode}, nil
+}
+
+// enable turns on a pending enrollment if code is valid for its secret and
+// returns the new recovery codes. actor is who turned it on, or nil for the
+// user signed in to ctx.
+func (tu *twoFactorUsecases) enable(ctx context.Context, twoFactor *domain.TwoFactor, code string, actor *domain.User) ([]string, error) {
+	if twoFactor.Enabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	now := time.Now()
+	step, ok := tu.totp.Validate(twoFactor.Secret, code, now)
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	twoFactor.Enabled = true
+	twoFactor.EnabledAt = &now
+	twoFactor.LastUsedStep = step
+	twoFactor.RecoveryCodes = hashes
+	err = inTransaction(ctx, tu.transactor, func(ctx context.Context) error {
+		if err := tu.twoFactorRepository.SaveTwoFactor(ctx, twoFactor); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditTwoFactorEnable, TargetType: domain.AuditTargetUser, TargetID: twoFactor.UserID,
+		}, actor, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verifyCode accepts a current TOTP code or an unused recovery code for an
+// enabled enrollment, spending it. Wrong codes count as failed logins.
+func (tu *twoFactorUsecases) verifyCode(ctx context.Context, user *domain.User, twoFactor *domain.TwoFactor, code, clientIP string) error {
+	now := time.Now()
+	reservation, err := tu.throttle.reserve(ctx, user.Email, clientIP, now)
+	if err != nil {
+		return err
+	}
+
+	used, err := tu.useCode(ctx, user, twoFactor, code, now)
+	if err == nil && !used {
+		// The reservation stands as the failure.
+		return domain.ErrInvalidTwoFactorCode
+	}
+	if releaseErr := tu.throttle.release(ctx, reservation); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+// useCode spends code if it is a current TOTP code or an unused recovery
+// code, and reports whether it was.
+func (tu *twoFactorUsecases) useCode(ctx context.Context, user *domain.User, twoFactor *domain.TwoFactor, code string, now time.Time) (bool, error) {
+	if step, ok := tu.totp.Validate(twoFactor.Secret, code, now); ok {
+		return tu.twoFactorRepository.UseTOTPStep(ctx, user.ID, step)
+	}
+	if normalized := normalizeRecoveryCode(code); len(normalized) == recoveryCodeLength {
+		return tu.twoFactorRepository.UseRecoveryCode(ctx, user.ID, hashToken(normalized))
+	}
+	return false, nil
+}
+
+func (tu *twoFactorUsecases) enabledTwoFactor(ctx context.Context, userId string) (*domain.User, *domain.TwoFactor, error) {
+	user, err := tu.userRepository.GetUserByID(ctx, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	twoFactor, err := tu.twoFactorRepository.GetTwoFactor(ctx, userId)
+	if err == domain.ErrTwoFactorNotEnrolled || (err == nil && !twoFactor.Enabled) {
+		return nil, nil, domain.ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, twoFactor, nil
+}
+
+// challengedUser resolves a challenge token to its user. Like a refresh
+// token, a challenge dies with a change to the user's token version.
+func (tu *twoFactorUsecases) challengedUser(ctx context.Context, challengeToken string) (*domain.User, *domain.TwoFactorChallengeClaims, error) {
+	claims, err := tu.challenges.ParseChallengeToken(challengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := tu.userRepository.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, nil, domain.ErrInvalidToken
+		}
+		return nil, nil, err
+	}
+	if user.TokenVersion != claims.TokenVersion {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	if user.Disabled {
+		return nil, nil, domain.ErrAccountDisabled
+	}
+	return user, claims, nil
+}
+
+func (tu *twoFactorUsecases) challenge(user *domain.User, setupRequired bool) (*domain.TwoFactorChallenge, error) {
+	token, expiresAt, err := tu.challenges.GenerateChallengeToken(user, setupRequired)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TwoFactorChallenge{Token: token, ExpiresAt: expiresAt, SetupRequired: setupRequired}, nil
+}
+
+func (tu *twoFactorUsecases) roleRequiresTwoFactor(ctx context.Context, role string) (bool, error) {
+	roles, err := tu.twoFactorRoleRepository.GetTwoFactorRoles(ctx)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(roles, role), nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes returns a fresh set of recovery codes, formatted for
+// display, and the hashes to store.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeLength*5/8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, hashToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts recovery codes as typed: in any case, with
+// or without the dash and spaces.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,317 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	twoFactorUsecases "task_manager/Usecases"
+	"task_manager/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoFactorUsecaseSuite struct {
+	suite.Suite
+	twoFactorRepo domain.TwoFactorRepository
+	roleRepo      domain.TwoFactorRoleRepository
+	attempts      domain.LoginAttemptRepository
+	userRepo      *mocks.UserRepository
+	roles         *mocks.RoleUsecases
+	totp          *mocks.ITOTPService
+	challenges    *mocks.ITwoFactorChallengeService
+	tokens        *mocks.TokenUsecases
+	auditLog      domain.AuditRepository
+	uc            domain.TwoFactorUsecases
+	user          *domain.User
+}
+
+func (s *TwoFactorUsecaseSuite) SetupTest() {
+	s.twoFactorRepo = repository.NewInMemoryTwoFactorRepository()
+	s.roleRepo = repository.NewInMemoryTwoFactorRoleRepository()
+	s.attempts = repository.NewInMemoryLoginAttemptRepository()
+	s.userRepo = new(mocks.UserRepository)
+	s.roles = new(mocks.RoleUsecases)
+	s.totp = new(mocks.ITOTPService)
+	s.challenges = new(mocks.ITwoFactorChallengeService)
+	s.tokens = new(mocks.TokenUsecases)
+	s.auditLog = repository.NewInMemoryAuditRepository()
+	s.uc = twoFactorUsecases.NewTwoFactorUsecases(s.twoFactorRepo, s.roleRepo, s.userRepo, s.roles, s.totp, s.challenges, s.tokens,
+		s.attempts, testAccountLockout, testIPLockout, nil, s.auditLog, 2*time.Second)
+
+	s.user = &domain.User{ID: "u1", Email: "john@example.com", Role: domain.RoleAdmin, TokenVersion: 3}
+	s.userRepo.On("GetUserByID", mock.Anything, "u1").Return(s.user, nil)
+	s.totp.On("GenerateSecret").Return("SECRET", nil)
+	s.totp.On("KeyURI", "john@example.com", "SECRET").Return("otpauth://totp/x")
+	s.totp.On("QRCode", "otpauth://totp/x").Return([]byte("png"), nil)
+	// "111111" and "222222" are valid in consecutive time steps.
+	s.totp.On("Validate", "SECRET", "111111", mock.Anything).Return(int64(100), true)
+	s.totp.On("Validate", "SECRET", "222222", mock.Anything).Return(int64(101), true)
+	s.totp.On("Validate", "SECRET", mock.Anything, mock.Anything).Return(int64(0), false)
+}
+
+func TestTwoFactorUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorUsecaseSuite))
+}
+
+// enable enrolls the user and returns their recovery codes.
+func (s *TwoFactorUsecaseSuite) enable() []string {
+	_, err := s.uc.Enroll(context.Background(), "u1")
+	require.NoError(s.T(), err)
+	codes, err := s.uc.Confirm(context.Background(), "u1", "111111")
+	require.NoError(s.T(), err)
+	return codes
+}
+
+func (s *TwoFactorUsecaseSuite) expectChallenge(setup bool) {
+	s.challenges.On("ParseChallengeToken", "challenge").
+		Return(&domain.TwoFactorChallengeClaims{UserID: "u1", TokenVersion: 3, SetupRequired: setup}, nil)
+}
+
+func (s *TwoFactorUsecaseSuite) TestEnrollAndConfirm() {
+	ctx := context.Background()
+
+	enrollment, err := s.uc.Enroll(ctx, "u1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), &domain.TwoFactorEnrollment{Secret: "SECRET", URI: "otpauth://totp/x", QRCode: []byte("png")}, enrollment)
+
+	_, err = s.uc.Confirm(ctx, "u1", "999999")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+
+	codes, err := s.uc.Confirm(ctx, "u1", "111111")
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), codes, 10)
+	assert.Regexp(s.T(), `^[a-z2-7]{5}-[a-z2-7]{5}$`, codes[0])
+
+	stored, err := s.twoFactorRepo.GetTwoFactor(ctx, "u1")
+	require.NoError(s.T(), err)
+	assert.True(s.T(), stored.Enabled)
+	assert.Len(s.T(), stored.RecoveryCodes, 10)
+	assert.NotContains(s.T(), stored.RecoveryCodes, codes[0], "only hashes are stored")
+
+	_, err = s.uc.Enroll(ctx, "u1")
+	assert.ErrorIs(s.T(), err, domain.ErrTwoFactorAlreadyEnabled)
+	_, err = s.uc.Confirm(ctx, "u1", "222222")
+	assert.ErrorIs(s.T(), err, domain.ErrTwoFactorAlreadyEnabled)
+}
+
+func (s *TwoFactorUsecaseSuite) TestConfirm_NotEnrolled() {
+	_, err := s.uc.Confirm(context.Background(), "u1", "111111")
+	assert.ErrorIs(s.T(), err, domain.ErrTwoFactorNotEnrolled)
+}
+
+func (s *TwoFactorUsecaseSuite) TestBeginLogin() {
+	ctx := context.Background()
+
+	challenge, err := s.uc.BeginLogin(ctx, s.user)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), challenge, "no second factor without enrollment or requirement")
+
+	expiresAt := time.Now().Add(5 * time.Minute)
+	s.challenges.On("GenerateChallengeToken", s.user, true).Return("setup-challenge", expiresAt, nil).Once()
+	require.NoError(s.T(), s.roleRepo.SetTwoFactorRequired(ctx, domain.RoleAdmin, true))
+	challenge, err = s.uc.BeginLogin(ctx, s.user)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &domain.TwoFactorChallenge{Token: "setup-challenge", ExpiresAt: expiresAt, SetupRequired: true}, challenge)
+
+	s.enable()
+	s.challenges.On("GenerateChallengeToken", s.user, false).Return("challenge", expiresAt, nil).Once()
+	challenge, err = s.uc.BeginLogin(ctx, s.user)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "challenge", challenge.Token)
+	assert.False(s.T(), challenge.SetupRequired)
+}
+
+func (s *TwoFactorUsecaseSuite) TestCompleteLogin_TOTPCodeWorksOnce() {
+	ctx := context.Background()
+	s.enable()
+	s.expectChallenge(false)
+	pair := &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+	s.tokens.On("IssueTokens", mock.Anything, s.user).Return(pair, nil)
+
+	// The code used to confirm enrollment is already spent.
+	_, err := s.uc.CompleteLogin(ctx, "challenge", "111111", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+
+	login, err := s.uc.CompleteLogin(ctx, "challenge", "222222", "192.0.2.1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), pair, login.Tokens)
+	assert.Nil(s.T(), login.RecoveryCodes)
+
+	_, err = s.uc.CompleteLogin(ctx, "challenge", "222222", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+}
+
+func (s *TwoFactorUsecaseSuite) TestVerifyLogin_ReturnsTheUserWithoutIssuingTokens() {
+	ctx := context.Background()
+	s.enable()
+	s.expectChallenge(false)
+
+	_, err := s.uc.VerifyLogin(ctx, "challenge", "999999", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+
+	user, err := s.uc.VerifyLogin(ctx, "challenge", "222222", "192.0.2.1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), s.user, user)
+	s.tokens.AssertNotCalled(s.T(), "IssueTokens", mock.Anything, mock.Anything)
+
+	attempts, err := s.attempts.GetLoginAttempts(ctx, "account:john@example.com")
+	require.NoError(s.T(), err)
+	assert.Nil(s.T(), attempts, "a passed second factor clears the failures")
+}
+
+func (s *TwoFactorUsecaseSuite) TestVerifyLogin_SetupChallenge() {
+	s.expectChallenge(true)
+
+	_, err := s.uc.VerifyLogin(context.Background(), "challenge", "111111", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrTwoFactorNotEnabled)
+}
+
+func (s *TwoFactorUsecaseSuite) TestCompleteLogin_RecoveryCodeWorksOnce() {
+	ctx := context.Background()
+	codes := s.enable()
+	s.expectChallenge(false)
+	s.tokens.On("IssueTokens", mock.Anything, s.user).Return(&domain.TokenPair{}, nil)
+
+	_, err := s.uc.CompleteLogin(ctx, "challenge", " "+codes[0][:5]+codes[0][6:]+" ", "192.0.2.1")
+	assert.NoError(s.T(), err, "recovery codes are accepted without the dash")
+	_, err = s.uc.CompleteLogin(ctx, "challenge", codes[0], "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+	_, err = s.uc.CompleteLogin(ctx, "challenge", codes[1], "192.0.2.1")
+	assert.NoError(s.T(), err)
+}
+
+func (s *TwoFactorUsecaseSuite) TestCompleteLogin_WrongCodesLockAccount() {
+	ctx := context.Background()
+	s.enable()
+	s.expectChallenge(false)
+
+	for i := 0; i < testAccountLockout.Threshold; i++ {
+		_, err := s.uc.CompleteLogin(ctx, "challenge", "000000", "192.0.2.1")
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+	}
+	_, err := s.uc.CompleteLogin(ctx, "challenge", "222222", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrAccountLocked)
+	s.tokens.AssertNotCalled(s.T(), "IssueTokens", mock.Anything, mock.Anything)
+}
+
+func (s *TwoFactorUsecaseSuite) TestCompleteLogin_IsAudited() {
+	ctx := context.Background()
+	s.enable()
+	s.expectChallenge(false)
+	s.tokens.On("IssueTokens", mock.Anything, s.user).Return(&domain.TokenPair{AccessToken: "access"}, nil)
+
+	_, err := s.uc.CompleteLogin(ctx, "challenge", "000000", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+	_, err = s.uc.CompleteLogin(ctx, "challenge", "222222", "192.0.2.1")
+	require.NoError(s.T(), err)
+
+	page, err := s.auditLog.GetAuditEntries(ctx, domain.AuditQuery{TargetID: "u1"})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Entries, 3)
+	assert.Equal(s.T(), domain.AuditTwoFactorEnable, page.Entries[0].Action)
+	assert.Equal(s.T(), domain.AuditUserLoginFailed, page.Entries[1].Action)
+	assert.Empty(s.T(), page.Entries[1].ActorID)
+	assert.Equal(s.T(), domain.ErrInvalidTwoFactorCode.Error(), page.Entries[1].Reason)
+	assert.Equal(s.T(), domain.AuditUserLogin, page.Entries[2].Action)
+	assert.Equal(s.T(), "u1", page.Entries[2].ActorID)
+	assert.Equal(s.T(), "192.0.2.1", page.Entries[2].IP)
+}
+
+func (s *TwoFactorUsecaseSuite) TestCompleteLogin_StaleChallenge() {
+	s.enable()
+	s.challenges.On("ParseChallengeToken", "old").Return(&domain.TwoFactorChallengeClaims{UserID: "u1", TokenVersion: 2}, nil)
+	s.challenges.On("ParseChallengeToken", "forged").Return(nil, domain.ErrInvalidToken)
+
+	_, err := s.uc.CompleteLogin(context.Background(), "old", "222222", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+	_, err = s.uc.CompleteLogin(context.Background(), "forged", "222222", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidToken)
+}
+
+func (s *TwoFactorUsecaseSuite) TestRequiredSetupDuringLogin() {
+	ctx := context.Background()
+	s.expectChallenge(true)
+	s.tokens.On("IssueTokens", mock.Anything, s.user).Return(&domain.TokenPair{AccessToken: "access"}, nil)
+
+	_, err := s.uc.CompleteLogin(ctx, "challenge", "111111", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrTwoFactorNotEnrolled)
+
+	enrollment, err := s.uc.SetupLogin(ctx, "challenge")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "otpauth://totp/x", enrollment.URI)
+
+	_, err = s.uc.CompleteLogin(ctx, "challenge", "000000", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+
+	login, err := s.uc.CompleteLogin(ctx, "challenge", "111111", "192.0.2.1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "access", login.Tokens.AccessToken)
+	assert.Len(s.T(), login.RecoveryCodes, 10)
+
+	_, err = s.uc.SetupLogin(ctx, "challenge")
+	assert.ErrorIs(s.T(), err, domain.ErrTwoFactorAlreadyEnabled)
+}
+
+func (s *TwoFactorUsecaseSuite) TestDisable() {
+	ctx := context.Background()
+	assert.ErrorIs(s.T(), s.uc.Disable(ctx, "u1", "111111"), domain.ErrTwoFactorNotEnabled)
+
+	s.enable()
+	require.NoError(s.T(), s.roleRepo.SetTwoFactorRequired(ctx, domain.RoleAdmin, true))
+	assert.ErrorIs(s.T(), s.uc.Disable(ctx, "u1", "222222"), domain.ErrTwoFactorRequired)
+
+	require.NoError(s.T(), s.roleRepo.SetTwoFactorRequired(ctx, domain.RoleAdmin, false))
+	assert.ErrorIs(s.T(), s.uc.Disable(ctx, "u1", "000000"), domain.ErrInvalidTwoFactorCode)
+	assert.NoError(s.T(), s.uc.Disable(ctx, "u1", "222222"))
+	_, err := s.twoFactorRepo.GetTwoFactor(ctx, "u1")
+	assert.ErrorIs(s.T(), err, domain.ErrTwoFactorNotEnrolled)
+}
+
+func (s *TwoFactorUsecaseSuite) TestRegenerateRecoveryCodes() {
+	ctx := context.Background()
+	old := s.enable()
+
+	codes, err := s.uc.RegenerateRecoveryCodes(ctx, "u1", "222222")
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), codes, 10)
+	assert.NotEqual(s.T(), old, codes)
+
+	// The old codes are gone and the code just used stays spent.
+	_, err = s.uc.RegenerateRecoveryCodes(ctx, "u1", old[0])
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+	_, err = s.uc.RegenerateRecoveryCodes(ctx, "u1", "222222")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidTwoFactorCode)
+}
+
+func (s *TwoFactorUsecaseSuite) TestSetRoleTwoFactorRequired() {
+	ctx := context.Background()
+	s.roles.On("GetPermissions", mock.Anything, domain.RoleAdmin).Return([]string{}, nil)
+	s.roles.On("GetPermissions", mock.Anything, "ghost").Return(nil, domain.ErrRoleNotFound)
+	s.enable()
+	unenrolled := &domain.User{ID: "u2", Email: "jane@example.com", Role: domain.RoleAdmin}
+	s.userRepo.On("GetUsersByRole", mock.Anything, domain.RoleAdmin).Return([]*domain.User{s.user, unenrolled}, nil)
+	s.userRepo.On("BumpTokenVersion", mock.Anything, "u2").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u2").Return().Once()
+
+	assert.NoError(s.T(), s.uc.SetRoleTwoFactorRequired(ctx, domain.RoleAdmin, true))
+	assert.ErrorIs(s.T(), s.uc.SetRoleTwoFactorRequired(ctx, "ghost", true), domain.ErrRoleNotFound)
+
+	roles, err := s.uc.GetTwoFactorRoles(ctx)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{domain.RoleAdmin}, roles)
+
+	// Only the member without 2FA is signed out.
+	s.userRepo.AssertExpectations(s.T())
+	s.userRepo.AssertNotCalled(s.T(), "BumpTokenVersion", mock.Anything, "u1")
+	s.tokens.AssertExpectations(s.T())
+
+	// Turning the requirement off signs nobody out.
+	assert.NoError(s.T(), s.uc.SetRoleTwoFactorRequired(ctx, domain.RoleAdmin, false))
+	s.userRepo.AssertNumberOfCalls(s.T(), "BumpTokenVersion", 1)
+}
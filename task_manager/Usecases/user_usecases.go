@@ -11,40 +11,98 @@ import (
 
 type userUsecases struct {
 	userRepository domain.UserRepository
+	taskRepository domain.TaskRepository
+	commentRepository domain.CommentRepository
+	dependencyRepository domain.DependencyRepository
 	passwordService domain.IPasswordService
-	jwtService domain.IJWTService
+	tokenUsecases domain.TokenUsecases
+	twoFactorUsecases domain.TwoFactorUsecases
+	throttle *loginThrottle
+	transactor domain.Transactor
+	events domain.EventPublisher
+	auditLog domain.AuditRepository
 	contextTimeout time.Duration
 }
 
-func NewUserUsecases(userRepository domain.UserRepository, ps domain.IPasswordService, js domain.IJWTService, contextTimeout time.Duration) domain.UserUsecases {
+// NewUserUsecases builds the user usecases. Failed logins are counted in
+// attempts; accountPolicy and ipPolicy decide when an account or a client
+// IP is locked out. Deleting a user deletes their tasks, the comments on them
+// and the dependencies between them. twoFactor decides whether a login needs a second step.
+// New, promoted and deleted users are published to events in the same
+// transaction of transactor as the change; either may be nil. Every change
+// to an account, and every login, failed ones included, is recorded in
+// auditLog, which may be nil too.
+func NewUserUsecases(userRepository domain.UserRepository, taskRepository domain.TaskRepository, commentRepository domain.CommentRepository, dependencyRepository domain.DependencyRepository, ps domain.IPasswordService, tokens domain.TokenUsecases, twoFactor domain.TwoFactorUsecases, attempts domain.LoginAttemptRepository, accountPolicy, ipPolicy domain.LockoutPolicy, transactor domain.Transactor, events domain.EventPublisher, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.UserUsecases {
 	return &userUsecases{
 		userRepository: userRepository,
+		taskRepository: taskRepository,
+		commentRepository: commentRepository,
+		dependencyRepository: dependencyRepository,
 		passwordService: ps,
-		jwtService: js,
+		tokenUsecases: tokens,
+		twoFactorUsecases: twoFactor,
+		throttle: &loginThrottle{
+			attempts:      attempts,
+			accountPolicy: accountPolicy,
+			ipPolicy:      ipPolicy,
+		},
+		transactor: transactor,
+		events: events,
+		auditLog: auditLog,
 		contextTimeout: contextTimeout,
 	}
 }
 
-func (uu *userUsecases) Login(ctx context.Context, email, password string) (string, error) {
+func (uu *userUsecases) Login(ctx context.Context, email, password, clientIP string) (*domain.LoginResult, error) {
 	ctx, cancel := context.WithTimeout(ctx, uu.contextTimeout)
 	defer cancel()
 
-	user, err := uu.userRepository.GetUserByEmail(ctx, email)
+	user, err := uu.throttle.checkPassword(ctx, uu.userRepository, uu.passwordService, email, password, clientIP)
 	if err != nil {
-		return "", err
+		uu.recordFailedLogin(ctx, email, clientIP, err)
+		return nil, err
 	}
 
-	if !uu.passwordService.VerifyPassword(user, password) {
-		return "", err
+	// The failure count is kept until the second factor is passed too, so
+	// knowing the password does not buy more guesses at the code.
+	challenge, err := uu.twoFactorUsecases.BeginLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &domain.LoginResult{Challenge: challenge}, nil
+	}
+	if err := uu.throttle.reset(ctx, email); err != nil {
+		return nil, err
 	}
 
-	// Generate JWT token
-	token, err := uu.jwtService.GenerateToken(user)
-
+	// The login is recorded before any tokens exist, so that there is no
+	// session the audit log does not know about.
+	err = recordAudit(ctx, uu.auditLog, &domain.AuditEntry{
+		Action: domain.AuditUserLogin, TargetType: domain.AuditTargetUser, TargetID: user.ID, IP: clientIP,
+	}, user, nil, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return token, nil
+	// Issue an access token and start a refresh token family
+	tokens, err := uu.tokenUsecases.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{Tokens: tokens}, nil
+}
+
+// recordFailedLogin looks up the account a refused login was for, so that
+// the audit entry names it as the target.
+func (uu *userUsecases) recordFailedLogin(ctx context.Context, email, clientIP string, err error) {
+	if uu.auditLog == nil || !isRefusedLogin(err) {
+		return
+	}
+	userID := ""
+	if user, _ := uu.userRepository.GetUserByEmail(ctx, email); user != nil {
+		userID = user.ID
+	}
+	recordFailedLogin(ctx, uu.auditLog, email, userID, clientIP, err)
 }
 
 
@@ -101,18 +159,71 @@ func (uu *userUsecases) CreateUser(ctx context.Context, user *domain.User) (*dom
 		return nil, err
 	}
 	if !exists {
-		user.Role = "admin"
+		user.Role = domain.RoleAdmin
 	} else {
-		user.Role = "user"
+		user.Role = domain.RoleUser
 	}
-	return uu.userRepository.CreateUser(ctx, user)
+	// Everyone, the first admin included, has to prove the address is
+	// theirs; the caller mails the verification link.
+	user.EmailUnverified = true
+	var created *domain.User
+	err = inTransaction(ctx, uu.transactor, func(ctx context.Context) error {
+		var err error
+		if created, err = uu.userRepository.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		if err := publishEvent(ctx, uu.events, domain.EventUserCreated, nil, created); err != nil {
+			return err
+		}
+		// Someone signing up is their own actor.
+		actor, _ := ctx.Value("user").(*domain.User)
+		if actor == nil {
+			actor = created
+		}
+		return recordAudit(ctx, uu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditUserCreate, TargetType: domain.AuditTargetUser, TargetID: created.ID,
+		}, actor, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
 func (uu *userUsecases) PromoteUserToAdmin(ctx context.Context, id string) error {
 	ctx, cancel := context.WithTimeout(ctx, uu.contextTimeout)
 	defer cancel()
 
-	return uu.userRepository.PromoteUserToAdmin(ctx, id)
+	err := inTransaction(ctx, uu.transactor, func(ctx context.Context) error {
+		// The user as they were is only needed for the audit log.
+		var before *domain.User
+		if uu.auditLog != nil {
+			var err error
+			if before, err = uu.userRepository.GetUserByID(ctx, id); err != nil {
+				return err
+			}
+		}
+		if err := uu.userRepository.PromoteUserToAdmin(ctx, id); err != nil {
+			return err
+		}
+		promoted, err := uu.userRepository.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := publishEvent(ctx, uu.events, domain.EventUserPromoted, nil, promoted); err != nil {
+			return err
+		}
+		return recordAudit(ctx, uu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditUserPromote, TargetType: domain.AuditTargetUser, TargetID: id,
+		}, nil, before, promoted)
+	})
+	if err != nil {
+		return err
+	}
+	// The promotion bumped the user's token version; stop trusting the
+	// cached one so tokens carrying the old role are refused right away.
+	uu.tokenUsecases.ForgetTokenVersion(id)
+	return nil
 }
 
 func (uu *userUsecases) GetCurrentUser(ctx context.Context) (*domain.User, error) {
@@ -122,3 +233,148 @@ func (uu *userUsecases) GetCurrentUser(ctx context.Context) (*domain.User, error
 	}
 	return user, nil
 }
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+func (uu *userUsecases) ListUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, uu.contextTimeout)
+	defer cancel()
+
+	if query.Limit < 0 || query.Limit > maxUserPageSize {
+		return nil, domain.ErrInvalidUserQuery
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultUserPageSize
+	}
+	return uu.userRepository.GetAllUsers(ctx, query)
+}
+
+func (uu *userUsecases) DemoteUser(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, uu.contextTimeout)
+	defer cancel()
+
+	user, err := uu.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.Role != domain.RoleAdmin {
+		return domain.ErrNotAdmin
+	}
+	err = inTransaction(ctx, uu.transactor, func(ctx context.Context) error {
+		if err := uu.userRepository.UpdateUserRole(ctx, id, domain.RoleUser); err != nil {
+			return err
+		}
+		demoted := *user
+		demoted.Role = domain.RoleUser
+		demoted.TokenVersion++
+		return recordAudit(ctx, uu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditUserDemote, TargetType: domain.AuditTargetUser, TargetID: id,
+		}, nil, user, &demoted)
+	})
+	if err != nil {
+		return err
+	}
+	uu.tokenUsecases.ForgetTokenVersion(id)
+	return nil
+}
+
+// SetUserDisabled disables or re-enables an account. Disabling bumps the
+// user's token version, so their existing tokens stop working at once.
+func (uu *userUsecases) SetUserDisabled(ctx context.Context, id string, disabled bool) error {
+	ctx, cancel := context.WithTimeout(ctx, uu.contextTimeout)
+	defer cancel()
+
+	user, err := uu.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	action := domain.AuditUserEnable
+	if disabled {
+		action = domain.AuditUserDisable
+	}
+	err = inTransaction(ctx, uu.transactor, func(ctx context.Context) error {
+		if err := uu.userRepository.SetUserDisabled(ctx, id, disabled); err != nil {
+			return err
+		}
+		changed := *user
+		changed.Disabled = disabled
+		changed.TokenVersion++
+		return recordAudit(ctx, uu.auditLog, &domain.AuditEntry{
+			Action: action, TargetType: domain.AuditTargetUser, TargetID: id,
+		}, nil, user, &changed)
+	})
+	if err != nil {
+		return err
+	}
+	uu.tokenUsecases.ForgetTokenVersion(id)
+	return nil
+}
+
+// DeleteUser removes the account first, so the user can no longer act, and
+// then their tasks, in one transaction. Tokens of a deleted user are
+// refused because the user can no longer be found.
+func (uu *userUsecases) DeleteUser(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, uu.contextTimeout)
+	defer cancel()
+
+	user, err := uu.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = inTransaction(ctx, uu.transactor, func(ctx context.Context) error {
+		if err := uu.userRepository.DeleteUser(ctx, id); err != nil {
+			return err
+		}
+		tasks, err := uu.taskRepository.GetAllTasks(ctx, domain.TaskQuery{UserID: id})
+		if err != nil {
+			return err
+		}
+		taskIds := make([]string, len(tasks.Tasks))
+		for i, task := range tasks.Tasks {
+			taskIds[i] = task.ID
+		}
+		if err := uu.commentRepository.DeleteCommentsByTasks(ctx, taskIds); err != nil {
+			return err
+		}
+		if err := uu.dependencyRepository.DeleteDependenciesByUser(ctx, id); err != nil {
+			return err
+		}
+		if err := uu.taskRepository.DeleteTasksByUser(ctx, id); err != nil {
+			return err
+		}
+		// Subscribers learn that the user's tasks went with them from this
+		// one event; no task.deleted is published for each.
+		if err := publishEvent(ctx, uu.events, domain.EventUserDeleted, nil, user); err != nil {
+			return err
+		}
+		return recordAudit(ctx, uu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditUserDelete, TargetType: domain.AuditTargetUser, TargetID: id,
+		}, nil, user, nil)
+	})
+	if err != nil {
+		return err
+	}
+	uu.tokenUsecases.ForgetTokenVersion(id)
+	return nil
+}
+
+func (uu *userUsecases) UnlockUser(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, uu.contextTimeout)
+	defer cancel()
+
+	user, err := uu.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return inTransaction(ctx, uu.transactor, func(ctx context.Context) error {
+		if err := uu.throttle.reset(ctx, user.Email); err != nil {
+			return err
+		}
+		return recordAudit(ctx, uu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditUserUnlock, TargetType: domain.AuditTargetUser, TargetID: id,
+		}, nil, nil, nil)
+	})
+}
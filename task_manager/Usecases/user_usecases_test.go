@@ -3,10 +3,13 @@ package usecases_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	domain "task_manager/Domain"
+	repository "task_manager/Repository"
 	"task_manager/mocks"
 	userUsecases "task_manager/Usecases"
 
@@ -17,20 +20,40 @@ import (
 
 type UserUsecaseSuite struct {
 	suite.Suite
-	repo     *mocks.UserRepository
-	ps       *mocks.IPasswordService
-	jwt      *mocks.IJWTService
-	uc       domain.UserUsecases
-	timeout  time.Duration
+	repo         *mocks.UserRepository
+	taskRepo     *mocks.TaskRepository
+	comments     domain.CommentRepository
+	dependencies domain.DependencyRepository
+	ps           *mocks.IPasswordService
+	tokens       *mocks.TokenUsecases
+	twoFactor    *mocks.TwoFactorUsecases
+	attempts     domain.LoginAttemptRepository
+	events       *mocks.EventPublisher
+	uc           domain.UserUsecases
+	timeout      time.Duration
 }
 
 func (s *UserUsecaseSuite) SetupTest() {
 	s.repo = new(mocks.UserRepository)
+	s.taskRepo = new(mocks.TaskRepository)
+	s.comments = repository.NewInMemoryCommentRepository()
+	s.dependencies = repository.NewInMemoryDependencyRepository()
 	s.ps = new(mocks.IPasswordService)
-	s.jwt = new(mocks.IJWTService)
-	s.uc = userUsecases.NewUserUsecases(s.repo, s.ps, s.jwt, s.timeout)
+	s.tokens = new(mocks.TokenUsecases)
+	s.twoFactor = new(mocks.TwoFactorUsecases)
+	s.attempts = repository.NewInMemoryLoginAttemptRepository()
+	s.events = new(mocks.EventPublisher)
+	s.events.On("Publish", mock.Anything, mock.Anything).Return(nil).Maybe()
+	s.uc = userUsecases.NewUserUsecases(s.repo, s.taskRepo, s.comments, s.dependencies, s.ps, s.tokens, s.twoFactor, s.attempts, testAccountLockout, testIPLockout, nil, s.events, nil, s.timeout)
 }
 
+// Small thresholds keep the lockout tests short; the delays are long
+// enough that a lock never lapses mid-test.
+var (
+	testAccountLockout = domain.LockoutPolicy{Threshold: 3, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: 2 * time.Hour}
+	testIPLockout      = domain.LockoutPolicy{Threshold: 5, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: 2 * time.Hour}
+)
+
 func TestUserUsecaseSuite(t *testing.T) {
 	suite.Run(t, new(UserUsecaseSuite))
 }
@@ -44,15 +67,17 @@ func (s *UserUsecaseSuite) TestLogin_Success() {
 
 	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil).Once()
 	s.ps.On("VerifyPassword", user, "secret").Return(true).Once()
-	s.jwt.On("GenerateToken", user).Return("jwt-token", nil).Once()
+	pair := &domain.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"}
+	s.twoFactor.On("BeginLogin", mock.Anything, user).Return(nil, nil)
+	s.tokens.On("IssueTokens", mock.Anything, user).Return(pair, nil).Once()
 
-	token, err := s.uc.Login(ctx, "john@example.com", "secret")
+	result, err := s.uc.Login(ctx, "john@example.com", "secret", "192.0.2.1")
 	assert.NoError(err)
-	assert.Equal("jwt-token", token)
+	assert.Equal(&domain.LoginResult{Tokens: pair}, result)
 
 	s.repo.AssertExpectations(s.T())
 	s.ps.AssertExpectations(s.T())
-	s.jwt.AssertExpectations(s.T())
+	s.tokens.AssertExpectations(s.T())
 }
 
 func (s *UserUsecaseSuite) TestLogin_RepoError() {
@@ -61,9 +86,22 @@ func (s *UserUsecaseSuite) TestLogin_RepoError() {
 
 	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(nil, errors.New("db error")).Once()
 
-	token, err := s.uc.Login(ctx, "john@example.com", "secret")
+	result, err := s.uc.Login(ctx, "john@example.com", "secret", "192.0.2.1")
 	assert.Error(err)
-	assert.Empty(token)
+	assert.Nil(result)
+
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseSuite) TestLogin_UnknownEmail() {
+	assert := assert.New(s.T())
+	ctx := context.Background()
+
+	s.repo.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(nil, domain.ErrUserNotFound).Once()
+
+	result, err := s.uc.Login(ctx, "nobody@example.com", "secret", "192.0.2.1")
+	assert.ErrorIs(err, domain.ErrInvalidCredentials)
+	assert.Nil(result)
 
 	s.repo.AssertExpectations(s.T())
 }
@@ -76,30 +114,186 @@ func (s *UserUsecaseSuite) TestLogin_WrongPassword() {
 	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil).Once()
 	s.ps.On("VerifyPassword", user, "wrong").Return(false).Once()
 
-	token, err := s.uc.Login(ctx, "john@example.com", "wrong")
-	assert.NoError(err) // because code returns the existing err (nil)
-	assert.Empty(token)
+	result, err := s.uc.Login(ctx, "john@example.com", "wrong", "192.0.2.1")
+	assert.ErrorIs(err, domain.ErrInvalidCredentials)
+	assert.Nil(result)
 
 	s.repo.AssertExpectations(s.T())
 	s.ps.AssertExpectations(s.T())
 }
 
-func (s *UserUsecaseSuite) TestLogin_JWTGenerationFails() {
+func (s *UserUsecaseSuite) TestLogin_DisabledAccount() {
+	user := &domain.User{ID: "u1", Email: "john@example.com", Password: "hashed", Disabled: true}
+	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil).Once()
+	s.ps.On("VerifyPassword", user, "secret").Return(true).Once()
+
+	result, err := s.uc.Login(context.Background(), "john@example.com", "secret", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrAccountDisabled)
+	assert.Nil(s.T(), result)
+	s.tokens.AssertNotCalled(s.T(), "IssueTokens", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestLogin_TokenIssueFails() {
 	assert := assert.New(s.T())
 	ctx := context.Background()
 
 	user := &domain.User{ID: "u1", Email: "john@example.com", Password: "hashed"}
 	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil).Once()
 	s.ps.On("VerifyPassword", user, "secret").Return(true).Once()
-	s.jwt.On("GenerateToken", user).Return("", errors.New("jwt error")).Once()
+	s.twoFactor.On("BeginLogin", mock.Anything, user).Return(nil, nil)
+	s.tokens.On("IssueTokens", mock.Anything, user).Return(nil, errors.New("jwt error")).Once()
 
-	token, err := s.uc.Login(ctx, "john@example.com", "secret")
+	result, err := s.uc.Login(ctx, "john@example.com", "secret", "192.0.2.1")
 	assert.Error(err)
-	assert.Empty(token)
+	assert.Nil(result)
 
 	s.repo.AssertExpectations(s.T())
 	s.ps.AssertExpectations(s.T())
-	s.jwt.AssertExpectations(s.T())
+	s.tokens.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseSuite) TestLogin_TwoFactorChallenge() {
+	ctx := context.Background()
+	user := &domain.User{ID: "u1", Email: "john@example.com", Password: "hashed"}
+	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	s.ps.On("VerifyPassword", user, "wrong").Return(false)
+	s.ps.On("VerifyPassword", user, "secret").Return(true)
+	challenge := &domain.TwoFactorChallenge{Token: "challenge", ExpiresAt: time.Now().Add(time.Minute)}
+	s.twoFactor.On("BeginLogin", mock.Anything, user).Return(challenge, nil).Once()
+
+	_, err := s.uc.Login(ctx, "john@example.com", "wrong", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidCredentials)
+	result, err := s.uc.Login(ctx, "john@example.com", "secret", "192.0.2.1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &domain.LoginResult{Challenge: challenge}, result)
+	s.tokens.AssertNotCalled(s.T(), "IssueTokens", mock.Anything, mock.Anything)
+
+	// The password alone does not clear earlier failures.
+	attempts, err := s.attempts.GetLoginAttempts(ctx, "account:john@example.com")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, attempts.Failures)
+}
+
+func (s *UserUsecaseSuite) TestLogin_LocksAccountAfterRepeatedFailures() {
+	ctx := context.Background()
+	user := &domain.User{ID: "u1", Email: "john@example.com", Password: "hashed"}
+	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	s.ps.On("VerifyPassword", user, "wrong").Return(false)
+
+	// Each failure comes from a different address, so only the account
+	// counter reaches its threshold.
+	for i := 0; i < testAccountLockout.Threshold; i++ {
+		_, err := s.uc.Login(ctx, "john@example.com", "wrong", fmt.Sprintf("192.0.2.%d", i+1))
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidCredentials)
+	}
+
+	// Even the right password is refused while the account is locked.
+	_, err := s.uc.Login(ctx, "John@Example.com", "secret", "198.51.100.1")
+	var lockout *domain.LockoutError
+	assert.ErrorAs(s.T(), err, &lockout)
+	assert.ErrorIs(s.T(), err, domain.ErrAccountLocked)
+	assert.InDelta(s.T(), testAccountLockout.BaseDelay.Seconds(), lockout.RetryAfter.Seconds(), 5)
+	s.ps.AssertNotCalled(s.T(), "VerifyPassword", user, "secret")
+	s.tokens.AssertNotCalled(s.T(), "IssueTokens", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestLogin_ConcurrentGuessesStopAtThreshold() {
+	ctx := context.Background()
+	user := &domain.User{ID: "u1", Email: "john@example.com", Password: "hashed"}
+	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	s.ps.On("VerifyPassword", user, "wrong").Return(false)
+
+	const guesses = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, guesses)
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.uc.Login(ctx, "john@example.com", "wrong", fmt.Sprintf("192.0.2.%d", i+1))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Only the free attempts get as far as the password.
+	checked := 0
+	for err := range errs {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			checked++
+		} else {
+			assert.ErrorIs(s.T(), err, domain.ErrAccountLocked)
+		}
+	}
+	assert.Equal(s.T(), testAccountLockout.Threshold, checked)
+	s.ps.AssertNumberOfCalls(s.T(), "VerifyPassword", testAccountLockout.Threshold)
+}
+
+func (s *UserUsecaseSuite) TestLogin_ThrottlesIPAcrossAccounts() {
+	ctx := context.Background()
+	s.repo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(nil, domain.ErrUserNotFound)
+
+	for i := 0; i < testIPLockout.Threshold; i++ {
+		_, err := s.uc.Login(ctx, fmt.Sprintf("user%d@example.com", i), "guess", "192.0.2.1")
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidCredentials)
+	}
+
+	_, err := s.uc.Login(ctx, "someone@example.com", "guess", "192.0.2.1")
+	assert.ErrorIs(s.T(), err, domain.ErrTooManyLoginAttempts)
+
+	// Other addresses are unaffected.
+	_, err = s.uc.Login(ctx, "someone@example.com", "guess", "192.0.2.2")
+	assert.ErrorIs(s.T(), err, domain.ErrInvalidCredentials)
+}
+
+func (s *UserUsecaseSuite) TestLogin_SuccessResetsAccountCounter() {
+	ctx := context.Background()
+	user := &domain.User{ID: "u1", Email: "john@example.com", Password: "hashed"}
+	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	s.ps.On("VerifyPassword", user, "wrong").Return(false)
+	s.ps.On("VerifyPassword", user, "secret").Return(true)
+	s.twoFactor.On("BeginLogin", mock.Anything, user).Return(nil, nil)
+	s.tokens.On("IssueTokens", mock.Anything, user).Return(&domain.TokenPair{}, nil)
+
+	for i := 0; i < testAccountLockout.Threshold-1; i++ {
+		s.uc.Login(ctx, "john@example.com", "wrong", "192.0.2.1")
+	}
+	_, err := s.uc.Login(ctx, "john@example.com", "secret", "192.0.2.1")
+	assert.NoError(s.T(), err)
+
+	attempts, err := s.attempts.GetLoginAttempts(ctx, "account:john@example.com")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), attempts)
+}
+
+func (s *UserUsecaseSuite) TestUnlockUser() {
+	ctx := context.Background()
+	user := &domain.User{ID: "u1", Email: "john@example.com", Password: "hashed"}
+	s.repo.On("GetUserByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(user, nil).Once()
+	s.ps.On("VerifyPassword", user, "wrong").Return(false)
+	s.ps.On("VerifyPassword", user, "secret").Return(true)
+	pair := &domain.TokenPair{AccessToken: "jwt-token"}
+	s.twoFactor.On("BeginLogin", mock.Anything, user).Return(nil, nil)
+	s.tokens.On("IssueTokens", mock.Anything, user).Return(pair, nil)
+
+	for i := 0; i < testAccountLockout.Threshold; i++ {
+		s.uc.Login(ctx, "john@example.com", "wrong", fmt.Sprintf("192.0.2.%d", i+1))
+	}
+	_, err := s.uc.Login(ctx, "john@example.com", "secret", "198.51.100.1")
+	assert.ErrorIs(s.T(), err, domain.ErrAccountLocked)
+
+	assert.NoError(s.T(), s.uc.UnlockUser(ctx, "u1"))
+	result, err := s.uc.Login(ctx, "john@example.com", "secret", "198.51.100.1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), pair, result.Tokens)
+}
+
+func (s *UserUsecaseSuite) TestUnlockUser_NotFound() {
+	s.repo.On("GetUserByID", mock.Anything, "missing").Return(nil, domain.ErrUserNotFound).Once()
+
+	assert.ErrorIs(s.T(), s.uc.UnlockUser(context.Background(), "missing"), domain.ErrUserNotFound)
 }
 
 // CreateUser
@@ -127,11 +321,15 @@ func (s *UserUsecaseSuite) TestCreateUser_FirstUserGetsAdminRole() {
 	created, err := s.uc.CreateUser(ctx, in)
 	assert.NoError(err)
 	assert.Equal("admin", created.Role)
+	assert.True(created.EmailUnverified, "the first admin verifies their email like everyone else")
 	assert.Equal("hashed", created.Password)
 	assert.NotEmpty(created.ID)
 
 	s.repo.AssertExpectations(s.T())
 	s.ps.AssertExpectations(s.T())
+	s.events.AssertCalled(s.T(), "Publish", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventUserCreated && e.User.ID == created.ID
+	}))
 }
 
 func (s *UserUsecaseSuite) TestCreateUser_NextUsersGetUserRole() {
@@ -151,6 +349,7 @@ func (s *UserUsecaseSuite) TestCreateUser_NextUsersGetUserRole() {
 	created, err := s.uc.CreateUser(ctx, in)
 	assert.NoError(err)
 	assert.Equal("user", created.Role)
+	assert.True(created.EmailUnverified)
 
 	s.repo.AssertExpectations(s.T())
 	s.ps.AssertExpectations(s.T())
@@ -292,10 +491,133 @@ func (s *UserUsecaseSuite) TestPromoteUserToAdmin_Success() {
 	ctx := context.Background()
 
 	s.repo.On("PromoteUserToAdmin", mock.Anything, "u1").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleAdmin}, nil).Once()
 
 	err := s.uc.PromoteUserToAdmin(ctx, "u1")
 	assert.NoError(err)
 	s.repo.AssertExpectations(s.T())
+	s.tokens.AssertExpectations(s.T())
+	s.events.AssertCalled(s.T(), "Publish", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventUserPromoted && e.User.ID == "u1"
+	}))
+}
+
+func (s *UserUsecaseSuite) TestPromoteUserToAdmin_NotFound() {
+	s.repo.On("PromoteUserToAdmin", mock.Anything, "missing").Return(domain.ErrUserNotFound).Once()
+
+	err := s.uc.PromoteUserToAdmin(context.Background(), "missing")
+	assert.ErrorIs(s.T(), err, domain.ErrUserNotFound)
+	s.tokens.AssertNotCalled(s.T(), "ForgetTokenVersion", mock.Anything)
+	s.events.AssertNotCalled(s.T(), "Publish", mock.Anything, mock.Anything)
+}
+
+// User management
+func (s *UserUsecaseSuite) TestListUsers_DefaultLimit() {
+	page := &domain.UserPage{Users: []*domain.User{{ID: "u1"}}}
+	s.repo.On("GetAllUsers", mock.Anything, domain.UserQuery{Search: "jo", Limit: 20}).Return(page, nil).Once()
+
+	got, err := s.uc.ListUsers(context.Background(), domain.UserQuery{Search: "jo"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), page, got)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseSuite) TestListUsers_InvalidLimit() {
+	for _, limit := range []int{-1, 101} {
+		_, err := s.uc.ListUsers(context.Background(), domain.UserQuery{Limit: limit})
+		assert.ErrorIs(s.T(), err, domain.ErrInvalidUserQuery)
+	}
+	s.repo.AssertNotCalled(s.T(), "GetAllUsers", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestDemoteUser_Success() {
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleAdmin}, nil).Once()
+	s.repo.On("UpdateUserRole", mock.Anything, "u1", domain.RoleUser).Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+
+	assert.NoError(s.T(), s.uc.DemoteUser(context.Background(), "u1"))
+	s.repo.AssertExpectations(s.T())
+	s.tokens.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseSuite) TestDemoteUser_LastAdmin() {
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleAdmin}, nil).Once()
+	s.repo.On("UpdateUserRole", mock.Anything, "u1", domain.RoleUser).Return(domain.ErrLastAdmin).Once()
+
+	assert.ErrorIs(s.T(), s.uc.DemoteUser(context.Background(), "u1"), domain.ErrLastAdmin)
+	s.tokens.AssertNotCalled(s.T(), "ForgetTokenVersion", mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestDemoteUser_NotAdmin() {
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleUser}, nil).Once()
+
+	assert.ErrorIs(s.T(), s.uc.DemoteUser(context.Background(), "u1"), domain.ErrNotAdmin)
+}
+
+func (s *UserUsecaseSuite) TestSetUserDisabled_LastAdmin() {
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleAdmin}, nil).Once()
+	s.repo.On("SetUserDisabled", mock.Anything, "u1", true).Return(domain.ErrLastAdmin).Once()
+
+	assert.ErrorIs(s.T(), s.uc.SetUserDisabled(context.Background(), "u1", true), domain.ErrLastAdmin)
+	s.tokens.AssertNotCalled(s.T(), "ForgetTokenVersion", mock.Anything)
+}
+
+func (s *UserUsecaseSuite) TestSetUserDisabled_Enable() {
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleAdmin, Disabled: true}, nil).Once()
+	s.repo.On("SetUserDisabled", mock.Anything, "u1", false).Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+
+	assert.NoError(s.T(), s.uc.SetUserDisabled(context.Background(), "u1", false))
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseSuite) TestDeleteUser_RemovesTasks() {
+	ctx := context.Background()
+	for _, comment := range []*domain.Comment{
+		{ID: "c1", TaskID: "t1", AuthorID: "u2", Body: "on u1's task", CreatedAt: time.Now()},
+		{ID: "c2", TaskID: "t2", AuthorID: "u1", Body: "on u2's task", CreatedAt: time.Now()},
+	} {
+		s.Require().NoError(s.comments.CreateComment(ctx, comment))
+	}
+	for _, dependency := range []*domain.TaskDependency{
+		{TaskID: "t1", BlockerID: "t3", UserID: "u1", CreatedAt: time.Now()},
+		{TaskID: "t2", BlockerID: "t4", UserID: "u2", CreatedAt: time.Now()},
+	} {
+		s.Require().NoError(s.dependencies.AddDependency(ctx, dependency))
+	}
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleUser}, nil).Once()
+	s.repo.On("DeleteUser", mock.Anything, "u1").Return(nil).Once()
+	s.tokens.On("ForgetTokenVersion", "u1").Return().Once()
+	s.taskRepo.On("GetAllTasks", mock.Anything, domain.TaskQuery{UserID: "u1"}).
+		Return(&domain.TaskPage{Tasks: []*domain.Task{{ID: "t1", UserID: "u1"}}}, nil).Once()
+	s.taskRepo.On("DeleteTasksByUser", mock.Anything, "u1").Return(nil).Once()
+
+	assert.NoError(s.T(), s.uc.DeleteUser(ctx, "u1"))
+	s.repo.AssertExpectations(s.T())
+	s.taskRepo.AssertExpectations(s.T())
+	_, err := s.comments.GetCommentByID(ctx, "c1")
+	assert.ErrorIs(s.T(), err, domain.ErrCommentNotFound)
+	_, err = s.comments.GetCommentByID(ctx, "c2")
+	assert.NoError(s.T(), err)
+	dependencies, err := s.dependencies.GetDependenciesByUser(ctx, "u1")
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), dependencies)
+	dependencies, err = s.dependencies.GetDependenciesByUser(ctx, "u2")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), dependencies, 1)
+	s.events.AssertCalled(s.T(), "Publish", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventUserDeleted && e.User.ID == "u1"
+	}))
+}
+
+func (s *UserUsecaseSuite) TestDeleteUser_LastAdmin() {
+	s.repo.On("GetUserByID", mock.Anything, "u1").Return(&domain.User{ID: "u1", Role: domain.RoleAdmin}, nil).Once()
+	s.repo.On("DeleteUser", mock.Anything, "u1").Return(domain.ErrLastAdmin).Once()
+
+	assert.ErrorIs(s.T(), s.uc.DeleteUser(context.Background(), "u1"), domain.ErrLastAdmin)
+	s.taskRepo.AssertNotCalled(s.T(), "DeleteTasksByUser", mock.Anything, mock.Anything)
+	s.events.AssertNotCalled(s.T(), "Publish", mock.Anything, mock.Anything)
 }
 
 // GetCurrentUser
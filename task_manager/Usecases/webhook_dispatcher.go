@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type webhookDispatcher struct {
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+	sender             domain.WebhookSender
+	config             domain.WebhookDispatcherConfig
+	// owner identifies this dispatcher in the leases it takes.
+	owner          string
+	contextTimeout time.Duration
+}
+
+func NewWebhookDispatcher(webhookRepository domain.WebhookRepository, deliveryRepository domain.WebhookDeliveryRepository, sender domain.WebhookSender, config domain.WebhookDispatcherConfig, contextTimeout time.Duration) domain.WebhookDispatcher {
+	return &webhookDispatcher{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		sender:             sender,
+		config:             config,
+		owner:              uuid.New().String(),
+		contextTimeout:     contextTimeout,
+	}
+}
+
+func (wd *webhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(wd.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := wd.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Webhook dispatcher: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending stops claiming once ctx is cancelled. Requests already
+// under way are finished, and deliveries claimed but not yet started are
+// released for the next dispatcher.
+func (wd *webhookDispatcher) DispatchPending(ctx context.Context) error {
+	for ctx.Err() == nil {
+		now := time.Now()
+		claimCtx, cancel := context.WithTimeout(ctx, wd.contextTimeout)
+		claimed, err := wd.deliveryRepository.ClaimWebhookDeliveries(claimCtx, wd.owner, now, now.Add(wd.config.Lease), wd.config.BatchSize)
+		cancel()
+		// A failed claim may still have leased some deliveries; they are
+		// sent, or released, rather than left until their lease runs out.
+		wd.deliverAll(ctx, claimed)
+		if err != nil {
+			return err
+		}
+		if len(claimed) < wd.config.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// deliverAll sends the claimed deliveries on up to Workers goroutines and
+// waits for them.
+func (wd *webhookDispatcher) deliverAll(ctx context.Context, claimed []*domain.WebhookDelivery) {
+	jobs := make(chan *domain.WebhookDelivery)
+	var wg sync.WaitGroup
+	for range min(max(wd.config.Workers, 1), len(claimed)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range jobs {
+				wd.deliver(ctx, delivery)
+			}
+		}()
+	}
+	for _, delivery := range claimed {
+		jobs <- delivery
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// deliver sends one claimed delivery and records the outcome. As with
+// reminders, the request and the record are not cut short by ctx, and a
+// delivery whose request could outlast its lease is handed back unsent.
+func (wd *webhookDispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	sendTimeout := wd.config.Lease / 2
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	defer cancel()
+
+	if ctx.Err() != nil || time.Now().Add(sendTimeout).After(delivery.LeaseUntil) {
+		// Not started: hand it back without counting the attempt.
+		delivery.Attempts--
+		delivery.LeaseUntil = time.Time{}
+	} else if webhook, err := wd.webhookRepository.GetWebhookByID(sendCtx, delivery.WebhookID); errors.Is(err, domain.ErrWebhookNotFound) || err == nil && !webhook.Active {
+		delivery.Status = domain.WebhookDead
+		delivery.LastError = "webhook deleted or deactivated"
+	} else if err != nil {
+		wd.retryLater(delivery, err)
+	} else if status, err := wd.sender.Send(sendCtx, webhook, delivery); err != nil {
+		delivery.ResponseStatus = 0
+		wd.retryLater(delivery, err)
+	} else if delivery.ResponseStatus = status; status < 200 || status > 299 {
+		wd.retryLater(delivery, fmt.Errorf("unexpected response status %d", status))
+	} else {
+		deliveredAt := time.Now()
+		delivery.Status = domain.WebhookDelivered
+		delivery.DeliveredAt = &deliveredAt
+		delivery.LastError = ""
+	}
+
+	if err := wd.deliveryRepository.FinishWebhookDelivery(sendCtx, delivery, wd.owner); err != nil {
+		log.Printf("Webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// retryLater leaves a delivery pending until its backoff has passed, or
+// moves it to the dead letters once it is out of attempts.
+func (wd *webhookDispatcher) retryLater(delivery *domain.WebhookDelivery, err error) {
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= wd.config.MaxAttempts {
+		delivery.Status = domain.WebhookDead
+		return
+	}
+	backoff := wd.config.RetryBackoff << (delivery.Attempts - 1)
+	delivery.LeaseUntil = time.Now().Add(backoff)
+}
@@ -0,0 +1,271 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	domain "task_manager/Domain"
+	repository "task_manager/Repository"
+	taskUsecases "task_manager/Usecases"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingSender records the events it sends, answering the first
+// failures calls with status (or an error when status is 0). Each call
+// takes delay.
+type recordingSender struct {
+	mu       sync.Mutex
+	delay    time.Duration
+	failures int
+	status   int
+	sent     []string
+}
+
+func (rs *recordingSender) Send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error) {
+	time.Sleep(rs.delay)
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.failures > 0 {
+		rs.failures--
+		if rs.status == 0 {
+			return 0, errors.New("connection refused")
+		}
+		return rs.status, nil
+	}
+	rs.sent = append(rs.sent, delivery.EventID)
+	return 204, nil
+}
+
+func (rs *recordingSender) Sent() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]string(nil), rs.sent...)
+}
+
+type WebhookDispatcherSuite struct {
+	suite.Suite
+	webhooks   domain.WebhookRepository
+	deliveries domain.WebhookDeliveryRepository
+	sender     *recordingSender
+	config     domain.WebhookDispatcherConfig
+	webhook    *domain.Webhook
+	ctx        context.Context
+}
+
+func (s *WebhookDispatcherSuite) SetupTest() {
+	s.webhooks = repository.NewInMemoryWebhookRepository()
+	s.deliveries = repository.NewInMemoryWebhookDeliveryRepository()
+	s.sender = &recordingSender{}
+	s.config = domain.WebhookDispatcherConfig{
+		PollInterval: time.Second,
+		Lease:        time.Minute,
+		MaxAttempts:  2,
+		RetryBackoff: 20 * time.Millisecond,
+		Workers:      2,
+		BatchSize:    2,
+	}
+	s.ctx = context.Background()
+	s.webhook = &domain.Webhook{ID: "hook", URL: "https://hooks.example.com", Events: []string{domain.WebhookAllEvents}, Secret: "sixteen-chars-ok", Active: true}
+	require.NoError(s.T(), s.webhooks.CreateWebhook(s.ctx, s.webhook))
+}
+
+func TestWebhookDispatcherSuite(t *testing.T) {
+	suite.Run(t, new(WebhookDispatcherSuite))
+}
+
+func (s *WebhookDispatcherSuite) dispatcher() domain.WebhookDispatcher {
+	return taskUsecases.NewWebhookDispatcher(s.webhooks, s.deliveries, s.sender, s.config, 2*time.Second)
+}
+
+func (s *WebhookDispatcherSuite) queue(eventId string) *domain.WebhookDelivery {
+	delivery := &domain.WebhookDelivery{
+		ID:        uuid.New().String(),
+		WebhookID: s.webhook.ID,
+		EventID:   eventId,
+		EventType: domain.EventTaskCreated,
+		Payload:   `{}`,
+		Status:    domain.WebhookPending,
+		CreatedAt: time.Now(),
+	}
+	require.NoError(s.T(), s.deliveries.CreateWebhookDelivery(s.ctx, delivery))
+	return delivery
+}
+
+func (s *WebhookDispatcherSuite) stored(id string) *domain.WebhookDelivery {
+	delivery, err := s.deliveries.GetWebhookDeliveryByID(s.ctx, id)
+	require.NoError(s.T(), err)
+	return delivery
+}
+
+func (s *WebhookDispatcherSuite) TestDispatchPending() {
+	assert := assert.New(s.T())
+	first := s.queue("a")
+	s.queue("b")
+	s.queue("c")
+
+	dispatcher := s.dispatcher()
+	require.NoError(s.T(), dispatcher.DispatchPending(s.ctx))
+	assert.ElementsMatch([]string{"a", "b", "c"}, s.sender.Sent())
+
+	delivered := s.stored(first.ID)
+	assert.Equal(domain.WebhookDelivered, delivered.Status)
+	assert.Equal(1, delivered.Attempts)
+	assert.Equal(204, delivered.ResponseStatus)
+	assert.NotNil(delivered.DeliveredAt)
+
+	// Nothing is sent twice.
+	require.NoError(s.T(), dispatcher.DispatchPending(s.ctx))
+	assert.Len(s.sender.Sent(), 3)
+}
+
+func (s *WebhookDispatcherSuite) TestDispatchPending_ConcurrentDispatchers() {
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		s.queue(id)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		dispatcher := s.dispatcher()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(s.T(), dispatcher.DispatchPending(s.ctx))
+		}()
+	}
+	wg.Wait()
+	assert.ElementsMatch(s.T(), []string{"a", "b", "c", "d", "e"}, s.sender.Sent())
+}
+
+// A batch that takes longer to send than its lease must not be sent again
+// by a dispatcher that claims what looks abandoned.
+func (s *WebhookDispatcherSuite) TestDispatchPending_SlowSendsKeepWithinLease() {
+	events := []string{"a", "b", "c", "d", "e"}
+	for _, event := range events {
+		s.queue(event)
+	}
+	s.sender.delay = 100 * time.Millisecond
+	s.config.Lease = 300 * time.Millisecond
+	s.config.Workers = 1
+	s.config.BatchSize = len(events)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(s.T(), s.dispatcher().DispatchPending(s.ctx))
+	}()
+	other := s.dispatcher()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		case <-time.After(10 * time.Millisecond):
+			assert.NoError(s.T(), other.DispatchPending(s.ctx))
+		}
+	}
+	require.NoError(s.T(), other.DispatchPending(s.ctx))
+	assert.ElementsMatch(s.T(), events, s.sender.Sent())
+}
+
+func (s *WebhookDispatcherSuite) TestDispatchPending_RetriesAfterBackoff() {
+	assert := assert.New(s.T())
+	s.sender.failures, s.sender.status = 1, 503
+	delivery := s.queue("flaky")
+	dispatcher := s.dispatcher()
+
+	require.NoError(s.T(), dispatcher.DispatchPending(s.ctx))
+	assert.Empty(s.sender.Sent())
+	failed := s.stored(delivery.ID)
+	assert.Equal(domain.WebhookPending, failed.Status)
+	assert.Equal(503, failed.ResponseStatus)
+	assert.Contains(failed.LastError, "503")
+
+	// Not before the backoff has passed.
+	require.NoError(s.T(), dispatcher.DispatchPending(s.ctx))
+	assert.Empty(s.sender.Sent())
+	time.Sleep(s.config.RetryBackoff)
+	require.NoError(s.T(), dispatcher.DispatchPending(s.ctx))
+	assert.Equal([]string{"flaky"}, s.sender.Sent())
+	assert.Equal(domain.WebhookDelivered, s.stored(delivery.ID).Status)
+}
+
+func (s *WebhookDispatcherSuite) TestDispatchPending_DeadLetter() {
+	s.sender.failures = s.config.MaxAttempts
+	delivery := s.queue("down")
+	dispatcher := s.dispatcher()
+
+	for range s.config.MaxAttempts + 1 {
+		require.NoError(s.T(), dispatcher.DispatchPending(s.ctx))
+		time.Sleep(4 * s.config.RetryBackoff)
+	}
+	assert.Empty(s.T(), s.sender.Sent())
+	assert.Zero(s.T(), s.sender.failures)
+	dead := s.stored(delivery.ID)
+	assert.Equal(s.T(), domain.WebhookDead, dead.Status)
+	assert.Equal(s.T(), s.config.MaxAttempts, dead.Attempts)
+	assert.Equal(s.T(), "connection refused", dead.LastError)
+}
+
+func (s *WebhookDispatcherSuite) TestDispatchPending_InactiveWebhook() {
+	delivery := s.queue("late")
+	s.webhook.Active = false
+	require.NoError(s.T(), s.webhooks.UpdateWebhook(s.ctx, s.webhook))
+
+	require.NoError(s.T(), s.dispatcher().DispatchPending(s.ctx))
+	assert.Empty(s.T(), s.sender.Sent())
+	assert.Equal(s.T(), domain.WebhookDead, s.stored(delivery.ID).Status)
+}
+
+// failingWebhookClaims claims one delivery and then fails, as a store that
+// goes away partway through a batch would.
+type failingWebhookClaims struct {
+	domain.WebhookDeliveryRepository
+}
+
+func (fc failingWebhookClaims) ClaimWebhookDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	claimed, err := fc.WebhookDeliveryRepository.ClaimWebhookDeliveries(c, owner, now, leaseUntil, 1)
+	if err != nil {
+		return nil, err
+	}
+	return claimed, errors.New("connection reset")
+}
+
+func (s *WebhookDispatcherSuite) TestDispatchPending_SendsPartialClaims() {
+	first := s.queue("a")
+	s.queue("b")
+	deliveries := s.deliveries
+	s.deliveries = failingWebhookClaims{deliveries}
+
+	assert.Error(s.T(), s.dispatcher().DispatchPending(s.ctx))
+	assert.Equal(s.T(), []string{"a"}, s.sender.Sent())
+	assert.Equal(s.T(), domain.WebhookDelivered, s.stored(first.ID).Status)
+
+	s.deliveries = deliveries
+	require.NoError(s.T(), s.dispatcher().DispatchPending(s.ctx))
+	assert.Equal(s.T(), []string{"a", "b"}, s.sender.Sent())
+}
+
+func (s *WebhookDispatcherSuite) TestRun_StopsOnCancel() {
+	s.queue("now")
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	go func() {
+		s.dispatcher().Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(s.T(), func() bool { return len(s.sender.Sent()) == 1 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.T().Fatal("Run did not return after cancellation")
+	}
+}
@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+type webhookPublisher struct {
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+	contextTimeout     time.Duration
+}
+
+// NewWebhookPublisher publishes events by recording a pending delivery for
+// every webhook subscribed to them. The webhook dispatcher sends them.
+func NewWebhookPublisher(webhookRepository domain.WebhookRepository, deliveryRepository domain.WebhookDeliveryRepository, contextTimeout time.Duration) domain.EventPublisher {
+	return &webhookPublisher{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		contextTimeout:     contextTimeout,
+	}
+}
+
+// webhookPayload is the JSON body of a webhook request. Tasks appear as
+// they do in the API; users without their credentials.
+type webhookPayload struct {
+	ID         string             `json:"id"`
+	Type       string             `json:"type"`
+	OccurredAt time.Time          `json:"occurred_at"`
+	Data       webhookPayloadData `json:"data"`
+}
+
+type webhookPayloadData struct {
+	Task *domain.Task `json:"task,omitempty"`
+	User *webhookUser `json:"user,omitempty"`
+}
+
+type webhookUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	Disabled bool   `json:"disabled"`
+}
+
+func (wp *webhookPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, wp.contextTimeout)
+	defer cancel()
+
+	webhooks, err := wp.webhookRepository.GetWebhooksForEvent(ctx, event.Type)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+	payload := webhookPayload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt.UTC(),
+		Data:       webhookPayloadData{Task: event.Task},
+	}
+	if user := event.User; user != nil {
+		payload.Data.User = &webhookUser{ID: user.ID, Username: user.Username, Email: user.Email, Role: user.Role, Disabled: user.Disabled}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// Each delivery's ID is derived from the event and the webhook, so an
+	// event published again after a crash adds no second delivery.
+	now := time.Now()
+	for _, webhook := range webhooks {
+		if err := wp.deliveryRepository.CreateWebhookDelivery(ctx, &domain.WebhookDelivery{
+			ID:        uuid.NewSHA1(uuid.NameSpaceURL, []byte(event.ID+"/"+webhook.ID)).String(),
+			WebhookID: webhook.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Payload:   string(body),
+			Status:    domain.WebhookPending,
+			CreatedAt: now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
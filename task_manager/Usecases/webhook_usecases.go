@@ -0,0 +1,259 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	domain "task_manager/Domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultWebhookDeliveryPage = 20
+	maxWebhookDeliveryPage     = 100
+)
+
+type webhookUsecases struct {
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+	transactor         domain.Transactor
+	auditLog           domain.AuditRepository
+	contextTimeout     time.Duration
+}
+
+// NewWebhookUsecases builds the webhook administration. Each change is
+// recorded in auditLog, in the same transaction of transactor as the
+// change; either may be nil.
+func NewWebhookUsecases(webhookRepository domain.WebhookRepository, deliveryRepository domain.WebhookDeliveryRepository, transactor domain.Transactor, auditLog domain.AuditRepository, contextTimeout time.Duration) domain.WebhookUsecases {
+	return &webhookUsecases{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		transactor:         transactor,
+		auditLog:           auditLog,
+		contextTimeout:     contextTimeout,
+	}
+}
+
+func (wu *webhookUsecases) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, wu.contextTimeout)
+	defer cancel()
+
+	return wu.webhookRepository.GetWebhooks(ctx)
+}
+
+func (wu *webhookUsecases) GetWebhook(ctx context.Context, webhookId string) (*domain.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, wu.contextTimeout)
+	defer cancel()
+
+	return wu.webhookRepository.GetWebhookByID(ctx, webhookId)
+}
+
+func (wu *webhookUsecases) CreateWebhook(ctx context.Context, url string, events []string, secret string, actor *domain.Actor) (*domain.NewWebhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, wu.contextTimeout)
+	defer cancel()
+
+	if actor == nil || actor.User == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	if err := validateWebhookURL(url); err != nil {
+		return nil, err
+	}
+	events, err := validateWebhookEvents(events)
+	if err != nil {
+		return nil, err
+	}
+	if secret, err = webhookSecret(secret); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	webhook := &domain.Webhook{
+		ID:        uuid.New().String(),
+		URL:       url,
+		Events:    events,
+		Secret:    secret,
+		Active:    true,
+		CreatedBy: actor.User.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err = inTransaction(ctx, wu.transactor, func(ctx context.Context) error {
+		if err := wu.webhookRepository.CreateWebhook(ctx, webhook); err != nil {
+			return err
+		}
+		return recordAudit(ctx, wu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditWebhookCreate, TargetType: domain.AuditTargetWebhook, TargetID: webhook.ID,
+		}, actor.User, nil, webhook)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.NewWebhook{Webhook: webhook, Secret: secret}, nil
+}
+
+func (wu *webhookUsecases) UpdateWebhook(ctx context.Context, webhookId string, update domain.WebhookUpdate) (*domain.NewWebhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, wu.contextTimeout)
+	defer cancel()
+
+	existing, err := wu.webhookRepository.GetWebhookByID(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+	webhook := *existing
+	if update.URL != nil {
+		if err := validateWebhookURL(*update.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *update.URL
+	}
+	if update.Events != nil {
+		if webhook.Events, err = validateWebhookEvents(update.Events); err != nil {
+			return nil, err
+		}
+	}
+	var secret string
+	if update.Secret != nil {
+		if secret, err = webhookSecret(*update.Secret); err != nil {
+			return nil, err
+		}
+		webhook.Secret = secret
+	}
+	if update.Active != nil {
+		webhook.Active = *update.Active
+	}
+	webhook.UpdatedAt = time.Now()
+
+	err = inTransaction(ctx, wu.transactor, func(ctx context.Context) error {
+		if err := wu.webhookRepository.UpdateWebhook(ctx, &webhook); err != nil {
+			return err
+		}
+		return recordAudit(ctx, wu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditWebhookUpdate, TargetType: domain.AuditTargetWebhook, TargetID: webhook.ID,
+		}, nil, existing, &webhook)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.NewWebhook{Webhook: &webhook, Secret: secret}, nil
+}
+
+// DeleteWebhook removes the webhook before its log, so that a dispatcher
+// holding one of its deliveries finds it gone rather than sending it.
+func (wu *webhookUsecases) DeleteWebhook(ctx context.Context, webhookId string) error {
+	ctx, cancel := context.WithTimeout(ctx, wu.contextTimeout)
+	defer cancel()
+
+	webhook, err := wu.webhookRepository.GetWebhookByID(ctx, webhookId)
+	if err != nil {
+		return err
+	}
+	return inTransaction(ctx, wu.transactor, func(ctx context.Context) error {
+		if err := wu.webhookRepository.DeleteWebhook(ctx, webhookId); err != nil {
+			return err
+		}
+		if err := wu.deliveryRepository.DeleteWebhookDeliveries(ctx, webhookId); err != nil {
+			return err
+		}
+		return recordAudit(ctx, wu.auditLog, &domain.AuditEntry{
+			Action: domain.AuditWebhookDelete, TargetType: domain.AuditTargetWebhook, TargetID: webhookId,
+		}, nil, webhook, nil)
+	})
+}
+
+func (wu *webhookUsecases) ListDeliveries(ctx context.Context, webhookId string, limit int) ([]*domain.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, wu.contextTimeout)
+	defer cancel()
+
+	if limit < 0 || limit > maxWebhookDeliveryPage {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidWebhook, maxWebhookDeliveryPage)
+	}
+	if limit == 0 {
+		limit = defaultWebhookDeliveryPage
+	}
+	if _, err := wu.webhookRepository.GetWebhookByID(ctx, webhookId); err != nil {
+		return nil, err
+	}
+	return wu.deliveryRepository.GetWebhookDeliveries(ctx, webhookId, limit)
+}
+
+// Redeliver refuses webhooks that are inactive, since the dispatcher would
+// only mark the new delivery dead.
+func (wu *webhookUsecases) Redeliver(ctx context.Context, webhookId string, deliveryId string) (*domain.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, wu.contextTimeout)
+	defer cancel()
+
+	webhook, err := wu.webhookRepository.GetWebhookByID(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+	original, err := wu.deliveryRepository.GetWebhookDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	if original.WebhookID != webhook.ID {
+		return nil, domain.ErrWebhookDeliveryNotFound
+	}
+	if !webhook.Active {
+		return nil, fmt.Errorf("%w: the webhook is inactive", domain.ErrInvalidWebhook)
+	}
+
+	delivery := &domain.WebhookDelivery{
+		ID:           uuid.New().String(),
+		WebhookID:    webhook.ID,
+		EventID:      original.EventID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		Status:       domain.WebhookPending,
+		RedeliveryOf: original.ID,
+		CreatedAt:    time.Now(),
+	}
+	if err := wu.deliveryRepository.CreateWebhookDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// validateWebhookURL accepts absolute http and https URLs without
+// credentials or a fragment.
+func validateWebhookURL(target string) error {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" || parsed.User != nil || parsed.Fragment != "" ||
+		(parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return fmt.Errorf("%w: %q is not an absolute http or https URL without credentials or a fragment", domain.ErrInvalidWebhook, target)
+	}
+	return nil
+}
+
+// validateWebhookEvents checks that events are known event types or
+// WebhookAllEvents, and removes duplicates.
+func validateWebhookEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: subscribe to at least one event", domain.ErrInvalidWebhook)
+	}
+	var unique []string
+	for _, event := range events {
+		if event != domain.WebhookAllEvents && !slices.Contains(domain.EventTypes, event) {
+			return nil, fmt.Errorf("%w: unknown event %q", domain.ErrInvalidWebhook, event)
+		}
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return unique, nil
+}
+
+// webhookSecret returns secret if it is long enough, or a new random one
+// if it is empty.
+func webhookSecret(secret string) (string, error) {
+	if secret == "" {
+		return newOpaqueToken()
+	}
+	if len(secret) < domain.MinWebhookSecretLength {
+		return "", fmt.Errorf("%w: the secret must be at least %d characters", domain.ErrInvalidWebhook, domain.MinWebhookSecretLength)
+	}
+	return secret, nil
+}
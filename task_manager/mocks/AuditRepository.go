@@ -0,0 +1,77 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+// AppendAuditEntry provides a mock function with given fields: c, entry
+func (_m *AuditRepository) AppendAuditEntry(c context.Context, entry *domain.AuditEntry) error {
+	ret := _m.Called(c, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppendAuditEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditEntry) error); ok {
+		r0 = rf(c, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAuditEntries provides a mock function with given fields: c, query
+func (_m *AuditRepository) GetAuditEntries(c context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	ret := _m.Called(c, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEntries")
+	}
+
+	var r0 *domain.AuditPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) (*domain.AuditPage, error)); ok {
+		return rf(c, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) *domain.AuditPage); ok {
+		r0 = rf(c, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AuditQuery) error); ok {
+		r1 = rf(c, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditUsecases is an autogenerated mock type for the AuditUsecases type
+type AuditUsecases struct {
+	mock.Mock
+}
+
+// ExportAuditLog provides a mock function with given fields: ctx, query, write
+func (_m *AuditUsecases) ExportAuditLog(ctx context.Context, query domain.AuditQuery, write func(*domain.AuditEntry) error) error {
+	ret := _m.Called(ctx, query, write)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportAuditLog")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery, func(*domain.AuditEntry) error) error); ok {
+		r0 = rf(ctx, query, write)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueryAuditLog provides a mock function with given fields: ctx, query
+func (_m *AuditUsecases) QueryAuditLog(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryAuditLog")
+	}
+
+	var r0 *domain.AuditPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) (*domain.AuditPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) *domain.AuditPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AuditQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyAuditLog provides a mock function with given fields: ctx
+func (_m *AuditUsecases) VerifyAuditLog(ctx context.Context) (*domain.AuditVerification, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAuditLog")
+	}
+
+	var r0 *domain.AuditVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.AuditVerification, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.AuditVerification); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuditUsecases creates a new instance of AuditUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditUsecases {
+	mock := &AuditUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
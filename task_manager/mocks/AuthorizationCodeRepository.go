@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// AuthorizationCodeRepository is an autogenerated mock type for the AuthorizationCodeRepository type
+type AuthorizationCodeRepository struct {
+	mock.Mock
+}
+
+// CreateAuthorizationCode provides a mock function with given fields: c, code
+func (_m *AuthorizationCodeRepository) CreateAuthorizationCode(c context.Context, code *domain.AuthorizationCode) error {
+	ret := _m.Called(c, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuthorizationCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuthorizationCode) error); ok {
+		r0 = rf(c, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAuthorizationCodeByHash provides a mock function with given fields: c, codeHash
+func (_m *AuthorizationCodeRepository) GetAuthorizationCodeByHash(c context.Context, codeHash string) (*domain.AuthorizationCode, error) {
+	ret := _m.Called(c, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuthorizationCodeByHash")
+	}
+
+	var r0 *domain.AuthorizationCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AuthorizationCode, error)); ok {
+		return rf(c, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AuthorizationCode); ok {
+		r0 = rf(c, codeHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthorizationCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkAuthorizationCodeUsed provides a mock function with given fields: c, codeId, usedAt
+func (_m *AuthorizationCodeRepository) MarkAuthorizationCodeUsed(c context.Context, codeId string, usedAt time.Time) (bool, error) {
+	ret := _m.Called(c, codeId, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAuthorizationCodeUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (bool, error)); ok {
+		return rf(c, codeId, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) bool); ok {
+		r0 = rf(c, codeId, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(c, codeId, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuthorizationCodeRepository creates a new instance of AuthorizationCodeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthorizationCodeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthorizationCodeRepository {
+	mock := &AuthorizationCodeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,223 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CommentRepository is an autogenerated mock type for the CommentRepository type
+type CommentRepository struct {
+	mock.Mock
+}
+
+// CountCommentsByTask provides a mock function with given fields: c, taskIds
+func (_m *CommentRepository) CountCommentsByTask(c context.Context, taskIds []string) (map[string]int, error) {
+	ret := _m.Called(c, taskIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountCommentsByTask")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]int, error)); ok {
+		return rf(c, taskIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]int); ok {
+		r0 = rf(c, taskIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(c, taskIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateComment provides a mock function with given fields: c, comment
+func (_m *CommentRepository) CreateComment(c context.Context, comment *domain.Comment) error {
+	ret := _m.Called(c, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Comment) error); ok {
+		r0 = rf(c, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteComment provides a mock function with given fields: c, commentId, deletedBy, deletedAt
+func (_m *CommentRepository) DeleteComment(c context.Context, commentId string, deletedBy string, deletedAt time.Time) error {
+	ret := _m.Called(c, commentId, deletedBy, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(c, commentId, deletedBy, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCommentsByTask provides a mock function with given fields: c, taskId
+func (_m *CommentRepository) DeleteCommentsByTask(c context.Context, taskId string) error {
+	ret := _m.Called(c, taskId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCommentsByTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, taskId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCommentsByTasks provides a mock function with given fields: c, taskIds
+func (_m *CommentRepository) DeleteCommentsByTasks(c context.Context, taskIds []string) error {
+	ret := _m.Called(c, taskIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCommentsByTasks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(c, taskIds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EditComment provides a mock function with given fields: c, commentId, body, editedAt
+func (_m *CommentRepository) EditComment(c context.Context, commentId string, body string, editedAt time.Time) (*domain.Comment, error) {
+	ret := _m.Called(c, commentId, body, editedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditComment")
+	}
+
+	var r0 *domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*domain.Comment, error)); ok {
+		return rf(c, commentId, body, editedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *domain.Comment); ok {
+		r0 = rf(c, commentId, body, editedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(c, commentId, body, editedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentByID provides a mock function with given fields: c, commentId
+func (_m *CommentRepository) GetCommentByID(c context.Context, commentId string) (*domain.Comment, error) {
+	ret := _m.Called(c, commentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentByID")
+	}
+
+	var r0 *domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Comment, error)); ok {
+		return rf(c, commentId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Comment); ok {
+		r0 = rf(c, commentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, commentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentsByTask provides a mock function with given fields: c, taskId
+func (_m *CommentRepository) GetCommentsByTask(c context.Context, taskId string) ([]*domain.Comment, error) {
+	ret := _m.Called(c, taskId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentsByTask")
+	}
+
+	var r0 []*domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Comment, error)); ok {
+		return rf(c, taskId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Comment); ok {
+		r0 = rf(c, taskId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, taskId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCommentRepository creates a new instance of CommentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCommentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CommentRepository {
+	mock := &CommentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,137 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentUsecases is an autogenerated mock type for the CommentUsecases type
+type CommentUsecases struct {
+	mock.Mock
+}
+
+// AddComment provides a mock function with given fields: ctx, taskId, parentId, body, actor
+func (_m *CommentUsecases) AddComment(ctx context.Context, taskId string, parentId string, body string, actor *domain.Actor) (*domain.Comment, error) {
+	ret := _m.Called(ctx, taskId, parentId, body, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddComment")
+	}
+
+	var r0 *domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *domain.Actor) (*domain.Comment, error)); ok {
+		return rf(ctx, taskId, parentId, body, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *domain.Actor) *domain.Comment); ok {
+		r0 = rf(ctx, taskId, parentId, body, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, parentId, body, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteComment provides a mock function with given fields: ctx, taskId, commentId, actor
+func (_m *CommentUsecases) DeleteComment(ctx context.Context, taskId string, commentId string, actor *domain.Actor) error {
+	ret := _m.Called(ctx, taskId, commentId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) error); ok {
+		r0 = rf(ctx, taskId, commentId, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EditComment provides a mock function with given fields: ctx, taskId, commentId, body, actor
+func (_m *CommentUsecases) EditComment(ctx context.Context, taskId string, commentId string, body string, actor *domain.Actor) (*domain.Comment, error) {
+	ret := _m.Called(ctx, taskId, commentId, body, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditComment")
+	}
+
+	var r0 *domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *domain.Actor) (*domain.Comment, error)); ok {
+		return rf(ctx, taskId, commentId, body, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *domain.Actor) *domain.Comment); ok {
+		r0 = rf(ctx, taskId, commentId, body, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, commentId, body, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListComments provides a mock function with given fields: ctx, taskId, actor
+func (_m *CommentUsecases) ListComments(ctx context.Context, taskId string, actor *domain.Actor) ([]*domain.Comment, error) {
+	ret := _m.Called(ctx, taskId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListComments")
+	}
+
+	var r0 []*domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) ([]*domain.Comment, error)); ok {
+		return rf(ctx, taskId, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) []*domain.Comment); ok {
+		r0 = rf(ctx, taskId, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCommentUsecases creates a new instance of CommentUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCommentUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CommentUsecases {
+	mock := &CommentUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
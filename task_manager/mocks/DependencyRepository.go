@@ -0,0 +1,191 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DependencyRepository is an autogenerated mock type for the DependencyRepository type
+type DependencyRepository struct {
+	mock.Mock
+}
+
+// AddDependency provides a mock function with given fields: c, dependency
+func (_m *DependencyRepository) AddDependency(c context.Context, dependency *domain.TaskDependency) error {
+	ret := _m.Called(c, dependency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDependency")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TaskDependency) error); ok {
+		r0 = rf(c, dependency)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDependenciesByTask provides a mock function with given fields: c, taskId
+func (_m *DependencyRepository) DeleteDependenciesByTask(c context.Context, taskId string) error {
+	ret := _m.Called(c, taskId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDependenciesByTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, taskId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDependenciesByUser provides a mock function with given fields: c, userId
+func (_m *DependencyRepository) DeleteDependenciesByUser(c context.Context, userId string) error {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDependenciesByUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBlockers provides a mock function with given fields: c, taskId
+func (_m *DependencyRepository) GetBlockers(c context.Context, taskId string) ([]*domain.TaskDependency, error) {
+	ret := _m.Called(c, taskId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockers")
+	}
+
+	var r0 []*domain.TaskDependency
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.TaskDependency, error)); ok {
+		return rf(c, taskId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.TaskDependency); ok {
+		r0 = rf(c, taskId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TaskDependency)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, taskId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDependenciesByUser provides a mock function with given fields: c, userId
+func (_m *DependencyRepository) GetDependenciesByUser(c context.Context, userId string) ([]*domain.TaskDependency, error) {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDependenciesByUser")
+	}
+
+	var r0 []*domain.TaskDependency
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.TaskDependency, error)); ok {
+		return rf(c, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.TaskDependency); ok {
+		r0 = rf(c, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TaskDependency)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDependents provides a mock function with given fields: c, blockerId
+func (_m *DependencyRepository) GetDependents(c context.Context, blockerId string) ([]*domain.TaskDependency, error) {
+	ret := _m.Called(c, blockerId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDependents")
+	}
+
+	var r0 []*domain.TaskDependency
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.TaskDependency, error)); ok {
+		return rf(c, blockerId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.TaskDependency); ok {
+		r0 = rf(c, blockerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TaskDependency)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, blockerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveDependency provides a mock function with given fields: c, taskId, blockerId
+func (_m *DependencyRepository) RemoveDependency(c context.Context, taskId string, blockerId string) error {
+	ret := _m.Called(c, taskId, blockerId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveDependency")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, taskId, blockerId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDependencyRepository creates a new instance of DependencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDependencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DependencyRepository {
+	mock := &DependencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
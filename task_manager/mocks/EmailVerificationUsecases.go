@@ -0,0 +1,83 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailVerificationUsecases is an autogenerated mock type for the EmailVerificationUsecases type
+type EmailVerificationUsecases struct {
+	mock.Mock
+}
+
+// ResendVerificationEmail provides a mock function with given fields: ctx, userId
+func (_m *EmailVerificationUsecases) ResendVerificationEmail(ctx context.Context, userId string) error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerificationEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendVerificationEmail provides a mock function with given fields: ctx, user
+func (_m *EmailVerificationUsecases) SendVerificationEmail(ctx context.Context, user *domain.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendVerificationEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, verificationToken
+func (_m *EmailVerificationUsecases) VerifyEmail(ctx context.Context, verificationToken string) error {
+	ret := _m.Called(ctx, verificationToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, verificationToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmailVerificationUsecases creates a new instance of EmailVerificationUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailVerificationUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailVerificationUsecases {
+	mock := &EmailVerificationUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "task_manager/Domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -41,6 +42,66 @@ func (_m *IJWTService) GenerateToken(user *domain.User) (string, error) {
 	return r0, r1
 }
 
+// ParseToken provides a mock function with given fields: token
+func (_m *IJWTService) ParseToken(token string) (*domain.AccessClaims, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseToken")
+	}
+
+	var r0 *domain.AccessClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*domain.AccessClaims, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) *domain.AccessClaims); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccessClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublicKeys provides a mock function with given fields: ctx
+func (_m *IJWTService) PublicKeys(ctx context.Context) ([]*domain.JSONWebKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 []*domain.JSONWebKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.JSONWebKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.JSONWebKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.JSONWebKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIJWTService creates a new instance of IJWTService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIJWTService(t interface {
@@ -0,0 +1,141 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IOIDCTokenService is an autogenerated mock type for the IOIDCTokenService type
+type IOIDCTokenService struct {
+	mock.Mock
+}
+
+// GenerateAccessToken provides a mock function with given fields: user, clientId, scopes
+func (_m *IOIDCTokenService) GenerateAccessToken(user *domain.User, clientId string, scopes []string) (string, time.Duration, error) {
+	ret := _m.Called(user, clientId, scopes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateAccessToken")
+	}
+
+	var r0 string
+	var r1 time.Duration
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*domain.User, string, []string) (string, time.Duration, error)); ok {
+		return rf(user, clientId, scopes)
+	}
+	if rf, ok := ret.Get(0).(func(*domain.User, string, []string) string); ok {
+		r0 = rf(user, clientId, scopes)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*domain.User, string, []string) time.Duration); ok {
+		r1 = rf(user, clientId, scopes)
+	} else {
+		r1 = ret.Get(1).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(2).(func(*domain.User, string, []string) error); ok {
+		r2 = rf(user, clientId, scopes)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GenerateIDToken provides a mock function with given fields: info, clientId, nonce, authTime
+func (_m *IOIDCTokenService) GenerateIDToken(info *domain.OIDCUserInfo, clientId string, nonce string, authTime time.Time) (string, error) {
+	ret := _m.Called(info, clientId, nonce, authTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateIDToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*domain.OIDCUserInfo, string, string, time.Time) (string, error)); ok {
+		return rf(info, clientId, nonce, authTime)
+	}
+	if rf, ok := ret.Get(0).(func(*domain.OIDCUserInfo, string, string, time.Time) string); ok {
+		r0 = rf(info, clientId, nonce, authTime)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*domain.OIDCUserInfo, string, string, time.Time) error); ok {
+		r1 = rf(info, clientId, nonce, authTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseAccessToken provides a mock function with given fields: token
+func (_m *IOIDCTokenService) ParseAccessToken(token string) (*domain.OIDCAccessClaims, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseAccessToken")
+	}
+
+	var r0 *domain.OIDCAccessClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*domain.OIDCAccessClaims, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) *domain.OIDCAccessClaims); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCAccessClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SigningAlgorithm provides a mock function with no fields
+func (_m *IOIDCTokenService) SigningAlgorithm() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SigningAlgorithm")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// NewIOIDCTokenService creates a new instance of IOIDCTokenService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIOIDCTokenService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IOIDCTokenService {
+	mock := &IOIDCTokenService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
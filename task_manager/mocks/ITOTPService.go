@@ -0,0 +1,132 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ITOTPService is an autogenerated mock type for the ITOTPService type
+type ITOTPService struct {
+	mock.Mock
+}
+
+// GenerateSecret provides a mock function with no fields
+func (_m *ITOTPService) GenerateSecret() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateSecret")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// KeyURI provides a mock function with given fields: accountName, secret
+func (_m *ITOTPService) KeyURI(accountName string, secret string) string {
+	ret := _m.Called(accountName, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for KeyURI")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(accountName, secret)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// QRCode provides a mock function with given fields: content
+func (_m *ITOTPService) QRCode(content string) ([]byte, error) {
+	ret := _m.Called(content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QRCode")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]byte, error)); ok {
+		return rf(content)
+	}
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Validate provides a mock function with given fields: secret, code, at
+func (_m *ITOTPService) Validate(secret string, code string, at time.Time) (int64, bool) {
+	ret := _m.Called(secret, code, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 int64
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) (int64, bool)); ok {
+		return rf(secret, code, at)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) int64); ok {
+		r0 = rf(secret, code, at)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) bool); ok {
+		r1 = rf(secret, code, at)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// NewITOTPService creates a new instance of ITOTPService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewITOTPService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ITOTPService {
+	mock := &ITOTPService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ITwoFactorChallengeService is an autogenerated mock type for the ITwoFactorChallengeService type
+type ITwoFactorChallengeService struct {
+	mock.Mock
+}
+
+// GenerateChallengeToken provides a mock function with given fields: user, setupRequired
+func (_m *ITwoFactorChallengeService) GenerateChallengeToken(user *domain.User, setupRequired bool) (string, time.Time, error) {
+	ret := _m.Called(user, setupRequired)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateChallengeToken")
+	}
+
+	var r0 string
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*domain.User, bool) (string, time.Time, error)); ok {
+		return rf(user, setupRequired)
+	}
+	if rf, ok := ret.Get(0).(func(*domain.User, bool) string); ok {
+		r0 = rf(user, setupRequired)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*domain.User, bool) time.Time); ok {
+		r1 = rf(user, setupRequired)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(*domain.User, bool) error); ok {
+		r2 = rf(user, setupRequired)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ParseChallengeToken provides a mock function with given fields: token
+func (_m *ITwoFactorChallengeService) ParseChallengeToken(token string) (*domain.TwoFactorChallengeClaims, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseChallengeToken")
+	}
+
+	var r0 *domain.TwoFactorChallengeClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*domain.TwoFactorChallengeClaims, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) *domain.TwoFactorChallengeClaims); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TwoFactorChallengeClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewITwoFactorChallengeService creates a new instance of ITwoFactorChallengeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewITwoFactorChallengeService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ITwoFactorChallengeService {
+	mock := &ITwoFactorChallengeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
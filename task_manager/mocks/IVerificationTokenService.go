@@ -0,0 +1,86 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IVerificationTokenService is an autogenerated mock type for the IVerificationTokenService type
+type IVerificationTokenService struct {
+	mock.Mock
+}
+
+// GenerateVerificationToken provides a mock function with given fields: user
+func (_m *IVerificationTokenService) GenerateVerificationToken(user *domain.User) (string, error) {
+	ret := _m.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateVerificationToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*domain.User) (string, error)); ok {
+		return rf(user)
+	}
+	if rf, ok := ret.Get(0).(func(*domain.User) string); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*domain.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseVerificationToken provides a mock function with given fields: token
+func (_m *IVerificationTokenService) ParseVerificationToken(token string) (*domain.VerificationClaims, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseVerificationToken")
+	}
+
+	var r0 *domain.VerificationClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*domain.VerificationClaims, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) *domain.VerificationClaims); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.VerificationClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIVerificationTokenService creates a new instance of IVerificationTokenService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIVerificationTokenService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IVerificationTokenService {
+	mock := &IVerificationTokenService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LabelRepository is an autogenerated mock type for the LabelRepository type
+type LabelRepository struct {
+	mock.Mock
+}
+
+// CreateLabel provides a mock function with given fields: c, label
+func (_m *LabelRepository) CreateLabel(c context.Context, label *domain.Label) error {
+	ret := _m.Called(c, label)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Label) error); ok {
+		r0 = rf(c, label)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteLabel provides a mock function with given fields: c, labelId
+func (_m *LabelRepository) DeleteLabel(c context.Context, labelId string) error {
+	ret := _m.Called(c, labelId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, labelId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLabelByID provides a mock function with given fields: c, labelId
+func (_m *LabelRepository) GetLabelByID(c context.Context, labelId string) (*domain.Label, error) {
+	ret := _m.Called(c, labelId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLabelByID")
+	}
+
+	var r0 *domain.Label
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Label, error)); ok {
+		return rf(c, labelId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Label); ok {
+		r0 = rf(c, labelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Label)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, labelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLabelsByUser provides a mock function with given fields: c, userId
+func (_m *LabelRepository) GetLabelsByUser(c context.Context, userId string) ([]*domain.Label, error) {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLabelsByUser")
+	}
+
+	var r0 []*domain.Label
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Label, error)); ok {
+		return rf(c, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Label); ok {
+		r0 = rf(c, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Label)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLabel provides a mock function with given fields: c, label
+func (_m *LabelRepository) UpdateLabel(c context.Context, label *domain.Label) error {
+	ret := _m.Called(c, label)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Label) error); ok {
+		r0 = rf(c, label)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLabelRepository creates a new instance of LabelRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLabelRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LabelRepository {
+	mock := &LabelRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,137 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LabelUsecases is an autogenerated mock type for the LabelUsecases type
+type LabelUsecases struct {
+	mock.Mock
+}
+
+// CreateLabel provides a mock function with given fields: ctx, name, color, actor
+func (_m *LabelUsecases) CreateLabel(ctx context.Context, name string, color string, actor *domain.Actor) (*domain.Label, error) {
+	ret := _m.Called(ctx, name, color, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLabel")
+	}
+
+	var r0 *domain.Label
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) (*domain.Label, error)); ok {
+		return rf(ctx, name, color, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) *domain.Label); ok {
+		r0 = rf(ctx, name, color, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Label)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, name, color, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteLabel provides a mock function with given fields: ctx, labelId, actor
+func (_m *LabelUsecases) DeleteLabel(ctx context.Context, labelId string, actor *domain.Actor) error {
+	ret := _m.Called(ctx, labelId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) error); ok {
+		r0 = rf(ctx, labelId, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListLabels provides a mock function with given fields: ctx, actor
+func (_m *LabelUsecases) ListLabels(ctx context.Context, actor *domain.Actor) ([]*domain.Label, error) {
+	ret := _m.Called(ctx, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLabels")
+	}
+
+	var r0 []*domain.Label
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Actor) ([]*domain.Label, error)); ok {
+		return rf(ctx, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Actor) []*domain.Label); ok {
+		r0 = rf(ctx, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Label)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Actor) error); ok {
+		r1 = rf(ctx, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLabel provides a mock function with given fields: ctx, labelId, name, color, actor
+func (_m *LabelUsecases) UpdateLabel(ctx context.Context, labelId string, name *string, color *string, actor *domain.Actor) (*domain.Label, error) {
+	ret := _m.Called(ctx, labelId, name, color, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLabel")
+	}
+
+	var r0 *domain.Label
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *string, *string, *domain.Actor) (*domain.Label, error)); ok {
+		return rf(ctx, labelId, name, color, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *string, *string, *domain.Actor) *domain.Label); ok {
+		r0 = rf(ctx, labelId, name, color, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Label)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *string, *string, *domain.Actor) error); ok {
+		r1 = rf(ctx, labelId, name, color, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLabelUsecases creates a new instance of LabelUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLabelUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LabelUsecases {
+	mock := &LabelUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,127 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoginAttemptRepository is an autogenerated mock type for the LoginAttemptRepository type
+type LoginAttemptRepository struct {
+	mock.Mock
+}
+
+// GetLoginAttempts provides a mock function with given fields: c, key
+func (_m *LoginAttemptRepository) GetLoginAttempts(c context.Context, key string) (*domain.LoginAttempts, error) {
+	ret := _m.Called(c, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginAttempts")
+	}
+
+	var r0 *domain.LoginAttempts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.LoginAttempts, error)); ok {
+		return rf(c, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.LoginAttempts); ok {
+		r0 = rf(c, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LoginAttempts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordLoginFailure provides a mock function with given fields: c, key, at, window
+func (_m *LoginAttemptRepository) RecordLoginFailure(c context.Context, key string, at time.Time, window time.Duration) (*domain.LoginAttempts, error) {
+	ret := _m.Called(c, key, at, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginFailure")
+	}
+
+	var r0 *domain.LoginAttempts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) (*domain.LoginAttempts, error)); ok {
+		return rf(c, key, at, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) *domain.LoginAttempts); ok {
+		r0 = rf(c, key, at, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LoginAttempts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = rf(c, key, at, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseLoginAttempt provides a mock function with given fields: c, key, recorded
+func (_m *LoginAttemptRepository) ReleaseLoginAttempt(c context.Context, key string, recorded *domain.LoginAttempts) error {
+	ret := _m.Called(c, key, recorded)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseLoginAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.LoginAttempts) error); ok {
+		r0 = rf(c, key, recorded)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetLoginAttempts provides a mock function with given fields: c, key
+func (_m *LoginAttemptRepository) ResetLoginAttempts(c context.Context, key string) error {
+	ret := _m.Called(c, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetLoginAttempts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLoginAttemptRepository creates a new instance of LoginAttemptRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginAttemptRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginAttemptRepository {
+	mock := &LoginAttemptRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,125 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCClientRepository is an autogenerated mock type for the OIDCClientRepository type
+type OIDCClientRepository struct {
+	mock.Mock
+}
+
+// CreateOIDCClient provides a mock function with given fields: c, client
+func (_m *OIDCClientRepository) CreateOIDCClient(c context.Context, client *domain.OIDCClient) error {
+	ret := _m.Called(c, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOIDCClient")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OIDCClient) error); ok {
+		r0 = rf(c, client)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOIDCClient provides a mock function with given fields: c, clientId
+func (_m *OIDCClientRepository) DeleteOIDCClient(c context.Context, clientId string) error {
+	ret := _m.Called(c, clientId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOIDCClient")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, clientId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetOIDCClientByID provides a mock function with given fields: c, clientId
+func (_m *OIDCClientRepository) GetOIDCClientByID(c context.Context, clientId string) (*domain.OIDCClient, error) {
+	ret := _m.Called(c, clientId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOIDCClientByID")
+	}
+
+	var r0 *domain.OIDCClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.OIDCClient, error)); ok {
+		return rf(c, clientId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.OIDCClient); ok {
+		r0 = rf(c, clientId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, clientId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOIDCClients provides a mock function with given fields: c
+func (_m *OIDCClientRepository) GetOIDCClients(c context.Context) ([]*domain.OIDCClient, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOIDCClients")
+	}
+
+	var r0 []*domain.OIDCClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.OIDCClient, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.OIDCClient); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OIDCClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOIDCClientRepository creates a new instance of OIDCClientRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCClientRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCClientRepository {
+	mock := &OIDCClientRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
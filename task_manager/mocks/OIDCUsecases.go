@@ -0,0 +1,277 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCUsecases is an autogenerated mock type for the OIDCUsecases type
+type OIDCUsecases struct {
+	mock.Mock
+}
+
+// Authorize provides a mock function with given fields: ctx, request, email, password, clientIP
+func (_m *OIDCUsecases) Authorize(ctx context.Context, request *domain.AuthorizationRequest, email string, password string, clientIP string) (*domain.Authorization, error) {
+	ret := _m.Called(ctx, request, email, password, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 *domain.Authorization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuthorizationRequest, string, string, string) (*domain.Authorization, error)); ok {
+		return rf(ctx, request, email, password, clientIP)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuthorizationRequest, string, string, string) *domain.Authorization); ok {
+		r0 = rf(ctx, request, email, password, clientIP)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Authorization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.AuthorizationRequest, string, string, string) error); ok {
+		r1 = rf(ctx, request, email, password, clientIP)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthorizeTwoFactor provides a mock function with given fields: ctx, request, challengeToken, code, clientIP
+func (_m *OIDCUsecases) AuthorizeTwoFactor(ctx context.Context, request *domain.AuthorizationRequest, challengeToken string, code string, clientIP string) (*domain.Authorization, error) {
+	ret := _m.Called(ctx, request, challengeToken, code, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthorizeTwoFactor")
+	}
+
+	var r0 *domain.Authorization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuthorizationRequest, string, string, string) (*domain.Authorization, error)); ok {
+		return rf(ctx, request, challengeToken, code, clientIP)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuthorizationRequest, string, string, string) *domain.Authorization); ok {
+		r0 = rf(ctx, request, challengeToken, code, clientIP)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Authorization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.AuthorizationRequest, string, string, string) error); ok {
+		r1 = rf(ctx, request, challengeToken, code, clientIP)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteClient provides a mock function with given fields: ctx, clientId
+func (_m *OIDCUsecases) DeleteClient(ctx context.Context, clientId string) error {
+	ret := _m.Called(ctx, clientId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteClient")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, clientId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Discovery provides a mock function with no fields
+func (_m *OIDCUsecases) Discovery() *domain.OIDCProviderMetadata {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Discovery")
+	}
+
+	var r0 *domain.OIDCProviderMetadata
+	if rf, ok := ret.Get(0).(func() *domain.OIDCProviderMetadata); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCProviderMetadata)
+		}
+	}
+
+	return r0
+}
+
+// Exchange provides a mock function with given fields: ctx, request
+func (_m *OIDCUsecases) Exchange(ctx context.Context, request *domain.TokenRequest) (*domain.OIDCTokens, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exchange")
+	}
+
+	var r0 *domain.OIDCTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TokenRequest) (*domain.OIDCTokens, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TokenRequest) *domain.OIDCTokens); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.TokenRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListClients provides a mock function with given fields: ctx
+func (_m *OIDCUsecases) ListClients(ctx context.Context) ([]*domain.OIDCClient, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListClients")
+	}
+
+	var r0 []*domain.OIDCClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.OIDCClient, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.OIDCClient); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OIDCClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegisterClient provides a mock function with given fields: ctx, name, redirectURIs, public
+func (_m *OIDCUsecases) RegisterClient(ctx context.Context, name string, redirectURIs []string, public bool) (*domain.NewOIDCClient, error) {
+	ret := _m.Called(ctx, name, redirectURIs, public)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterClient")
+	}
+
+	var r0 *domain.NewOIDCClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, bool) (*domain.NewOIDCClient, error)); ok {
+		return rf(ctx, name, redirectURIs, public)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, bool) *domain.NewOIDCClient); ok {
+		r0 = rf(ctx, name, redirectURIs, public)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.NewOIDCClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, bool) error); ok {
+		r1 = rf(ctx, name, redirectURIs, public)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserInfo provides a mock function with given fields: ctx, accessToken
+func (_m *OIDCUsecases) UserInfo(ctx context.Context, accessToken string) (*domain.OIDCUserInfo, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserInfo")
+	}
+
+	var r0 *domain.OIDCUserInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.OIDCUserInfo, error)); ok {
+		return rf(ctx, accessToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.OIDCUserInfo); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCUserInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateAuthorization provides a mock function with given fields: ctx, request
+func (_m *OIDCUsecases) ValidateAuthorization(ctx context.Context, request *domain.AuthorizationRequest) (*domain.OIDCClient, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateAuthorization")
+	}
+
+	var r0 *domain.OIDCClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuthorizationRequest) (*domain.OIDCClient, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuthorizationRequest) *domain.OIDCClient); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.AuthorizationRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOIDCUsecases creates a new instance of OIDCUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCUsecases {
+	mock := &OIDCUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,109 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxCheckpointRepository is an autogenerated mock type for the OutboxCheckpointRepository type
+type OutboxCheckpointRepository struct {
+	mock.Mock
+}
+
+// AdvanceCheckpoint provides a mock function with given fields: c, consumer, owner, sequence
+func (_m *OutboxCheckpointRepository) AdvanceCheckpoint(c context.Context, consumer string, owner string, sequence int64) error {
+	ret := _m.Called(c, consumer, owner, sequence)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdvanceCheckpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) error); ok {
+		r0 = rf(c, consumer, owner, sequence)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimCheckpoint provides a mock function with given fields: c, consumer, owner, now, leaseUntil
+func (_m *OutboxCheckpointRepository) ClaimCheckpoint(c context.Context, consumer string, owner string, now time.Time, leaseUntil time.Time) (*domain.OutboxCheckpoint, error) {
+	ret := _m.Called(c, consumer, owner, now, leaseUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimCheckpoint")
+	}
+
+	var r0 *domain.OutboxCheckpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) (*domain.OutboxCheckpoint, error)); ok {
+		return rf(c, consumer, owner, now, leaseUntil)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) *domain.OutboxCheckpoint); ok {
+		r0 = rf(c, consumer, owner, now, leaseUntil)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OutboxCheckpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Time) error); ok {
+		r1 = rf(c, consumer, owner, now, leaseUntil)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCheckpoints provides a mock function with given fields: c
+func (_m *OutboxCheckpointRepository) GetCheckpoints(c context.Context) ([]*domain.OutboxCheckpoint, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCheckpoints")
+	}
+
+	var r0 []*domain.OutboxCheckpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.OutboxCheckpoint, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.OutboxCheckpoint); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OutboxCheckpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOutboxCheckpointRepository creates a new instance of OutboxCheckpointRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxCheckpointRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxCheckpointRepository {
+	mock := &OutboxCheckpointRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,51 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OutboxRelay is an autogenerated mock type for the OutboxRelay type
+type OutboxRelay struct {
+	mock.Mock
+}
+
+// RelayPending provides a mock function with given fields: ctx
+func (_m *OutboxRelay) RelayPending(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RelayPending")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Run provides a mock function with given fields: ctx
+func (_m *OutboxRelay) Run(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// NewOutboxRelay creates a new instance of OutboxRelay. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRelay(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRelay {
+	mock := &OutboxRelay{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepository is an autogenerated mock type for the OutboxRepository type
+type OutboxRepository struct {
+	mock.Mock
+}
+
+// AppendEvent provides a mock function with given fields: c, event
+func (_m *OutboxRepository) AppendEvent(c context.Context, event *domain.Event) error {
+	ret := _m.Called(c, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppendEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Event) error); ok {
+		r0 = rf(c, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteEvents provides a mock function with given fields: c, through, before
+func (_m *OutboxRepository) DeleteEvents(c context.Context, through int64, before time.Time) error {
+	ret := _m.Called(c, through, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(c, through, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetEventsAfter provides a mock function with given fields: c, sequence, limit
+func (_m *OutboxRepository) GetEventsAfter(c context.Context, sequence int64, limit int) ([]*domain.Event, error) {
+	ret := _m.Called(c, sequence, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEventsAfter")
+	}
+
+	var r0 []*domain.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*domain.Event, error)); ok {
+		return rf(c, sequence, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*domain.Event); ok {
+		r0 = rf(c, sequence, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(c, sequence, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRepository {
+	mock := &OutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
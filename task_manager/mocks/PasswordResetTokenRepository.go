@@ -0,0 +1,125 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PasswordResetTokenRepository is an autogenerated mock type for the PasswordResetTokenRepository type
+type PasswordResetTokenRepository struct {
+	mock.Mock
+}
+
+// CreatePasswordResetToken provides a mock function with given fields: c, token
+func (_m *PasswordResetTokenRepository) CreatePasswordResetToken(c context.Context, token *domain.PasswordResetToken) error {
+	ret := _m.Called(c, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePasswordResetToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PasswordResetToken) error); ok {
+		r0 = rf(c, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePasswordResetTokensByUser provides a mock function with given fields: c, userId
+func (_m *PasswordResetTokenRepository) DeletePasswordResetTokensByUser(c context.Context, userId string) error {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePasswordResetTokensByUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPasswordResetTokenByHash provides a mock function with given fields: c, tokenHash
+func (_m *PasswordResetTokenRepository) GetPasswordResetTokenByHash(c context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	ret := _m.Called(c, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPasswordResetTokenByHash")
+	}
+
+	var r0 *domain.PasswordResetToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.PasswordResetToken, error)); ok {
+		return rf(c, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.PasswordResetToken); ok {
+		r0 = rf(c, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PasswordResetToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkPasswordResetTokenUsed provides a mock function with given fields: c, tokenId, usedAt
+func (_m *PasswordResetTokenRepository) MarkPasswordResetTokenUsed(c context.Context, tokenId string, usedAt time.Time) (bool, error) {
+	ret := _m.Called(c, tokenId, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPasswordResetTokenUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (bool, error)); ok {
+		return rf(c, tokenId, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) bool); ok {
+		r0 = rf(c, tokenId, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(c, tokenId, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPasswordResetTokenRepository creates a new instance of PasswordResetTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordResetTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordResetTokenRepository {
+	mock := &PasswordResetTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
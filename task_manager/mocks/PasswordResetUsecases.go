@@ -0,0 +1,64 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordResetUsecases is an autogenerated mock type for the PasswordResetUsecases type
+type PasswordResetUsecases struct {
+	mock.Mock
+}
+
+// RequestPasswordReset provides a mock function with given fields: ctx, email
+func (_m *PasswordResetUsecases) RequestPasswordReset(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, resetToken, newPassword
+func (_m *PasswordResetUsecases) ResetPassword(ctx context.Context, resetToken string, newPassword string) error {
+	ret := _m.Called(ctx, resetToken, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, resetToken, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPasswordResetUsecases creates a new instance of PasswordResetUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordResetUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordResetUsecases {
+	mock := &PasswordResetUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
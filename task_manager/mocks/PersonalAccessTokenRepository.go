@@ -0,0 +1,163 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PersonalAccessTokenRepository is an autogenerated mock type for the PersonalAccessTokenRepository type
+type PersonalAccessTokenRepository struct {
+	mock.Mock
+}
+
+// CreatePersonalAccessToken provides a mock function with given fields: c, token
+func (_m *PersonalAccessTokenRepository) CreatePersonalAccessToken(c context.Context, token *domain.PersonalAccessToken) error {
+	ret := _m.Called(c, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePersonalAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PersonalAccessToken) error); ok {
+		r0 = rf(c, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePersonalAccessToken provides a mock function with given fields: c, userId, tokenId
+func (_m *PersonalAccessTokenRepository) DeletePersonalAccessToken(c context.Context, userId string, tokenId string) error {
+	ret := _m.Called(c, userId, tokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePersonalAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userId, tokenId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePersonalAccessTokensByUser provides a mock function with given fields: c, userId
+func (_m *PersonalAccessTokenRepository) DeletePersonalAccessTokensByUser(c context.Context, userId string) error {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePersonalAccessTokensByUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPersonalAccessTokenByHash provides a mock function with given fields: c, tokenHash
+func (_m *PersonalAccessTokenRepository) GetPersonalAccessTokenByHash(c context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	ret := _m.Called(c, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPersonalAccessTokenByHash")
+	}
+
+	var r0 *domain.PersonalAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.PersonalAccessToken, error)); ok {
+		return rf(c, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.PersonalAccessToken); ok {
+		r0 = rf(c, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PersonalAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPersonalAccessTokensByUser provides a mock function with given fields: c, userId
+func (_m *PersonalAccessTokenRepository) GetPersonalAccessTokensByUser(c context.Context, userId string) ([]*domain.PersonalAccessToken, error) {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPersonalAccessTokensByUser")
+	}
+
+	var r0 []*domain.PersonalAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.PersonalAccessToken, error)); ok {
+		return rf(c, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.PersonalAccessToken); ok {
+		r0 = rf(c, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.PersonalAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TouchPersonalAccessToken provides a mock function with given fields: c, tokenId, usedAt
+func (_m *PersonalAccessTokenRepository) TouchPersonalAccessToken(c context.Context, tokenId string, usedAt time.Time) error {
+	ret := _m.Called(c, tokenId, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchPersonalAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, tokenId, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPersonalAccessTokenRepository creates a new instance of PersonalAccessTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPersonalAccessTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PersonalAccessTokenRepository {
+	mock := &PersonalAccessTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
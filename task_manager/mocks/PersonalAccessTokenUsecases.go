@@ -0,0 +1,148 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PersonalAccessTokenUsecases is an autogenerated mock type for the PersonalAccessTokenUsecases type
+type PersonalAccessTokenUsecases struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, token
+func (_m *PersonalAccessTokenUsecases) Authenticate(ctx context.Context, token string) (*domain.User, *domain.PersonalAccessToken, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 *domain.User
+	var r1 *domain.PersonalAccessToken
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.User, *domain.PersonalAccessToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.PersonalAccessToken); ok {
+		r1 = rf(ctx, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.PersonalAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, token)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateToken provides a mock function with given fields: ctx, userId, name, scopes, expiresAt
+func (_m *PersonalAccessTokenUsecases) CreateToken(ctx context.Context, userId string, name string, scopes []string, expiresAt time.Time) (*domain.NewPersonalAccessToken, error) {
+	ret := _m.Called(ctx, userId, name, scopes, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateToken")
+	}
+
+	var r0 *domain.NewPersonalAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, time.Time) (*domain.NewPersonalAccessToken, error)); ok {
+		return rf(ctx, userId, name, scopes, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, time.Time) *domain.NewPersonalAccessToken); ok {
+		r0 = rf(ctx, userId, name, scopes, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.NewPersonalAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string, time.Time) error); ok {
+		r1 = rf(ctx, userId, name, scopes, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTokens provides a mock function with given fields: ctx, userId
+func (_m *PersonalAccessTokenUsecases) ListTokens(ctx context.Context, userId string) ([]*domain.PersonalAccessToken, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTokens")
+	}
+
+	var r0 []*domain.PersonalAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.PersonalAccessToken, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.PersonalAccessToken); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.PersonalAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeToken provides a mock function with given fields: ctx, userId, tokenId
+func (_m *PersonalAccessTokenUsecases) RevokeToken(ctx context.Context, userId string, tokenId string) error {
+	ret := _m.Called(ctx, userId, tokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, tokenId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPersonalAccessTokenUsecases creates a new instance of PersonalAccessTokenUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPersonalAccessTokenUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PersonalAccessTokenUsecases {
+	mock := &PersonalAccessTokenUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
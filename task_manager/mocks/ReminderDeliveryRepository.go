@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ReminderDeliveryRepository is an autogenerated mock type for the ReminderDeliveryRepository type
+type ReminderDeliveryRepository struct {
+	mock.Mock
+}
+
+// ClaimReminderDeliveries provides a mock function with given fields: c, owner, now, leaseUntil, limit
+func (_m *ReminderDeliveryRepository) ClaimReminderDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.ReminderDelivery, error) {
+	ret := _m.Called(c, owner, now, leaseUntil, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimReminderDeliveries")
+	}
+
+	var r0 []*domain.ReminderDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) ([]*domain.ReminderDelivery, error)); ok {
+		return rf(c, owner, now, leaseUntil, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) []*domain.ReminderDelivery); ok {
+		r0 = rf(c, owner, now, leaseUntil, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ReminderDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time, int) error); ok {
+		r1 = rf(c, owner, now, leaseUntil, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnqueueReminderDelivery provides a mock function with given fields: c, delivery
+func (_m *ReminderDeliveryRepository) EnqueueReminderDelivery(c context.Context, delivery *domain.ReminderDelivery) error {
+	ret := _m.Called(c, delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueReminderDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ReminderDelivery) error); ok {
+		r0 = rf(c, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishReminderDelivery provides a mock function with given fields: c, delivery, owner
+func (_m *ReminderDeliveryRepository) FinishReminderDelivery(c context.Context, delivery *domain.ReminderDelivery, owner string) error {
+	ret := _m.Called(c, delivery, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishReminderDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ReminderDelivery, string) error); ok {
+		r0 = rf(c, delivery, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReminderDeliveryRepository creates a new instance of ReminderDeliveryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReminderDeliveryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReminderDeliveryRepository {
+	mock := &ReminderDeliveryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
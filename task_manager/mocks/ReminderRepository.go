@@ -0,0 +1,157 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ReminderRepository is an autogenerated mock type for the ReminderRepository type
+type ReminderRepository struct {
+	mock.Mock
+}
+
+// CreateReminder provides a mock function with given fields: c, reminder
+func (_m *ReminderRepository) CreateReminder(c context.Context, reminder *domain.Reminder) error {
+	ret := _m.Called(c, reminder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReminder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Reminder) error); ok {
+		r0 = rf(c, reminder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteReminder provides a mock function with given fields: c, reminderId
+func (_m *ReminderRepository) DeleteReminder(c context.Context, reminderId string) error {
+	ret := _m.Called(c, reminderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteReminder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, reminderId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetReminderByID provides a mock function with given fields: c, reminderId
+func (_m *ReminderRepository) GetReminderByID(c context.Context, reminderId string) (*domain.Reminder, error) {
+	ret := _m.Called(c, reminderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReminderByID")
+	}
+
+	var r0 *domain.Reminder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Reminder, error)); ok {
+		return rf(c, reminderId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Reminder); ok {
+		r0 = rf(c, reminderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Reminder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, reminderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReminderLeads provides a mock function with given fields: c
+func (_m *ReminderRepository) GetReminderLeads(c context.Context) ([]time.Duration, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReminderLeads")
+	}
+
+	var r0 []time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]time.Duration, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []time.Duration); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Duration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRemindersByUser provides a mock function with given fields: c, userId
+func (_m *ReminderRepository) GetRemindersByUser(c context.Context, userId string) ([]*domain.Reminder, error) {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRemindersByUser")
+	}
+
+	var r0 []*domain.Reminder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Reminder, error)); ok {
+		return rf(c, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Reminder); ok {
+		r0 = rf(c, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Reminder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReminderRepository creates a new instance of ReminderRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReminderRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReminderRepository {
+	mock := &ReminderRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
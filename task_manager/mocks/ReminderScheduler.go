@@ -0,0 +1,51 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReminderScheduler is an autogenerated mock type for the ReminderScheduler type
+type ReminderScheduler struct {
+	mock.Mock
+}
+
+// DispatchDue provides a mock function with given fields: ctx
+func (_m *ReminderScheduler) DispatchDue(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DispatchDue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Run provides a mock function with given fields: ctx
+func (_m *ReminderScheduler) Run(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// NewReminderScheduler creates a new instance of ReminderScheduler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReminderScheduler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReminderScheduler {
+	mock := &ReminderScheduler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,109 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ReminderUsecases is an autogenerated mock type for the ReminderUsecases type
+type ReminderUsecases struct {
+	mock.Mock
+}
+
+// CreateReminder provides a mock function with given fields: ctx, lead, channel, actor
+func (_m *ReminderUsecases) CreateReminder(ctx context.Context, lead time.Duration, channel string, actor *domain.Actor) (*domain.Reminder, error) {
+	ret := _m.Called(ctx, lead, channel, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReminder")
+	}
+
+	var r0 *domain.Reminder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, string, *domain.Actor) (*domain.Reminder, error)); ok {
+		return rf(ctx, lead, channel, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, string, *domain.Actor) *domain.Reminder); ok {
+		r0 = rf(ctx, lead, channel, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Reminder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, lead, channel, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteReminder provides a mock function with given fields: ctx, reminderId, actor
+func (_m *ReminderUsecases) DeleteReminder(ctx context.Context, reminderId string, actor *domain.Actor) error {
+	ret := _m.Called(ctx, reminderId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteReminder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) error); ok {
+		r0 = rf(ctx, reminderId, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListReminders provides a mock function with given fields: ctx, actor
+func (_m *ReminderUsecases) ListReminders(ctx context.Context, actor *domain.Actor) ([]*domain.Reminder, error) {
+	ret := _m.Called(ctx, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListReminders")
+	}
+
+	var r0 []*domain.Reminder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Actor) ([]*domain.Reminder, error)); ok {
+		return rf(ctx, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Actor) []*domain.Reminder); ok {
+		r0 = rf(ctx, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Reminder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Actor) error); ok {
+		r1 = rf(ctx, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReminderUsecases creates a new instance of ReminderUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReminderUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReminderUsecases {
+	mock := &ReminderUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
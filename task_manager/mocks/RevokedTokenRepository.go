@@ -0,0 +1,76 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RevokedTokenRepository is an autogenerated mock type for the RevokedTokenRepository type
+type RevokedTokenRepository struct {
+	mock.Mock
+}
+
+// IsTokenRevoked provides a mock function with given fields: c, tokenId
+func (_m *RevokedTokenRepository) IsTokenRevoked(c context.Context, tokenId string) (bool, error) {
+	ret := _m.Called(c, tokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(c, tokenId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(c, tokenId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, tokenId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeToken provides a mock function with given fields: c, tokenId, expiresAt
+func (_m *RevokedTokenRepository) RevokeToken(c context.Context, tokenId string, expiresAt time.Time) error {
+	ret := _m.Called(c, tokenId, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, tokenId, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRevokedTokenRepository creates a new instance of RevokedTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRevokedTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RevokedTokenRepository {
+	mock := &RevokedTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
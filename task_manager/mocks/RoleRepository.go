@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoleRepository is an autogenerated mock type for the RoleRepository type
+type RoleRepository struct {
+	mock.Mock
+}
+
+// CreateRole provides a mock function with given fields: c, role
+func (_m *RoleRepository) CreateRole(c context.Context, role *domain.Role) error {
+	ret := _m.Called(c, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Role) error); ok {
+		r0 = rf(c, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllRoles provides a mock function with given fields: c
+func (_m *RoleRepository) GetAllRoles(c context.Context) ([]*domain.Role, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllRoles")
+	}
+
+	var r0 []*domain.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Role, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Role); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoleByName provides a mock function with given fields: c, name
+func (_m *RoleRepository) GetRoleByName(c context.Context, name string) (*domain.Role, error) {
+	ret := _m.Called(c, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoleByName")
+	}
+
+	var r0 *domain.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Role, error)); ok {
+		return rf(c, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Role); ok {
+		r0 = rf(c, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRoleRepository creates a new instance of RoleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleRepository {
+	mock := &RoleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
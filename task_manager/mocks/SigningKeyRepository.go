@@ -0,0 +1,79 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SigningKeyRepository is an autogenerated mock type for the SigningKeyRepository type
+type SigningKeyRepository struct {
+	mock.Mock
+}
+
+// CreateSigningKey provides a mock function with given fields: c, key
+func (_m *SigningKeyRepository) CreateSigningKey(c context.Context, key *domain.SigningKey) error {
+	ret := _m.Called(c, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSigningKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.SigningKey) error); ok {
+		r0 = rf(c, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSigningKeys provides a mock function with given fields: c, now
+func (_m *SigningKeyRepository) GetSigningKeys(c context.Context, now time.Time) ([]*domain.SigningKey, error) {
+	ret := _m.Called(c, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSigningKeys")
+	}
+
+	var r0 []*domain.SigningKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]*domain.SigningKey, error)); ok {
+		return rf(c, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []*domain.SigningKey); ok {
+		r0 = rf(c, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.SigningKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(c, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSigningKeyRepository creates a new instance of SigningKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSigningKeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SigningKeyRepository {
+	mock := &SigningKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
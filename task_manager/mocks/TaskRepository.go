@@ -7,6 +7,8 @@ import (
 	domain "task_manager/Domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TaskRepository is an autogenerated mock type for the TaskRepository type
@@ -14,6 +16,54 @@ type TaskRepository struct {
 	mock.Mock
 }
 
+// AddTaskLabel provides a mock function with given fields: c, taskId, labelId
+func (_m *TaskRepository) AddTaskLabel(c context.Context, taskId string, labelId string) error {
+	ret := _m.Called(c, taskId, labelId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTaskLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, taskId, labelId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountSubtasksByStatus provides a mock function with given fields: c, parentIds
+func (_m *TaskRepository) CountSubtasksByStatus(c context.Context, parentIds []string) (map[string]map[string]int, error) {
+	ret := _m.Called(c, parentIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountSubtasksByStatus")
+	}
+
+	var r0 map[string]map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]map[string]int, error)); ok {
+		return rf(c, parentIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]map[string]int); ok {
+		r0 = rf(c, parentIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(c, parentIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTask provides a mock function with given fields: c, task
 func (_m *TaskRepository) CreateTask(c context.Context, task *domain.Task) error {
 	ret := _m.Called(c, task)
@@ -50,21 +100,87 @@ func (_m *TaskRepository) DeleteTask(c context.Context, taskId string) error {
 	return r0
 }
 
-// GetAllTasks provides a mock function with given fields: c, userId
-func (_m *TaskRepository) GetAllTasks(c context.Context, userId string) ([]*domain.Task, error) {
+// DeleteTasksByUser provides a mock function with given fields: c, userId
+func (_m *TaskRepository) DeleteTasksByUser(c context.Context, userId string) error {
 	ret := _m.Called(c, userId)
 
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTasksByUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachSubtasks provides a mock function with given fields: c, parentId
+func (_m *TaskRepository) DetachSubtasks(c context.Context, parentId string) error {
+	ret := _m.Called(c, parentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetachSubtasks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, parentId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllTasks provides a mock function with given fields: c, query
+func (_m *TaskRepository) GetAllTasks(c context.Context, query domain.TaskQuery) (*domain.TaskPage, error) {
+	ret := _m.Called(c, query)
+
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllTasks")
 	}
 
+	var r0 *domain.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery) (*domain.TaskPage, error)); ok {
+		return rf(c, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery) *domain.TaskPage); ok {
+		r0 = rf(c, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskQuery) error); ok {
+		r1 = rf(c, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubtasks provides a mock function with given fields: c, parentId
+func (_m *TaskRepository) GetSubtasks(c context.Context, parentId string) ([]*domain.Task, error) {
+	ret := _m.Called(c, parentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubtasks")
+	}
+
 	var r0 []*domain.Task
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Task, error)); ok {
-		return rf(c, userId)
+		return rf(c, parentId)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Task); ok {
-		r0 = rf(c, userId)
+		r0 = rf(c, parentId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Task)
@@ -72,7 +188,7 @@ func (_m *TaskRepository) GetAllTasks(c context.Context, userId string) ([]*doma
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(c, userId)
+		r1 = rf(c, parentId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,6 +226,138 @@ func (_m *TaskRepository) GetTaskByID(c context.Context, taskId string) (*domain
 	return r0, r1
 }
 
+// GetTasksBySeries provides a mock function with given fields: c, seriesId
+func (_m *TaskRepository) GetTasksBySeries(c context.Context, seriesId string) ([]*domain.Task, error) {
+	ret := _m.Called(c, seriesId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTasksBySeries")
+	}
+
+	var r0 []*domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Task, error)); ok {
+		return rf(c, seriesId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Task); ok {
+		r0 = rf(c, seriesId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, seriesId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTasksDueBetween provides a mock function with given fields: c, from, to
+func (_m *TaskRepository) GetTasksDueBetween(c context.Context, from time.Time, to time.Time) ([]*domain.Task, error) {
+	ret := _m.Called(c, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTasksDueBetween")
+	}
+
+	var r0 []*domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]*domain.Task, error)); ok {
+		return rf(c, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []*domain.Task); ok {
+		r0 = rf(c, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(c, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveLabelFromTasks provides a mock function with given fields: c, userId, labelId
+func (_m *TaskRepository) RemoveLabelFromTasks(c context.Context, userId string, labelId string) error {
+	ret := _m.Called(c, userId, labelId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveLabelFromTasks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userId, labelId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveTaskLabel provides a mock function with given fields: c, taskId, labelId
+func (_m *TaskRepository) RemoveTaskLabel(c context.Context, taskId string, labelId string) error {
+	ret := _m.Called(c, taskId, labelId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTaskLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, taskId, labelId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTaskChecklist provides a mock function with given fields: c, taskId, checklist
+func (_m *TaskRepository) SetTaskChecklist(c context.Context, taskId string, checklist []domain.ChecklistItem) error {
+	ret := _m.Called(c, taskId, checklist)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTaskChecklist")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.ChecklistItem) error); ok {
+		r0 = rf(c, taskId, checklist)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTaskRecurrence provides a mock function with given fields: c, taskId, recurrence
+func (_m *TaskRepository) SetTaskRecurrence(c context.Context, taskId string, recurrence *domain.Recurrence) error {
+	ret := _m.Called(c, taskId, recurrence)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTaskRecurrence")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Recurrence) error); ok {
+		r0 = rf(c, taskId, recurrence)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateTask provides a mock function with given fields: c, taskId, task
 func (_m *TaskRepository) UpdateTask(c context.Context, taskId string, task *domain.Task) (*domain.Task, error) {
 	ret := _m.Called(c, taskId, task)
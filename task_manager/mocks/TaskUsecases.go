@@ -7,6 +7,8 @@ import (
 	domain "task_manager/Domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TaskUsecases is an autogenerated mock type for the TaskUsecases type
@@ -14,6 +16,84 @@ type TaskUsecases struct {
 	mock.Mock
 }
 
+// AddChecklistItem provides a mock function with given fields: ctx, taskId, text, actor
+func (_m *TaskUsecases) AddChecklistItem(ctx context.Context, taskId string, text string, actor *domain.Actor) (*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, text, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddChecklistItem")
+	}
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) (*domain.Task, error)); ok {
+		return rf(ctx, taskId, text, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) *domain.Task); ok {
+		r0 = rf(ctx, taskId, text, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, text, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddTaskBlocker provides a mock function with given fields: ctx, taskId, blockerId, actor
+func (_m *TaskUsecases) AddTaskBlocker(ctx context.Context, taskId string, blockerId string, actor *domain.Actor) error {
+	ret := _m.Called(ctx, taskId, blockerId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTaskBlocker")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) error); ok {
+		r0 = rf(ctx, taskId, blockerId, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddTaskLabel provides a mock function with given fields: ctx, taskId, labelId, actor
+func (_m *TaskUsecases) AddTaskLabel(ctx context.Context, taskId string, labelId string, actor *domain.Actor) (*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, labelId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTaskLabel")
+	}
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) (*domain.Task, error)); ok {
+		return rf(ctx, taskId, labelId, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) *domain.Task); ok {
+		r0 = rf(ctx, taskId, labelId, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, labelId, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTask provides a mock function with given fields: ctx, task, userId
 func (_m *TaskUsecases) CreateTask(ctx context.Context, task *domain.Task, userId string) error {
 	ret := _m.Called(ctx, task, userId)
@@ -32,17 +112,17 @@ func (_m *TaskUsecases) CreateTask(ctx context.Context, task *domain.Task, userI
 	return r0
 }
 
-// DeleteTask provides a mock function with given fields: ctx, taskId
-func (_m *TaskUsecases) DeleteTask(ctx context.Context, taskId string) error {
-	ret := _m.Called(ctx, taskId)
+// DeleteTask provides a mock function with given fields: ctx, taskId, actor
+func (_m *TaskUsecases) DeleteTask(ctx context.Context, taskId string, actor *domain.Actor) error {
+	ret := _m.Called(ctx, taskId, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, taskId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) error); ok {
+		r0 = rf(ctx, taskId, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -50,29 +130,119 @@ func (_m *TaskUsecases) DeleteTask(ctx context.Context, taskId string) error {
 	return r0
 }
 
-// GetAllTasks provides a mock function with given fields: ctx, userId
-func (_m *TaskUsecases) GetAllTasks(ctx context.Context, userId string) ([]*domain.Task, error) {
-	ret := _m.Called(ctx, userId)
+// GetAllTasks provides a mock function with given fields: ctx, query, actor
+func (_m *TaskUsecases) GetAllTasks(ctx context.Context, query domain.TaskQuery, actor *domain.Actor) (*domain.TaskPage, error) {
+	ret := _m.Called(ctx, query, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllTasks")
 	}
 
+	var r0 *domain.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery, *domain.Actor) (*domain.TaskPage, error)); ok {
+		return rf(ctx, query, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery, *domain.Actor) *domain.TaskPage); ok {
+		r0 = rf(ctx, query, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskQuery, *domain.Actor) error); ok {
+		r1 = rf(ctx, query, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadyTasks provides a mock function with given fields: ctx, userId, actor
+func (_m *TaskUsecases) GetReadyTasks(ctx context.Context, userId string, actor *domain.Actor) ([]*domain.ReadyTask, error) {
+	ret := _m.Called(ctx, userId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadyTasks")
+	}
+
+	var r0 []*domain.ReadyTask
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) ([]*domain.ReadyTask, error)); ok {
+		return rf(ctx, userId, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) []*domain.ReadyTask); ok {
+		r0 = rf(ctx, userId, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ReadyTask)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, userId, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubtasks provides a mock function with given fields: ctx, taskId, actor
+func (_m *TaskUsecases) GetSubtasks(ctx context.Context, taskId string, actor *domain.Actor) ([]*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubtasks")
+	}
+
+	var r0 []*domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) ([]*domain.Task, error)); ok {
+		return rf(ctx, taskId, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) []*domain.Task); ok {
+		r0 = rf(ctx, taskId, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskBlockers provides a mock function with given fields: ctx, taskId, actor
+func (_m *TaskUsecases) GetTaskBlockers(ctx context.Context, taskId string, actor *domain.Actor) ([]*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskBlockers")
+	}
+
 	var r0 []*domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Task, error)); ok {
-		return rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) ([]*domain.Task, error)); ok {
+		return rf(ctx, taskId, actor)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Task); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) []*domain.Task); ok {
+		r0 = rf(ctx, taskId, actor)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userId)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, actor)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -80,9 +250,9 @@ func (_m *TaskUsecases) GetAllTasks(ctx context.Context, userId string) ([]*doma
 	return r0, r1
 }
 
-// GetTaskByID provides a mock function with given fields: ctx, taskId
-func (_m *TaskUsecases) GetTaskByID(ctx context.Context, taskId string) (*domain.Task, error) {
-	ret := _m.Called(ctx, taskId)
+// GetTaskByID provides a mock function with given fields: ctx, taskId, actor
+func (_m *TaskUsecases) GetTaskByID(ctx context.Context, taskId string, actor *domain.Actor) (*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTaskByID")
@@ -90,19 +260,19 @@ func (_m *TaskUsecases) GetTaskByID(ctx context.Context, taskId string) (*domain
 
 	var r0 *domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Task, error)); ok {
-		return rf(ctx, taskId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) (*domain.Task, error)); ok {
+		return rf(ctx, taskId, actor)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Task); ok {
-		r0 = rf(ctx, taskId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) *domain.Task); ok {
+		r0 = rf(ctx, taskId, actor)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, taskId)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, actor)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,9 +280,177 @@ func (_m *TaskUsecases) GetTaskByID(ctx context.Context, taskId string) (*domain
 	return r0, r1
 }
 
-// UpdateTask provides a mock function with given fields: ctx, taskId, task
-func (_m *TaskUsecases) UpdateTask(ctx context.Context, taskId string, task *domain.Task) (*domain.Task, error) {
-	ret := _m.Called(ctx, taskId, task)
+// GetTaskDependents provides a mock function with given fields: ctx, taskId, actor
+func (_m *TaskUsecases) GetTaskDependents(ctx context.Context, taskId string, actor *domain.Actor) ([]*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskDependents")
+	}
+
+	var r0 []*domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) ([]*domain.Task, error)); ok {
+		return rf(ctx, taskId, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Actor) []*domain.Task); ok {
+		r0 = rf(ctx, taskId, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PreviewOccurrences provides a mock function with given fields: ctx, taskId, limit, actor
+func (_m *TaskUsecases) PreviewOccurrences(ctx context.Context, taskId string, limit int, actor *domain.Actor) ([]time.Time, error) {
+	ret := _m.Called(ctx, taskId, limit, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewOccurrences")
+	}
+
+	var r0 []time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *domain.Actor) ([]time.Time, error)); ok {
+		return rf(ctx, taskId, limit, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *domain.Actor) []time.Time); ok {
+		r0 = rf(ctx, taskId, limit, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, limit, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveChecklistItem provides a mock function with given fields: ctx, taskId, itemId, actor
+func (_m *TaskUsecases) RemoveChecklistItem(ctx context.Context, taskId string, itemId string, actor *domain.Actor) (*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, itemId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveChecklistItem")
+	}
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) (*domain.Task, error)); ok {
+		return rf(ctx, taskId, itemId, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) *domain.Task); ok {
+		r0 = rf(ctx, taskId, itemId, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, itemId, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTaskBlocker provides a mock function with given fields: ctx, taskId, blockerId, actor
+func (_m *TaskUsecases) RemoveTaskBlocker(ctx context.Context, taskId string, blockerId string, actor *domain.Actor) error {
+	ret := _m.Called(ctx, taskId, blockerId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTaskBlocker")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) error); ok {
+		r0 = rf(ctx, taskId, blockerId, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveTaskLabel provides a mock function with given fields: ctx, taskId, labelId, actor
+func (_m *TaskUsecases) RemoveTaskLabel(ctx context.Context, taskId string, labelId string, actor *domain.Actor) (*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, labelId, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTaskLabel")
+	}
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) (*domain.Task, error)); ok {
+		return rf(ctx, taskId, labelId, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.Actor) *domain.Task); ok {
+		r0 = rf(ctx, taskId, labelId, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, labelId, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateChecklistItem provides a mock function with given fields: ctx, taskId, itemId, text, done, actor
+func (_m *TaskUsecases) UpdateChecklistItem(ctx context.Context, taskId string, itemId string, text *string, done *bool, actor *domain.Actor) (*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, itemId, text, done, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateChecklistItem")
+	}
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *string, *bool, *domain.Actor) (*domain.Task, error)); ok {
+		return rf(ctx, taskId, itemId, text, done, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *string, *bool, *domain.Actor) *domain.Task); ok {
+		r0 = rf(ctx, taskId, itemId, text, done, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *string, *bool, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, itemId, text, done, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTask provides a mock function with given fields: ctx, taskId, task, actor
+func (_m *TaskUsecases) UpdateTask(ctx context.Context, taskId string, task *domain.Task, actor *domain.Actor) (*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, task, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateTask")
@@ -120,19 +458,49 @@ func (_m *TaskUsecases) UpdateTask(ctx context.Context, taskId string, task *dom
 
 	var r0 *domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Task) (*domain.Task, error)); ok {
-		return rf(ctx, taskId, task)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Task, *domain.Actor) (*domain.Task, error)); ok {
+		return rf(ctx, taskId, task, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Task, *domain.Actor) *domain.Task); ok {
+		r0 = rf(ctx, taskId, task, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Task, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, task, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTaskSeries provides a mock function with given fields: ctx, taskId, task, scope, actor
+func (_m *TaskUsecases) UpdateTaskSeries(ctx context.Context, taskId string, task *domain.Task, scope string, actor *domain.Actor) (*domain.Task, error) {
+	ret := _m.Called(ctx, taskId, task, scope, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTaskSeries")
+	}
+
+	var r0 *domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Task, string, *domain.Actor) (*domain.Task, error)); ok {
+		return rf(ctx, taskId, task, scope, actor)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Task) *domain.Task); ok {
-		r0 = rf(ctx, taskId, task)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Task, string, *domain.Actor) *domain.Task); ok {
+		r0 = rf(ctx, taskId, task, scope, actor)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Task) error); ok {
-		r1 = rf(ctx, taskId, task)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Task, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, taskId, task, scope, actor)
 	} else {
 		r1 = ret.Error(1)
 	}
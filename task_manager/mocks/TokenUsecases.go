@@ -0,0 +1,172 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenUsecases is an autogenerated mock type for the TokenUsecases type
+type TokenUsecases struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, accessToken
+func (_m *TokenUsecases) Authenticate(ctx context.Context, accessToken string) (*domain.AccessClaims, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 *domain.AccessClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AccessClaims, error)); ok {
+		return rf(ctx, accessToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AccessClaims); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccessClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgetTokenVersion provides a mock function with given fields: userId
+func (_m *TokenUsecases) ForgetTokenVersion(userId string) {
+	_m.Called(userId)
+}
+
+// IssueTokens provides a mock function with given fields: ctx, user
+func (_m *TokenUsecases) IssueTokens(ctx context.Context, user *domain.User) (*domain.TokenPair, error) {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueTokens")
+	}
+
+	var r0 *domain.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) (*domain.TokenPair, error)); ok {
+		return rf(ctx, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) *domain.TokenPair); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TokenPair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, refreshToken
+func (_m *TokenUsecases) Logout(ctx context.Context, refreshToken string) error {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublicKeys provides a mock function with given fields: ctx
+func (_m *TokenUsecases) PublicKeys(ctx context.Context) ([]*domain.JSONWebKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 []*domain.JSONWebKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.JSONWebKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.JSONWebKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.JSONWebKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshTokens provides a mock function with given fields: ctx, refreshToken
+func (_m *TokenUsecases) RefreshTokens(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshTokens")
+	}
+
+	var r0 *domain.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TokenPair, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TokenPair); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TokenPair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTokenUsecases creates a new instance of TokenUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenUsecases {
+	mock := &TokenUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
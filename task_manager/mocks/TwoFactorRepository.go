@@ -0,0 +1,151 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactorRepository is an autogenerated mock type for the TwoFactorRepository type
+type TwoFactorRepository struct {
+	mock.Mock
+}
+
+// DeleteTwoFactor provides a mock function with given fields: c, userId
+func (_m *TwoFactorRepository) DeleteTwoFactor(c context.Context, userId string) error {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTwoFactor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTwoFactor provides a mock function with given fields: c, userId
+func (_m *TwoFactorRepository) GetTwoFactor(c context.Context, userId string) (*domain.TwoFactor, error) {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTwoFactor")
+	}
+
+	var r0 *domain.TwoFactor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TwoFactor, error)); ok {
+		return rf(c, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TwoFactor); ok {
+		r0 = rf(c, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TwoFactor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveTwoFactor provides a mock function with given fields: c, twoFactor
+func (_m *TwoFactorRepository) SaveTwoFactor(c context.Context, twoFactor *domain.TwoFactor) error {
+	ret := _m.Called(c, twoFactor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTwoFactor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TwoFactor) error); ok {
+		r0 = rf(c, twoFactor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: c, userId, codeHash
+func (_m *TwoFactorRepository) UseRecoveryCode(c context.Context, userId string, codeHash string) (bool, error) {
+	ret := _m.Called(c, userId, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(c, userId, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(c, userId, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, userId, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseTOTPStep provides a mock function with given fields: c, userId, step
+func (_m *TwoFactorRepository) UseTOTPStep(c context.Context, userId string, step int64) (bool, error) {
+	ret := _m.Called(c, userId, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (bool, error)); ok {
+		return rf(c, userId, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) bool); ok {
+		r0 = rf(c, userId, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(c, userId, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTwoFactorRepository creates a new instance of TwoFactorRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactorRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactorRepository {
+	mock := &TwoFactorRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
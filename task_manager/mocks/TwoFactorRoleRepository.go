@@ -0,0 +1,76 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactorRoleRepository is an autogenerated mock type for the TwoFactorRoleRepository type
+type TwoFactorRoleRepository struct {
+	mock.Mock
+}
+
+// GetTwoFactorRoles provides a mock function with given fields: c
+func (_m *TwoFactorRoleRepository) GetTwoFactorRoles(c context.Context) ([]string, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTwoFactorRoles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetTwoFactorRequired provides a mock function with given fields: c, role, required
+func (_m *TwoFactorRoleRepository) SetTwoFactorRequired(c context.Context, role string, required bool) error {
+	ret := _m.Called(c, role, required)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTwoFactorRequired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(c, role, required)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTwoFactorRoleRepository creates a new instance of TwoFactorRoleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactorRoleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactorRoleRepository {
+	mock := &TwoFactorRoleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,305 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactorUsecases is an autogenerated mock type for the TwoFactorUsecases type
+type TwoFactorUsecases struct {
+	mock.Mock
+}
+
+// BeginLogin provides a mock function with given fields: ctx, user
+func (_m *TwoFactorUsecases) BeginLogin(ctx context.Context, user *domain.User) (*domain.TwoFactorChallenge, error) {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginLogin")
+	}
+
+	var r0 *domain.TwoFactorChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) (*domain.TwoFactorChallenge, error)); ok {
+		return rf(ctx, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) *domain.TwoFactorChallenge); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TwoFactorChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteLogin provides a mock function with given fields: ctx, challengeToken, code, clientIP
+func (_m *TwoFactorUsecases) CompleteLogin(ctx context.Context, challengeToken string, code string, clientIP string) (*domain.TwoFactorLogin, error) {
+	ret := _m.Called(ctx, challengeToken, code, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteLogin")
+	}
+
+	var r0 *domain.TwoFactorLogin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.TwoFactorLogin, error)); ok {
+		return rf(ctx, challengeToken, code, clientIP)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.TwoFactorLogin); ok {
+		r0 = rf(ctx, challengeToken, code, clientIP)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TwoFactorLogin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, challengeToken, code, clientIP)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Confirm provides a mock function with given fields: ctx, userId, code
+func (_m *TwoFactorUsecases) Confirm(ctx context.Context, userId string, code string) ([]string, error) {
+	ret := _m.Called(ctx, userId, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return rf(ctx, userId, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(ctx, userId, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userId, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Disable provides a mock function with given fields: ctx, userId, code
+func (_m *TwoFactorUsecases) Disable(ctx context.Context, userId string, code string) error {
+	ret := _m.Called(ctx, userId, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enroll provides a mock function with given fields: ctx, userId
+func (_m *TwoFactorUsecases) Enroll(ctx context.Context, userId string) (*domain.TwoFactorEnrollment, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enroll")
+	}
+
+	var r0 *domain.TwoFactorEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TwoFactorEnrollment, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TwoFactorEnrollment); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TwoFactorEnrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTwoFactorRoles provides a mock function with given fields: ctx
+func (_m *TwoFactorUsecases) GetTwoFactorRoles(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTwoFactorRoles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: ctx, userId, code
+func (_m *TwoFactorUsecases) RegenerateRecoveryCodes(ctx context.Context, userId string, code string) ([]string, error) {
+	ret := _m.Called(ctx, userId, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return rf(ctx, userId, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(ctx, userId, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userId, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRoleTwoFactorRequired provides a mock function with given fields: ctx, role, required
+func (_m *TwoFactorUsecases) SetRoleTwoFactorRequired(ctx context.Context, role string, required bool) error {
+	ret := _m.Called(ctx, role, required)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRoleTwoFactorRequired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, role, required)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetupLogin provides a mock function with given fields: ctx, challengeToken
+func (_m *TwoFactorUsecases) SetupLogin(ctx context.Context, challengeToken string) (*domain.TwoFactorEnrollment, error) {
+	ret := _m.Called(ctx, challengeToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetupLogin")
+	}
+
+	var r0 *domain.TwoFactorEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TwoFactorEnrollment, error)); ok {
+		return rf(ctx, challengeToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TwoFactorEnrollment); ok {
+		r0 = rf(ctx, challengeToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TwoFactorEnrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, challengeToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyLogin provides a mock function with given fields: ctx, challengeToken, code, clientIP
+func (_m *TwoFactorUsecases) VerifyLogin(ctx context.Context, challengeToken string, code string, clientIP string) (*domain.User, error) {
+	ret := _m.Called(ctx, challengeToken, code, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyLogin")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.User, error)); ok {
+		return rf(ctx, challengeToken, code, clientIP)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.User); ok {
+		r0 = rf(ctx, challengeToken, code, clientIP)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, challengeToken, code, clientIP)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTwoFactorUsecases creates a new instance of TwoFactorUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactorUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactorUsecases {
+	mock := &TwoFactorUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
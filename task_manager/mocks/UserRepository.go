@@ -14,6 +14,52 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// BumpTokenVersion provides a mock function with given fields: c, userId
+func (_m *UserRepository) BumpTokenVersion(c context.Context, userId string) error {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BumpTokenVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountActiveAdmins provides a mock function with given fields: c
+func (_m *UserRepository) CountActiveAdmins(c context.Context) (int, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountActiveAdmins")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateUser provides a mock function with given fields: c, user
 func (_m *UserRepository) CreateUser(c context.Context, user *domain.User) (*domain.User, error) {
 	ret := _m.Called(c, user)
@@ -44,29 +90,47 @@ func (_m *UserRepository) CreateUser(c context.Context, user *domain.User) (*dom
 	return r0, r1
 }
 
-// GetAllUsers provides a mock function with given fields: c, user
-func (_m *UserRepository) GetAllUsers(c context.Context, user *domain.User) ([]*domain.User, error) {
-	ret := _m.Called(c, user)
+// DeleteUser provides a mock function with given fields: c, userId
+func (_m *UserRepository) DeleteUser(c context.Context, userId string) error {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllUsers provides a mock function with given fields: c, query
+func (_m *UserRepository) GetAllUsers(c context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	ret := _m.Called(c, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllUsers")
 	}
 
-	var r0 []*domain.User
+	var r0 *domain.UserPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) ([]*domain.User, error)); ok {
-		return rf(c, user)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserQuery) (*domain.UserPage, error)); ok {
+		return rf(c, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) []*domain.User); ok {
-		r0 = rf(c, user)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserQuery) *domain.UserPage); ok {
+		r0 = rf(c, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.User)
+			r0 = ret.Get(0).(*domain.UserPage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.User) error); ok {
-		r1 = rf(c, user)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserQuery) error); ok {
+		r1 = rf(c, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -164,6 +228,54 @@ func (_m *UserRepository) GetUserByUsername(c context.Context, username string)
 	return r0, r1
 }
 
+// GetUsersByRole provides a mock function with given fields: c, role
+func (_m *UserRepository) GetUsersByRole(c context.Context, role string) ([]*domain.User, error) {
+	ret := _m.Called(c, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersByRole")
+	}
+
+	var r0 []*domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.User, error)); ok {
+		return rf(c, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.User); ok {
+		r0 = rf(c, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkEmailVerified provides a mock function with given fields: c, userId
+func (_m *UserRepository) MarkEmailVerified(c context.Context, userId string) error {
+	ret := _m.Called(c, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PromoteUserToAdmin provides a mock function with given fields: c, userId
 func (_m *UserRepository) PromoteUserToAdmin(c context.Context, userId string) error {
 	ret := _m.Called(c, userId)
@@ -182,6 +294,60 @@ func (_m *UserRepository) PromoteUserToAdmin(c context.Context, userId string) e
 	return r0
 }
 
+// SetUserDisabled provides a mock function with given fields: c, userId, disabled
+func (_m *UserRepository) SetUserDisabled(c context.Context, userId string, disabled bool) error {
+	ret := _m.Called(c, userId, disabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserDisabled")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(c, userId, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserPassword provides a mock function with given fields: c, userId, passwordHash
+func (_m *UserRepository) UpdateUserPassword(c context.Context, userId string, passwordHash string) error {
+	ret := _m.Called(c, userId, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userId, passwordHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserRole provides a mock function with given fields: c, userId, role
+func (_m *UserRepository) UpdateUserRole(c context.Context, userId string, role string) error {
+	ret := _m.Called(c, userId, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userId, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UserExists provides a mock function with given fields: c
 func (_m *UserRepository) UserExists(c context.Context) (bool, error) {
 	ret := _m.Called(c)
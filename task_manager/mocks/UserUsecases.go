@@ -44,6 +44,42 @@ func (_m *UserUsecases) CreateUser(ctx context.Context, user *domain.User) (*dom
 	return r0, r1
 }
 
+// DeleteUser provides a mock function with given fields: ctx, userId
+func (_m *UserUsecases) DeleteUser(ctx context.Context, userId string) error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DemoteUser provides a mock function with given fields: ctx, userId
+func (_m *UserUsecases) DemoteUser(ctx context.Context, userId string) error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DemoteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetCurrentUser provides a mock function with given fields: ctx
 func (_m *UserUsecases) GetCurrentUser(ctx context.Context) (*domain.User, error) {
 	ret := _m.Called(ctx)
@@ -164,27 +200,59 @@ func (_m *UserUsecases) GetUserByUsername(ctx context.Context, username string)
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, email, password
-func (_m *UserUsecases) Login(ctx context.Context, email string, password string) (string, error) {
-	ret := _m.Called(ctx, email, password)
+// ListUsers provides a mock function with given fields: ctx, query
+func (_m *UserUsecases) ListUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 *domain.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserQuery) (*domain.UserPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserQuery) *domain.UserPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, email, password, clientIP
+func (_m *UserUsecases) Login(ctx context.Context, email string, password string, clientIP string) (*domain.LoginResult, error) {
+	ret := _m.Called(ctx, email, password, clientIP)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 string
+	var r0 *domain.LoginResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.LoginResult, error)); ok {
+		return rf(ctx, email, password, clientIP)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.LoginResult); ok {
+		r0 = rf(ctx, email, password, clientIP)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LoginResult)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, email, password, clientIP)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -210,6 +278,42 @@ func (_m *UserUsecases) PromoteUserToAdmin(ctx context.Context, userId string) e
 	return r0
 }
 
+// SetUserDisabled provides a mock function with given fields: ctx, userId, disabled
+func (_m *UserUsecases) SetUserDisabled(ctx context.Context, userId string, disabled bool) error {
+	ret := _m.Called(ctx, userId, disabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserDisabled")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, userId, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnlockUser provides a mock function with given fields: ctx, userId
+func (_m *UserUsecases) UnlockUser(ctx context.Context, userId string) error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserUsecases creates a new instance of UserUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserUsecases(t interface {
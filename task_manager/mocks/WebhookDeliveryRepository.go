@@ -0,0 +1,175 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookDeliveryRepository is an autogenerated mock type for the WebhookDeliveryRepository type
+type WebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+// ClaimWebhookDeliveries provides a mock function with given fields: c, owner, now, leaseUntil, limit
+func (_m *WebhookDeliveryRepository) ClaimWebhookDeliveries(c context.Context, owner string, now time.Time, leaseUntil time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	ret := _m.Called(c, owner, now, leaseUntil, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimWebhookDeliveries")
+	}
+
+	var r0 []*domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) ([]*domain.WebhookDelivery, error)); ok {
+		return rf(c, owner, now, leaseUntil, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) []*domain.WebhookDelivery); ok {
+		r0 = rf(c, owner, now, leaseUntil, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time, int) error); ok {
+		r1 = rf(c, owner, now, leaseUntil, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhookDelivery provides a mock function with given fields: c, delivery
+func (_m *WebhookDeliveryRepository) CreateWebhookDelivery(c context.Context, delivery *domain.WebhookDelivery) error {
+	ret := _m.Called(c, delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhookDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.WebhookDelivery) error); ok {
+		r0 = rf(c, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhookDeliveries provides a mock function with given fields: c, webhookId
+func (_m *WebhookDeliveryRepository) DeleteWebhookDeliveries(c context.Context, webhookId string) error {
+	ret := _m.Called(c, webhookId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhookDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, webhookId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishWebhookDelivery provides a mock function with given fields: c, delivery, owner
+func (_m *WebhookDeliveryRepository) FinishWebhookDelivery(c context.Context, delivery *domain.WebhookDelivery, owner string) error {
+	ret := _m.Called(c, delivery, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishWebhookDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.WebhookDelivery, string) error); ok {
+		r0 = rf(c, delivery, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetWebhookDeliveries provides a mock function with given fields: c, webhookId, limit
+func (_m *WebhookDeliveryRepository) GetWebhookDeliveries(c context.Context, webhookId string, limit int) ([]*domain.WebhookDelivery, error) {
+	ret := _m.Called(c, webhookId, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeliveries")
+	}
+
+	var r0 []*domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*domain.WebhookDelivery, error)); ok {
+		return rf(c, webhookId, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*domain.WebhookDelivery); ok {
+		r0 = rf(c, webhookId, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(c, webhookId, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookDeliveryByID provides a mock function with given fields: c, deliveryId
+func (_m *WebhookDeliveryRepository) GetWebhookDeliveryByID(c context.Context, deliveryId string) (*domain.WebhookDelivery, error) {
+	ret := _m.Called(c, deliveryId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeliveryByID")
+	}
+
+	var r0 *domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.WebhookDelivery, error)); ok {
+		return rf(c, deliveryId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.WebhookDelivery); ok {
+		r0 = rf(c, deliveryId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, deliveryId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookDeliveryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookDeliveryRepository {
+	mock := &WebhookDeliveryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
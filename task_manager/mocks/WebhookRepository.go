@@ -0,0 +1,173 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookRepository is an autogenerated mock type for the WebhookRepository type
+type WebhookRepository struct {
+	mock.Mock
+}
+
+// CreateWebhook provides a mock function with given fields: c, webhook
+func (_m *WebhookRepository) CreateWebhook(c context.Context, webhook *domain.Webhook) error {
+	ret := _m.Called(c, webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook) error); ok {
+		r0 = rf(c, webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhook provides a mock function with given fields: c, webhookId
+func (_m *WebhookRepository) DeleteWebhook(c context.Context, webhookId string) error {
+	ret := _m.Called(c, webhookId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, webhookId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetWebhookByID provides a mock function with given fields: c, webhookId
+func (_m *WebhookRepository) GetWebhookByID(c context.Context, webhookId string) (*domain.Webhook, error) {
+	ret := _m.Called(c, webhookId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookByID")
+	}
+
+	var r0 *domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Webhook, error)); ok {
+		return rf(c, webhookId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Webhook); ok {
+		r0 = rf(c, webhookId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, webhookId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhooks provides a mock function with given fields: c
+func (_m *WebhookRepository) GetWebhooks(c context.Context) ([]*domain.Webhook, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhooks")
+	}
+
+	var r0 []*domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Webhook, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Webhook); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhooksForEvent provides a mock function with given fields: c, eventType
+func (_m *WebhookRepository) GetWebhooksForEvent(c context.Context, eventType string) ([]*domain.Webhook, error) {
+	ret := _m.Called(c, eventType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhooksForEvent")
+	}
+
+	var r0 []*domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Webhook, error)); ok {
+		return rf(c, eventType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Webhook); ok {
+		r0 = rf(c, eventType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebhook provides a mock function with given fields: c, webhook
+func (_m *WebhookRepository) UpdateWebhook(c context.Context, webhook *domain.Webhook) error {
+	ret := _m.Called(c, webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook) error); ok {
+		r0 = rf(c, webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookRepository {
+	mock := &WebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookSender is an autogenerated mock type for the WebhookSender type
+type WebhookSender struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: ctx, webhook, delivery
+func (_m *WebhookSender) Send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error) {
+	ret := _m.Called(ctx, webhook, delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook, *domain.WebhookDelivery) (int, error)); ok {
+		return rf(ctx, webhook, delivery)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook, *domain.WebhookDelivery) int); ok {
+		r0 = rf(ctx, webhook, delivery)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Webhook, *domain.WebhookDelivery) error); ok {
+		r1 = rf(ctx, webhook, delivery)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebhookSender creates a new instance of WebhookSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookSender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookSender {
+	mock := &WebhookSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,227 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manager/Domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookUsecases is an autogenerated mock type for the WebhookUsecases type
+type WebhookUsecases struct {
+	mock.Mock
+}
+
+// CreateWebhook provides a mock function with given fields: ctx, url, events, secret, actor
+func (_m *WebhookUsecases) CreateWebhook(ctx context.Context, url string, events []string, secret string, actor *domain.Actor) (*domain.NewWebhook, error) {
+	ret := _m.Called(ctx, url, events, secret, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 *domain.NewWebhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, string, *domain.Actor) (*domain.NewWebhook, error)); ok {
+		return rf(ctx, url, events, secret, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, string, *domain.Actor) *domain.NewWebhook); ok {
+		r0 = rf(ctx, url, events, secret, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.NewWebhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, string, *domain.Actor) error); ok {
+		r1 = rf(ctx, url, events, secret, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, webhookId
+func (_m *WebhookUsecases) DeleteWebhook(ctx context.Context, webhookId string) error {
+	ret := _m.Called(ctx, webhookId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, webhookId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetWebhook provides a mock function with given fields: ctx, webhookId
+func (_m *WebhookUsecases) GetWebhook(ctx context.Context, webhookId string) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, webhookId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhook")
+	}
+
+	var r0 *domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Webhook, error)); ok {
+		return rf(ctx, webhookId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Webhook); ok {
+		r0 = rf(ctx, webhookId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, webhookId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, webhookId, limit
+func (_m *WebhookUsecases) ListDeliveries(ctx context.Context, webhookId string, limit int) ([]*domain.WebhookDelivery, error) {
+	ret := _m.Called(ctx, webhookId, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 []*domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*domain.WebhookDelivery, error)); ok {
+		return rf(ctx, webhookId, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*domain.WebhookDelivery); ok {
+		r0 = rf(ctx, webhookId, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, webhookId, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhooks provides a mock function with given fields: ctx
+func (_m *WebhookUsecases) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhooks")
+	}
+
+	var r0 []*domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Webhook, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Webhook); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Redeliver provides a mock function with given fields: ctx, webhookId, deliveryId
+func (_m *WebhookUsecases) Redeliver(ctx context.Context, webhookId string, deliveryId string) (*domain.WebhookDelivery, error) {
+	ret := _m.Called(ctx, webhookId, deliveryId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Redeliver")
+	}
+
+	var r0 *domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.WebhookDelivery, error)); ok {
+		return rf(ctx, webhookId, deliveryId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.WebhookDelivery); ok {
+		r0 = rf(ctx, webhookId, deliveryId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, webhookId, deliveryId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebhook provides a mock function with given fields: ctx, webhookId, update
+func (_m *WebhookUsecases) UpdateWebhook(ctx context.Context, webhookId string, update domain.WebhookUpdate) (*domain.NewWebhook, error) {
+	ret := _m.Called(ctx, webhookId, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhook")
+	}
+
+	var r0 *domain.NewWebhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.WebhookUpdate) (*domain.NewWebhook, error)); ok {
+		return rf(ctx, webhookId, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.WebhookUpdate) *domain.NewWebhook); ok {
+		r0 = rf(ctx, webhookId, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.NewWebhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.WebhookUpdate) error); ok {
+		r1 = rf(ctx, webhookId, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebhookUsecases creates a new instance of WebhookUsecases. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookUsecases(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookUsecases {
+	mock := &WebhookUsecases{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}